@@ -35,6 +35,7 @@ import (
 	"k8s.io/client-go/tools/leaderelection"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
 	"k8s.io/utils/clock"
 
 	"github.com/jetstack/cert-manager/cmd/controller/app/options"
@@ -69,12 +70,16 @@ func Run(opts *options.ControllerOptions, stopCh <-chan struct{}) {
 		os.Exit(1)
 	}
 
-	metricsServer, err := ctx.Metrics.Start(opts.MetricsListenAddress, opts.EnablePprof)
+	metricsServer, err := ctx.Metrics.Start(opts.MetricsListenAddress, opts.EnablePprof, opts.WorkqueueBacklogThreshold)
 	if err != nil {
 		log.Error(err, "failed to listen on prometheus address", "address", opts.MetricsListenAddress)
 		os.Exit(1)
 	}
 
+	// the provider must be set before any controllers, and so their
+	// workqueues, are constructed
+	workqueue.SetProvider(ctx.Metrics.WorkqueueMetricsProvider())
+
 	var wg sync.WaitGroup
 	run := func(_ context.Context) {
 		for n, fn := range controller.Known() {
@@ -163,6 +168,14 @@ func buildControllerContext(ctx context.Context, stopCh <-chan struct{}, opts *o
 	}
 
 	nameservers := opts.DNS01RecursiveNameservers
+	checkAuthoritative := !opts.DNS01RecursiveNameserversOnly
+	if len(opts.DNS01DoHResolvers) > 0 {
+		// Authoritative nameservers can only be queried over plain DNS, so
+		// DoH resolvers are always used for the entire self check.
+		nameservers = opts.DNS01DoHResolvers
+		checkAuthoritative = false
+		log.V(logf.InfoLevel).Info("using DNS-over-HTTPS resolvers for acme dns01 self check, authoritative nameservers will not be queried")
+	}
 	if len(nameservers) == 0 {
 		nameservers = dnsutil.RecursiveNameservers
 	}
@@ -221,15 +234,21 @@ func buildControllerContext(ctx context.Context, stopCh <-chan struct{}, opts *o
 			HTTP01SolverResourceRequestMemory: HTTP01SolverResourceRequestMemory,
 			HTTP01SolverResourceLimitsCPU:     HTTP01SolverResourceLimitsCPU,
 			HTTP01SolverResourceLimitsMemory:  HTTP01SolverResourceLimitsMemory,
-			DNS01CheckAuthoritative:           !opts.DNS01RecursiveNameserversOnly,
+			HTTP01SolverCleanupDelay:          opts.HTTP01CleanupDelay,
+			DNS01CheckAuthoritative:           checkAuthoritative,
 			DNS01Nameservers:                  nameservers,
 			AccountRegistry:                   acmeAccountRegistry,
 			DNS01CheckRetryPeriod:             opts.DNS01CheckRetryPeriod,
+			IncludeWildcardApex:               opts.ACMEIncludeWildcardApex,
 		},
 		IssuerOptions: controller.IssuerOptions{
 			ClusterIssuerAmbientCredentials: opts.ClusterIssuerAmbientCredentials,
 			IssuerAmbientCredentials:        opts.IssuerAmbientCredentials,
 			ClusterResourceNamespace:        opts.ClusterResourceNamespace,
+			MaxConcurrentSignsPerIssuer:     opts.MaxConcurrentSignsPerIssuer,
+			FallbackIssuerFailureThreshold:  opts.FallbackIssuerFailureThreshold,
+			FallbackIssuerPendingTimeout:    opts.FallbackIssuerPendingTimeout,
+			EnableVerbatimCSRSigning:        opts.EnableVerbatimCSRSigning,
 		},
 		IngressShimOptions: controller.IngressShimOptions{
 			DefaultIssuerName:                 opts.DefaultIssuerName,
@@ -238,7 +257,14 @@ func buildControllerContext(ctx context.Context, stopCh <-chan struct{}, opts *o
 			DefaultAutoCertificateAnnotations: opts.DefaultAutoCertificateAnnotations,
 		},
 		CertificateOptions: controller.CertificateOptions{
-			EnableOwnerRef: opts.EnableCertificateOwnerRef,
+			EnableOwnerRef:              opts.EnableCertificateOwnerRef,
+			ReadinessClockSkewTolerance: opts.CertificateClockSkewTolerance,
+			ResyncOnIssuerReady:         opts.CertificateResyncOnIssuerReady,
+			DefaultIssuanceTimeout:      opts.CertificateIssuanceTimeout,
+			RenewalJitter:               opts.CertificateRenewalJitter,
+			IssuanceWebhookURL:          opts.CertificateIssuanceWebhookURL,
+			Workers:                     opts.ConcurrentCertificateWorkers,
+			WorkersPerIssuerType:        opts.ConcurrentCertificateWorkersPerIssuerType,
 		},
 		SchedulerOptions: controller.SchedulerOptions{
 			MaxConcurrentChallenges: opts.MaxConcurrentChallenges,
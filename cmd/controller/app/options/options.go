@@ -19,18 +19,22 @@ package options
 import (
 	"fmt"
 	"net"
+	"net/url"
 	"time"
 
 	"github.com/spf13/pflag"
 
 	cm "github.com/jetstack/cert-manager/pkg/apis/certmanager"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	challengescontroller "github.com/jetstack/cert-manager/pkg/controller/acmechallenges"
 	orderscontroller "github.com/jetstack/cert-manager/pkg/controller/acmeorders"
 	cracmecontroller "github.com/jetstack/cert-manager/pkg/controller/certificaterequests/acme"
+	crawspcacontroller "github.com/jetstack/cert-manager/pkg/controller/certificaterequests/awspca"
 	crcacontroller "github.com/jetstack/cert-manager/pkg/controller/certificaterequests/ca"
 	crselfsignedcontroller "github.com/jetstack/cert-manager/pkg/controller/certificaterequests/selfsigned"
 	crvaultcontroller "github.com/jetstack/cert-manager/pkg/controller/certificaterequests/vault"
 	crvenaficontroller "github.com/jetstack/cert-manager/pkg/controller/certificaterequests/venafi"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates/deletionprotection"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates/issuing"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates/keymanager"
 	certificatesmetricscontroller "github.com/jetstack/cert-manager/pkg/controller/certificates/metrics"
@@ -38,6 +42,7 @@ import (
 	"github.com/jetstack/cert-manager/pkg/controller/certificates/requestmanager"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates/trigger"
 	clusterissuerscontroller "github.com/jetstack/cert-manager/pkg/controller/clusterissuers"
+	crlcontroller "github.com/jetstack/cert-manager/pkg/controller/crl"
 	ingressshimcontroller "github.com/jetstack/cert-manager/pkg/controller/ingress-shim"
 	issuerscontroller "github.com/jetstack/cert-manager/pkg/controller/issuers"
 	"github.com/jetstack/cert-manager/pkg/util"
@@ -66,6 +71,14 @@ type ControllerOptions struct {
 	ACMEHTTP01SolverResourceLimitsCPU     string
 	ACMEHTTP01SolverResourceLimitsMemory  string
 
+	// HTTP01CleanupDelay is the time to wait after an HTTP01 challenge has
+	// become valid before deleting its solver resources.
+	HTTP01CleanupDelay time.Duration
+
+	// ACMEIncludeWildcardApex causes the apex domain to be requested
+	// alongside the wildcard DNS names of Certificates issued by ACME issuers.
+	ACMEIncludeWildcardApex bool
+
 	ClusterIssuerAmbientCredentials bool
 	IssuerAmbientCredentials        bool
 
@@ -80,17 +93,75 @@ type ControllerOptions struct {
 	// Allows controlling if recursive nameservers are only used for all checks.
 	// Normally authoritative nameservers are used for checking propagation.
 	DNS01RecursiveNameserversOnly bool
+	// Allows specifying a list of DNS-over-HTTPS endpoint URLs to perform
+	// DNS01 checks with, instead of plain DNS.
+	DNS01DoHResolvers []string
 
 	EnableCertificateOwnerRef bool
 
+	// CertificateClockSkewTolerance is the amount of clock skew tolerated
+	// when checking whether a certificate is within its validity period to
+	// determine whether it is Ready.
+	CertificateClockSkewTolerance time.Duration
+
+	// CertificateResyncOnIssuerReady causes Certificates that failed to be
+	// issued to be retried as soon as their issuer becomes Ready.
+	CertificateResyncOnIssuerReady bool
+
+	// CertificateIssuanceTimeout is the default amount of time an issuance
+	// may remain in progress before it is marked as failed. Zero means
+	// issuances never time out unless spec.issuanceTimeout is set.
+	CertificateIssuanceTimeout time.Duration
+
+	// CertificateRenewalJitter is the maximum fraction of the renewal window
+	// by which the renewal time of each Certificate is moved earlier.
+	CertificateRenewalJitter float64
+
+	// CertificateIssuanceWebhookURL is the URL that a notification is POSTed
+	// to each time a certificate has been issued.
+	CertificateIssuanceWebhookURL string
+
+	// ConcurrentCertificateWorkers is the number of workers each certificates
+	// controller runs to process Certificates whose issuer type does not have
+	// its own number of workers configured.
+	ConcurrentCertificateWorkers int
+
+	// ConcurrentCertificateWorkersPerIssuerType is the number of workers each
+	// certificates controller runs exclusively for Certificates of a given
+	// issuer type, keyed by issuer type.
+	ConcurrentCertificateWorkersPerIssuerType map[string]int
+
 	MaxConcurrentChallenges int
 
+	// MaxConcurrentSignsPerIssuer is the maximum number of CertificateRequests
+	// that may be signed concurrently by a single issuer. Zero means no limit.
+	MaxConcurrentSignsPerIssuer int
+
+	// FallbackIssuerFailureThreshold is the number of times signing a
+	// CertificateRequest with its issuerRef must fail before its
+	// fallbackIssuerRef is used instead.
+	FallbackIssuerFailureThreshold int
+
+	// FallbackIssuerPendingTimeout is how long the issuer referenced by the
+	// issuerRef of a CertificateRequest may be missing or not ready before its
+	// fallbackIssuerRef is used instead.
+	FallbackIssuerPendingTimeout time.Duration
+
+	// EnableVerbatimCSRSigning allows CertificateRequests annotated with
+	// cert-manager.io/sign-csr-verbatim to have their CSR signed as-is by
+	// issuers that support it.
+	EnableVerbatimCSRSigning bool
+
 	// The host and port address, separated by a ':', that the Prometheus server
 	// should expose metrics on.
 	MetricsListenAddress string
 	// EnablePprof controls whether net/http/pprof handlers are registered with
 	// the HTTP listener.
 	EnablePprof bool
+	// WorkqueueBacklogThreshold is the number of items that any controller
+	// workqueue may hold before the /readyz endpoint of the metrics server
+	// reports not ready. If zero, readiness does not depend on the backlog.
+	WorkqueueBacklogThreshold int
 
 	DNS01CheckRetryPeriod time.Duration
 }
@@ -118,13 +189,31 @@ const (
 	defaultTLSACMEIssuerGroup        = cm.GroupName
 	defaultEnableCertificateOwnerRef = false
 
+	defaultCertificateClockSkewTolerance = 30 * time.Second
+
+	defaultCertificateResyncOnIssuerReady = false
+
+	defaultCertificateIssuanceTimeout = time.Duration(0)
+
+	defaultCertificateRenewalJitter = 0.0
+
+	defaultConcurrentCertificateWorkers = 5
+
 	defaultDNS01RecursiveNameserversOnly = false
 
 	defaultMaxConcurrentChallenges = 60
 
+	defaultMaxConcurrentSignsPerIssuer = 0
+
+	defaultFallbackIssuerFailureThreshold = 3
+	defaultFallbackIssuerPendingTimeout   = 30 * time.Minute
+
 	defaultPrometheusMetricsServerAddress = "0.0.0.0:9402"
 
 	defaultDNS01CheckRetryPeriod = 10 * time.Second
+
+	defaultHTTP01CleanupDelay = 0
+	maxHTTP01CleanupDelay     = 5 * time.Minute
 )
 
 var (
@@ -148,12 +237,15 @@ var (
 		crselfsignedcontroller.CRControllerName,
 		crvaultcontroller.CRControllerName,
 		crvenaficontroller.CRControllerName,
+		crawspcacontroller.CRControllerName,
+		crlcontroller.ControllerName,
 		// certificate controllers
 		trigger.ControllerName,
 		issuing.ControllerName,
 		keymanager.ControllerName,
 		requestmanager.ControllerName,
 		readiness.ControllerName,
+		deletionprotection.ControllerName,
 	}
 )
 
@@ -178,9 +270,19 @@ func NewControllerOptions() *ControllerOptions {
 		DefaultAutoCertificateAnnotations: defaultAutoCertificateAnnotations,
 		DNS01RecursiveNameservers:         []string{},
 		DNS01RecursiveNameserversOnly:     defaultDNS01RecursiveNameserversOnly,
+		DNS01DoHResolvers:                 []string{},
 		EnableCertificateOwnerRef:         defaultEnableCertificateOwnerRef,
+		CertificateClockSkewTolerance:     defaultCertificateClockSkewTolerance,
+		CertificateResyncOnIssuerReady:    defaultCertificateResyncOnIssuerReady,
+		CertificateIssuanceTimeout:        defaultCertificateIssuanceTimeout,
+		CertificateRenewalJitter:          defaultCertificateRenewalJitter,
+		ConcurrentCertificateWorkers:      defaultConcurrentCertificateWorkers,
 		MetricsListenAddress:              defaultPrometheusMetricsServerAddress,
 		DNS01CheckRetryPeriod:             defaultDNS01CheckRetryPeriod,
+		HTTP01CleanupDelay:                defaultHTTP01CleanupDelay,
+		MaxConcurrentSignsPerIssuer:       defaultMaxConcurrentSignsPerIssuer,
+		FallbackIssuerFailureThreshold:    defaultFallbackIssuerFailureThreshold,
+		FallbackIssuerPendingTimeout:      defaultFallbackIssuerPendingTimeout,
 		EnablePprof:                       false,
 	}
 }
@@ -237,6 +339,14 @@ func (s *ControllerOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&s.ACMEHTTP01SolverResourceLimitsMemory, "acme-http01-solver-resource-limits-memory", defaultACMEHTTP01SolverResourceLimitsMemory, ""+
 		"Defines the resource limits Memory size when spawning new ACME HTTP01 challenge solver pods.")
 
+	fs.DurationVar(&s.HTTP01CleanupDelay, "http01-cleanup-delay", defaultHTTP01CleanupDelay, ""+
+		"The time to wait after an ACME HTTP01 challenge has become valid before deleting the "+
+		"challenge solver pod, service and ingress. This gives the ACME server time to finish "+
+		"any outstanding validation requests. Must be no longer than 5m.")
+	fs.BoolVar(&s.ACMEIncludeWildcardApex, "acme-include-wildcard-apex", false, ""+
+		"If true, CertificateRequests for Certificates issued by an ACME issuer that request a wildcard DNS name "+
+		"such as '*.example.com' will also request its apex domain 'example.com' if it is not already requested.")
+
 	fs.BoolVar(&s.ClusterIssuerAmbientCredentials, "cluster-issuer-ambient-credentials", defaultClusterIssuerAmbientCredentials, ""+
 		"Whether a cluster-issuer may make use of ambient credentials for issuers. 'Ambient Credentials' are credentials drawn from the environment, metadata services, or local files which are not explicitly configured in the ClusterIssuer API object. "+
 		"When this flag is enabled, the following sources for credentials are also used: "+
@@ -270,11 +380,60 @@ func (s *ControllerOptions) AddFlags(fs *pflag.FlagSet) {
 			"DNS01 check requests. This should be a list containing host and port, "+
 			"for example 8.8.8.8:53,8.8.4.4:53")
 	fs.MarkDeprecated("dns01-self-check-nameservers", "Deprecated in favour of dns01-recursive-nameservers")
+	fs.StringSliceVar(&s.DNS01DoHResolvers, "dns01-doh-resolvers",
+		[]string{}, "A list of comma separated DNS-over-HTTPS (RFC 8484) endpoint "+
+			"URLs used for DNS01 check requests instead of plain DNS, for example "+
+			"https://cloudflare-dns.com/dns-query. As authoritative nameservers "+
+			"cannot be queried over HTTPS, only the configured resolvers will be "+
+			"used to perform the DNS01 self check.")
 	fs.BoolVar(&s.EnableCertificateOwnerRef, "enable-certificate-owner-ref", defaultEnableCertificateOwnerRef, ""+
 		"Whether to set the certificate resource as an owner of secret where the tls certificate is stored. "+
-		"When this flag is enabled, the secret will be automatically removed when the certificate resource is deleted.")
+		"When this flag is enabled, the secret will be automatically removed when the certificate resource is deleted. "+
+		"This can be overridden for individual certificates using the cert-manager.io/secret-owner-reference annotation.")
+	fs.DurationVar(&s.CertificateClockSkewTolerance, "certificate-clock-skew-tolerance", defaultCertificateClockSkewTolerance, ""+
+		"The amount of clock skew to tolerate when comparing the current time against the NotBefore and NotAfter "+
+		"times of a certificate to determine whether it is Ready.")
+	fs.BoolVar(&s.CertificateResyncOnIssuerReady, "certificate-resync-on-issuer-ready", defaultCertificateResyncOnIssuerReady, ""+
+		"If true, certificates that failed to be issued are retried as soon as their Issuer or ClusterIssuer "+
+		"becomes Ready, instead of waiting for the 1 hour failure back-off to elapse.")
+	fs.DurationVar(&s.CertificateIssuanceTimeout, "certificate-issuance-timeout", defaultCertificateIssuanceTimeout, ""+
+		"The default amount of time an issuance may remain in progress before the certificate is marked as failed "+
+		"and retried after the failure back-off. This can be overridden for individual certificates using "+
+		"spec.issuanceTimeout. Set to 0 to disable the timeout.")
+	fs.Float64Var(&s.CertificateRenewalJitter, "certificate-renewal-jitter", defaultCertificateRenewalJitter, ""+
+		"The maximum fraction of the renewal window, the time between a certificate's renewal time and its expiry, "+
+		"by which the renewal of each certificate is brought forward. The amount is derived from the UID of the "+
+		"certificate so that certificates issued at the same time are not all renewed at once. "+
+		"Must be at least 0 and less than 1. Set to 0 to disable jitter.")
+	fs.StringVar(&s.CertificateIssuanceWebhookURL, "certificate-issuance-webhook-url", "", ""+
+		"If set, a JSON notification describing each issued certificate, including its name, subject alternative names, "+
+		"issuer, serial number and expiry, is POSTed to this http or https URL. Delivery is retried in the background "+
+		"and never delays issuance.")
+	fs.IntVar(&s.ConcurrentCertificateWorkers, "concurrent-certificate-workers", defaultConcurrentCertificateWorkers, ""+
+		"The number of workers each certificates controller uses to process Certificates whose issuer type "+
+		"does not have its own number of workers set with --concurrent-certificate-workers-per-issuer-type.")
+	fs.StringToIntVar(&s.ConcurrentCertificateWorkersPerIssuerType, "concurrent-certificate-workers-per-issuer-type", nil, ""+
+		"A set of issuer type to number of workers pairs, for example 'venafi=2,acme=10'. Each certificates controller "+
+		"runs the given number of additional workers dedicated to Certificates of that issuer type, so that "+
+		"Certificates using a slow issuer do not hold up those using other issuers. Issuer types are acme, awspca, ca, "+
+		"selfsigned, vault and venafi, or the API group of an external issuer.")
 	fs.IntVar(&s.MaxConcurrentChallenges, "max-concurrent-challenges", defaultMaxConcurrentChallenges, ""+
 		"The maximum number of challenges that can be scheduled as 'processing' at once.")
+	fs.IntVar(&s.MaxConcurrentSignsPerIssuer, "max-concurrent-signs-per-issuer", defaultMaxConcurrentSignsPerIssuer, ""+
+		"The maximum number of CertificateRequests that may be signed concurrently by a single issuer. "+
+		"CertificateRequests over this limit are requeued until a slot becomes available. "+
+		"Set to 0 to disable the limit.")
+	fs.IntVar(&s.FallbackIssuerFailureThreshold, "fallback-issuer-failure-threshold", defaultFallbackIssuerFailureThreshold, ""+
+		"The number of times signing a CertificateRequest with its issuerRef must fail before the issuer referenced "+
+		"by its fallbackIssuerRef is used instead.")
+	fs.DurationVar(&s.FallbackIssuerPendingTimeout, "fallback-issuer-pending-timeout", defaultFallbackIssuerPendingTimeout, ""+
+		"The amount of time the issuer referenced by the issuerRef of a CertificateRequest may be missing or not ready "+
+		"before the issuer referenced by its fallbackIssuerRef is used instead. Requests that the issuer is still "+
+		"working on, such as pending ACME orders, are not affected.")
+	fs.BoolVar(&s.EnableVerbatimCSRSigning, "enable-verbatim-csr-signing", false, ""+
+		"If true, CertificateRequests annotated with 'cert-manager.io/sign-csr-verbatim: \"true\"' will have "+
+		"their CSR signed as-is by the CA and Vault issuers, even if the key usages or subject it requests "+
+		"differ from those set on the CertificateRequest.")
 	fs.DurationVar(&s.DNS01CheckRetryPeriod, "dns01-check-retry-period", defaultDNS01CheckRetryPeriod, ""+
 		"The duration the controller should wait between checking if a ACME dns entry exists."+
 		"This should be a valid duration string, for example 180s or 1h")
@@ -283,6 +442,10 @@ func (s *ControllerOptions) AddFlags(fs *pflag.FlagSet) {
 		"The host and port that the metrics endpoint should listen on.")
 	fs.BoolVar(&s.EnablePprof, "enable-profiling", false, ""+
 		"Enable profiling for controller.")
+	fs.IntVar(&s.WorkqueueBacklogThreshold, "workqueue-backlog-threshold", 0, ""+
+		"The number of items that any controller workqueue may hold before the /readyz endpoint "+
+		"of the metrics server reports not ready. If set to 0, readiness does not depend on the "+
+		"workqueue backlog.")
 }
 
 func (o *ControllerOptions) Validate() error {
@@ -305,6 +468,54 @@ func (o *ControllerOptions) Validate() error {
 		return fmt.Errorf("invalid value for kube-api-burst: %v must be higher or equal to kube-api-qps: %v", o.KubernetesAPIQPS, o.KubernetesAPIQPS)
 	}
 
+	if o.HTTP01CleanupDelay < 0 || o.HTTP01CleanupDelay > maxHTTP01CleanupDelay {
+		return fmt.Errorf("invalid value for http01-cleanup-delay: %v must be between 0 and %v", o.HTTP01CleanupDelay, maxHTTP01CleanupDelay)
+	}
+
+	if o.WorkqueueBacklogThreshold < 0 {
+		return fmt.Errorf("invalid value for workqueue-backlog-threshold: %v must be 0 or higher", o.WorkqueueBacklogThreshold)
+	}
+
+	if o.CertificateClockSkewTolerance < 0 {
+		return fmt.Errorf("invalid value for certificate-clock-skew-tolerance: %v must be 0 or higher", o.CertificateClockSkewTolerance)
+	}
+
+	if o.CertificateIssuanceTimeout != 0 && o.CertificateIssuanceTimeout < cmapi.MinimumIssuanceTimeout {
+		return fmt.Errorf("invalid value for certificate-issuance-timeout: %v must be 0 or at least %v", o.CertificateIssuanceTimeout, cmapi.MinimumIssuanceTimeout)
+	}
+
+	if o.ConcurrentCertificateWorkers < 1 {
+		return fmt.Errorf("invalid value for concurrent-certificate-workers: %v must be 1 or higher", o.ConcurrentCertificateWorkers)
+	}
+
+	for issuerType, workers := range o.ConcurrentCertificateWorkersPerIssuerType {
+		if workers < 1 {
+			return fmt.Errorf("invalid value for concurrent-certificate-workers-per-issuer-type: %v workers for issuer type %q must be 1 or higher", workers, issuerType)
+		}
+	}
+
+	if o.CertificateRenewalJitter < 0 || o.CertificateRenewalJitter >= 1 {
+		return fmt.Errorf("invalid value for certificate-renewal-jitter: %v must be at least 0 and less than 1", o.CertificateRenewalJitter)
+	}
+
+	if len(o.CertificateIssuanceWebhookURL) > 0 {
+		if u, err := url.Parse(o.CertificateIssuanceWebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+			return fmt.Errorf("invalid value for certificate-issuance-webhook-url: %q must be a http or https URL", o.CertificateIssuanceWebhookURL)
+		}
+	}
+
+	if o.MaxConcurrentSignsPerIssuer < 0 {
+		return fmt.Errorf("invalid value for max-concurrent-signs-per-issuer: %v must be 0 or higher", o.MaxConcurrentSignsPerIssuer)
+	}
+
+	if o.FallbackIssuerFailureThreshold < 1 {
+		return fmt.Errorf("invalid value for fallback-issuer-failure-threshold: %v must be 1 or higher", o.FallbackIssuerFailureThreshold)
+	}
+
+	if o.FallbackIssuerPendingTimeout <= 0 {
+		return fmt.Errorf("invalid value for fallback-issuer-pending-timeout: %v must be greater than 0", o.FallbackIssuerPendingTimeout)
+	}
+
 	for _, server := range o.DNS01RecursiveNameservers {
 		// ensure all servers have a port number
 		_, _, err := net.SplitHostPort(server)
@@ -313,5 +524,19 @@ func (o *ControllerOptions) Validate() error {
 		}
 	}
 
+	if len(o.DNS01DoHResolvers) > 0 && len(o.DNS01RecursiveNameservers) > 0 {
+		return fmt.Errorf("dns01-doh-resolvers cannot be used together with dns01-recursive-nameservers")
+	}
+
+	for _, resolver := range o.DNS01DoHResolvers {
+		u, err := url.Parse(resolver)
+		if err != nil {
+			return fmt.Errorf("invalid DNS-over-HTTPS resolver (%v): %v", err, resolver)
+		}
+		if u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("invalid DNS-over-HTTPS resolver, must be an https URL: %v", resolver)
+		}
+	}
+
 	return nil
 }
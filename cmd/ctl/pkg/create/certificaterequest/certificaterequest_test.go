@@ -20,7 +20,16 @@ import (
 	"context"
 	"io/ioutil"
 	"os"
+	"reflect"
 	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+	"github.com/jetstack/cert-manager/test/unit/gen"
 )
 
 func TestValidate(t *testing.T) {
@@ -241,3 +250,75 @@ spec:
 		})
 	}
 }
+
+func TestBuildCertificateRequest(t *testing.T) {
+	crt := gen.Certificate("testcert-1",
+		gen.SetCertificateNamespace("testns-1"),
+		gen.SetCertificateCommonName("example.com"),
+		gen.SetCertificateDNSNames("example.com", "www.example.com"),
+		gen.SetCertificateIPs("10.0.0.1", "2001:db8::1"),
+		gen.SetCertificateURIs("spiffe://cluster.local/ns/testns-1/sa/default"),
+		gen.SetCertificateDuration(time.Hour*24),
+		gen.SetCertificateIsCA(true),
+		gen.SetCertificateKeyUsages(cmapi.UsageDigitalSignature, cmapi.UsageCertSign),
+		gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "ca-issuer", Kind: "Issuer", Group: "cert-manager.io"}),
+	)
+	crt.Spec.EmailAddresses = []string{"admin@example.com"}
+
+	pk, err := pki.GenerateRSAPrivateKey(2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, err := buildCertificateRequest(crt, pki.EncodePKCS1PrivateKey(pk), "testcr-1")
+	if err != nil {
+		t.Fatalf("unexpected error building CertificateRequest: %v", err)
+	}
+
+	if req.Name != "testcr-1" {
+		t.Errorf("unexpected CertificateRequest name, exp=%q got=%q", "testcr-1", req.Name)
+	}
+	if req.Spec.IssuerRef != crt.Spec.IssuerRef {
+		t.Errorf("unexpected issuerRef, exp=%v got=%v", crt.Spec.IssuerRef, req.Spec.IssuerRef)
+	}
+	if !reflect.DeepEqual(req.Spec.Duration, &metav1.Duration{Duration: time.Hour * 24}) {
+		t.Errorf("unexpected duration, exp=%v got=%v", time.Hour*24, req.Spec.Duration)
+	}
+	if !req.Spec.IsCA {
+		t.Errorf("expected CertificateRequest to be a CA")
+	}
+	if !reflect.DeepEqual(req.Spec.Usages, crt.Spec.Usages) {
+		t.Errorf("unexpected usages, exp=%v got=%v", crt.Spec.Usages, req.Spec.Usages)
+	}
+
+	csr, err := pki.DecodeX509CertificateRequestBytes(req.Spec.Request)
+	if err != nil {
+		t.Fatalf("failed to decode CSR: %v", err)
+	}
+	if err := csr.CheckSignature(); err != nil {
+		t.Errorf("CSR signature is invalid: %v", err)
+	}
+	equal, err := pki.PublicKeysEqual(csr.PublicKey, pk.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equal {
+		t.Errorf("CSR public key does not match the generated private key")
+	}
+
+	if csr.Subject.CommonName != crt.Spec.CommonName {
+		t.Errorf("unexpected CSR common name, exp=%q got=%q", crt.Spec.CommonName, csr.Subject.CommonName)
+	}
+	if !reflect.DeepEqual(csr.DNSNames, crt.Spec.DNSNames) {
+		t.Errorf("unexpected CSR DNS names, exp=%v got=%v", crt.Spec.DNSNames, csr.DNSNames)
+	}
+	if ips := pki.IPAddressesToString(csr.IPAddresses); !reflect.DeepEqual(ips, crt.Spec.IPAddresses) {
+		t.Errorf("unexpected CSR IP addresses, exp=%v got=%v", crt.Spec.IPAddresses, ips)
+	}
+	if uris := pki.URLsToString(csr.URIs); !reflect.DeepEqual(uris, crt.Spec.URIs) {
+		t.Errorf("unexpected CSR URIs, exp=%v got=%v", crt.Spec.URIs, uris)
+	}
+	if !reflect.DeepEqual(csr.EmailAddresses, crt.Spec.EmailAddresses) {
+		t.Errorf("unexpected CSR email addresses, exp=%v got=%v", crt.Spec.EmailAddresses, csr.EmailAddresses)
+	}
+}
@@ -20,6 +20,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
@@ -49,7 +52,10 @@ kubectl cert-manager renew my-app vault
 kubectl cert-manager renew --namespace kube-system --all
 
 # Renew all Certificates in all namespaces, provided those Certificates have the label 'app=my-service'
-kubectl cert-manager renew --all-namespaces -l app=my-service`))
+kubectl cert-manager renew --all-namespaces -l app=my-service
+
+# Renew all Certificates in all namespaces that expire within the next 30 days.
+kubectl cert-manager renew --all-namespaces --all-expiring-within 30d`))
 )
 
 // Options is a struct to support renew command
@@ -63,6 +69,9 @@ type Options struct {
 	LabelSelector string
 	All           bool
 	AllNamespaces bool
+	// AllExpiringWithin selects all Certificates whose certificate expires
+	// within the given duration, e.g. '12h' or '30d'.
+	AllExpiringWithin string
 
 	genericclioptions.IOStreams
 }
@@ -92,6 +101,7 @@ func NewCmdRenew(ctx context.Context, ioStreams genericclioptions.IOStreams, fac
 	cmd.Flags().StringVarP(&o.LabelSelector, "selector", "l", o.LabelSelector, "Selector (label query) to filter on, supports '=', '==', and '!='.(e.g. -l key1=value1,key2=value2)")
 	cmd.Flags().BoolVarP(&o.AllNamespaces, "all-namespaces", "A", o.AllNamespaces, "If present, mark Certificates across namespaces for manual renewal. Namespace in current context is ignored even if specified with --namespace.")
 	cmd.Flags().BoolVar(&o.All, "all", o.All, "Renew all Certificates in the given Namespace, or all namespaces with --all-namespaces enabled.")
+	cmd.Flags().StringVar(&o.AllExpiringWithin, "all-expiring-within", o.AllExpiringWithin, "Renew all Certificates whose certificate expires within the given duration (e.g. 12h or 30d) in the given Namespace, or all namespaces with --all-namespaces enabled.")
 
 	return cmd
 }
@@ -114,6 +124,20 @@ func (o *Options) Validate(cmd *cobra.Command, args []string) error {
 		return errors.New("cannot specify --namespace flag in conjunction with --all flag")
 	}
 
+	if len(o.AllExpiringWithin) > 0 {
+		if o.All {
+			return errors.New("cannot specify --all flag in conjunction with --all-expiring-within flag")
+		}
+
+		if len(args) > 0 {
+			return errors.New("cannot specify Certificate names in conjunction with --all-expiring-within flag")
+		}
+
+		if _, err := parseExpiringWithin(o.AllExpiringWithin); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -160,7 +184,7 @@ func (o *Options) Run(ctx context.Context, args []string) error {
 	var crts []cmapi.Certificate
 	for _, ns := range nss {
 		switch {
-		case o.All, len(o.LabelSelector) > 0:
+		case o.All, len(o.LabelSelector) > 0, len(o.AllExpiringWithin) > 0:
 			crtsList, err := o.CMClient.CertmanagerV1().Certificates(ns.Name).List(ctx, metav1.ListOptions{
 				LabelSelector: o.LabelSelector,
 			})
@@ -182,6 +206,15 @@ func (o *Options) Run(ctx context.Context, args []string) error {
 		}
 	}
 
+	if len(o.AllExpiringWithin) > 0 {
+		within, err := parseExpiringWithin(o.AllExpiringWithin)
+		if err != nil {
+			return err
+		}
+
+		crts = certificatesExpiringBefore(crts, time.Now().Add(within))
+	}
+
 	if len(crts) == 0 {
 		if o.AllNamespaces {
 			fmt.Fprintln(o.ErrOut, "No Certificates found")
@@ -210,3 +243,46 @@ func (o *Options) renewCertificate(ctx context.Context, crt *cmapi.Certificate)
 	fmt.Fprintf(o.Out, "Manually triggered issuance of Certificate %s/%s\n", crt.Namespace, crt.Name)
 	return nil
 }
+
+// certificatesExpiringBefore returns the Certificates whose certificate has a
+// notAfter time before the given deadline. Certificates that have not been
+// issued yet are skipped.
+func certificatesExpiringBefore(crts []cmapi.Certificate, deadline time.Time) []cmapi.Certificate {
+	var expiring []cmapi.Certificate
+	for _, crt := range crts {
+		if crt.Status.NotAfter == nil {
+			continue
+		}
+
+		if crt.Status.NotAfter.Time.Before(deadline) {
+			expiring = append(expiring, crt)
+		}
+	}
+
+	return expiring
+}
+
+// parseExpiringWithin parses a duration as accepted by time.ParseDuration,
+// additionally accepting a whole number of days such as '30d'.
+func parseExpiringWithin(s string) (time.Duration, error) {
+	var d time.Duration
+	if days := strings.TrimSuffix(s, "d"); days != s {
+		n, err := strconv.ParseUint(days, 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("invalid --all-expiring-within duration %q: %v", s, err)
+		}
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		d, err = time.ParseDuration(s)
+		if err != nil {
+			return 0, fmt.Errorf("invalid --all-expiring-within duration %q: %v", s, err)
+		}
+	}
+
+	if d <= 0 {
+		return 0, fmt.Errorf("invalid --all-expiring-within duration %q: must be greater than zero", s)
+	}
+
+	return d, nil
+}
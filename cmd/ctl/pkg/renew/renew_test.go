@@ -17,10 +17,20 @@ limitations under the License.
 package renew
 
 import (
+	"bytes"
 	"context"
 	"testing"
+	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/jetstack/cert-manager/pkg/client/clientset/versioned/fake"
+	"github.com/jetstack/cert-manager/test/unit/gen"
 )
 
 type stringFlag struct {
@@ -72,6 +82,39 @@ func TestValidate(t *testing.T) {
 			},
 			expErr: true,
 		},
+		"If --all-expiring-within is specified with a label selector, don't error": {
+			options: &Options{
+				AllExpiringWithin: "30d",
+				LabelSelector:     "foo=bar",
+			},
+			expErr: false,
+		},
+		"If --all-expiring-within is specified with --all, error": {
+			options: &Options{
+				AllExpiringWithin: "30d",
+				All:               true,
+			},
+			expErr: true,
+		},
+		"If --all-expiring-within is specified with arguments, error": {
+			options: &Options{
+				AllExpiringWithin: "30d",
+			},
+			args:   []string{"abc"},
+			expErr: true,
+		},
+		"If --all-expiring-within is not a valid duration, error": {
+			options: &Options{
+				AllExpiringWithin: "a month",
+			},
+			expErr: true,
+		},
+		"If --all-expiring-within is not positive, error": {
+			options: &Options{
+				AllExpiringWithin: "0d",
+			},
+			expErr: true,
+		},
 	}
 
 	for name, test := range tests {
@@ -99,3 +142,82 @@ func TestValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestParseExpiringWithin(t *testing.T) {
+	tests := map[string]struct {
+		input  string
+		exp    time.Duration
+		expErr bool
+	}{
+		"days":              {input: "30d", exp: 30 * 24 * time.Hour},
+		"hours":             {input: "12h", exp: 12 * time.Hour},
+		"mixed units":       {input: "1h30m", exp: 90 * time.Minute},
+		"fractional days":   {input: "1.5d", expErr: true},
+		"negative duration": {input: "-1h", expErr: true},
+		"invalid":           {input: "soon", expErr: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			d, err := parseExpiringWithin(test.input)
+			if test.expErr != (err != nil) {
+				t.Fatalf("expected error=%t got=%v", test.expErr, err)
+			}
+			if d != test.exp {
+				t.Errorf("expected duration %s, got %s", test.exp, d)
+			}
+		})
+	}
+}
+
+func TestRunAllExpiringWithin(t *testing.T) {
+	expiringIn := func(name string, d time.Duration) runtime.Object {
+		return gen.Certificate(name,
+			gen.SetCertificateNamespace(gen.DefaultTestNamespace),
+			gen.SetCertificateNotAfter(metav1.NewTime(time.Now().Add(d))),
+		)
+	}
+
+	cmClient := fake.NewSimpleClientset(
+		expiringIn("expired", -time.Hour),
+		expiringIn("expires-in-1h", time.Hour),
+		expiringIn("expires-in-29d", 29*24*time.Hour),
+		expiringIn("expires-in-31d", 31*24*time.Hour),
+		expiringIn("expires-in-1y", 365*24*time.Hour),
+		gen.Certificate("not-issued", gen.SetCertificateNamespace(gen.DefaultTestNamespace)),
+	)
+
+	out := new(bytes.Buffer)
+	o := &Options{
+		CMClient:          cmClient,
+		Namespace:         gen.DefaultTestNamespace,
+		AllExpiringWithin: "30d",
+		IOStreams:         genericclioptions.IOStreams{Out: out, ErrOut: out},
+	}
+	if err := o.Run(context.TODO(), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	expRenewed := map[string]bool{
+		"expired":        true,
+		"expires-in-1h":  true,
+		"expires-in-29d": true,
+		"expires-in-31d": false,
+		"expires-in-1y":  false,
+		"not-issued":     false,
+	}
+	for name, exp := range expRenewed {
+		crt, err := cmClient.CertmanagerV1().Certificates(gen.DefaultTestNamespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		renewed := apiutil.CertificateHasCondition(crt, cmapi.CertificateCondition{
+			Type:   cmapi.CertificateConditionIssuing,
+			Status: cmmeta.ConditionTrue,
+		})
+		if renewed != exp {
+			t.Errorf("Certificate %q: expected renewed=%t, got=%t", name, exp, renewed)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"fmt"
+	"strings"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+)
+
+// treeNode is a node of the tree of resources involved in issuing a
+// Certificate, along with a summary of the state of the resource.
+type treeNode struct {
+	label    string
+	children []*treeNode
+}
+
+func (n *treeNode) addChild(label string) *treeNode {
+	child := &treeNode{label: label}
+	n.children = append(n.children, child)
+	return child
+}
+
+// render returns the tree rooted at n, with each node on a new line and
+// connected to its parent.
+func (n *treeNode) render() string {
+	var b strings.Builder
+	b.WriteString(n.label + "\n")
+	n.renderChildren(&b, "")
+	return b.String()
+}
+
+func (n *treeNode) renderChildren(b *strings.Builder, prefix string) {
+	for i, child := range n.children {
+		connector, childPrefix := "├── ", "│   "
+		if i == len(n.children)-1 {
+			connector, childPrefix = "└── ", "    "
+		}
+		b.WriteString(prefix + connector + child.label + "\n")
+		child.renderChildren(b, prefix+childPrefix)
+	}
+}
+
+// TreeString returns the chain of resources involved in issuing the
+// Certificate, from the Certificate down to the ACME Challenges, as a tree
+// to be printed as output. Each resource is annotated with its state and the
+// last error reported on it, if any.
+func (status *CertificateStatus) TreeString() string {
+	return "Resource Tree:\n" + status.resourceTree().render()
+}
+
+func (status *CertificateStatus) resourceTree() *treeNode {
+	root := &treeNode{label: fmt.Sprintf("Certificate %s/%s: %s", status.Namespace, status.Name, certificateSummary(status.Conditions))}
+
+	switch {
+	case status.CRStatus == nil:
+		return root
+	case status.CRStatus.Error != nil:
+		root.addChild("CertificateRequest: " + errorSummary(status.CRStatus.Error))
+		return root
+	}
+	crNode := root.addChild(fmt.Sprintf("CertificateRequest %s: %s", status.CRStatus.Name, certificateRequestSummary(status.CRStatus.Conditions)))
+
+	// OrderStatus is nil if the Issuer/ClusterIssuer is not an ACME Issuer
+	switch {
+	case status.OrderStatus == nil:
+		return root
+	case status.OrderStatus.Error != nil:
+		crNode.addChild("Order: " + errorSummary(status.OrderStatus.Error))
+		return root
+	}
+	orderNode := crNode.addChild(fmt.Sprintf("Order %s: %s", status.OrderStatus.Name, status.OrderStatus.summary()))
+
+	switch {
+	case status.ChallengeStatusList == nil:
+		return root
+	case status.ChallengeStatusList.Error != nil:
+		orderNode.addChild("Challenges: " + errorSummary(status.ChallengeStatusList.Error))
+		return root
+	}
+	for _, ch := range status.ChallengeStatusList.ChallengeStatuses {
+		orderNode.addChild(fmt.Sprintf("Challenge %s: %s", ch.Name, ch.summary()))
+	}
+
+	return root
+}
+
+// certificateSummary returns a summary of the Ready condition of a
+// Certificate, including the reason and message if it is not Ready.
+func certificateSummary(conditions []cmapi.CertificateCondition) string {
+	for _, con := range conditions {
+		if con.Type == cmapi.CertificateConditionReady {
+			return readySummary(con.Status, con.Reason, con.Message)
+		}
+	}
+	return "No Ready condition set"
+}
+
+// certificateRequestSummary returns a summary of the Ready condition of a
+// CertificateRequest, including the reason and message if it is not Ready.
+func certificateRequestSummary(conditions []cmapi.CertificateRequestCondition) string {
+	for _, con := range conditions {
+		if con.Type == cmapi.CertificateRequestConditionReady {
+			return readySummary(con.Status, con.Reason, con.Message)
+		}
+	}
+	return "No Ready condition set"
+}
+
+func readySummary(status cmmeta.ConditionStatus, reason, message string) string {
+	if status == cmmeta.ConditionTrue {
+		return "Ready"
+	}
+	return fmt.Sprintf("Not Ready, Reason: %s, Message: %s", reason, message)
+}
+
+func (orderStatus *OrderStatus) summary() string {
+	summary := fmt.Sprintf("State: %s, URL: %s", orDefault(string(orderStatus.State), "<none>"), orDefault(orderStatus.URL, "<none>"))
+	if orderStatus.Reason != "" {
+		summary += ", Reason: " + orderStatus.Reason
+	}
+	return summary
+}
+
+func (challengeStatus *ChallengeStatus) summary() string {
+	summary := fmt.Sprintf("Type: %s, DNS Name: %s, State: %s", challengeStatus.Type, orDefault(challengeStatus.DNSName, "<none>"), orDefault(string(challengeStatus.State), "<none>"))
+	if challengeStatus.Reason != "" {
+		summary += ", Reason: " + challengeStatus.Reason
+	}
+	return summary
+}
+
+// errorSummary returns the error message on a single line, as errors found
+// when gathering resources are terminated with a newline.
+func errorSummary(err error) string {
+	return strings.TrimSpace(err.Error())
+}
+
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
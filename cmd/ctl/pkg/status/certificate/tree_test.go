@@ -0,0 +1,160 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1beta1"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestTreeString(t *testing.T) {
+	ns := "ns1"
+	challengeErr := `error getting clouddns service: googleapi: Error 403: Forbidden, forbidden`
+
+	crt := gen.Certificate("test-crt",
+		gen.SetCertificateNamespace(ns),
+		gen.SetCertificateStatusCondition(cmapi.CertificateCondition{Type: cmapi.CertificateConditionReady,
+			Status: cmmeta.ConditionFalse, Reason: "DoesNotExist", Message: "Issuing certificate as Secret does not exist"}),
+	)
+	req := gen.CertificateRequest("test-req",
+		gen.SetCertificateRequestNamespace(ns),
+		gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{Type: cmapi.CertificateRequestConditionReady,
+			Status: cmmeta.ConditionFalse, Reason: "Pending", Message: "Waiting on certificate issuance from order ns1/test-order: \"pending\""}),
+	)
+	order := &cmacme.Order{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-order", Namespace: ns},
+		Status: cmacme.OrderStatus{
+			URL:   "https://acme.example.com/order/1234",
+			State: cmacme.Pending,
+		},
+	}
+	challenges := []*cmacme.Challenge{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-challenge1", Namespace: ns},
+			Spec:       cmacme.ChallengeSpec{Type: cmacme.ACMEChallengeTypeDNS01, DNSName: "example.com"},
+			Status:     cmacme.ChallengeStatus{State: cmacme.Pending, Reason: challengeErr},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-challenge2", Namespace: ns},
+			Spec:       cmacme.ChallengeSpec{Type: cmacme.ACMEChallengeTypeHTTP01, DNSName: "www.example.com"},
+			Status:     cmacme.ChallengeStatus{State: cmacme.Valid},
+		},
+	}
+
+	tests := map[string]struct {
+		inputData *Data
+		expOutput string
+	}{
+		"full tree with challenge error": {
+			inputData: &Data{
+				Certificate: crt,
+				Req:         req,
+				Order:       order,
+				Challenges:  challenges,
+			},
+			expOutput: `Resource Tree:
+Certificate ns1/test-crt: Not Ready, Reason: DoesNotExist, Message: Issuing certificate as Secret does not exist
+└── CertificateRequest test-req: Not Ready, Reason: Pending, Message: Waiting on certificate issuance from order ns1/test-order: "pending"
+    └── Order test-order: State: pending, URL: https://acme.example.com/order/1234
+        ├── Challenge test-challenge1: Type: DNS-01, DNS Name: example.com, State: pending, Reason: ` + challengeErr + `
+        └── Challenge test-challenge2: Type: HTTP-01, DNS Name: www.example.com, State: valid
+`,
+		},
+		"tree stops at CertificateRequest error": {
+			inputData: &Data{
+				Certificate: crt,
+				ReqError:    errors.New("No CertificateRequest found for this Certificate\n"),
+			},
+			expOutput: `Resource Tree:
+Certificate ns1/test-crt: Not Ready, Reason: DoesNotExist, Message: Issuing certificate as Secret does not exist
+└── CertificateRequest: No CertificateRequest found for this Certificate
+`,
+		},
+		"tree stops at CertificateRequest for non ACME issuers": {
+			inputData: &Data{
+				Certificate: gen.Certificate("test-crt",
+					gen.SetCertificateNamespace(ns),
+					gen.SetCertificateStatusCondition(cmapi.CertificateCondition{Type: cmapi.CertificateConditionReady, Status: cmmeta.ConditionTrue}),
+				),
+				Req: gen.CertificateRequest("test-req",
+					gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{Type: cmapi.CertificateRequestConditionReady, Status: cmmeta.ConditionTrue}),
+				),
+			},
+			expOutput: `Resource Tree:
+Certificate ns1/test-crt: Ready
+└── CertificateRequest test-req: Ready
+`,
+		},
+		"order error is shown under CertificateRequest": {
+			inputData: &Data{
+				Certificate: crt,
+				Req:         req,
+				OrderError:  errors.New("No Order found for this Certificate\n"),
+			},
+			expOutput: `Resource Tree:
+Certificate ns1/test-crt: Not Ready, Reason: DoesNotExist, Message: Issuing certificate as Secret does not exist
+└── CertificateRequest test-req: Not Ready, Reason: Pending, Message: Waiting on certificate issuance from order ns1/test-order: "pending"
+    └── Order: No Order found for this Certificate
+`,
+		},
+		"challenges error is shown under Order": {
+			inputData: &Data{
+				Certificate:  crt,
+				Req:          req,
+				Order:        order,
+				ChallengeErr: errors.New("No Challenges found for this Certificate\n"),
+			},
+			expOutput: `Resource Tree:
+Certificate ns1/test-crt: Not Ready, Reason: DoesNotExist, Message: Issuing certificate as Secret does not exist
+└── CertificateRequest test-req: Not Ready, Reason: Pending, Message: Waiting on certificate issuance from order ns1/test-order: "pending"
+    └── Order test-order: State: pending, URL: https://acme.example.com/order/1234
+        └── Challenges: No Challenges found for this Certificate
+`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			status := StatusFromResources(test.inputData)
+			if actualOutput := status.TreeString(); actualOutput != test.expOutput {
+				t.Errorf("Unexpected output; expected: \n%s\nactual: \n%s", test.expOutput, actualOutput)
+			}
+		})
+	}
+
+	t.Run("status output includes the challenge error", func(t *testing.T) {
+		status := StatusFromResources(&Data{
+			Certificate: crt,
+			IssuerError: errors.New("error when getting Issuer\n"),
+			SecretError: errors.New("error when finding Secret\n"),
+			Req:         req,
+			Order:       order,
+			Challenges:  challenges,
+		})
+		if output := status.String(); !strings.Contains(output, "Challenge test-challenge1: Type: DNS-01, DNS Name: example.com, State: pending, Reason: "+challengeErr) {
+			t.Errorf("expected status output to contain the challenge error, got:\n%s", output)
+		}
+	})
+}
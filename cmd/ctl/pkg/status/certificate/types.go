@@ -129,6 +129,8 @@ type OrderStatus struct {
 	Error error
 	// Name of the Order resource
 	Name string
+	// URL of the order on the ACME server
+	URL string
 	// State of Order resource
 	State cmacme.State
 	// Reason why the Order resource is in its State
@@ -149,6 +151,7 @@ type ChallengeStatusList struct {
 type ChallengeStatus struct {
 	Name       string
 	Type       cmacme.ACMEChallengeType
+	DNSName    string
 	Token      string
 	Key        string
 	State      cmacme.State
@@ -242,7 +245,7 @@ func (status *CertificateStatus) withOrder(order *cmacme.Order, err error) *Cert
 		return status
 	}
 
-	status.OrderStatus = &OrderStatus{Name: order.Name, State: order.Status.State,
+	status.OrderStatus = &OrderStatus{Name: order.Name, URL: order.Status.URL, State: order.Status.State,
 		Reason: order.Status.Reason, Authorizations: order.Status.Authorizations,
 		FailureTime: order.Status.FailureTime}
 	return status
@@ -262,6 +265,7 @@ func (status *CertificateStatus) withChallenges(challenges []*cmacme.Challenge,
 		list = append(list, &ChallengeStatus{
 			Name:       challenge.Name,
 			Type:       challenge.Spec.Type,
+			DNSName:    challenge.Spec.DNSName,
 			Token:      challenge.Spec.Token,
 			Key:        challenge.Spec.Key,
 			State:      challenge.Status.State,
@@ -313,6 +317,8 @@ func (status *CertificateStatus) String() string {
 		output += status.ChallengeStatusList.String()
 	}
 
+	output += status.TreeString()
+
 	return output
 }
 
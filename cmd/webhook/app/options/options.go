@@ -43,8 +43,42 @@ type WebhookOptions struct {
 	// List of DNSNames that must be present on serving certificates.
 	DynamicServingDNSNames []string
 
+	// EnableNamespaceDefaultIssuer defaults the issuerRef of Certificates that
+	// do not specify one to the issuer named in the
+	// cert-manager.io/default-issuer annotation of their Namespace.
+	EnableNamespaceDefaultIssuer bool
+
+	// EnableCertificateTemplates sets the unset fields of Certificates that
+	// reference a template ConfigMap in spec.templateRef from that template.
+	EnableCertificateTemplates bool
+
+	// EnableIssuerValidation resolves the issuer referenced by a Certificate,
+	// so that the Certificate can be checked against the requirements of its
+	// issuer.
+	EnableIssuerValidation bool
+
+	// CertificateSecretNamePattern is a regular expression that the
+	// spec.secretName of every Certificate must match.
+	// If not specified, any secretName is allowed.
+	CertificateSecretNamePattern string
+
+	// MaxSubjectAltNames is the maximum number of subject alternative names
+	// a Certificate may request. If 0, the limit of Let's Encrypt is enforced
+	// for Certificates that reference an ACME issuer when
+	// EnableIssuerValidation is set.
+	MaxSubjectAltNames int
+
+	// MinRSAKeySize is the minimum size of the RSA private key of every
+	// Certificate. If 0, any valid size is allowed.
+	MinRSAKeySize int
+
+	// MinECDSACurve is the minimum curve size of the ECDSA private key of
+	// every Certificate. If 0, any valid curve is allowed.
+	MinECDSACurve int
+
 	// Optional path to the kubeconfig used to connect to the apiserver when
-	// using the 'dynamic serving' certificate sources.
+	// using the 'dynamic serving' certificate sources, namespace default
+	// issuers, Certificate templates or issuer validation.
 	// If not specified, in cluster config will be used.
 	Kubeconfig string
 
@@ -66,6 +100,13 @@ func (o *WebhookOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&o.DynamicServingCASecretNamespace, "dynamic-serving-ca-secret-namespace", "", "namespace of the secret used to store the CA that signs serving certificates")
 	fs.StringVar(&o.DynamicServingCASecretName, "dynamic-serving-ca-secret-name", "", "name of the secret used to store the CA that signs serving certificates certificates")
 	fs.StringSliceVar(&o.DynamicServingDNSNames, "dynamic-serving-dns-names", []string{""}, "DNS names that should be present on certificates generated by the dynamic serving CA")
+	fs.BoolVar(&o.EnableNamespaceDefaultIssuer, "enable-namespace-default-issuer", false, "default the issuerRef of Certificates that do not specify one to the issuer named in the cert-manager.io/default-issuer annotation of their namespace. Requires permission to get namespaces")
+	fs.BoolVar(&o.EnableCertificateTemplates, "enable-certificate-templates", false, "set the fields of Certificates that are not specified from the template ConfigMap referenced by their spec.templateRef. Requires permission to get configmaps")
+	fs.BoolVar(&o.EnableIssuerValidation, "enable-issuer-validation", false, "validate Certificates against the requirements of the Issuer or ClusterIssuer they reference, such as the cert-manager.io/require-common-name annotation. Requires permission to list and watch issuers and clusterissuers")
+	fs.StringVar(&o.CertificateSecretNamePattern, "certificate-secret-name-pattern", "", "regular expression that the spec.secretName of every Certificate must fully match, e.g. '[a-z0-9-]+-tls'. If not specified, any secretName is allowed")
+	fs.IntVar(&o.MaxSubjectAltNames, "max-subject-alt-names", 0, "maximum total number of dnsNames, ipAddresses, uris and emailAddresses a Certificate may request. If 0, at most 100 are allowed for Certificates that reference an ACME issuer if --enable-issuer-validation is set")
+	fs.IntVar(&o.MinRSAKeySize, "min-rsa-key-size", 0, "minimum size of the RSA private key of every Certificate, between 2048 & 8192. Certificates that do not specify a size are checked against the default of 2048. If 0, any valid size is allowed")
+	fs.IntVar(&o.MinECDSACurve, "min-ecdsa-curve", 0, "minimum curve size of the ECDSA private key of every Certificate, one of 256, 384 or 521. Certificates that do not specify a size are checked against the default of 256. If 0, any valid curve is allowed")
 	fs.StringVar(&o.Kubeconfig, "kubeconfig", "", "optional path to the kubeconfig used to connect to the apiserver. If not specified, in-cluster-config will be used")
 
 	tlsCipherPossibleValues := cliflag.TLSCipherPossibleValues()
@@ -19,12 +19,18 @@ package app
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clientcmd"
 
 	"github.com/jetstack/cert-manager/cmd/webhook/app/options"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	clientset "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	informers "github.com/jetstack/cert-manager/pkg/client/informers/externalversions"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
 	"github.com/jetstack/cert-manager/pkg/util"
 	"github.com/jetstack/cert-manager/pkg/webhook"
@@ -34,11 +40,10 @@ import (
 	"github.com/jetstack/cert-manager/pkg/webhook/server/tls"
 )
 
-var validationHook handlers.ValidatingAdmissionHook = handlers.NewRegistryBackedValidator(logf.Log, webhook.Scheme, webhook.ValidationRegistry)
 var mutationHook handlers.MutatingAdmissionHook = handlers.NewSchemeBackedDefaulter(logf.Log, webhook.Scheme)
 var conversionHook handlers.ConversionHook = handlers.NewSchemeBackedConverter(logf.Log, webhook.Scheme)
 
-func NewServerWithOptions(log logr.Logger, opts options.WebhookOptions) (*server.Server, error) {
+func NewServerWithOptions(log logr.Logger, opts options.WebhookOptions, stopCh <-chan struct{}) (*server.Server, error) {
 	var source tls.CertificateSource
 	switch {
 	case options.FileTLSSourceEnabled(opts):
@@ -69,6 +74,77 @@ func NewServerWithOptions(log logr.Logger, opts options.WebhookOptions) (*server
 		log.V(logf.WarnLevel).Info("serving insecurely as tls certificate data not provided")
 	}
 
+	policy := webhook.CertificatePolicy{
+		SecretNamePattern:  opts.CertificateSecretNamePattern,
+		MinRSAKeySize:      opts.MinRSAKeySize,
+		MinECDSACurve:      opts.MinECDSACurve,
+		MaxSubjectAltNames: opts.MaxSubjectAltNames,
+	}
+	if opts.EnableIssuerValidation {
+		restcfg, err := clientcmd.BuildConfigFromFlags("", opts.Kubeconfig)
+		if err != nil {
+			return nil, err
+		}
+		cmclient, err := clientset.NewForConfig(restcfg)
+		if err != nil {
+			return nil, err
+		}
+
+		factory := informers.NewSharedInformerFactory(cmclient, time.Minute*5)
+		issuers := factory.Certmanager().V1().Issuers()
+		clusterIssuers := factory.Certmanager().V1().ClusterIssuers()
+		policy.Issuers = issuers.Lister()
+		policy.ClusterIssuers = clusterIssuers.Lister()
+
+		// start the informers and wait for the caches to sync, so that
+		// Certificates are not admitted without checking an issuer that
+		// exists but has not been observed yet
+		log.V(logf.InfoLevel).Info("validating certificates against the requirements of their issuer")
+		factory.Start(stopCh)
+		if !cache.WaitForCacheSync(stopCh, issuers.Informer().HasSynced, clusterIssuers.Informer().HasSynced) {
+			return nil, fmt.Errorf("failed waiting for issuer caches to sync")
+		}
+	}
+
+	registry, err := webhook.NewValidationRegistry(policy)
+	if err != nil {
+		return nil, err
+	}
+	if opts.CertificateSecretNamePattern != "" {
+		log.V(logf.InfoLevel).Info("enforcing Certificate secretName pattern", "pattern", opts.CertificateSecretNamePattern)
+	}
+	if opts.MinRSAKeySize != 0 || opts.MinECDSACurve != 0 {
+		log.V(logf.InfoLevel).Info("enforcing Certificate minimum private key sizes", "rsa", opts.MinRSAKeySize, "ecdsa", opts.MinECDSACurve)
+	}
+	if opts.MaxSubjectAltNames != 0 {
+		log.V(logf.InfoLevel).Info("enforcing Certificate maximum number of subject alternative names", "max", opts.MaxSubjectAltNames)
+	}
+
+	mutation := mutationHook
+	if opts.EnableNamespaceDefaultIssuer || opts.EnableCertificateTemplates {
+		restcfg, err := clientcmd.BuildConfigFromFlags("", opts.Kubeconfig)
+		if err != nil {
+			return nil, err
+		}
+		client, err := kubernetes.NewForConfig(restcfg)
+		if err != nil {
+			return nil, err
+		}
+
+		// templates are applied before the namespace default issuer so that an
+		// issuerRef set in a template takes precedence over the namespace's
+		var defaulters []handlers.AdmissionDefaulter
+		if opts.EnableCertificateTemplates {
+			log.V(logf.InfoLevel).Info("setting certificate fields from templates referenced in spec.templateRef", "key", cmapi.CertificateTemplateConfigMapKey)
+			defaulters = append(defaulters, webhook.NewCertificateTemplate(client))
+		}
+		if opts.EnableNamespaceDefaultIssuer {
+			log.V(logf.InfoLevel).Info("defaulting certificate issuerRef from namespace annotations", "annotation", cmapi.DefaultIssuerNameAnnotationKey)
+			defaulters = append(defaulters, webhook.NewNamespaceDefaultIssuer(client))
+		}
+		mutation = handlers.NewSchemeBackedDefaulter(log, webhook.Scheme, defaulters...)
+	}
+
 	return &server.Server{
 		ListenAddr:        fmt.Sprintf(":%d", opts.ListenPort),
 		HealthzAddr:       fmt.Sprintf(":%d", opts.HealthzPort),
@@ -76,8 +152,8 @@ func NewServerWithOptions(log logr.Logger, opts options.WebhookOptions) (*server
 		CertificateSource: source,
 		CipherSuites:      opts.TLSCipherSuites,
 		MinTLSVersion:     opts.MinTLSVersion,
-		ValidationWebhook: validationHook,
-		MutationWebhook:   mutationHook,
+		ValidationWebhook: handlers.NewRegistryBackedValidator(log, webhook.Scheme, registry),
+		MutationWebhook:   mutation,
 		ConversionWebhook: conversionHook,
 		Log:               log,
 	}, nil
@@ -94,7 +170,7 @@ func NewServerCommand(stopCh <-chan struct{}) *cobra.Command {
 			ctx = logf.NewContext(ctx, nil, "webhook")
 			log := logf.FromContext(ctx)
 
-			srv, err := NewServerWithOptions(log, opts)
+			srv, err := NewServerWithOptions(log, opts, stopCh)
 			if err != nil {
 				return err
 			}
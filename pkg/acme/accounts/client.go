@@ -34,12 +34,14 @@ import (
 
 // NewClient will return a new ACME client.
 func NewClient(client *http.Client, config cmacme.ACMEIssuer, privateKey *rsa.PrivateKey) acmecl.Interface {
-	return &acmeapi.Client{
-		Key:          privateKey,
-		HTTPClient:   client,
-		DirectoryURL: config.Server,
-		UserAgent:    util.CertManagerUserAgent,
-		RetryBackoff: acmeutil.RetryBackoff,
+	return &acmecl.Client{
+		Client: &acmeapi.Client{
+			Key:          privateKey,
+			HTTPClient:   client,
+			DirectoryURL: config.Server,
+			UserAgent:    util.CertManagerUserAgent,
+			RetryBackoff: acmeutil.RetryBackoff,
+		},
 	}
 }
 
@@ -0,0 +1,162 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accounts
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	acmeapi "golang.org/x/crypto/acme"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+// jws is the flattened JSON serialization of a JWS, as sent by the ACME
+// client and as used for the externalAccountBinding field.
+type jws struct {
+	Protected string `json:"protected"`
+	Payload   string `json:"payload"`
+	Signature string `json:"signature"`
+}
+
+// newEABTestServer returns an ACME server that accepts new account requests
+// and passes the externalAccountBinding JWS of each to the given function.
+func newEABTestServer(t *testing.T, fn func(eab jws)) *httptest.Server {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Replay-Nonce", "nonce")
+		switch r.URL.Path {
+		case "/directory":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"newNonce":%q,"newAccount":%q,"newOrder":%q}`,
+				srv.URL+"/new-nonce", srv.URL+"/new-account", srv.URL+"/new-order")
+		case "/new-nonce":
+			w.WriteHeader(http.StatusOK)
+		case "/new-account":
+			var req jws
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("failed to decode new account request: %v", err)
+			}
+			var payload struct {
+				ExternalAccountBinding *jws `json:"externalAccountBinding"`
+			}
+			if err := decodeSegment(req.Payload, &payload); err != nil {
+				t.Errorf("failed to decode new account payload: %v", err)
+			}
+			if payload.ExternalAccountBinding == nil {
+				t.Errorf("expected new account request to contain an externalAccountBinding")
+			} else {
+				fn(*payload.ExternalAccountBinding)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Location", srv.URL+"/account/1")
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"status":"valid"}`)
+		default:
+			t.Errorf("unexpected request to %q", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return srv
+}
+
+func decodeSegment(s string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+func TestNewClientExternalAccountBindingKeyAlgorithm(t *testing.T) {
+	macKey := []byte("a-very-secret-mac-key")
+
+	tests := map[string]struct {
+		keyAlgorithm cmacme.HMACKeyAlgorithm
+		hash         func() hash.Hash
+	}{
+		"HS256": {keyAlgorithm: cmacme.HS256, hash: sha256.New},
+		"HS384": {keyAlgorithm: cmacme.HS384, hash: sha512.New384},
+		"HS512": {keyAlgorithm: cmacme.HS512, hash: sha512.New},
+	}
+
+	pk, err := pki.GenerateRSAPrivateKey(2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			called := false
+			srv := newEABTestServer(t, func(eab jws) {
+				called = true
+
+				var protected struct {
+					Alg string `json:"alg"`
+					KID string `json:"kid"`
+				}
+				if err := decodeSegment(eab.Protected, &protected); err != nil {
+					t.Errorf("failed to decode externalAccountBinding protected header: %v", err)
+					return
+				}
+				if protected.Alg != string(test.keyAlgorithm) {
+					t.Errorf("expected JWS alg %q but got %q", test.keyAlgorithm, protected.Alg)
+				}
+				if protected.KID != "test-kid" {
+					t.Errorf("expected JWS kid %q but got %q", "test-kid", protected.KID)
+				}
+
+				mac := hmac.New(test.hash, macKey)
+				mac.Write([]byte(eab.Protected + "." + eab.Payload))
+				sig, err := base64.RawURLEncoding.DecodeString(eab.Signature)
+				if err != nil {
+					t.Errorf("failed to decode externalAccountBinding signature: %v", err)
+					return
+				}
+				if !hmac.Equal(sig, mac.Sum(nil)) {
+					t.Errorf("externalAccountBinding signature was not computed using %s", test.keyAlgorithm)
+				}
+			})
+			defer srv.Close()
+
+			cl := NewClient(srv.Client(), cmacme.ACMEIssuer{Server: srv.URL + "/directory"}, pk)
+			_, err := cl.Register(context.TODO(), &acmeapi.Account{
+				ExternalAccountBinding: &acmeapi.ExternalAccountBinding{
+					KID:          "test-kid",
+					Key:          macKey,
+					KeyAlgorithm: string(test.keyAlgorithm),
+				},
+			}, acmeapi.AcceptTOS)
+			if err != nil {
+				t.Fatalf("unexpected error registering account: %v", err)
+			}
+			if !called {
+				t.Errorf("expected new account request to be made")
+			}
+		})
+	}
+}
@@ -0,0 +1,476 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/acme"
+)
+
+const (
+	// badNonceError is the problem type returned by an ACME server when the
+	// nonce of a request has already been used or has expired.
+	badNonceError = "urn:ietf:params:acme:error:badNonce"
+
+	// alreadyReplacedError is the problem type returned by an ACME server
+	// when the certificate named by a new Order's replaces field has already
+	// been replaced by another Order.
+	alreadyReplacedError = "urn:ietf:params:acme:error:alreadyReplaced"
+
+	// rateLimitedError is the problem type returned by an ACME server when a
+	// request exceeds one of its rate limits.
+	rateLimitedError = "urn:ietf:params:acme:error:rateLimited"
+
+	// maxBadNonceRetries is the number of times a request is retried with a
+	// fresh nonce after the ACME server rejects its nonce.
+	maxBadNonceRetries = 3
+)
+
+// Client is an ACME client that adds support for requesting Orders that
+// replace a previously issued certificate, as described by the ACME Renewal
+// Information (ARI) extension in RFC 9773. All other requests are handled by
+// the embedded acme.Client.
+// The acme.Client does not support ARI and does not expose its request
+// signing, so replacement Orders are signed by this Client. The directory,
+// account URL and latest nonce are cached so that only the new Order request
+// itself is sent for each replacement Order.
+type Client struct {
+	*acme.Client
+
+	lock       sync.Mutex
+	dir        *directory
+	accountURI string
+	nonce      string
+}
+
+var _ Interface = &Client{}
+
+// directory contains the fields of the ACME server's directory that are used
+// to create replacement Orders and that are not exposed by acme.Directory.
+type directory struct {
+	NewNonce    string `json:"newNonce"`
+	NewOrder    string `json:"newOrder"`
+	RenewalInfo string `json:"renewalInfo"`
+}
+
+type orderRequest struct {
+	Identifiers []identifier `json:"identifiers"`
+	NotAfter    string       `json:"notAfter,omitempty"`
+	Replaces    string       `json:"replaces"`
+}
+
+type identifier struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type orderResponse struct {
+	Status         string       `json:"status"`
+	Expires        time.Time    `json:"expires"`
+	Identifiers    []identifier `json:"identifiers"`
+	NotBefore      time.Time    `json:"notBefore"`
+	NotAfter       time.Time    `json:"notAfter"`
+	Authorizations []string     `json:"authorizations"`
+	Finalize       string       `json:"finalize"`
+	Certificate    string       `json:"certificate"`
+	Error          *problem     `json:"error"`
+}
+
+type problem struct {
+	Type   string `json:"type"`
+	Detail string `json:"detail"`
+}
+
+// AuthorizeReplacementOrder creates a new Order in the same way as
+// AuthorizeOrder, additionally identifying the certificate it replaces using
+// the given ARI certificate identifier so that the ACME server is able to
+// apply any renewal exemptions.
+// If the ACME server does not support ARI, or if the certificate has already
+// been replaced by another Order, an Order without the replaces field is
+// created instead.
+// If notAfter is not the zero time, it is requested as the notAfter date of
+// the issued certificate.
+func (c *Client) AuthorizeReplacementOrder(ctx context.Context, id []acme.AuthzID, replaces string, notAfter time.Time) (*acme.Order, error) {
+	var opts []acme.OrderOption
+	if !notAfter.IsZero() {
+		opts = append(opts, acme.WithOrderNotAfter(notAfter))
+	}
+
+	dir, err := c.directory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// ACME servers that do not implement ARI may reject Orders that contain
+	// the replaces field
+	if dir.RenewalInfo == "" || replaces == "" {
+		return c.Client.AuthorizeOrder(ctx, id, opts...)
+	}
+
+	// the account URL is required as the key ID of the request
+	accountURI, err := c.accountURL(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req := orderRequest{Replaces: replaces}
+	for _, v := range id {
+		req.Identifiers = append(req.Identifiers, identifier{Type: v.Type, Value: v.Value})
+	}
+	if !notAfter.IsZero() {
+		req.NotAfter = notAfter.UTC().Format(time.RFC3339)
+	}
+
+	res, err := c.post(ctx, dir, accountURI, dir.NewOrder, req, http.StatusCreated)
+	if isReplacementRejected(err) {
+		return c.Client.AuthorizeOrder(ctx, id, opts...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var v orderResponse
+	if err := json.NewDecoder(res.Body).Decode(&v); err != nil {
+		return nil, fmt.Errorf("acme: invalid response: %v", err)
+	}
+	order := &acme.Order{
+		URI:         res.Header.Get("Location"),
+		Status:      v.Status,
+		Expires:     v.Expires,
+		NotBefore:   v.NotBefore,
+		NotAfter:    v.NotAfter,
+		AuthzURLs:   v.Authorizations,
+		FinalizeURL: v.Finalize,
+		CertURL:     v.Certificate,
+	}
+	for _, id := range v.Identifiers {
+		order.Identifiers = append(order.Identifiers, acme.AuthzID{Type: id.Type, Value: id.Value})
+	}
+	if v.Error != nil {
+		order.Error = &acme.Error{ProblemType: v.Error.Type, Detail: v.Error.Detail}
+	}
+	return order, nil
+}
+
+// isReplacementRejected returns true if the given error from a request for a
+// replacement Order may have been caused by the replaces field, in which case
+// an Order without it should be requested instead. This is the case for any
+// client error, e.g. because the certificate has already been replaced or the
+// server does not accept the certificate identifier, other than a rate limit,
+// which applies regardless of the replaces field.
+func isReplacementRejected(err error) bool {
+	var acmeErr *acme.Error
+	if !errors.As(err, &acmeErr) {
+		return false
+	}
+	if acmeErr.ProblemType == alreadyReplacedError {
+		return true
+	}
+	return acmeErr.StatusCode >= 400 && acmeErr.StatusCode < 500 &&
+		acmeErr.StatusCode != http.StatusTooManyRequests && acmeErr.ProblemType != rateLimitedError
+}
+
+// directory returns the directory of the ACME server, which is only fetched
+// the first time it is needed.
+func (c *Client) directory(ctx context.Context) (*directory, error) {
+	c.lock.Lock()
+	dir := c.dir
+	c.lock.Unlock()
+	if dir != nil {
+		return dir, nil
+	}
+
+	url := c.Client.DirectoryURL
+	if url == "" {
+		url = acme.LetsEncryptURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, responseError(res)
+	}
+
+	dir = &directory{}
+	if err := json.NewDecoder(res.Body).Decode(dir); err != nil {
+		return nil, fmt.Errorf("acme: invalid response: %v", err)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.dir = dir
+	return dir, nil
+}
+
+// accountURL returns the URL of the ACME account of the Client's key, which
+// is only looked up the first time it is needed.
+func (c *Client) accountURL(ctx context.Context) (string, error) {
+	c.lock.Lock()
+	accountURI := c.accountURI
+	c.lock.Unlock()
+	if accountURI != "" {
+		return accountURI, nil
+	}
+
+	account, err := c.Client.GetReg(ctx, "")
+	if err != nil {
+		return "", err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.accountURI = account.URI
+	return account.URI, nil
+}
+
+// post sends the given body to the ACME server signed using the account key,
+// retrying with a new nonce if the nonce used is rejected.
+// The response is returned only if it has the expected status code, and must
+// be closed by the caller.
+func (c *Client) post(ctx context.Context, dir *directory, kid, url string, body interface{}, expectedStatus int) (*http.Response, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := c.popNonce()
+	for attempt := 0; ; attempt++ {
+		if nonce == "" {
+			if nonce, err = c.fetchNonce(ctx, dir.NewNonce); err != nil {
+				return nil, err
+			}
+		}
+		jws, err := signJWS(c.Client.Key, kid, nonce, url, payload)
+		if err != nil {
+			return nil, err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(jws))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/jose+json")
+		res, err := c.do(req)
+		if err != nil {
+			return nil, err
+		}
+		if res.StatusCode == expectedStatus {
+			c.pushNonce(res.Header.Get("Replay-Nonce"))
+			return res, nil
+		}
+
+		err = responseError(res)
+		res.Body.Close()
+		if e, ok := err.(*acme.Error); ok && e.ProblemType == badNonceError && attempt < maxBadNonceRetries {
+			nonce = res.Header.Get("Replay-Nonce")
+			continue
+		}
+		c.pushNonce(res.Header.Get("Replay-Nonce"))
+		return nil, err
+	}
+}
+
+// popNonce returns the nonce of the latest response from the ACME server, if
+// it has not been used yet.
+func (c *Client) popNonce() string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	nonce := c.nonce
+	c.nonce = ""
+	return nonce
+}
+
+// pushNonce stores the given nonce to be used by the next request.
+func (c *Client) pushNonce(nonce string) {
+	if nonce == "" {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.nonce = nonce
+}
+
+func (c *Client) fetchNonce(ctx context.Context, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return "", err
+	}
+	res, err := c.do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	nonce := res.Header.Get("Replay-Nonce")
+	if nonce == "" {
+		return "", errors.New("acme: nonce not found")
+	}
+	return nonce, nil
+}
+
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	if c.Client.UserAgent != "" {
+		req.Header.Set("User-Agent", c.Client.UserAgent)
+	}
+	httpClient := c.Client.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return httpClient.Do(req)
+}
+
+// responseError builds an acme.Error from the problem document in the body of
+// the given response.
+func responseError(res *http.Response) error {
+	body, _ := ioutil.ReadAll(res.Body)
+	var p problem
+	if err := json.Unmarshal(body, &p); err != nil {
+		p.Detail = string(body)
+	}
+	return &acme.Error{
+		StatusCode:  res.StatusCode,
+		ProblemType: p.Type,
+		Detail:      p.Detail,
+		Header:      res.Header,
+	}
+}
+
+// signJWS returns the flattened JSON serialization of a JWS containing the
+// given payload, signed using the account key identified by kid as described
+// in RFC 8555 section 6.2.
+func signJWS(key crypto.Signer, kid, nonce, url string, payload []byte) ([]byte, error) {
+	alg, hash, err := jwsAlgorithm(key)
+	if err != nil {
+		return nil, err
+	}
+	protected, err := json.Marshal(map[string]string{
+		"alg":   alg,
+		"kid":   kid,
+		"nonce": nonce,
+		"url":   url,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	enc := base64.RawURLEncoding
+	signingInput := enc.EncodeToString(protected) + "." + enc.EncodeToString(payload)
+	h := hash.New()
+	h.Write([]byte(signingInput))
+	sig, err := key.Sign(rand.Reader, h.Sum(nil), hash)
+	if err != nil {
+		return nil, err
+	}
+	if pub, ok := key.Public().(*ecdsa.PublicKey); ok {
+		// JWS uses the concatenation of R and S rather than the ASN.1 DER
+		// encoding returned by crypto.Signer
+		if sig, err = ecdsaJWSSignature(sig, pub.Curve.Params().BitSize); err != nil {
+			return nil, err
+		}
+	}
+
+	return json.Marshal(struct {
+		Protected string `json:"protected"`
+		Payload   string `json:"payload"`
+		Signature string `json:"signature"`
+	}{
+		Protected: enc.EncodeToString(protected),
+		Payload:   enc.EncodeToString(payload),
+		Signature: enc.EncodeToString(sig),
+	})
+}
+
+func jwsAlgorithm(key crypto.Signer) (string, crypto.Hash, error) {
+	switch pub := key.Public().(type) {
+	case *rsa.PublicKey:
+		return "RS256", crypto.SHA256, nil
+	case *ecdsa.PublicKey:
+		switch pub.Curve.Params().BitSize {
+		case 256:
+			return "ES256", crypto.SHA256, nil
+		case 384:
+			return "ES384", crypto.SHA384, nil
+		case 521:
+			return "ES512", crypto.SHA512, nil
+		}
+	}
+	return "", 0, fmt.Errorf("acme: unsupported account key type %T", key.Public())
+}
+
+func ecdsaJWSSignature(der []byte, bitSize int) ([]byte, error) {
+	var sig struct {
+		R, S *big.Int
+	}
+	if _, err := asn1.Unmarshal(der, &sig); err != nil {
+		return nil, err
+	}
+	size := (bitSize + 7) / 8
+	out := make([]byte, 2*size)
+	r, s := sig.R.Bytes(), sig.S.Bytes()
+	copy(out[size-len(r):size], r)
+	copy(out[2*size-len(s):], s)
+	return out, nil
+}
+
+// ARICertID returns the ARI certificate identifier of the given certificate,
+// used to refer to it when requesting a replacement Order. It is formed from
+// the key identifier of the certificate's Authority Key Identifier extension
+// and its serial number, as described in RFC 9773 section 4.1.
+func ARICertID(cert *x509.Certificate) (string, error) {
+	if len(cert.AuthorityKeyId) == 0 {
+		return "", errors.New("certificate does not contain an authority key identifier")
+	}
+	if cert.SerialNumber == nil || cert.SerialNumber.Sign() <= 0 {
+		return "", errors.New("certificate does not contain a positive serial number")
+	}
+
+	// the DER encoding of a positive INTEGER is prefixed with a zero byte if
+	// its most significant bit is set
+	serial := cert.SerialNumber.Bytes()
+	if serial[0]&0x80 != 0 {
+		serial = append([]byte{0}, serial...)
+	}
+
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString(cert.AuthorityKeyId) + "." + enc.EncodeToString(serial), nil
+}
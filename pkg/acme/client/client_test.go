@@ -0,0 +1,360 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/acme"
+)
+
+// jws is the flattened JSON serialization of a JWS, as sent by the ACME
+// client.
+type jws struct {
+	Protected string `json:"protected"`
+	Payload   string `json:"payload"`
+	Signature string `json:"signature"`
+}
+
+func decodeSegment(s string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+type newOrderPayload struct {
+	Identifiers []struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	} `json:"identifiers"`
+	NotAfter string  `json:"notAfter"`
+	Replaces *string `json:"replaces"`
+}
+
+// replacementTestServer is an ACME server that records the payloads of the
+// new order requests it receives.
+type replacementTestServer struct {
+	*httptest.Server
+
+	t   *testing.T
+	key *rsa.PublicKey
+
+	// renewalInfo controls whether the server advertises support for ARI
+	renewalInfo bool
+	// newOrderProblems are returned, in order, in response to the first new
+	// order requests before an order is created
+	newOrderProblems []string
+
+	lock   sync.Mutex
+	orders []newOrderPayload
+	// requests is the number of requests received for each path
+	requests map[string]int
+}
+
+func newReplacementTestServer(t *testing.T, key *rsa.PublicKey) *replacementTestServer {
+	s := &replacementTestServer{t: t, key: key, requests: make(map[string]int)}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+func (s *replacementTestServer) handle(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Replay-Nonce", fmt.Sprintf("nonce-%d", time.Now().UnixNano()))
+	s.lock.Lock()
+	s.requests[r.URL.Path]++
+	s.lock.Unlock()
+	switch r.URL.Path {
+	case "/directory":
+		renewalInfo := ""
+		if s.renewalInfo {
+			renewalInfo = s.URL + "/renewal-info"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"newNonce":%q,"newAccount":%q,"newOrder":%q,"renewalInfo":%q}`,
+			s.URL+"/new-nonce", s.URL+"/new-account", s.URL+"/new-order", renewalInfo)
+	case "/new-nonce":
+		w.WriteHeader(http.StatusOK)
+	case "/new-account":
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", s.URL+"/account/1")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"status":"valid"}`)
+	case "/new-order":
+		var req jws
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			s.t.Errorf("failed to decode new order request: %v", err)
+		}
+		var payload newOrderPayload
+		if err := decodeSegment(req.Payload, &payload); err != nil {
+			s.t.Errorf("failed to decode new order payload: %v", err)
+		}
+		if payload.Replaces != nil {
+			s.verify(req)
+		}
+
+		s.lock.Lock()
+		s.orders = append(s.orders, payload)
+		var problemType string
+		if len(s.newOrderProblems) > 0 {
+			problemType, s.newOrderProblems = s.newOrderProblems[0], s.newOrderProblems[1:]
+		}
+		s.lock.Unlock()
+
+		if problemType != "" {
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{"type":%q,"detail":"rejected"}`, problemType)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", s.URL+"/order/1")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"status":"pending","identifiers":[{"type":"dns","value":"example.com"}],"authorizations":[%q],"finalize":%q}`,
+			s.URL+"/authz/1", s.URL+"/order/1/finalize")
+	default:
+		s.t.Errorf("unexpected request to %q", r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// verify checks that a new order request sent by AuthorizeReplacementOrder
+// is signed using the account key and identifies the account using its URL.
+func (s *replacementTestServer) verify(req jws) {
+	var protected struct {
+		Alg   string `json:"alg"`
+		KID   string `json:"kid"`
+		Nonce string `json:"nonce"`
+		URL   string `json:"url"`
+	}
+	if err := decodeSegment(req.Protected, &protected); err != nil {
+		s.t.Errorf("failed to decode new order protected header: %v", err)
+		return
+	}
+	if protected.Alg != "RS256" {
+		s.t.Errorf("expected JWS alg %q but got %q", "RS256", protected.Alg)
+	}
+	if protected.KID != s.URL+"/account/1" {
+		s.t.Errorf("expected JWS kid %q but got %q", s.URL+"/account/1", protected.KID)
+	}
+	if protected.Nonce == "" {
+		s.t.Errorf("expected JWS nonce to be set")
+	}
+	if protected.URL != s.URL+"/new-order" {
+		s.t.Errorf("expected JWS url %q but got %q", s.URL+"/new-order", protected.URL)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(req.Signature)
+	if err != nil {
+		s.t.Errorf("failed to decode new order signature: %v", err)
+		return
+	}
+	digest := sha256.Sum256([]byte(req.Protected + "." + req.Payload))
+	if err := rsa.VerifyPKCS1v15(s.key, crypto.SHA256, digest[:], sig); err != nil {
+		s.t.Errorf("new order request was not signed by the account key: %v", err)
+	}
+}
+
+func TestAuthorizeReplacementOrder(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	notAfter := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		renewalInfo      bool
+		newOrderProblems []string
+		expectErr        bool
+
+		// expectedReplaces is the replaces field expected on each new order
+		// request, where an empty string denotes that the field is not set
+		expectedReplaces []string
+	}{
+		"sets replaces on the new order if the server supports ARI": {
+			renewalInfo:      true,
+			expectedReplaces: []string{"aYhba4dGQEHhs3uEe6CuLN4ByNQ.AIdlQyE"},
+		},
+		"does not set replaces if the server does not support ARI": {
+			expectedReplaces: []string{""},
+		},
+		"creates a new order without replaces if the certificate has already been replaced": {
+			renewalInfo:      true,
+			newOrderProblems: []string{alreadyReplacedError},
+			expectedReplaces: []string{"aYhba4dGQEHhs3uEe6CuLN4ByNQ.AIdlQyE", ""},
+		},
+		"creates a new order without replaces if the server rejects the replaces field": {
+			renewalInfo:      true,
+			newOrderProblems: []string{"urn:ietf:params:acme:error:malformed"},
+			expectedReplaces: []string{"aYhba4dGQEHhs3uEe6CuLN4ByNQ.AIdlQyE", ""},
+		},
+		"does not retry the new order without replaces if it is rate limited": {
+			renewalInfo:      true,
+			newOrderProblems: []string{rateLimitedError},
+			expectErr:        true,
+			expectedReplaces: []string{"aYhba4dGQEHhs3uEe6CuLN4ByNQ.AIdlQyE"},
+		},
+		"retries the new order with a fresh nonce if the nonce is rejected": {
+			renewalInfo:      true,
+			newOrderProblems: []string{badNonceError},
+			expectedReplaces: []string{"aYhba4dGQEHhs3uEe6CuLN4ByNQ.AIdlQyE", "aYhba4dGQEHhs3uEe6CuLN4ByNQ.AIdlQyE"},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			srv := newReplacementTestServer(t, &key.PublicKey)
+			defer srv.Close()
+			srv.renewalInfo = test.renewalInfo
+			srv.newOrderProblems = test.newOrderProblems
+
+			cl := &Client{Client: &acme.Client{
+				Key:          key,
+				HTTPClient:   srv.Client(),
+				DirectoryURL: srv.URL + "/directory",
+			}}
+			order, err := cl.AuthorizeReplacementOrder(context.TODO(), acme.DomainIDs("example.com"), "aYhba4dGQEHhs3uEe6CuLN4ByNQ.AIdlQyE", notAfter)
+			if err != nil && !test.expectErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err == nil && test.expectErr {
+				t.Fatalf("expected an error but got none")
+			}
+			if !test.expectErr {
+				if order.URI != srv.URL+"/order/1" {
+					t.Errorf("expected order URI %q but got %q", srv.URL+"/order/1", order.URI)
+				}
+				if order.FinalizeURL != srv.URL+"/order/1/finalize" {
+					t.Errorf("expected order finalize URL %q but got %q", srv.URL+"/order/1/finalize", order.FinalizeURL)
+				}
+				if len(order.AuthzURLs) != 1 || order.AuthzURLs[0] != srv.URL+"/authz/1" {
+					t.Errorf("expected order authorizations %v but got %v", []string{srv.URL + "/authz/1"}, order.AuthzURLs)
+				}
+			}
+
+			if len(srv.orders) != len(test.expectedReplaces) {
+				t.Fatalf("expected %d new order requests but got %d", len(test.expectedReplaces), len(srv.orders))
+			}
+			for i, payload := range srv.orders {
+				var replaces string
+				if payload.Replaces != nil {
+					replaces = *payload.Replaces
+				}
+				if replaces != test.expectedReplaces[i] {
+					t.Errorf("new order request %d: expected replaces %q but got %q", i, test.expectedReplaces[i], replaces)
+				}
+				if len(payload.Identifiers) != 1 || payload.Identifiers[0].Type != "dns" || payload.Identifiers[0].Value != "example.com" {
+					t.Errorf("new order request %d: unexpected identifiers %v", i, payload.Identifiers)
+				}
+				if payload.NotAfter != "2021-01-01T00:00:00Z" {
+					t.Errorf("new order request %d: expected notAfter %q but got %q", i, "2021-01-01T00:00:00Z", payload.NotAfter)
+				}
+			}
+		})
+	}
+}
+
+func TestARICertID(t *testing.T) {
+	tests := map[string]struct {
+		cert        *x509.Certificate
+		expected    string
+		expectedErr bool
+	}{
+		// example from RFC 9773 section 4.1
+		"serial number with the most significant bit set": {
+			cert: &x509.Certificate{
+				AuthorityKeyId: []byte{0x69, 0x88, 0x5b, 0x6b, 0x87, 0x46, 0x40, 0x41, 0xe1, 0xb3, 0x7b, 0x84, 0x7b, 0xa0, 0xae, 0x2c, 0xde, 0x01, 0xc8, 0xd4},
+				SerialNumber:   big.NewInt(0x87654321),
+			},
+			expected: "aYhba4dGQEHhs3uEe6CuLN4ByNQ.AIdlQyE",
+		},
+		"serial number without the most significant bit set": {
+			cert: &x509.Certificate{
+				AuthorityKeyId: []byte{0x69, 0x88, 0x5b, 0x6b},
+				SerialNumber:   big.NewInt(0x1234),
+			},
+			expected: "aYhbaw.EjQ",
+		},
+		"certificate without an authority key identifier": {
+			cert:        &x509.Certificate{SerialNumber: big.NewInt(0x1234)},
+			expectedErr: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			certID, err := ARICertID(test.cert)
+			if err != nil != test.expectedErr {
+				t.Fatalf("expected error %t but got: %v", test.expectedErr, err)
+			}
+			if certID != test.expected {
+				t.Errorf("expected certificate ID %q but got %q", test.expected, certID)
+			}
+		})
+	}
+}
+
+func TestAuthorizeReplacementOrderCachesDirectoryAndAccount(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := newReplacementTestServer(t, &key.PublicKey)
+	defer srv.Close()
+	srv.renewalInfo = true
+
+	cl := &Client{Client: &acme.Client{
+		Key:          key,
+		HTTPClient:   srv.Client(),
+		DirectoryURL: srv.URL + "/directory",
+	}}
+	authorize := func() map[string]int {
+		if _, err := cl.AuthorizeReplacementOrder(context.TODO(), acme.DomainIDs("example.com"), "aYhba4dGQEHhs3uEe6CuLN4ByNQ.AIdlQyE", time.Time{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		srv.lock.Lock()
+		defer srv.lock.Unlock()
+		requests := make(map[string]int)
+		for path, n := range srv.requests {
+			requests[path] = n
+		}
+		return requests
+	}
+
+	first := authorize()
+	second := authorize()
+	for _, path := range []string{"/directory", "/new-account", "/new-nonce"} {
+		if first[path] != second[path] {
+			t.Errorf("expected no further requests to %q for the second order, got %d", path, second[path]-first[path])
+		}
+	}
+	if second["/new-order"] != 2 {
+		t.Errorf("expected 2 new order requests but got %d", second["/new-order"])
+	}
+}
@@ -18,7 +18,9 @@ package client
 
 import (
 	"context"
+	"crypto"
 	"fmt"
+	"time"
 
 	"golang.org/x/crypto/acme"
 )
@@ -28,22 +30,24 @@ import (
 
 // FakeACME is a convenience structure to create a stub ACME implementation
 type FakeACME struct {
-	FakeAuthorizeOrder          func(ctx context.Context, id []acme.AuthzID, opt ...acme.OrderOption) (*acme.Order, error)
-	FakeGetOrder                func(ctx context.Context, url string) (*acme.Order, error)
-	FakeFetchCert               func(ctx context.Context, url string, bundle bool) ([][]byte, error)
-	FakeFetchCertAlternatives   func(ctx context.Context, url string, bundle bool) ([][][]byte, error)
-	FakeWaitOrder               func(ctx context.Context, url string) (*acme.Order, error)
-	FakeCreateOrderCert         func(ctx context.Context, finalizeURL string, csr []byte, bundle bool) (der [][]byte, certURL string, err error)
-	FakeAccept                  func(ctx context.Context, chal *acme.Challenge) (*acme.Challenge, error)
-	FakeGetChallenge            func(ctx context.Context, url string) (*acme.Challenge, error)
-	FakeGetAuthorization        func(ctx context.Context, url string) (*acme.Authorization, error)
-	FakeWaitAuthorization       func(ctx context.Context, url string) (*acme.Authorization, error)
-	FakeRegister                func(ctx context.Context, a *acme.Account, prompt func(tosURL string) bool) (*acme.Account, error)
-	FakeGetReg                  func(ctx context.Context, url string) (*acme.Account, error)
-	FakeHTTP01ChallengeResponse func(token string) (string, error)
-	FakeDNS01ChallengeRecord    func(token string) (string, error)
-	FakeDiscover                func(ctx context.Context) (acme.Directory, error)
-	FakeUpdateReg               func(ctx context.Context, a *acme.Account) (*acme.Account, error)
+	FakeAuthorizeOrder            func(ctx context.Context, id []acme.AuthzID, opt ...acme.OrderOption) (*acme.Order, error)
+	FakeAuthorizeReplacementOrder func(ctx context.Context, id []acme.AuthzID, replaces string, notAfter time.Time) (*acme.Order, error)
+	FakeGetOrder                  func(ctx context.Context, url string) (*acme.Order, error)
+	FakeFetchCert                 func(ctx context.Context, url string, bundle bool) ([][]byte, error)
+	FakeFetchCertAlternatives     func(ctx context.Context, url string, bundle bool) ([][][]byte, error)
+	FakeWaitOrder                 func(ctx context.Context, url string) (*acme.Order, error)
+	FakeCreateOrderCert           func(ctx context.Context, finalizeURL string, csr []byte, bundle bool) (der [][]byte, certURL string, err error)
+	FakeAccept                    func(ctx context.Context, chal *acme.Challenge) (*acme.Challenge, error)
+	FakeGetChallenge              func(ctx context.Context, url string) (*acme.Challenge, error)
+	FakeGetAuthorization          func(ctx context.Context, url string) (*acme.Authorization, error)
+	FakeWaitAuthorization         func(ctx context.Context, url string) (*acme.Authorization, error)
+	FakeRegister                  func(ctx context.Context, a *acme.Account, prompt func(tosURL string) bool) (*acme.Account, error)
+	FakeGetReg                    func(ctx context.Context, url string) (*acme.Account, error)
+	FakeHTTP01ChallengeResponse   func(token string) (string, error)
+	FakeDNS01ChallengeRecord      func(token string) (string, error)
+	FakeDiscover                  func(ctx context.Context) (acme.Directory, error)
+	FakeUpdateReg                 func(ctx context.Context, a *acme.Account) (*acme.Account, error)
+	FakeRevokeCert                func(ctx context.Context, key crypto.Signer, cert []byte, reason acme.CRLReasonCode) error
 }
 
 var _ Interface = &FakeACME{}
@@ -55,6 +59,13 @@ func (f *FakeACME) AuthorizeOrder(ctx context.Context, id []acme.AuthzID, opt ..
 	return nil, fmt.Errorf("AuthorizeOrder not implemented")
 }
 
+func (f *FakeACME) AuthorizeReplacementOrder(ctx context.Context, id []acme.AuthzID, replaces string, notAfter time.Time) (*acme.Order, error) {
+	if f.FakeAuthorizeReplacementOrder != nil {
+		return f.FakeAuthorizeReplacementOrder(ctx, id, replaces, notAfter)
+	}
+	return nil, fmt.Errorf("AuthorizeReplacementOrder not implemented")
+}
+
 func (f *FakeACME) GetOrder(ctx context.Context, url string) (*acme.Order, error) {
 	if f.FakeGetOrder != nil {
 		return f.FakeGetOrder(ctx, url)
@@ -162,3 +173,10 @@ func (f *FakeACME) UpdateReg(ctx context.Context, a *acme.Account) (*acme.Accoun
 	}
 	return nil, fmt.Errorf("UpdateReg not implemented")
 }
+
+func (f *FakeACME) RevokeCert(ctx context.Context, key crypto.Signer, cert []byte, reason acme.CRLReasonCode) error {
+	if f.FakeRevokeCert != nil {
+		return f.FakeRevokeCert(ctx, key, cert, reason)
+	}
+	return fmt.Errorf("RevokeCert not implemented")
+}
@@ -26,7 +26,8 @@ import (
 )
 
 // This file implements a custom instrumented HTTP client round tripper that
-// exposes prometheus metrics for each endpoint called.
+// exposes prometheus metrics for each endpoint called, and records any
+// Retry-After header returned by the ACME server.
 //
 // We implement this as part of the HTTP client to ensure we don't miss any
 // calls made to the ACME server caused by retries in the underlying ACME
@@ -73,6 +74,7 @@ func (it *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
 	resp, err := it.wrappedRT.RoundTrip(req)
 	if resp != nil {
 		statusCode = resp.StatusCode
+		RecordRetryAfter(req.Context(), resp.Header.Get("Retry-After"), time.Now())
 	}
 
 	labels := []string{
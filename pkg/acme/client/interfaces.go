@@ -18,6 +18,8 @@ package client
 
 import (
 	"context"
+	"crypto"
+	"time"
 
 	acmeutil "github.com/jetstack/cert-manager/pkg/acme/util"
 
@@ -26,6 +28,7 @@ import (
 
 type Interface interface {
 	AuthorizeOrder(ctx context.Context, id []acme.AuthzID, opt ...acme.OrderOption) (*acme.Order, error)
+	AuthorizeReplacementOrder(ctx context.Context, id []acme.AuthzID, replaces string, notAfter time.Time) (*acme.Order, error)
 	GetOrder(ctx context.Context, url string) (*acme.Order, error)
 	FetchCert(ctx context.Context, url string, bundle bool) ([][]byte, error)
 	FetchCertAlternatives(ctx context.Context, url string, bundle bool) ([][][]byte, error)
@@ -41,8 +44,11 @@ type Interface interface {
 	DNS01ChallengeRecord(token string) (string, error)
 	Discover(ctx context.Context) (acme.Directory, error)
 	UpdateReg(ctx context.Context, a *acme.Account) (*acme.Account, error)
+	RevokeCert(ctx context.Context, key crypto.Signer, cert []byte, reason acme.CRLReasonCode) error
 }
 
-var _ Interface = &acme.Client{
-	RetryBackoff: acmeutil.RetryBackoff,
+var _ Interface = &Client{
+	Client: &acme.Client{
+		RetryBackoff: acmeutil.RetryBackoff,
+	},
 }
@@ -18,6 +18,7 @@ package middleware
 
 import (
 	"context"
+	"crypto"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -55,6 +56,15 @@ func (l *Logger) AuthorizeOrder(ctx context.Context, id []acme.AuthzID, opt ...a
 	return l.baseCl.AuthorizeOrder(ctx, id, opt...)
 }
 
+func (l *Logger) AuthorizeReplacementOrder(ctx context.Context, id []acme.AuthzID, replaces string, notAfter time.Time) (*acme.Order, error) {
+	l.log.V(logf.TraceLevel).Info("Calling AuthorizeReplacementOrder")
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	return l.baseCl.AuthorizeReplacementOrder(ctx, id, replaces, notAfter)
+}
+
 func (l *Logger) GetOrder(ctx context.Context, url string) (*acme.Order, error) {
 	l.log.V(logf.TraceLevel).Info("Calling GetOrder")
 
@@ -181,3 +191,12 @@ func (l *Logger) UpdateReg(ctx context.Context, a *acme.Account) (*acme.Account,
 
 	return l.baseCl.UpdateReg(ctx, a)
 }
+
+func (l *Logger) RevokeCert(ctx context.Context, key crypto.Signer, cert []byte, reason acme.CRLReasonCode) error {
+	l.log.V(logf.TraceLevel).Info("Calling RevokeCert")
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	return l.baseCl.RevokeCert(ctx, key, cert, reason)
+}
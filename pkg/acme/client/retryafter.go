@@ -0,0 +1,111 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// This file implements recording of the Retry-After header returned by the
+// ACME server, so that callers polling orders and authorizations can honor
+// the delay requested by the server.
+//
+// The golang.org/x/crypto/acme client does not expose response headers for
+// successful requests, so the delay is recorded by the HTTP client's
+// RoundTripper onto the context of the request.
+
+type retryAfterKey struct{}
+
+type retryAfterRecorder struct {
+	lock  sync.Mutex
+	delay time.Duration
+	set   bool
+}
+
+// WithRetryAfter returns a copy of ctx that records the delay requested by
+// the Retry-After header of responses to ACME requests made with it. The
+// recorded delay can be retrieved using RetryAfter.
+func WithRetryAfter(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retryAfterKey{}, &retryAfterRecorder{})
+}
+
+// RetryAfter returns the delay requested by the most recent ACME server
+// response with a Retry-After header received using ctx, and true if one has
+// been recorded. It returns false if ctx was not created by WithRetryAfter.
+func RetryAfter(ctx context.Context) (time.Duration, bool) {
+	r, ok := ctx.Value(retryAfterKey{}).(*retryAfterRecorder)
+	if !ok {
+		return 0, false
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.delay, r.set
+}
+
+// RecordRetryAfter parses the given Retry-After header value and records the
+// delay onto ctx if it was created by WithRetryAfter. Invalid or empty values
+// are ignored.
+func RecordRetryAfter(ctx context.Context, value string, now time.Time) {
+	r, ok := ctx.Value(retryAfterKey{}).(*retryAfterRecorder)
+	if !ok {
+		return
+	}
+
+	delay, ok := ParseRetryAfter(value, now)
+	if !ok {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.delay = delay
+	r.set = true
+}
+
+// ParseRetryAfter parses the value of a Retry-After header, which is either
+// a number of seconds or an HTTP-date, and returns the delay it represents
+// relative to now. An HTTP-date in the past results in a zero delay.
+// It returns false if the value is empty or cannot be parsed.
+func ParseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	delay := date.Sub(now)
+	if delay < 0 {
+		delay = 0
+	}
+	return delay, true
+}
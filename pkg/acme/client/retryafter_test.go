@@ -0,0 +1,127 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	logtesting "github.com/jetstack/cert-manager/pkg/logs/testing"
+	"github.com/jetstack/cert-manager/pkg/metrics"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2020, time.November, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		value     string
+		expDelay  time.Duration
+		expParsed bool
+	}{
+		"empty value": {
+			value: "",
+		},
+		"delay in seconds": {
+			value:     "120",
+			expDelay:  2 * time.Minute,
+			expParsed: true,
+		},
+		"zero seconds": {
+			value:     "0",
+			expDelay:  0,
+			expParsed: true,
+		},
+		"negative seconds": {
+			value: "-5",
+		},
+		"HTTP-date in the future": {
+			value:     now.Add(90 * time.Second).Format(http.TimeFormat),
+			expDelay:  90 * time.Second,
+			expParsed: true,
+		},
+		"HTTP-date in the past": {
+			value:     now.Add(-time.Hour).Format(http.TimeFormat),
+			expDelay:  0,
+			expParsed: true,
+		},
+		"invalid value": {
+			value: "soon",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			delay, parsed := ParseRetryAfter(test.value, now)
+			if parsed != test.expParsed {
+				t.Errorf("expected parsed=%t but got %t", test.expParsed, parsed)
+			}
+			if delay != test.expDelay {
+				t.Errorf("expected delay %s but got %s", test.expDelay, delay)
+			}
+		})
+	}
+}
+
+func TestInstrumentedClientRecordsRetryAfter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/retry" {
+			w.Header().Set("Retry-After", "30")
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cl := NewInstrumentedClient(metrics.New(logtesting.TestLogger{T: t}), &http.Client{})
+
+	do := func(ctx context.Context, path string) {
+		req, err := http.NewRequest(http.MethodGet, server.URL+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := cl.Do(req.WithContext(ctx))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	ctx := WithRetryAfter(context.Background())
+	do(ctx, "/")
+	if delay, ok := RetryAfter(ctx); ok {
+		t.Errorf("expected no Retry-After to be recorded, but got %s", delay)
+	}
+
+	do(ctx, "/retry")
+	delay, ok := RetryAfter(ctx)
+	if !ok {
+		t.Fatalf("expected Retry-After to be recorded")
+	}
+	if delay != 30*time.Second {
+		t.Errorf("expected recorded delay of %s but got %s", 30*time.Second, delay)
+	}
+
+	// requests made with a context not created by WithRetryAfter are not
+	// recorded
+	plainCtx := context.Background()
+	do(plainCtx, "/retry")
+	if _, ok := RetryAfter(plainCtx); ok {
+		t.Errorf("expected no Retry-After to be recorded on a plain context")
+	}
+}
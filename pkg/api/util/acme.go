@@ -0,0 +1,62 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"strings"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
+)
+
+const (
+	// ACMEChallengeTypeHTTP01 is the name of the ACME HTTP-01 challenge type
+	// as it appears in ACME authorizations
+	ACMEChallengeTypeHTTP01 string = "http-01"
+	// ACMEChallengeTypeDNS01 is the name of the ACME DNS-01 challenge type as
+	// it appears in ACME authorizations
+	ACMEChallengeTypeDNS01 string = "dns-01"
+)
+
+// ACMEChallengeTypePreference returns the ACME challenge types listed in the
+// ACMEChallengeTypePreferenceAnnotationKey annotation, most preferred first.
+// It returns nil if the annotation is not set, and an error if the annotation
+// contains an unknown or duplicated challenge type.
+func ACMEChallengeTypePreference(annotations map[string]string) ([]string, error) {
+	value, ok := annotations[cmacme.ACMEChallengeTypePreferenceAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+
+	var preference []string
+	seen := make(map[string]bool)
+	for _, t := range strings.Split(value, ",") {
+		t = strings.ToLower(strings.TrimSpace(t))
+		switch t {
+		case ACMEChallengeTypeHTTP01, ACMEChallengeTypeDNS01:
+		default:
+			return nil, fmt.Errorf("unsupported challenge type %q, must be one of %q or %q", t, ACMEChallengeTypeHTTP01, ACMEChallengeTypeDNS01)
+		}
+		if seen[t] {
+			return nil, fmt.Errorf("challenge type %q is listed more than once", t)
+		}
+		seen[t] = true
+		preference = append(preference, t)
+	}
+
+	return preference, nil
+}
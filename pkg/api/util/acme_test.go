@@ -0,0 +1,72 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
+)
+
+func TestACMEChallengeTypePreference(t *testing.T) {
+	tests := map[string]struct {
+		annotations map[string]string
+		want        []string
+		wantErr     bool
+	}{
+		"no annotations": {},
+		"annotation not set": {
+			annotations: map[string]string{"foo": "bar"},
+		},
+		"single challenge type": {
+			annotations: map[string]string{cmacme.ACMEChallengeTypePreferenceAnnotationKey: "dns-01"},
+			want:        []string{"dns-01"},
+		},
+		"multiple challenge types are returned in order": {
+			annotations: map[string]string{cmacme.ACMEChallengeTypePreferenceAnnotationKey: "dns-01,http-01"},
+			want:        []string{"dns-01", "http-01"},
+		},
+		"whitespace and case are ignored": {
+			annotations: map[string]string{cmacme.ACMEChallengeTypePreferenceAnnotationKey: " HTTP-01 , dns-01"},
+			want:        []string{"http-01", "dns-01"},
+		},
+		"empty value": {
+			annotations: map[string]string{cmacme.ACMEChallengeTypePreferenceAnnotationKey: ""},
+			wantErr:     true,
+		},
+		"unknown challenge type": {
+			annotations: map[string]string{cmacme.ACMEChallengeTypePreferenceAnnotationKey: "tls-alpn-01"},
+			wantErr:     true,
+		},
+		"duplicate challenge type": {
+			annotations: map[string]string{cmacme.ACMEChallengeTypePreferenceAnnotationKey: "dns-01,DNS-01"},
+			wantErr:     true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ACMEChallengeTypePreference(test.annotations)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("unexpected error, wantErr=%t, got: %v", test.wantErr, err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("unexpected preference, want=%v, got=%v", test.want, got)
+			}
+		})
+	}
+}
@@ -18,6 +18,7 @@ package util
 
 import (
 	"fmt"
+	"strconv"
 
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
@@ -34,6 +35,8 @@ const (
 	IssuerSelfSigned string = "selfsigned"
 	// IssuerVenafi uses Venafi Trust Protection Platform and Venafi Cloud
 	IssuerVenafi string = "venafi"
+	// IssuerAWSPCA uses an AWS Certificate Manager Private Certificate Authority
+	IssuerAWSPCA string = "awspca"
 )
 
 // NameForIssuer determines the name of the Issuer implementation given an
@@ -50,10 +53,40 @@ func NameForIssuer(i cmapi.GenericIssuer) (string, error) {
 		return IssuerSelfSigned, nil
 	case i.GetSpec().Venafi != nil:
 		return IssuerVenafi, nil
+	case i.GetSpec().AWSPCA != nil:
+		return IssuerAWSPCA, nil
 	}
 	return "", fmt.Errorf("no issuer specified for Issuer '%s/%s'", i.GetObjectMeta().Namespace, i.GetObjectMeta().Name)
 }
 
+// CertificateRequestIssuerFailures returns the number of times signing the
+// given CertificateRequest with the issuer referenced by its issuerRef has
+// failed.
+func CertificateRequestIssuerFailures(cr *cmapi.CertificateRequest) int {
+	failures, err := strconv.Atoi(cr.Annotations[cmapi.CertificateRequestIssuerFailuresAnnotationKey])
+	if err != nil || failures < 0 {
+		return 0
+	}
+	return failures
+}
+
+// CertificateRequestUsesFallbackIssuer returns true if the given
+// CertificateRequest should be signed by the issuer referenced by its
+// fallbackIssuerRef rather than its issuerRef.
+func CertificateRequestUsesFallbackIssuer(cr *cmapi.CertificateRequest) bool {
+	return cr.Spec.FallbackIssuerRef != nil &&
+		cr.Annotations[cmapi.CertificateRequestUsingFallbackIssuerAnnotationKey] == "true"
+}
+
+// CertificateRequestIssuerRef returns a reference to the issuer that should
+// currently sign the given CertificateRequest.
+func CertificateRequestIssuerRef(cr *cmapi.CertificateRequest) cmmeta.ObjectReference {
+	if CertificateRequestUsesFallbackIssuer(cr) {
+		return *cr.Spec.FallbackIssuerRef
+	}
+	return cr.Spec.IssuerRef
+}
+
 // issuerKind returns the kind of issuer for a certificate
 func IssuerKind(ref cmmeta.ObjectReference) string {
 	if ref.Kind == "" {
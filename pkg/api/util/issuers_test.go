@@ -0,0 +1,77 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+)
+
+func TestCertificateRequestIssuerRef(t *testing.T) {
+	primary := cmmeta.ObjectReference{Name: "primary"}
+	fallback := &cmmeta.ObjectReference{Name: "fallback", Kind: "ClusterIssuer"}
+
+	tests := map[string]struct {
+		fallbackRef   *cmmeta.ObjectReference
+		usingFallback string
+		expRef        cmmeta.ObjectReference
+	}{
+		"no fallback issuer configured": {
+			usingFallback: "true",
+			expRef:        primary,
+		},
+		"fallback issuer not in use": {
+			fallbackRef: fallback,
+			expRef:      primary,
+		},
+		"fallback issuer in use": {
+			fallbackRef:   fallback,
+			usingFallback: "true",
+			expRef:        *fallback,
+		},
+		"invalid fallback issuer annotation": {
+			fallbackRef:   fallback,
+			usingFallback: "yes",
+			expRef:        primary,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cr := &cmapi.CertificateRequest{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{},
+				},
+				Spec: cmapi.CertificateRequestSpec{
+					IssuerRef:         primary,
+					FallbackIssuerRef: test.fallbackRef,
+				},
+			}
+			if test.usingFallback != "" {
+				cr.Annotations[cmapi.CertificateRequestUsingFallbackIssuerAnnotationKey] = test.usingFallback
+			}
+
+			if ref := CertificateRequestIssuerRef(cr); ref != test.expRef {
+				t.Errorf("expected issuer ref %+v but got %+v", test.expRef, ref)
+			}
+		})
+	}
+}
@@ -32,6 +32,13 @@ const (
 	// solver for each ingress class.
 	ACMECertificateHTTP01IngressClassOverride = "acme.cert-manager.io/http01-override-ingress-class"
 
+	// If this annotation is specified on a Certificate or Order resource, the
+	// ACME challenge types it lists will be preferred, in the order given,
+	// when more than one of the issuer's solvers can be used for an
+	// authorization. The value is a comma separated list containing any of
+	// 'http-01' and 'dns-01', for example 'dns-01,http-01'.
+	ACMEChallengeTypePreferenceAnnotationKey = "acme.cert-manager.io/challenge-type-preference"
+
 	// IngressEditInPlaceAnnotation is used to toggle the use of ingressClass instead
 	// of ingress on the created Certificate resource
 	IngressEditInPlaceAnnotationKey = "acme.cert-manager.io/http01-edit-in-place"
@@ -19,6 +19,7 @@ package v1alpha3
 import (
 	corev1 "k8s.io/api/core/v1"
 	apiext "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 )
@@ -101,6 +102,15 @@ type ACMEIssuer struct {
 	// Defaults to false.
 	// +optional
 	EnableDurationFeature bool `json:"enableDurationFeature,omitempty"`
+
+	// AllowedDomains is a list of glob patterns, such as '*.example.com', that
+	// restrict the DNS names which may be requested from this issuer.
+	// Orders containing DNS names that do not match any of the patterns will
+	// be marked as errored. A '*' matches any sequence of characters,
+	// including dots.
+	// If not set, all DNS names are allowed.
+	// +optional
+	AllowedDomains []string `json:"allowedDomains,omitempty"`
 }
 
 // ACMEExternalAccountBinding is a reference to a CA external account of the ACME
@@ -167,6 +177,15 @@ type CertificateDNSNameSelector struct {
 	// +optional
 	MatchLabels map[string]string `json:"matchLabels,omitempty"`
 
+	// A map of annotations that is used to refine the set of certificate's
+	// that this challenge solver will apply to. All annotations must be
+	// present on the certificate with the given values for the solver to be
+	// selected.
+	// If multiple solvers match, annotations that match are counted together
+	// with matchLabels when choosing the most specific solver.
+	// +optional
+	MatchAnnotations map[string]string `json:"matchAnnotations,omitempty"`
+
 	// List of DNSNames that this solver will be used to solve.
 	// If specified and a match is found, a dnsNames selector will take
 	// precedence over a dnsZones selector.
@@ -188,8 +207,34 @@ type CertificateDNSNameSelector struct {
 	// will be selected.
 	// +optional
 	DNSZones []string `json:"dnsZones,omitempty"`
+
+	// A label selector that is used to refine the set of certificate's that
+	// this challenge solver will apply to, based on the labels of the
+	// namespace the certificate is in.
+	// If multiple solvers match, namespace labels that match are counted
+	// together with matchLabels when choosing the most specific solver.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// IdentifierType restricts this solver to authorizations for identifiers
+	// of the given type, either `dns` for DNS names or `ip` for IP addresses.
+	// If not specified, the solver is used for identifiers of any type.
+	// If multiple solvers match, a matching identifierType is counted
+	// together with matchLabels when choosing the most specific solver.
+	// +optional
+	IdentifierType string `json:"identifierType,omitempty"`
 }
 
+const (
+	// ACMEIdentifierTypeDNS is the identifierType of authorizations for DNS
+	// names.
+	ACMEIdentifierTypeDNS = "dns"
+
+	// ACMEIdentifierTypeIP is the identifierType of authorizations for IP
+	// addresses.
+	ACMEIdentifierTypeIP = "ip"
+)
+
 // ACMEChallengeSolverHTTP01 contains configuration detailing how to solve
 // HTTP01 challenges within a Kubernetes cluster.
 // Typically this is accomplished through creating 'routes' of some description
@@ -209,6 +254,14 @@ type ACMEChallengeSolverHTTP01Ingress struct {
 	// +optional
 	ServiceType corev1.ServiceType `json:"serviceType,omitempty"`
 
+	// Optional IP family of the Kubernetes solver service, either `IPv4` or
+	// `IPv6`. This should be set to `IPv6` in IPv6-only clusters, so that the
+	// challenge solver is reachable on an IPv6 cluster IP. If not set, the
+	// cluster's default IP family is used.
+	// +optional
+	// +kubebuilder:validation:Enum=IPv4;IPv6
+	ServiceIPFamily corev1.IPFamily `json:"serviceIPFamily,omitempty"`
+
 	// The ingress class to use when creating Ingress resources to solve ACME
 	// challenges that use this challenge solver.
 	// Only one of 'class' or 'name' may be specified.
@@ -232,6 +285,14 @@ type ACMEChallengeSolverHTTP01Ingress struct {
 	// ingress used for HTTP01 challenges
 	// +optional
 	IngressTemplate *ACMEChallengeSolverHTTP01IngressTemplate `json:"ingressTemplate,omitempty"`
+
+	// ReadTimeoutSeconds is the maximum number of seconds to wait for a
+	// response to each self check request made against the challenge
+	// solver before the attempt is considered failed. If not set, requests
+	// are only bound by the overall HTTP01 self check timeout.
+	// Must be between 1 and 300.
+	// +optional
+	ReadTimeoutSeconds *int32 `json:"readTimeoutSeconds,omitempty"`
 }
 
 type ACMEChallengeSolverHTTP01IngressPodTemplate struct {
@@ -244,8 +305,8 @@ type ACMEChallengeSolverHTTP01IngressPodTemplate struct {
 
 	// PodSpec defines overrides for the HTTP01 challenge solver pod.
 	// Only the 'priorityClassName', 'nodeSelector', 'affinity',
-	// 'serviceAccountName' and 'tolerations' fields are supported currently.
-	// All other fields will be ignored.
+	// 'serviceAccountName', 'tolerations' and 'image' fields are supported
+	// currently. All other fields will be ignored.
 	// +optional
 	Spec ACMEChallengeSolverHTTP01IngressPodSpec `json:"spec"`
 }
@@ -282,6 +343,11 @@ type ACMEChallengeSolverHTTP01IngressPodSpec struct {
 	// If specified, the pod's service account
 	// +optional
 	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// If specified, the image used for the solver pod's container, overriding
+	// the HTTP01 solver image configured on the controller.
+	// +optional
+	Image string `json:"image,omitempty"`
 }
 
 type ACMEChallengeSolverHTTP01IngressTemplate struct {
@@ -312,6 +378,22 @@ type ACMEChallengeSolverDNS01 struct {
 	// +optional
 	CNAMEStrategy CNAMEStrategy `json:"cnameStrategy,omitempty"`
 
+	// SelfCheckNameservers is a list of nameservers, in host:port form, that
+	// will be queried exclusively when performing the DNS01 self check for
+	// this solver. This is useful in split-horizon DNS environments where the
+	// challenge record is only visible to particular nameservers.
+	// If not set, the nameservers configured for the controller are used.
+	// +optional
+	SelfCheckNameservers []string `json:"selfCheckNameservers,omitempty"`
+
+	// RecordTTL is the TTL, in seconds, of the TXT records created by this
+	// solver. Where the limits of the DNS provider are known, the TTL is
+	// validated to be within the range that the provider accepts.
+	// It is not supported by the acmeDNS, rfc2136 and webhook providers.
+	// If not set, the default TTL of the configured DNS provider is used.
+	// +optional
+	RecordTTL *int32 `json:"recordTTL,omitempty"`
+
 	// Use the Akamai DNS zone management API to manage DNS01 challenge records.
 	// +optional
 	Akamai *ACMEIssuerDNS01ProviderAkamai `json:"akamai,omitempty"`
@@ -336,6 +418,14 @@ type ACMEChallengeSolverDNS01 struct {
 	// +optional
 	DigitalOcean *ACMEIssuerDNS01ProviderDigitalOcean `json:"digitalocean,omitempty"`
 
+	// Use the Gandi LiveDNS API to manage DNS01 challenge records.
+	// +optional
+	Gandi *ACMEIssuerDNS01ProviderGandi `json:"gandi,omitempty"`
+
+	// Use the Porkbun DNS API to manage DNS01 challenge records.
+	// +optional
+	Porkbun *ACMEIssuerDNS01ProviderPorkbun `json:"porkbun,omitempty"`
+
 	// Use the 'ACME DNS' (https://github.com/joohoi/acme-dns) API to manage
 	// DNS01 challenge records.
 	// +optional
@@ -419,6 +509,26 @@ type ACMEIssuerDNS01ProviderDigitalOcean struct {
 	Token cmmeta.SecretKeySelector `json:"tokenSecretRef"`
 }
 
+// ACMEIssuerDNS01ProviderGandi is a structure containing the DNS
+// configuration for Gandi LiveDNS
+type ACMEIssuerDNS01ProviderGandi struct {
+	// A reference to a specific 'key' within a Secret resource containing a
+	// Gandi personal access token with permission to manage DNS records.
+	Token cmmeta.SecretKeySelector `json:"tokenSecretRef"`
+}
+
+// ACMEIssuerDNS01ProviderPorkbun is a structure containing the DNS
+// configuration for Porkbun
+type ACMEIssuerDNS01ProviderPorkbun struct {
+	// A reference to a specific 'key' within a Secret resource containing a
+	// Porkbun API key.
+	APIKey cmmeta.SecretKeySelector `json:"apiKeySecretRef"`
+
+	// A reference to a specific 'key' within a Secret resource containing the
+	// Porkbun secret API key that belongs to the API key.
+	SecretAPIKey cmmeta.SecretKeySelector `json:"secretApiKeySecretRef"`
+}
+
 // ACMEIssuerDNS01ProviderRoute53 is a structure containing the Route 53
 // configuration for AWS
 type ACMEIssuerDNS01ProviderRoute53 struct {
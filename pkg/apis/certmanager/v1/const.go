@@ -22,6 +22,10 @@ const (
 	// minimum permitted certificate duration by cert-manager
 	MinimumCertificateDuration = time.Hour
 
+	// maximum permitted certificate duration by cert-manager, regardless of
+	// issuer. Durations above this are almost always a mistake.
+	MaximumCertificateDuration = time.Hour * 24 * 365 * 100
+
 	// default certificate duration if Issuer.spec.duration is not set
 	DefaultCertificateDuration = time.Hour * 24 * 90
 
@@ -30,6 +34,10 @@ const (
 
 	// Default duration before certificate expiration if  Issuer.spec.renewBefore is not set
 	DefaultRenewBefore = time.Hour * 24 * 30
+
+	// minimum permitted issuance timeout of a certificate, as issuances that
+	// time out sooner are unlikely to ever complete
+	MinimumIssuanceTimeout = time.Minute
 )
 
 const (
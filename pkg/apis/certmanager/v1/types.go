@@ -30,6 +30,14 @@ const (
 	// Annotation key for certificate common name.
 	CommonNameAnnotationKey = "cert-manager.io/common-name"
 
+	// Annotation key for the NotBefore time of the certificate, in RFC3339
+	// format.
+	CertificateNotBeforeAnnotationKey = "cert-manager.io/certificate-not-before"
+
+	// Annotation key for the NotAfter time of the certificate, in RFC3339
+	// format.
+	CertificateNotAfterAnnotationKey = "cert-manager.io/certificate-not-after"
+
 	// Duration key for certificate duration.
 	DurationAnnotationKey = "cert-manager.io/duration"
 
@@ -48,6 +56,18 @@ const (
 	// Annotation key for the 'group' of the Issuer resource.
 	IssuerGroupAnnotationKey = "cert-manager.io/issuer-group"
 
+	// Annotation key on a Namespace for the 'name' of the Issuer resource used
+	// by Certificates in the Namespace that do not specify an issuerRef.
+	DefaultIssuerNameAnnotationKey = "cert-manager.io/default-issuer"
+
+	// Annotation key on a Namespace for the 'kind' of the default Issuer
+	// resource. Defaults to Issuer.
+	DefaultIssuerKindAnnotationKey = "cert-manager.io/default-issuer-kind"
+
+	// Annotation key on a Namespace for the 'group' of the default Issuer
+	// resource. Defaults to cert-manager.io.
+	DefaultIssuerGroupAnnotationKey = "cert-manager.io/default-issuer-group"
+
 	// Annotation key for the name of the certificate that a resource is related to.
 	CertificateNameKey = "cert-manager.io/certificate-name"
 
@@ -85,6 +105,36 @@ const (
 
 	// Annotation to declare the CertificateRequest "revision", belonging to a Certificate Resource
 	CertificateRequestRevisionAnnotationKey = "cert-manager.io/certificate-revision"
+
+	// Annotation that can be added to CertificateRequest resources to request
+	// that the CSR be signed as-is, using the key usages and subject it
+	// contains even if they differ from those set on the spec.
+	// It is only honoured by the CA and Vault issuers, and only if verbatim
+	// CSR signing has been enabled on the controller.
+	CertificateRequestSignCSRVerbatimAnnotationKey = "cert-manager.io/sign-csr-verbatim"
+
+	// Annotation used to record the number of times signing a
+	// CertificateRequest with the issuer referenced by its issuerRef has
+	// failed. It is only set on CertificateRequests that have a
+	// fallbackIssuerRef.
+	CertificateRequestIssuerFailuresAnnotationKey = "cert-manager.io/issuer-failures"
+
+	// Annotation set to "true" on CertificateRequests that are signed by the
+	// issuer referenced by their fallbackIssuerRef, because signing with the
+	// issuer referenced by their issuerRef has failed.
+	CertificateRequestUsingFallbackIssuerAnnotationKey = "cert-manager.io/using-fallback-issuer"
+
+	// Annotation used to record the RFC 3339 time since which the issuer
+	// referenced by the issuerRef of a CertificateRequest has been missing or
+	// not ready. It is only set on CertificateRequests that have a
+	// fallbackIssuerRef.
+	CertificateRequestIssuerUnavailableSinceAnnotationKey = "cert-manager.io/issuer-unavailable-since"
+
+	// Annotation that can be added to CertificateRequest resources to revoke
+	// the certificate issued for them, set to the RFC 3339 time at which the
+	// certificate was revoked. Revoked certificates are listed in the CRL
+	// published by CA issuers that have a CRL configured.
+	CertificateRequestRevokedAtAnnotationKey = "cert-manager.io/revoked-at"
 )
 
 const (
@@ -141,6 +191,85 @@ const (
 	// Venafi Pickup ID of a certificate signing request that has been submitted
 	// to the Venafi API for collection later.
 	VenafiPickupIDAnnotationKey = "venafi.cert-manager.io/pickup-id"
+
+	// AWSPCACertificateARNAnnotationKey is the annotation key used to record
+	// the ARN of a certificate that has been requested from an AWS Private
+	// Certificate Authority, so that it can be retrieved once issued.
+	AWSPCACertificateARNAnnotationKey = "awspca.cert-manager.io/certificate-arn"
+
+	// RequireCommonNameAnnotationKey can be set to "true" on an Issuer or
+	// ClusterIssuer whose CA requires a non-empty common name. Certificates
+	// referencing such an issuer will be rejected if they only request
+	// subject alternative names.
+	RequireCommonNameAnnotationKey = "cert-manager.io/require-common-name"
+
+	// AllowCAKeyRotationAnnotationKey can be set to "true" on a Certificate
+	// to allow it to set both `isCA: true` and the `Always` private key
+	// rotation policy. Rotating the private key of a CA invalidates the chain
+	// of every certificate it has issued, so this combination is rejected
+	// unless explicitly allowed.
+	AllowCAKeyRotationAnnotationKey = "cert-manager.io/allow-ca-key-rotation"
+
+	// DeletionProtectionAnnotationKey can be set to "true" on a Certificate
+	// to prevent it from being deleted. Deletion of the Certificate is held
+	// back by a finalizer until the annotation is removed again.
+	DeletionProtectionAnnotationKey = "cert-manager.io/deletion-protection"
+
+	// PreserveSecretOnDeletionAnnotationKey can be set to "true" on a
+	// Certificate to keep its Secret when the Certificate is deleted. Any
+	// owner reference to the Certificate is removed from the Secret before
+	// the Certificate is allowed to be deleted.
+	PreserveSecretOnDeletionAnnotationKey = "cert-manager.io/preserve-secret-on-deletion"
+
+	// RevokeOnDeletionAnnotationKey can be set to "true" on a Certificate
+	// issued by an ACME Issuer to revoke the certificate stored in its Secret
+	// with the ACME server when the Certificate is deleted. Deletion of the
+	// Certificate is held back by a finalizer until the certificate has been
+	// revoked.
+	RevokeOnDeletionAnnotationKey = "cert-manager.io/revoke-on-deletion"
+
+	// AllowInsecureACMEServerAnnotationKey can be set to "true" on an Issuer
+	// or ClusterIssuer to allow `spec.acme.server` to be a plain HTTP URL,
+	// for example when testing against a local ACME server.
+	AllowInsecureACMEServerAnnotationKey = "cert-manager.io/allow-insecure-acme-server"
+
+	// SecretOwnerReferenceAnnotationKey can be set to "true" or "false" on a
+	// Certificate to override the --enable-certificate-owner-ref flag for
+	// that Certificate. If "false", any owner reference to the Certificate is
+	// removed from its Secret the next time the Secret is written.
+	SecretOwnerReferenceAnnotationKey = "cert-manager.io/secret-owner-reference"
+
+	// MigrateSecretOnRenameAnnotationKey can be set to "true" on a
+	// Certificate to move the existing certificate and private key to the
+	// new Secret when `spec.secretName` is changed, and delete the old
+	// Secret, instead of issuing a new certificate into the new Secret.
+	MigrateSecretOnRenameAnnotationKey = "cert-manager.io/migrate-secret-on-rename"
+
+	// CommonNameFromDNSNameAnnotationKey can be set to "true" on a
+	// Certificate that does not set `spec.commonName` to request the first
+	// DNS name as the common name, for CAs that require one. The common name
+	// is left empty if the first DNS name is longer than 64 bytes.
+	CommonNameFromDNSNameAnnotationKey = "cert-manager.io/common-name-from-dns-name"
+
+	// CertificatePausedAnnotationKey can be set to "true" on a Certificate to
+	// stop cert-manager from reconciling it. A paused Certificate is not
+	// renewed or re-issued, and any issuance in progress is not completed,
+	// until the annotation is removed again.
+	CertificatePausedAnnotationKey = "cert-manager.io/paused"
+)
+
+const (
+	// CertificateDeletionProtectionFinalizer is added to Certificates that
+	// set the deletion protection, preserve Secret or revoke on deletion
+	// annotations, so that their deletion can be held back, or their Secret
+	// orphaned or certificate revoked first.
+	CertificateDeletionProtectionFinalizer = "cert-manager.io/deletion-protection"
+)
+
+const (
+	// CertificateTemplateConfigMapKey is the key in the ConfigMap referenced
+	// by the templateRef of a Certificate that holds the template.
+	CertificateTemplateConfigMapKey = "template"
 )
 
 // KeyUsage specifies valid usage contexts for keys.
@@ -51,7 +51,7 @@ type CertificateList struct {
 	Items []Certificate `json:"items"`
 }
 
-// +kubebuilder:validation:Enum=rsa;ecdsa
+// +kubebuilder:validation:Enum=rsa;ecdsa;ed25519
 type KeyAlgorithm string
 
 const (
@@ -60,6 +60,9 @@ const (
 
 	// Denotes the ECDSA private key type.
 	ECDSAKeyAlgorithm KeyAlgorithm = "ecdsa"
+
+	// Denotes the Ed25519 private key type.
+	Ed25519KeyAlgorithm KeyAlgorithm = "ed25519"
 )
 
 // +kubebuilder:validation:Enum=pkcs1;pkcs8
@@ -78,6 +81,24 @@ const (
 	PKCS8 KeyEncoding = "pkcs8"
 )
 
+// +kubebuilder:validation:Enum=SHA1;SHA256;SHA384;SHA512
+type PrivateKeySignatureHash string
+
+const (
+	// SHA1SignatureHash signs with SHA-1. It is only intended for legacy
+	// issuers that do not support stronger hash functions.
+	SHA1SignatureHash PrivateKeySignatureHash = "SHA1"
+
+	// SHA256SignatureHash signs with SHA-256.
+	SHA256SignatureHash PrivateKeySignatureHash = "SHA256"
+
+	// SHA384SignatureHash signs with SHA-384.
+	SHA384SignatureHash PrivateKeySignatureHash = "SHA384"
+
+	// SHA512SignatureHash signs with SHA-512.
+	SHA512SignatureHash PrivateKeySignatureHash = "SHA512"
+)
+
 // CertificateSpec defines the desired state of Certificate.
 type CertificateSpec struct {
 	// Full X509 name specification (https://golang.org/pkg/crypto/x509/pkix/#Name).
@@ -112,6 +133,14 @@ type CertificateSpec struct {
 	// +optional
 	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
 
+	// IssuanceTimeout is the maximum amount of time an issuance of this
+	// Certificate may remain in progress before it is marked as failed. Once
+	// timed out, the issuance is retried after the usual failure back-off.
+	// If not set, the controller's default issuance timeout is used, and
+	// issuances never time out if that is not set either.
+	// +optional
+	IssuanceTimeout *metav1.Duration `json:"issuanceTimeout,omitempty"`
+
 	// DNSNames is a list of DNS subjectAltNames to be set on the Certificate.
 	// +optional
 	DNSNames []string `json:"dnsNames,omitempty"`
@@ -125,9 +154,17 @@ type CertificateSpec struct {
 	URISANs []string `json:"uriSANs,omitempty"`
 
 	// EmailSANs is a list of email subjectAltNames to be set on the Certificate.
+	// They are encoded as rfc822Name subjectAltNames, so must only contain
+	// ASCII characters.
 	// +optional
 	EmailSANs []string `json:"emailSANs,omitempty"`
 
+	// RegisteredIDs is a list of registeredID subjectAltNames to be set on the
+	// Certificate. Each entry must be an object identifier in dotted-decimal
+	// notation, for example 1.2.3.4.
+	// +optional
+	RegisteredIDs []string `json:"registeredIDs,omitempty"`
+
 	// SecretName is the name of the secret resource that will be automatically
 	// created and managed by this Certificate resource.
 	// It will be populated with a private key and certificate, signed by the
@@ -139,6 +176,12 @@ type CertificateSpec struct {
 	// +optional
 	Keystores *CertificateKeystores `json:"keystores,omitempty"`
 
+	// AdditionalOutputFormats defines extra output formats of the private key
+	// and signed certificate chain to be written to this Certificate's target
+	// Secret.
+	// +optional
+	AdditionalOutputFormats []CertificateAdditionalOutputFormat `json:"additionalOutputFormats,omitempty"`
+
 	// IssuerRef is a reference to the issuer for this certificate.
 	// If the `kind` field is not set, or set to `Issuer`, an Issuer resource
 	// with the given name in the same namespace as the Certificate will be used.
@@ -147,11 +190,32 @@ type CertificateSpec struct {
 	// The `name` field in this stanza is required at all times.
 	IssuerRef cmmeta.ObjectReference `json:"issuerRef"`
 
+	// FallbackIssuerRef is a reference to an issuer that will be used to
+	// sign the certificate if signing with the issuer referenced by `issuerRef`
+	// repeatedly fails, e.g. because the CA is unavailable.
+	// It has the same format as `issuerRef`.
+	// +optional
+	FallbackIssuerRef *cmmeta.ObjectReference `json:"fallbackIssuerRef,omitempty"`
+
 	// IsCA will mark this Certificate as valid for certificate signing.
 	// This will automatically add the `cert sign` usage to the list of `usages`.
 	// +optional
 	IsCA bool `json:"isCA,omitempty"`
 
+	// MaxPathLen is the maximum number of non-self-issued intermediate CA
+	// certificates that may follow this certificate in a valid certification
+	// path, encoded in its BasicConstraints extension. It may only be set if
+	// `isCA` is true. A value of 0 is treated as unset unless `maxPathLenZero`
+	// is also true.
+	// +optional
+	MaxPathLen int `json:"maxPathLen,omitempty"`
+
+	// MaxPathLenZero indicates that a `maxPathLen` of 0 is set, meaning this
+	// CA certificate may only be used to sign end-entity certificates. It may
+	// only be set if `isCA` is true and `maxPathLen` is unset or 0.
+	// +optional
+	MaxPathLenZero bool `json:"maxPathLenZero,omitempty"`
+
 	// Usages is the set of x509 usages that are requested for the certificate.
 	// Defaults to `digital signature` and `key encipherment` if not specified.
 	// +optional
@@ -162,12 +226,14 @@ type CertificateSpec struct {
 	// and will default to `2048` if not specified.
 	// If `keyAlgorithm` is set to `ecdsa`, valid values are `256`, `384` or `521`,
 	// and will default to `256` if not specified.
+	// If `keyAlgorithm` is set to `ed25519`, keySize must not be specified.
 	// No other values are allowed.
 	// +optional
 	KeySize int `json:"keySize,omitempty"` // Validated by webhook. Be mindful of adding OpenAPI validation- see https://github.com/jetstack/cert-manager/issues/3644 .
 
 	// KeyAlgorithm is the private key algorithm of the corresponding private key
-	// for this certificate. If provided, allowed values are either `rsa` or `ecdsa`
+	// for this certificate. If provided, allowed values are either `rsa`,
+	// `ecdsa` or `ed25519`.
 	// If `keyAlgorithm` is specified and `keySize` is not provided,
 	// key size of 256 will be used for `ecdsa` key algorithm and
 	// key size of 2048 will be used for `rsa` key algorithm.
@@ -189,6 +255,14 @@ type CertificateSpec struct {
 	// in the CertificateRequest
 	// +optional
 	EncodeUsagesInRequest *bool `json:"encodeUsagesInRequest,omitempty"`
+
+	// TemplateRef is a reference to a ConfigMap in the same namespace as the
+	// Certificate holding common fields of a Certificate's spec, such as
+	// usages, duration and keystores, in YAML under the `template` key.
+	// When the Certificate is created or updated, any of these fields that
+	// are not set on the Certificate are set from the template.
+	// +optional
+	TemplateRef *cmmeta.LocalObjectReference `json:"templateRef,omitempty"`
 }
 
 // CertificatePrivateKey contains configuration options for private keys
@@ -206,6 +280,26 @@ type CertificatePrivateKey struct {
 	// Default is 'Never' for backward compatibility.
 	// +optional
 	RotationPolicy PrivateKeyRotationPolicy `json:"rotationPolicy,omitempty"`
+
+	// EncryptionPassphraseSecretRef is a reference to a key in a Secret
+	// resource containing a passphrase used to encrypt the private key
+	// written to the `tls.key` field of the `spec.secretName` Secret.
+	// If set, the private key is stored as a PEM encoded, encrypted PKCS#8
+	// key, and applications consuming the Secret must decrypt it before use.
+	// This is unusual and most applications are not able to read encrypted
+	// keys.
+	// +optional
+	EncryptionPassphraseSecretRef *cmmeta.SecretKeySelector `json:"encryptionPassphraseSecretRef,omitempty"`
+
+	// SignatureHash is the hash function used to sign the CertificateRequest
+	// with the private key. If provided, allowed values are `SHA1`, `SHA256`,
+	// `SHA384` or `SHA512`. It can only be set for `RSA` and `ECDSA` keys.
+	// If not specified, the hash function is chosen based on the key size:
+	// SHA-256 for RSA keys smaller than 3072 bits and ECDSA P-256 keys,
+	// SHA-384 for RSA keys smaller than 4096 bits and ECDSA P-384 keys,
+	// and SHA-512 otherwise.
+	// +optional
+	SignatureHash PrivateKeySignatureHash `json:"signatureHash,omitempty"`
 }
 
 // Denotes how private keys should be generated or sourced when a Certificate
@@ -249,6 +343,26 @@ type X509Subject struct {
 	SerialNumber string `json:"serialNumber,omitempty"`
 }
 
+// CertificateOutputFormatType specifies which additional output formats should
+// be written to the Certificate's target Secret.
+// +kubebuilder:validation:Enum=DER
+type CertificateOutputFormatType string
+
+const (
+	// CertificateOutputFormatDER writes the leaf certificate as raw DER
+	// encoded bytes to the `tls.der` key of the target Secret.
+	CertificateOutputFormatDER CertificateOutputFormatType = "DER"
+)
+
+// CertificateAdditionalOutputFormat defines an additional output format of a
+// Certificate resource. These contain supplementary data formats of the signed
+// certificate chain and paired private key.
+type CertificateAdditionalOutputFormat struct {
+	// Type is the name of the format type that should be written to the
+	// Certificate's target Secret.
+	Type CertificateOutputFormatType `json:"type"`
+}
+
 // CertificateKeystores configures additional keystore output formats to be
 // created in the Certificate's output Secret.
 type CertificateKeystores struct {
@@ -289,6 +403,17 @@ type PKCS12Keystore struct {
 	// PasswordSecretRef is a reference to a key in a Secret resource
 	// containing the password used to encrypt the PKCS12 keystore.
 	PasswordSecretRef cmmeta.SecretKeySelector `json:"passwordSecretRef"`
+
+	// IncludeCAChain controls whether the complete CA chain is stored in the
+	// PKCS12 files.
+	// If true, the deduplicated certificate chain (every certificate in
+	// `tls.crt` and `ca.crt`) is stored alongside the private key in
+	// `keystore.p12`, and every CA certificate in that chain is added as a
+	// trusted entry to `truststore.p12`, rather than only the first
+	// certificate in `ca.crt`.
+	// May only be set if `create` is true.
+	// +optional
+	IncludeCAChain bool `json:"includeCAChain,omitempty"`
 }
 
 // CertificateStatus defines the observed state of Certificate
@@ -402,4 +527,17 @@ const (
 	//
 	// It will be removed by the 'issuing' controller upon completing issuance.
 	CertificateConditionIssuing CertificateConditionType = "Issuing"
+
+	// CertificateConditionSecretConflict is added to Certificate resources
+	// when the target Secret appears to be managed by another controller, for
+	// example because it has a foreign controller owner reference or because
+	// another field manager has written the certificate data.
+	// While this condition is True, re-issuance is not triggered for the
+	// Certificate to avoid repeatedly overwriting the other controller's data.
+	CertificateConditionSecretConflict CertificateConditionType = "SecretConflict"
+
+	// CertificateConditionPaused is added to Certificate resources that have
+	// been paused using the `cert-manager.io/paused` annotation.
+	// While this condition is True, the Certificate is not reconciled.
+	CertificateConditionPaused CertificateConditionType = "Paused"
 )
@@ -117,6 +117,41 @@ type IssuerConfig struct {
 	// or Venafi Cloud policy zone.
 	// +optional
 	Venafi *VenafiIssuer `json:"venafi,omitempty"`
+
+	// AWSPCA configures this issuer to sign certificates using an AWS
+	// Certificate Manager Private Certificate Authority.
+	// +optional
+	AWSPCA *AWSPCAIssuer `json:"awsPCA,omitempty"`
+}
+
+// AWSPCAIssuer configures an issuer to sign certificates using an AWS
+// Certificate Manager Private Certificate Authority (ACM PCA).
+type AWSPCAIssuer struct {
+	// ARN is the Amazon Resource Name of the private certificate authority
+	// used to sign certificates, for example
+	// `arn:aws:acm-pca:us-east-1:123456789012:certificate-authority/12345678-1234-1234-1234-123456789012`.
+	ARN string `json:"arn"`
+
+	// Region is the AWS region of the private certificate authority.
+	// If set, it must match the region of the ARN.
+	// Defaults to the region of the ARN.
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// The AccessKeyID is used for authentication. If not set we fall-back to using env vars, shared credentials file or AWS Instance metadata
+	// see: https://docs.aws.amazon.com/sdk-for-go/v1/developer-guide/configuring-sdk.html#specifying-credentials
+	// +optional
+	AccessKeyID string `json:"accessKeyID,omitempty"`
+
+	// The SecretAccessKey is used for authentication. If not set we fall-back to using env vars, shared credentials file or AWS Instance metadata
+	// https://docs.aws.amazon.com/sdk-for-go/v1/developer-guide/configuring-sdk.html#specifying-credentials
+	// +optional
+	SecretAccessKey cmmeta.SecretKeySelector `json:"secretAccessKeySecretRef"`
+
+	// Role is a Role ARN which the issuer will assume using either the explicit credentials AccessKeyID/SecretAccessKey
+	// or the inferred credentials from environment variables, shared credentials file or AWS Instance metadata
+	// +optional
+	Role string `json:"role,omitempty"`
 }
 
 // Configures an issuer to sign certificates using a Venafi TPP
@@ -137,6 +172,12 @@ type VenafiIssuer struct {
 	// Only one of TPP or Cloud may be specified.
 	// +optional
 	Cloud *VenafiCloud `json:"cloud,omitempty"`
+
+	// CustomFields are Venafi custom fields that are sent with every
+	// certificate request made by this issuer, in addition to any set with
+	// the venafi.cert-manager.io/custom-fields annotation.
+	// +optional
+	CustomFields []VenafiCustomField `json:"customFields,omitempty"`
 }
 
 // VenafiTPP defines connection configuration details for a Venafi TPP instance
@@ -145,9 +186,13 @@ type VenafiTPP struct {
 	// for example: "https://tpp.example.com/vedsdk".
 	URL string `json:"url"`
 
-	// CredentialsRef is a reference to a Secret containing the username and
-	// password for the TPP server.
-	// The secret must contain two keys, 'username' and 'password'.
+	// CredentialsRef is a reference to a Secret containing the credentials
+	// for the TPP server.
+	// The secret must contain either the two keys 'username' and 'password',
+	// or the key 'access-token'. If the secret also contains a 'refresh-token'
+	// key, the access token is refreshed and written back to the secret when
+	// it expires. The OAuth client ID used for refreshing may be set with the
+	// 'client-id' key, and defaults to 'cert-manager.io'.
 	CredentialsRef cmmeta.LocalObjectReference `json:"credentialsRef"`
 
 	// CABundle is a PEM encoded TLS certificate to use to verify connections to
@@ -171,6 +216,15 @@ type VenafiCloud struct {
 	APITokenSecretRef cmmeta.SecretKeySelector `json:"apiTokenSecretRef"`
 }
 
+// VenafiCustomField is a Venafi custom field and its value.
+type VenafiCustomField struct {
+	// Name is the name of the custom field.
+	Name string `json:"name"`
+
+	// Value is the value of the custom field.
+	Value string `json:"value"`
+}
+
 // Configures an issuer to 'self sign' certificates using the
 // private key used to create the CertificateRequest object.
 type SelfSignedIssuer struct {
@@ -281,8 +335,99 @@ type CAIssuer struct {
 	// OCSP server URL could be "http://ocsp.int-x3.letsencrypt.org".
 	// +optional
 	OCSPServers []string `json:"ocspServers,omitempty"`
+
+	// IssuingCertificateURLs is a list of URLs at which the certificate of
+	// this Issuer can be retrieved. They are added to the Authority Information
+	// Access extension of issued certificates as caIssuers access methods,
+	// alongside any OCSP servers. If not set, certificates will be issued
+	// without caIssuers URLs set. For example, an issuing certificate URL could
+	// be "http://ca.example.com/ca.crt".
+	// +optional
+	IssuingCertificateURLs []string `json:"issuingCertificateURLs,omitempty"`
+
+	// FreshestCRLDistributionPoints is a list of URLs at which delta CRLs
+	// for this Issuer can be retrieved. They are added to the FreshestCRL
+	// extension of issued certificates, as defined in RFC 5280, 4.2.1.15.
+	// If not set, certificates will be issued without the FreshestCRL
+	// extension. For example, a freshest CRL distribution point could be
+	// "http://crl.example.com/delta.crl".
+	// +optional
+	FreshestCRLDistributionPoints []string `json:"freshestCRLDistributionPoints,omitempty"`
+
+	// PreferredChain is the chain to use if the signing CA can be chained to
+	// multiple roots. Alternative issuing certificates, such as those
+	// published at the signing CA's Authority Information Access URL, can be
+	// supplied in the `tls.crt` and `ca.crt` keys of the CA Secret.
+	// This value picks the first chain that is anchored at a root
+	// certificate with this value as its CN.
+	// If no chain matches, the default chain is used.
+	// +optional
+	// +kubebuilder:validation:MaxLength=64
+	PreferredChain string `json:"preferredChain,omitempty"`
+
+	// AuthorityKeyIdentifier is the hex encoded key identifier to set as the
+	// Authority Key Identifier of certificates issued by this Issuer, for
+	// example to match the Subject Key Identifier of a previous CA key.
+	// It must be exactly 20 bytes (40 hex characters) long.
+	// If not set, the Subject Key Identifier of the signing CA certificate is
+	// used.
+	// +optional
+	AuthorityKeyIdentifier string `json:"authorityKeyIdentifier,omitempty"`
+
+	// SubjectKeyIdentifierMethod is the method used to derive the Subject Key
+	// Identifier of certificates issued by this Issuer from their public key.
+	// If set to `RFC5280`, the key identifier is the SHA-1 hash of the subject
+	// public key, as described in RFC 5280, 4.2.1.2.
+	// If set to `RFC7093Method1`, the key identifier is the leftmost 160 bits
+	// of the SHA-256 hash of the subject public key, as described in RFC 7093,
+	// section 2, method 1.
+	// If not set, a Subject Key Identifier is only included in issued CA
+	// certificates.
+	// +optional
+	SubjectKeyIdentifierMethod SubjectKeyIdentifierMethod `json:"subjectKeyIdentifierMethod,omitempty"`
+
+	// CRL configures this Issuer to publish a certificate revocation list
+	// (CRL) of the certificates it has issued that have been revoked.
+	// Certificates are revoked by setting the
+	// `cert-manager.io/revoked-at` annotation on the CertificateRequest
+	// that they were issued for.
+	// +optional
+	CRL *CAIssuerCRL `json:"crl,omitempty"`
+}
+
+// CAIssuerCRL configures the certificate revocation list published by a CA
+// issuer.
+type CAIssuerCRL struct {
+	// SecretName is the name of the Secret that the PEM encoded CRL is
+	// written to, under the `ca.crl` key. The Secret is created in the same
+	// namespace as the CA Secret.
+	SecretName string `json:"secretName"`
+
+	// Duration is the period each generated CRL is valid for, i.e. the time
+	// between its thisUpdate and nextUpdate fields. A new CRL is generated
+	// once two thirds of this period has elapsed, or whenever the set of
+	// revoked certificates changes. Defaults to 24 hours.
+	// Minimum accepted duration is 1 hour.
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
 }
 
+// SubjectKeyIdentifierMethod is the method used to derive the Subject Key
+// Identifier of a certificate from its public key.
+// +kubebuilder:validation:Enum=RFC5280;RFC7093Method1
+type SubjectKeyIdentifierMethod string
+
+const (
+	// SubjectKeyIdentifierMethodRFC5280 derives the key identifier from the
+	// SHA-1 hash of the value of the subject public key BIT STRING.
+	SubjectKeyIdentifierMethodRFC5280 SubjectKeyIdentifierMethod = "RFC5280"
+
+	// SubjectKeyIdentifierMethodRFC7093Method1 derives the key identifier
+	// from the leftmost 160 bits of the SHA-256 hash of the value of the
+	// subject public key BIT STRING.
+	SubjectKeyIdentifierMethodRFC7093Method1 SubjectKeyIdentifierMethod = "RFC7093Method1"
+)
+
 // IssuerStatus contains status information about an Issuer
 type IssuerStatus struct {
 	// List of status conditions to indicate the status of a CertificateRequest.
@@ -22,6 +22,10 @@ const (
 	// minimum permitted certificate duration by cert-manager
 	MinimumCertificateDuration = time.Hour
 
+	// maximum permitted certificate duration by cert-manager, regardless of
+	// issuer. Durations above this are almost always a mistake.
+	MaximumCertificateDuration = time.Hour * 24 * 365 * 100
+
 	// default certificate duration if Issuer.spec.duration is not set
 	DefaultCertificateDuration = time.Hour * 24 * 90
 
@@ -50,6 +50,7 @@ type controller struct {
 
 	// all the listers used by this controller
 	challengeLister     cmacmelisters.ChallengeLister
+	orderLister         cmacmelisters.OrderLister
 	issuerLister        cmlisters.IssuerLister
 	clusterIssuerLister cmlisters.ClusterIssuerLister
 	secretLister        corelisters.SecretLister
@@ -90,6 +91,7 @@ func (c *controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitin
 
 	// obtain references to all the informers used by this controller
 	challengeInformer := ctx.SharedInformerFactory.Acme().V1().Challenges()
+	orderInformer := ctx.SharedInformerFactory.Acme().V1().Orders()
 	issuerInformer := ctx.SharedInformerFactory.Certmanager().V1().Issuers()
 	secretInformer := ctx.KubeSharedInformerFactory.Core().V1().Secrets()
 	// we register these informers here so the HTTP01 solver has a synced
@@ -101,6 +103,7 @@ func (c *controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitin
 	// the controller will only begin processing items once all of these informers have synced.
 	mustSync := []cache.InformerSynced{
 		challengeInformer.Informer().HasSynced,
+		orderInformer.Informer().HasSynced,
 		issuerInformer.Informer().HasSynced,
 		secretInformer.Informer().HasSynced,
 		podInformer.Informer().HasSynced,
@@ -110,6 +113,7 @@ func (c *controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitin
 
 	// set all the references to the listers for used by the Sync function
 	c.challengeLister = challengeInformer.Lister()
+	c.orderLister = orderInformer.Lister()
 	c.issuerLister = issuerInformer.Lister()
 	c.secretLister = secretInformer.Lister()
 
@@ -217,6 +221,7 @@ func init() {
 		return controllerpkg.NewBuilder(ctx, ControllerName).
 			For(c).
 			With(c.runScheduler, time.Second).
+			With(c.cleanupOrphanedChallenges, orphanedChallengeCheckPeriod).
 			Complete()
 	})
 }
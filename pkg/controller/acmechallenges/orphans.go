@@ -0,0 +1,93 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package acmechallenges
+
+import (
+	"context"
+	"time"
+
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+)
+
+// orphanedChallengeCheckPeriod is how often the controller checks for
+// Challenges whose owning Order no longer exists.
+const orphanedChallengeCheckPeriod = time.Minute * 5
+
+// cleanupOrphanedChallenges deletes any Challenge that is controlled by an
+// Order that no longer exists. This can happen if an Order is deleted without
+// its dependents being garbage collected, for example when the orphan
+// deletion propagation policy is used.
+// Deleting the Challenge causes its finalizer to be run by Sync, which cleans
+// up any resources created by the challenge solver.
+func (c *controller) cleanupOrphanedChallenges(ctx context.Context) {
+	log := logf.FromContext(ctx, "orphans")
+
+	challenges, err := c.challengeLister.List(labels.Everything())
+	if err != nil {
+		log.Error(err, "error listing challenges")
+		return
+	}
+
+	for _, ch := range challenges {
+		log := logf.WithResource(log, ch)
+		if ch.DeletionTimestamp != nil {
+			continue
+		}
+
+		orphaned, err := c.isOrphaned(ch)
+		if err != nil {
+			log.Error(err, "error determining whether challenge is orphaned")
+			continue
+		}
+		if !orphaned {
+			continue
+		}
+
+		log.V(logf.InfoLevel).Info("deleting challenge as the order that owns it no longer exists")
+		err = c.cmClient.AcmeV1().Challenges(ch.Namespace).Delete(context.TODO(), ch.Name, metav1.DeleteOptions{})
+		if err != nil && !k8sErrors.IsNotFound(err) {
+			log.Error(err, "error deleting orphaned challenge")
+		}
+	}
+}
+
+// isOrphaned returns true if the Challenge is controlled by an Order that no
+// longer exists. Challenges that are not controlled by an Order are never
+// considered orphaned.
+func (c *controller) isOrphaned(ch *cmacme.Challenge) (bool, error) {
+	ref := metav1.GetControllerOf(ch)
+	if ref == nil || ref.Kind != cmacme.OrderKind {
+		return false, nil
+	}
+
+	order, err := c.orderLister.Orders(ch.Namespace).Get(ref.Name)
+	if k8sErrors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	// an Order with the same name may have been created since the owning
+	// Order was deleted
+	return order.UID != ref.UID, nil
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package acmechallenges
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	coretesting "k8s.io/client-go/testing"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
+	v1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestCleanupOrphanedChallenges(t *testing.T) {
+	order := gen.Order("testorder", gen.SetOrderUID("order-uid"))
+	ownedChallenge := gen.Challenge("testchal",
+		gen.AddChallengeOwnerReferences(*metav1.NewControllerRef(order, cmacme.SchemeGroupVersion.WithKind(cmacme.OrderKind))),
+	)
+
+	tests := map[string]struct {
+		builder *testpkg.Builder
+	}{
+		"does nothing if the owning order exists": {
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{order, ownedChallenge},
+			},
+		},
+		"deletes a challenge whose owning order no longer exists": {
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{ownedChallenge},
+				ExpectedActions: []testpkg.Action{
+					newDeleteChallengeAction("testchal"),
+				},
+			},
+		},
+		"deletes a challenge whose owning order has been replaced by one with the same name": {
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{
+					gen.OrderFrom(order, gen.SetOrderUID("new-order-uid")),
+					ownedChallenge,
+				},
+				ExpectedActions: []testpkg.Action{
+					newDeleteChallengeAction("testchal"),
+				},
+			},
+		},
+		"does nothing if the challenge is not owned by an order": {
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{gen.Challenge("testchal")},
+			},
+		},
+		"does nothing if the orphaned challenge is already being deleted": {
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{
+					gen.ChallengeFrom(ownedChallenge,
+						gen.SetChallengeFinalizers(cmacme.ACMEFinalizer),
+						gen.SetChallengeDeletionTimestamp(metav1.NewTime(time.Now())),
+					),
+				},
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			test.builder.T = t
+			test.builder.Init()
+			defer test.builder.Stop()
+
+			c := &controller{}
+			c.Register(test.builder.Context)
+			test.builder.Start()
+
+			c.cleanupOrphanedChallenges(context.Background())
+
+			test.builder.CheckAndFinish()
+		})
+	}
+}
+
+// TestCleanupOrphanedChallengesDeletesOnlyOrphans checks that only the
+// orphaned Challenge is removed when Challenges owned by an existing Order
+// are present in the same namespace.
+func TestCleanupOrphanedChallengesDeletesOnlyOrphans(t *testing.T) {
+	order := gen.Order("testorder", gen.SetOrderUID("order-uid"))
+	ownedChallenge := gen.Challenge("ownedchal",
+		gen.AddChallengeOwnerReferences(*metav1.NewControllerRef(order, cmacme.SchemeGroupVersion.WithKind(cmacme.OrderKind))),
+	)
+	orphanedChallenge := gen.Challenge("orphanedchal",
+		gen.AddChallengeOwnerReferences(*metav1.NewControllerRef(gen.Order("deletedorder", gen.SetOrderUID("deleted-order-uid")), cmacme.SchemeGroupVersion.WithKind(cmacme.OrderKind))),
+	)
+
+	builder := &testpkg.Builder{
+		T:                  t,
+		CertManagerObjects: []runtime.Object{order, ownedChallenge, orphanedChallenge},
+		ExpectedActions: []testpkg.Action{
+			newDeleteChallengeAction("orphanedchal"),
+		},
+	}
+	builder.Init()
+	defer builder.Stop()
+
+	c := &controller{}
+	c.Register(builder.Context)
+	builder.Start()
+
+	c.cleanupOrphanedChallenges(context.Background())
+
+	builder.CheckAndFinish()
+
+	challenges := builder.FakeCMClient().AcmeV1().Challenges(gen.DefaultTestNamespace)
+	if _, err := challenges.Get(context.TODO(), "orphanedchal", metav1.GetOptions{}); !k8sErrors.IsNotFound(err) {
+		t.Errorf("expected orphaned challenge to be deleted, but got: %v", err)
+	}
+	if _, err := challenges.Get(context.TODO(), "ownedchal", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected owned challenge to still exist, but got: %v", err)
+	}
+}
+
+// newDeleteChallengeAction returns an Action that matches the deletion of
+// the named Challenge. The default matcher does not compare the names of
+// delete actions.
+func newDeleteChallengeAction(name string) testpkg.Action {
+	return testpkg.NewCustomMatch(
+		coretesting.NewDeleteAction(cmacme.SchemeGroupVersion.WithResource("challenges"), gen.DefaultTestNamespace, name),
+		func(exp, act coretesting.Action) error {
+			deleteAction, ok := act.(coretesting.DeleteAction)
+			if !ok {
+				return fmt.Errorf("expected a delete action, but got %T", act)
+			}
+			if deleteAction.GetName() != name {
+				return fmt.Errorf("expected deletion of challenge %q, but got %q", name, deleteAction.GetName())
+			}
+			return nil
+		},
+	)
+}
+
+// TestSyncCleansUpDeletedOrphanedChallenge checks that once an orphaned
+// Challenge has been deleted, its finalizer cleans up the solver resources.
+func TestSyncCleansUpDeletedOrphanedChallenge(t *testing.T) {
+	testIssuerHTTP01Enabled := gen.Issuer("testissuer", gen.SetIssuerACME(cmacme.ACMEIssuer{
+		Solvers: []cmacme.ACMEChallengeSolver{
+			{
+				HTTP01: &cmacme.ACMEChallengeSolverHTTP01{
+					Ingress: &cmacme.ACMEChallengeSolverHTTP01Ingress{},
+				},
+			},
+		},
+	}))
+	deletedChallenge := gen.Challenge("testchal",
+		gen.SetChallengeIssuer(cmmeta.ObjectReference{
+			Name: "testissuer",
+		}),
+		gen.SetChallengeType(cmacme.ACMEChallengeTypeHTTP01),
+		gen.SetChallengeProcessing(true),
+		gen.SetChallengePresented(true),
+		gen.AddChallengeOwnerReferences(*metav1.NewControllerRef(gen.Order("testorder"), cmacme.SchemeGroupVersion.WithKind(cmacme.OrderKind))),
+		gen.SetChallengeFinalizers(cmacme.ACMEFinalizer),
+		gen.SetChallengeDeletionTimestamp(metav1.NewTime(time.Now())),
+	)
+
+	cleanedUp := false
+	runTest(t, testT{
+		challenge: deletedChallenge,
+		httpSolver: &fakeSolver{
+			fakeCleanUp: func(context.Context, v1.GenericIssuer, *cmacme.Challenge) error {
+				cleanedUp = true
+				return nil
+			},
+		},
+		builder: &testpkg.Builder{
+			CertManagerObjects: []runtime.Object{deletedChallenge, testIssuerHTTP01Enabled},
+			ExpectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewUpdateSubresourceAction(cmacme.SchemeGroupVersion.WithResource("challenges"),
+					"status",
+					gen.DefaultTestNamespace,
+					deletedChallenge,
+				)),
+				// the finalizer is removed by re-slicing the list, which
+				// leaves an empty rather than a nil slice
+				testpkg.NewAction(coretesting.NewUpdateAction(cmacme.SchemeGroupVersion.WithResource("challenges"),
+					gen.DefaultTestNamespace,
+					gen.ChallengeFrom(deletedChallenge, gen.SetChallengeFinalizers([]string{}...)),
+				)),
+			},
+		},
+	})
+
+	if !cleanedUp {
+		t.Errorf("expected the challenge solver's resources to be cleaned up")
+	}
+}
@@ -18,6 +18,7 @@ package acmechallenges
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 
@@ -33,6 +34,7 @@ import (
 	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
 	"github.com/jetstack/cert-manager/pkg/feature"
 	dnsutil "github.com/jetstack/cert-manager/pkg/issuer/acme/dns/util"
+	"github.com/jetstack/cert-manager/pkg/issuer/acme/http"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
 	utilfeature "github.com/jetstack/cert-manager/pkg/util/feature"
 )
@@ -100,6 +102,20 @@ func (c *controller) Sync(ctx context.Context, ch *cmacme.Challenge) (err error)
 			}
 
 			err = solver.CleanUp(ctx, genericIssuer, ch)
+			var deferred *http.CleanupDeferredError
+			if errors.As(err, &deferred) {
+				log.V(logf.DebugLevel).Info("deferring clean up of challenge", "retry_after", deferred.RetryAfter)
+
+				key, err := controllerpkg.KeyFunc(ch)
+				// This is an unexpected edge case and should never occur
+				if err != nil {
+					return err
+				}
+
+				c.queue.AddAfter(key, deferred.RetryAfter)
+
+				return nil
+			}
 			if err != nil {
 				c.recorder.Eventf(ch, corev1.EventTypeWarning, "CleanUpError", "Error cleaning up challenge: %v", err)
 				ch.Status.Reason = err.Error()
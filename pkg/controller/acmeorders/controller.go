@@ -47,9 +47,11 @@ type controller struct {
 	// all the listers used by this controller
 	orderLister         cmacmelisters.OrderLister
 	challengeLister     cmacmelisters.ChallengeLister
+	certificateLister   cmlisters.CertificateLister
 	issuerLister        cmlisters.IssuerLister
 	clusterIssuerLister cmlisters.ClusterIssuerLister
 	secretLister        corelisters.SecretLister
+	namespaceLister     corelisters.NamespaceLister
 
 	// used for testing
 	clock clock.Clock
@@ -80,21 +82,28 @@ func (c *controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitin
 	orderInformer := ctx.SharedInformerFactory.Acme().V1().Orders()
 	issuerInformer := ctx.SharedInformerFactory.Certmanager().V1().Issuers()
 	challengeInformer := ctx.SharedInformerFactory.Acme().V1().Challenges()
+	// Certificates are used to find the certificate that an Order renews
+	certificateInformer := ctx.SharedInformerFactory.Certmanager().V1().Certificates()
 	secretInformer := ctx.KubeSharedInformerFactory.Core().V1().Secrets()
+	namespaceInformer := ctx.KubeSharedInformerFactory.Core().V1().Namespaces()
 	// build a list of InformerSynced functions that will be returned by the Register method.
 	// the controller will only begin processing items once all of these informers have synced.
 	mustSync := []cache.InformerSynced{
 		orderInformer.Informer().HasSynced,
 		issuerInformer.Informer().HasSynced,
 		challengeInformer.Informer().HasSynced,
+		certificateInformer.Informer().HasSynced,
 		secretInformer.Informer().HasSynced,
+		namespaceInformer.Informer().HasSynced,
 	}
 
 	// set all the references to the listers for used by the Sync function
 	c.orderLister = orderInformer.Lister()
 	c.issuerLister = issuerInformer.Lister()
 	c.challengeLister = challengeInformer.Lister()
+	c.certificateLister = certificateInformer.Lister()
 	c.secretLister = secretInformer.Lister()
+	c.namespaceLister = namespaceInformer.Lister()
 
 	// if we are running in non-namespaced mode (i.e. --namespace=""), we also
 	// register event handlers and obtain a lister for clusterissuers.
@@ -0,0 +1,50 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package selectors
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
+)
+
+func Annotations(sel cmacme.CertificateDNSNameSelector) Selector {
+	return &annotationSelector{
+		requiredAnnotations: sel.MatchAnnotations,
+	}
+}
+
+type annotationSelector struct {
+	requiredAnnotations map[string]string
+}
+
+func (s *annotationSelector) Matches(meta metav1.ObjectMeta, dnsName string) (bool, int) {
+	if len(s.requiredAnnotations) == 0 {
+		return true, 0
+	}
+
+	matches := 0
+	for k, v := range s.requiredAnnotations {
+		actualV, hasAnnotation := meta.Annotations[k]
+		if !hasAnnotation || v != actualV {
+			return false, matches
+		}
+		matches++
+	}
+
+	return true, matches
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package selectors
+
+import (
+	"net"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
+)
+
+func IdentifierType(sel cmacme.CertificateDNSNameSelector) Selector {
+	return &identifierTypeSelector{
+		identifierType: sel.IdentifierType,
+	}
+}
+
+type identifierTypeSelector struct {
+	identifierType string
+}
+
+func (s *identifierTypeSelector) Matches(meta metav1.ObjectMeta, dnsName string) (bool, int) {
+	if s.identifierType == "" {
+		return true, 0
+	}
+
+	if s.identifierType != identifierType(dnsName) {
+		return false, 0
+	}
+
+	return true, 1
+}
+
+// identifierType returns the ACME identifier type of the given authorization
+// identifier, which is an IP address for 'ip' identifiers and a DNS name
+// otherwise.
+func identifierType(identifier string) string {
+	if net.ParseIP(identifier) != nil {
+		return cmacme.ACMEIdentifierTypeIP
+	}
+	return cmacme.ACMEIdentifierTypeDNS
+}
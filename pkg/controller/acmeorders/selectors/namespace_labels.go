@@ -0,0 +1,60 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package selectors
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
+)
+
+// NamespaceLabels returns a Selector that matches if the given namespace
+// labels satisfy the selector's namespaceSelector. The object metadata passed
+// to Matches is ignored, as it is the namespace of the object that is
+// selected on.
+func NamespaceLabels(sel cmacme.CertificateDNSNameSelector, namespaceLabels map[string]string) Selector {
+	return &namespaceLabelSelector{
+		selector:        sel.NamespaceSelector,
+		namespaceLabels: namespaceLabels,
+	}
+}
+
+type namespaceLabelSelector struct {
+	selector        *metav1.LabelSelector
+	namespaceLabels map[string]string
+}
+
+func (s *namespaceLabelSelector) Matches(meta metav1.ObjectMeta, dnsName string) (bool, int) {
+	if s.selector == nil {
+		return true, 0
+	}
+
+	sel, err := metav1.LabelSelectorAsSelector(s.selector)
+	if err != nil {
+		// an invalid selector should have been rejected by validation, so
+		// never match it rather than guessing at the intended behaviour
+		return false, 0
+	}
+
+	if !sel.Matches(labels.Set(s.namespaceLabels)) {
+		return false, 0
+	}
+
+	requirements, _ := sel.Requirements()
+	return true, len(requirements)
+}
@@ -23,6 +23,7 @@ import (
 	"encoding/pem"
 	"fmt"
 	"reflect"
+	"time"
 
 	acmeapi "golang.org/x/crypto/acme"
 	corev1 "k8s.io/api/core/v1"
@@ -37,6 +38,7 @@ import (
 	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
 )
 
 func (c *controller) Sync(ctx context.Context, o *cmacme.Order) (err error) {
@@ -62,6 +64,31 @@ func (c *controller) Sync(ctx context.Context, o *cmacme.Order) (err error) {
 		dbg.Info("updated Order resource status successfully")
 	}()
 
+	// record any Retry-After delay requested by the ACME server whilst
+	// processing the Order, and requeue the Order after that delay instead of
+	// polling the ACME server again sooner.
+	ctx = acmecl.WithRetryAfter(ctx)
+	defer func() {
+		retryAfter, ok := acmecl.RetryAfter(ctx)
+		if !ok {
+			return
+		}
+		key, keyErr := keyFunc(o)
+		if keyErr != nil {
+			log.Error(keyErr, "failed to construct key for Order, not honoring Retry-After")
+			return
+		}
+		dbg.Info("ACME server requested a Retry-After delay, requeueing Order", "retry_after", retryAfter)
+		c.queue.AddAfter(key, retryAfter)
+		if err != nil {
+			// the Order has been requeued after the delay requested by the
+			// ACME server, so do not return the error to avoid it also being
+			// retried with the default backoff.
+			log.Error(err, "error processing Order, will retry after the delay requested by the ACME server", "retry_after", retryAfter)
+			err = nil
+		}
+	}()
+
 	genericIssuer, err := c.helper.GetGenericIssuer(o.Spec.IssuerRef, o.Namespace)
 	if err != nil {
 		return fmt.Errorf("error reading (cluster)issuer %q: %v", o.Spec.IssuerRef.Name, err)
@@ -74,7 +101,7 @@ func (c *controller) Sync(ctx context.Context, o *cmacme.Order) (err error) {
 	switch {
 	case o.Status.URL == "":
 		log.V(logf.DebugLevel).Info("Creating new ACME order as status.url is not set")
-		return c.createOrder(ctx, cl, o)
+		return c.createOrder(ctx, cl, o, genericIssuer)
 	case o.Status.FinalizeURL == "":
 		log.V(logf.DebugLevel).Info("Updating Order status as status.finalizeURL is not set")
 		_, err := c.updateOrderStatus(ctx, cl, o)
@@ -105,7 +132,11 @@ func (c *controller) Sync(ctx context.Context, o *cmacme.Order) (err error) {
 	}
 
 	dbg.Info("Computing list of Challenge resources that need to exist to complete this Order")
-	requiredChallenges, err := buildRequiredChallenges(ctx, cl, genericIssuer, o)
+	namespaceLabels, err := c.namespaceLabelsForSolvers(genericIssuer, o.Namespace)
+	if err != nil {
+		return err
+	}
+	requiredChallenges, err := buildRequiredChallenges(ctx, cl, genericIssuer, o, namespaceLabels)
 	if err != nil {
 		log.Error(err, "Failed to determine the list of Challenge resources needed for the Order")
 		c.recorder.Eventf(o, corev1.EventTypeWarning, "Solver", "Failed to determine a valid solver configuration for the set of domains on the Order: %v", err)
@@ -180,7 +211,7 @@ func (c *controller) Sync(ctx context.Context, o *cmacme.Order) (err error) {
 	return nil
 }
 
-func (c *controller) createOrder(ctx context.Context, cl acmecl.Interface, o *cmacme.Order) error {
+func (c *controller) createOrder(ctx context.Context, cl acmecl.Interface, o *cmacme.Order, issuer cmapi.GenericIssuer) error {
 	log := logf.FromContext(ctx)
 
 	if o.Status.URL != "" {
@@ -188,12 +219,33 @@ func (c *controller) createOrder(ctx context.Context, cl acmecl.Interface, o *cm
 	}
 	log.V(logf.DebugLevel).Info("order URL not set, submitting Order to ACME server")
 
-	dnsIdentifierSet := sets.NewString(o.Spec.DNSNames...)
+	// ACME identifiers must be in ASCII form, so any Unicode DNS names are
+	// converted to punycode.
+	dnsNames, err := pki.DNSNamesToASCII(o.Spec.DNSNames)
+	if err != nil {
+		c.setOrderState(&o.Status, string(cmacme.Errored))
+		o.Status.Reason = fmt.Sprintf("Failed to convert DNS names to ASCII: %v", err)
+		c.recorder.Event(o, corev1.EventTypeWarning, "InvalidDNSName", o.Status.Reason)
+		return nil
+	}
+	dnsIdentifierSet := sets.NewString(dnsNames...)
 	if o.Spec.CommonName != "" {
 		dnsIdentifierSet.Insert(o.Spec.CommonName)
 	}
 	log.V(logf.DebugLevel).Info("build set of domains for Order", "domains", dnsIdentifierSet.List())
 
+	if notAllowed := dnsNamesNotAllowed(issuer.GetSpec().ACME.AllowedDomains, dnsIdentifierSet.List()); len(notAllowed) > 0 {
+		if acme.IsFailureState(o.Status.State) {
+			// the Order has already been marked as failed
+			return nil
+		}
+		log.V(logf.DebugLevel).Info("Order contains DNS names not allowed by the issuer, marking Order as failed", "domains", notAllowed)
+		c.setOrderState(&o.Status, string(cmacme.Errored))
+		o.Status.Reason = fmt.Sprintf("DNS names %v are not allowed by the issuer's allowedDomains", notAllowed)
+		c.recorder.Event(o, corev1.EventTypeWarning, "DomainNotAllowed", o.Status.Reason)
+		return nil
+	}
+
 	ipIdentifierSet := sets.NewString(o.Spec.IPAddresses...)
 	log.V(logf.DebugLevel).Info("build set of IPs for Order", "domains", dnsIdentifierSet.List())
 
@@ -201,11 +253,27 @@ func (c *controller) createOrder(ctx context.Context, cl acmecl.Interface, o *cm
 	authzIDs = append(authzIDs, acmeapi.IPIDs(ipIdentifierSet.List()...)...)
 	// create a new order with the acme server
 
+	var notAfter time.Time
 	var options []acmeapi.OrderOption
 	if o.Spec.Duration != nil {
-		options = append(options, acmeapi.WithOrderNotAfter(c.clock.Now().Add(o.Spec.Duration.Duration)))
+		notAfter = c.clock.Now().Add(o.Spec.Duration.Duration)
+		options = append(options, acmeapi.WithOrderNotAfter(notAfter))
+	}
+
+	replaces, err := c.replacedCertificateID(ctx, o, dnsIdentifierSet.Union(ipIdentifierSet))
+	if err != nil {
+		return err
+	}
+	var acmeOrder *acmeapi.Order
+	if replaces != "" {
+		// If the ACME server rejects the replaces field, the Order is
+		// requested again without it, so any client error returned here is
+		// not caused by the replaces field.
+		log.V(logf.DebugLevel).Info("requesting Order as a replacement of the existing certificate", "replaces", replaces)
+		acmeOrder, err = cl.AuthorizeReplacementOrder(ctx, authzIDs, replaces, notAfter)
+	} else {
+		acmeOrder, err = cl.AuthorizeOrder(ctx, authzIDs, options...)
 	}
-	acmeOrder, err := cl.AuthorizeOrder(ctx, authzIDs, options...)
 	if acmeErr, ok := err.(*acmeapi.Error); ok {
 		if acmeErr.StatusCode >= 400 && acmeErr.StatusCode < 500 {
 			log.Error(err, "failed to create Order resource due to bad request, marking Order as failed")
@@ -227,6 +295,75 @@ func (c *controller) createOrder(ctx context.Context, cl acmecl.Interface, o *cm
 	return nil
 }
 
+// replacedCertificateID returns the ARI certificate identifier of the
+// certificate that the given Order renews, so that the ACME server is able to
+// apply any renewal exemptions to the new Order.
+// An empty string is returned if the Certificate the Order belongs to does
+// not have an existing certificate issued by the same issuer for any of the
+// given identifiers.
+func (c *controller) replacedCertificateID(ctx context.Context, o *cmacme.Order, identifiers sets.String) (string, error) {
+	log := logf.FromContext(ctx)
+
+	crtName := o.Annotations[cmapi.CertificateNameKey]
+	if crtName == "" {
+		return "", nil
+	}
+	crt, err := c.certificateLister.Certificates(o.Namespace).Get(crtName)
+	if apierrors.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	secret, err := c.secretLister.Secrets(o.Namespace).Get(crt.Spec.SecretName)
+	if apierrors.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// the certificate being replaced must have been issued by the same ACME
+	// server
+	issuerKind := o.Spec.IssuerRef.Kind
+	if issuerKind == "" {
+		issuerKind = cmapi.IssuerKind
+	}
+	secretIssuerKind := secret.Annotations[cmapi.IssuerKindAnnotationKey]
+	if secretIssuerKind == "" {
+		secretIssuerKind = cmapi.IssuerKind
+	}
+	if secret.Annotations[cmapi.IssuerNameAnnotationKey] != o.Spec.IssuerRef.Name || secretIssuerKind != issuerKind {
+		return "", nil
+	}
+
+	if len(secret.Data[corev1.TLSCertKey]) == 0 {
+		return "", nil
+	}
+	cert, err := pki.DecodeX509CertificateBytes(secret.Data[corev1.TLSCertKey])
+	if err != nil {
+		log.V(logf.DebugLevel).Info("not requesting Order as a replacement as the existing certificate cannot be decoded", "error", err.Error())
+		return "", nil
+	}
+
+	// ACME servers require the certificate being replaced to share at least
+	// one identifier with the new Order
+	certIdentifiers := sets.NewString(cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		certIdentifiers.Insert(ip.String())
+	}
+	if !certIdentifiers.HasAny(identifiers.List()...) {
+		return "", nil
+	}
+
+	certID, err := acmecl.ARICertID(cert)
+	if err != nil {
+		log.V(logf.DebugLevel).Info("not requesting Order as a replacement of the existing certificate", "error", err.Error())
+		return "", nil
+	}
+	return certID, nil
+}
+
 func (c *controller) updateOrderStatus(ctx context.Context, cl acmecl.Interface, o *cmacme.Order) (*acmeapi.Order, error) {
 	log := logf.FromContext(ctx)
 	if o.Status.URL == "" {
@@ -18,23 +18,31 @@ package acmeorders
 
 import (
 	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/pem"
 	"errors"
 	"fmt"
+	"math/big"
+	"net/http"
 	"testing"
 	"time"
 
 	acmeapi "golang.org/x/crypto/acme"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	coretesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/util/workqueue"
 	fakeclock "k8s.io/utils/clock/testing"
 
 	accountstest "github.com/jetstack/cert-manager/pkg/acme/accounts/test"
 	acmecl "github.com/jetstack/cert-manager/pkg/acme/client"
 	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
 	"github.com/jetstack/cert-manager/test/unit/gen"
 )
 
@@ -87,8 +95,13 @@ func TestSyncHappyPath(t *testing.T) {
 		}),
 	)
 
+	testIssuerHTTP01AllowedDomains := gen.IssuerFrom(testIssuerHTTP01TestCom, gen.SetIssuerACMEAllowedDomains("test.com", "*.test.com"))
+	testIssuerHTTP01OtherAllowedDomains := gen.IssuerFrom(testIssuerHTTP01TestCom, gen.SetIssuerACMEAllowedDomains("*.example.com"))
+
 	testOrderIP := gen.Order("testorder", gen.SetOrderIssuer(cmmeta.ObjectReference{Name: testIssuerHTTP01.Name}), gen.SetOrderIPAddresses("10.0.0.1"))
 
+	testOrderIDN := gen.Order("testorder", gen.SetOrderIssuer(cmmeta.ObjectReference{Name: testIssuerHTTP01.Name}), gen.SetOrderDNSNames("例え.jp"))
+
 	pendingStatus := cmacme.OrderStatus{
 		State:       cmacme.Pending,
 		URL:         "http://testurl.com/abcde",
@@ -167,7 +180,7 @@ rUCGwbCUDI0mxadJ3Bz4WxR6fyNpBK2yAinWEsikxqEt
 			return "key", nil
 		},
 	}
-	testAuthorizationChallenge, err := buildChallenge(context.TODO(), fakeHTTP01ACMECl, testIssuerHTTP01TestCom, testOrderPending, testOrderPending.Status.Authorizations[0])
+	testAuthorizationChallenge, err := buildChallenge(context.TODO(), fakeHTTP01ACMECl, testIssuerHTTP01TestCom, testOrderPending, nil, testOrderPending.Status.Authorizations[0])
 	if err != nil {
 		t.Fatalf("error building Challenge resource test fixture: %v", err)
 	}
@@ -252,6 +265,56 @@ rUCGwbCUDI0mxadJ3Bz4WxR6fyNpBK2yAinWEsikxqEt
 				},
 			},
 		},
+		"create a new order with the acme server if all DNS names are within the issuer's allowedDomains": {
+			order: testOrder,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{testIssuerHTTP01AllowedDomains, testOrder},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(cmacme.SchemeGroupVersion.WithResource("orders"),
+						"status",
+						testOrderPending.Namespace,
+						gen.OrderFrom(testOrder, gen.SetOrderStatus(cmacme.OrderStatus{
+							State:       cmacme.Pending,
+							URL:         "http://testurl.com/abcde",
+							FinalizeURL: "http://testurl.com/abcde/finalize",
+							Authorizations: []cmacme.ACMEAuthorization{
+								{
+									URL: "http://authzurl",
+								},
+							},
+						})))),
+				},
+			},
+			acmeClient: &acmecl.FakeACME{
+				FakeAuthorizeOrder: func(ctx context.Context, id []acmeapi.AuthzID, opt ...acmeapi.OrderOption) (*acmeapi.Order, error) {
+					return testACMEOrderPending, nil
+				},
+			},
+		},
+		"mark the order as errored without contacting the acme server if a DNS name is outside the issuer's allowedDomains": {
+			order: testOrder,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{testIssuerHTTP01OtherAllowedDomains, testOrder},
+				ExpectedEvents: []string{
+					"Warning DomainNotAllowed DNS names [test.com] are not allowed by the issuer's allowedDomains",
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(cmacme.SchemeGroupVersion.WithResource("orders"),
+						"status",
+						testOrder.Namespace,
+						gen.OrderFrom(testOrder, gen.SetOrderStatus(cmacme.OrderStatus{
+							State:       cmacme.Errored,
+							Reason:      "DNS names [test.com] are not allowed by the issuer's allowedDomains",
+							FailureTime: &nowMetaTime,
+						})))),
+				},
+			},
+			acmeClient: &acmecl.FakeACME{
+				FakeAuthorizeOrder: func(ctx context.Context, id []acmeapi.AuthzID, opt ...acmeapi.OrderOption) (*acmeapi.Order, error) {
+					return nil, errors.New("AuthorizeOrder should not be called")
+				},
+			},
+		},
 		"create a new order with the acme server with an IP address": {
 			order: testOrderIP,
 			builder: &testpkg.Builder{
@@ -291,6 +354,35 @@ rUCGwbCUDI0mxadJ3Bz4WxR6fyNpBK2yAinWEsikxqEt
 				},
 			},
 		},
+		"create a new order with the acme server with a Unicode DNS name converted to punycode": {
+			order: testOrderIDN,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{testIssuerHTTP01, testOrderIDN},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(cmacme.SchemeGroupVersion.WithResource("orders"),
+						"status",
+						testOrderPending.Namespace,
+						gen.OrderFrom(testOrderIDN, gen.SetOrderStatus(cmacme.OrderStatus{
+							State:       cmacme.Pending,
+							URL:         "http://testurl.com/abcde",
+							FinalizeURL: "http://testurl.com/abcde/finalize",
+							Authorizations: []cmacme.ACMEAuthorization{
+								{
+									URL: "http://authzurl",
+								},
+							},
+						})))),
+				},
+			},
+			acmeClient: &acmecl.FakeACME{
+				FakeAuthorizeOrder: func(ctx context.Context, id []acmeapi.AuthzID, opt ...acmeapi.OrderOption) (*acmeapi.Order, error) {
+					if len(id) != 1 || id[0].Value != "xn--r8jz45g.jp" || id[0].Type != "dns" {
+						return nil, fmt.Errorf("expected a single dns AuthzID for xn--r8jz45g.jp but got %v", id)
+					}
+					return testACMEOrderPending, nil
+				},
+			},
+		},
 		"create a challenge resource for the test.com dnsName on the order": {
 			order: testOrderPending,
 			builder: &testpkg.Builder{
@@ -636,3 +728,296 @@ func runTest(t *testing.T, test testT) {
 
 	test.builder.CheckAndFinish(err)
 }
+
+func TestSyncReplacementOrder(t *testing.T) {
+	testIssuer := gen.Issuer("testissuer", gen.SetIssuerACME(cmacme.ACMEIssuer{
+		Solvers: []cmacme.ACMEChallengeSolver{
+			{
+				HTTP01: &cmacme.ACMEChallengeSolverHTTP01{
+					Ingress: &cmacme.ACMEChallengeSolverHTTP01Ingress{},
+				},
+			},
+		},
+	}))
+	testOrder := gen.Order("testorder",
+		gen.SetOrderCommonName("test.com"),
+		gen.SetOrderIssuer(cmmeta.ObjectReference{
+			Name: testIssuer.Name,
+		}),
+	)
+	testOrder.Annotations = map[string]string{cmapi.CertificateNameKey: "testcrt"}
+	testCertificate := gen.Certificate("testcrt",
+		gen.SetCertificateNamespace(testOrder.Namespace),
+		gen.SetCertificateSecretName("testcrt-tls"),
+	)
+
+	caKey, err := pki.GenerateRSAPrivateKey(2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		SubjectKeyId:          []byte{1, 2, 3, 4},
+	}
+	_, caCert, err := pki.SignCertificate(caTemplate, caTemplate, caKey.Public(), caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signCertificate := func(dnsName string) []byte {
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(0x87654321),
+			NotBefore:    time.Now(),
+			NotAfter:     time.Now().Add(time.Hour),
+			DNSNames:     []string{dnsName},
+		}
+		certPEM, _, err := pki.SignCertificate(template, caCert, caKey.Public(), caKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return certPEM
+	}
+	secret := func(issuerName string, certPEM []byte) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: testOrder.Namespace,
+				Name:      "testcrt-tls",
+				Annotations: map[string]string{
+					cmapi.IssuerNameAnnotationKey: issuerName,
+					cmapi.IssuerKindAnnotationKey: cmapi.IssuerKind,
+				},
+			},
+			Data: map[string][]byte{corev1.TLSCertKey: certPEM},
+		}
+	}
+
+	testACMEOrderPending := &acmeapi.Order{
+		URI:         "http://testurl.com/abcde",
+		FinalizeURL: "http://testurl.com/abcde/finalize",
+		AuthzURLs:   []string{"http://authzurl"},
+		Status:      acmeapi.StatusPending,
+	}
+	updatePendingAction := testpkg.NewAction(coretesting.NewUpdateSubresourceAction(cmacme.SchemeGroupVersion.WithResource("orders"),
+		"status",
+		testOrder.Namespace,
+		gen.OrderFrom(testOrder, gen.SetOrderStatus(cmacme.OrderStatus{
+			State:       cmacme.Pending,
+			URL:         "http://testurl.com/abcde",
+			FinalizeURL: "http://testurl.com/abcde/finalize",
+			Authorizations: []cmacme.ACMEAuthorization{
+				{
+					URL: "http://authzurl",
+				},
+			},
+		}))))
+
+	tests := map[string]struct {
+		secrets []runtime.Object
+
+		// expectedReplaces is the certificate identifier expected to be sent
+		// to the ACME server, or empty if a regular order should be created
+		expectedReplaces string
+	}{
+		"create a replacement order if the existing certificate was issued by the same issuer": {
+			secrets:          []runtime.Object{secret(testIssuer.Name, signCertificate("test.com"))},
+			expectedReplaces: "AQIDBA.AIdlQyE",
+		},
+		"create a regular order if the existing certificate was issued by another issuer": {
+			secrets: []runtime.Object{secret("otherissuer", signCertificate("test.com"))},
+		},
+		"create a regular order if the existing certificate does not share any identifiers with the order": {
+			secrets: []runtime.Object{secret(testIssuer.Name, signCertificate("example.com"))},
+		},
+		"create a regular order if there is no existing certificate": {},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var replaces string
+			called := false
+			runTest(t, testT{
+				order: testOrder,
+				builder: &testpkg.Builder{
+					CertManagerObjects: []runtime.Object{testIssuer, testOrder, testCertificate},
+					KubeObjects:        test.secrets,
+					ExpectedActions:    []testpkg.Action{updatePendingAction},
+				},
+				acmeClient: &acmecl.FakeACME{
+					FakeAuthorizeOrder: func(ctx context.Context, id []acmeapi.AuthzID, opt ...acmeapi.OrderOption) (*acmeapi.Order, error) {
+						called = true
+						return testACMEOrderPending, nil
+					},
+					FakeAuthorizeReplacementOrder: func(ctx context.Context, id []acmeapi.AuthzID, r string, notAfter time.Time) (*acmeapi.Order, error) {
+						called = true
+						replaces = r
+						return testACMEOrderPending, nil
+					},
+				},
+			})
+			if !called {
+				t.Fatalf("expected an order to be created with the ACME server")
+			}
+			if replaces != test.expectedReplaces {
+				t.Errorf("expected order to replace %q but got %q", test.expectedReplaces, replaces)
+			}
+		})
+	}
+}
+
+// fakeQueue records the delay of items added to the queue with AddAfter.
+type fakeQueue struct {
+	workqueue.RateLimitingInterface
+
+	addedAfter map[interface{}]time.Duration
+}
+
+func (q *fakeQueue) AddAfter(item interface{}, duration time.Duration) {
+	q.addedAfter[item] = duration
+}
+
+func TestSyncRetryAfter(t *testing.T) {
+	nowTime := time.Now()
+	fixedClock := fakeclock.NewFakeClock(nowTime)
+
+	testIssuer := gen.Issuer("testissuer", gen.SetIssuerACME(cmacme.ACMEIssuer{
+		Solvers: []cmacme.ACMEChallengeSolver{
+			{
+				HTTP01: &cmacme.ACMEChallengeSolverHTTP01{
+					Ingress: &cmacme.ACMEChallengeSolverHTTP01Ingress{},
+				},
+			},
+		},
+	}))
+	testOrder := gen.Order("testorder",
+		gen.SetOrderCommonName("test.com"),
+		gen.SetOrderIssuer(cmmeta.ObjectReference{
+			Name: testIssuer.Name,
+		}),
+		gen.SetOrderURL("http://testurl.com/abcde"),
+	)
+	testOrderProcessing := gen.OrderFrom(testOrder, gen.SetOrderStatus(cmacme.OrderStatus{
+		URL:         "http://testurl.com/abcde",
+		FinalizeURL: "http://testurl.com/abcde/finalize",
+		State:       cmacme.Processing,
+		// the ACME order does not name any authorizations
+		Authorizations: []cmacme.ACMEAuthorization{},
+	}))
+	testACMEOrderProcessing := &acmeapi.Order{
+		URI:         "http://testurl.com/abcde",
+		Status:      acmeapi.StatusProcessing,
+		FinalizeURL: "http://testurl.com/abcde/finalize",
+	}
+	updateProcessingAction := testpkg.NewAction(coretesting.NewUpdateSubresourceAction(cmacme.SchemeGroupVersion.WithResource("orders"),
+		"status",
+		testOrderProcessing.Namespace, testOrderProcessing))
+
+	tests := map[string]struct {
+		acmeClient       acmecl.Interface
+		expectedActions  []testpkg.Action
+		expectRetryAfter *time.Duration
+		expectErr        bool
+	}{
+		"requeue after the delay in seconds requested when polling the order": {
+			acmeClient: &acmecl.FakeACME{
+				FakeGetOrder: func(ctx context.Context, url string) (*acmeapi.Order, error) {
+					acmecl.RecordRetryAfter(ctx, "30", nowTime)
+					return testACMEOrderProcessing, nil
+				},
+			},
+			expectedActions:  []testpkg.Action{updateProcessingAction},
+			expectRetryAfter: durationPtr(30 * time.Second),
+		},
+		"requeue after the delay of an HTTP-date requested when polling the order": {
+			acmeClient: &acmecl.FakeACME{
+				FakeGetOrder: func(ctx context.Context, url string) (*acmeapi.Order, error) {
+					acmecl.RecordRetryAfter(ctx, nowTime.Add(2*time.Minute).Format(http.TimeFormat), nowTime)
+					return testACMEOrderProcessing, nil
+				},
+			},
+			expectedActions: []testpkg.Action{updateProcessingAction},
+			// HTTP-dates have a resolution of one second
+			expectRetryAfter: durationPtr(nowTime.Add(2 * time.Minute).Truncate(time.Second).Sub(nowTime)),
+		},
+		"requeue after the requested delay instead of returning an error when polling the order fails": {
+			acmeClient: &acmecl.FakeACME{
+				FakeGetOrder: func(ctx context.Context, url string) (*acmeapi.Order, error) {
+					acmecl.RecordRetryAfter(ctx, "120", nowTime)
+					return nil, &acmeapi.Error{StatusCode: http.StatusServiceUnavailable, Detail: "service unavailable"}
+				},
+			},
+			expectedActions:  []testpkg.Action{},
+			expectRetryAfter: durationPtr(2 * time.Minute),
+		},
+		"do not requeue if no delay is requested when polling the order": {
+			acmeClient: &acmecl.FakeACME{
+				FakeGetOrder: func(ctx context.Context, url string) (*acmeapi.Order, error) {
+					return testACMEOrderProcessing, nil
+				},
+			},
+			expectedActions: []testpkg.Action{updateProcessingAction},
+		},
+		"return an error if polling the order fails and no delay is requested": {
+			acmeClient: &acmecl.FakeACME{
+				FakeGetOrder: func(ctx context.Context, url string) (*acmeapi.Order, error) {
+					return nil, &acmeapi.Error{StatusCode: http.StatusServiceUnavailable, Detail: "service unavailable"}
+				},
+			},
+			expectedActions: []testpkg.Action{},
+			expectErr:       true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			fixedClock.SetTime(nowTime)
+			builder := &testpkg.Builder{
+				T:                  t,
+				Clock:              fixedClock,
+				CertManagerObjects: []runtime.Object{testIssuer, testOrder},
+				ExpectedActions:    test.expectedActions,
+			}
+			builder.Init()
+			defer builder.Stop()
+
+			c := &controller{}
+			c.Register(builder.Context)
+			queue := &fakeQueue{RateLimitingInterface: c.queue, addedAfter: make(map[interface{}]time.Duration)}
+			c.queue = queue
+			c.accountRegistry = &accountstest.FakeRegistry{
+				GetClientFunc: func(_ string) (acmecl.Interface, error) {
+					return test.acmeClient, nil
+				},
+			}
+			builder.Start()
+
+			err := c.Sync(context.Background(), testOrder)
+			if err != nil && !test.expectErr {
+				t.Errorf("Expected function to not error, but got: %v", err)
+			}
+			if err == nil && test.expectErr {
+				t.Errorf("Expected function to get an error, but got: %v", err)
+			}
+
+			key, _ := keyFunc(testOrder)
+			retryAfter, requeued := queue.addedAfter[key]
+			switch {
+			case test.expectRetryAfter == nil && requeued:
+				t.Errorf("Expected Order to not be requeued, but it was requeued after %s", retryAfter)
+			case test.expectRetryAfter != nil && !requeued:
+				t.Errorf("Expected Order to be requeued after %s, but it was not requeued", *test.expectRetryAfter)
+			case test.expectRetryAfter != nil && retryAfter != *test.expectRetryAfter:
+				t.Errorf("Expected Order to be requeued after %s, but it was requeued after %s", *test.expectRetryAfter, retryAfter)
+			}
+
+			builder.CheckAndFinish(err)
+		})
+	}
+}
+
+func durationPtr(d time.Duration) *time.Duration {
+	return &d
+}
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"hash/fnv"
+	"path"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -37,7 +38,56 @@ var (
 	orderGvk = cmacme.SchemeGroupVersion.WithKind("Order")
 )
 
-func buildRequiredChallenges(ctx context.Context, cl acmecl.Interface, issuer cmapi.GenericIssuer, o *cmacme.Order) ([]cmacme.Challenge, error) {
+// namespaceLabelsForSolvers returns the labels of the given namespace if any
+// of the issuer's solvers has a namespaceSelector. Otherwise it returns nil,
+// so that the namespace does not need to be fetched.
+func (c *controller) namespaceLabelsForSolvers(issuer cmapi.GenericIssuer, namespace string) (map[string]string, error) {
+	usesNamespaceSelector := false
+	for _, s := range issuer.GetSpec().ACME.Solvers {
+		if s.Selector != nil && s.Selector.NamespaceSelector != nil {
+			usesNamespaceSelector = true
+			break
+		}
+	}
+	if !usesNamespaceSelector {
+		return nil, nil
+	}
+
+	ns, err := c.namespaceLister.Get(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return ns.Labels, nil
+}
+
+// dnsNamesNotAllowed returns the DNS names that do not match any of the
+// allowed glob patterns. If no patterns are given, all DNS names are allowed.
+func dnsNamesNotAllowed(allowedDomains []string, dnsNames []string) []string {
+	if len(allowedDomains) == 0 {
+		return nil
+	}
+
+	var notAllowed []string
+	for _, name := range dnsNames {
+		allowed := false
+		for _, pattern := range allowedDomains {
+			// invalid patterns are rejected by validation, so errors are
+			// treated as a non-match here
+			if ok, _ := path.Match(pattern, name); ok {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			notAllowed = append(notAllowed, name)
+		}
+	}
+
+	return notAllowed
+}
+
+func buildRequiredChallenges(ctx context.Context, cl acmecl.Interface, issuer cmapi.GenericIssuer, o *cmacme.Order, namespaceLabels map[string]string) ([]cmacme.Challenge, error) {
 	chs := make([]cmacme.Challenge, 0)
 	for _, a := range o.Status.Authorizations {
 		if a.InitialState == cmacme.Valid {
@@ -48,7 +98,7 @@ func buildRequiredChallenges(ctx context.Context, cl acmecl.Interface, issuer cm
 			logf.FromContext(ctx).V(logf.DebugLevel).Info("Authorization already valid, not creating Challenge resource", "identifier", a.Identifier, "is_wildcard", wc)
 			continue
 		}
-		ch, err := buildChallenge(ctx, cl, issuer, o, a)
+		ch, err := buildChallenge(ctx, cl, issuer, o, namespaceLabels, a)
 		if err != nil {
 			return nil, err
 		}
@@ -57,8 +107,8 @@ func buildRequiredChallenges(ctx context.Context, cl acmecl.Interface, issuer cm
 	return chs, nil
 }
 
-func buildChallenge(ctx context.Context, cl acmecl.Interface, issuer cmapi.GenericIssuer, o *cmacme.Order, authz cmacme.ACMEAuthorization) (*cmacme.Challenge, error) {
-	chSpec, err := challengeSpecForAuthorization(ctx, cl, issuer, o, authz)
+func buildChallenge(ctx context.Context, cl acmecl.Interface, issuer cmapi.GenericIssuer, o *cmacme.Order, namespaceLabels map[string]string, authz cmacme.ACMEAuthorization) (*cmacme.Challenge, error) {
+	chSpec, err := challengeSpecForAuthorization(ctx, cl, issuer, o, namespaceLabels, authz)
 	if err != nil {
 		// TODO: in this case, we should probably not return the error as it's
 		//  unlikely we can make it succeed by retrying.
@@ -96,7 +146,7 @@ func hashChallenge(spec cmacme.ChallengeSpec) (uint32, error) {
 	return hashF.Sum32(), nil
 }
 
-func challengeSpecForAuthorization(ctx context.Context, cl acmecl.Interface, issuer cmapi.GenericIssuer, o *cmacme.Order, authz cmacme.ACMEAuthorization) (*cmacme.ChallengeSpec, error) {
+func challengeSpecForAuthorization(ctx context.Context, cl acmecl.Interface, issuer cmapi.GenericIssuer, o *cmacme.Order, namespaceLabels map[string]string, authz cmacme.ACMEAuthorization) (*cmacme.ChallengeSpec, error) {
 	log := logf.FromContext(ctx, "challengeSpecForAuthorization")
 	dbg := log.V(logf.DebugLevel)
 
@@ -112,6 +162,71 @@ func challengeSpecForAuthorization(ctx context.Context, cl acmecl.Interface, iss
 		domainToFind = "*." + domainToFind
 	}
 
+	// 2. if the Order expresses a preference for particular challenge types,
+	//    attempt to select a solver for each of those types in turn before
+	//    falling back to considering solvers of any type
+	preference, err := util.ACMEChallengeTypePreference(o.Annotations)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %v", cmacme.ACMEChallengeTypePreferenceAnnotationKey, err)
+	}
+
+	var selectedSolver *cmacme.ACMEChallengeSolver
+	var selectedChallenge *cmacme.ACMEChallenge
+	for _, t := range append(preference, "") {
+		selectedSolver, selectedChallenge = selectSolverForAuthorization(ctx, solvers, o, namespaceLabels, authz, domainToFind, t)
+		if selectedSolver != nil {
+			break
+		}
+		if t != "" {
+			dbg.Info("no solver can be used for preferred challenge type", "challenge_type", t)
+		}
+	}
+
+	if selectedSolver == nil || selectedChallenge == nil {
+		return nil, fmt.Errorf("no configured challenge solvers can be used for this challenge")
+	}
+
+	// It should never be possible for this case to be hit as earlier in this
+	// method we already assert that the challenge type is one of 'http-01'
+	// or 'dns-01'.
+	chType, err := challengeType(selectedChallenge.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := keyForChallenge(cl, selectedChallenge.Token, chType)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. handle overriding the HTTP01 ingress class and name fields using the
+	//    ACMECertificateHTTP01IngressNameOverride & Class annotations
+	if err := applyIngressParameterAnnotationOverrides(o, selectedSolver); err != nil {
+		return nil, err
+	}
+
+	// 5. construct Challenge resource with spec.solver field set
+	return &cmacme.ChallengeSpec{
+		AuthorizationURL: authz.URL,
+		Type:             chType,
+		URL:              selectedChallenge.URL,
+		DNSName:          authz.Identifier,
+		Token:            selectedChallenge.Token,
+		Key:              key,
+		// selectedSolver cannot be nil due to the check above.
+		Solver:    *selectedSolver,
+		Wildcard:  wc,
+		IssuerRef: o.Spec.IssuerRef,
+	}, nil
+}
+
+// selectSolverForAuthorization returns the most specific of the given solvers that matches
+// the Order and can be used to solve one of the authorization's challenges,
+// along with the challenge it will solve. If allowedType is not empty, only
+// challenges of that type are considered.
+func selectSolverForAuthorization(ctx context.Context, solvers []cmacme.ACMEChallengeSolver, o *cmacme.Order, namespaceLabels map[string]string, authz cmacme.ACMEAuthorization, domainToFind, allowedType string) (*cmacme.ACMEChallengeSolver, *cmacme.ACMEChallenge) {
+	dbg := logf.FromContext(ctx, "selectSolverForAuthorization").V(logf.DebugLevel)
+
 	var selectedSolver *cmacme.ACMEChallengeSolver
 	var selectedChallenge *cmacme.ACMEChallenge
 	selectedNumLabelsMatch := 0
@@ -120,6 +235,9 @@ func challengeSpecForAuthorization(ctx context.Context, cl acmecl.Interface, iss
 
 	challengeForSolver := func(solver *cmacme.ACMEChallengeSolver) *cmacme.ACMEChallenge {
 		for _, ch := range authz.Challenges {
+			if allowedType != "" && ch.Type != allowedType {
+				continue
+			}
 			switch {
 			case ch.Type == "http-01" && solver.HTTP01 != nil:
 				return &ch
@@ -130,7 +248,7 @@ func challengeSpecForAuthorization(ctx context.Context, cl acmecl.Interface, iss
 		return nil
 	}
 
-	// 2. filter solvers to only those that matchLabels
+	// filter solvers to only those that matchLabels
 	for _, cfg := range solvers {
 		acmech := challengeForSolver(&cfg)
 		if acmech == nil {
@@ -150,14 +268,21 @@ func challengeSpecForAuthorization(ctx context.Context, cl acmecl.Interface, iss
 		}
 
 		labelsMatch, numLabelsMatch := selectors.Labels(*cfg.Selector).Matches(o.ObjectMeta, domainToFind)
+		annotationsMatch, numAnnotationsMatch := selectors.Annotations(*cfg.Selector).Matches(o.ObjectMeta, domainToFind)
+		namespaceLabelsMatch, numNamespaceLabelsMatch := selectors.NamespaceLabels(*cfg.Selector, namespaceLabels).Matches(o.ObjectMeta, domainToFind)
 		dnsNamesMatch, numDNSNamesMatch := selectors.DNSNames(*cfg.Selector).Matches(o.ObjectMeta, domainToFind)
 		dnsZonesMatch, numDNSZonesMatch := selectors.DNSZones(*cfg.Selector).Matches(o.ObjectMeta, domainToFind)
+		identifierTypeMatch, numIdentifierTypeMatch := selectors.IdentifierType(*cfg.Selector).Matches(o.ObjectMeta, domainToFind)
 
-		if !labelsMatch || !dnsNamesMatch || !dnsZonesMatch {
-			dbg.Info("not selecting solver", "labels_match", labelsMatch, "dnsnames_match", dnsNamesMatch, "dnszones_match", dnsZonesMatch)
+		if !labelsMatch || !annotationsMatch || !namespaceLabelsMatch || !dnsNamesMatch || !dnsZonesMatch || !identifierTypeMatch {
+			dbg.Info("not selecting solver", "labels_match", labelsMatch, "annotations_match", annotationsMatch, "namespace_labels_match", namespaceLabelsMatch, "dnsnames_match", dnsNamesMatch, "dnszones_match", dnsZonesMatch, "identifier_type_match", identifierTypeMatch)
 			continue
 		}
 
+		// matching annotations, namespace labels and identifier types carry
+		// the same weight as matching labels on the resource itself
+		numLabelsMatch += numAnnotationsMatch + numNamespaceLabelsMatch + numIdentifierTypeMatch
+
 		dbg.Info("selector matches")
 
 		selectSolver := func() {
@@ -264,42 +389,7 @@ func challengeSpecForAuthorization(ctx context.Context, cl acmecl.Interface, iss
 		// fallback to choosing the first in the list
 	}
 
-	if selectedSolver == nil || selectedChallenge == nil {
-		return nil, fmt.Errorf("no configured challenge solvers can be used for this challenge")
-	}
-
-	// It should never be possible for this case to be hit as earlier in this
-	// method we already assert that the challenge type is one of 'http-01'
-	// or 'dns-01'.
-	chType, err := challengeType(selectedChallenge.Type)
-	if err != nil {
-		return nil, err
-	}
-
-	key, err := keyForChallenge(cl, selectedChallenge.Token, chType)
-	if err != nil {
-		return nil, err
-	}
-
-	// 4. handle overriding the HTTP01 ingress class and name fields using the
-	//    ACMECertificateHTTP01IngressNameOverride & Class annotations
-	if err := applyIngressParameterAnnotationOverrides(o, selectedSolver); err != nil {
-		return nil, err
-	}
-
-	// 5. construct Challenge resource with spec.solver field set
-	return &cmacme.ChallengeSpec{
-		AuthorizationURL: authz.URL,
-		Type:             chType,
-		URL:              selectedChallenge.URL,
-		DNSName:          authz.Identifier,
-		Token:            selectedChallenge.Token,
-		Key:              key,
-		// selectedSolver cannot be nil due to the check above.
-		Solver:    *selectedSolver,
-		Wildcard:  wc,
-		IssuerRef: o.Spec.IssuerRef,
-	}, nil
+	return selectedSolver, selectedChallenge
 }
 
 func challengeType(t string) (cmacme.ACMEChallengeType, error) {
@@ -69,6 +69,64 @@ func TestChallengeSpecForAuthorization(t *testing.T) {
 			},
 		},
 	}
+	tenantANamespaceSelectorSolver := cmacme.ACMEChallengeSolver{
+		Selector: &cmacme.CertificateDNSNameSelector{
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"tenant": "a",
+				},
+			},
+		},
+		HTTP01: &cmacme.ACMEChallengeSolverHTTP01{
+			Ingress: &cmacme.ACMEChallengeSolverHTTP01Ingress{
+				Name: "tenant-a-namespace-selector-solver",
+			},
+		},
+	}
+	dnsAnnotationSelectorSolver := cmacme.ACMEChallengeSolver{
+		Selector: &cmacme.CertificateDNSNameSelector{
+			MatchAnnotations: map[string]string{
+				"example.com/solver": "dns",
+			},
+		},
+		DNS01: &cmacme.ACMEChallengeSolverDNS01{
+			Cloudflare: &cmacme.ACMEIssuerDNS01ProviderCloudflare{
+				Email: "test",
+			},
+		},
+	}
+	httpAnnotationSelectorSolver := cmacme.ACMEChallengeSolver{
+		Selector: &cmacme.CertificateDNSNameSelector{
+			MatchAnnotations: map[string]string{
+				"example.com/solver": "http",
+			},
+		},
+		HTTP01: &cmacme.ACMEChallengeSolverHTTP01{
+			Ingress: &cmacme.ACMEChallengeSolverHTTP01Ingress{
+				Name: "http-annotation-selector-solver",
+			},
+		},
+	}
+	dnsIdentifierTypeSelectorSolver := cmacme.ACMEChallengeSolver{
+		Selector: &cmacme.CertificateDNSNameSelector{
+			IdentifierType: cmacme.ACMEIdentifierTypeDNS,
+		},
+		DNS01: &cmacme.ACMEChallengeSolverDNS01{
+			Cloudflare: &cmacme.ACMEIssuerDNS01ProviderCloudflare{
+				Email: "dns-identifiers",
+			},
+		},
+	}
+	ipIdentifierTypeSelectorSolver := cmacme.ACMEChallengeSolver{
+		Selector: &cmacme.CertificateDNSNameSelector{
+			IdentifierType: cmacme.ACMEIdentifierTypeIP,
+		},
+		DNS01: &cmacme.ACMEChallengeSolverDNS01{
+			Cloudflare: &cmacme.ACMEIssuerDNS01ProviderCloudflare{
+				Email: "ip-identifiers",
+			},
+		},
+	}
 	exampleComDNSNameSelectorSolver := cmacme.ACMEChallengeSolver{
 		Selector: &cmacme.CertificateDNSNameSelector{
 			DNSNames: []string{"example.com"},
@@ -90,10 +148,11 @@ func TestChallengeSpecForAuthorization(t *testing.T) {
 	}
 
 	tests := map[string]struct {
-		acmeClient acmecl.Interface
-		issuer     v1.GenericIssuer
-		order      *cmacme.Order
-		authz      *cmacme.ACMEAuthorization
+		acmeClient      acmecl.Interface
+		issuer          v1.GenericIssuer
+		order           *cmacme.Order
+		namespaceLabels map[string]string
+		authz           *cmacme.ACMEAuthorization
 
 		expectedChallengeSpec *cmacme.ChallengeSpec
 		expectedError         bool
@@ -1256,11 +1315,471 @@ func TestChallengeSpecForAuthorization(t *testing.T) {
 				Solver:  exampleComDNSNameSelectorSolver,
 			},
 		},
+		"uses namespace selector solver when the namespace labels match": {
+			acmeClient: basicACMEClient,
+			issuer: &v1.Issuer{
+				Spec: v1.IssuerSpec{
+					IssuerConfig: v1.IssuerConfig{
+						ACME: &cmacme.ACMEIssuer{
+							Solvers: []cmacme.ACMEChallengeSolver{
+								emptySelectorSolverHTTP01,
+								tenantANamespaceSelectorSolver,
+							},
+						},
+					},
+				},
+			},
+			order: &cmacme.Order{
+				Spec: cmacme.OrderSpec{
+					DNSNames: []string{"example.com"},
+				},
+			},
+			namespaceLabels: map[string]string{"tenant": "a"},
+			authz: &cmacme.ACMEAuthorization{
+				Identifier: "example.com",
+				Challenges: []cmacme.ACMEChallenge{*acmeChallengeHTTP01},
+			},
+			expectedChallengeSpec: &cmacme.ChallengeSpec{
+				Type:    cmacme.ACMEChallengeTypeHTTP01,
+				DNSName: "example.com",
+				Token:   acmeChallengeHTTP01.Token,
+				Key:     "http01",
+				Solver:  tenantANamespaceSelectorSolver,
+			},
+		},
+		"does not use namespace selector solver when the namespace is unlabeled": {
+			acmeClient: basicACMEClient,
+			issuer: &v1.Issuer{
+				Spec: v1.IssuerSpec{
+					IssuerConfig: v1.IssuerConfig{
+						ACME: &cmacme.ACMEIssuer{
+							Solvers: []cmacme.ACMEChallengeSolver{
+								tenantANamespaceSelectorSolver,
+								emptySelectorSolverHTTP01,
+							},
+						},
+					},
+				},
+			},
+			order: &cmacme.Order{
+				Spec: cmacme.OrderSpec{
+					DNSNames: []string{"example.com"},
+				},
+			},
+			authz: &cmacme.ACMEAuthorization{
+				Identifier: "example.com",
+				Challenges: []cmacme.ACMEChallenge{*acmeChallengeHTTP01},
+			},
+			expectedChallengeSpec: &cmacme.ChallengeSpec{
+				Type:    cmacme.ACMEChallengeTypeHTTP01,
+				DNSName: "example.com",
+				Token:   acmeChallengeHTTP01.Token,
+				Key:     "http01",
+				Solver:  emptySelectorSolverHTTP01,
+			},
+		},
+		"does not use namespace selector solver when the namespace labels do not match": {
+			acmeClient: basicACMEClient,
+			issuer: &v1.Issuer{
+				Spec: v1.IssuerSpec{
+					IssuerConfig: v1.IssuerConfig{
+						ACME: &cmacme.ACMEIssuer{
+							Solvers: []cmacme.ACMEChallengeSolver{
+								tenantANamespaceSelectorSolver,
+							},
+						},
+					},
+				},
+			},
+			order: &cmacme.Order{
+				Spec: cmacme.OrderSpec{
+					DNSNames: []string{"example.com"},
+				},
+			},
+			namespaceLabels: map[string]string{"tenant": "b"},
+			authz: &cmacme.ACMEAuthorization{
+				Identifier: "example.com",
+				Challenges: []cmacme.ACMEChallenge{*acmeChallengeHTTP01},
+			},
+			expectedError: true,
+		},
+		"uses the annotation selector solver matching the order's annotations": {
+			acmeClient: basicACMEClient,
+			issuer: &v1.Issuer{
+				Spec: v1.IssuerSpec{
+					IssuerConfig: v1.IssuerConfig{
+						ACME: &cmacme.ACMEIssuer{
+							Solvers: []cmacme.ACMEChallengeSolver{
+								emptySelectorSolverHTTP01,
+								dnsAnnotationSelectorSolver,
+								httpAnnotationSelectorSolver,
+							},
+						},
+					},
+				},
+			},
+			order: &cmacme.Order{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						"example.com/solver": "http",
+					},
+				},
+				Spec: cmacme.OrderSpec{
+					DNSNames: []string{"example.com"},
+				},
+			},
+			authz: &cmacme.ACMEAuthorization{
+				Identifier: "example.com",
+				Challenges: []cmacme.ACMEChallenge{*acmeChallengeHTTP01, *acmeChallengeDNS01},
+			},
+			expectedChallengeSpec: &cmacme.ChallengeSpec{
+				Type:    cmacme.ACMEChallengeTypeHTTP01,
+				DNSName: "example.com",
+				Token:   acmeChallengeHTTP01.Token,
+				Key:     "http01",
+				Solver:  httpAnnotationSelectorSolver,
+			},
+		},
+		"uses a different annotation selector solver when the order's annotation value differs": {
+			acmeClient: basicACMEClient,
+			issuer: &v1.Issuer{
+				Spec: v1.IssuerSpec{
+					IssuerConfig: v1.IssuerConfig{
+						ACME: &cmacme.ACMEIssuer{
+							Solvers: []cmacme.ACMEChallengeSolver{
+								emptySelectorSolverHTTP01,
+								dnsAnnotationSelectorSolver,
+								httpAnnotationSelectorSolver,
+							},
+						},
+					},
+				},
+			},
+			order: &cmacme.Order{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						"example.com/solver": "dns",
+					},
+				},
+				Spec: cmacme.OrderSpec{
+					DNSNames: []string{"example.com"},
+				},
+			},
+			authz: &cmacme.ACMEAuthorization{
+				Identifier: "example.com",
+				Challenges: []cmacme.ACMEChallenge{*acmeChallengeHTTP01, *acmeChallengeDNS01},
+			},
+			expectedChallengeSpec: &cmacme.ChallengeSpec{
+				Type:    cmacme.ACMEChallengeTypeDNS01,
+				DNSName: "example.com",
+				Token:   acmeChallengeDNS01.Token,
+				Key:     "dns01",
+				Solver:  dnsAnnotationSelectorSolver,
+			},
+		},
+		"does not use annotation selector solvers when the order is not annotated": {
+			acmeClient: basicACMEClient,
+			issuer: &v1.Issuer{
+				Spec: v1.IssuerSpec{
+					IssuerConfig: v1.IssuerConfig{
+						ACME: &cmacme.ACMEIssuer{
+							Solvers: []cmacme.ACMEChallengeSolver{
+								dnsAnnotationSelectorSolver,
+								httpAnnotationSelectorSolver,
+								emptySelectorSolverHTTP01,
+							},
+						},
+					},
+				},
+			},
+			order: &cmacme.Order{
+				Spec: cmacme.OrderSpec{
+					DNSNames: []string{"example.com"},
+				},
+			},
+			authz: &cmacme.ACMEAuthorization{
+				Identifier: "example.com",
+				Challenges: []cmacme.ACMEChallenge{*acmeChallengeHTTP01, *acmeChallengeDNS01},
+			},
+			expectedChallengeSpec: &cmacme.ChallengeSpec{
+				Type:    cmacme.ACMEChallengeTypeHTTP01,
+				DNSName: "example.com",
+				Token:   acmeChallengeHTTP01.Token,
+				Key:     "http01",
+				Solver:  emptySelectorSolverHTTP01,
+			},
+		},
+		"uses the identifierType selector solver for dns identifiers": {
+			acmeClient: basicACMEClient,
+			issuer: &v1.Issuer{
+				Spec: v1.IssuerSpec{
+					IssuerConfig: v1.IssuerConfig{
+						ACME: &cmacme.ACMEIssuer{
+							Solvers: []cmacme.ACMEChallengeSolver{
+								emptySelectorSolverDNS01,
+								ipIdentifierTypeSelectorSolver,
+								dnsIdentifierTypeSelectorSolver,
+							},
+						},
+					},
+				},
+			},
+			order: &cmacme.Order{
+				Spec: cmacme.OrderSpec{
+					DNSNames:    []string{"example.com"},
+					IPAddresses: []string{"10.0.0.1"},
+				},
+			},
+			authz: &cmacme.ACMEAuthorization{
+				Identifier: "example.com",
+				Challenges: []cmacme.ACMEChallenge{*acmeChallengeDNS01},
+			},
+			expectedChallengeSpec: &cmacme.ChallengeSpec{
+				Type:    cmacme.ACMEChallengeTypeDNS01,
+				DNSName: "example.com",
+				Token:   acmeChallengeDNS01.Token,
+				Key:     "dns01",
+				Solver:  dnsIdentifierTypeSelectorSolver,
+			},
+		},
+		"uses the identifierType selector solver for ip identifiers": {
+			acmeClient: basicACMEClient,
+			issuer: &v1.Issuer{
+				Spec: v1.IssuerSpec{
+					IssuerConfig: v1.IssuerConfig{
+						ACME: &cmacme.ACMEIssuer{
+							Solvers: []cmacme.ACMEChallengeSolver{
+								emptySelectorSolverDNS01,
+								dnsIdentifierTypeSelectorSolver,
+								ipIdentifierTypeSelectorSolver,
+							},
+						},
+					},
+				},
+			},
+			order: &cmacme.Order{
+				Spec: cmacme.OrderSpec{
+					DNSNames:    []string{"example.com"},
+					IPAddresses: []string{"10.0.0.1"},
+				},
+			},
+			authz: &cmacme.ACMEAuthorization{
+				Identifier: "10.0.0.1",
+				Challenges: []cmacme.ACMEChallenge{*acmeChallengeDNS01},
+			},
+			expectedChallengeSpec: &cmacme.ChallengeSpec{
+				Type:    cmacme.ACMEChallengeTypeDNS01,
+				DNSName: "10.0.0.1",
+				Token:   acmeChallengeDNS01.Token,
+				Key:     "dns01",
+				Solver:  ipIdentifierTypeSelectorSolver,
+			},
+		},
+		"does not use an identifierType selector solver for identifiers of another type": {
+			acmeClient: basicACMEClient,
+			issuer: &v1.Issuer{
+				Spec: v1.IssuerSpec{
+					IssuerConfig: v1.IssuerConfig{
+						ACME: &cmacme.ACMEIssuer{
+							Solvers: []cmacme.ACMEChallengeSolver{
+								ipIdentifierTypeSelectorSolver,
+								emptySelectorSolverDNS01,
+							},
+						},
+					},
+				},
+			},
+			order: &cmacme.Order{
+				Spec: cmacme.OrderSpec{
+					DNSNames: []string{"example.com"},
+				},
+			},
+			authz: &cmacme.ACMEAuthorization{
+				Identifier: "example.com",
+				Challenges: []cmacme.ACMEChallenge{*acmeChallengeDNS01},
+			},
+			expectedChallengeSpec: &cmacme.ChallengeSpec{
+				Type:    cmacme.ACMEChallengeTypeDNS01,
+				DNSName: "example.com",
+				Token:   acmeChallengeDNS01.Token,
+				Key:     "dns01",
+				Solver:  emptySelectorSolverDNS01,
+			},
+		},
+		"uses the first matching solver when both HTTP01 and DNS01 solvers match and no preference is set": {
+			acmeClient: basicACMEClient,
+			issuer: &v1.Issuer{
+				Spec: v1.IssuerSpec{
+					IssuerConfig: v1.IssuerConfig{
+						ACME: &cmacme.ACMEIssuer{
+							Solvers: []cmacme.ACMEChallengeSolver{
+								emptySelectorSolverHTTP01,
+								emptySelectorSolverDNS01,
+							},
+						},
+					},
+				},
+			},
+			order: &cmacme.Order{
+				Spec: cmacme.OrderSpec{
+					DNSNames: []string{"example.com"},
+				},
+			},
+			authz: &cmacme.ACMEAuthorization{
+				Identifier: "example.com",
+				Challenges: []cmacme.ACMEChallenge{*acmeChallengeHTTP01, *acmeChallengeDNS01},
+			},
+			expectedChallengeSpec: &cmacme.ChallengeSpec{
+				Type:    cmacme.ACMEChallengeTypeHTTP01,
+				DNSName: "example.com",
+				Token:   acmeChallengeHTTP01.Token,
+				Key:     "http01",
+				Solver:  emptySelectorSolverHTTP01,
+			},
+		},
+		"uses the preferred challenge type when both HTTP01 and DNS01 solvers match": {
+			acmeClient: basicACMEClient,
+			issuer: &v1.Issuer{
+				Spec: v1.IssuerSpec{
+					IssuerConfig: v1.IssuerConfig{
+						ACME: &cmacme.ACMEIssuer{
+							Solvers: []cmacme.ACMEChallengeSolver{
+								emptySelectorSolverHTTP01,
+								emptySelectorSolverDNS01,
+							},
+						},
+					},
+				},
+			},
+			order: &cmacme.Order{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						cmacme.ACMEChallengeTypePreferenceAnnotationKey: "dns-01,http-01",
+					},
+				},
+				Spec: cmacme.OrderSpec{
+					DNSNames: []string{"example.com"},
+				},
+			},
+			authz: &cmacme.ACMEAuthorization{
+				Identifier: "example.com",
+				Challenges: []cmacme.ACMEChallenge{*acmeChallengeHTTP01, *acmeChallengeDNS01},
+			},
+			expectedChallengeSpec: &cmacme.ChallengeSpec{
+				Type:    cmacme.ACMEChallengeTypeDNS01,
+				DNSName: "example.com",
+				Token:   acmeChallengeDNS01.Token,
+				Key:     "dns01",
+				Solver:  emptySelectorSolverDNS01,
+			},
+		},
+		"uses the preferred challenge type even if a solver of another type has a more specific selector": {
+			acmeClient: basicACMEClient,
+			issuer: &v1.Issuer{
+				Spec: v1.IssuerSpec{
+					IssuerConfig: v1.IssuerConfig{
+						ACME: &cmacme.ACMEIssuer{
+							Solvers: []cmacme.ACMEChallengeSolver{
+								exampleComDNSNameSelectorSolver,
+								emptySelectorSolverDNS01,
+							},
+						},
+					},
+				},
+			},
+			order: &cmacme.Order{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						cmacme.ACMEChallengeTypePreferenceAnnotationKey: "dns-01",
+					},
+				},
+				Spec: cmacme.OrderSpec{
+					DNSNames: []string{"example.com"},
+				},
+			},
+			authz: &cmacme.ACMEAuthorization{
+				Identifier: "example.com",
+				Challenges: []cmacme.ACMEChallenge{*acmeChallengeHTTP01, *acmeChallengeDNS01},
+			},
+			expectedChallengeSpec: &cmacme.ChallengeSpec{
+				Type:    cmacme.ACMEChallengeTypeDNS01,
+				DNSName: "example.com",
+				Token:   acmeChallengeDNS01.Token,
+				Key:     "dns01",
+				Solver:  emptySelectorSolverDNS01,
+			},
+		},
+		"falls back to other challenge types if no solver of the preferred type matches": {
+			acmeClient: basicACMEClient,
+			issuer: &v1.Issuer{
+				Spec: v1.IssuerSpec{
+					IssuerConfig: v1.IssuerConfig{
+						ACME: &cmacme.ACMEIssuer{
+							Solvers: []cmacme.ACMEChallengeSolver{
+								dnsAnnotationSelectorSolver,
+								emptySelectorSolverHTTP01,
+							},
+						},
+					},
+				},
+			},
+			order: &cmacme.Order{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						cmacme.ACMEChallengeTypePreferenceAnnotationKey: "dns-01",
+					},
+				},
+				Spec: cmacme.OrderSpec{
+					DNSNames: []string{"example.com"},
+				},
+			},
+			authz: &cmacme.ACMEAuthorization{
+				Identifier: "example.com",
+				Challenges: []cmacme.ACMEChallenge{*acmeChallengeHTTP01, *acmeChallengeDNS01},
+			},
+			expectedChallengeSpec: &cmacme.ChallengeSpec{
+				Type:    cmacme.ACMEChallengeTypeHTTP01,
+				DNSName: "example.com",
+				Token:   acmeChallengeHTTP01.Token,
+				Key:     "http01",
+				Solver:  emptySelectorSolverHTTP01,
+			},
+		},
+		"returns an error if the challenge type preference is invalid": {
+			acmeClient: basicACMEClient,
+			issuer: &v1.Issuer{
+				Spec: v1.IssuerSpec{
+					IssuerConfig: v1.IssuerConfig{
+						ACME: &cmacme.ACMEIssuer{
+							Solvers: []cmacme.ACMEChallengeSolver{
+								emptySelectorSolverHTTP01,
+								emptySelectorSolverDNS01,
+							},
+						},
+					},
+				},
+			},
+			order: &cmacme.Order{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						cmacme.ACMEChallengeTypePreferenceAnnotationKey: "tls-alpn-01",
+					},
+				},
+				Spec: cmacme.OrderSpec{
+					DNSNames: []string{"example.com"},
+				},
+			},
+			authz: &cmacme.ACMEAuthorization{
+				Identifier: "example.com",
+				Challenges: []cmacme.ACMEChallenge{*acmeChallengeHTTP01, *acmeChallengeDNS01},
+			},
+			expectedError: true,
+		},
 	}
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
 			ctx := context.Background()
-			cs, err := challengeSpecForAuthorization(ctx, test.acmeClient, test.issuer, test.order, *test.authz)
+			cs, err := challengeSpecForAuthorization(ctx, test.acmeClient, test.issuer, test.order, test.namespaceLabels, *test.authz)
 			if err != nil && !test.expectedError {
 				t.Errorf("expected to not get an error, but got: %v", err)
 				t.Fail()
@@ -1274,3 +1793,42 @@ func TestChallengeSpecForAuthorization(t *testing.T) {
 		})
 	}
 }
+
+func TestDNSNamesNotAllowed(t *testing.T) {
+	tests := map[string]struct {
+		allowedDomains []string
+		dnsNames       []string
+		expected       []string
+	}{
+		"no allowedDomains allows all DNS names": {
+			dnsNames: []string{"example.com", "www.example.org"},
+		},
+		"exact matches are allowed": {
+			allowedDomains: []string{"example.com"},
+			dnsNames:       []string{"example.com"},
+		},
+		"wildcard patterns match subdomains": {
+			allowedDomains: []string{"*.example.com"},
+			dnsNames:       []string{"www.example.com", "a.b.example.com"},
+		},
+		"wildcard patterns do not match the apex domain": {
+			allowedDomains: []string{"*.example.com"},
+			dnsNames:       []string{"example.com", "www.example.com"},
+			expected:       []string{"example.com"},
+		},
+		"DNS names outside the allowedDomains are returned": {
+			allowedDomains: []string{"example.com", "*.example.com"},
+			dnsNames:       []string{"example.com", "example.org", "www.example.net"},
+			expected:       []string{"example.org", "www.example.net"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			notAllowed := dnsNamesNotAllowed(test.allowedDomains, test.dnsNames)
+			if !reflect.DeepEqual(notAllowed, test.expected) {
+				t.Errorf("expected %v but got %v", test.expected, notAllowed)
+			}
+		})
+	}
+}
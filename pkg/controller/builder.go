@@ -49,6 +49,10 @@ type Builder struct {
 	// runDurationFuncs are a list of functions that will be called every
 	// 'duration'
 	runDurationFuncs []runDurationFunc
+
+	// workers is the number of workers the controller should run with. If
+	// zero, the number of workers passed to Run is used.
+	workers int
 }
 
 // New creates a basic Builder, setting the sync call to the one given
@@ -85,6 +89,13 @@ func (b *Builder) First(function func(context.Context)) *Builder {
 	return b
 }
 
+// Workers sets the number of workers the controller will run with,
+// overriding the number of workers passed to Run.
+func (b *Builder) Workers(workers int) *Builder {
+	b.workers = workers
+	return b
+}
+
 func (b *Builder) Complete() (Interface, error) {
 	if b.context == nil {
 		return nil, fmt.Errorf("controller context must be non-nil")
@@ -97,5 +108,8 @@ func (b *Builder) Complete() (Interface, error) {
 		return nil, fmt.Errorf("error registering controller: %v", err)
 	}
 
-	return NewController(b.ctx, b.name, b.context.Metrics, b.impl.ProcessItem, mustSync, b.runDurationFuncs, queue), nil
+	ctrl := newController(b.ctx, b.name, b.context.Metrics, b.impl.ProcessItem, mustSync, b.runDurationFuncs, queue)
+	ctrl.workers = b.workers
+
+	return ctrl, nil
 }
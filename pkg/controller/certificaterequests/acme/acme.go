@@ -218,7 +218,7 @@ func buildOrder(cr *v1.CertificateRequest, csr *x509.CertificateRequest, enableD
 
 	spec := cmacme.OrderSpec{
 		Request:     cr.Spec.Request,
-		IssuerRef:   cr.Spec.IssuerRef,
+		IssuerRef:   apiutil.CertificateRequestIssuerRef(cr),
 		CommonName:  csr.Subject.CommonName,
 		DNSNames:    dnsNames,
 		IPAddresses: ipAddresses,
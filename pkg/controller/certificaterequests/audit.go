@@ -0,0 +1,141 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificaterequests
+
+import (
+	"context"
+	"crypto/x509"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager"
+	v1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+// AuditRecord describes a certificate that has been signed for a
+// CertificateRequest.
+type AuditRecord struct {
+	// Namespace and Name of the CertificateRequest that was signed.
+	Namespace string
+	Name      string
+
+	// Requester is the kind and name of the resource that created the
+	// CertificateRequest, taken from its controller owner reference, for
+	// example "Certificate/example". It is empty if the CertificateRequest
+	// has no controller.
+	Requester string
+
+	// IssuerName, IssuerKind and IssuerGroup reference the issuer that signed
+	// the CertificateRequest.
+	IssuerName  string
+	IssuerKind  string
+	IssuerGroup string
+
+	// The subject and subject alternative names of the signed certificate.
+	CommonName     string
+	DNSNames       []string
+	IPAddresses    []string
+	URIs           []string
+	EmailAddresses []string
+
+	SerialNumber string
+	NotBefore    time.Time
+	NotAfter     time.Time
+}
+
+// AuditHook is called with an AuditRecord each time a CertificateRequest has
+// been signed successfully.
+type AuditHook func(ctx context.Context, record AuditRecord)
+
+var (
+	auditHooks []AuditHook
+)
+
+// RegisterAuditHook registers a hook to be called by every certificate request
+// controller each time a CertificateRequest has been signed successfully, in
+// addition to the default hook that writes the record to the log.
+// Hooks must be registered before the controllers are started.
+func RegisterAuditHook(hook AuditHook) {
+	auditHooks = append(auditHooks, hook)
+}
+
+// newLogAuditHook returns an AuditHook that writes each record to the given
+// logger as a structured log line.
+func newLogAuditHook(log logr.Logger) AuditHook {
+	return func(_ context.Context, record AuditRecord) {
+		log.V(logf.InfoLevel).Info("certificate signed",
+			"audit", true,
+			"namespace", record.Namespace,
+			"name", record.Name,
+			"requester", record.Requester,
+			"issuer_name", record.IssuerName,
+			"issuer_kind", record.IssuerKind,
+			"issuer_group", record.IssuerGroup,
+			"common_name", record.CommonName,
+			"dns_names", record.DNSNames,
+			"ip_addresses", record.IPAddresses,
+			"uris", record.URIs,
+			"email_addresses", record.EmailAddresses,
+			"serial_number", record.SerialNumber,
+			"not_before", record.NotBefore,
+			"not_after", record.NotAfter,
+		)
+	}
+}
+
+// auditRecordForCertificateRequest builds the AuditRecord for the given
+// CertificateRequest that has been signed with the given certificate.
+func auditRecordForCertificateRequest(cr *v1.CertificateRequest, cert *x509.Certificate) AuditRecord {
+	// The request may have been signed by its fallback issuer.
+	issuerRef := apiutil.CertificateRequestIssuerRef(cr)
+	record := AuditRecord{
+		Namespace:      cr.Namespace,
+		Name:           cr.Name,
+		IssuerName:     issuerRef.Name,
+		IssuerKind:     apiutil.IssuerKind(issuerRef),
+		IssuerGroup:    issuerRef.Group,
+		CommonName:     cert.Subject.CommonName,
+		DNSNames:       cert.DNSNames,
+		IPAddresses:    pki.IPAddressesToString(cert.IPAddresses),
+		URIs:           pki.URLsToString(cert.URIs),
+		EmailAddresses: cert.EmailAddresses,
+		SerialNumber:   cert.SerialNumber.String(),
+		NotBefore:      cert.NotBefore,
+		NotAfter:       cert.NotAfter,
+	}
+	if record.IssuerGroup == "" {
+		record.IssuerGroup = certmanager.GroupName
+	}
+	if ref := metav1.GetControllerOf(cr); ref != nil {
+		record.Requester = ref.Kind + "/" + ref.Name
+	}
+	return record
+}
+
+// audit calls each of the controller's audit hooks with the record for the
+// given signed CertificateRequest.
+func (c *Controller) audit(ctx context.Context, cr *v1.CertificateRequest, cert *x509.Certificate) {
+	record := auditRecordForCertificateRequest(cr, cert)
+	for _, hook := range c.auditHooks {
+		hook(ctx, record)
+	}
+}
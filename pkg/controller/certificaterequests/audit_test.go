@@ -0,0 +1,183 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificaterequests
+
+import (
+	"context"
+	"encoding/pem"
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	coretesting "k8s.io/client-go/testing"
+
+	"github.com/jetstack/cert-manager/pkg/api/util"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/jetstack/cert-manager/pkg/controller/certificaterequests/fake"
+	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/pkg/issuer"
+	_ "github.com/jetstack/cert-manager/pkg/issuer/selfsigned"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestSyncAuditHook(t *testing.T) {
+	nowMetaTime := metav1.NewTime(fixedClockStart)
+
+	sk, err := pki.GenerateRSAPrivateKey(2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	csr, err := pki.GenerateCSR(gen.Certificate("test-cert",
+		gen.SetCertificateCommonName("example.com"),
+		gen.SetCertificateDNSNames("example.com", "www.example.com"),
+		gen.SetCertificateIPs("10.0.0.1"),
+		gen.SetCertificateURIs("spiffe://example.com/test"),
+		gen.SetCertificateEmails("test@example.com"),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	csrDER, err := pki.EncodeCSR(csr, sk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	csrPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER})
+
+	baseIssuer := gen.Issuer("test-issuer",
+		gen.SetIssuerSelfSigned(cmapi.SelfSignedIssuer{}),
+		gen.AddIssuerCondition(cmapi.IssuerCondition{
+			Type:   cmapi.IssuerConditionReady,
+			Status: cmmeta.ConditionTrue,
+		}),
+	)
+	baseCR := gen.CertificateRequest("test-cr",
+		gen.SetCertificateRequestCSR(csrPEM),
+		gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+			Kind: baseIssuer.Kind,
+			Name: baseIssuer.Name,
+		}),
+		gen.AddCertificateRequestOwnerReferences(*metav1.NewControllerRef(
+			gen.Certificate("test-cert", gen.SetCertificateUID("test-uid")),
+			cmapi.SchemeGroupVersion.WithKind(cmapi.CertificateKind),
+		)),
+	)
+
+	certPEM := generateSelfSignedCert(t, baseCR, sk, fixedClockStart, fixedClockStart.Add(time.Hour*12))
+	cert, err := pki.DecodeX509CertificateBytes(certPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		issuerImpl      Issuer
+		builder         *testpkg.Builder
+		expectedRecords []AuditRecord
+	}{
+		"an audit record is emitted when a certificate is signed": {
+			issuerImpl: &fake.Issuer{
+				FakeSign: func(context.Context, *cmapi.CertificateRequest, cmapi.GenericIssuer) (*issuer.IssueResponse, error) {
+					return &issuer.IssueResponse{Certificate: certPEM}, nil
+				},
+			},
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{baseIssuer, baseCR.DeepCopy()},
+				ExpectedEvents: []string{
+					"Normal CertificateIssued Certificate fetched from issuer successfully",
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificaterequests"),
+						"status",
+						gen.DefaultTestNamespace,
+						gen.CertificateRequestFrom(baseCR,
+							gen.SetCertificateRequestCertificate(certPEM),
+							gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+								Type:               cmapi.CertificateRequestConditionReady,
+								Status:             cmmeta.ConditionTrue,
+								Reason:             "Issued",
+								Message:            "Certificate fetched from issuer successfully",
+								LastTransitionTime: &nowMetaTime,
+							}),
+						),
+					)),
+				},
+			},
+			expectedRecords: []AuditRecord{
+				{
+					Namespace:      gen.DefaultTestNamespace,
+					Name:           "test-cr",
+					Requester:      "Certificate/test-cert",
+					IssuerName:     "test-issuer",
+					IssuerKind:     "Issuer",
+					IssuerGroup:    "cert-manager.io",
+					CommonName:     "example.com",
+					DNSNames:       []string{"example.com", "www.example.com"},
+					IPAddresses:    []string{"10.0.0.1"},
+					URIs:           []string{"spiffe://example.com/test"},
+					EmailAddresses: []string{"test@example.com"},
+					SerialNumber:   cert.SerialNumber.String(),
+					NotBefore:      cert.NotBefore,
+					NotAfter:       cert.NotAfter,
+				},
+			},
+		},
+		"no audit record is emitted if the issuer has not signed the request": {
+			issuerImpl: &fake.Issuer{
+				FakeSign: func(context.Context, *cmapi.CertificateRequest, cmapi.GenericIssuer) (*issuer.IssueResponse, error) {
+					return nil, nil
+				},
+			},
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{baseIssuer, baseCR.DeepCopy()},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			test.builder.T = t
+			test.builder.Clock = fixedClock
+			test.builder.Init()
+			defer test.builder.Stop()
+
+			c := New(util.IssuerSelfSigned, test.issuerImpl)
+			c.Register(test.builder.Context)
+
+			var records []AuditRecord
+			c.auditHooks = append(c.auditHooks, func(_ context.Context, record AuditRecord) {
+				records = append(records, record)
+			})
+
+			test.builder.Start()
+
+			err := c.Sync(context.Background(), baseCR.DeepCopy())
+			if err != nil {
+				t.Errorf("expected to not get an error, but got: %v", err)
+			}
+			test.builder.CheckAndFinish(err)
+
+			if !reflect.DeepEqual(records, test.expectedRecords) {
+				t.Errorf("unexpected audit records, exp=%+v got=%+v", test.expectedRecords, records)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awspca
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"math"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/acmpca"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	"github.com/jetstack/cert-manager/pkg/controller/certificaterequests"
+	crutil "github.com/jetstack/cert-manager/pkg/controller/certificaterequests/util"
+	issuerpkg "github.com/jetstack/cert-manager/pkg/issuer"
+	awspcaclient "github.com/jetstack/cert-manager/pkg/issuer/awspca"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+const (
+	CRControllerName = "certificaterequests-issuer-awspca"
+)
+
+type AWSPCA struct {
+	issuerOptions controllerpkg.IssuerOptions
+	secretsLister corelisters.SecretLister
+	reporter      *crutil.Reporter
+
+	clientBuilder awspcaclient.ClientBuilder
+}
+
+func init() {
+	// create certificate request controller for AWS PCA issuer
+	controllerpkg.Register(CRControllerName, func(ctx *controllerpkg.Context) (controllerpkg.Interface, error) {
+		return controllerpkg.NewBuilder(ctx, CRControllerName).
+			For(certificaterequests.New(apiutil.IssuerAWSPCA, NewAWSPCA(ctx))).
+			Complete()
+	})
+}
+
+func NewAWSPCA(ctx *controllerpkg.Context) *AWSPCA {
+	return &AWSPCA{
+		issuerOptions: ctx.IssuerOptions,
+		secretsLister: ctx.KubeSharedInformerFactory.Core().V1().Secrets().Lister(),
+		reporter:      crutil.NewReporter(ctx.Clock, ctx.Recorder),
+		clientBuilder: awspcaclient.NewClient,
+	}
+}
+
+// Sign requests a certificate from the AWS Private Certificate Authority
+// configured on the issuer. The ARN of the requested certificate is recorded
+// as an annotation on the CertificateRequest, and the certificate is retrieved
+// on a later sync once the certificate authority has issued it.
+func (a *AWSPCA) Sign(ctx context.Context, cr *cmapi.CertificateRequest, issuerObj cmapi.GenericIssuer) (*issuerpkg.IssueResponse, error) {
+	log := logf.FromContext(ctx, "sign")
+	log = logf.WithRelatedResource(log, issuerObj)
+
+	client, err := a.clientBuilder(a.issuerOptions.ResourceNamespace(issuerObj), a.secretsLister, issuerObj, a.issuerOptions.CanUseAmbientCredentials(issuerObj))
+	if k8sErrors.IsNotFound(err) {
+		message := "Required secret resource not found"
+
+		a.reporter.Pending(cr, err, "SecretMissing", message)
+		log.Error(err, message)
+
+		return nil, nil
+	}
+
+	if err != nil {
+		message := "Failed to initialise AWS PCA client for signing"
+
+		a.reporter.Pending(cr, err, "AWSPCAInitError", message)
+		log.Error(err, message)
+
+		return nil, err
+	}
+
+	caARN := issuerObj.GetSpec().AWSPCA.ARN
+	certificateARN := cr.ObjectMeta.Annotations[cmapi.AWSPCACertificateARNAnnotationKey]
+
+	// check if the certificate ARN annotation is there, if not request a
+	// certificate and record its ARN.
+	if certificateARN == "" {
+		input, err := issueCertificateInput(cr, caARN)
+		if err != nil {
+			message := "Failed to build AWS PCA certificate request"
+
+			a.reporter.Failed(cr, err, "RequestError", message)
+			log.Error(err, message)
+
+			return nil, nil
+		}
+
+		out, err := client.IssueCertificateWithContext(ctx, input)
+		if err != nil {
+			message := "Failed to request AWS PCA certificate"
+
+			a.reporter.Failed(cr, err, "RequestError", message)
+			log.Error(err, message)
+
+			return nil, err
+		}
+
+		a.reporter.Pending(cr, nil, "IssuancePending", "AWS PCA certificate is requested")
+
+		metav1.SetMetaDataAnnotation(&cr.ObjectMeta, cmapi.AWSPCACertificateARNAnnotationKey, aws.StringValue(out.CertificateArn))
+
+		return nil, nil
+	}
+
+	out, err := client.GetCertificateWithContext(ctx, &acmpca.GetCertificateInput{
+		CertificateAuthorityArn: aws.String(caARN),
+		CertificateArn:          aws.String(certificateARN),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == acmpca.ErrCodeRequestInProgressException {
+			message := "AWS PCA certificate still in a pending state, the request will be retried"
+
+			a.reporter.Pending(cr, err, "IssuancePending", message)
+			log.Error(err, message)
+			return nil, err
+		}
+
+		message := "Failed to obtain AWS PCA certificate"
+
+		a.reporter.Failed(cr, err, "RetrieveError", message)
+		log.Error(err, message)
+
+		return nil, err
+	}
+
+	log.V(logf.DebugLevel).Info("certificate issued")
+
+	chainPEM := []byte(aws.StringValue(out.CertificateChain))
+
+	// The chain is ordered from the issuing certificate authority to the
+	// root, so assume the last certificate is the root CA
+	var (
+		block, lastBlock *pem.Block
+		remainingBytes   = chainPEM
+	)
+	for {
+		block, remainingBytes = pem.Decode(remainingBytes)
+		if block == nil {
+			break
+		}
+		lastBlock = block
+	}
+
+	var caPEM []byte
+	if lastBlock != nil {
+		caPEM = pem.EncodeToMemory(lastBlock)
+	}
+
+	certPEM := []byte(aws.StringValue(out.Certificate))
+	if len(chainPEM) > 0 {
+		certPEM = append(append(certPEM, '\n'), chainPEM...)
+	}
+
+	return &issuerpkg.IssueResponse{
+		Certificate: certPEM,
+		CA:          caPEM,
+	}, nil
+}
+
+// issueCertificateInput builds the IssueCertificate request for the given
+// CertificateRequest. The signing algorithm is chosen to match the public key
+// of the CSR, and the validity is the requested duration rounded up to a
+// whole number of days.
+func issueCertificateInput(cr *cmapi.CertificateRequest, caARN string) (*acmpca.IssueCertificateInput, error) {
+	csr, err := pki.DecodeX509CertificateRequestBytes(cr.Spec.Request)
+	if err != nil {
+		return nil, err
+	}
+
+	var signingAlgorithm string
+	switch csr.PublicKeyAlgorithm {
+	case x509.RSA:
+		signingAlgorithm = acmpca.SigningAlgorithmSha256withrsa
+	case x509.ECDSA:
+		signingAlgorithm = acmpca.SigningAlgorithmSha256withecdsa
+	default:
+		return nil, fmt.Errorf("unsupported public key algorithm: %s", csr.PublicKeyAlgorithm)
+	}
+
+	duration := apiutil.DefaultCertDuration(cr.Spec.Duration)
+	days := int64(math.Ceil(duration.Hours() / 24))
+
+	return &acmpca.IssueCertificateInput{
+		CertificateAuthorityArn: aws.String(caARN),
+		Csr:                     cr.Spec.Request,
+		SigningAlgorithm:        aws.String(signingAlgorithm),
+		// the UID of the CertificateRequest ensures retried requests do not
+		// issue more than one certificate
+		IdempotencyToken: aws.String(string(cr.UID)),
+		Validity: &acmpca.Validity{
+			Type:  aws.String(acmpca.ValidityPeriodTypeDays),
+			Value: aws.Int64(days),
+		},
+	}, nil
+}
@@ -0,0 +1,272 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awspca
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/acmpca"
+	"github.com/aws/aws-sdk-go/service/acmpca/acmpcaiface"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	fakeclock "k8s.io/utils/clock/testing"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	crutil "github.com/jetstack/cert-manager/pkg/controller/certificaterequests/util"
+	controllertest "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/pkg/issuer/awspca/fake"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+const (
+	testCAARN          = "arn:aws:acm-pca:us-east-1:123456789012:certificate-authority/12345678-1234-1234-1234-123456789012"
+	testCertificateARN = testCAARN + "/certificate/0123456789abcdef0123456789abcdef"
+)
+
+var (
+	fixedClockStart = time.Now()
+	fixedClock      = fakeclock.NewFakeClock(fixedClockStart)
+)
+
+func generateCSR(t *testing.T, secretKey crypto.Signer) []byte {
+	template := x509.CertificateRequest{
+		Subject: pkix.Name{
+			CommonName: "test-common-name",
+		},
+		DNSNames: []string{
+			"foo.example.com", "bar.example.com",
+		},
+	}
+
+	csrBytes, err := x509.CreateCertificateRequest(rand.Reader, &template, secretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrBytes})
+}
+
+func pemBlock(contents string) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte(contents)}))
+}
+
+func newTestAWSPCA(client acmpcaiface.ACMPCAAPI) *AWSPCA {
+	return &AWSPCA{
+		reporter: crutil.NewReporter(fixedClock, &controllertest.FakeRecorder{}),
+		clientBuilder: func(string, corelisters.SecretLister, cmapi.GenericIssuer, bool) (acmpcaiface.ACMPCAAPI, error) {
+			return client, nil
+		},
+	}
+}
+
+// Requests a certificate, polls for it while it is still being issued and
+// then retrieves it, calling Sign as the CertificateRequest controller would
+// on each sync.
+func TestSignIssuePollRetrieve(t *testing.T) {
+	rsaSK, err := pki.GenerateRSAPrivateKey(2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	csrPEM := generateCSR(t, rsaSK)
+
+	issuer := gen.Issuer("test-issuer",
+		gen.SetIssuerAWSPCA(cmapi.AWSPCAIssuer{
+			ARN: testCAARN,
+		}),
+	)
+	cr := gen.CertificateRequest("test-cr",
+		gen.SetCertificateRequestCSR(csrPEM),
+		// rounded up to 2 days
+		gen.SetCertificateRequestDuration(&metav1.Duration{Duration: 36 * time.Hour}),
+	)
+	cr.UID = types.UID("test-uid")
+
+	var (
+		issueCalls   int
+		getCalls     int
+		leafPEM      = pemBlock("leaf")
+		chainPEM     = pemBlock("intermediate") + pemBlock("root")
+		inProgress   = awserr.New(acmpca.ErrCodeRequestInProgressException, "the certificate is being issued", nil)
+		expectedCert = []byte(leafPEM + "\n" + chainPEM)
+	)
+
+	client := &fake.Client{
+		IssueCertificateFn: func(input *acmpca.IssueCertificateInput) (*acmpca.IssueCertificateOutput, error) {
+			issueCalls++
+			if got := aws.StringValue(input.CertificateAuthorityArn); got != testCAARN {
+				t.Errorf("unexpected certificate authority ARN, exp=%s got=%s", testCAARN, got)
+			}
+			if !bytes.Equal(input.Csr, csrPEM) {
+				t.Errorf("unexpected CSR, exp=%s got=%s", csrPEM, input.Csr)
+			}
+			if got := aws.StringValue(input.SigningAlgorithm); got != acmpca.SigningAlgorithmSha256withrsa {
+				t.Errorf("unexpected signing algorithm, exp=%s got=%s", acmpca.SigningAlgorithmSha256withrsa, got)
+			}
+			if got := aws.StringValue(input.IdempotencyToken); got != "test-uid" {
+				t.Errorf("unexpected idempotency token, exp=test-uid got=%s", got)
+			}
+			if typ, days := aws.StringValue(input.Validity.Type), aws.Int64Value(input.Validity.Value); typ != acmpca.ValidityPeriodTypeDays || days != 2 {
+				t.Errorf("unexpected validity, exp=%s/2 got=%s/%d", acmpca.ValidityPeriodTypeDays, typ, days)
+			}
+			return &acmpca.IssueCertificateOutput{
+				CertificateArn: aws.String(testCertificateARN),
+			}, nil
+		},
+		GetCertificateFn: func(input *acmpca.GetCertificateInput) (*acmpca.GetCertificateOutput, error) {
+			getCalls++
+			if got := aws.StringValue(input.CertificateArn); got != testCertificateARN {
+				t.Errorf("unexpected certificate ARN, exp=%s got=%s", testCertificateARN, got)
+			}
+			if getCalls == 1 {
+				return nil, inProgress
+			}
+			return &acmpca.GetCertificateOutput{
+				Certificate:      aws.String(leafPEM),
+				CertificateChain: aws.String(chainPEM),
+			}, nil
+		},
+	}
+	a := newTestAWSPCA(client)
+
+	// issue
+	resp, err := a.Sign(context.TODO(), cr, issuer)
+	if err != nil || resp != nil {
+		t.Fatalf("expected no response and no error when requesting the certificate, got resp=%v err=%v", resp, err)
+	}
+	if got := cr.Annotations[cmapi.AWSPCACertificateARNAnnotationKey]; got != testCertificateARN {
+		t.Fatalf("expected certificate ARN annotation %q, got %q", testCertificateARN, got)
+	}
+	if reason := apiutil.CertificateRequestReadyReason(cr); reason != cmapi.CertificateRequestReasonPending {
+		t.Errorf("expected Ready reason %q, got %q", cmapi.CertificateRequestReasonPending, reason)
+	}
+
+	// poll
+	resp, err = a.Sign(context.TODO(), cr, issuer)
+	if err != inProgress || resp != nil {
+		t.Fatalf("expected the in progress error and no response while the certificate is issued, got resp=%v err=%v", resp, err)
+	}
+	if reason := apiutil.CertificateRequestReadyReason(cr); reason != cmapi.CertificateRequestReasonPending {
+		t.Errorf("expected Ready reason %q, got %q", cmapi.CertificateRequestReasonPending, reason)
+	}
+
+	// retrieve
+	resp, err = a.Sign(context.TODO(), cr, issuer)
+	if err != nil {
+		t.Fatalf("unexpected error retrieving the certificate: %v", err)
+	}
+	if resp == nil {
+		t.Fatal("expected a response once the certificate is issued")
+	}
+	if !bytes.Equal(resp.Certificate, expectedCert) {
+		t.Errorf("unexpected certificate, exp=%s got=%s", expectedCert, resp.Certificate)
+	}
+	if !bytes.Equal(resp.CA, []byte(pemBlock("root"))) {
+		t.Errorf("unexpected CA, exp=%s got=%s", pemBlock("root"), resp.CA)
+	}
+
+	if issueCalls != 1 || getCalls != 2 {
+		t.Errorf("expected 1 IssueCertificate and 2 GetCertificate calls, got %d and %d", issueCalls, getCalls)
+	}
+}
+
+func TestSignFailures(t *testing.T) {
+	rsaSK, err := pki.GenerateRSAPrivateKey(2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	csrPEM := generateCSR(t, rsaSK)
+
+	issuer := gen.Issuer("test-issuer",
+		gen.SetIssuerAWSPCA(cmapi.AWSPCAIssuer{
+			ARN: testCAARN,
+		}),
+	)
+	baseCR := gen.CertificateRequest("test-cr",
+		gen.SetCertificateRequestCSR(csrPEM),
+	)
+
+	tests := map[string]struct {
+		cr     *cmapi.CertificateRequest
+		client *fake.Client
+
+		expectedErr    bool
+		expectedReason string
+	}{
+		"if the CSR cannot be decoded then fail without requesting a certificate": {
+			cr: gen.CertificateRequestFrom(baseCR,
+				gen.SetCertificateRequestCSR([]byte("not a csr")),
+			),
+			client:         &fake.Client{},
+			expectedReason: cmapi.CertificateRequestReasonFailed,
+		},
+		"if requesting the certificate fails then fail and return the error": {
+			cr: baseCR,
+			client: &fake.Client{
+				IssueCertificateFn: func(*acmpca.IssueCertificateInput) (*acmpca.IssueCertificateOutput, error) {
+					return nil, awserr.New(acmpca.ErrCodeInvalidStateException, "the certificate authority is disabled", nil)
+				},
+			},
+			expectedErr:    true,
+			expectedReason: cmapi.CertificateRequestReasonFailed,
+		},
+		"if retrieving the certificate fails then fail and return the error": {
+			cr: gen.CertificateRequestFrom(baseCR,
+				gen.SetCertificateRequestAnnotations(map[string]string{
+					cmapi.AWSPCACertificateARNAnnotationKey: testCertificateARN,
+				}),
+			),
+			client: &fake.Client{
+				GetCertificateFn: func(*acmpca.GetCertificateInput) (*acmpca.GetCertificateOutput, error) {
+					return nil, errors.New("this is a network error")
+				},
+			},
+			expectedErr:    true,
+			expectedReason: cmapi.CertificateRequestReasonFailed,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cr := test.cr.DeepCopy()
+
+			resp, err := newTestAWSPCA(test.client).Sign(context.TODO(), cr, issuer)
+			if resp != nil {
+				t.Errorf("expected no response, got %v", resp)
+			}
+			if (err != nil) != test.expectedErr {
+				t.Errorf("unexpected error, exp=%t got=%v", test.expectedErr, err)
+			}
+			if reason := apiutil.CertificateRequestReadyReason(cr); reason != test.expectedReason {
+				t.Errorf("unexpected Ready reason, exp=%s got=%s", test.expectedReason, reason)
+			}
+		})
+	}
+}
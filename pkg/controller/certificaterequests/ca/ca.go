@@ -19,6 +19,7 @@ package ca
 import (
 	"context"
 	"crypto/x509"
+	"encoding/hex"
 	"fmt"
 
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
@@ -26,6 +27,7 @@ import (
 
 	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
 	"github.com/jetstack/cert-manager/pkg/controller/certificaterequests"
 	crutil "github.com/jetstack/cert-manager/pkg/controller/certificaterequests/util"
@@ -50,6 +52,9 @@ type CA struct {
 
 	// Used for testing to get reproducible resulting certificates
 	templateGenerator templateGenerator
+	// Used to generate templates for CertificateRequests that request
+	// their CSR be signed verbatim
+	verbatimTemplateGenerator templateGenerator
 }
 
 func init() {
@@ -67,6 +72,8 @@ func NewCA(ctx *controllerpkg.Context) *CA {
 		secretsLister:     ctx.KubeSharedInformerFactory.Core().V1().Secrets().Lister(),
 		reporter:          crutil.NewReporter(ctx.Clock, ctx.Recorder),
 		templateGenerator: pki.GenerateTemplateFromCertificateRequest,
+
+		verbatimTemplateGenerator: pki.GenerateTemplateFromCertificateRequestVerbatim,
 	}
 }
 
@@ -106,7 +113,24 @@ func (c *CA) Sign(ctx context.Context, cr *cmapi.CertificateRequest, issuerObj c
 		return nil, err
 	}
 
-	template, err := c.templateGenerator(cr)
+	if preferredChain := issuerObj.GetSpec().CA.PreferredChain; preferredChain != "" {
+		caCerts = c.preferredCAChain(ctx, caCerts, resourceNamespace, secretName, preferredChain)
+	}
+
+	verbatim, err := crutil.SignCSRVerbatim(cr, c.issuerOptions.EnableVerbatimCSRSigning)
+	if err != nil {
+		message := "Refusing to sign CSR verbatim"
+		c.reporter.Failed(cr, err, "VerbatimSigningDisabled", message)
+		log.Error(err, message)
+		return nil, nil
+	}
+
+	generateTemplate := c.templateGenerator
+	if verbatim {
+		generateTemplate = c.verbatimTemplateGenerator
+	}
+
+	template, err := generateTemplate(cr)
 	if err != nil {
 		message := "Error generating certificate template"
 		c.reporter.Failed(cr, err, "SigningError", message)
@@ -116,8 +140,41 @@ func (c *CA) Sign(ctx context.Context, cr *cmapi.CertificateRequest, issuerObj c
 
 	template.CRLDistributionPoints = issuerObj.GetSpec().CA.CRLDistributionPoints
 	template.OCSPServer = issuerObj.GetSpec().CA.OCSPServers
+	template.IssuingCertificateURL = issuerObj.GetSpec().CA.IssuingCertificateURLs
+
+	if urls := issuerObj.GetSpec().CA.FreshestCRLDistributionPoints; len(urls) > 0 {
+		freshestCRL, err := pki.MarshalFreshestCRL(urls)
+		if err != nil {
+			message := "Failed to encode freshest CRL distribution points"
+			c.reporter.Failed(cr, err, "SigningError", message)
+			log.Error(err, message)
+			return nil, nil
+		}
+		template.ExtraExtensions = append(template.ExtraExtensions, freshestCRL)
+	}
+
+	if method := issuerObj.GetSpec().CA.SubjectKeyIdentifierMethod; method != "" {
+		template.SubjectKeyId, err = pki.SubjectKeyIdentifier(template.PublicKey, method)
+		if err != nil {
+			message := "Failed to derive subject key identifier"
+			c.reporter.Failed(cr, err, "SigningError", message)
+			log.Error(err, message)
+			return nil, nil
+		}
+	}
 
-	certPEM, caPEM, err := pki.SignCSRTemplate(caCerts, caKey, template)
+	signingCerts := caCerts
+	if aki := issuerObj.GetSpec().CA.AuthorityKeyIdentifier; aki != "" {
+		signingCerts, err = withAuthorityKeyIdentifier(template, caCerts, aki)
+		if err != nil {
+			message := "Failed to set authority key identifier"
+			c.reporter.Failed(cr, err, "SigningError", message)
+			log.Error(err, message)
+			return nil, nil
+		}
+	}
+
+	certPEM, caPEM, err := pki.SignCSRTemplate(signingCerts, caKey, template)
 	if err != nil {
 		message := "Error signing certificate"
 		c.reporter.Failed(cr, err, "SigningError", message)
@@ -132,3 +189,56 @@ func (c *CA) Sign(ctx context.Context, cr *cmapi.CertificateRequest, issuerObj c
 		CA:          caPEM,
 	}, nil
 }
+
+// withAuthorityKeyIdentifier sets the given hex encoded authority key
+// identifier on the template. The x509 package always derives the authority
+// key identifier from the signing certificate's subject key identifier when
+// it is present, so the returned chain has a copy of the signing CA
+// certificate with its subject key identifier cleared.
+func withAuthorityKeyIdentifier(template *x509.Certificate, caCerts []*x509.Certificate, aki string) ([]*x509.Certificate, error) {
+	keyID, err := hex.DecodeString(aki)
+	if err != nil {
+		return nil, fmt.Errorf("invalid authority key identifier %q: %v", aki, err)
+	}
+
+	template.AuthorityKeyId = keyID
+
+	signingCA := *caCerts[0]
+	signingCA.SubjectKeyId = nil
+
+	return append([]*x509.Certificate{&signingCA}, caCerts[1:]...), nil
+}
+
+// preferredCAChain returns the chain of the signing CA certificate that is
+// anchored at the root with the given common name. Candidate issuing
+// certificates are read from the certificates following the signing CA in the
+// CA Secret's tls.crt, as well as from its ca.crt. If no chain anchored at the
+// preferred root can be built, the chain stored in tls.crt is returned.
+func (c *CA) preferredCAChain(ctx context.Context, caCerts []*x509.Certificate, namespace, name, preferredChain string) []*x509.Certificate {
+	log := logf.FromContext(ctx, "preferred_chain")
+
+	candidates := append([]*x509.Certificate{}, caCerts[1:]...)
+
+	secret, err := c.secretsLister.Secrets(namespace).Get(name)
+	if err != nil {
+		log.Error(err, "failed to get CA secret, using default chain")
+		return caCerts
+	}
+
+	if caBytes := secret.Data[cmmeta.TLSCAKey]; len(caBytes) > 0 {
+		roots, err := pki.DecodeX509CertificateChainBytes(caBytes)
+		if err != nil {
+			log.Error(err, "failed to decode CA secret ca.crt, using default chain")
+			return caCerts
+		}
+		candidates = append(candidates, roots...)
+	}
+
+	chain, ok := pki.PreferredCertificateChain(caCerts[0], candidates, preferredChain)
+	if !ok {
+		log.V(logf.DebugLevel).Info("no chain anchored at the preferred root, using default chain", "preferred_chain", preferredChain)
+		return caCerts
+	}
+
+	return chain
+}
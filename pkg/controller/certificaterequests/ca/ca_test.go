@@ -22,9 +22,12 @@ import (
 	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/asn1"
+	"encoding/hex"
 	"encoding/pem"
 	"errors"
 	"math/big"
@@ -405,6 +408,15 @@ func TestCA_Sign(t *testing.T) {
 	require.NoError(t, err)
 	rsaCSR := generateCSR(t, rsaPair)
 
+	serialNumberCSRTemplate, err := pki.GenerateCSR(&cmapi.Certificate{Spec: cmapi.CertificateSpec{
+		CommonName: "device",
+		Subject:    &cmapi.X509Subject{SerialNumber: "DEVICE-0001"},
+	}})
+	require.NoError(t, err)
+	serialNumberCSRDER, err := pki.EncodeCSR(serialNumberCSRTemplate, rsaPair)
+	require.NoError(t, err)
+	serialNumberCSR := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: serialNumberCSRDER})
+
 	tests := map[string]struct {
 		givenCASecret    *corev1.Secret
 		givenCAIssuer    cmapi.GenericIssuer
@@ -440,6 +452,36 @@ func TestCA_Sign(t *testing.T) {
 				assert.Equalf(t, expectNotAfter, got.NotAfter, "time mismatch, expect='%s', got='%s'", expectNotAfter.String(), got.NotAfter.String())
 			},
 		},
+		"when the CertificateRequest has a subject serialNumber, it should appear in the subject of the signed certificate": {
+			givenCAIssuer: gen.Issuer("issuer-1", gen.SetIssuerCA(cmapi.CAIssuer{
+				SecretName: "secret-1",
+			})),
+			givenCR: gen.CertificateRequest("cr-1",
+				gen.SetCertificateRequestCSR(serialNumberCSR),
+				gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+					Name:  "issuer-1",
+					Group: certmanager.GroupName,
+					Kind:  "Issuer",
+				}),
+			),
+			givenCASecret: gen.SecretFrom(gen.Secret("secret-1"), gen.SetSecretNamespace("default"), gen.SetSecretData(secretDataFor(t, rsaPair,
+				&x509.Certificate{
+					SerialNumber: big.NewInt(1234),
+					IsCA:         true,
+				},
+			))),
+			assertSignedCert: func(t *testing.T, got *x509.Certificate) {
+				// id-at-serialNumber
+				oidSerialNumber := asn1.ObjectIdentifier{2, 5, 4, 5}
+				var serialNumbers []interface{}
+				for _, attr := range got.Subject.Names {
+					if attr.Type.Equal(oidSerialNumber) {
+						serialNumbers = append(serialNumbers, attr.Value)
+					}
+				}
+				assert.Equal(t, []interface{}{"DEVICE-0001"}, serialNumbers)
+			},
+		},
 		"when the CertificateRequest has the isCA field set, it should appear on the signed ca": {
 			givenCAIssuer: gen.Issuer("issuer-1", gen.SetIssuerCA(cmapi.CAIssuer{
 				SecretName: "secret-1",
@@ -486,6 +528,109 @@ func TestCA_Sign(t *testing.T) {
 				assert.Equal(t, []string{"http://ocsp-v3.example.org"}, got.OCSPServer)
 			},
 		},
+		"when the Issuer has issuingCertificateURLs and ocspServers set, they should appear in the authority information access extension": {
+			givenCAIssuer: gen.Issuer("issuer-1", gen.SetIssuerCA(cmapi.CAIssuer{
+				SecretName:             "secret-1",
+				IssuingCertificateURLs: []string{"http://ca.example.com/ca.crt"},
+				OCSPServers:            []string{"http://ocsp.example.com"},
+			})),
+			givenCR: gen.CertificateRequest("cr-1",
+				gen.SetCertificateRequestCSR(rsaCSR),
+				gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+					Name:  "issuer-1",
+					Group: certmanager.GroupName,
+					Kind:  "Issuer",
+				}),
+			),
+			givenCASecret: gen.SecretFrom(gen.Secret("secret-1"), gen.SetSecretNamespace("default"), gen.SetSecretData(secretDataFor(t, rsaPair,
+				&x509.Certificate{
+					SerialNumber: big.NewInt(1234),
+					IsCA:         true,
+				},
+			))),
+			assertSignedCert: func(t *testing.T, got *x509.Certificate) {
+				assert.Equal(t, []string{"http://ca.example.com/ca.crt"}, got.IssuingCertificateURL)
+				assert.Equal(t, []string{"http://ocsp.example.com"}, got.OCSPServer)
+
+				// decode the extension itself to check that both access
+				// methods are present, as described in RFC 5280, 4.2.2.1
+				type accessDescription struct {
+					Method   asn1.ObjectIdentifier
+					Location asn1.RawValue
+				}
+				oidExtensionAuthorityInfoAccess := asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 1, 1}
+				oidAccessMethodOCSP := asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 48, 1}
+				oidAccessMethodCAIssuers := asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 48, 2}
+
+				locations := make(map[string][]string)
+				for _, ext := range got.Extensions {
+					if !ext.Id.Equal(oidExtensionAuthorityInfoAccess) {
+						continue
+					}
+					var descriptions []accessDescription
+					_, err := asn1.Unmarshal(ext.Value, &descriptions)
+					require.NoError(t, err)
+					for _, d := range descriptions {
+						locations[d.Method.String()] = append(locations[d.Method.String()], string(d.Location.Bytes))
+					}
+				}
+				assert.Equal(t, map[string][]string{
+					oidAccessMethodOCSP.String():      {"http://ocsp.example.com"},
+					oidAccessMethodCAIssuers.String(): {"http://ca.example.com/ca.crt"},
+				}, locations)
+			},
+		},
+		"when the Issuer has freshestCRLDistributionPoints set, they should appear in the freshest CRL extension": {
+			givenCAIssuer: gen.Issuer("issuer-1", gen.SetIssuerCA(cmapi.CAIssuer{
+				SecretName:                    "secret-1",
+				CRLDistributionPoints:         []string{"http://crl.example.com/ca.crl"},
+				FreshestCRLDistributionPoints: []string{"http://crl.example.com/delta.crl", "ldap://ldap.example.com/cn=delta"},
+			})),
+			givenCR: gen.CertificateRequest("cr-1",
+				gen.SetCertificateRequestCSR(rsaCSR),
+				gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+					Name:  "issuer-1",
+					Group: certmanager.GroupName,
+					Kind:  "Issuer",
+				}),
+			),
+			givenCASecret: gen.SecretFrom(gen.Secret("secret-1"), gen.SetSecretNamespace("default"), gen.SetSecretData(secretDataFor(t, rsaPair,
+				&x509.Certificate{
+					SerialNumber: big.NewInt(1234),
+					IsCA:         true,
+				},
+			))),
+			assertSignedCert: func(t *testing.T, got *x509.Certificate) {
+				assert.Equal(t, []string{"http://crl.example.com/ca.crl"}, got.CRLDistributionPoints)
+
+				// decode the extension itself, as the x509 package does not
+				// parse it, as described in RFC 5280, 4.2.1.15
+				type distributionPointName struct {
+					FullName []asn1.RawValue `asn1:"optional,tag:0"`
+				}
+				type distributionPoint struct {
+					DistributionPoint distributionPointName `asn1:"optional,tag:0"`
+				}
+				oidExtensionFreshestCRL := asn1.ObjectIdentifier{2, 5, 29, 46}
+
+				var urls []string
+				for _, ext := range got.Extensions {
+					if !ext.Id.Equal(oidExtensionFreshestCRL) {
+						continue
+					}
+					assert.False(t, ext.Critical)
+					var points []distributionPoint
+					_, err := asn1.Unmarshal(ext.Value, &points)
+					require.NoError(t, err)
+					for _, p := range points {
+						for _, name := range p.DistributionPoint.FullName {
+							urls = append(urls, string(name.Bytes))
+						}
+					}
+				}
+				assert.Equal(t, []string{"http://crl.example.com/delta.crl", "ldap://ldap.example.com/cn=delta"}, urls)
+			},
+		},
 		"when the Issuer has crlDistributionPoints set, it should appear on the signed ca ": {
 			givenCAIssuer: gen.Issuer("issuer-1", gen.SetIssuerCA(cmapi.CAIssuer{
 				SecretName:            "secret-1",
@@ -510,6 +655,142 @@ func TestCA_Sign(t *testing.T) {
 				assert.Equal(t, []string{"http://www.example.com/crl/test.crl"}, gotCA.CRLDistributionPoints)
 			},
 		},
+		"when the Issuer has multiple crlDistributionPoints set, they should all appear in a non-critical extension": {
+			givenCAIssuer: gen.Issuer("issuer-1", gen.SetIssuerCA(cmapi.CAIssuer{
+				SecretName: "secret-1",
+				CRLDistributionPoints: []string{
+					"http://www.example.com/crl/test.crl",
+					"ldap://ldap.example.com/cn=CA,dc=example,dc=com?certificateRevocationList",
+				},
+			})),
+			givenCR: gen.CertificateRequest("cr-1",
+				gen.SetCertificateRequestCSR(rsaCSR),
+				gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+					Name:  "issuer-1",
+					Group: certmanager.GroupName,
+					Kind:  "Issuer",
+				}),
+			),
+			givenCASecret: gen.SecretFrom(gen.Secret("secret-1"), gen.SetSecretNamespace("default"), gen.SetSecretData(secretDataFor(t, rsaPair,
+				&x509.Certificate{
+					SerialNumber: big.NewInt(1234),
+					IsCA:         true,
+				},
+			))),
+			assertSignedCert: func(t *testing.T, got *x509.Certificate) {
+				assert.Equal(t, []string{
+					"http://www.example.com/crl/test.crl",
+					"ldap://ldap.example.com/cn=CA,dc=example,dc=com?certificateRevocationList",
+				}, got.CRLDistributionPoints)
+
+				oidExtensionCRLDistributionPoints := asn1.ObjectIdentifier{2, 5, 29, 31}
+				var found bool
+				for _, ext := range got.Extensions {
+					if ext.Id.Equal(oidExtensionCRLDistributionPoints) {
+						found = true
+						assert.False(t, ext.Critical, "expected the CRL distribution points extension to be non-critical")
+					}
+				}
+				assert.True(t, found, "expected the CRL distribution points extension to be present")
+			},
+		},
+		"when the Issuer has authorityKeyIdentifier set, it should appear on the signed certificate": {
+			givenCAIssuer: gen.Issuer("issuer-1", gen.SetIssuerCA(cmapi.CAIssuer{
+				SecretName:             "secret-1",
+				AuthorityKeyIdentifier: "0123456789abcdef0123456789abcdef01234567",
+			})),
+			givenCR: gen.CertificateRequest("cr-1",
+				gen.SetCertificateRequestCSR(rsaCSR),
+				gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+					Name:  "issuer-1",
+					Group: certmanager.GroupName,
+					Kind:  "Issuer",
+				}),
+			),
+			givenCASecret: gen.SecretFrom(gen.Secret("secret-1"), gen.SetSecretNamespace("default"), gen.SetSecretData(secretDataFor(t, rsaPair,
+				&x509.Certificate{
+					SerialNumber: big.NewInt(1234),
+					IsCA:         true,
+					SubjectKeyId: []byte{1, 2, 3, 4},
+				},
+			))),
+			assertSignedCert: func(t *testing.T, got *x509.Certificate) {
+				expected, err := hex.DecodeString("0123456789abcdef0123456789abcdef01234567")
+				require.NoError(t, err)
+				assert.Equal(t, expected, got.AuthorityKeyId)
+			},
+		},
+		"when the Issuer does not have authorityKeyIdentifier set, it should be derived from the signing ca": {
+			givenCAIssuer: gen.Issuer("issuer-1", gen.SetIssuerCA(cmapi.CAIssuer{
+				SecretName: "secret-1",
+			})),
+			givenCR: gen.CertificateRequest("cr-1",
+				gen.SetCertificateRequestCSR(rsaCSR),
+				gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+					Name:  "issuer-1",
+					Group: certmanager.GroupName,
+					Kind:  "Issuer",
+				}),
+			),
+			givenCASecret: gen.SecretFrom(gen.Secret("secret-1"), gen.SetSecretNamespace("default"), gen.SetSecretData(secretDataFor(t, rsaPair,
+				&x509.Certificate{
+					SerialNumber: big.NewInt(1234),
+					IsCA:         true,
+					SubjectKeyId: []byte{1, 2, 3, 4},
+				},
+			))),
+			assertSignedCert: func(t *testing.T, got *x509.Certificate) {
+				assert.Equal(t, []byte{1, 2, 3, 4}, got.AuthorityKeyId)
+			},
+		},
+		"when the Issuer has subjectKeyIdentifierMethod set to RFC7093Method1, the subject key identifier should be the truncated SHA-256 hash of the public key": {
+			givenCAIssuer: gen.Issuer("issuer-1", gen.SetIssuerCA(cmapi.CAIssuer{
+				SecretName:                 "secret-1",
+				SubjectKeyIdentifierMethod: cmapi.SubjectKeyIdentifierMethodRFC7093Method1,
+			})),
+			givenCR: gen.CertificateRequest("cr-1",
+				gen.SetCertificateRequestCSR(rsaCSR),
+				gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+					Name:  "issuer-1",
+					Group: certmanager.GroupName,
+					Kind:  "Issuer",
+				}),
+			),
+			givenCASecret: gen.SecretFrom(gen.Secret("secret-1"), gen.SetSecretNamespace("default"), gen.SetSecretData(secretDataFor(t, rsaPair,
+				&x509.Certificate{
+					SerialNumber: big.NewInt(1234),
+					IsCA:         true,
+				},
+			))),
+			assertSignedCert: func(t *testing.T, got *x509.Certificate) {
+				sum := sha256.Sum256(subjectPublicKey(t, got))
+				assert.Equal(t, sum[:20], got.SubjectKeyId)
+			},
+		},
+		"when the Issuer has subjectKeyIdentifierMethod set to RFC5280, the subject key identifier should be the SHA-1 hash of the public key": {
+			givenCAIssuer: gen.Issuer("issuer-1", gen.SetIssuerCA(cmapi.CAIssuer{
+				SecretName:                 "secret-1",
+				SubjectKeyIdentifierMethod: cmapi.SubjectKeyIdentifierMethodRFC5280,
+			})),
+			givenCR: gen.CertificateRequest("cr-1",
+				gen.SetCertificateRequestCSR(rsaCSR),
+				gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+					Name:  "issuer-1",
+					Group: certmanager.GroupName,
+					Kind:  "Issuer",
+				}),
+			),
+			givenCASecret: gen.SecretFrom(gen.Secret("secret-1"), gen.SetSecretNamespace("default"), gen.SetSecretData(secretDataFor(t, rsaPair,
+				&x509.Certificate{
+					SerialNumber: big.NewInt(1234),
+					IsCA:         true,
+				},
+			))),
+			assertSignedCert: func(t *testing.T, got *x509.Certificate) {
+				sum := sha1.Sum(subjectPublicKey(t, got))
+				assert.Equal(t, sum[:], got.SubjectKeyId)
+			},
+		},
 	}
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -544,6 +825,19 @@ func TestCA_Sign(t *testing.T) {
 	}
 }
 
+// subjectPublicKey returns the value of the subjectPublicKey BIT STRING of
+// the given certificate, which is the input to the subject key identifier
+// hash.
+func subjectPublicKey(t *testing.T, crt *x509.Certificate) []byte {
+	var spki struct {
+		Algorithm        pkix.AlgorithmIdentifier
+		SubjectPublicKey asn1.BitString
+	}
+	_, err := asn1.Unmarshal(crt.RawSubjectPublicKeyInfo, &spki)
+	require.NoError(t, err)
+	return spki.SubjectPublicKey.Bytes
+}
+
 // Returns a map that is meant to be used for creating a certificate Secret
 // that contains the fields "tls.crt" and "tls.key".
 func secretDataFor(t *testing.T, caKey *rsa.PrivateKey, caCrt *x509.Certificate) (secretData map[string][]byte) {
@@ -561,3 +855,317 @@ func secretDataFor(t *testing.T, caKey *rsa.PrivateKey, caCrt *x509.Certificate)
 		"tls.crt": caCrtPEM,
 	}
 }
+
+func TestCA_SignPreferredChain(t *testing.T) {
+	rsaPair, err := pki.GenerateRSAPrivateKey(2048)
+	require.NoError(t, err)
+	rsaCSR := generateCSR(t, rsaPair)
+
+	// The signing CA is issued by "Root X", which is both self-signed and
+	// cross-signed by "Root Y".
+	rootY, rootYKey := createCACert(t, "Root Y", nil, nil, nil)
+	rootX, rootXKey := createCACert(t, "Root X", nil, nil, nil)
+	rootXCrossSigned, _ := createCACert(t, "Root X", rootXKey, rootY, rootYKey)
+	signingCA, signingCAKey := createCACert(t, "Signing CA", nil, rootX, rootXKey)
+
+	signingCAKeyPEM, err := pki.EncodePKCS8PrivateKey(signingCAKey)
+	require.NoError(t, err)
+	tlsCrt, err := pki.EncodeX509Chain([]*x509.Certificate{signingCA, rootX})
+	require.NoError(t, err)
+	caCrt, err := pki.EncodeX509Chain([]*x509.Certificate{rootXCrossSigned, rootY})
+	require.NoError(t, err)
+
+	caSecret := gen.SecretFrom(gen.Secret("secret-1"), gen.SetSecretNamespace("default"), gen.SetSecretData(map[string][]byte{
+		corev1.TLSPrivateKeyKey: signingCAKeyPEM,
+		corev1.TLSCertKey:       tlsCrt,
+		cmmeta.TLSCAKey:         caCrt,
+	}))
+
+	tests := map[string]struct {
+		preferredChain string
+		// expectedIssuers is the issuer common name of each certificate in
+		// the returned chain, starting at the leaf
+		expectedIssuers []string
+	}{
+		"no preferred chain uses the chain in tls.crt": {
+			expectedIssuers: []string{"Signing CA", "Root X"},
+		},
+		"preferring the self-signed root uses the chain anchored at it": {
+			preferredChain:  "Root X",
+			expectedIssuers: []string{"Signing CA", "Root X"},
+		},
+		"preferring the cross-signing root uses the cross-signed chain": {
+			preferredChain:  "Root Y",
+			expectedIssuers: []string{"Signing CA", "Root X", "Root Y"},
+		},
+		"preferring an unknown root uses the chain in tls.crt": {
+			preferredChain:  "Root Z",
+			expectedIssuers: []string{"Signing CA", "Root X"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &CA{
+				reporter: util.NewReporter(fixedClock, &controllertest.FakeRecorder{}),
+				secretsLister: listers.FakeSecretListerFrom(listers.NewFakeSecretLister(),
+					listers.SetFakeSecretNamespaceListerGet(caSecret, nil),
+				),
+				templateGenerator: pki.GenerateTemplateFromCertificateRequest,
+			}
+
+			issuer := gen.Issuer("issuer-1", gen.SetIssuerCA(cmapi.CAIssuer{
+				SecretName:     "secret-1",
+				PreferredChain: test.preferredChain,
+			}))
+			cr := gen.CertificateRequest("cr-1",
+				gen.SetCertificateRequestCSR(rsaCSR),
+				gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+					Name:  "issuer-1",
+					Group: certmanager.GroupName,
+					Kind:  "Issuer",
+				}),
+			)
+
+			resp, err := c.Sign(context.Background(), cr, issuer)
+			require.NoError(t, err)
+			require.NotNil(t, resp)
+
+			chain, err := pki.DecodeX509CertificateChainBytes(resp.Certificate)
+			require.NoError(t, err)
+
+			var issuers []string
+			for _, crt := range chain {
+				issuers = append(issuers, crt.Issuer.CommonName)
+			}
+			assert.Equal(t, test.expectedIssuers, issuers)
+		})
+	}
+}
+
+// createCACert returns a CA certificate with the given common name, issued by
+// parent. If key is nil, a new key is generated. If parent is nil, the
+// certificate is self-signed.
+func createCACert(t *testing.T, cn string, key crypto.Signer, parent *x509.Certificate, parentKey crypto.Signer) (*x509.Certificate, crypto.Signer) {
+	if key == nil {
+		var err error
+		key, err = pki.GenerateECPrivateKey(256)
+		require.NoError(t, err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+
+	_, cert, err := pki.SignCertificate(template, parent, key.Public(), parentKey)
+	require.NoError(t, err)
+
+	return cert, key
+}
+
+func TestCA_SignVerbatim(t *testing.T) {
+	rsaPair, err := pki.GenerateRSAPrivateKey(2048)
+	require.NoError(t, err)
+
+	// Build a CSR outside of cert-manager that requests key usages and a
+	// subject that differ from those set on the CertificateRequest.
+	csrTemplate, err := pki.GenerateCSR(gen.Certificate("external",
+		gen.SetCertificateCommonName("external.example.com"),
+		gen.SetCertificateOrganization("External Org"),
+		gen.SetCertificateKeyUsages(cmapi.UsageDigitalSignature, cmapi.UsageContentCommittment, cmapi.UsageCodeSigning),
+	))
+	require.NoError(t, err)
+	externalCSR, err := pki.EncodeCSR(csrTemplate, rsaPair)
+	require.NoError(t, err)
+	externalCSRPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: externalCSR})
+
+	caSecret := gen.SecretFrom(gen.Secret("secret-1"), gen.SetSecretNamespace("default"), gen.SetSecretData(secretDataFor(t, rsaPair,
+		&x509.Certificate{
+			SerialNumber: big.NewInt(1234),
+			IsCA:         true,
+		},
+	)))
+	issuer := gen.Issuer("issuer-1", gen.SetIssuerCA(cmapi.CAIssuer{
+		SecretName: "secret-1",
+	}))
+	baseCR := gen.CertificateRequest("cr-1",
+		gen.SetCertificateRequestCSR(externalCSRPEM),
+		gen.SetCertificateRequestKeyUsages(cmapi.UsageServerAuth),
+		gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+			Name:  "issuer-1",
+			Group: certmanager.GroupName,
+			Kind:  "Issuer",
+		}),
+	)
+	verbatimCR := gen.CertificateRequestFrom(baseCR,
+		gen.AddCertificateRequestAnnotations(map[string]string{
+			cmapi.CertificateRequestSignCSRVerbatimAnnotationKey: "true",
+		}),
+	)
+
+	tests := map[string]struct {
+		givenCR         *cmapi.CertificateRequest
+		enableVerbatim  bool
+		expectKeyUsage  x509.KeyUsage
+		expectExtUsages []x509.ExtKeyUsage
+		expectFailed    bool
+	}{
+		"when the annotation is not set, the usages on the spec should be used": {
+			givenCR:         baseCR,
+			enableVerbatim:  true,
+			expectExtUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		},
+		"when the annotation is set and verbatim signing is enabled, the usages in the CSR should be used": {
+			givenCR:         verbatimCR,
+			enableVerbatim:  true,
+			expectKeyUsage:  x509.KeyUsageDigitalSignature | x509.KeyUsageContentCommitment,
+			expectExtUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		},
+		"when the annotation is set but verbatim signing is disabled, the request should fail": {
+			givenCR:        verbatimCR,
+			enableVerbatim: false,
+			expectFailed:   true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &CA{
+				issuerOptions: controller.IssuerOptions{
+					EnableVerbatimCSRSigning: test.enableVerbatim,
+				},
+				reporter: util.NewReporter(fixedClock, &controllertest.FakeRecorder{}),
+				secretsLister: listers.FakeSecretListerFrom(listers.NewFakeSecretLister(),
+					listers.SetFakeSecretNamespaceListerGet(caSecret, nil),
+				),
+				templateGenerator:         pki.GenerateTemplateFromCertificateRequest,
+				verbatimTemplateGenerator: pki.GenerateTemplateFromCertificateRequestVerbatim,
+			}
+
+			cr := test.givenCR.DeepCopy()
+			resp, err := c.Sign(context.Background(), cr, issuer)
+			require.NoError(t, err)
+
+			if test.expectFailed {
+				assert.Nil(t, resp)
+				cond := apiutil.GetCertificateRequestCondition(cr, cmapi.CertificateRequestConditionReady)
+				require.NotNil(t, cond)
+				assert.Equal(t, cmapi.CertificateRequestReasonFailed, cond.Reason)
+				return
+			}
+
+			require.NotNil(t, resp)
+			got, err := pki.DecodeX509CertificateBytes(resp.Certificate)
+			require.NoError(t, err)
+
+			assert.Equal(t, "external.example.com", got.Subject.CommonName)
+			assert.Equal(t, []string{"External Org"}, got.Subject.Organization)
+			assert.Equal(t, test.expectKeyUsage, got.KeyUsage)
+			assert.Equal(t, test.expectExtUsages, got.ExtKeyUsage)
+		})
+	}
+}
+
+func TestCA_SignMaxPathLen(t *testing.T) {
+	rsaPair, err := pki.GenerateRSAPrivateKey(2048)
+	require.NoError(t, err)
+
+	caSecret := gen.SecretFrom(gen.Secret("secret-1"), gen.SetSecretNamespace("default"), gen.SetSecretData(secretDataFor(t, rsaPair,
+		&x509.Certificate{
+			SerialNumber: big.NewInt(1234),
+			IsCA:         true,
+		},
+	)))
+	issuer := gen.Issuer("issuer-1", gen.SetIssuerCA(cmapi.CAIssuer{
+		SecretName: "secret-1",
+	}))
+
+	// requestFor returns a CertificateRequest for the given Certificate, as
+	// it would be created by the certificates controllers.
+	requestFor := func(t *testing.T, crt *cmapi.Certificate) *cmapi.CertificateRequest {
+		csrTemplate, err := pki.GenerateCSR(crt)
+		require.NoError(t, err)
+		csr, err := pki.EncodeCSR(csrTemplate, rsaPair)
+		require.NoError(t, err)
+		return gen.CertificateRequest("cr-1",
+			gen.SetCertificateRequestCSR(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csr})),
+			gen.SetCertificateRequestIsCA(crt.Spec.IsCA),
+			gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+				Name:  "issuer-1",
+				Group: certmanager.GroupName,
+				Kind:  "Issuer",
+			}),
+		)
+	}
+	subCA := func(mods ...gen.CertificateModifier) *cmapi.Certificate {
+		return gen.Certificate("sub-ca", append([]gen.CertificateModifier{
+			gen.SetCertificateCommonName("sub-ca"),
+			gen.SetCertificateIsCA(true),
+		}, mods...)...)
+	}
+
+	tests := map[string]struct {
+		givenCertificate     *cmapi.Certificate
+		expectIsCA           bool
+		expectMaxPathLen     int
+		expectMaxPathLenZero bool
+	}{
+		"a CA without a path length constraint": {
+			givenCertificate: subCA(),
+			expectIsCA:       true,
+			expectMaxPathLen: -1,
+		},
+		"a CA with a path length of 2": {
+			givenCertificate: subCA(gen.SetCertificateMaxPathLen(2)),
+			expectIsCA:       true,
+			expectMaxPathLen: 2,
+		},
+		"a CA with a path length of 0": {
+			givenCertificate:     subCA(gen.SetCertificateMaxPathLenZero(true)),
+			expectIsCA:           true,
+			expectMaxPathLen:     0,
+			expectMaxPathLenZero: true,
+		},
+		"a path length is not requested for a leaf certificate": {
+			givenCertificate: gen.Certificate("leaf",
+				gen.SetCertificateCommonName("leaf"),
+				gen.SetCertificateMaxPathLen(2),
+			),
+			expectMaxPathLen: -1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &CA{
+				reporter: util.NewReporter(fixedClock, &controllertest.FakeRecorder{}),
+				secretsLister: listers.FakeSecretListerFrom(listers.NewFakeSecretLister(),
+					listers.SetFakeSecretNamespaceListerGet(caSecret, nil),
+				),
+				templateGenerator:         pki.GenerateTemplateFromCertificateRequest,
+				verbatimTemplateGenerator: pki.GenerateTemplateFromCertificateRequestVerbatim,
+			}
+
+			resp, err := c.Sign(context.Background(), requestFor(t, test.givenCertificate), issuer)
+			require.NoError(t, err)
+			require.NotNil(t, resp)
+
+			got, err := pki.DecodeX509CertificateBytes(resp.Certificate)
+			require.NoError(t, err)
+
+			assert.True(t, got.BasicConstraintsValid)
+			assert.Equal(t, test.expectIsCA, got.IsCA)
+			assert.Equal(t, test.expectMaxPathLen, got.MaxPathLen)
+			assert.Equal(t, test.expectMaxPathLenZero, got.MaxPathLenZero)
+		})
+	}
+}
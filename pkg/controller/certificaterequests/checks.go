@@ -21,6 +21,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/labels"
 
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
 )
@@ -62,7 +63,8 @@ func (c *Controller) certificatesRequestsForGenericIssuer(iss cmapi.GenericIssue
 
 	var affected []*cmapi.CertificateRequest
 	for _, crt := range crts {
-		if isClusterIssuer && crt.Spec.IssuerRef.Kind != cmapi.ClusterIssuerKind {
+		issuerRef := apiutil.CertificateRequestIssuerRef(crt)
+		if isClusterIssuer && issuerRef.Kind != cmapi.ClusterIssuerKind {
 			continue
 		}
 		if !isClusterIssuer {
@@ -70,7 +72,7 @@ func (c *Controller) certificatesRequestsForGenericIssuer(iss cmapi.GenericIssue
 				continue
 			}
 		}
-		if crt.Spec.IssuerRef.Name != iss.GetObjectMeta().Name {
+		if issuerRef.Name != iss.GetObjectMeta().Name {
 			continue
 		}
 		affected = append(affected, crt)
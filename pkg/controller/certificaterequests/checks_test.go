@@ -0,0 +1,106 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificaterequests
+
+import (
+	"sort"
+	"testing"
+
+	logtest "github.com/go-logr/logr/testing"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestHandleGenericIssuerReadyTransition(t *testing.T) {
+	ready := gen.AddIssuerCondition(cmapi.IssuerCondition{Type: cmapi.IssuerConditionReady, Status: cmmeta.ConditionTrue})
+	notReady := gen.AddIssuerCondition(cmapi.IssuerCondition{Type: cmapi.IssuerConditionReady, Status: cmmeta.ConditionFalse})
+
+	requests := []*cmapi.CertificateRequest{
+		gen.CertificateRequest("issuer-cr", gen.SetCertificateRequestNamespace("testns"),
+			gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{Name: "test-issuer", Kind: cmapi.IssuerKind})),
+		gen.CertificateRequest("other-issuer-cr", gen.SetCertificateRequestNamespace("testns"),
+			gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{Name: "other-issuer", Kind: cmapi.IssuerKind})),
+		gen.CertificateRequest("other-namespace-cr", gen.SetCertificateRequestNamespace("otherns"),
+			gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{Name: "test-issuer", Kind: cmapi.IssuerKind})),
+		gen.CertificateRequest("cluster-issuer-cr", gen.SetCertificateRequestNamespace("otherns"),
+			gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{Name: "test-issuer", Kind: cmapi.ClusterIssuerKind})),
+	}
+
+	tests := map[string]struct {
+		oldIssuer, newIssuer cmapi.GenericIssuer
+		expectedKeys         []string
+	}{
+		"should requeue CertificateRequests referencing an Issuer that becomes ready": {
+			oldIssuer:    gen.Issuer("test-issuer", gen.SetIssuerNamespace("testns"), notReady),
+			newIssuer:    gen.Issuer("test-issuer", gen.SetIssuerNamespace("testns"), ready),
+			expectedKeys: []string{"testns/issuer-cr"},
+		},
+		"should requeue CertificateRequests referencing a ClusterIssuer that becomes ready": {
+			oldIssuer:    gen.ClusterIssuer("test-issuer", notReady),
+			newIssuer:    gen.ClusterIssuer("test-issuer", ready),
+			expectedKeys: []string{"otherns/cluster-issuer-cr"},
+		},
+		"should not requeue CertificateRequests if the issuer is unchanged": {
+			oldIssuer: gen.Issuer("test-issuer", gen.SetIssuerNamespace("testns"), ready),
+			newIssuer: gen.Issuer("test-issuer", gen.SetIssuerNamespace("testns"), ready),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			for _, cr := range requests {
+				if err := indexer.Add(cr); err != nil {
+					t.Fatal(err)
+				}
+			}
+			queue := workqueue.NewRateLimitingQueue(controllerpkg.DefaultItemBasedRateLimiter())
+			defer queue.ShutDown()
+
+			c := &Controller{
+				certificateRequestLister: cmlisters.NewCertificateRequestLister(indexer),
+				queue:                    queue,
+				log:                      logtest.TestLogger{T: t},
+			}
+
+			// use the same event handler as is registered on the issuer informers
+			handler := &controllerpkg.BlockingEventHandler{WorkFunc: c.handleGenericIssuer}
+			handler.OnUpdate(test.oldIssuer, test.newIssuer)
+
+			var keys []string
+			for queue.Len() > 0 {
+				item, _ := queue.Get()
+				keys = append(keys, item.(string))
+				queue.Done(item)
+			}
+			sort.Strings(keys)
+			if len(keys) != len(test.expectedKeys) {
+				t.Fatalf("expected requeued keys %v, got %v", test.expectedKeys, keys)
+			}
+			for i := range keys {
+				if keys[i] != test.expectedKeys[i] {
+					t.Errorf("expected requeued keys %v, got %v", test.expectedKeys, keys)
+				}
+			}
+		})
+	}
+}
@@ -18,6 +18,7 @@ package certificaterequests
 
 import (
 	"context"
+	"time"
 
 	"github.com/go-logr/logr"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
@@ -37,6 +38,11 @@ import (
 
 const (
 	ControllerName = "certificaterequests"
+
+	// signLimitRequeueDelay is how long to wait before retrying a
+	// CertificateRequest whose issuer already has the maximum number of sign
+	// operations in flight.
+	signLimitRequeueDelay = time.Second * 5
 )
 
 var keyFunc = controllerpkg.KeyFunc
@@ -79,6 +85,18 @@ type Controller struct {
 	clock clock.Clock
 
 	reporter *util.Reporter
+
+	// signLimiter bounds the number of concurrent sign operations per issuer
+	signLimiter *issuerLimiter
+
+	// fallbackFailureThreshold and fallbackPendingTimeout control when a
+	// CertificateRequest with a fallbackIssuerRef is moved on to its fallback
+	// issuer
+	fallbackFailureThreshold int
+	fallbackPendingTimeout   time.Duration
+
+	// auditHooks are called each time a CertificateRequest has been signed
+	auditHooks []AuditHook
 }
 
 // New will construct a new certificaterequest controller using the given
@@ -162,7 +180,11 @@ func (c *Controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitin
 	// recorder records events about resources to the Kubernetes api
 	c.recorder = ctx.Recorder
 	c.reporter = util.NewReporter(c.clock, c.recorder)
+	c.signLimiter = newIssuerLimiter(ctx.MaxConcurrentSignsPerIssuer)
+	c.fallbackFailureThreshold = ctx.FallbackIssuerFailureThreshold
+	c.fallbackPendingTimeout = ctx.FallbackIssuerPendingTimeout
 	c.cmClient = ctx.CMClient
+	c.auditHooks = append([]AuditHook{newLogAuditHook(c.log)}, auditHooks...)
 
 	c.log.V(logf.DebugLevel).Info("new certificate request controller registered",
 		"type", c.issuerType)
@@ -0,0 +1,85 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificaterequests
+
+import (
+	"fmt"
+	"sync"
+
+	v1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+// issuerLimiter limits the number of sign operations that may be in flight
+// at once for each issuer. It holds a semaphore per issuer that is created
+// the first time the issuer is seen.
+type issuerLimiter struct {
+	// limit is the maximum number of concurrent sign operations per issuer.
+	// A limit of zero or less disables limiting.
+	limit int
+
+	lock       sync.Mutex
+	semaphores map[string]chan struct{}
+}
+
+func newIssuerLimiter(limit int) *issuerLimiter {
+	return &issuerLimiter{
+		limit:      limit,
+		semaphores: make(map[string]chan struct{}),
+	}
+}
+
+// tryAcquire attempts to reserve a slot for a sign operation against the
+// issuer identified by key without blocking. If a slot was reserved, the
+// returned release function must be called once the operation completes.
+// It returns false if the issuer already has the maximum number of sign
+// operations in flight.
+func (l *issuerLimiter) tryAcquire(key string) (func(), bool) {
+	if l.limit <= 0 {
+		return func() {}, true
+	}
+
+	sem := l.semaphore(key)
+	select {
+	case sem <- struct{}{}:
+		return func() { <-sem }, true
+	default:
+		return nil, false
+	}
+}
+
+func (l *issuerLimiter) semaphore(key string) chan struct{} {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	sem, ok := l.semaphores[key]
+	if !ok {
+		sem = make(chan struct{}, l.limit)
+		l.semaphores[key] = sem
+	}
+
+	return sem
+}
+
+// issuerLimiterKey returns the key used to identify the given issuer in an
+// issuerLimiter.
+func issuerLimiterKey(iss v1.GenericIssuer) string {
+	meta := iss.GetObjectMeta()
+	if meta.Namespace == "" {
+		return fmt.Sprintf("%s/%s", v1.ClusterIssuerKind, meta.Name)
+	}
+	return fmt.Sprintf("%s/%s/%s", v1.IssuerKind, meta.Namespace, meta.Name)
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificaterequests
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"sync"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/jetstack/cert-manager/pkg/api/util"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	"github.com/jetstack/cert-manager/pkg/controller/certificaterequests/fake"
+	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/pkg/issuer"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestIssuerLimiter(t *testing.T) {
+	l := newIssuerLimiter(1)
+
+	releaseA, ok := l.tryAcquire("a")
+	if !ok {
+		t.Fatalf("expected to acquire a slot for issuer a")
+	}
+	if _, ok := l.tryAcquire("a"); ok {
+		t.Errorf("expected not to acquire a second slot for issuer a")
+	}
+	releaseB, ok := l.tryAcquire("b")
+	if !ok {
+		t.Errorf("expected issuer b to be limited independently of issuer a")
+	}
+	releaseB()
+
+	releaseA()
+	if _, ok := l.tryAcquire("a"); !ok {
+		t.Errorf("expected to acquire a slot for issuer a after it was released")
+	}
+
+	unlimited := newIssuerLimiter(0)
+	for i := 0; i < 100; i++ {
+		if _, ok := unlimited.tryAcquire("a"); !ok {
+			t.Fatalf("expected a limit of 0 to never block")
+		}
+	}
+}
+
+func TestSyncBoundsConcurrentSignsPerIssuer(t *testing.T) {
+	const limit = 2
+	const numRequests = 10
+
+	sk, err := pki.GenerateECPrivateKey(256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	csrPEM := generateCSR(t, sk, x509.ECDSAWithSHA256)
+
+	baseIssuer := gen.Issuer("test-issuer",
+		gen.SetIssuerSelfSigned(cmapi.SelfSignedIssuer{}),
+		gen.AddIssuerCondition(cmapi.IssuerCondition{
+			Type:   cmapi.IssuerConditionReady,
+			Status: cmmeta.ConditionTrue,
+		}),
+	)
+
+	objects := []runtime.Object{baseIssuer}
+	var crs []*cmapi.CertificateRequest
+	for i := 0; i < numRequests; i++ {
+		cr := gen.CertificateRequest(fmt.Sprintf("test-cr-%d", i),
+			gen.SetCertificateRequestIsCA(false),
+			gen.SetCertificateRequestCSR(csrPEM),
+			gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+				Kind: baseIssuer.Kind,
+				Name: baseIssuer.Name,
+			}),
+		)
+		crs = append(crs, cr)
+		objects = append(objects, cr)
+	}
+
+	var lock sync.Mutex
+	inFlight, maxInFlight, signCalls := 0, 0, 0
+	started := make(chan struct{}, numRequests)
+	unblock := make(chan struct{})
+	issuerImpl := &fake.Issuer{
+		FakeSign: func(context.Context, *cmapi.CertificateRequest, cmapi.GenericIssuer) (*issuer.IssueResponse, error) {
+			lock.Lock()
+			signCalls++
+			inFlight++
+			if inFlight > maxInFlight {
+				maxInFlight = inFlight
+			}
+			lock.Unlock()
+
+			started <- struct{}{}
+			<-unblock
+
+			lock.Lock()
+			inFlight--
+			lock.Unlock()
+			return nil, nil
+		},
+	}
+
+	builder := &testpkg.Builder{
+		T:                  t,
+		Clock:              fixedClock,
+		CertManagerObjects: objects,
+		Context: &controllerpkg.Context{
+			RootContext: context.Background(),
+			IssuerOptions: controllerpkg.IssuerOptions{
+				MaxConcurrentSignsPerIssuer: limit,
+			},
+		},
+	}
+	builder.Init()
+	defer builder.Stop()
+
+	c := New(util.IssuerSelfSigned, issuerImpl)
+	c.Register(builder.Context)
+	builder.Start()
+
+	errs := make(chan error, numRequests)
+	for _, cr := range crs {
+		go func(cr *cmapi.CertificateRequest) {
+			errs <- c.Sync(context.Background(), cr)
+		}(cr)
+	}
+
+	// Sign operations block until unblock is closed, so every Sync that
+	// returns before then must have been turned away by the limiter.
+	for i := 0; i < numRequests-limit; i++ {
+		if err := <-errs; err != nil {
+			t.Errorf("expected no error from a requeued request, got: %v", err)
+		}
+	}
+	for i := 0; i < limit; i++ {
+		<-started
+	}
+	close(unblock)
+	for i := 0; i < limit; i++ {
+		if err := <-errs; err != nil {
+			t.Errorf("expected no error from a signed request, got: %v", err)
+		}
+	}
+
+	if signCalls != limit {
+		t.Errorf("expected %d sign calls, got %d", limit, signCalls)
+	}
+	if maxInFlight > limit {
+		t.Errorf("expected at most %d concurrent sign calls, got %d", limit, maxInFlight)
+	}
+}
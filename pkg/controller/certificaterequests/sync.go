@@ -21,8 +21,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"strconv"
+	"time"
 
 	"github.com/kr/pretty"
+	corev1 "k8s.io/api/core/v1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
@@ -45,7 +48,10 @@ func (c *Controller) Sync(ctx context.Context, cr *v1.CertificateRequest) (err e
 	log := logf.FromContext(ctx)
 	dbg := log.V(logf.DebugLevel)
 
-	if !(cr.Spec.IssuerRef.Group == "" || cr.Spec.IssuerRef.Group == certmanager.GroupName) {
+	// The issuer that should sign the request may be the fallback issuer if
+	// signing with the primary issuer has repeatedly failed.
+	issuerRef := apiutil.CertificateRequestIssuerRef(cr)
+	if !(issuerRef.Group == "" || issuerRef.Group == certmanager.GroupName) {
 		dbg.Info("certificate request issuerRef group does not match certmanager group so skipping processing")
 		return nil
 	}
@@ -70,10 +76,11 @@ func (c *Controller) Sync(ctx context.Context, cr *v1.CertificateRequest) (err e
 
 	dbg.Info("fetching issuer object referenced by CertificateRequest")
 
-	issuerObj, err := c.helper.GetGenericIssuer(crCopy.Spec.IssuerRef, crCopy.Namespace)
+	issuerObj, err := c.helper.GetGenericIssuer(issuerRef, crCopy.Namespace)
 	if k8sErrors.IsNotFound(err) {
 		c.reporter.Pending(crCopy, err, "IssuerNotFound",
-			fmt.Sprintf("Referenced %q not found", apiutil.IssuerKind(crCopy.Spec.IssuerRef)))
+			fmt.Sprintf("Referenced %q not found", apiutil.IssuerKind(issuerRef)))
+		c.checkIssuerUnavailableTimeout(crCopy)
 		return nil
 	}
 
@@ -107,9 +114,14 @@ func (c *Controller) Sync(ctx context.Context, cr *v1.CertificateRequest) (err e
 	}) {
 		c.reporter.Pending(crCopy, nil, "IssuerNotReady",
 			"Referenced issuer does not have a Ready status condition")
+		c.checkIssuerUnavailableTimeout(crCopy)
 		return nil
 	}
 
+	// The issuer is available again, so it should be given the full timeout
+	// if it becomes unavailable later on.
+	delete(crCopy.Annotations, v1.CertificateRequestIssuerUnavailableSinceAnnotationKey)
+
 	dbg.Info("validating CertificateRequest resource object")
 
 	el := webhook.ValidationRegistry.Validate(crCopy, internalapi.SchemeGroupVersion.WithKind("CertificateRequest"))
@@ -124,15 +136,35 @@ func (c *Controller) Sync(ctx context.Context, cr *v1.CertificateRequest) (err e
 		return nil
 	}
 
+	release, ok := c.signLimiter.tryAcquire(issuerLimiterKey(issuerObj))
+	if !ok {
+		key, err := keyFunc(crCopy)
+		if err != nil {
+			return err
+		}
+		dbg.Info("issuer has the maximum number of concurrent sign operations in flight, requeueing")
+		c.queue.AddAfter(key, signLimitRequeueDelay)
+		return nil
+	}
+	defer release()
+
 	dbg.Info("invoking sign function as existing certificate does not exist")
 
 	// Attempt to call the Sign function on our issuer
 	resp, err := c.issuer.Sign(ctx, crCopy, issuerObj)
 	if err != nil {
 		log.Error(err, "error issuing certificate request")
+		c.recordIssuerFailure(crCopy, false)
 		return err
 	}
 
+	// A request that the primary issuer has marked as failed will not be
+	// retried, so move straight on to the fallback issuer if there is one.
+	if apiutil.CertificateRequestReadyReason(crCopy) == v1.CertificateRequestReasonFailed {
+		c.recordIssuerFailure(crCopy, true)
+		return nil
+	}
+
 	// If the issuer has not returned any data we may be pending or failed. The
 	// underlying issuer will have set the condition of pending or failed and we
 	// should potentially wait for a re-sync.
@@ -145,7 +177,7 @@ func (c *Controller) Sync(ctx context.Context, cr *v1.CertificateRequest) (err e
 	crCopy.Status.CA = resp.CA
 
 	// invalid cert
-	_, err = pki.DecodeX509CertificateBytes(crCopy.Status.Certificate)
+	cert, err := pki.DecodeX509CertificateBytes(crCopy.Status.Certificate)
 	if err != nil {
 		c.reporter.Failed(crCopy, err, "DecodeError", "Failed to decode returned certificate")
 		return nil
@@ -153,10 +185,80 @@ func (c *Controller) Sync(ctx context.Context, cr *v1.CertificateRequest) (err e
 
 	// Set condition to Ready.
 	c.reporter.Ready(crCopy)
+	c.audit(ctx, crCopy, cert)
 
 	return nil
 }
 
+// recordIssuerFailure records a failed attempt at signing the given
+// CertificateRequest with the issuer referenced by its issuerRef, if it has a
+// fallbackIssuerRef. Once signing has failed fallbackFailureThreshold times,
+// or if the failure is terminal, the CertificateRequest is marked as pending
+// so that it will be signed by its fallback issuer instead.
+func (c *Controller) recordIssuerFailure(cr *v1.CertificateRequest, terminal bool) {
+	if cr.Spec.FallbackIssuerRef == nil || apiutil.CertificateRequestUsesFallbackIssuer(cr) {
+		return
+	}
+
+	failures := apiutil.CertificateRequestIssuerFailures(cr) + 1
+	if cr.Annotations == nil {
+		cr.Annotations = make(map[string]string)
+	}
+	cr.Annotations[v1.CertificateRequestIssuerFailuresAnnotationKey] = strconv.Itoa(failures)
+
+	if !terminal && failures < c.fallbackFailureThreshold {
+		return
+	}
+
+	cr.Annotations[v1.CertificateRequestUsingFallbackIssuerAnnotationKey] = "true"
+	delete(cr.Annotations, v1.CertificateRequestIssuerUnavailableSinceAnnotationKey)
+
+	message := fmt.Sprintf("Signing with %s %q failed, using fallback %s %q",
+		apiutil.IssuerKind(cr.Spec.IssuerRef), cr.Spec.IssuerRef.Name,
+		apiutil.IssuerKind(*cr.Spec.FallbackIssuerRef), cr.Spec.FallbackIssuerRef.Name)
+	c.recorder.Event(cr, corev1.EventTypeWarning, "UsingFallbackIssuer", message)
+	cr.Status.FailureTime = nil
+	apiutil.SetCertificateRequestCondition(cr, v1.CertificateRequestConditionReady,
+		cmmeta.ConditionFalse, v1.CertificateRequestReasonPending, message)
+}
+
+// checkIssuerUnavailableTimeout moves a CertificateRequest whose issuerRef
+// references a missing or not ready issuer on to its fallback issuer once the
+// issuer has been unavailable for fallbackPendingTimeout. The time since which
+// the issuer has been unavailable is recorded on the CertificateRequest, and
+// until the timeout has passed the CertificateRequest is re-queued to be
+// checked again, as it may not otherwise be re-synced.
+// Requests that are pending while the issuer works on them, such as those
+// waiting on an ACME order, are not subject to this timeout.
+func (c *Controller) checkIssuerUnavailableTimeout(cr *v1.CertificateRequest) {
+	if cr.Spec.FallbackIssuerRef == nil || apiutil.CertificateRequestUsesFallbackIssuer(cr) {
+		return
+	}
+
+	now := c.clock.Now()
+	since, err := time.Parse(time.RFC3339, cr.Annotations[v1.CertificateRequestIssuerUnavailableSinceAnnotationKey])
+	if err != nil {
+		since = now
+		if cr.Annotations == nil {
+			cr.Annotations = make(map[string]string)
+		}
+		cr.Annotations[v1.CertificateRequestIssuerUnavailableSinceAnnotationKey] = since.UTC().Format(time.RFC3339)
+	}
+
+	unavailable := now.Sub(since)
+	if unavailable >= c.fallbackPendingTimeout {
+		c.recordIssuerFailure(cr, true)
+		return
+	}
+
+	key, err := keyFunc(cr)
+	if err != nil {
+		c.log.Error(err, "failed to compute key for CertificateRequest")
+		return
+	}
+	c.queue.AddAfter(key, c.fallbackPendingTimeout-unavailable)
+}
+
 func (c *Controller) updateCertificateRequestStatusAndAnnotations(ctx context.Context, old, new *v1.CertificateRequest) (*v1.CertificateRequest, error) {
 	log := logf.FromContext(ctx, "updateStatus")
 
@@ -26,6 +26,7 @@ import (
 	"encoding/asn1"
 	"encoding/pem"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -37,6 +38,7 @@ import (
 	"github.com/jetstack/cert-manager/pkg/api/util"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
 	"github.com/jetstack/cert-manager/pkg/controller/certificaterequests/fake"
 	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
 	"github.com/jetstack/cert-manager/pkg/issuer"
@@ -581,3 +583,286 @@ func runTest(t *testing.T, test testT) {
 	}
 	test.builder.CheckAndFinish(err)
 }
+
+func TestSyncFallbackIssuer(t *testing.T) {
+	const pendingTimeout = 30 * time.Minute
+	nowMetaTime := metav1.NewTime(fixedClockStart)
+
+	sk, err := pki.GenerateRSAPrivateKey(2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	readyCondition := gen.AddIssuerCondition(cmapi.IssuerCondition{
+		Type:   cmapi.IssuerConditionReady,
+		Status: cmmeta.ConditionTrue,
+	})
+	primaryIssuer := gen.Issuer("primary-issuer",
+		gen.SetIssuerSelfSigned(cmapi.SelfSignedIssuer{}), readyCondition)
+	fallbackIssuer := gen.Issuer("fallback-issuer",
+		gen.SetIssuerSelfSigned(cmapi.SelfSignedIssuer{}), readyCondition)
+	notReadyPrimaryIssuer := gen.Issuer("primary-issuer",
+		gen.SetIssuerSelfSigned(cmapi.SelfSignedIssuer{}))
+
+	baseCR := gen.CertificateRequest("test-cr",
+		gen.SetCertificateRequestCSR(generateCSR(t, sk, x509.SHA256WithRSA)),
+		gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{Name: primaryIssuer.Name}),
+		gen.SetCertificateRequestFallbackIssuer(cmmeta.ObjectReference{Name: fallbackIssuer.Name}),
+	)
+	withAnnotation := func(key, value string) gen.CertificateRequestModifier {
+		return func(cr *cmapi.CertificateRequest) {
+			if cr.Annotations == nil {
+				cr.Annotations = make(map[string]string)
+			}
+			cr.Annotations[key] = value
+		}
+	}
+	withFailures := func(failures string) gen.CertificateRequestModifier {
+		return withAnnotation(cmapi.CertificateRequestIssuerFailuresAnnotationKey, failures)
+	}
+	usingFallback := withAnnotation(cmapi.CertificateRequestUsingFallbackIssuerAnnotationKey, "true")
+	unavailableSince := func(t time.Time) gen.CertificateRequestModifier {
+		return withAnnotation(cmapi.CertificateRequestIssuerUnavailableSinceAnnotationKey, t.UTC().Format(time.RFC3339))
+	}
+	createdAt := func(t time.Time) gen.CertificateRequestModifier {
+		return func(cr *cmapi.CertificateRequest) {
+			cr.CreationTimestamp = metav1.NewTime(t)
+		}
+	}
+	fallbackCondition := gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+		Type:               cmapi.CertificateRequestConditionReady,
+		Status:             cmmeta.ConditionFalse,
+		Reason:             "Pending",
+		Message:            `Signing with Issuer "primary-issuer" failed, using fallback Issuer "fallback-issuer"`,
+		LastTransitionTime: &nowMetaTime,
+	})
+	notReadyCondition := gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+		Type:               cmapi.CertificateRequestConditionReady,
+		Status:             cmmeta.ConditionFalse,
+		Reason:             "Pending",
+		Message:            "Referenced issuer does not have a Ready status condition",
+		LastTransitionTime: &nowMetaTime,
+	})
+	issuedCondition := gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+		Type:               cmapi.CertificateRequestConditionReady,
+		Status:             cmmeta.ConditionTrue,
+		Reason:             "Issued",
+		Message:            "Certificate fetched from issuer successfully",
+		LastTransitionTime: &nowMetaTime,
+	})
+
+	cert := generateSelfSignedCert(t, baseCR, sk, fixedClockStart, fixedClockStart.Add(time.Hour*12))
+
+	signWith := func(issuerName string, resp *issuer.IssueResponse, err error) Issuer {
+		return &fake.Issuer{
+			FakeSign: func(_ context.Context, _ *cmapi.CertificateRequest, iss cmapi.GenericIssuer) (*issuer.IssueResponse, error) {
+				if iss.GetObjectMeta().Name != issuerName {
+					return nil, fmt.Errorf("expected sign to be called with issuer %q but got %q", issuerName, iss.GetObjectMeta().Name)
+				}
+				return resp, err
+			},
+		}
+	}
+
+	tests := map[string]testT{
+		"should record a failure if signing with the primary issuer fails": {
+			certificateRequest: baseCR.DeepCopy(),
+			issuerImpl:         signWith(primaryIssuer.Name, nil, errors.New("CA unavailable")),
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{primaryIssuer, fallbackIssuer, baseCR},
+				ExpectedEvents:     []string{},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateAction(
+						cmapi.SchemeGroupVersion.WithResource("certificaterequests"),
+						gen.DefaultTestNamespace,
+						gen.CertificateRequestFrom(baseCR, withFailures("1")),
+					)),
+				},
+			},
+			expectedErr: true,
+		},
+		"should switch to the fallback issuer once signing with the primary issuer has failed enough times": {
+			certificateRequest: gen.CertificateRequestFrom(baseCR, withFailures("2")),
+			issuerImpl:         signWith(primaryIssuer.Name, nil, errors.New("CA unavailable")),
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{primaryIssuer, fallbackIssuer, gen.CertificateRequestFrom(baseCR, withFailures("2"))},
+				ExpectedEvents: []string{
+					`Warning UsingFallbackIssuer Signing with Issuer "primary-issuer" failed, using fallback Issuer "fallback-issuer"`,
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateAction(
+						cmapi.SchemeGroupVersion.WithResource("certificaterequests"),
+						gen.DefaultTestNamespace,
+						gen.CertificateRequestFrom(baseCR, withFailures("3"), usingFallback, fallbackCondition),
+					)),
+				},
+			},
+			expectedErr: true,
+		},
+		"should switch to the fallback issuer immediately if the primary issuer marks the request as failed": {
+			certificateRequest: baseCR.DeepCopy(),
+			issuerImpl: &fake.Issuer{
+				FakeSign: func(_ context.Context, cr *cmapi.CertificateRequest, _ cmapi.GenericIssuer) (*issuer.IssueResponse, error) {
+					cr.Status.FailureTime = &nowMetaTime
+					util.SetCertificateRequestCondition(cr, cmapi.CertificateRequestConditionReady,
+						cmmeta.ConditionFalse, cmapi.CertificateRequestReasonFailed, "order failed")
+					return nil, nil
+				},
+			},
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{primaryIssuer, fallbackIssuer, baseCR},
+				ExpectedEvents: []string{
+					`Warning UsingFallbackIssuer Signing with Issuer "primary-issuer" failed, using fallback Issuer "fallback-issuer"`,
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateAction(
+						cmapi.SchemeGroupVersion.WithResource("certificaterequests"),
+						gen.DefaultTestNamespace,
+						gen.CertificateRequestFrom(baseCR, withFailures("1"), usingFallback, fallbackCondition),
+					)),
+				},
+			},
+		},
+		"should issue the certificate using the fallback issuer": {
+			certificateRequest: gen.CertificateRequestFrom(baseCR, withFailures("3"), usingFallback, fallbackCondition),
+			issuerImpl:         signWith(fallbackIssuer.Name, &issuer.IssueResponse{Certificate: cert}, nil),
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{primaryIssuer, fallbackIssuer, gen.CertificateRequestFrom(baseCR, withFailures("3"), usingFallback, fallbackCondition)},
+				ExpectedEvents: []string{
+					"Normal CertificateIssued Certificate fetched from issuer successfully",
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificaterequests"),
+						"status",
+						gen.DefaultTestNamespace,
+						gen.CertificateRequestFrom(baseCR, withFailures("3"), usingFallback,
+							gen.SetCertificateRequestCertificate(cert), issuedCondition),
+					)),
+				},
+			},
+		},
+		"should record when the primary issuer was first seen not ready": {
+			certificateRequest: baseCR.DeepCopy(),
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{notReadyPrimaryIssuer, fallbackIssuer, baseCR},
+				ExpectedEvents: []string{
+					"Normal IssuerNotReady Referenced issuer does not have a Ready status condition",
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateAction(
+						cmapi.SchemeGroupVersion.WithResource("certificaterequests"),
+						gen.DefaultTestNamespace,
+						gen.CertificateRequestFrom(baseCR, unavailableSince(fixedClockStart), notReadyCondition),
+					)),
+				},
+			},
+		},
+		"should not switch to the fallback issuer if the primary issuer has not been ready for less than the pending timeout": {
+			certificateRequest: gen.CertificateRequestFrom(baseCR, unavailableSince(fixedClockStart.Add(-time.Minute))),
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{notReadyPrimaryIssuer, fallbackIssuer, gen.CertificateRequestFrom(baseCR, unavailableSince(fixedClockStart.Add(-time.Minute)))},
+				ExpectedEvents: []string{
+					"Normal IssuerNotReady Referenced issuer does not have a Ready status condition",
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificaterequests"),
+						"status",
+						gen.DefaultTestNamespace,
+						gen.CertificateRequestFrom(baseCR, unavailableSince(fixedClockStart.Add(-time.Minute)), notReadyCondition),
+					)),
+				},
+			},
+		},
+		"should switch to the fallback issuer if the primary issuer has not been ready for longer than the pending timeout": {
+			certificateRequest: gen.CertificateRequestFrom(baseCR, unavailableSince(fixedClockStart.Add(-pendingTimeout))),
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{notReadyPrimaryIssuer, fallbackIssuer, gen.CertificateRequestFrom(baseCR, unavailableSince(fixedClockStart.Add(-pendingTimeout)))},
+				ExpectedEvents: []string{
+					"Normal IssuerNotReady Referenced issuer does not have a Ready status condition",
+					`Warning UsingFallbackIssuer Signing with Issuer "primary-issuer" failed, using fallback Issuer "fallback-issuer"`,
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateAction(
+						cmapi.SchemeGroupVersion.WithResource("certificaterequests"),
+						gen.DefaultTestNamespace,
+						gen.CertificateRequestFrom(baseCR, withFailures("1"), usingFallback, fallbackCondition),
+					)),
+				},
+			},
+		},
+		"should forget when the primary issuer was not ready once it is ready again": {
+			certificateRequest: gen.CertificateRequestFrom(baseCR, unavailableSince(fixedClockStart.Add(-time.Minute))),
+			issuerImpl:         signWith(primaryIssuer.Name, &issuer.IssueResponse{Certificate: cert}, nil),
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{primaryIssuer, fallbackIssuer, gen.CertificateRequestFrom(baseCR, unavailableSince(fixedClockStart.Add(-time.Minute)))},
+				ExpectedEvents: []string{
+					"Normal CertificateIssued Certificate fetched from issuer successfully",
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateAction(
+						cmapi.SchemeGroupVersion.WithResource("certificaterequests"),
+						gen.DefaultTestNamespace,
+						gen.CertificateRequestFrom(baseCR, gen.SetCertificateRequestAnnotations(map[string]string{}),
+							gen.SetCertificateRequestCertificate(cert), issuedCondition),
+					)),
+				},
+			},
+		},
+		"should not switch to the fallback issuer while the primary issuer is still working on the request": {
+			certificateRequest: gen.CertificateRequestFrom(baseCR, createdAt(fixedClockStart.Add(-2*pendingTimeout))),
+			issuerImpl: &fake.Issuer{
+				FakeSign: func(_ context.Context, cr *cmapi.CertificateRequest, _ cmapi.GenericIssuer) (*issuer.IssueResponse, error) {
+					util.SetCertificateRequestCondition(cr, cmapi.CertificateRequestConditionReady,
+						cmmeta.ConditionFalse, cmapi.CertificateRequestReasonPending, "Waiting on certificate issuance from order: \"pending\"")
+					return nil, nil
+				},
+			},
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{primaryIssuer, fallbackIssuer, gen.CertificateRequestFrom(baseCR, createdAt(fixedClockStart.Add(-2*pendingTimeout)))},
+				ExpectedEvents:     []string{},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificaterequests"),
+						"status",
+						gen.DefaultTestNamespace,
+						gen.CertificateRequestFrom(baseCR, createdAt(fixedClockStart.Add(-2*pendingTimeout)),
+							gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+								Type:               cmapi.CertificateRequestConditionReady,
+								Status:             cmmeta.ConditionFalse,
+								Reason:             "Pending",
+								Message:            "Waiting on certificate issuance from order: \"pending\"",
+								LastTransitionTime: &nowMetaTime,
+							}),
+						),
+					)),
+				},
+			},
+		},
+		"should not record failures if no fallback issuer is configured": {
+			certificateRequest: gen.CertificateRequestFrom(baseCR, func(cr *cmapi.CertificateRequest) { cr.Spec.FallbackIssuerRef = nil }),
+			issuerImpl:         signWith(primaryIssuer.Name, nil, errors.New("CA unavailable")),
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{primaryIssuer, fallbackIssuer, baseCR},
+				ExpectedEvents:     []string{},
+				ExpectedActions:    []testpkg.Action{},
+			},
+			expectedErr: true,
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			fixedClock.SetTime(fixedClockStart)
+			test.builder.Context = &controllerpkg.Context{
+				RootContext: context.Background(),
+				IssuerOptions: controllerpkg.IssuerOptions{
+					FallbackIssuerFailureThreshold: 3,
+					FallbackIssuerPendingTimeout:   pendingTimeout,
+				},
+			}
+			runTest(t, test)
+		})
+	}
+}
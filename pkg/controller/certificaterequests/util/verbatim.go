@@ -0,0 +1,39 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+// SignCSRVerbatim returns true if the given CertificateRequest has requested
+// that its CSR be signed as-is. An error is returned if the CertificateRequest
+// requests verbatim signing but it has not been enabled on the controller.
+func SignCSRVerbatim(cr *cmapi.CertificateRequest, enabled bool) (bool, error) {
+	if cr.Annotations[cmapi.CertificateRequestSignCSRVerbatimAnnotationKey] != "true" {
+		return false, nil
+	}
+
+	if !enabled {
+		return false, fmt.Errorf("annotation %q is set but verbatim CSR signing is not enabled on the controller",
+			cmapi.CertificateRequestSignCSRVerbatimAnnotationKey)
+	}
+
+	return true, nil
+}
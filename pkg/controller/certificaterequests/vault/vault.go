@@ -85,8 +85,21 @@ func (v *Vault) Sign(ctx context.Context, cr *v1.CertificateRequest, issuerObj v
 		return nil, nil
 	}
 
+	verbatim, err := crutil.SignCSRVerbatim(cr, v.issuerOptions.EnableVerbatimCSRSigning)
+	if err != nil {
+		message := "Refusing to sign CSR verbatim"
+		v.reporter.Failed(cr, err, "VerbatimSigningDisabled", message)
+		log.Error(err, message)
+		return nil, nil
+	}
+
+	sign := client.Sign
+	if verbatim {
+		sign = client.SignVerbatim
+	}
+
 	certDuration := apiutil.DefaultCertDuration(cr.Spec.Duration)
-	certPem, caPem, err := client.Sign(cr.Spec.Request, certDuration)
+	certPem, caPem, err := sign(cr.Spec.Request, certDuration)
 	if err != nil {
 		message := "Vault failed to sign certificate"
 
@@ -150,6 +150,12 @@ func TestSign(t *testing.T) {
 		},
 	}
 
+	verbatimCR := gen.CertificateRequestFrom(baseCR,
+		gen.AddCertificateRequestAnnotations(map[string]string{
+			cmapi.CertificateRequestSignCSRVerbatimAnnotationKey: "true",
+		}),
+	)
+
 	tests := map[string]testT{
 		"no token, app role secret or kubernetes auth reference should report pending": {
 			certificateRequest: baseCR.DeepCopy(),
@@ -447,6 +453,88 @@ func TestSign(t *testing.T) {
 			},
 			fakeVault: fakevault.New().WithSign(rsaPEMCert, rsaPEMCert, nil),
 		},
+		"a request to sign verbatim with verbatim signing enabled should sign the CSR verbatim": {
+			certificateRequest:       verbatimCR.DeepCopy(),
+			enableVerbatimCSRSigning: true,
+			builder: &testpkg.Builder{
+				KubeObjects: []runtime.Object{tokenSecret},
+				CertManagerObjects: []runtime.Object{verbatimCR.DeepCopy(), gen.IssuerFrom(baseIssuer,
+					gen.SetIssuerVault(cmapi.VaultIssuer{
+						Auth: cmapi.VaultAuth{
+							TokenSecretRef: &cmmeta.SecretKeySelector{
+								Key: "my-token-key",
+								LocalObjectReference: cmmeta.LocalObjectReference{
+									Name: "token-secret",
+								},
+							},
+						},
+					}),
+				)},
+				ExpectedEvents: []string{
+					"Normal CertificateIssued Certificate fetched from issuer successfully",
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificaterequests"),
+						"status",
+						gen.DefaultTestNamespace,
+						gen.CertificateRequestFrom(verbatimCR,
+							gen.SetCertificateRequestCertificate(rsaPEMCert),
+							gen.SetCertificateRequestCA(rsaPEMCert),
+							gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+								Type:               cmapi.CertificateRequestConditionReady,
+								Status:             cmmeta.ConditionTrue,
+								Reason:             cmapi.CertificateRequestReasonIssued,
+								Message:            "Certificate fetched from issuer successfully",
+								LastTransitionTime: &metaFixedClockStart,
+							}),
+						),
+					)),
+				},
+			},
+			fakeVault: fakevault.New().
+				WithSign(nil, nil, errors.New("unexpected call to Sign")).
+				WithSignVerbatim(rsaPEMCert, rsaPEMCert, nil),
+		},
+		"a request to sign verbatim with verbatim signing disabled should report fail": {
+			certificateRequest: verbatimCR.DeepCopy(),
+			builder: &testpkg.Builder{
+				KubeObjects: []runtime.Object{tokenSecret},
+				CertManagerObjects: []runtime.Object{verbatimCR.DeepCopy(), gen.IssuerFrom(baseIssuer,
+					gen.SetIssuerVault(cmapi.VaultIssuer{
+						Auth: cmapi.VaultAuth{
+							TokenSecretRef: &cmmeta.SecretKeySelector{
+								Key: "my-token-key",
+								LocalObjectReference: cmmeta.LocalObjectReference{
+									Name: "token-secret",
+								},
+							},
+						},
+					}),
+				)},
+				ExpectedEvents: []string{
+					`Warning VerbatimSigningDisabled Refusing to sign CSR verbatim: annotation "cert-manager.io/sign-csr-verbatim" is set but verbatim CSR signing is not enabled on the controller`,
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificaterequests"),
+						"status",
+						gen.DefaultTestNamespace,
+						gen.CertificateRequestFrom(verbatimCR,
+							gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+								Type:               cmapi.CertificateRequestConditionReady,
+								Status:             cmmeta.ConditionFalse,
+								Reason:             cmapi.CertificateRequestReasonFailed,
+								Message:            `Refusing to sign CSR verbatim: annotation "cert-manager.io/sign-csr-verbatim" is set but verbatim CSR signing is not enabled on the controller`,
+								LastTransitionTime: &metaFixedClockStart,
+							}),
+							gen.SetCertificateRequestFailureTime(metaFixedClockStart),
+						),
+					)),
+				},
+			},
+			fakeVault: fakevault.New().WithSignVerbatim(rsaPEMCert, rsaPEMCert, nil),
+		},
 	}
 
 	for name, test := range tests {
@@ -464,6 +552,8 @@ type testT struct {
 
 	expectedErr bool
 
+	enableVerbatimCSRSigning bool
+
 	fakeVault *fakevault.Vault
 }
 
@@ -473,6 +563,7 @@ func runTest(t *testing.T, test testT) {
 	defer test.builder.Stop()
 
 	vault := NewVault(test.builder.Context)
+	vault.issuerOptions.EnableVerbatimCSRSigning = test.enableVerbatimCSRSigning
 
 	if test.fakeVault != nil {
 		vault.vaultClientBuilder = func(ns string, sl corelisters.SecretLister,
@@ -24,6 +24,7 @@ import (
 
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 	corelisters "k8s.io/client-go/listers/core/v1"
 
 	"github.com/Venafi/vcert/v4/pkg/endpoint"
@@ -49,6 +50,7 @@ type Venafi struct {
 	secretsLister corelisters.SecretLister
 	reporter      *crutil.Reporter
 	cmClient      clientset.Interface
+	kubeClient    kubernetes.Interface
 
 	clientBuilder venaficlient.VenafiClientBuilder
 }
@@ -69,6 +71,7 @@ func NewVenafi(ctx *controllerpkg.Context) *Venafi {
 		reporter:      crutil.NewReporter(ctx.Clock, ctx.Recorder),
 		clientBuilder: venaficlient.New,
 		cmClient:      ctx.CMClient,
+		kubeClient:    ctx.Client,
 	}
 }
 
@@ -76,7 +79,7 @@ func (v *Venafi) Sign(ctx context.Context, cr *cmapi.CertificateRequest, issuerO
 	log := logf.FromContext(ctx, "sign")
 	log = logf.WithRelatedResource(log, issuerObj)
 
-	client, err := v.clientBuilder(v.issuerOptions.ResourceNamespace(issuerObj), v.secretsLister, issuerObj)
+	client, err := v.clientBuilder(v.issuerOptions.ResourceNamespace(issuerObj), v.secretsLister, v.kubeClient.CoreV1(), issuerObj)
 	if k8sErrors.IsNotFound(err) {
 		message := "Required secret resource not found"
 
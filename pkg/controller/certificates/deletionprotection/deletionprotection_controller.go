@@ -0,0 +1,360 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletionprotection
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"golang.org/x/crypto/acme"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/jetstack/cert-manager/pkg/acme/accounts"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	cminformers "github.com/jetstack/cert-manager/pkg/client/informers/externalversions"
+	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates"
+	"github.com/jetstack/cert-manager/pkg/issuer"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+	"github.com/jetstack/cert-manager/pkg/util/predicate"
+)
+
+const (
+	ControllerName = "CertificateDeletionProtection"
+
+	// unauthorizedError is the problem type returned by an ACME server when
+	// the key used to sign a request is not authorized to perform it.
+	unauthorizedError = "urn:ietf:params:acme:error:unauthorized"
+
+	// alreadyRevokedError is the problem type returned by an ACME server
+	// when the certificate to revoke has already been revoked.
+	alreadyRevokedError = "urn:ietf:params:acme:error:alreadyRevoked"
+)
+
+// controller adds the deletion protection finalizer to Certificates that opt
+// in to it, and holds back their deletion until the opt-in annotation is
+// removed again, their Secret has been orphaned or their certificate revoked.
+type controller struct {
+	certificateLister cmlisters.CertificateLister
+	secretLister      corelisters.SecretLister
+	client            cmclient.Interface
+	coreClient        kubernetes.Interface
+	recorder          record.EventRecorder
+
+	// issuerHelper and accountRegistry are used to find the ACME client
+	// that revokes the certificate of a Certificate on deletion.
+	issuerHelper    issuer.Helper
+	accountRegistry accounts.Getter
+}
+
+func NewController(
+	log logr.Logger,
+	client cmclient.Interface,
+	coreClient kubernetes.Interface,
+	factory informers.SharedInformerFactory,
+	cmFactory cminformers.SharedInformerFactory,
+	recorder record.EventRecorder,
+	accountRegistry accounts.Getter,
+) (*controller, workqueue.RateLimitingInterface, []cache.InformerSynced) {
+	// create a queue used to queue up items to be processed
+	queue := workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(time.Second*1, time.Second*30), ControllerName)
+
+	// obtain references to all the informers used by this controller
+	certificateInformer := cmFactory.Certmanager().V1().Certificates()
+	issuerInformer := cmFactory.Certmanager().V1().Issuers()
+	clusterIssuerInformer := cmFactory.Certmanager().V1().ClusterIssuers()
+	secretsInformer := factory.Core().V1().Secrets()
+
+	certificateInformer.Informer().AddEventHandler(&controllerpkg.QueuingEventHandler{Queue: queue})
+
+	secretsInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{
+		// Trigger reconciles on changes to certificates named as spec.secretName
+		WorkFunc: certificates.EnqueueCertificatesForResourceUsingPredicates(log, queue, certificateInformer.Lister(), labels.Everything(),
+			predicate.ExtractResourceName(predicate.CertificateSecretName),
+		),
+	})
+
+	// build a list of InformerSynced functions that will be returned by the Register method.
+	// the controller will only begin processing items once all of these informers have synced.
+	mustSync := []cache.InformerSynced{
+		secretsInformer.Informer().HasSynced,
+		certificateInformer.Informer().HasSynced,
+		issuerInformer.Informer().HasSynced,
+		clusterIssuerInformer.Informer().HasSynced,
+	}
+
+	return &controller{
+		certificateLister: certificateInformer.Lister(),
+		secretLister:      secretsInformer.Lister(),
+		client:            client,
+		coreClient:        coreClient,
+		recorder:          recorder,
+		issuerHelper:      issuer.NewHelper(issuerInformer.Lister(), clusterIssuerInformer.Lister()),
+		accountRegistry:   accountRegistry,
+	}, queue, mustSync
+}
+
+func (c *controller) ProcessItem(ctx context.Context, key string) error {
+	log := logf.FromContext(ctx).WithValues("key", key)
+
+	ctx = logf.NewContext(ctx, log)
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		log.Error(err, "invalid resource key passed to ProcessItem")
+		return nil
+	}
+
+	crt, err := c.certificateLister.Certificates(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		log.V(logf.DebugLevel).Info("certificate not found for key", "error", err.Error())
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	protected := crt.Annotations[cmapi.DeletionProtectionAnnotationKey] == "true"
+	preserveSecret := crt.Annotations[cmapi.PreserveSecretOnDeletionAnnotationKey] == "true"
+	revoke := crt.Annotations[cmapi.RevokeOnDeletionAnnotationKey] == "true"
+	optedIn := protected || preserveSecret || revoke
+	hasFinalizer := hasDeletionProtectionFinalizer(crt)
+
+	if crt.DeletionTimestamp == nil {
+		switch {
+		case optedIn && !hasFinalizer:
+			log.V(logf.DebugLevel).Info("adding deletion protection finalizer")
+			crt = crt.DeepCopy()
+			crt.Finalizers = append(crt.Finalizers, cmapi.CertificateDeletionProtectionFinalizer)
+			_, err := c.client.CertmanagerV1().Certificates(crt.Namespace).Update(ctx, crt, metav1.UpdateOptions{})
+			return err
+		case !optedIn && hasFinalizer:
+			log.V(logf.DebugLevel).Info("removing deletion protection finalizer as the Certificate has opted out")
+			return c.removeFinalizer(ctx, crt)
+		}
+		return nil
+	}
+
+	if !hasFinalizer {
+		return nil
+	}
+
+	if protected {
+		log.V(logf.InfoLevel).Info("deletion of certificate is blocked by deletion protection")
+		c.recorder.Eventf(crt, corev1.EventTypeWarning, "DeletionBlocked",
+			"Deletion is blocked until the %q annotation is removed", cmapi.DeletionProtectionAnnotationKey)
+		return nil
+	}
+
+	if revoke {
+		if err := c.revokeCertificate(ctx, crt); err != nil {
+			return err
+		}
+	}
+
+	if preserveSecret {
+		if err := c.orphanSecret(ctx, crt); err != nil {
+			return err
+		}
+	}
+
+	log.V(logf.DebugLevel).Info("removing deletion protection finalizer to allow deletion")
+	return c.removeFinalizer(ctx, crt)
+}
+
+// orphanSecret removes any owner references to the Certificate from its
+// Secret, so that the Secret is not garbage collected once the Certificate
+// has been deleted.
+func (c *controller) orphanSecret(ctx context.Context, crt *cmapi.Certificate) error {
+	log := logf.FromContext(ctx)
+
+	secret, err := c.secretLister.Secrets(crt.Namespace).Get(crt.Spec.SecretName)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var ownerRefs []metav1.OwnerReference
+	for _, ref := range secret.OwnerReferences {
+		if ref.UID != crt.UID {
+			ownerRefs = append(ownerRefs, ref)
+		}
+	}
+	if len(ownerRefs) == len(secret.OwnerReferences) {
+		return nil
+	}
+
+	secret = secret.DeepCopy()
+	secret.OwnerReferences = ownerRefs
+	if _, err := c.coreClient.CoreV1().Secrets(secret.Namespace).Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to remove owner reference from Secret %q: %w", secret.Name, err)
+	}
+
+	logf.WithRelatedResource(log, secret).V(logf.DebugLevel).Info("removed owner reference to preserve Secret after deletion")
+	c.recorder.Eventf(crt, corev1.EventTypeNormal, "SecretPreserved", "Secret %q will not be deleted with the Certificate", secret.Name)
+	return nil
+}
+
+// revokeCertificate revokes the certificate stored in the Certificate's
+// Secret with the ACME server of the Certificate's issuer. The revocation
+// request is signed with the ACME account key, which the server authorizes
+// if the certificate was issued to that account. Otherwise, the request is
+// retried signed with the certificate's own private key.
+// Certificates that are not issued by an ACME issuer are not revoked.
+func (c *controller) revokeCertificate(ctx context.Context, crt *cmapi.Certificate) error {
+	log := logf.FromContext(ctx)
+
+	secret, err := c.secretLister.Secrets(crt.Namespace).Get(crt.Spec.SecretName)
+	if apierrors.IsNotFound(err) {
+		log.V(logf.DebugLevel).Info("not revoking certificate as its Secret does not exist")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	cert, err := pki.DecodeX509CertificateBytes(secret.Data[corev1.TLSCertKey])
+	if err != nil {
+		log.V(logf.DebugLevel).Info("not revoking certificate as its Secret does not contain a valid certificate", "error", err.Error())
+		return nil
+	}
+
+	issuerObj, err := c.issuerHelper.GetGenericIssuer(crt.Spec.IssuerRef, crt.Namespace)
+	if apierrors.IsNotFound(err) {
+		c.recorder.Eventf(crt, corev1.EventTypeWarning, "RevocationSkipped", "Certificate was not revoked as the issuer %q does not exist", crt.Spec.IssuerRef.Name)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if issuerObj.GetSpec().ACME == nil {
+		c.recorder.Eventf(crt, corev1.EventTypeWarning, "RevocationSkipped", "Certificate was not revoked as the issuer %q is not an ACME issuer", crt.Spec.IssuerRef.Name)
+		return nil
+	}
+
+	cl, err := c.accountRegistry.GetClient(string(issuerObj.GetUID()))
+	if err != nil {
+		return fmt.Errorf("failed to get ACME client for issuer %q: %w", issuerObj.GetName(), err)
+	}
+
+	err = cl.RevokeCert(ctx, nil, cert.Raw, acme.CRLReasonUnspecified)
+	if isACMEProblem(err, unauthorizedError) {
+		log.V(logf.DebugLevel).Info("ACME account is not authorized to revoke the certificate, retrying with the certificate's private key")
+		var key crypto.Signer
+		key, err = c.certificatePrivateKey(crt, secret)
+		if err != nil {
+			return fmt.Errorf("ACME account is not authorized to revoke the certificate and its private key could not be loaded: %w", err)
+		}
+		err = cl.RevokeCert(ctx, key, cert.Raw, acme.CRLReasonUnspecified)
+	}
+	if isACMEProblem(err, alreadyRevokedError) {
+		log.V(logf.DebugLevel).Info("certificate has already been revoked")
+		err = nil
+	}
+	if err != nil {
+		c.recorder.Eventf(crt, corev1.EventTypeWarning, "RevocationFailed", "Failed to revoke certificate: %v", err)
+		return err
+	}
+
+	c.recorder.Eventf(crt, corev1.EventTypeNormal, "Revoked", "Certificate in Secret %q has been revoked", secret.Name)
+	return nil
+}
+
+// certificatePrivateKey returns the private key stored in the given Secret,
+// decrypting it first if the Certificate requests an encrypted private key.
+func (c *controller) certificatePrivateKey(crt *cmapi.Certificate, secret *corev1.Secret) (crypto.Signer, error) {
+	secret, err := certificates.DecryptSecretPrivateKey(c.secretLister, crt, secret)
+	if err != nil {
+		return nil, err
+	}
+	return pki.DecodePrivateKeyBytes(secret.Data[corev1.TLSPrivateKeyKey])
+}
+
+func isACMEProblem(err error, problemType string) bool {
+	var acmeErr *acme.Error
+	return errors.As(err, &acmeErr) && acmeErr.ProblemType == problemType
+}
+
+func (c *controller) removeFinalizer(ctx context.Context, crt *cmapi.Certificate) error {
+	crt = crt.DeepCopy()
+	var finalizers []string
+	for _, f := range crt.Finalizers {
+		if f != cmapi.CertificateDeletionProtectionFinalizer {
+			finalizers = append(finalizers, f)
+		}
+	}
+	crt.Finalizers = finalizers
+	_, err := c.client.CertmanagerV1().Certificates(crt.Namespace).Update(ctx, crt, metav1.UpdateOptions{})
+	return err
+}
+
+func hasDeletionProtectionFinalizer(crt *cmapi.Certificate) bool {
+	for _, f := range crt.Finalizers {
+		if f == cmapi.CertificateDeletionProtectionFinalizer {
+			return true
+		}
+	}
+	return false
+}
+
+// controllerWrapper wraps the `controller` structure to make it implement
+// the controllerpkg.queueingController interface
+type controllerWrapper struct {
+	*controller
+}
+
+func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.RateLimitingInterface, []cache.InformerSynced, error) {
+	// construct a new named logger to be reused throughout the controller
+	log := logf.FromContext(ctx.RootContext, ControllerName)
+
+	ctrl, queue, mustSync := NewController(log,
+		ctx.CMClient,
+		ctx.Client,
+		ctx.KubeSharedInformerFactory,
+		ctx.SharedInformerFactory,
+		ctx.Recorder,
+		ctx.ACMEOptions.AccountRegistry,
+	)
+	c.controller = ctrl
+
+	return queue, mustSync, nil
+}
+
+func init() {
+	controllerpkg.Register(ControllerName, func(ctx *controllerpkg.Context) (controllerpkg.Interface, error) {
+		return controllerpkg.NewBuilder(ctx, ControllerName).
+			For(certificates.NewWorkerPools(&controllerWrapper{})).
+			Workers(certificates.Workers(ctx.CertificateOptions)).
+			Complete()
+	})
+}
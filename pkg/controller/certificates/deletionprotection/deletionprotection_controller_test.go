@@ -0,0 +1,413 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletionprotection
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"errors"
+	"testing"
+
+	"golang.org/x/crypto/acme"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	coretesting "k8s.io/client-go/testing"
+
+	accountstest "github.com/jetstack/cert-manager/pkg/acme/accounts/test"
+	acmecl "github.com/jetstack/cert-manager/pkg/acme/client"
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestProcessItem(t *testing.T) {
+	deletionTimestamp := metav1.Now()
+
+	certificate := func(annotations map[string]string, finalizers []string, deleting bool) *cmapi.Certificate {
+		crt := &cmapi.Certificate{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "testns",
+				Name:        "test",
+				UID:         "test-uid",
+				Annotations: annotations,
+				Finalizers:  finalizers,
+			},
+			Spec: cmapi.CertificateSpec{
+				SecretName: "test-secret",
+				IssuerRef:  cmmeta.ObjectReference{Name: "test-issuer"},
+			},
+		}
+		if deleting {
+			crt.DeletionTimestamp = &deletionTimestamp
+		}
+		return crt
+	}
+	protected := map[string]string{cmapi.DeletionProtectionAnnotationKey: "true"}
+	preserveSecret := map[string]string{cmapi.PreserveSecretOnDeletionAnnotationKey: "true"}
+	revoke := map[string]string{cmapi.RevokeOnDeletionAnnotationKey: "true"}
+	finalizer := []string{cmapi.CertificateDeletionProtectionFinalizer}
+
+	pk, err := pki.GenerateECPrivateKey(pki.ECCurve256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkPEM, err := pki.EncodePrivateKey(pk, cmapi.PKCS8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template, err := pki.GenerateTemplate(gen.Certificate("test", gen.SetCertificateDNSNames("example.com")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM, cert, err := pki.SignCertificate(template, template, pk.Public(), pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tlsSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test-secret"},
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       certPEM,
+			corev1.TLSPrivateKeyKey: pkPEM,
+		},
+	}
+	acmeIssuer := gen.Issuer("test-issuer",
+		gen.SetIssuerNamespace("testns"),
+		gen.SetIssuerACME(cmacme.ACMEIssuer{Server: "https://acme.example.com/directory"}),
+	)
+	caIssuer := gen.Issuer("test-issuer",
+		gen.SetIssuerNamespace("testns"),
+		gen.SetIssuerCA(cmapi.CAIssuer{SecretName: "ca"}),
+	)
+
+	// revokeWithAccountKey accepts revocation requests signed with the ACME
+	// account key
+	revokeWithAccountKey := func(t *testing.T) func(context.Context, crypto.Signer, []byte, acme.CRLReasonCode) error {
+		return func(_ context.Context, key crypto.Signer, der []byte, _ acme.CRLReasonCode) error {
+			if key != nil {
+				t.Errorf("expected revocation to be signed with the account key, but got a certificate key")
+			}
+			if !bytes.Equal(der, cert.Raw) {
+				t.Errorf("unexpected certificate passed to RevokeCert")
+			}
+			return nil
+		}
+	}
+	// revokeWithCertificateKey rejects revocation requests signed with the
+	// ACME account key, as if the certificate was issued to another account
+	revokeWithCertificateKey := func(t *testing.T) func(context.Context, crypto.Signer, []byte, acme.CRLReasonCode) error {
+		return func(_ context.Context, key crypto.Signer, der []byte, _ acme.CRLReasonCode) error {
+			if key == nil {
+				return &acme.Error{ProblemType: unauthorizedError, Detail: "account did not issue the certificate"}
+			}
+			equal, err := pki.PublicKeysEqual(key.Public(), cert.PublicKey)
+			if err != nil || !equal {
+				t.Errorf("expected revocation to be signed with the certificate's private key")
+			}
+			return nil
+		}
+	}
+
+	ownedSecret := func(ownerUIDs ...string) *corev1.Secret {
+		s := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test-secret"}}
+		for _, uid := range ownerUIDs {
+			s.OwnerReferences = append(s.OwnerReferences, metav1.OwnerReference{
+				APIVersion: cmapi.SchemeGroupVersion.String(),
+				Kind:       "Certificate",
+				Name:       "owner-" + uid,
+				UID:        types.UID(uid),
+			})
+		}
+		return s
+	}
+
+	tests := map[string]struct {
+		certificate *cmapi.Certificate
+		secrets     []runtime.Object
+		issuer      runtime.Object
+
+		// revokeCert, if set, is called by the fake ACME client to revoke a
+		// certificate
+		revokeCert          func(t *testing.T) func(context.Context, crypto.Signer, []byte, acme.CRLReasonCode) error
+		expectedRevocations int
+
+		expectedActions []testpkg.Action
+		expectedEvents  []string
+		expectedErr     bool
+	}{
+		"do nothing if the Certificate has not opted in to deletion protection": {
+			certificate: certificate(nil, nil, false),
+		},
+		"add the finalizer if the Certificate has opted in to deletion protection": {
+			certificate: certificate(protected, nil, false),
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewUpdateAction(
+					cmapi.SchemeGroupVersion.WithResource("certificates"),
+					"testns",
+					certificate(protected, finalizer, false),
+				)),
+			},
+		},
+		"add the finalizer if the Certificate has opted in to preserving its Secret": {
+			certificate: certificate(preserveSecret, nil, false),
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewUpdateAction(
+					cmapi.SchemeGroupVersion.WithResource("certificates"),
+					"testns",
+					certificate(preserveSecret, finalizer, false),
+				)),
+			},
+		},
+		"do nothing if a protected Certificate already has the finalizer": {
+			certificate: certificate(protected, finalizer, false),
+		},
+		"keep the finalizer and block deletion of a protected Certificate": {
+			certificate:    certificate(protected, finalizer, true),
+			expectedEvents: []string{`Warning DeletionBlocked Deletion is blocked until the "cert-manager.io/deletion-protection" annotation is removed`},
+		},
+		"remove the finalizer if the Certificate has opted out of deletion protection": {
+			certificate: certificate(nil, finalizer, false),
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewUpdateAction(
+					cmapi.SchemeGroupVersion.WithResource("certificates"),
+					"testns",
+					certificate(nil, nil, false),
+				)),
+			},
+		},
+		"remove the finalizer to allow deletion once the Certificate has opted out of deletion protection": {
+			certificate: certificate(nil, finalizer, true),
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewUpdateAction(
+					cmapi.SchemeGroupVersion.WithResource("certificates"),
+					"testns",
+					certificate(nil, nil, true),
+				)),
+			},
+		},
+		"only remove the deletion protection finalizer from a Certificate being deleted": {
+			certificate: certificate(nil, []string{"example.com/other", cmapi.CertificateDeletionProtectionFinalizer}, true),
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewUpdateAction(
+					cmapi.SchemeGroupVersion.WithResource("certificates"),
+					"testns",
+					certificate(nil, []string{"example.com/other"}, true),
+				)),
+			},
+		},
+		"remove the owner reference from the Secret before allowing deletion if the Secret should be preserved": {
+			certificate: certificate(preserveSecret, finalizer, true),
+			secrets:     []runtime.Object{ownedSecret("test-uid", "other-uid")},
+			expectedEvents: []string{
+				`Normal SecretPreserved Secret "test-secret" will not be deleted with the Certificate`,
+			},
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewUpdateAction(
+					corev1.SchemeGroupVersion.WithResource("secrets"),
+					"testns",
+					ownedSecret("other-uid"),
+				)),
+				testpkg.NewAction(coretesting.NewUpdateAction(
+					cmapi.SchemeGroupVersion.WithResource("certificates"),
+					"testns",
+					certificate(preserveSecret, nil, true),
+				)),
+			},
+		},
+		"add the finalizer if the Certificate has opted in to revocation on deletion": {
+			certificate: certificate(revoke, nil, false),
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewUpdateAction(
+					cmapi.SchemeGroupVersion.WithResource("certificates"),
+					"testns",
+					certificate(revoke, finalizer, false),
+				)),
+			},
+		},
+		"revoke the certificate with the ACME account key before allowing deletion": {
+			certificate:         certificate(revoke, finalizer, true),
+			secrets:             []runtime.Object{tlsSecret},
+			issuer:              acmeIssuer,
+			revokeCert:          revokeWithAccountKey,
+			expectedRevocations: 1,
+			expectedEvents:      []string{`Normal Revoked Certificate in Secret "test-secret" has been revoked`},
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewUpdateAction(
+					cmapi.SchemeGroupVersion.WithResource("certificates"),
+					"testns",
+					certificate(revoke, nil, true),
+				)),
+			},
+		},
+		"revoke the certificate with its private key if the ACME account is not authorized to": {
+			certificate:         certificate(revoke, finalizer, true),
+			secrets:             []runtime.Object{tlsSecret},
+			issuer:              acmeIssuer,
+			revokeCert:          revokeWithCertificateKey,
+			expectedRevocations: 2,
+			expectedEvents:      []string{`Normal Revoked Certificate in Secret "test-secret" has been revoked`},
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewUpdateAction(
+					cmapi.SchemeGroupVersion.WithResource("certificates"),
+					"testns",
+					certificate(revoke, nil, true),
+				)),
+			},
+		},
+		"allow deletion if the certificate has already been revoked": {
+			certificate: certificate(revoke, finalizer, true),
+			secrets:     []runtime.Object{tlsSecret},
+			issuer:      acmeIssuer,
+			revokeCert: func(t *testing.T) func(context.Context, crypto.Signer, []byte, acme.CRLReasonCode) error {
+				return func(context.Context, crypto.Signer, []byte, acme.CRLReasonCode) error {
+					return &acme.Error{ProblemType: alreadyRevokedError}
+				}
+			},
+			expectedRevocations: 1,
+			expectedEvents:      []string{`Normal Revoked Certificate in Secret "test-secret" has been revoked`},
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewUpdateAction(
+					cmapi.SchemeGroupVersion.WithResource("certificates"),
+					"testns",
+					certificate(revoke, nil, true),
+				)),
+			},
+		},
+		"keep the finalizer and retry if revoking the certificate fails": {
+			certificate: certificate(revoke, finalizer, true),
+			secrets:     []runtime.Object{tlsSecret},
+			issuer:      acmeIssuer,
+			revokeCert: func(t *testing.T) func(context.Context, crypto.Signer, []byte, acme.CRLReasonCode) error {
+				return func(context.Context, crypto.Signer, []byte, acme.CRLReasonCode) error {
+					return errors.New("connection refused")
+				}
+			},
+			expectedRevocations: 1,
+			expectedEvents:      []string{`Warning RevocationFailed Failed to revoke certificate: connection refused`},
+			expectedErr:         true,
+		},
+		"allow deletion without revoking if the issuer is not an ACME issuer": {
+			certificate:    certificate(revoke, finalizer, true),
+			secrets:        []runtime.Object{tlsSecret},
+			issuer:         caIssuer,
+			expectedEvents: []string{`Warning RevocationSkipped Certificate was not revoked as the issuer "test-issuer" is not an ACME issuer`},
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewUpdateAction(
+					cmapi.SchemeGroupVersion.WithResource("certificates"),
+					"testns",
+					certificate(revoke, nil, true),
+				)),
+			},
+		},
+		"allow deletion without revoking if the Secret does not exist": {
+			certificate: certificate(revoke, finalizer, true),
+			issuer:      acmeIssuer,
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewUpdateAction(
+					cmapi.SchemeGroupVersion.WithResource("certificates"),
+					"testns",
+					certificate(revoke, nil, true),
+				)),
+			},
+		},
+		"allow deletion if the Secret to preserve is not owned by the Certificate": {
+			certificate: certificate(preserveSecret, finalizer, true),
+			secrets:     []runtime.Object{ownedSecret()},
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewUpdateAction(
+					cmapi.SchemeGroupVersion.WithResource("certificates"),
+					"testns",
+					certificate(preserveSecret, nil, true),
+				)),
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cmObjects := []runtime.Object{test.certificate}
+			if test.issuer != nil {
+				cmObjects = append(cmObjects, test.issuer)
+			}
+			// Create and initialise a new unit test builder
+			builder := &testpkg.Builder{
+				T:                  t,
+				CertManagerObjects: cmObjects,
+				KubeObjects:        test.secrets,
+				ExpectedEvents:     test.expectedEvents,
+				ExpectedActions:    test.expectedActions,
+			}
+			builder.Init()
+
+			// Register informers used by the controller using the registration wrapper
+			w := &controllerWrapper{}
+			_, _, err := w.Register(builder.Context)
+			if err != nil {
+				t.Fatal(err)
+			}
+			revocations := 0
+			w.controller.accountRegistry = &accountstest.FakeRegistry{
+				GetClientFunc: func(string) (acmecl.Interface, error) {
+					return &acmecl.FakeACME{
+						FakeRevokeCert: func(ctx context.Context, key crypto.Signer, der []byte, reason acme.CRLReasonCode) error {
+							revocations++
+							if test.revokeCert == nil {
+								t.Errorf("unexpected call to RevokeCert")
+								return nil
+							}
+							return test.revokeCert(t)(ctx, key, der, reason)
+						},
+					}, nil
+				},
+			}
+			// Start the informers and begin processing updates
+			builder.Start()
+			defer builder.Stop()
+
+			key, err := controllerpkg.KeyFunc(test.certificate)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = w.controller.ProcessItem(context.Background(), key)
+			if err != nil && !test.expectedErr {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if err == nil && test.expectedErr {
+				t.Errorf("expected an error but got none")
+			}
+			if revocations != test.expectedRevocations {
+				t.Errorf("expected %d calls to RevokeCert but got %d", test.expectedRevocations, revocations)
+			}
+
+			if err := builder.AllEventsCalled(); err != nil {
+				builder.T.Error(err)
+			}
+			if err := builder.AllActionsExecuted(); err != nil {
+				builder.T.Error(err)
+			}
+			if err := builder.AllReactorsCalled(); err != nil {
+				builder.T.Error(err)
+			}
+		})
+	}
+}
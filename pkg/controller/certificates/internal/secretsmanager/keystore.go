@@ -26,6 +26,7 @@ import (
 	"bytes"
 	"crypto/rand"
 	"crypto/x509"
+	"fmt"
 	"time"
 
 	jks "github.com/pavel-v-chernykh/keystore-go"
@@ -53,7 +54,9 @@ const (
 // If the certificate data contains multiple certificates, the first will be used
 // as the keystores 'certificate' and the remaining certificates will be prepended
 // to the list of CAs in the resulting keystore.
-func encodePKCS12Keystore(password string, rawKey []byte, certPem []byte, caPem []byte) ([]byte, error) {
+// If includeCAChain is true, certificates that appear more than once in the
+// chain are only stored once.
+func encodePKCS12Keystore(password string, rawKey []byte, certPem []byte, caPem []byte, includeCAChain bool) ([]byte, error) {
 	key, err := pki.DecodePrivateKeyBytes(rawKey)
 	if err != nil {
 		return nil, err
@@ -74,6 +77,9 @@ func encodePKCS12Keystore(password string, rawKey []byte, certPem []byte, caPem
 	if len(certs) > 1 {
 		cas = append(certs[1:], cas...)
 	}
+	if includeCAChain {
+		cas = dedupeCertificates(certs[0], cas)
+	}
 	return pkcs12.Encode(rand.Reader, key, certs[0], cas, password)
 }
 
@@ -87,6 +93,52 @@ func encodePKCS12Truststore(password string, caPem []byte) ([]byte, error) {
 	return pkcs12.EncodeTrustStore(rand.Reader, cas, password)
 }
 
+// encodePKCS12ChainTruststore will encode a PKCS12 truststore containing
+// every CA certificate in the chain as a trusted entry: the non-leaf
+// certificates in certPem followed by all certificates in caPem.
+// It returns nil if the chain contains no CA certificates.
+func encodePKCS12ChainTruststore(password string, certPem []byte, caPem []byte) ([]byte, error) {
+	certs, err := pki.DecodeX509CertificateChainBytes(certPem)
+	if err != nil {
+		return nil, err
+	}
+	cas := certs[1:]
+	if len(caPem) > 0 {
+		caCerts, err := pki.DecodeX509CertificateChainBytes(caPem)
+		if err != nil {
+			return nil, err
+		}
+		cas = append(cas, caCerts...)
+	}
+	cas = dedupeCertificates(certs[0], cas)
+	if len(cas) == 0 {
+		return nil, nil
+	}
+	return pkcs12.EncodeTrustStore(rand.Reader, cas, password)
+}
+
+// dedupeCertificates returns certs with any certificate equal to leaf or to
+// an earlier entry removed, preserving the order of the remaining entries.
+func dedupeCertificates(leaf *x509.Certificate, certs []*x509.Certificate) []*x509.Certificate {
+	var out []*x509.Certificate
+	for _, cert := range certs {
+		if cert.Equal(leaf) {
+			continue
+		}
+		dup := false
+		for _, o := range out {
+			if cert.Equal(o) {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			out = append(out, cert)
+		}
+	}
+	return out
+}
+
 func encodeJKSKeystore(password []byte, rawKey []byte, certPem []byte, caPem []byte) ([]byte, error) {
 	// encode the private key to PKCS8
 	key, err := pki.DecodePrivateKeyBytes(rawKey)
@@ -145,14 +197,23 @@ func encodeJKSKeystore(password []byte, rawKey []byte, certPem []byte, caPem []b
 	return buf.Bytes(), nil
 }
 
+// encodeJKSTruststore will encode a JKS truststore containing each of the
+// certificates in caPem as a trusted certificate entry, and no private key.
+// The first certificate is stored under the alias 'ca', subsequent
+// certificates under 'ca-1', 'ca-2' and so on.
 func encodeJKSTruststore(password []byte, caPem []byte) ([]byte, error) {
-	ca, err := pki.DecodeX509CertificateBytes(caPem)
+	cas, err := pki.DecodeX509CertificateChainBytes(caPem)
 	if err != nil {
 		return nil, err
 	}
 
-	ks := jks.KeyStore{
-		"ca": &jks.TrustedCertificateEntry{
+	ks := jks.KeyStore{}
+	for i, ca := range cas {
+		alias := "ca"
+		if i > 0 {
+			alias = fmt.Sprintf("ca-%d", i)
+		}
+		ks[alias] = &jks.TrustedCertificateEntry{
 			Entry: jks.Entry{
 				CreationDate: time.Now(),
 			},
@@ -160,7 +221,7 @@ func encodeJKSTruststore(password []byte, caPem []byte) ([]byte, error) {
 				Type:    "X509",
 				Content: ca.Raw,
 			},
-		},
+		}
 	}
 
 	buf := &bytes.Buffer{}
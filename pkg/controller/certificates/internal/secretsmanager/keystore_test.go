@@ -225,6 +225,49 @@ func TestEncodeJKSKeystore(t *testing.T) {
 	}
 }
 
+func TestEncodeJKSTruststore(t *testing.T) {
+	const password = "password"
+
+	t.Run("encodes the CA as a trusted certificate entry without a private key", func(t *testing.T) {
+		caPEM := mustSelfSignCertificate(t, nil)
+		ca, err := pki.DecodeX509CertificateBytes(caPEM)
+		require.NoError(t, err)
+
+		out, err := encodeJKSTruststore([]byte(password), caPEM)
+		require.NoError(t, err)
+
+		ks, err := jks.Decode(bytes.NewBuffer(out), []byte(password))
+		require.NoError(t, err)
+		if assert.Len(t, ks, 1, "truststore should contain a single entry") {
+			entry, ok := ks["ca"].(*jks.TrustedCertificateEntry)
+			if assert.True(t, ok, "ca entry should be a trusted certificate entry") {
+				assert.Equal(t, ca.Raw, entry.Certificate.Content, "trusted CA certificate does not match")
+			}
+		}
+	})
+	t.Run("encodes every certificate in the CA bundle as a trusted certificate entry", func(t *testing.T) {
+		chain := mustLeafWithChain(t)
+
+		out, err := encodeJKSTruststore([]byte(password), chain.cas.certsToPEM())
+		require.NoError(t, err)
+
+		ks, err := jks.Decode(bytes.NewBuffer(out), []byte(password))
+		require.NoError(t, err)
+		if assert.Len(t, ks, 2, "truststore should contain the intermediate and top-level certificates") {
+			for alias, kc := range map[string]*keyAndCert{"ca": chain.cas[0], "ca-1": chain.cas[1]} {
+				entry, ok := ks[alias].(*jks.TrustedCertificateEntry)
+				if assert.True(t, ok, "%s entry should be a trusted certificate entry", alias) {
+					assert.Equal(t, kc.cert.Raw, entry.Certificate.Content, "%s certificate does not match", alias)
+				}
+			}
+		}
+		for alias, entry := range ks {
+			_, isKey := entry.(*jks.PrivateKeyEntry)
+			assert.False(t, isKey, "truststore should not contain a private key, found one under %q", alias)
+		}
+	})
+}
+
 func TestEncodePKCS12Keystore(t *testing.T) {
 	tests := map[string]struct {
 		password               string
@@ -302,7 +345,7 @@ func TestEncodePKCS12Keystore(t *testing.T) {
 	}
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			out, err := encodePKCS12Keystore(test.password, test.rawKey, test.certPEM, test.caPEM)
+			out, err := encodePKCS12Keystore(test.password, test.rawKey, test.certPEM, test.caPEM, false)
 			test.verify(t, out, err)
 		})
 	}
@@ -311,7 +354,7 @@ func TestEncodePKCS12Keystore(t *testing.T) {
 		var emptyCAChain []byte = nil
 
 		chain := mustLeafWithChain(t)
-		out, err := encodePKCS12Keystore(password, chain.leaf.keyPEM, chain.all.certsToPEM(), emptyCAChain)
+		out, err := encodePKCS12Keystore(password, chain.leaf.keyPEM, chain.all.certsToPEM(), emptyCAChain, false)
 		require.NoError(t, err)
 
 		pkOut, certOut, caChain, err := pkcs12.DecodeChain(out, password)
@@ -330,7 +373,7 @@ func TestEncodePKCS12Keystore(t *testing.T) {
 		require.NoError(t, err)
 
 		chain := mustLeafWithChain(t)
-		out, err := encodePKCS12Keystore(password, chain.leaf.keyPEM, chain.all.certsToPEM(), caChainInPEM)
+		out, err := encodePKCS12Keystore(password, chain.leaf.keyPEM, chain.all.certsToPEM(), caChainInPEM, false)
 		require.NoError(t, err)
 
 		pkOut, certOut, caChainOut, err := pkcs12.DecodeChain(out, password)
@@ -343,6 +386,27 @@ func TestEncodePKCS12Keystore(t *testing.T) {
 			assert.Equal(t, caChainIn, caChainOut[2:], "supplied certificate chain is not at the end of the chain")
 		}
 	})
+	t.Run("encodePKCS12Keystore stores each certificate of the chain once if includeCAChain is true", func(t *testing.T) {
+		const password = "password"
+		chain := mustLeafWithChain(t)
+		extraCAPEM := mustSelfSignCertificate(t, nil)
+		extraCA, err := pki.DecodeX509CertificateBytes(extraCAPEM)
+		require.NoError(t, err)
+		caChainInPEM := append(certChain{chain.cas[1]}.certsToPEM(), extraCAPEM...)
+
+		out, err := encodePKCS12Keystore(password, chain.leaf.keyPEM, chain.all.certsToPEM(), caChainInPEM, true)
+		require.NoError(t, err)
+
+		pkOut, certOut, caChainOut, err := pkcs12.DecodeChain(out, password)
+		require.NoError(t, err)
+		assert.NotNil(t, pkOut)
+		assert.Equal(t, chain.leaf.cert.Signature, certOut.Signature, "leaf certificate signature does not match")
+		if assert.Len(t, caChainOut, 3, "caChain should contain 3 items: intermediate certificate, top-level certificate and supplied CA") {
+			assert.Equal(t, chain.cas[0].cert.Signature, caChainOut[0].Signature, "intermediate certificate signature does not match")
+			assert.Equal(t, chain.cas[1].cert.Signature, caChainOut[1].Signature, "top-level certificate signature does not match")
+			assert.Equal(t, extraCA.Signature, caChainOut[2].Signature, "supplied CA certificate signature does not match")
+		}
+	})
 }
 
 func TestEncodePKCS12Truststore(t *testing.T) {
@@ -382,3 +446,31 @@ func TestEncodePKCS12Truststore(t *testing.T) {
 		})
 	}
 }
+
+func TestEncodePKCS12ChainTruststore(t *testing.T) {
+	const password = "password"
+
+	t.Run("encodes every CA certificate of the chain as a trusted entry", func(t *testing.T) {
+		chain := mustLeafWithChain(t)
+		extraCAPEM := mustSelfSignCertificate(t, nil)
+		extraCA, err := pki.DecodeX509CertificateBytes(extraCAPEM)
+		require.NoError(t, err)
+		caChainInPEM := append(certChain{chain.cas[1]}.certsToPEM(), extraCAPEM...)
+
+		out, err := encodePKCS12ChainTruststore(password, chain.all.certsToPEM(), caChainInPEM)
+		require.NoError(t, err)
+
+		certs, err := pkcs12.DecodeTrustStore(out, password)
+		require.NoError(t, err)
+		if assert.Len(t, certs, 3, "Trusted CA certificates should include intermediate, top-level and supplied CA certificates") {
+			assert.Equal(t, chain.cas[0].cert.Signature, certs[0].Signature, "intermediate certificate signature does not match")
+			assert.Equal(t, chain.cas[1].cert.Signature, certs[1].Signature, "top-level certificate signature does not match")
+			assert.Equal(t, extraCA.Signature, certs[2].Signature, "supplied CA certificate signature does not match")
+		}
+	})
+	t.Run("returns no data if the chain contains no CA certificates", func(t *testing.T) {
+		out, err := encodePKCS12ChainTruststore(password, mustSelfSignCertificate(t, nil), nil)
+		require.NoError(t, err)
+		assert.Nil(t, out)
+	})
+}
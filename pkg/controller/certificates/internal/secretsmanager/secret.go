@@ -19,18 +19,23 @@ package secretsmanager
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	corelisters "k8s.io/client-go/listers/core/v1"
 
 	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates"
 	utilpki "github.com/jetstack/cert-manager/pkg/util/pki"
 )
 
@@ -38,6 +43,42 @@ var (
 	certificateGvk = cmapi.SchemeGroupVersion.WithKind("Certificate")
 )
 
+const (
+	// derSecretKey is the name of the data entry in the Secret resource
+	// used to store the DER encoded leaf certificate.
+	derSecretKey = "tls.der"
+)
+
+var (
+	// managedDataKeys are the data entries of a Secret resource that are
+	// written by the SecretsManager.
+	managedDataKeys = []string{
+		corev1.TLSPrivateKeyKey,
+		corev1.TLSCertKey,
+		cmmeta.TLSCAKey,
+		pkcs12SecretKey,
+		pkcs12TruststoreKey,
+		jksSecretKey,
+		jksTruststoreKey,
+		derSecretKey,
+	}
+
+	// managedAnnotationKeys are the annotations on a Secret resource that are
+	// written by the SecretsManager.
+	managedAnnotationKeys = []string{
+		cmapi.CertificateNameKey,
+		cmapi.IssuerNameAnnotationKey,
+		cmapi.IssuerKindAnnotationKey,
+		cmapi.IssuerGroupAnnotationKey,
+		cmapi.CommonNameAnnotationKey,
+		cmapi.AltNamesAnnotationKey,
+		cmapi.IPSANAnnotationKey,
+		cmapi.URISANAnnotationKey,
+		cmapi.CertificateNotBeforeAnnotationKey,
+		cmapi.CertificateNotAfterAnnotationKey,
+	}
+)
+
 type SecretsManager struct {
 	kubeClient   kubernetes.Interface
 	secretLister corelisters.SecretLister
@@ -68,22 +109,26 @@ func New(
 
 // UpdateData will ensure the Secret resource contains the given secret
 // data as well as appropriate metadata.
-// If the Secret resource does not exist, it will be created.
-// Otherwise, the existing resource will be updated.
-// The first return argument will be true if the resource was updated/created
-// without error.
-// UpdateData will also update deprecated annotations if they exist.
+// The Secret resource is written using server-side apply, so it will be
+// created if it does not exist. Only the fields managed by cert-manager are
+// applied, leaving data and annotations set by other field managers intact.
+// Fields that cert-manager no longer writes are removed explicitly first, as
+// leaving them out of the apply patch does not remove them if they are owned
+// by another field manager, such as the one recorded for Secrets written by
+// older versions of cert-manager using Update.
+// No write is made if the existing Secret resource is already up to date.
 func (s *SecretsManager) UpdateData(ctx context.Context, crt *cmapi.Certificate, data SecretData) error {
-	// Fetch a copy of the existing Secret resource
-	secret, err := s.secretLister.Secrets(crt.Namespace).Get(crt.Spec.SecretName)
+	existing, err := s.secretLister.Secrets(crt.Namespace).Get(crt.Spec.SecretName)
 	if !apierrors.IsNotFound(err) && err != nil {
 		// If secret doesn't exist yet, then don't error
 		return err
 	}
-	secretExists := (secret != nil)
 
-	// If the seret does not exist yet, then we need to create one
-	if !secretExists {
+	var secret *corev1.Secret
+	if existing != nil {
+		// Copy the existing Secret resource to avoid modifying data in-cache
+		secret = existing.DeepCopy()
+	} else {
 		secret = &corev1.Secret{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      crt.Spec.SecretName,
@@ -93,28 +138,189 @@ func (s *SecretsManager) UpdateData(ctx context.Context, crt *cmapi.Certificate,
 		}
 	}
 
-	// secret will be overwritten by 'existingSecret' if existingSecret is non-nil
-	if s.enableSecretOwnerReferences {
-		secret.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(crt, certificateGvk)}
-	}
+	s.setOwnerReference(crt, secret)
 
 	err = s.setValues(crt, secret, data)
 	if err != nil {
 		return err
 	}
 
-	// If secret does not exist then create it
-	if !secretExists {
+	// Avoid writing to the apiserver if nothing has changed, so that repeated
+	// reconciles of the same Certificate do not result in no-op writes.
+	if existing != nil && secretUpToDate(existing, secret) {
+		return nil
+	}
 
-		_, err = s.kubeClient.CoreV1().Secrets(secret.Namespace).Create(ctx, secret, metav1.CreateOptions{})
-		return err
+	if existing != nil {
+		if patch := removedFieldsPatch(crt, existing, secret); patch != nil {
+			patchData, err := json.Marshal(patch)
+			if err != nil {
+				return fmt.Errorf("failed to encode Secret patch: %w", err)
+			}
+			_, err = s.kubeClient.CoreV1().Secrets(secret.Namespace).Patch(ctx, secret.Name, types.StrategicMergePatchType, patchData, metav1.PatchOptions{
+				FieldManager: certificates.SecretApplyFieldManager,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to remove stale fields from Secret: %w", err)
+			}
+		}
 	}
 
-	// Currently we are always updating. We should devise a way to not have to call an update if it is not necessary.
-	_, err = s.kubeClient.CoreV1().Secrets(secret.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
+	applyData, err := json.Marshal(applyConfiguration(crt, secret))
+	if err != nil {
+		return fmt.Errorf("failed to encode Secret apply configuration: %w", err)
+	}
+
+	force := true
+	_, err = s.kubeClient.CoreV1().Secrets(secret.Namespace).Patch(ctx, secret.Name, types.ApplyPatchType, applyData, metav1.PatchOptions{
+		FieldManager: certificates.SecretApplyFieldManager,
+		Force:        &force,
+	})
 	return err
 }
 
+// secretUpToDate returns true if the desired Secret resource does not differ
+// from the existing one in any of the fields written by the SecretsManager.
+func secretUpToDate(existing, desired *corev1.Secret) bool {
+	return existing.Type == desired.Type &&
+		apiequality.Semantic.DeepEqual(existing.Data, desired.Data) &&
+		apiequality.Semantic.DeepEqual(existing.Annotations, desired.Annotations) &&
+		apiequality.Semantic.DeepEqual(existing.OwnerReferences, desired.OwnerReferences)
+}
+
+// applyConfiguration returns the subset of the given Secret resource that is
+// managed by cert-manager, to be sent as a server-side apply patch.
+// Data, annotations and owner references that are not written by cert-manager
+// are left out, so that cert-manager does not take ownership of them.
+func applyConfiguration(crt *cmapi.Certificate, secret *corev1.Secret) *corev1.Secret {
+	cfg := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        secret.Name,
+			Namespace:   secret.Namespace,
+			Annotations: make(map[string]string),
+		},
+		Data: make(map[string][]byte),
+		Type: secret.Type,
+	}
+
+	for _, k := range managedDataKeys {
+		if v, ok := secret.Data[k]; ok {
+			// nil values are encoded as null, which would remove the entry
+			if v == nil {
+				v = []byte{}
+			}
+			cfg.Data[k] = v
+		}
+	}
+	for _, k := range managedAnnotationKeys {
+		if v, ok := secret.Annotations[k]; ok {
+			cfg.Annotations[k] = v
+		}
+	}
+	for _, ref := range secret.OwnerReferences {
+		if ref.UID == crt.UID {
+			cfg.OwnerReferences = append(cfg.OwnerReferences, ref)
+		}
+	}
+
+	return cfg
+}
+
+// removedFieldsPatch returns a strategic merge patch removing the data
+// entries, annotations and owner reference written by cert-manager that are
+// present on the existing Secret resource but not on the desired one, or nil
+// if there is nothing to remove.
+func removedFieldsPatch(crt *cmapi.Certificate, existing, desired *corev1.Secret) map[string]interface{} {
+	data := make(map[string]interface{})
+	for _, k := range managedDataKeys {
+		if _, ok := existing.Data[k]; !ok {
+			continue
+		}
+		if _, ok := desired.Data[k]; !ok {
+			data[k] = nil
+		}
+	}
+
+	annotations := make(map[string]interface{})
+	for _, k := range managedAnnotationKeys {
+		if _, ok := existing.Annotations[k]; !ok {
+			continue
+		}
+		if _, ok := desired.Annotations[k]; !ok {
+			annotations[k] = nil
+		}
+	}
+
+	var ownerRefs []interface{}
+	for _, ref := range existing.OwnerReferences {
+		if ref.UID != crt.UID || hasOwnerReference(desired, ref.UID) {
+			continue
+		}
+		ownerRefs = append(ownerRefs, map[string]interface{}{
+			"$patch": "delete",
+			"uid":    string(ref.UID),
+		})
+	}
+
+	if len(data) == 0 && len(annotations) == 0 && len(ownerRefs) == 0 {
+		return nil
+	}
+
+	metadata := make(map[string]interface{})
+	if len(annotations) > 0 {
+		metadata["annotations"] = annotations
+	}
+	if len(ownerRefs) > 0 {
+		metadata["ownerReferences"] = ownerRefs
+	}
+	patch := map[string]interface{}{"metadata": metadata}
+	if len(data) > 0 {
+		patch["data"] = data
+	}
+	return patch
+}
+
+// hasOwnerReference returns true if the given Secret has an owner reference
+// with the given UID.
+func hasOwnerReference(secret *corev1.Secret, uid types.UID) bool {
+	for _, ref := range secret.OwnerReferences {
+		if ref.UID == uid {
+			return true
+		}
+	}
+	return false
+}
+
+// setOwnerReference sets or removes the owner reference to the Certificate on
+// the given Secret. The SecretOwnerReferenceAnnotationKey annotation on the
+// Certificate takes precedence over the enableSecretOwnerReferences option.
+// Owner references are left unchanged if neither enables them, for
+// compatibility with Secrets written by older versions.
+func (s *SecretsManager) setOwnerReference(crt *cmapi.Certificate, secret *corev1.Secret) {
+	enabled := s.enableSecretOwnerReferences
+	switch crt.Annotations[cmapi.SecretOwnerReferenceAnnotationKey] {
+	case "true":
+		enabled = true
+	case "false":
+		var ownerRefs []metav1.OwnerReference
+		for _, ref := range secret.OwnerReferences {
+			if ref.UID != crt.UID {
+				ownerRefs = append(ownerRefs, ref)
+			}
+		}
+		secret.OwnerReferences = ownerRefs
+		return
+	}
+
+	if enabled {
+		secret.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(crt, certificateGvk)}
+	}
+}
+
 // setValues will update the Secret resource 'secret' with the data contained
 // in the given secretData.
 // It will update labels and annotations on the Secret resource appropriately.
@@ -130,10 +336,19 @@ func (s *SecretsManager) setValues(crt *cmapi.Certificate, secret *corev1.Secret
 		secret.Data = make(map[string][]byte)
 	}
 
+	passphrase, err := certificates.PrivateKeyPassphrase(s.secretLister, crt)
+	if err != nil {
+		return err
+	}
+
+	// The private key may be stored encrypted, so it is compared with the new
+	// private key in its unencrypted form.
+	privateKeyChanged := !bytes.Equal(decryptedPrivateKey(crt, secret.Data[corev1.TLSPrivateKeyKey], passphrase), data.PrivateKey)
+
 	// Only write a new PKCS12/JKS file if any of the private key/certificate/CA
 	// data has actually changed.
 	if data.PrivateKey != nil && data.Certificate != nil &&
-		(!bytes.Equal(secret.Data[corev1.TLSPrivateKeyKey], data.PrivateKey) ||
+		(privateKeyChanged ||
 			!bytes.Equal(secret.Data[corev1.TLSCertKey], data.Certificate) ||
 			!bytes.Equal(secret.Data[cmmeta.TLSCAKey], data.CA)) {
 
@@ -148,14 +363,25 @@ func (s *SecretsManager) setValues(crt *cmapi.Certificate, secret *corev1.Secret
 				return fmt.Errorf("PKCS12 keystore password Secret contains no data for key %q", ref.Key)
 			}
 			pw := pwSecret.Data[ref.Key]
-			keystoreData, err := encodePKCS12Keystore(string(pw), data.PrivateKey, data.Certificate, data.CA)
+			includeCAChain := crt.Spec.Keystores.PKCS12.IncludeCAChain
+			keystoreData, err := encodePKCS12Keystore(string(pw), data.PrivateKey, data.Certificate, data.CA, includeCAChain)
 			if err != nil {
 				return fmt.Errorf("error encoding PKCS12 bundle: %w", err)
 			}
 			// always overwrite the keystore entry for now
 			secret.Data[pkcs12SecretKey] = keystoreData
 
-			if len(data.CA) > 0 {
+			if includeCAChain {
+				truststoreData, err := encodePKCS12ChainTruststore(string(pw), data.Certificate, data.CA)
+				if err != nil {
+					return fmt.Errorf("error encoding PKCS12 trust store bundle: %w", err)
+				}
+				if truststoreData != nil {
+					secret.Data[pkcs12TruststoreKey] = truststoreData
+				} else {
+					delete(secret.Data, pkcs12TruststoreKey)
+				}
+			} else if len(data.CA) > 0 {
 				truststoreData, err := encodePKCS12Truststore(string(pw), data.CA)
 				if err != nil {
 					return fmt.Errorf("error encoding PKCS12 trust store bundle: %w", err)
@@ -191,8 +417,10 @@ func (s *SecretsManager) setValues(crt *cmapi.Certificate, secret *corev1.Secret
 				if err != nil {
 					return fmt.Errorf("error encoding JKS trust store bundle: %w", err)
 				}
-				// always overwrite the keystore entry
+				// always overwrite the truststore entry
 				secret.Data[jksTruststoreKey] = truststoreData
+			} else {
+				delete(secret.Data, jksTruststoreKey)
 			}
 		} else {
 			delete(secret.Data, jksSecretKey)
@@ -200,7 +428,19 @@ func (s *SecretsManager) setValues(crt *cmapi.Certificate, secret *corev1.Secret
 		}
 	}
 
-	secret.Data[corev1.TLSPrivateKeyKey] = data.PrivateKey
+	switch {
+	case passphrase == nil || len(data.PrivateKey) == 0:
+		secret.Data[corev1.TLSPrivateKeyKey] = data.PrivateKey
+	case !privateKeyChanged && utilpki.IsEncryptedPKCS8PrivateKey(secret.Data[corev1.TLSPrivateKeyKey]):
+		// Keep the existing encrypted private key, as encrypting it again
+		// uses a new salt and IV, which would change the Secret on every sync.
+	default:
+		pkData, err := encryptPrivateKey(data.PrivateKey, passphrase)
+		if err != nil {
+			return err
+		}
+		secret.Data[corev1.TLSPrivateKeyKey] = pkData
+	}
 	secret.Data[corev1.TLSCertKey] = data.Certificate
 	if len(data.CA) > 0 {
 		secret.Data[cmmeta.TLSCAKey] = data.CA
@@ -208,6 +448,10 @@ func (s *SecretsManager) setValues(crt *cmapi.Certificate, secret *corev1.Secret
 		delete(secret.Data, cmmeta.TLSCAKey)
 	}
 
+	if err := setAdditionalOutputFormats(crt, secret, data); err != nil {
+		return err
+	}
+
 	if secret.Annotations == nil {
 		secret.Annotations = make(map[string]string)
 	}
@@ -223,6 +467,8 @@ func (s *SecretsManager) setValues(crt *cmapi.Certificate, secret *corev1.Secret
 		delete(secret.Annotations, cmapi.AltNamesAnnotationKey)
 		delete(secret.Annotations, cmapi.IPSANAnnotationKey)
 		delete(secret.Annotations, cmapi.URISANAnnotationKey)
+		delete(secret.Annotations, cmapi.CertificateNotBeforeAnnotationKey)
+		delete(secret.Annotations, cmapi.CertificateNotAfterAnnotationKey)
 	} else {
 		x509Cert, err := utilpki.DecodeX509CertificateBytes(data.Certificate)
 		// TODO: handle InvalidData here?
@@ -234,7 +480,77 @@ func (s *SecretsManager) setValues(crt *cmapi.Certificate, secret *corev1.Secret
 		secret.Annotations[cmapi.AltNamesAnnotationKey] = strings.Join(x509Cert.DNSNames, ",")
 		secret.Annotations[cmapi.IPSANAnnotationKey] = strings.Join(utilpki.IPAddressesToString(x509Cert.IPAddresses), ",")
 		secret.Annotations[cmapi.URISANAnnotationKey] = strings.Join(utilpki.URLsToString(x509Cert.URIs), ",")
+		secret.Annotations[cmapi.CertificateNotBeforeAnnotationKey] = x509Cert.NotBefore.UTC().Format(time.RFC3339)
+		secret.Annotations[cmapi.CertificateNotAfterAnnotationKey] = x509Cert.NotAfter.UTC().Format(time.RFC3339)
+	}
+
+	return nil
+}
+
+// encryptPrivateKey will encrypt the given PEM encoded private key with the
+// given passphrase.
+func encryptPrivateKey(pkData, passphrase []byte) ([]byte, error) {
+	pk, err := utilpki.DecodePrivateKeyBytes(pkData)
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted, err := utilpki.EncryptPKCS8PrivateKey(pk, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("error encrypting private key: %w", err)
+	}
+
+	return encrypted, nil
+}
+
+// decryptedPrivateKey returns the given PEM encoded private key in the
+// encoding requested by the Certificate if it is encrypted, so that it can be
+// compared with an unencrypted private key.
+// Unencrypted private keys are returned unmodified. nil is returned if the
+// private key cannot be decrypted with the given passphrase.
+func decryptedPrivateKey(crt *cmapi.Certificate, pkData, passphrase []byte) []byte {
+	if !utilpki.IsEncryptedPKCS8PrivateKey(pkData) {
+		return pkData
+	}
+	if passphrase == nil {
+		return nil
+	}
+
+	pk, err := utilpki.DecryptPKCS8PrivateKey(pkData, passphrase)
+	if err != nil {
+		return nil
+	}
+
+	decrypted, err := utilpki.EncodePrivateKey(pk, crt.Spec.PrivateKey.Encoding)
+	if err != nil {
+		return nil
+	}
+
+	return decrypted
+}
+
+// setAdditionalOutputFormats will write any additional output formats
+// requested by the Certificate to the Secret, and remove those that are no
+// longer requested.
+func setAdditionalOutputFormats(crt *cmapi.Certificate, secret *corev1.Secret, data SecretData) error {
+	var der bool
+	for _, f := range crt.Spec.AdditionalOutputFormats {
+		if f.Type == cmapi.CertificateOutputFormatDER {
+			der = true
+		}
+	}
+
+	if !der || len(data.Certificate) == 0 {
+		delete(secret.Data, derSecretKey)
+		return nil
+	}
+
+	// the leaf certificate is the first in the PEM encoded chain
+	x509Cert, err := utilpki.DecodeX509CertificateBytes(data.Certificate)
+	if err != nil {
+		return fmt.Errorf("error decoding certificate for DER output format: %w", err)
 	}
+	secret.Data[derSecretKey] = x509Cert.Raw
 
 	return nil
 }
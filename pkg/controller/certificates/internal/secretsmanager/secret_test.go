@@ -17,7 +17,13 @@ limitations under the License.
 package secretsmanager
 
 import (
+	"bytes"
 	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -25,6 +31,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	coretesting "k8s.io/client-go/testing"
 	fakeclock "k8s.io/utils/clock/testing"
 
@@ -83,7 +90,7 @@ func TestSecretsManager(t *testing.T) {
 			builder: &testpkg.Builder{
 				KubeObjects: []runtime.Object{},
 				ExpectedActions: []testpkg.Action{
-					testpkg.NewAction(coretesting.NewCreateAction(
+					testpkg.NewApplyAction(
 						corev1.SchemeGroupVersion.WithResource("secrets"),
 						gen.DefaultTestNamespace,
 						&corev1.Secret{
@@ -96,10 +103,12 @@ func TestSecretsManager(t *testing.T) {
 									cmapi.IssuerKindAnnotationKey:  "Issuer",
 									cmapi.IssuerNameAnnotationKey:  "ca-issuer",
 
-									cmapi.CommonNameAnnotationKey: exampleBundle.Cert.Subject.CommonName,
-									cmapi.AltNamesAnnotationKey:   strings.Join(exampleBundle.Cert.DNSNames, ","),
-									cmapi.IPSANAnnotationKey:      strings.Join(utilpki.IPAddressesToString(exampleBundle.Cert.IPAddresses), ","),
-									cmapi.URISANAnnotationKey:     strings.Join(utilpki.URLsToString(exampleBundle.Cert.URIs), ","),
+									cmapi.CommonNameAnnotationKey:           exampleBundle.Cert.Subject.CommonName,
+									cmapi.AltNamesAnnotationKey:             strings.Join(exampleBundle.Cert.DNSNames, ","),
+									cmapi.IPSANAnnotationKey:                strings.Join(utilpki.IPAddressesToString(exampleBundle.Cert.IPAddresses), ","),
+									cmapi.URISANAnnotationKey:               strings.Join(utilpki.URLsToString(exampleBundle.Cert.URIs), ","),
+									cmapi.CertificateNotBeforeAnnotationKey: exampleBundle.Cert.NotBefore.UTC().Format(time.RFC3339),
+									cmapi.CertificateNotAfterAnnotationKey:  exampleBundle.Cert.NotAfter.UTC().Format(time.RFC3339),
 								},
 								OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(exampleBundle.Certificate, certificateGvk)},
 							},
@@ -110,7 +119,7 @@ func TestSecretsManager(t *testing.T) {
 							},
 							Type: corev1.SecretTypeTLS,
 						},
-					)),
+					),
 				},
 			},
 			expectedErr: false,
@@ -141,7 +150,7 @@ func TestSecretsManager(t *testing.T) {
 					},
 				},
 				ExpectedActions: []testpkg.Action{
-					testpkg.NewAction(coretesting.NewUpdateAction(
+					testpkg.NewApplyAction(
 						corev1.SchemeGroupVersion.WithResource("secrets"),
 						gen.DefaultTestNamespace,
 						&corev1.Secret{
@@ -149,17 +158,17 @@ func TestSecretsManager(t *testing.T) {
 								Namespace: gen.DefaultTestNamespace,
 								Name:      "output",
 								Annotations: map[string]string{
-									"my-custom": "annotation",
-
 									cmapi.CertificateNameKey:       "test",
 									cmapi.IssuerGroupAnnotationKey: "foo.io",
 									cmapi.IssuerKindAnnotationKey:  "Issuer",
 									cmapi.IssuerNameAnnotationKey:  "ca-issuer",
 
-									cmapi.CommonNameAnnotationKey: exampleBundle.Cert.Subject.CommonName,
-									cmapi.AltNamesAnnotationKey:   strings.Join(exampleBundle.Cert.DNSNames, ","),
-									cmapi.IPSANAnnotationKey:      strings.Join(utilpki.IPAddressesToString(exampleBundle.Cert.IPAddresses), ","),
-									cmapi.URISANAnnotationKey:     strings.Join(utilpki.URLsToString(exampleBundle.Cert.URIs), ","),
+									cmapi.CommonNameAnnotationKey:           exampleBundle.Cert.Subject.CommonName,
+									cmapi.AltNamesAnnotationKey:             strings.Join(exampleBundle.Cert.DNSNames, ","),
+									cmapi.IPSANAnnotationKey:                strings.Join(utilpki.IPAddressesToString(exampleBundle.Cert.IPAddresses), ","),
+									cmapi.URISANAnnotationKey:               strings.Join(utilpki.URLsToString(exampleBundle.Cert.URIs), ","),
+									cmapi.CertificateNotBeforeAnnotationKey: exampleBundle.Cert.NotBefore.UTC().Format(time.RFC3339),
+									cmapi.CertificateNotAfterAnnotationKey:  exampleBundle.Cert.NotAfter.UTC().Format(time.RFC3339),
 								},
 								OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(exampleBundle.Certificate, certificateGvk)},
 							},
@@ -170,7 +179,7 @@ func TestSecretsManager(t *testing.T) {
 							},
 							Type: corev1.SecretTypeTLS,
 						},
-					)),
+					),
 				},
 			},
 			expectedErr: false,
@@ -185,7 +194,7 @@ func TestSecretsManager(t *testing.T) {
 			builder: &testpkg.Builder{
 				KubeObjects: []runtime.Object{},
 				ExpectedActions: []testpkg.Action{
-					testpkg.NewAction(coretesting.NewCreateAction(
+					testpkg.NewApplyAction(
 						corev1.SchemeGroupVersion.WithResource("secrets"),
 						gen.DefaultTestNamespace,
 						&corev1.Secret{
@@ -198,10 +207,12 @@ func TestSecretsManager(t *testing.T) {
 									cmapi.IssuerKindAnnotationKey:  "Issuer",
 									cmapi.IssuerNameAnnotationKey:  "ca-issuer",
 
-									cmapi.CommonNameAnnotationKey: exampleBundle.Cert.Subject.CommonName,
-									cmapi.AltNamesAnnotationKey:   strings.Join(exampleBundle.Cert.DNSNames, ","),
-									cmapi.IPSANAnnotationKey:      strings.Join(utilpki.IPAddressesToString(exampleBundle.Cert.IPAddresses), ","),
-									cmapi.URISANAnnotationKey:     strings.Join(utilpki.URLsToString(exampleBundle.Cert.URIs), ","),
+									cmapi.CommonNameAnnotationKey:           exampleBundle.Cert.Subject.CommonName,
+									cmapi.AltNamesAnnotationKey:             strings.Join(exampleBundle.Cert.DNSNames, ","),
+									cmapi.IPSANAnnotationKey:                strings.Join(utilpki.IPAddressesToString(exampleBundle.Cert.IPAddresses), ","),
+									cmapi.URISANAnnotationKey:               strings.Join(utilpki.URLsToString(exampleBundle.Cert.URIs), ","),
+									cmapi.CertificateNotBeforeAnnotationKey: exampleBundle.Cert.NotBefore.UTC().Format(time.RFC3339),
+									cmapi.CertificateNotAfterAnnotationKey:  exampleBundle.Cert.NotAfter.UTC().Format(time.RFC3339),
 								},
 							},
 							Data: map[string][]byte{
@@ -211,7 +222,7 @@ func TestSecretsManager(t *testing.T) {
 							},
 							Type: corev1.SecretTypeTLS,
 						},
-					)),
+					),
 				},
 			},
 			expectedErr: false,
@@ -242,7 +253,7 @@ func TestSecretsManager(t *testing.T) {
 					},
 				},
 				ExpectedActions: []testpkg.Action{
-					testpkg.NewAction(coretesting.NewUpdateAction(
+					testpkg.NewApplyAction(
 						corev1.SchemeGroupVersion.WithResource("secrets"),
 						gen.DefaultTestNamespace,
 						&corev1.Secret{
@@ -250,17 +261,17 @@ func TestSecretsManager(t *testing.T) {
 								Namespace: gen.DefaultTestNamespace,
 								Name:      "output",
 								Annotations: map[string]string{
-									"my-custom": "annotation",
-
 									cmapi.CertificateNameKey:       "test",
 									cmapi.IssuerGroupAnnotationKey: "foo.io",
 									cmapi.IssuerKindAnnotationKey:  "Issuer",
 									cmapi.IssuerNameAnnotationKey:  "ca-issuer",
 
-									cmapi.CommonNameAnnotationKey: exampleBundle.Cert.Subject.CommonName,
-									cmapi.AltNamesAnnotationKey:   strings.Join(exampleBundle.Cert.DNSNames, ","),
-									cmapi.IPSANAnnotationKey:      strings.Join(utilpki.IPAddressesToString(exampleBundle.Cert.IPAddresses), ","),
-									cmapi.URISANAnnotationKey:     strings.Join(utilpki.URLsToString(exampleBundle.Cert.URIs), ","),
+									cmapi.CommonNameAnnotationKey:           exampleBundle.Cert.Subject.CommonName,
+									cmapi.AltNamesAnnotationKey:             strings.Join(exampleBundle.Cert.DNSNames, ","),
+									cmapi.IPSANAnnotationKey:                strings.Join(utilpki.IPAddressesToString(exampleBundle.Cert.IPAddresses), ","),
+									cmapi.URISANAnnotationKey:               strings.Join(utilpki.URLsToString(exampleBundle.Cert.URIs), ","),
+									cmapi.CertificateNotBeforeAnnotationKey: exampleBundle.Cert.NotBefore.UTC().Format(time.RFC3339),
+									cmapi.CertificateNotAfterAnnotationKey:  exampleBundle.Cert.NotAfter.UTC().Format(time.RFC3339),
 								},
 							},
 							Data: map[string][]byte{
@@ -270,11 +281,53 @@ func TestSecretsManager(t *testing.T) {
 							},
 							Type: corev1.SecretTypeTLS,
 						},
-					)),
+					),
 				},
 			},
 			expectedErr: false,
 		},
+
+		"if secret does exist and is already up to date, do not write to it": {
+			certificate: exampleBundle.Certificate,
+			certificateOptions: controllerpkg.CertificateOptions{
+				EnableOwnerRef: true,
+			},
+			SecretData: SecretData{Certificate: exampleBundle.CertBytes, CA: []byte("test-ca"), PrivateKey: []byte("test-key")},
+			builder: &testpkg.Builder{
+				KubeObjects: []runtime.Object{
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: gen.DefaultTestNamespace,
+							Name:      "output",
+							Annotations: map[string]string{
+								"my-custom": "annotation",
+
+								cmapi.CertificateNameKey:       "test",
+								cmapi.IssuerGroupAnnotationKey: "foo.io",
+								cmapi.IssuerKindAnnotationKey:  "Issuer",
+								cmapi.IssuerNameAnnotationKey:  "ca-issuer",
+
+								cmapi.CommonNameAnnotationKey:           exampleBundle.Cert.Subject.CommonName,
+								cmapi.AltNamesAnnotationKey:             strings.Join(exampleBundle.Cert.DNSNames, ","),
+								cmapi.IPSANAnnotationKey:                strings.Join(utilpki.IPAddressesToString(exampleBundle.Cert.IPAddresses), ","),
+								cmapi.URISANAnnotationKey:               strings.Join(utilpki.URLsToString(exampleBundle.Cert.URIs), ","),
+								cmapi.CertificateNotBeforeAnnotationKey: exampleBundle.Cert.NotBefore.UTC().Format(time.RFC3339),
+								cmapi.CertificateNotAfterAnnotationKey:  exampleBundle.Cert.NotAfter.UTC().Format(time.RFC3339),
+							},
+							OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(exampleBundle.Certificate, certificateGvk)},
+						},
+						Data: map[string][]byte{
+							corev1.TLSCertKey:       exampleBundle.CertBytes,
+							corev1.TLSPrivateKeyKey: []byte("test-key"),
+							cmmeta.TLSCAKey:         []byte("test-ca"),
+						},
+						Type: corev1.SecretTypeTLS,
+					},
+				},
+				ExpectedActions: []testpkg.Action{},
+			},
+			expectedErr: false,
+		},
 	}
 
 	// TODO: add to these tests once the JKS/PKCS12 support is updated
@@ -309,3 +362,444 @@ func TestSecretsManager(t *testing.T) {
 		})
 	}
 }
+
+func TestSecretsManagerRepeatedUpdate(t *testing.T) {
+	baseCert := gen.Certificate("test",
+		gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "ca-issuer", Kind: "Issuer", Group: "foo.io"}),
+		gen.SetCertificateSecretName("output"),
+		gen.SetCertificateDNSNames("example.com"),
+	)
+	exampleBundle := internaltest.MustCreateCryptoBundle(t, baseCert, fixedClock)
+	data := SecretData{Certificate: exampleBundle.CertBytes, CA: []byte("test-ca"), PrivateKey: exampleBundle.PrivateKeyBytes}
+
+	builder := &testpkg.Builder{
+		T:     t,
+		Clock: fixedClock,
+		// only the first update should result in the Secret being applied
+		ExpectedActions: []testpkg.Action{
+			testpkg.NewCustomMatch(coretesting.NewPatchAction(
+				corev1.SchemeGroupVersion.WithResource("secrets"),
+				gen.DefaultTestNamespace,
+				"output",
+				types.ApplyPatchType,
+				nil,
+			), func(_, act coretesting.Action) error {
+				if pt := act.(coretesting.PatchAction).GetPatchType(); pt != types.ApplyPatchType {
+					return fmt.Errorf("expected patch type %q, but got %q", types.ApplyPatchType, pt)
+				}
+				return nil
+			}),
+		},
+	}
+	builder.Init()
+	defer builder.Stop()
+
+	testManager := New(
+		builder.Client,
+		builder.KubeSharedInformerFactory.Core().V1().Secrets().Lister(),
+		true,
+	)
+
+	builder.Start()
+
+	if err := testManager.UpdateData(context.Background(), exampleBundle.Certificate, data); err != nil {
+		t.Fatalf("unexpected error on first update: %v", err)
+	}
+	// wait for the applied Secret to be observed by the lister
+	builder.Sync()
+	if err := testManager.UpdateData(context.Background(), exampleBundle.Certificate, data); err != nil {
+		t.Fatalf("unexpected error on second update: %v", err)
+	}
+
+	builder.CheckAndFinish()
+}
+
+func TestSecretsManagerEncryptedPrivateKey(t *testing.T) {
+	baseCert := gen.Certificate("test",
+		gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "ca-issuer", Kind: "Issuer", Group: "foo.io"}),
+		gen.SetCertificateSecretName("output"),
+		gen.SetCertificateDNSNames("example.com"),
+	)
+	exampleBundle := internaltest.MustCreateCryptoBundle(t, baseCert, fixedClock)
+
+	crt := exampleBundle.Certificate.DeepCopy()
+	crt.Spec.PrivateKey = &cmapi.CertificatePrivateKey{
+		EncryptionPassphraseSecretRef: &cmmeta.SecretKeySelector{
+			LocalObjectReference: cmmeta.LocalObjectReference{Name: "passphrase"},
+			Key:                  "passphrase",
+		},
+	}
+	crt.Spec.Keystores = &cmapi.CertificateKeystores{
+		PKCS12: &cmapi.PKCS12Keystore{
+			Create: true,
+			PasswordSecretRef: cmmeta.SecretKeySelector{
+				LocalObjectReference: cmmeta.LocalObjectReference{Name: "passphrase"},
+				Key:                  "passphrase",
+			},
+		},
+	}
+	passphraseSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: gen.DefaultTestNamespace,
+			Name:      "passphrase",
+		},
+		Data: map[string][]byte{"passphrase": []byte("correct horse battery staple")},
+	}
+
+	tests := map[string]struct {
+		kubeObjects []runtime.Object
+		expectedErr bool
+	}{
+		"if the passphrase Secret exists, the private key should be encrypted": {
+			kubeObjects: []runtime.Object{passphraseSecret},
+		},
+		"if the passphrase Secret does not exist, error": {
+			expectedErr: true,
+		},
+		"if the passphrase Secret does not contain the key, error": {
+			kubeObjects: []runtime.Object{gen.SecretFrom(passphraseSecret, gen.SetSecretData(map[string][]byte{"other": []byte("x")}))},
+			expectedErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			builder := &testpkg.Builder{
+				T:           t,
+				Clock:       fixedClock,
+				KubeObjects: test.kubeObjects,
+			}
+			builder.Init()
+			defer builder.Stop()
+
+			testManager := New(
+				builder.Client,
+				builder.KubeSharedInformerFactory.Core().V1().Secrets().Lister(),
+				false,
+			)
+
+			builder.Start()
+
+			secret := &corev1.Secret{}
+			err := testManager.setValues(crt, secret, SecretData{
+				Certificate: exampleBundle.CertBytes,
+				PrivateKey:  exampleBundle.PrivateKeyBytes,
+			})
+			if err != nil && !test.expectedErr {
+				t.Fatalf("expected to not get an error, but got: %v", err)
+			}
+			if err == nil && test.expectedErr {
+				t.Fatalf("expected to get an error but did not get one")
+			}
+			if test.expectedErr {
+				return
+			}
+
+			pkData := secret.Data[corev1.TLSPrivateKeyKey]
+			if !utilpki.IsEncryptedPKCS8PrivateKey(pkData) {
+				t.Fatalf("expected private key to be encrypted, got %q", pkData)
+			}
+
+			pk, err := utilpki.DecryptPKCS8PrivateKey(pkData, passphraseSecret.Data["passphrase"])
+			if err != nil {
+				t.Fatalf("failed to decrypt private key: %v", err)
+			}
+			equal, err := utilpki.PublicKeysEqual(pk.Public(), exampleBundle.PrivateKey.Public())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !equal {
+				t.Errorf("decrypted private key does not match the issued private key")
+			}
+
+			// the existing encrypted private key and keystore should be kept
+			// if the private key has not changed, so that the Secret is
+			// unchanged
+			keystoreData := secret.Data[pkcs12SecretKey]
+			err = testManager.setValues(crt, secret, SecretData{
+				Certificate: exampleBundle.CertBytes,
+				PrivateKey:  exampleBundle.PrivateKeyBytes,
+			})
+			if err != nil {
+				t.Fatalf("expected to not get an error, but got: %v", err)
+			}
+			if !bytes.Equal(secret.Data[corev1.TLSPrivateKeyKey], pkData) {
+				t.Errorf("expected the existing encrypted private key to be kept, got %q", secret.Data[corev1.TLSPrivateKeyKey])
+			}
+			if !bytes.Equal(secret.Data[pkcs12SecretKey], keystoreData) {
+				t.Errorf("expected the existing PKCS12 keystore to be kept")
+			}
+		})
+	}
+}
+
+func TestSecretsManagerAdditionalOutputFormats(t *testing.T) {
+	baseCert := gen.Certificate("test",
+		gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "ca-issuer", Kind: "Issuer", Group: "foo.io"}),
+		gen.SetCertificateSecretName("output"),
+		gen.SetCertificateDNSNames("example.com"),
+	)
+	exampleBundle := internaltest.MustCreateCryptoBundle(t, baseCert, fixedClock)
+
+	crtWithDER := exampleBundle.Certificate.DeepCopy()
+	crtWithDER.Spec.AdditionalOutputFormats = []cmapi.CertificateAdditionalOutputFormat{
+		{Type: cmapi.CertificateOutputFormatDER},
+	}
+
+	tests := map[string]struct {
+		certificate *cmapi.Certificate
+		existing    map[string][]byte
+		expectDER   bool
+	}{
+		"if the DER output format is requested, tls.der should be written": {
+			certificate: crtWithDER,
+			expectDER:   true,
+		},
+		"if no additional output formats are requested, tls.der should not be written": {
+			certificate: exampleBundle.Certificate,
+		},
+		"if the DER output format is no longer requested, tls.der should be removed": {
+			certificate: exampleBundle.Certificate,
+			existing:    map[string][]byte{derSecretKey: []byte("old")},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			secret := &corev1.Secret{Data: test.existing}
+			testManager := &SecretsManager{}
+			err := testManager.setValues(test.certificate, secret, SecretData{
+				Certificate: exampleBundle.CertBytes,
+				PrivateKey:  exampleBundle.PrivateKeyBytes,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			der, ok := secret.Data[derSecretKey]
+			if ok != test.expectDER {
+				t.Fatalf("expected tls.der to exist=%t, got=%t", test.expectDER, ok)
+			}
+			if !test.expectDER {
+				return
+			}
+
+			cert, err := x509.ParseCertificate(der)
+			if err != nil {
+				t.Fatalf("failed to parse DER certificate: %v", err)
+			}
+			if !cert.Equal(exampleBundle.Cert) {
+				t.Errorf("DER certificate does not match the issued certificate")
+			}
+
+			// encoding the DER bytes back to PEM should give the original certificate
+			roundTripped := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+			if string(roundTripped) != string(exampleBundle.CertBytes) {
+				t.Errorf("expected DER certificate to round-trip to the original PEM, got %q", roundTripped)
+			}
+		})
+	}
+}
+
+func TestSecretsManagerOwnerReferenceAnnotation(t *testing.T) {
+	baseCert := gen.Certificate("test",
+		gen.SetCertificateNamespace("default"),
+		gen.SetCertificateUID("cert-uid"),
+		gen.SetCertificateSecretName("output"),
+	)
+	withAnnotation := func(value string) *cmapi.Certificate {
+		crt := baseCert.DeepCopy()
+		crt.Annotations = map[string]string{cmapi.SecretOwnerReferenceAnnotationKey: value}
+		return crt
+	}
+	certRef := *metav1.NewControllerRef(baseCert, certificateGvk)
+	otherRef := metav1.OwnerReference{APIVersion: "v1", Kind: "ConfigMap", Name: "other", UID: "other-uid"}
+
+	tests := map[string]struct {
+		certificate    *cmapi.Certificate
+		enableOwnerRef bool
+		existingRefs   []metav1.OwnerReference
+		expectedRefs   []metav1.OwnerReference
+	}{
+		"flag enabled and no annotation sets the owner reference": {
+			certificate:    baseCert,
+			enableOwnerRef: true,
+			expectedRefs:   []metav1.OwnerReference{certRef},
+		},
+		"flag disabled and no annotation leaves owner references unchanged": {
+			certificate:  baseCert,
+			existingRefs: []metav1.OwnerReference{certRef},
+			expectedRefs: []metav1.OwnerReference{certRef},
+		},
+		"flag disabled and annotation true sets the owner reference": {
+			certificate:  withAnnotation("true"),
+			expectedRefs: []metav1.OwnerReference{certRef},
+		},
+		"flag enabled and annotation false does not set the owner reference": {
+			certificate:    withAnnotation("false"),
+			enableOwnerRef: true,
+		},
+		"annotation false removes an existing owner reference to the Certificate only": {
+			certificate:    withAnnotation("false"),
+			enableOwnerRef: true,
+			existingRefs:   []metav1.OwnerReference{otherRef, certRef},
+			expectedRefs:   []metav1.OwnerReference{otherRef},
+		},
+		"an invalid annotation value falls back to the flag": {
+			certificate:    withAnnotation("foo"),
+			enableOwnerRef: true,
+			expectedRefs:   []metav1.OwnerReference{certRef},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := &SecretsManager{enableSecretOwnerReferences: test.enableOwnerRef}
+			secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{OwnerReferences: test.existingRefs}}
+
+			s.setOwnerReference(test.certificate, secret)
+
+			if !reflect.DeepEqual(secret.OwnerReferences, test.expectedRefs) {
+				t.Errorf("unexpected owner references, exp=%v got=%v", test.expectedRefs, secret.OwnerReferences)
+			}
+		})
+	}
+}
+
+func TestSecretsManagerValidityAnnotations(t *testing.T) {
+	baseCert := gen.Certificate("test",
+		gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "ca-issuer", Kind: "Issuer", Group: "foo.io"}),
+		gen.SetCertificateSecretName("output"),
+		gen.SetCertificateDNSNames("example.com"),
+	)
+	issuedBundle := internaltest.MustCreateCryptoBundle(t, baseCert, fixedClock)
+	// the renewed certificate has a different duration so that its NotAfter
+	// time is guaranteed to differ from the issued certificate
+	renewedBundle := internaltest.MustCreateCryptoBundle(t, gen.CertificateFrom(baseCert,
+		gen.SetCertificateDuration(time.Hour*24*30),
+	), fixedClock)
+
+	validityAnnotations := func(cert *x509.Certificate) map[string]string {
+		return map[string]string{
+			cmapi.CertificateNotBeforeAnnotationKey: cert.NotBefore.UTC().Format(time.RFC3339),
+			cmapi.CertificateNotAfterAnnotationKey:  cert.NotAfter.UTC().Format(time.RFC3339),
+		}
+	}
+
+	tests := map[string]struct {
+		existing    map[string]string
+		certificate []byte
+		expected    map[string]string
+	}{
+		"annotations are set to the validity of the issued certificate": {
+			certificate: issuedBundle.CertBytes,
+			expected:    validityAnnotations(issuedBundle.Cert),
+		},
+		"annotations are updated to the validity of the renewed certificate": {
+			existing:    validityAnnotations(issuedBundle.Cert),
+			certificate: renewedBundle.CertBytes,
+			expected:    validityAnnotations(renewedBundle.Cert),
+		},
+		"annotations are removed if there is no certificate": {
+			existing: validityAnnotations(issuedBundle.Cert),
+			expected: map[string]string{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Annotations: test.existing}}
+			testManager := &SecretsManager{}
+			err := testManager.setValues(baseCert, secret, SecretData{
+				Certificate: test.certificate,
+				PrivateKey:  issuedBundle.PrivateKeyBytes,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for _, key := range []string{cmapi.CertificateNotBeforeAnnotationKey, cmapi.CertificateNotAfterAnnotationKey} {
+				value, ok := secret.Annotations[key]
+				expected, expectedOK := test.expected[key]
+				if ok != expectedOK || value != expected {
+					t.Errorf("unexpected %s annotation, exp=%q got=%q", key, expected, value)
+				}
+			}
+		})
+	}
+}
+
+func TestRemovedFieldsPatch(t *testing.T) {
+	crt := gen.Certificate("test",
+		gen.SetCertificateUID("cert-uid"),
+		gen.SetCertificateSecretName("output"),
+	)
+	certRef := *metav1.NewControllerRef(crt, certificateGvk)
+	otherRef := metav1.OwnerReference{APIVersion: "v1", Kind: "ConfigMap", Name: "other", UID: "other-uid"}
+
+	tests := map[string]struct {
+		existing *corev1.Secret
+		desired  *corev1.Secret
+		expected string
+	}{
+		"nothing is removed": {
+			existing: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{cmapi.CertificateNameKey: "test"}},
+				Data:       map[string][]byte{corev1.TLSCertKey: []byte("cert")},
+			},
+			desired: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{cmapi.CertificateNameKey: "test"}},
+				Data:       map[string][]byte{corev1.TLSCertKey: []byte("cert")},
+			},
+			expected: "null",
+		},
+		"removed data entries and annotations are set to null": {
+			existing: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+					cmapi.CertificateNameKey:                "test",
+					cmapi.CertificateNotAfterAnnotationKey:  "2021-01-01T00:00:00Z",
+					cmapi.CertificateNotBeforeAnnotationKey: "2020-01-01T00:00:00Z",
+				}},
+				Data: map[string][]byte{
+					corev1.TLSCertKey: []byte("cert"),
+					pkcs12SecretKey:   []byte("keystore"),
+					derSecretKey:      []byte("der"),
+				},
+			},
+			desired: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{cmapi.CertificateNameKey: "test"}},
+				Data:       map[string][]byte{corev1.TLSCertKey: []byte("cert")},
+			},
+			expected: `{"data":{"keystore.p12":null,"tls.der":null},"metadata":{"annotations":{"cert-manager.io/certificate-not-after":null,"cert-manager.io/certificate-not-before":null}}}`,
+		},
+		"entries not written by cert-manager are not removed": {
+			existing: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"my-custom": "annotation"}},
+				Data:       map[string][]byte{"custom": []byte("data")},
+			},
+			desired:  &corev1.Secret{},
+			expected: "null",
+		},
+		"a removed owner reference to the Certificate is deleted": {
+			existing: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{otherRef, certRef}},
+			},
+			desired: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{otherRef}},
+			},
+			expected: `{"metadata":{"ownerReferences":[{"$patch":"delete","uid":"cert-uid"}]}}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			patch, err := json.Marshal(removedFieldsPatch(crt, test.existing, test.desired))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(patch) != test.expected {
+				t.Errorf("unexpected patch, exp=%s got=%s", test.expected, patch)
+			}
+		})
+	}
+}
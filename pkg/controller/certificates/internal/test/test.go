@@ -127,10 +127,11 @@ func createCryptoBundle(originalCert *cmapi.Certificate, fixedClock *fakeclock.F
 			Annotations:     annotations,
 		},
 		Spec: cmapi.CertificateRequestSpec{
-			Request:   csrPEM,
-			Duration:  crt.Spec.Duration,
-			IssuerRef: crt.Spec.IssuerRef,
-			IsCA:      crt.Spec.IsCA,
+			Request:           csrPEM,
+			Duration:          crt.Spec.Duration,
+			IssuerRef:         crt.Spec.IssuerRef,
+			FallbackIssuerRef: crt.Spec.FallbackIssuerRef,
+			IsCA:              crt.Spec.IsCA,
 		},
 	}
 
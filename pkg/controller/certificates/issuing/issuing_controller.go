@@ -66,12 +66,15 @@ type controller struct {
 	recorder                 record.EventRecorder
 	clock                    clock.Clock
 
-	client cmclient.Interface
+	kubeClient kubernetes.Interface
+	client     cmclient.Interface
 
 	// secretManager is used to create and update Secrets with certificate and key data
 	secretsManager *secretsmanager.SecretsManager
 	// localTemporarySigner signs a certificate that is stored temporarily
 	localTemporarySigner localTemporarySignerFn
+	// notifier is notified of each successful issuance, if configured
+	notifier issuanceNotifier
 }
 
 func NewController(
@@ -123,15 +126,22 @@ func NewController(
 		certificateControllerOptions.EnableOwnerRef,
 	)
 
+	var notifier issuanceNotifier
+	if len(certificateControllerOptions.IssuanceWebhookURL) > 0 {
+		notifier = newWebhookNotifier(log, certificateControllerOptions.IssuanceWebhookURL)
+	}
+
 	return &controller{
 		certificateLister:        certificateInformer.Lister(),
 		certificateRequestLister: certificateRequestInformer.Lister(),
 		secretLister:             secretsInformer.Lister(),
+		kubeClient:               kubeClient,
 		client:                   client,
 		recorder:                 recorder,
 		clock:                    clock,
 		secretsManager:           secretsManager,
 		localTemporarySigner:     certificates.GenerateLocallySignedTemporaryCertificate,
+		notifier:                 notifier,
 	}, queue, mustSync
 }
 
@@ -159,6 +169,18 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 	log = logf.WithResource(log, crt)
 	ctx = logf.NewContext(ctx, log)
 
+	if certificates.IsPaused(crt) {
+		log.V(logf.DebugLevel).Info("Not reconciling Certificate as it is paused")
+		return nil
+	}
+
+	// If `spec.secretName` has been renamed, move the existing certificate
+	// to the new Secret before anything else. Creating the new Secret will
+	// cause the Certificate to be re-queued.
+	if migrated, err := c.migrateRenamedSecret(ctx, crt); err != nil || migrated {
+		return err
+	}
+
 	if !apiutil.CertificateHasCondition(crt, cmapi.CertificateCondition{
 		Type:   cmapi.CertificateConditionIssuing,
 		Status: cmmeta.ConditionTrue,
@@ -307,6 +329,45 @@ func (c *controller) failIssueCertificate(ctx context.Context, log logr.Logger,
 	return nil
 }
 
+// migrateRenamedSecret will copy the Secret previously named by
+// `spec.secretName` to the Secret currently named by `spec.secretName`, and
+// delete the old Secret, if the Certificate has opted in to migrating its
+// Secret on rename. It returns true if a Secret was migrated.
+func (c *controller) migrateRenamedSecret(ctx context.Context, crt *cmapi.Certificate) (bool, error) {
+	log := logf.FromContext(ctx)
+
+	oldSecret, err := certificates.SecretToMigrate(c.secretLister, crt)
+	if err != nil || oldSecret == nil {
+		return false, err
+	}
+
+	newSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            crt.Spec.SecretName,
+			Namespace:       crt.Namespace,
+			Labels:          oldSecret.Labels,
+			Annotations:     oldSecret.Annotations,
+			OwnerReferences: oldSecret.OwnerReferences,
+		},
+		Type: oldSecret.Type,
+		Data: oldSecret.Data,
+	}
+	_, err = c.kubeClient.CoreV1().Secrets(crt.Namespace).Create(ctx, newSecret, metav1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+
+	err = c.kubeClient.CoreV1().Secrets(crt.Namespace).Delete(ctx, oldSecret.Name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return false, err
+	}
+
+	log.V(logf.InfoLevel).Info("migrated certificate data to renamed Secret", "old_secret", oldSecret.Name)
+	c.recorder.Eventf(crt, corev1.EventTypeNormal, "SecretMigrated", "Moved the certificate from Secret %q to %q", oldSecret.Name, crt.Spec.SecretName)
+
+	return true, nil
+}
+
 // issueCertificate will ensure the public key of the CSR matches the signed
 // certificate, and then store the certificate, CA and private key into the
 // Secret in the appropriate format type.
@@ -348,6 +409,17 @@ func (c *controller) issueCertificate(ctx context.Context, nextRevision int, crt
 	message := "The certificate has been successfully issued"
 	c.recorder.Event(crt, corev1.EventTypeNormal, "Issuing", message)
 
+	if c.notifier != nil {
+		cert, err := utilpki.DecodeX509CertificateBytes(req.Status.Certificate)
+		if err != nil {
+			// The certificate has already been verified, so this should
+			// never happen, and must not fail the issuance if it does.
+			logf.FromContext(ctx).Error(err, "failed to decode issued certificate for issuance notification")
+			return nil
+		}
+		c.notifier.Notify(issuanceNotificationFor(crt, req, cert))
+	}
+
 	return nil
 }
 
@@ -378,7 +450,8 @@ func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.Rate
 func init() {
 	controllerpkg.Register(ControllerName, func(ctx *controllerpkg.Context) (controllerpkg.Interface, error) {
 		return controllerpkg.NewBuilder(ctx, ControllerName).
-			For(&controllerWrapper{}).
+			For(certificates.NewWorkerPools(&controllerWrapper{})).
+			Workers(certificates.Workers(ctx.CertificateOptions)).
 			Complete()
 	})
 }
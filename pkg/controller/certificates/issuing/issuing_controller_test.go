@@ -18,6 +18,8 @@ package issuing
 
 import (
 	"context"
+	"encoding/json"
+	"encoding/pem"
 	"fmt"
 	"testing"
 	"time"
@@ -25,6 +27,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	coretesting "k8s.io/client-go/testing"
 	"k8s.io/client-go/tools/cache"
 	fakeclock "k8s.io/utils/clock/testing"
@@ -33,6 +36,7 @@ import (
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 	internaltest "github.com/jetstack/cert-manager/pkg/controller/certificates/internal/test"
 	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	utilpki "github.com/jetstack/cert-manager/pkg/util/pki"
 	"github.com/jetstack/cert-manager/test/unit/gen"
 )
 
@@ -70,6 +74,11 @@ func TestIssuingController(t *testing.T) {
 
 	exampleBundleAlt := internaltest.MustCreateCryptoBundle(t, baseCert.DeepCopy(), fixedClock)
 
+	temporaryCert, err := utilpki.DecodeX509CertificateBytes(exampleBundle.LocalTemporaryCertificateBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	issuingCert := gen.CertificateFrom(baseCert.DeepCopy(),
 		gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
 			Type:   cmapi.CertificateConditionIssuing,
@@ -79,7 +88,82 @@ func TestIssuingController(t *testing.T) {
 
 	metaFixedClockStart := metav1.NewTime(fixedClockStart)
 
+	migratingCert := gen.CertificateFrom(baseCert.DeepCopy(),
+		gen.AddCertificateAnnotations(map[string]string{cmapi.MigrateSecretOnRenameAnnotationKey: "true"}),
+	)
+	renamedSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: baseCert.Namespace,
+			Name:      "old-output",
+			Labels:    map[string]string{"app": "web"},
+			Annotations: map[string]string{
+				cmapi.CertificateNameKey:      "test",
+				cmapi.IssuerNameAnnotationKey: "ca-issuer",
+			},
+		},
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       exampleBundle.CertificateRequestReady.Status.Certificate,
+			corev1.TLSPrivateKeyKey: exampleBundle.PrivateKeyBytes,
+		},
+		Type: corev1.SecretTypeTLS,
+	}
+
 	tests := map[string]testT{
+		"if secretName has been renamed and the Certificate opts in to migration, move the existing Secret to the new name": {
+			certificate: migratingCert,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{migratingCert.DeepCopy()},
+				KubeObjects:        []runtime.Object{renamedSecret.DeepCopy()},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewCreateAction(
+						corev1.SchemeGroupVersion.WithResource("secrets"),
+						baseCert.Namespace,
+						&corev1.Secret{
+							ObjectMeta: metav1.ObjectMeta{
+								Namespace:   baseCert.Namespace,
+								Name:        "output",
+								Labels:      renamedSecret.Labels,
+								Annotations: renamedSecret.Annotations,
+							},
+							Data: renamedSecret.Data,
+							Type: corev1.SecretTypeTLS,
+						},
+					)),
+					testpkg.NewAction(coretesting.NewDeleteAction(
+						corev1.SchemeGroupVersion.WithResource("secrets"),
+						baseCert.Namespace,
+						"old-output",
+					)),
+				},
+				ExpectedEvents: []string{
+					`Normal SecretMigrated Moved the certificate from Secret "old-output" to "output"`,
+				},
+			},
+		},
+		"if secretName has been renamed but the Certificate does not opt in to migration, leave the old Secret": {
+			certificate: baseCert,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{baseCert.DeepCopy()},
+				KubeObjects:        []runtime.Object{renamedSecret.DeepCopy()},
+				ExpectedActions:    []testpkg.Action{},
+			},
+		},
+		"if the Secret named by secretName already exists, do not migrate another Secret": {
+			certificate: migratingCert,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{migratingCert.DeepCopy()},
+				KubeObjects: []runtime.Object{
+					renamedSecret.DeepCopy(),
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: baseCert.Namespace,
+							Name:      "output",
+						},
+					},
+				},
+				ExpectedActions: []testpkg.Action{},
+			},
+		},
 		"if certificate is not in Issuing state, then do nothing": {
 			certificate: exampleBundle.Certificate,
 			builder: &testpkg.Builder{
@@ -379,6 +463,37 @@ func TestIssuingController(t *testing.T) {
 			expectedErr: false,
 		},
 
+		"if certificate is in Issuing state, one CertificateRequests, and is ready, but the certificate is paused, do nothing": {
+			certificate: exampleBundle.Certificate,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{
+					gen.CertificateFrom(issuingCert,
+						gen.AddCertificateAnnotations(map[string]string{
+							cmapi.CertificatePausedAnnotationKey: "true",
+						}),
+					),
+					gen.CertificateRequestFrom(exampleBundle.CertificateRequestReady,
+						gen.AddCertificateRequestAnnotations(map[string]string{
+							cmapi.CertificateRequestRevisionAnnotationKey: "2", // Current Certificate revision=1
+						}),
+					)},
+				KubeObjects: []runtime.Object{
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      nextPrivateKeySecretName,
+							Namespace: exampleBundle.Certificate.Namespace,
+						},
+						Data: map[string][]byte{
+							corev1.TLSPrivateKeyKey: exampleBundle.PrivateKeyBytes,
+						},
+					},
+				},
+				ExpectedActions: []testpkg.Action{},
+				ExpectedEvents:  []string{},
+			},
+			expectedErr: false,
+		},
+
 		"if certificate is in Issuing state, one CertificateRequests, and is ready, store the signed certificate, ca, and private key to a new secret, and log an event": {
 			certificate: exampleBundle.Certificate,
 			builder: &testpkg.Builder{
@@ -409,7 +524,7 @@ func TestIssuingController(t *testing.T) {
 							gen.SetCertificateRevision(2),
 						),
 					)),
-					testpkg.NewAction(coretesting.NewCreateAction(
+					testpkg.NewApplyAction(
 						corev1.SchemeGroupVersion.WithResource("secrets"),
 						exampleBundle.Certificate.Namespace,
 						&corev1.Secret{
@@ -417,14 +532,16 @@ func TestIssuingController(t *testing.T) {
 								Namespace: exampleBundle.Certificate.Namespace,
 								Name:      "output",
 								Annotations: map[string]string{
-									cmapi.CertificateNameKey:       "test",
-									cmapi.IssuerKindAnnotationKey:  "Issuer",
-									cmapi.IssuerNameAnnotationKey:  "ca-issuer",
-									cmapi.IssuerGroupAnnotationKey: "foo.io",
-									cmapi.CommonNameAnnotationKey:  "",
-									cmapi.AltNamesAnnotationKey:    "example.com",
-									cmapi.IPSANAnnotationKey:       "",
-									cmapi.URISANAnnotationKey:      "",
+									cmapi.CertificateNameKey:                "test",
+									cmapi.IssuerKindAnnotationKey:           "Issuer",
+									cmapi.IssuerNameAnnotationKey:           "ca-issuer",
+									cmapi.IssuerGroupAnnotationKey:          "foo.io",
+									cmapi.CommonNameAnnotationKey:           "",
+									cmapi.AltNamesAnnotationKey:             "example.com",
+									cmapi.IPSANAnnotationKey:                "",
+									cmapi.URISANAnnotationKey:               "",
+									cmapi.CertificateNotBeforeAnnotationKey: exampleBundle.Cert.NotBefore.UTC().Format(time.RFC3339),
+									cmapi.CertificateNotAfterAnnotationKey:  exampleBundle.Cert.NotAfter.UTC().Format(time.RFC3339),
 								},
 							},
 							Data: map[string][]byte{
@@ -433,7 +550,7 @@ func TestIssuingController(t *testing.T) {
 							},
 							Type: corev1.SecretTypeTLS,
 						},
-					)),
+					),
 				},
 				ExpectedEvents: []string{
 					"Normal Issuing The certificate has been successfully issued",
@@ -482,7 +599,7 @@ func TestIssuingController(t *testing.T) {
 							gen.SetCertificateRevision(2),
 						),
 					)),
-					testpkg.NewAction(coretesting.NewUpdateAction(
+					testpkg.NewApplyAction(
 						corev1.SchemeGroupVersion.WithResource("secrets"),
 						exampleBundle.Certificate.Namespace,
 						&corev1.Secret{
@@ -490,15 +607,16 @@ func TestIssuingController(t *testing.T) {
 								Namespace: exampleBundle.Certificate.Namespace,
 								Name:      "output",
 								Annotations: map[string]string{
-									"my-custom":                    "annotation",
-									cmapi.CertificateNameKey:       "test",
-									cmapi.IssuerGroupAnnotationKey: "foo.io",
-									cmapi.IssuerKindAnnotationKey:  "Issuer",
-									cmapi.IssuerNameAnnotationKey:  "ca-issuer",
-									cmapi.CommonNameAnnotationKey:  "",
-									cmapi.AltNamesAnnotationKey:    "example.com",
-									cmapi.IPSANAnnotationKey:       "",
-									cmapi.URISANAnnotationKey:      "",
+									cmapi.CertificateNameKey:                "test",
+									cmapi.IssuerGroupAnnotationKey:          "foo.io",
+									cmapi.IssuerKindAnnotationKey:           "Issuer",
+									cmapi.IssuerNameAnnotationKey:           "ca-issuer",
+									cmapi.CommonNameAnnotationKey:           "",
+									cmapi.AltNamesAnnotationKey:             "example.com",
+									cmapi.IPSANAnnotationKey:                "",
+									cmapi.URISANAnnotationKey:               "",
+									cmapi.CertificateNotBeforeAnnotationKey: exampleBundle.Cert.NotBefore.UTC().Format(time.RFC3339),
+									cmapi.CertificateNotAfterAnnotationKey:  exampleBundle.Cert.NotAfter.UTC().Format(time.RFC3339),
 								},
 							},
 							Data: map[string][]byte{
@@ -507,7 +625,7 @@ func TestIssuingController(t *testing.T) {
 							},
 							Type: corev1.SecretTypeTLS,
 						},
-					)),
+					),
 				},
 				ExpectedEvents: []string{
 					"Normal Issuing The certificate has been successfully issued",
@@ -542,7 +660,7 @@ func TestIssuingController(t *testing.T) {
 					},
 				},
 				ExpectedActions: []testpkg.Action{
-					testpkg.NewAction(coretesting.NewCreateAction(
+					testpkg.NewApplyAction(
 						corev1.SchemeGroupVersion.WithResource("secrets"),
 						exampleBundle.Certificate.Namespace,
 						&corev1.Secret{
@@ -550,14 +668,16 @@ func TestIssuingController(t *testing.T) {
 								Namespace: exampleBundle.Certificate.Namespace,
 								Name:      "output",
 								Annotations: map[string]string{
-									cmapi.CertificateNameKey:       "test",
-									cmapi.IssuerGroupAnnotationKey: "foo.io",
-									cmapi.IssuerKindAnnotationKey:  "Issuer",
-									cmapi.IssuerNameAnnotationKey:  "ca-issuer",
-									cmapi.CommonNameAnnotationKey:  "",
-									cmapi.AltNamesAnnotationKey:    "example.com",
-									cmapi.IPSANAnnotationKey:       "",
-									cmapi.URISANAnnotationKey:      "",
+									cmapi.CertificateNameKey:                "test",
+									cmapi.IssuerGroupAnnotationKey:          "foo.io",
+									cmapi.IssuerKindAnnotationKey:           "Issuer",
+									cmapi.IssuerNameAnnotationKey:           "ca-issuer",
+									cmapi.CommonNameAnnotationKey:           "",
+									cmapi.AltNamesAnnotationKey:             "example.com",
+									cmapi.IPSANAnnotationKey:                "",
+									cmapi.URISANAnnotationKey:               "",
+									cmapi.CertificateNotBeforeAnnotationKey: temporaryCert.NotBefore.UTC().Format(time.RFC3339),
+									cmapi.CertificateNotAfterAnnotationKey:  temporaryCert.NotAfter.UTC().Format(time.RFC3339),
 								},
 							},
 							Data: map[string][]byte{
@@ -566,7 +686,7 @@ func TestIssuingController(t *testing.T) {
 							},
 							Type: corev1.SecretTypeTLS,
 						},
-					)),
+					),
 				},
 				ExpectedEvents: []string{
 					"Normal Issuing Issued temporary certificate",
@@ -612,7 +732,7 @@ func TestIssuingController(t *testing.T) {
 					},
 				},
 				ExpectedActions: []testpkg.Action{
-					testpkg.NewAction(coretesting.NewUpdateAction(
+					testpkg.NewApplyAction(
 						corev1.SchemeGroupVersion.WithResource("secrets"),
 						exampleBundle.Certificate.Namespace,
 						&corev1.Secret{
@@ -620,15 +740,16 @@ func TestIssuingController(t *testing.T) {
 								Namespace: exampleBundle.Certificate.Namespace,
 								Name:      "output",
 								Annotations: map[string]string{
-									"my-custom":                    "annotation",
-									cmapi.CertificateNameKey:       "test",
-									cmapi.IssuerGroupAnnotationKey: "foo.io",
-									cmapi.IssuerKindAnnotationKey:  "Issuer",
-									cmapi.IssuerNameAnnotationKey:  "ca-issuer",
-									cmapi.CommonNameAnnotationKey:  "",
-									cmapi.AltNamesAnnotationKey:    "example.com",
-									cmapi.IPSANAnnotationKey:       "",
-									cmapi.URISANAnnotationKey:      "",
+									cmapi.CertificateNameKey:                "test",
+									cmapi.IssuerGroupAnnotationKey:          "foo.io",
+									cmapi.IssuerKindAnnotationKey:           "Issuer",
+									cmapi.IssuerNameAnnotationKey:           "ca-issuer",
+									cmapi.CommonNameAnnotationKey:           "",
+									cmapi.AltNamesAnnotationKey:             "example.com",
+									cmapi.IPSANAnnotationKey:                "",
+									cmapi.URISANAnnotationKey:               "",
+									cmapi.CertificateNotBeforeAnnotationKey: temporaryCert.NotBefore.UTC().Format(time.RFC3339),
+									cmapi.CertificateNotAfterAnnotationKey:  temporaryCert.NotAfter.UTC().Format(time.RFC3339),
 								},
 							},
 							Data: map[string][]byte{
@@ -637,7 +758,7 @@ func TestIssuingController(t *testing.T) {
 							},
 							Type: corev1.SecretTypeTLS,
 						},
-					)),
+					),
 				},
 				ExpectedEvents: []string{
 					"Normal Issuing Issued temporary certificate",
@@ -730,7 +851,7 @@ func TestIssuingController(t *testing.T) {
 					},
 				},
 				ExpectedActions: []testpkg.Action{
-					testpkg.NewAction(coretesting.NewUpdateAction(
+					testpkg.NewApplyAction(
 						corev1.SchemeGroupVersion.WithResource("secrets"),
 						exampleBundle.Certificate.Namespace,
 						&corev1.Secret{
@@ -738,15 +859,16 @@ func TestIssuingController(t *testing.T) {
 								Namespace: exampleBundle.Certificate.Namespace,
 								Name:      "output",
 								Annotations: map[string]string{
-									"my-custom":                    "annotation",
-									cmapi.CertificateNameKey:       "test",
-									cmapi.IssuerGroupAnnotationKey: "foo.io",
-									cmapi.IssuerKindAnnotationKey:  "Issuer",
-									cmapi.IssuerNameAnnotationKey:  "ca-issuer",
-									cmapi.CommonNameAnnotationKey:  "",
-									cmapi.AltNamesAnnotationKey:    "example.com",
-									cmapi.IPSANAnnotationKey:       "",
-									cmapi.URISANAnnotationKey:      "",
+									cmapi.CertificateNameKey:                "test",
+									cmapi.IssuerGroupAnnotationKey:          "foo.io",
+									cmapi.IssuerKindAnnotationKey:           "Issuer",
+									cmapi.IssuerNameAnnotationKey:           "ca-issuer",
+									cmapi.CommonNameAnnotationKey:           "",
+									cmapi.AltNamesAnnotationKey:             "example.com",
+									cmapi.IPSANAnnotationKey:                "",
+									cmapi.URISANAnnotationKey:               "",
+									cmapi.CertificateNotBeforeAnnotationKey: temporaryCert.NotBefore.UTC().Format(time.RFC3339),
+									cmapi.CertificateNotAfterAnnotationKey:  temporaryCert.NotAfter.UTC().Format(time.RFC3339),
 								},
 							},
 							Data: map[string][]byte{
@@ -755,7 +877,7 @@ func TestIssuingController(t *testing.T) {
 							},
 							Type: corev1.SecretTypeTLS,
 						},
-					)),
+					),
 				},
 				ExpectedEvents: []string{
 					"Normal Issuing Issued temporary certificate",
@@ -845,7 +967,7 @@ func TestIssuingController(t *testing.T) {
 							gen.SetCertificateRevision(2),
 						),
 					)),
-					testpkg.NewAction(coretesting.NewCreateAction(
+					testpkg.NewApplyAction(
 						corev1.SchemeGroupVersion.WithResource("secrets"),
 						exampleBundle.Certificate.Namespace,
 						&corev1.Secret{
@@ -853,14 +975,16 @@ func TestIssuingController(t *testing.T) {
 								Namespace: exampleBundle.Certificate.Namespace,
 								Name:      "output",
 								Annotations: map[string]string{
-									cmapi.CertificateNameKey:       "test",
-									cmapi.IssuerGroupAnnotationKey: "foo.io",
-									cmapi.IssuerKindAnnotationKey:  "Issuer",
-									cmapi.IssuerNameAnnotationKey:  "ca-issuer",
-									cmapi.CommonNameAnnotationKey:  "",
-									cmapi.AltNamesAnnotationKey:    "example.com",
-									cmapi.IPSANAnnotationKey:       "",
-									cmapi.URISANAnnotationKey:      "",
+									cmapi.CertificateNameKey:                "test",
+									cmapi.IssuerGroupAnnotationKey:          "foo.io",
+									cmapi.IssuerKindAnnotationKey:           "Issuer",
+									cmapi.IssuerNameAnnotationKey:           "ca-issuer",
+									cmapi.CommonNameAnnotationKey:           "",
+									cmapi.AltNamesAnnotationKey:             "example.com",
+									cmapi.IPSANAnnotationKey:                "",
+									cmapi.URISANAnnotationKey:               "",
+									cmapi.CertificateNotBeforeAnnotationKey: exampleBundle.Cert.NotBefore.UTC().Format(time.RFC3339),
+									cmapi.CertificateNotAfterAnnotationKey:  exampleBundle.Cert.NotAfter.UTC().Format(time.RFC3339),
 								},
 							},
 							Data: map[string][]byte{
@@ -869,7 +993,7 @@ func TestIssuingController(t *testing.T) {
 							},
 							Type: corev1.SecretTypeTLS,
 						},
-					)),
+					),
 				},
 				ExpectedEvents: []string{
 					"Normal Issuing The certificate has been successfully issued",
@@ -969,3 +1093,128 @@ func TestIssuingController(t *testing.T) {
 		})
 	}
 }
+
+func TestIssuingControllerPrivateKeyEncoding(t *testing.T) {
+	nextPrivateKeySecretName := "next-private-key"
+
+	tests := map[string]struct {
+		encoding     cmapi.PrivateKeyEncoding
+		expectedType string
+	}{
+		"rsa private key with empty key encoding": {
+			encoding:     cmapi.PrivateKeyEncoding(""),
+			expectedType: "RSA PRIVATE KEY",
+		},
+		"rsa private key with pkcs1 key encoding": {
+			encoding:     cmapi.PKCS1,
+			expectedType: "RSA PRIVATE KEY",
+		},
+		"rsa private key with pkcs8 key encoding": {
+			encoding:     cmapi.PKCS8,
+			expectedType: "PRIVATE KEY",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			crt := gen.Certificate("test",
+				gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "ca-issuer", Kind: "Issuer", Group: "foo.io"}),
+				gen.SetCertificateSecretName("output"),
+				gen.SetCertificateDNSNames("example.com"),
+				gen.SetCertificateRevision(1),
+				gen.SetCertificateNextPrivateKeySecretName(nextPrivateKeySecretName),
+				gen.SetCertificateKeyAlgorithm(cmapi.RSAKeyAlgorithm),
+				gen.SetCertificateKeyEncoding(test.encoding),
+			)
+			bundle := internaltest.MustCreateCryptoBundle(t, crt, fixedClock)
+
+			// the keymanager controller always stores the next private key
+			// PKCS#8 encoded, regardless of the requested encoding
+			nextPrivateKey, err := utilpki.EncodePrivateKey(bundle.PrivateKey, cmapi.PKCS8)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			builder := &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{
+					gen.CertificateFrom(crt,
+						gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
+							Type:   cmapi.CertificateConditionIssuing,
+							Status: cmmeta.ConditionTrue,
+						}),
+					),
+					gen.CertificateRequestFrom(bundle.CertificateRequestReady,
+						gen.AddCertificateRequestAnnotations(map[string]string{
+							cmapi.CertificateRequestRevisionAnnotationKey: "2",
+						}),
+					),
+				},
+				KubeObjects: []runtime.Object{
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      nextPrivateKeySecretName,
+							Namespace: crt.Namespace,
+						},
+						Data: map[string][]byte{
+							corev1.TLSPrivateKeyKey: nextPrivateKey,
+						},
+					},
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificates"),
+						"status",
+						crt.Namespace,
+						gen.CertificateFrom(bundle.Certificate,
+							gen.SetCertificateRevision(2),
+						),
+					)),
+					testpkg.NewCustomMatch(coretesting.NewPatchAction(
+						corev1.SchemeGroupVersion.WithResource("secrets"),
+						crt.Namespace,
+						crt.Spec.SecretName,
+						types.ApplyPatchType,
+						nil,
+					), func(_, act coretesting.Action) error {
+						secret := &corev1.Secret{}
+						if err := json.Unmarshal(act.(coretesting.PatchAction).GetPatch(), secret); err != nil {
+							return fmt.Errorf("failed to decode applied Secret: %v", err)
+						}
+						block, _ := pem.Decode(secret.Data[corev1.TLSPrivateKeyKey])
+						if block == nil {
+							return fmt.Errorf("failed to decode PEM encoded private key in Secret")
+						}
+						if block.Type != test.expectedType {
+							return fmt.Errorf("expected PEM block type %q, but got %q", test.expectedType, block.Type)
+						}
+						return nil
+					}),
+				},
+				ExpectedEvents: []string{
+					"Normal Issuing The certificate has been successfully issued",
+				},
+			}
+
+			fixedClock.SetTime(fixedClockStart)
+			builder.Clock = fixedClock
+			builder.T = t
+			builder.Init()
+			defer builder.Stop()
+
+			w := controllerWrapper{}
+			w.Register(builder.Context)
+			builder.Start()
+
+			key, err := cache.MetaNamespaceKeyFunc(crt)
+			if err != nil {
+				t.Fatalf("failed to build meta namespace key from certificate: %s", err)
+			}
+
+			err = w.controller.ProcessItem(context.Background(), key)
+			if err != nil {
+				t.Errorf("expected to not get an error, but got: %v", err)
+			}
+			builder.CheckAndFinish(err)
+		})
+	}
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package issuing
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	utilpki "github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+const (
+	// webhookNotificationAttempts is the number of times delivery of an
+	// issuance notification is attempted before it is dropped.
+	webhookNotificationAttempts = 5
+	// webhookNotificationBackoff is the delay before the first retry of a
+	// failed delivery, which is doubled for each subsequent retry.
+	webhookNotificationBackoff = time.Second
+	// webhookNotificationTimeout is the timeout of each delivery attempt.
+	webhookNotificationTimeout = 10 * time.Second
+)
+
+// issuanceNotification is the JSON payload sent to the issuance webhook each
+// time a Certificate has been issued.
+type issuanceNotification struct {
+	Namespace      string                     `json:"namespace"`
+	Name           string                     `json:"name"`
+	CommonName     string                     `json:"commonName,omitempty"`
+	DNSNames       []string                   `json:"dnsNames,omitempty"`
+	IPAddresses    []string                   `json:"ipAddresses,omitempty"`
+	URIs           []string                   `json:"uris,omitempty"`
+	EmailAddresses []string                   `json:"emailAddresses,omitempty"`
+	Issuer         issuanceNotificationIssuer `json:"issuer"`
+	SerialNumber   string                     `json:"serialNumber"`
+	NotAfter       time.Time                  `json:"notAfter"`
+}
+
+type issuanceNotificationIssuer struct {
+	Name  string `json:"name"`
+	Kind  string `json:"kind"`
+	Group string `json:"group"`
+}
+
+// issuanceNotificationFor returns the notification for the given Certificate
+// that has been issued the given certificate by the given request.
+func issuanceNotificationFor(crt *cmapi.Certificate, req *cmapi.CertificateRequest, cert *x509.Certificate) issuanceNotification {
+	// The request may have been signed by its fallback issuer.
+	issuerRef := apiutil.CertificateRequestIssuerRef(req)
+	group := issuerRef.Group
+	if group == "" {
+		group = certmanager.GroupName
+	}
+	return issuanceNotification{
+		Namespace:      crt.Namespace,
+		Name:           crt.Name,
+		CommonName:     cert.Subject.CommonName,
+		DNSNames:       cert.DNSNames,
+		IPAddresses:    utilpki.IPAddressesToString(cert.IPAddresses),
+		URIs:           utilpki.URLsToString(cert.URIs),
+		EmailAddresses: cert.EmailAddresses,
+		Issuer: issuanceNotificationIssuer{
+			Name:  issuerRef.Name,
+			Kind:  apiutil.IssuerKind(issuerRef),
+			Group: group,
+		},
+		SerialNumber: cert.SerialNumber.String(),
+		NotAfter:     cert.NotAfter,
+	}
+}
+
+// issuanceNotifier is notified each time a Certificate has been issued.
+// Notify must not block.
+type issuanceNotifier interface {
+	Notify(notification issuanceNotification)
+}
+
+// webhookNotifier POSTs each issuance notification as JSON to a webhook URL.
+// Notifications are delivered in the background and retried with an
+// exponential back-off, so that a slow or unavailable webhook never delays
+// issuance. Notifications that cannot be delivered are logged and dropped.
+type webhookNotifier struct {
+	log    logr.Logger
+	url    string
+	client *http.Client
+
+	attempts int
+	backoff  time.Duration
+}
+
+func newWebhookNotifier(log logr.Logger, url string) *webhookNotifier {
+	return &webhookNotifier{
+		log:      log.WithName("issuance-webhook"),
+		url:      url,
+		client:   &http.Client{Timeout: webhookNotificationTimeout},
+		attempts: webhookNotificationAttempts,
+		backoff:  webhookNotificationBackoff,
+	}
+}
+
+func (w *webhookNotifier) Notify(notification issuanceNotification) {
+	log := w.log.WithValues("namespace", notification.Namespace, "name", notification.Name)
+	body, err := json.Marshal(notification)
+	if err != nil {
+		log.Error(err, "failed to encode issuance notification")
+		return
+	}
+	go w.deliver(log, body)
+}
+
+// deliver sends the given body to the webhook, retrying failed attempts.
+func (w *webhookNotifier) deliver(log logr.Logger, body []byte) {
+	backoff := w.backoff
+	for attempt := 1; ; attempt++ {
+		err := w.post(body)
+		if err == nil {
+			log.V(logf.DebugLevel).Info("delivered issuance notification", "attempt", attempt)
+			return
+		}
+		if attempt >= w.attempts {
+			log.Error(err, "failed to deliver issuance notification, giving up", "attempts", attempt)
+			return
+		}
+		log.V(logf.DebugLevel).Info("failed to deliver issuance notification, retrying", "error", err.Error(), "attempt", attempt, "retry_after", backoff)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func (w *webhookNotifier) post(body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), webhookNotificationTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// drain the body so that the connection can be reused
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response status %q", resp.Status)
+	}
+	return nil
+}
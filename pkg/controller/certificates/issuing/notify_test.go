@@ -0,0 +1,193 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package issuing
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+// receivedRequest is a request received by the fake webhook sink.
+type receivedRequest struct {
+	contentType string
+	body        []byte
+}
+
+// newFakeSink starts a HTTP server that sends each request it receives on the
+// returned channel, responding with the status codes returned by respond.
+func newFakeSink(t *testing.T, respond func(attempt int32) int) (*httptest.Server, <-chan receivedRequest) {
+	received := make(chan receivedRequest, 10)
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("failed to read request body: %v", err)
+		}
+		received <- receivedRequest{contentType: r.Header.Get("Content-Type"), body: body}
+		w.WriteHeader(respond(atomic.AddInt32(&attempts, 1)))
+	}))
+	t.Cleanup(server.Close)
+	return server, received
+}
+
+func waitForRequest(t *testing.T, received <-chan receivedRequest) receivedRequest {
+	select {
+	case req := <-received:
+		return req
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for an issuance notification")
+		return receivedRequest{}
+	}
+}
+
+func testNotification() issuanceNotification {
+	crt := gen.Certificate("test",
+		gen.SetCertificateNamespace("testns"),
+		gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "ca-issuer", Kind: "ClusterIssuer"}),
+	)
+	req := gen.CertificateRequest("test-1",
+		gen.SetCertificateRequestIssuer(crt.Spec.IssuerRef),
+	)
+	cert := &x509.Certificate{
+		SerialNumber:   big.NewInt(1234),
+		Subject:        pkix.Name{CommonName: "example.com"},
+		DNSNames:       []string{"example.com", "www.example.com"},
+		IPAddresses:    []net.IP{net.ParseIP("10.0.0.1")},
+		URIs:           []*url.URL{{Scheme: "spiffe", Host: "example.com", Path: "/test"}},
+		EmailAddresses: []string{"test@example.com"},
+		NotAfter:       time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	return issuanceNotificationFor(crt, req, cert)
+}
+
+func TestWebhookNotifierPayload(t *testing.T) {
+	server, received := newFakeSink(t, func(int32) int { return http.StatusOK })
+
+	newWebhookNotifier(logf.Log, server.URL).Notify(testNotification())
+
+	req := waitForRequest(t, received)
+	if req.contentType != "application/json" {
+		t.Errorf("expected content type application/json, got %q", req.contentType)
+	}
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(req.body, &payload); err != nil {
+		t.Fatalf("failed to decode payload %q: %v", req.body, err)
+	}
+	expected := map[string]interface{}{
+		"namespace":      "testns",
+		"name":           "test",
+		"commonName":     "example.com",
+		"dnsNames":       []interface{}{"example.com", "www.example.com"},
+		"ipAddresses":    []interface{}{"10.0.0.1"},
+		"uris":           []interface{}{"spiffe://example.com/test"},
+		"emailAddresses": []interface{}{"test@example.com"},
+		"issuer": map[string]interface{}{
+			"name":  "ca-issuer",
+			"kind":  cmapi.ClusterIssuerKind,
+			"group": "cert-manager.io",
+		},
+		"serialNumber": "1234",
+		"notAfter":     "2021-01-02T03:04:05Z",
+	}
+	if !reflect.DeepEqual(expected, payload) {
+		t.Errorf("unexpected payload\nexp=%v\ngot=%v", expected, payload)
+	}
+}
+
+func TestWebhookNotifierRetries(t *testing.T) {
+	// fail the first two attempts
+	server, received := newFakeSink(t, func(attempt int32) int {
+		if attempt <= 2 {
+			return http.StatusServiceUnavailable
+		}
+		return http.StatusOK
+	})
+
+	notifier := newWebhookNotifier(logf.Log, server.URL)
+	notifier.backoff = time.Millisecond
+	notifier.Notify(testNotification())
+
+	first := waitForRequest(t, received)
+	for i := 0; i < 2; i++ {
+		if retry := waitForRequest(t, received); string(retry.body) != string(first.body) {
+			t.Errorf("expected retry to send the same payload, got %q and %q", first.body, retry.body)
+		}
+	}
+
+	select {
+	case <-received:
+		t.Error("expected no further attempts after a successful delivery")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestWebhookNotifierGivesUp(t *testing.T) {
+	server, received := newFakeSink(t, func(int32) int { return http.StatusInternalServerError })
+
+	notifier := newWebhookNotifier(logf.Log, server.URL)
+	notifier.backoff = time.Millisecond
+	notifier.attempts = 3
+	notifier.Notify(testNotification())
+
+	for i := 0; i < 3; i++ {
+		waitForRequest(t, received)
+	}
+
+	select {
+	case <-received:
+		t.Error("expected delivery to be abandoned after the maximum number of attempts")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestWebhookNotifierDoesNotBlock(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	done := make(chan struct{})
+	go func() {
+		newWebhookNotifier(logf.Log, server.URL).Notify(testNotification())
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("expected Notify to return before the notification was delivered")
+	}
+}
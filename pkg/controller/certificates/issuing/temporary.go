@@ -24,6 +24,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates/internal/secretsmanager"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates/trigger/policies"
 	utilpki "github.com/jetstack/cert-manager/pkg/util/pki"
@@ -57,6 +58,11 @@ func (c *controller) ensureTemporaryCertificate(ctx context.Context, crt *cmapi.
 	if err != nil && !apierrors.IsNotFound(err) {
 		return false, err
 	}
+	// Tolerate errors decrypting the private key, as the key is then treated
+	// as invalid and a temporary certificate will be issued.
+	if decrypted, err := certificates.DecryptSecretPrivateKey(c.secretLister, crt, secret); err == nil {
+		secret = decrypted
+	}
 	input := policies.Input{Secret: secret}
 	// If the target Secret exists with a signed certificate and matching private
 	// key, do not issue.
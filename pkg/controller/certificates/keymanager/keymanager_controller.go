@@ -141,6 +141,11 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 		return err
 	}
 
+	if certificates.IsPaused(crt) {
+		log.V(logf.DebugLevel).Info("Not reconciling Certificate as it is paused")
+		return nil
+	}
+
 	// Discover all 'owned' secrets that have the `next-private-key` label
 	secrets, err := certificates.ListSecretsMatchingPredicates(c.secretLister.Secrets(crt.Namespace), isNextPrivateKeyLabelSelector, predicate.ResourceOwnedBy(crt))
 	if err != nil {
@@ -160,10 +165,7 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 
 	// if there is no existing Secret resource, create a new one
 	if len(secrets) == 0 {
-		rotationPolicy := cmapi.RotationPolicyNever
-		if crt.Spec.PrivateKey != nil && crt.Spec.PrivateKey.RotationPolicy != "" {
-			rotationPolicy = crt.Spec.PrivateKey.RotationPolicy
-		}
+		rotationPolicy := certificates.PrivateKeyRotationPolicy(crt)
 		switch rotationPolicy {
 		case cmapi.RotationPolicyNever:
 			return c.createNextPrivateKeyRotationPolicyNever(ctx, crt)
@@ -218,6 +220,18 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 		return c.deleteSecretResources(ctx, secrets)
 	}
 
+	// A next private key may have been generated whilst the rotation policy
+	// was Always. If the policy is now Never, it must not be used in place of
+	// the private key already stored in the Secret.
+	matches, err := certificates.NextPrivateKeyMatchesStoredKey(c.secretLister, crt, pk)
+	if err != nil {
+		return err
+	}
+	if !matches {
+		log.V(logf.DebugLevel).Info("Deleting existing private key secret as it does not match the stored private key and rotation policy is Never")
+		return c.deleteSecretResources(ctx, secrets)
+	}
+
 	return nil
 }
 
@@ -235,6 +249,10 @@ func (c *controller) createNextPrivateKeyRotationPolicyNever(ctx context.Context
 		log.V(logf.DebugLevel).Info("Creating new nextPrivateKeySecretName Secret because existing Secret contains empty data and rotation policy is Never")
 		return c.createAndSetNextPrivateKey(ctx, crt)
 	}
+	s, err = certificates.DecryptSecretPrivateKey(c.secretLister, crt, s)
+	if err != nil {
+		return err
+	}
 	existingPKData := s.Data[corev1.TLSPrivateKeyKey]
 	pk, err := pki.DecodePrivateKeyBytes(existingPKData)
 	if err != nil {
@@ -247,8 +265,8 @@ func (c *controller) createNextPrivateKeyRotationPolicyNever(ctx context.Context
 		return c.createAndSetNextPrivateKey(ctx, crt)
 	}
 	if len(violations) > 0 {
-		c.recorder.Eventf(crt, corev1.EventTypeWarning, "DecodeFailed", "Existing private key in Secret %q does not match requirements on Certificate resource, mismatching fields: %v", crt.Spec.SecretName, violations)
-		return nil
+		c.recorder.Eventf(crt, corev1.EventTypeWarning, "DecodeFailed", "Existing private key in Secret %q does not match requirements on Certificate resource, mismatching fields: %v - generating new key", crt.Spec.SecretName, violations)
+		return c.createAndSetNextPrivateKey(ctx, crt)
 	}
 
 	nextPkSecret, err := c.createNewPrivateKeySecret(ctx, crt, pk)
@@ -367,7 +385,8 @@ func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.Rate
 func init() {
 	controllerpkg.Register(ControllerName, func(ctx *controllerpkg.Context) (controllerpkg.Interface, error) {
 		return controllerpkg.NewBuilder(ctx, ControllerName).
-			For(&controllerWrapper{}).
+			For(certificates.NewWorkerPools(&controllerWrapper{})).
+			Workers(certificates.Workers(ctx.CertificateOptions)).
 			Complete()
 	})
 }
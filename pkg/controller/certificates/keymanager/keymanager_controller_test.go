@@ -17,7 +17,9 @@ limitations under the License.
 package keymanager
 
 import (
+	"bytes"
 	"context"
+	"crypto/ecdsa"
 	"fmt"
 	"reflect"
 	"testing"
@@ -444,6 +446,63 @@ func TestProcessItem(t *testing.T) {
 				)),
 			},
 		},
+		"if an owned secret exists but does not match the stored private key and rotation policy is Never, delete it": {
+			certificate: &cmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test", UID: types.UID("test")},
+				Spec: cmapi.CertificateSpec{
+					SecretName: "crt-secret",
+					PrivateKey: &cmapi.CertificatePrivateKey{RotationPolicy: cmapi.RotationPolicyNever},
+				},
+				Status: cmapi.CertificateStatus{
+					NextPrivateKeySecretName: pointer.StringPtr("fixed-name"),
+					Conditions: []cmapi.CertificateCondition{
+						{
+							Type:   cmapi.CertificateConditionIssuing,
+							Status: cmmeta.ConditionTrue,
+						},
+					},
+				},
+			},
+			secrets: []runtime.Object{
+				ownedSecretWithName("testns", "fixed-name", "test", map[string][]byte{"tls.key": mustGenerateRSA(t, 2048)}),
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "crt-secret"},
+					Data:       map[string][]byte{"tls.key": mustGenerateRSA(t, 2048)},
+				},
+			},
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewDeleteAction(
+					corev1.SchemeGroupVersion.WithResource("secrets"),
+					"testns",
+					"fixed-name",
+				)),
+			},
+		},
+		"if an owned secret exists but does not match the stored private key and rotation policy is Always, do nothing": {
+			certificate: &cmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test", UID: types.UID("test")},
+				Spec: cmapi.CertificateSpec{
+					SecretName: "crt-secret",
+					PrivateKey: &cmapi.CertificatePrivateKey{RotationPolicy: cmapi.RotationPolicyAlways},
+				},
+				Status: cmapi.CertificateStatus{
+					NextPrivateKeySecretName: pointer.StringPtr("fixed-name"),
+					Conditions: []cmapi.CertificateCondition{
+						{
+							Type:   cmapi.CertificateConditionIssuing,
+							Status: cmmeta.ConditionTrue,
+						},
+					},
+				},
+			},
+			secrets: []runtime.Object{
+				ownedSecretWithName("testns", "fixed-name", "test", map[string][]byte{"tls.key": mustGenerateRSA(t, 2048)}),
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "crt-secret"},
+					Data:       map[string][]byte{"tls.key": mustGenerateRSA(t, 2048)},
+				},
+			},
+		},
 		"if an owned secret exists and contains data valid for the spec, do nothing'": {
 			certificate: &cmapi.Certificate{
 				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test", UID: types.UID("test")},
@@ -525,3 +584,178 @@ func TestProcessItem(t *testing.T) {
 		})
 	}
 }
+
+// Simulates two renewals of a Certificate with a rotation policy of Never,
+// storing the next private key in the Certificate's Secret between them as
+// the issuing controller would, and ensures the key is reused byte-for-byte.
+func TestProcessItem_RotationPolicyNeverReusesKeyAcrossRenewals(t *testing.T) {
+	pk, err := pki.GenerateRSAPrivateKey(2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	storedKey, err := pki.EncodePrivateKey(pk, cmapi.PKCS1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	originalKey := storedKey
+
+	for renewal := 1; renewal <= 2; renewal++ {
+		crt := &cmapi.Certificate{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test", UID: types.UID("test")},
+			Spec: cmapi.CertificateSpec{
+				SecretName: "crt-secret",
+				PrivateKey: &cmapi.CertificatePrivateKey{RotationPolicy: cmapi.RotationPolicyNever},
+			},
+			Status: cmapi.CertificateStatus{
+				Revision: func(i int) *int { return &i }(renewal),
+				Conditions: []cmapi.CertificateCondition{
+					{
+						Type:   cmapi.CertificateConditionIssuing,
+						Status: cmmeta.ConditionTrue,
+					},
+				},
+			},
+		}
+		builder := &testpkg.Builder{
+			T:                  t,
+			CertManagerObjects: []runtime.Object{crt},
+			KubeObjects: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "crt-secret"},
+				Data:       map[string][]byte{corev1.TLSPrivateKeyKey: storedKey},
+			}},
+			StringGenerator: func(i int) string { return "notrandom" },
+		}
+		builder.Init()
+
+		w := &controllerWrapper{}
+		if _, _, err := w.Register(builder.Context); err != nil {
+			t.Fatal(err)
+		}
+		builder.Start()
+
+		key, err := controllerpkg.KeyFunc(crt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := w.controller.ProcessItem(context.Background(), key); err != nil {
+			t.Fatalf("renewal %d: unexpected error: %v", renewal, err)
+		}
+
+		var nextKey []byte
+		for _, action := range builder.FakeKubeClient().Actions() {
+			create, ok := action.(coretesting.CreateAction)
+			if !ok || action.GetResource().Resource != "secrets" {
+				continue
+			}
+			nextKey = create.GetObject().(*corev1.Secret).Data[corev1.TLSPrivateKeyKey]
+		}
+		builder.Stop()
+
+		if nextKey == nil {
+			t.Fatalf("renewal %d: expected a next private key Secret to be created", renewal)
+		}
+		nextPK, err := pki.DecodePrivateKeyBytes(nextKey)
+		if err != nil {
+			t.Fatalf("renewal %d: failed to decode next private key: %v", renewal, err)
+		}
+
+		// store the next private key as the issuing controller would
+		storedKey, err = pki.EncodePrivateKey(nextPK, cmapi.PKCS1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(originalKey, storedKey) {
+			t.Errorf("renewal %d: private key was not reused byte-for-byte", renewal)
+		}
+	}
+}
+
+// Ensures that a Certificate with a rotation policy of Never does not reuse
+// the private key stored in its Secret once the requested key algorithm has
+// changed, and that a key of the new algorithm is generated instead.
+func TestProcessItem_RotationPolicyNeverRegeneratesKeyOnAlgorithmChange(t *testing.T) {
+	crt := &cmapi.Certificate{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test", UID: types.UID("test")},
+		Spec: cmapi.CertificateSpec{
+			SecretName: "crt-secret",
+			PrivateKey: &cmapi.CertificatePrivateKey{
+				Algorithm:      cmapi.ECDSAKeyAlgorithm,
+				RotationPolicy: cmapi.RotationPolicyNever,
+			},
+		},
+		Status: cmapi.CertificateStatus{
+			NextPrivateKeySecretName: pointer.StringPtr("fixed-name"),
+			Conditions: []cmapi.CertificateCondition{
+				{
+					Type:   cmapi.CertificateConditionIssuing,
+					Status: cmmeta.ConditionTrue,
+				},
+			},
+		},
+	}
+	builder := &testpkg.Builder{
+		T:                  t,
+		CertManagerObjects: []runtime.Object{crt},
+		KubeObjects: []runtime.Object{&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "crt-secret"},
+			Data:       map[string][]byte{corev1.TLSPrivateKeyKey: mustGenerateRSA(t, 2048)},
+		}},
+		ExpectedEvents: []string{
+			`Warning DecodeFailed Existing private key in Secret "crt-secret" does not match requirements on Certificate resource, mismatching fields: [spec.keyAlgorithm] - generating new key`,
+			`Normal Generated Stored new private key in temporary Secret resource "fixed-name"`,
+		},
+		ExpectedActions: []testpkg.Action{
+			testpkg.NewCustomMatch(coretesting.NewCreateAction(
+				corev1.SchemeGroupVersion.WithResource("secrets"),
+				"testns",
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace:       "testns",
+						Name:            "fixed-name",
+						Labels:          map[string]string{cmapi.IsNextPrivateKeySecretLabelKey: "true"},
+						OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(crt, certificateGvk)},
+					},
+					Data: map[string][]byte{"tls.key": nil},
+				},
+			), relaxedSecretMatcher),
+		},
+		StringGenerator: func(i int) string { return "notrandom" },
+	}
+	builder.Init()
+
+	w := &controllerWrapper{}
+	if _, _, err := w.Register(builder.Context); err != nil {
+		t.Fatal(err)
+	}
+	builder.Start()
+	defer builder.Stop()
+
+	key, err := controllerpkg.KeyFunc(crt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.controller.ProcessItem(context.Background(), key); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := builder.AllEventsCalled(); err != nil {
+		t.Error(err)
+	}
+	if err := builder.AllActionsExecuted(); err != nil {
+		t.Error(err)
+	}
+
+	for _, action := range builder.FakeKubeClient().Actions() {
+		create, ok := action.(coretesting.CreateAction)
+		if !ok || action.GetResource().Resource != "secrets" {
+			continue
+		}
+		nextPK, err := pki.DecodePrivateKeyBytes(create.GetObject().(*corev1.Secret).Data[corev1.TLSPrivateKeyKey])
+		if err != nil {
+			t.Fatalf("failed to decode next private key: %v", err)
+		}
+		if _, ok := nextPK.(*ecdsa.PrivateKey); !ok {
+			t.Errorf("expected an ECDSA private key to be generated, got %T", nextPK)
+		}
+	}
+}
@@ -48,14 +48,6 @@ const (
 	ControllerName = "CertificateReadiness"
 )
 
-var PolicyChain = policies.Chain{
-	policies.SecretDoesNotExist,
-	policies.SecretHasData,
-	policies.SecretPublicKeysMatch,
-	policies.CurrentCertificateRequestValidForSpec,
-	policies.CurrentCertificateHasExpired,
-}
-
 type controller struct {
 	// the policies to use to define readiness - named here to make testing simpler
 	policyChain                      policies.Chain
@@ -214,7 +206,7 @@ func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.Rate
 		ctx.CMClient,
 		ctx.KubeSharedInformerFactory,
 		ctx.SharedInformerFactory,
-		PolicyChain,
+		policies.NewReadinessPolicyChain(ctx.Clock, ctx.CertificateOptions.ReadinessClockSkewTolerance),
 		cmapi.DefaultRenewBefore,
 	)
 	c.controller = ctrl
@@ -225,7 +217,8 @@ func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.Rate
 func init() {
 	controllerpkg.Register(ControllerName, func(ctx *controllerpkg.Context) (controllerpkg.Interface, error) {
 		return controllerpkg.NewBuilder(ctx, ControllerName).
-			For(&controllerWrapper{}).
+			For(certificates.NewWorkerPools(&controllerWrapper{})).
+			Workers(certificates.Workers(ctx.CertificateOptions)).
 			Complete()
 	})
 }
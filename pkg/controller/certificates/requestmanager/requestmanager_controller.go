@@ -38,6 +38,7 @@ import (
 	"k8s.io/client-go/util/workqueue"
 
 	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
@@ -45,6 +46,7 @@ import (
 	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
 	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates"
+	"github.com/jetstack/cert-manager/pkg/issuer"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
 	"github.com/jetstack/cert-manager/pkg/util/pki"
 	"github.com/jetstack/cert-manager/pkg/util/predicate"
@@ -64,6 +66,11 @@ type controller struct {
 	secretLister             corelisters.SecretLister
 	client                   cmclient.Interface
 	recorder                 record.EventRecorder
+
+	// issuerHelper is used to look up the issuer of a Certificate when the
+	// apex domain of wildcard DNS names is requested from ACME issuers, and
+	// is nil otherwise.
+	issuerHelper issuer.Helper
 }
 
 func NewController(
@@ -72,6 +79,7 @@ func NewController(
 	factory informers.SharedInformerFactory,
 	cmFactory cminformers.SharedInformerFactory,
 	recorder record.EventRecorder,
+	includeWildcardApex bool,
 ) (*controller, workqueue.RateLimitingInterface, []cache.InformerSynced) {
 	// create a queue used to queue up items to be processed
 	queue := workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(time.Second*1, time.Second*30), ControllerName)
@@ -103,12 +111,21 @@ func NewController(
 		certificateInformer.Informer().HasSynced,
 	}
 
+	var issuerHelper issuer.Helper
+	if includeWildcardApex {
+		issuerInformer := cmFactory.Certmanager().V1().Issuers()
+		clusterIssuerInformer := cmFactory.Certmanager().V1().ClusterIssuers()
+		mustSync = append(mustSync, issuerInformer.Informer().HasSynced, clusterIssuerInformer.Informer().HasSynced)
+		issuerHelper = issuer.NewHelper(issuerInformer.Lister(), clusterIssuerInformer.Lister())
+	}
+
 	return &controller{
 		certificateLister:        certificateInformer.Lister(),
 		certificateRequestLister: certificateRequestInformer.Lister(),
 		secretLister:             secretsInformer.Lister(),
 		client:                   client,
 		recorder:                 recorder,
+		issuerHelper:             issuerHelper,
 	}, queue, mustSync
 }
 
@@ -131,6 +148,11 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 		return err
 	}
 
+	if certificates.IsPaused(crt) {
+		log.V(logf.DebugLevel).Info("Not reconciling Certificate as it is paused")
+		return nil
+	}
+
 	if !apiutil.CertificateHasCondition(crt, cmapi.CertificateCondition{
 		Type:   cmapi.CertificateConditionIssuing,
 		Status: cmmeta.ConditionTrue,
@@ -138,6 +160,11 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 		return nil
 	}
 
+	crt, err = c.withWildcardApexDomains(crt)
+	if err != nil {
+		return err
+	}
+
 	// Check for and fetch the 'status.nextPrivateKeySecretName' secret
 	if crt.Status.NextPrivateKeySecretName == nil {
 		log.V(logf.DebugLevel).Info("status.nextPrivateKeySecretName not yet set, waiting for keymanager before processing certificate")
@@ -160,6 +187,23 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 		log.Error(err, "Failed to decode next private key secret data, waiting for keymanager before processing certificate")
 		return nil
 	}
+	violations, err := certificates.PrivateKeyMatchesSpec(pk, crt.Spec)
+	if err != nil {
+		log.Error(err, "Internal error verifying if next private key matches spec - please open an issue.")
+		return nil
+	}
+	if len(violations) > 0 {
+		log.V(logf.DebugLevel).Info("Next private key does not match requirements on certificate.spec, waiting for keymanager to regenerate it before processing certificate", "violations", violations)
+		return nil
+	}
+	matches, err := certificates.NextPrivateKeyMatchesStoredKey(c.secretLister, crt, pk)
+	if err != nil {
+		return err
+	}
+	if !matches {
+		log.V(logf.DebugLevel).Info("Next private key does not match the private key stored in the Secret and rotation policy is Never, waiting for keymanager before processing certificate")
+		return nil
+	}
 
 	// Discover all 'owned' CertificateRequests
 	requests, err := certificates.ListCertificateRequestsMatchingPredicates(c.certificateRequestLister.CertificateRequests(crt.Namespace), labels.Everything(), predicate.ResourceOwnedBy(crt))
@@ -206,6 +250,44 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 	return c.createNewCertificateRequest(ctx, crt, pk, nextRevision, nextPrivateKeySecret.Name)
 }
 
+// withWildcardApexDomains returns a copy of the Certificate that also
+// requests the apex domain of each of its wildcard DNS names if the
+// controller is configured to include them and the Certificate is issued by
+// an ACME issuer. Otherwise, the Certificate is returned unchanged.
+// The apex domains are added here so that they are part of the CSR, which
+// must request exactly the identifiers of the ACME order it finalizes.
+func (c *controller) withWildcardApexDomains(crt *cmapi.Certificate) (*cmapi.Certificate, error) {
+	if c.issuerHelper == nil {
+		return crt, nil
+	}
+	if ref := crt.Spec.IssuerRef; ref.Group != "" && ref.Group != certmanager.GroupName {
+		return crt, nil
+	}
+
+	dnsNames := certificates.WithWildcardApexDomains(crt.Spec.DNSNames)
+	if len(dnsNames) == len(crt.Spec.DNSNames) {
+		return crt, nil
+	}
+
+	iss, err := c.issuerHelper.GetGenericIssuer(crt.Spec.IssuerRef, crt.Namespace)
+	if apierrors.IsNotFound(err) {
+		// the issuer controller will not sign the request until the issuer
+		// exists, and the request will be re-created if it was made for an
+		// ACME issuer without the apex domains
+		return crt, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if iss.GetSpec().ACME == nil {
+		return crt, nil
+	}
+
+	crt = crt.DeepCopy()
+	crt.Spec.DNSNames = dnsNames
+	return crt, nil
+}
+
 func (c *controller) deleteRequestsWithoutRevision(ctx context.Context, reqs ...*cmapi.CertificateRequest) ([]*cmapi.CertificateRequest, error) {
 	log := logf.FromContext(ctx)
 	var remaining []*cmapi.CertificateRequest
@@ -328,11 +410,12 @@ func (c *controller) createNewCertificateRequest(ctx context.Context, crt *cmapi
 			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(crt, certificateGvk)},
 		},
 		Spec: cmapi.CertificateRequestSpec{
-			Duration:  crt.Spec.Duration,
-			IssuerRef: crt.Spec.IssuerRef,
-			Request:   csrPEM.Bytes(),
-			IsCA:      crt.Spec.IsCA,
-			Usages:    crt.Spec.Usages,
+			Duration:          crt.Spec.Duration,
+			IssuerRef:         crt.Spec.IssuerRef,
+			FallbackIssuerRef: crt.Spec.FallbackIssuerRef,
+			Request:           csrPEM.Bytes(),
+			IsCA:              crt.Spec.IsCA,
+			Usages:            crt.Spec.Usages,
 		},
 	}
 
@@ -375,6 +458,7 @@ func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.Rate
 		ctx.KubeSharedInformerFactory,
 		ctx.SharedInformerFactory,
 		ctx.Recorder,
+		ctx.ACMEOptions.IncludeWildcardApex,
 	)
 	c.controller = ctrl
 
@@ -384,7 +468,8 @@ func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.Rate
 func init() {
 	controllerpkg.Register(ControllerName, func(ctx *controllerpkg.Context) (controllerpkg.Interface, error) {
 		return controllerpkg.NewBuilder(ctx, ControllerName).
-			For(&controllerWrapper{}).
+			For(certificates.NewWorkerPools(&controllerWrapper{})).
+			Workers(certificates.Workers(ctx.CertificateOptions)).
 			Complete()
 	})
 }
@@ -0,0 +1,79 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trigger
+
+import (
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+const (
+	// SecretConflictReason is the reason set on the SecretConflict condition
+	// and the event fired when the target Secret is managed by another
+	// controller.
+	SecretConflictReason = "SecretManagedByAnotherController"
+)
+
+// secretManagedByAnotherController returns a message describing why the
+// given Secret appears to be managed by a controller other than cert-manager,
+// and true if it is.
+// A Secret is considered to be managed by another controller if it has a
+// controller owner reference that is not the Certificate, or if a field
+// manager not in fieldManagers owns the certificate or private key data.
+// fieldManagers are the field managers recorded by the apiserver for writes
+// made by cert-manager.
+func secretManagedByAnotherController(crt *cmapi.Certificate, secret *corev1.Secret, fieldManagers sets.String) (string, bool) {
+	if secret == nil {
+		return "", false
+	}
+
+	if ref := metav1.GetControllerOf(secret); ref != nil && ref.UID != crt.UID {
+		return fmt.Sprintf("Secret %q is controlled by %s %q, not re-issuing to avoid conflicting writes", secret.Name, ref.Kind, ref.Name), true
+	}
+
+	for _, entry := range secret.ManagedFields {
+		if fieldManagers.Has(entry.Manager) || entry.FieldsV1 == nil {
+			continue
+		}
+		if ownsKeypairData(entry.FieldsV1.Raw) {
+			return fmt.Sprintf("Secret %q data was written by field manager %q, not re-issuing to avoid conflicting writes", secret.Name, entry.Manager), true
+		}
+	}
+
+	return "", false
+}
+
+// ownsKeypairData returns true if the given FieldsV1 set includes the
+// certificate or private key entries of a Secret's data.
+func ownsKeypairData(raw []byte) bool {
+	var fields struct {
+		Data map[string]json.RawMessage `json:"f:data"`
+	}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return false
+	}
+
+	_, ownsCert := fields.Data["f:"+corev1.TLSCertKey]
+	_, ownsKey := fields.Data["f:"+corev1.TLSPrivateKeyKey]
+	return ownsCert || ownsKey
+}
@@ -46,6 +46,15 @@ func (g *Gatherer) DataForCertificate(ctx context.Context, crt *cmapi.Certificat
 		return Input{}, err
 	}
 
+	// If the private key is stored encrypted, decrypt it so that policies
+	// can inspect it. If it cannot be decrypted the raw Secret is used, which
+	// causes the key to be treated as invalid and the certificate reissued.
+	if decrypted, err := certificates.DecryptSecretPrivateKey(g.SecretLister, crt, secret); err != nil {
+		log.Error(err, "failed to decrypt private key stored in Secret")
+	} else {
+		secret = decrypted
+	}
+
 	// Attempt to fetch the CertificateRequest resource for the current 'status.revision'.
 	var req *cmapi.CertificateRequest
 	if crt.Status.Revision != nil {
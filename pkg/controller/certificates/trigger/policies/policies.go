@@ -19,6 +19,8 @@ package policies
 import (
 	"crypto/tls"
 	"fmt"
+	"hash/fnv"
+	"math"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -29,6 +31,13 @@ import (
 	"github.com/jetstack/cert-manager/pkg/util/pki"
 )
 
+// MinimumRenewalInterval is the minimum amount of time that must pass after a
+// certificate has been issued before it is renewed because it is nearing
+// expiry. It prevents Certificates from being re-issued in a tight loop when
+// an issuer returns certificates that are shorter lived than the renewal
+// window.
+const MinimumRenewalInterval = time.Minute * 5
+
 type Input struct {
 	Certificate            *cmapi.Certificate
 	CurrentRevisionRequest *cmapi.CertificateRequest
@@ -56,7 +65,11 @@ func (c Chain) Evaluate(input Input) (string, string, bool) {
 	return "", "", false
 }
 
-func NewTriggerPolicyChain(c clock.Clock) Chain {
+// NewTriggerPolicyChain returns the policy chain used to decide whether a
+// Certificate should be re-issued. The renewal time of each Certificate is
+// moved earlier by up to the given renewalJitter fraction of its renewal
+// window, see RenewalTimeWithJitter.
+func NewTriggerPolicyChain(c clock.Clock, renewalJitter float64) Chain {
 	return Chain{
 		SecretDoesNotExist,
 		SecretHasData,
@@ -64,7 +77,23 @@ func NewTriggerPolicyChain(c clock.Clock) Chain {
 		SecretPrivateKeyMatchesSpec,
 		SecretHasUpToDateIssuerAnnotations,
 		CurrentCertificateRequestValidForSpec,
-		CurrentCertificateNearingExpiry(c),
+		CurrentCertificateNearingExpiry(c, renewalJitter),
+	}
+}
+
+// NewReadinessPolicyChain returns the policy chain used to decide whether a
+// Certificate is Ready. The validity period of the current certificate is
+// compared against the current time allowing for the given clock skew
+// tolerance, so that small amounts of clock drift between the issuer and the
+// controller do not cause the Certificate to flap between Ready and not Ready.
+func NewReadinessPolicyChain(c clock.Clock, clockSkewTolerance time.Duration) Chain {
+	return Chain{
+		SecretDoesNotExist,
+		SecretHasData,
+		SecretPublicKeysMatch,
+		CurrentCertificateRequestValidForSpec,
+		CurrentCertificateNotYetValid(c, clockSkewTolerance),
+		CurrentCertificateHasExpired(c, clockSkewTolerance),
 	}
 }
 
@@ -178,38 +207,101 @@ func currentSecretValidForSpec(input Input) (string, string, bool) {
 	return "", "", false
 }
 
-func CurrentCertificateNearingExpiry(c clock.Clock) Func {
+// CurrentCertificateNearingExpiry checks if the renewal time of the
+// Certificate, moved earlier by the given renewalJitter, has passed.
+func CurrentCertificateNearingExpiry(c clock.Clock, renewalJitter float64) Func {
 	return func(input Input) (string, string, bool) {
-		if input.Certificate.Status.RenewalTime == nil {
+		renewalTime := RenewalTimeWithJitter(input.Certificate, renewalJitter)
+		if renewalTime == nil {
 			return "", "", false
 		}
 
-		renewIn := input.Certificate.Status.RenewalTime.Time.Sub(c.Now())
+		renewIn := renewalTime.Sub(c.Now())
 		if renewIn > 0 {
 			return "", "", false
 		}
 
-		return "Renewing", fmt.Sprintf("Renewing certificate as renewal was scheduled at %s", input.Certificate.Status.RenewalTime), true
+		// Don't renew a certificate that has only just been issued, even if
+		// its renewal time has already passed.
+		if notBefore := input.Certificate.Status.NotBefore; notBefore != nil && c.Now().Before(notBefore.Add(MinimumRenewalInterval)) {
+			return "", "", false
+		}
+
+		return "Renewing", fmt.Sprintf("Renewing certificate as renewal was scheduled at %s", renewalTime), true
+	}
+}
+
+// RenewalTimeWithJitter returns the renewal time of the Certificate moved
+// earlier by a fraction of its renewal window, the time between the renewal
+// time and the expiry of the current certificate. The fraction is between 0
+// and renewalJitter and is derived from the UID of the Certificate, so that it
+// is stable across re-syncs but differs between Certificates that were issued
+// at the same time, spreading out their renewals.
+// It returns nil if the Certificate has no renewal time.
+func RenewalTimeWithJitter(crt *cmapi.Certificate, renewalJitter float64) *time.Time {
+	if crt.Status.RenewalTime == nil {
+		return nil
+	}
+
+	renewalTime := crt.Status.RenewalTime.Time
+	if renewalJitter <= 0 || crt.Status.NotAfter == nil || len(crt.UID) == 0 {
+		return &renewalTime
+	}
+
+	window := crt.Status.NotAfter.Sub(renewalTime)
+	if window <= 0 {
+		return &renewalTime
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(crt.UID))
+	fraction := float64(h.Sum64()) / float64(math.MaxUint64)
+
+	renewalTime = renewalTime.Add(-time.Duration(renewalJitter * fraction * float64(window)))
+	return &renewalTime
+}
+
+// CurrentCertificateNotYetValid checks if the current issued certificate is
+// not yet valid, i.e. its NotBefore time is further in the future than the
+// given clock skew tolerance.
+func CurrentCertificateNotYetValid(c clock.Clock, clockSkewTolerance time.Duration) Func {
+	return func(input Input) (string, string, bool) {
+		certData := input.Secret.Data[corev1.TLSCertKey]
+		cert, err := pki.DecodeX509CertificateBytes(certData)
+		if err != nil {
+			// This case should never happen as it should always be caught by the
+			// secretPublicKeysMatch function beforehand, but handle it just in case.
+			return "InvalidCertificate", fmt.Sprintf("Failed to decode stored certificate: %v", err), true
+		}
+
+		if c.Now().Add(clockSkewTolerance).Before(cert.NotBefore) {
+			return "NotYetValid", fmt.Sprintf("Certificate is not valid until %s", cert.NotBefore.Format(time.RFC1123)), true
+		}
+		return "", "", false
 	}
 }
 
 // CurrentCertificateHasExpired is used exclusively to check if the current
 // issued certificate has actually expired rather than just nearing expiry.
-func CurrentCertificateHasExpired(input Input) (string, string, bool) {
-	certData := input.Secret.Data[corev1.TLSCertKey]
-	// TODO: replace this with a generic decoder that can handle different
-	//  formats such as JKS, P12 etc (i.e. add proper support for keystores)
-	cert, err := pki.DecodeX509CertificateBytes(certData)
-	if err != nil {
-		// This case should never happen as it should always be caught by the
-		// secretPublicKeysMatch function beforehand, but handle it just in case.
-		return "InvalidCertificate", fmt.Sprintf("Failed to decode stored certificate: %v", err), true
-	}
+// The certificate is only considered expired once its NotAfter time is
+// further in the past than the given clock skew tolerance.
+func CurrentCertificateHasExpired(c clock.Clock, clockSkewTolerance time.Duration) Func {
+	return func(input Input) (string, string, bool) {
+		certData := input.Secret.Data[corev1.TLSCertKey]
+		// TODO: replace this with a generic decoder that can handle different
+		//  formats such as JKS, P12 etc (i.e. add proper support for keystores)
+		cert, err := pki.DecodeX509CertificateBytes(certData)
+		if err != nil {
+			// This case should never happen as it should always be caught by the
+			// secretPublicKeysMatch function beforehand, but handle it just in case.
+			return "InvalidCertificate", fmt.Sprintf("Failed to decode stored certificate: %v", err), true
+		}
 
-	if time.Now().After(cert.NotAfter) {
-		return "Expired", fmt.Sprintf("Certificate expired on %s", cert.NotAfter.Format(time.RFC1123)), true
+		if c.Now().Add(-clockSkewTolerance).After(cert.NotAfter) {
+			return "Expired", fmt.Sprintf("Certificate expired on %s", cert.NotAfter.Format(time.RFC1123)), true
+		}
+		return "", "", false
 	}
-	return "", "", false
 }
 
 func formatIssuerRef(name, kind, group string) string {
@@ -23,6 +23,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	fakeclock "k8s.io/utils/clock/testing"
 
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
@@ -452,7 +453,7 @@ func TestDefaultPolicyChain(t *testing.T) {
 			},
 		},
 	}
-	policyChain := NewTriggerPolicyChain(clock)
+	policyChain := NewTriggerPolicyChain(clock, 0)
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
 			reason, message, reissue := policyChain.Evaluate(Input{
@@ -474,6 +475,200 @@ func TestDefaultPolicyChain(t *testing.T) {
 	}
 }
 
+// Ensures short lived certificates are renewed at their renewal time, but not
+// within the minimum renewal interval of having been issued.
+func TestCurrentCertificateNearingExpiry(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	clock := fakeclock.NewFakeClock(now)
+	// a certificate with a duration of 1 hour
+	certificate := func(notBefore, renewalTime time.Time) *cmapi.Certificate {
+		return &cmapi.Certificate{
+			Status: cmapi.CertificateStatus{
+				NotBefore:   &metav1.Time{Time: notBefore},
+				NotAfter:    &metav1.Time{Time: notBefore.Add(time.Hour)},
+				RenewalTime: &metav1.Time{Time: renewalTime},
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		certificate *cmapi.Certificate
+		reissue     bool
+	}{
+		"does not renew before the renewal time": {
+			certificate: certificate(now.Add(-30*time.Minute), now.Add(10*time.Minute)),
+		},
+		"renews once the renewal time has passed": {
+			certificate: certificate(now.Add(-40*time.Minute), now),
+			reissue:     true,
+		},
+		"does not renew within the minimum renewal interval of being issued": {
+			certificate: certificate(now.Add(-time.Minute), now.Add(-time.Minute)),
+		},
+		"renews once the minimum renewal interval has passed": {
+			certificate: certificate(now.Add(-MinimumRenewalInterval), now.Add(-MinimumRenewalInterval)),
+			reissue:     true,
+		},
+		"renews if the issue time is not known": {
+			certificate: &cmapi.Certificate{
+				Status: cmapi.CertificateStatus{
+					RenewalTime: &metav1.Time{Time: now},
+				},
+			},
+			reissue: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, _, reissue := CurrentCertificateNearingExpiry(clock, 0)(Input{Certificate: test.certificate})
+			if test.reissue != reissue {
+				t.Errorf("unexpected 'reissue' exp=%v, got=%v", test.reissue, reissue)
+			}
+		})
+	}
+}
+
+// Ensures the renewal time of a Certificate is brought forward by a stable
+// amount that differs between Certificates with the same validity period.
+func TestRenewalTimeWithJitter(t *testing.T) {
+	notBefore := time.Date(2020, 11, 20, 16, 05, 00, 0000, time.UTC)
+	notAfter := notBefore.Add(90 * 24 * time.Hour)
+	renewalTime := notAfter.Add(-30 * 24 * time.Hour)
+	certificate := func(uid types.UID) *cmapi.Certificate {
+		return &cmapi.Certificate{
+			ObjectMeta: metav1.ObjectMeta{UID: uid},
+			Status: cmapi.CertificateStatus{
+				NotBefore:   &metav1.Time{Time: notBefore},
+				NotAfter:    &metav1.Time{Time: notAfter},
+				RenewalTime: &metav1.Time{Time: renewalTime},
+			},
+		}
+	}
+	const jitter = 0.5
+
+	first := RenewalTimeWithJitter(certificate("uid-1"), jitter)
+	second := RenewalTimeWithJitter(certificate("uid-2"), jitter)
+	if first == nil || second == nil {
+		t.Fatalf("expected renewal times to be set, got %v and %v", first, second)
+	}
+	if first.Equal(*second) {
+		t.Errorf("expected certificates with different UIDs to have different renewal times, both got %s", first)
+	}
+
+	earliest := renewalTime.Add(-time.Duration(jitter * float64(notAfter.Sub(renewalTime))))
+	for _, got := range []*time.Time{first, second} {
+		if got.Before(earliest) || got.After(renewalTime) {
+			t.Errorf("expected renewal time between %s and %s, got %s", earliest, renewalTime, got)
+		}
+	}
+
+	if again := RenewalTimeWithJitter(certificate("uid-1"), jitter); !again.Equal(*first) {
+		t.Errorf("expected renewal time to be deterministic, got %s then %s", first, again)
+	}
+
+	if got := RenewalTimeWithJitter(certificate("uid-1"), 0); !got.Equal(renewalTime) {
+		t.Errorf("expected renewal time to be unchanged without jitter, exp=%s got=%s", renewalTime, got)
+	}
+
+	if got := RenewalTimeWithJitter(&cmapi.Certificate{}, jitter); got != nil {
+		t.Errorf("expected no renewal time for a certificate without one, got %s", got)
+	}
+
+	clock := fakeclock.NewFakeClock(*first)
+	if _, _, reissue := CurrentCertificateNearingExpiry(clock, jitter)(Input{Certificate: certificate("uid-1")}); !reissue {
+		t.Errorf("expected certificate to be renewed at its jittered renewal time")
+	}
+	clock.SetTime(first.Add(-time.Second))
+	if _, _, reissue := CurrentCertificateNearingExpiry(clock, jitter)(Input{Certificate: certificate("uid-1")}); reissue {
+		t.Errorf("expected certificate not to be renewed before its jittered renewal time")
+	}
+}
+
+// Runs tests against the readiness policy chain, ensuring the validity period
+// of the current certificate is checked within the clock skew tolerance.
+func TestReadinessPolicyChain(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	clock := fakeclock.NewFakeClock(now)
+	staticFixedPrivateKey := generatePEMPrivateKey(t)
+	certificate := &cmapi.Certificate{Spec: cmapi.CertificateSpec{
+		SecretName: "something",
+		CommonName: "example.com",
+	}}
+	secretWithValidity := func(notBefore, notAfter time.Time) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "something"},
+			Data: map[string][]byte{
+				corev1.TLSPrivateKeyKey: staticFixedPrivateKey,
+				corev1.TLSCertKey: selfSignCertificateWithNotBeforeAfter(t, staticFixedPrivateKey,
+					&cmapi.Certificate{Spec: cmapi.CertificateSpec{CommonName: "example.com"}},
+					notBefore, notAfter,
+				),
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		secret             *corev1.Secret
+		clockSkewTolerance time.Duration
+
+		// expected outputs
+		reason, message string
+		reissue         bool
+	}{
+		"ready if the certificate is within its validity period": {
+			secret:             secretWithValidity(now.Add(-time.Hour), now.Add(time.Hour)),
+			clockSkewTolerance: 30 * time.Second,
+		},
+		"ready if NotBefore is a few seconds in the future but within the tolerance": {
+			secret:             secretWithValidity(now.Add(5*time.Second), now.Add(time.Hour)),
+			clockSkewTolerance: 30 * time.Second,
+		},
+		"not ready if NotBefore is a few seconds in the future without any tolerance": {
+			secret:  secretWithValidity(now.Add(5*time.Second), now.Add(time.Hour)),
+			reason:  "NotYetValid",
+			message: "Certificate is not valid until " + now.Add(5*time.Second).UTC().Format(time.RFC1123),
+			reissue: true,
+		},
+		"not ready if NotBefore is further in the future than the tolerance": {
+			secret:             secretWithValidity(now.Add(time.Minute), now.Add(time.Hour)),
+			clockSkewTolerance: 30 * time.Second,
+			reason:             "NotYetValid",
+			message:            "Certificate is not valid until " + now.Add(time.Minute).UTC().Format(time.RFC1123),
+			reissue:            true,
+		},
+		"ready if NotAfter is a few seconds in the past but within the tolerance": {
+			secret:             secretWithValidity(now.Add(-time.Hour), now.Add(-5*time.Second)),
+			clockSkewTolerance: 30 * time.Second,
+		},
+		"not ready if NotAfter is further in the past than the tolerance": {
+			secret:             secretWithValidity(now.Add(-time.Hour), now.Add(-time.Minute)),
+			clockSkewTolerance: 30 * time.Second,
+			reason:             "Expired",
+			message:            "Certificate expired on " + now.Add(-time.Minute).UTC().Format(time.RFC1123),
+			reissue:            true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			policyChain := NewReadinessPolicyChain(clock, test.clockSkewTolerance)
+			reason, message, reissue := policyChain.Evaluate(Input{
+				Certificate: certificate,
+				Secret:      test.secret,
+			})
+
+			if test.reason != reason {
+				t.Errorf("unexpected 'reason' exp=%s, got=%s", test.reason, reason)
+			}
+			if test.message != message {
+				t.Errorf("unexpected 'message' exp=%s, got=%s", test.message, message)
+			}
+			if test.reissue != reissue {
+				t.Errorf("unexpected 'reissue' exp=%v, got=%v", test.reissue, reissue)
+			}
+		})
+	}
+}
+
 func generatePEMPrivateKey(t *testing.T) []byte {
 	pk, err := pki.GenerateRSAPrivateKey(2048)
 	if err != nil {
@@ -18,6 +18,7 @@ package trigger
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -25,14 +26,17 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/informers"
 	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/utils/clock"
 
 	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
@@ -41,6 +45,7 @@ import (
 	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates/trigger/policies"
+	"github.com/jetstack/cert-manager/pkg/issuer"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
 	"github.com/jetstack/cert-manager/pkg/scheduler"
 	"github.com/jetstack/cert-manager/pkg/util/predicate"
@@ -54,6 +59,15 @@ const (
 	// In future this should be replaced with a more dynamic exponential
 	// back-off algorithm.
 	retryAfterLastFailure = time.Hour
+
+	// IssuanceTimedOutReason is the reason set on the Issuing condition and
+	// the event fired when an issuance has been in progress for longer than
+	// the issuance timeout of the Certificate.
+	IssuanceTimedOutReason = "IssuanceTimedOut"
+
+	// PausedReason is the reason set on the Paused condition of Certificates
+	// that have been paused.
+	PausedReason = "Paused"
 )
 
 // This controller observes the state of the certificate's currently
@@ -72,6 +86,25 @@ type controller struct {
 	clock                    clock.Clock
 	scheduledWorkQueue       scheduler.ScheduledWorkQueue
 	gatherer                 *policies.Gatherer
+
+	// issuerHelper is used to look up the issuer of a Certificate when
+	// re-syncing Certificates on issuer readiness is enabled, and is nil
+	// otherwise.
+	issuerHelper issuer.Helper
+
+	// defaultIssuanceTimeout is the issuance timeout of Certificates that do
+	// not set spec.issuanceTimeout. Zero means issuances never time out.
+	defaultIssuanceTimeout time.Duration
+
+	// renewalJitter is the maximum fraction of the renewal window by which
+	// the renewal time of a Certificate is moved earlier.
+	renewalJitter float64
+
+	// fieldManagers are the field managers recorded by the apiserver for
+	// writes made by cert-manager. Secrets with certificate data owned by
+	// any other field manager are considered to be managed by another
+	// controller.
+	fieldManagers sets.String
 }
 
 func NewController(
@@ -82,6 +115,9 @@ func NewController(
 	recorder record.EventRecorder,
 	clock clock.Clock,
 	chain policies.Chain,
+	resyncOnIssuerReady bool,
+	defaultIssuanceTimeout time.Duration,
+	renewalJitter float64,
 ) (*controller, workqueue.RateLimitingInterface, []cache.InformerSynced) {
 	// create a queue used to queue up items to be processed
 	queue := workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(time.Second*1, time.Second*30), ControllerName)
@@ -112,6 +148,22 @@ func NewController(
 		certificateInformer.Informer().HasSynced,
 	}
 
+	var issuerHelper issuer.Helper
+	if resyncOnIssuerReady {
+		issuerInformer := cmFactory.Certmanager().V1().Issuers()
+		clusterIssuerInformer := cmFactory.Certmanager().V1().ClusterIssuers()
+		// When an Issuer or ClusterIssuer becomes Ready, enqueue the Certificate
+		// resources that reference it so that they are retried without waiting
+		// for the failure back-off to elapse.
+		handler := cache.ResourceEventHandlerFuncs{
+			UpdateFunc: enqueueCertificatesOnIssuerReady(log, queue, certificateInformer.Lister()),
+		}
+		issuerInformer.Informer().AddEventHandler(handler)
+		clusterIssuerInformer.Informer().AddEventHandler(handler)
+		mustSync = append(mustSync, issuerInformer.Informer().HasSynced, clusterIssuerInformer.Informer().HasSynced)
+		issuerHelper = issuer.NewHelper(issuerInformer.Lister(), clusterIssuerInformer.Lister())
+	}
+
 	return &controller{
 		policyChain:              chain,
 		certificateLister:        certificateInformer.Lister(),
@@ -125,6 +177,13 @@ func NewController(
 			CertificateRequestLister: certificateRequestInformer.Lister(),
 			SecretLister:             secretsInformer.Lister(),
 		},
+		issuerHelper:           issuerHelper,
+		defaultIssuanceTimeout: defaultIssuanceTimeout,
+		renewalJitter:          renewalJitter,
+		// clients configured with rest.AddUserAgent prefix the user agent
+		// with the default one, so the field manager recorded for
+		// cert-manager's writes is derived from the name of the binary
+		fieldManagers: certificates.FieldManagers(rest.DefaultKubernetesUserAgent()),
 	}, queue, mustSync
 }
 
@@ -145,11 +204,36 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 	if err != nil {
 		return err
 	}
-	if apiutil.CertificateHasCondition(crt, cmapi.CertificateCondition{
-		Type:   cmapi.CertificateConditionIssuing,
-		Status: cmmeta.ConditionTrue,
-	}) {
-		// Do nothing if an issuance is already in progress.
+
+	// Paused Certificates are not re-issued. The Paused condition is kept in
+	// sync with the annotation so that users can see why.
+	if certificates.IsPaused(crt) {
+		log.V(logf.DebugLevel).Info("Not re-issuing certificate as it is paused")
+		return c.setPausedCondition(ctx, crt)
+	}
+	if apiutil.GetCertificateCondition(crt, cmapi.CertificateConditionPaused) != nil {
+		// The status update causes the Certificate to be processed again,
+		// at which point re-issuance is evaluated as usual.
+		crt = crt.DeepCopy()
+		apiutil.RemoveCertificateCondition(crt, cmapi.CertificateConditionPaused)
+		_, err = c.client.CertmanagerV1().Certificates(crt.Namespace).UpdateStatus(ctx, crt, metav1.UpdateOptions{})
+		return err
+	}
+
+	if cond := apiutil.GetCertificateCondition(crt, cmapi.CertificateConditionIssuing); cond != nil && cond.Status == cmmeta.ConditionTrue {
+		// Do nothing if an issuance is already in progress, unless it has
+		// been in progress for longer than the issuance timeout.
+		return c.failIssuanceIfTimedOut(ctx, key, crt, cond)
+	}
+
+	// Don't re-issue whilst the issuing controller migrates the existing
+	// Secret to a renamed `spec.secretName`.
+	oldSecret, err := certificates.SecretToMigrate(c.secretLister, crt)
+	if err != nil {
+		return err
+	}
+	if oldSecret != nil {
+		log.V(logf.DebugLevel).Info("Not re-issuing certificate as the existing Secret is waiting to be migrated", "secret", oldSecret.Name)
 		return nil
 	}
 
@@ -161,25 +245,50 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 	// Back off from re-issuing immediately when the certificate has been
 	// in failing mode for less than 1 hour.
 	backoff, delay := shouldBackoffReissuingOnFailure(log, c.clock, input.Certificate)
+	if backoff && c.issuerBecameReadySinceFailure(crt) {
+		log.V(logf.InfoLevel).Info("Not backing off from re-issuing certificate as its issuer has become ready since the last failure")
+		backoff = false
+	}
 	if backoff {
 		log.V(logf.InfoLevel).Info("Not re-issuing certificate as an attempt has been made in the last hour", "retry_delay", delay)
 		c.scheduleRecheckOfCertificateIfRequired(log, key, delay)
 		return nil
 	}
 
-	if crt.Status.RenewalTime != nil {
+	if renewalCheckTime := renewalCheckTime(crt, c.renewalJitter); renewalCheckTime != nil {
 		// ensure a resync is scheduled in the future so that we re-check
 		// Certificate resources and trigger them near expiry time
-		c.scheduleRecheckOfCertificateIfRequired(log, key, crt.Status.RenewalTime.Time.Sub(c.clock.Now()))
+		c.scheduleRecheckOfCertificateIfRequired(log, key, renewalCheckTime.Sub(c.clock.Now()))
 	}
 
 	reason, message, reissue := c.policyChain.Evaluate(input)
 	if !reissue {
-		// no re-issuance required, return early
+		// no re-issuance required, clear any stale conflict and return early
+		return c.removeSecretConflictCondition(ctx, crt)
+	}
+
+	// Don't re-issue if another controller is writing to the Secret, as the
+	// two controllers would otherwise continually overwrite each other.
+	if conflictMessage, conflict := secretManagedByAnotherController(crt, input.Secret, c.fieldManagers); conflict {
+		log.V(logf.InfoLevel).Info("Not re-issuing certificate as the Secret is managed by another controller", "reason", reason, "conflict", conflictMessage)
+		if apiutil.CertificateHasCondition(crt, cmapi.CertificateCondition{
+			Type:   cmapi.CertificateConditionSecretConflict,
+			Status: cmmeta.ConditionTrue,
+		}) {
+			return nil
+		}
+		crt = crt.DeepCopy()
+		apiutil.SetCertificateCondition(crt, cmapi.CertificateConditionSecretConflict, cmmeta.ConditionTrue, SecretConflictReason, conflictMessage)
+		_, err = c.client.CertmanagerV1().Certificates(crt.Namespace).UpdateStatus(ctx, crt, metav1.UpdateOptions{})
+		if err != nil {
+			return err
+		}
+		c.recorder.Event(crt, corev1.EventTypeWarning, SecretConflictReason, conflictMessage)
 		return nil
 	}
 
 	crt = crt.DeepCopy()
+	apiutil.RemoveCertificateCondition(crt, cmapi.CertificateConditionSecretConflict)
 	apiutil.SetCertificateCondition(crt, cmapi.CertificateConditionIssuing, cmmeta.ConditionTrue, reason, message)
 	_, err = c.client.CertmanagerV1().Certificates(crt.Namespace).UpdateStatus(ctx, crt, metav1.UpdateOptions{})
 	if err != nil {
@@ -190,6 +299,75 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 	return nil
 }
 
+// setPausedCondition sets the Paused condition on the Certificate if it is
+// not already present.
+func (c *controller) setPausedCondition(ctx context.Context, crt *cmapi.Certificate) error {
+	if apiutil.CertificateHasCondition(crt, cmapi.CertificateCondition{
+		Type:   cmapi.CertificateConditionPaused,
+		Status: cmmeta.ConditionTrue,
+	}) {
+		return nil
+	}
+
+	crt = crt.DeepCopy()
+	apiutil.SetCertificateCondition(crt, cmapi.CertificateConditionPaused, cmmeta.ConditionTrue, PausedReason,
+		fmt.Sprintf("Certificate is paused using the %s annotation", cmapi.CertificatePausedAnnotationKey))
+	_, err := c.client.CertmanagerV1().Certificates(crt.Namespace).UpdateStatus(ctx, crt, metav1.UpdateOptions{})
+	return err
+}
+
+// removeSecretConflictCondition removes the SecretConflict condition from the
+// Certificate if it is present.
+func (c *controller) removeSecretConflictCondition(ctx context.Context, crt *cmapi.Certificate) error {
+	if apiutil.GetCertificateCondition(crt, cmapi.CertificateConditionSecretConflict) == nil {
+		return nil
+	}
+
+	crt = crt.DeepCopy()
+	apiutil.RemoveCertificateCondition(crt, cmapi.CertificateConditionSecretConflict)
+	_, err := c.client.CertmanagerV1().Certificates(crt.Namespace).UpdateStatus(ctx, crt, metav1.UpdateOptions{})
+	return err
+}
+
+// failIssuanceIfTimedOut marks the in-progress issuance of the Certificate as
+// failed if the Issuing condition has been True for longer than the issuance
+// timeout of the Certificate. Setting the LastFailureTime causes the issuance
+// to be retried after the usual failure back-off.
+// If the issuance has not yet timed out, the Certificate is scheduled to be
+// re-checked once it does.
+func (c *controller) failIssuanceIfTimedOut(ctx context.Context, key string, crt *cmapi.Certificate, cond *cmapi.CertificateCondition) error {
+	log := logf.FromContext(ctx)
+
+	timeout := c.defaultIssuanceTimeout
+	if crt.Spec.IssuanceTimeout != nil {
+		timeout = crt.Spec.IssuanceTimeout.Duration
+	}
+	if timeout <= 0 || cond.LastTransitionTime == nil {
+		return nil
+	}
+
+	inProgress := c.clock.Now().Sub(cond.LastTransitionTime.Time)
+	if inProgress < timeout {
+		c.scheduleRecheckOfCertificateIfRequired(log, key, timeout-inProgress)
+		return nil
+	}
+
+	log.V(logf.InfoLevel).Info("Issuance has timed out, marking it as failed", "issuance_timeout", timeout, "in_progress", inProgress)
+	message := fmt.Sprintf("Issuance did not complete within the issuance timeout of %s and will be retried after %s", timeout, retryAfterLastFailure)
+
+	crt = crt.DeepCopy()
+	nowTime := metav1.NewTime(c.clock.Now())
+	crt.Status.LastFailureTime = &nowTime
+	apiutil.SetCertificateCondition(crt, cmapi.CertificateConditionIssuing, cmmeta.ConditionFalse, IssuanceTimedOutReason, message)
+	_, err := c.client.CertmanagerV1().Certificates(crt.Namespace).UpdateStatus(ctx, crt, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	c.recorder.Event(crt, corev1.EventTypeWarning, IssuanceTimedOutReason, message)
+
+	return nil
+}
+
 // shouldBackoffReissuingOnFailure tells us if we should back off from
 // reissuing the certificate and for how much time.
 func shouldBackoffReissuingOnFailure(log logr.Logger, c clock.Clock, crt *cmapi.Certificate) (backoff bool, delay time.Duration) {
@@ -207,6 +385,100 @@ func shouldBackoffReissuingOnFailure(log logr.Logger, c clock.Clock, crt *cmapi.
 	return true, retryAfterLastFailure - durationSinceFailure
 }
 
+// issuerBecameReadySinceFailure returns true if re-syncing Certificates on
+// issuer readiness is enabled and the issuer of the Certificate became Ready
+// after the Certificate last failed to be issued.
+func (c *controller) issuerBecameReadySinceFailure(crt *cmapi.Certificate) bool {
+	if c.issuerHelper == nil || crt.Status.LastFailureTime == nil {
+		return false
+	}
+
+	iss, err := c.issuerHelper.GetGenericIssuer(crt.Spec.IssuerRef, crt.Namespace)
+	if err != nil {
+		return false
+	}
+
+	for _, cond := range iss.GetStatus().Conditions {
+		if cond.Type != cmapi.IssuerConditionReady || cond.Status != cmmeta.ConditionTrue || cond.LastTransitionTime == nil {
+			continue
+		}
+		return cond.LastTransitionTime.After(crt.Status.LastFailureTime.Time)
+	}
+	return false
+}
+
+// enqueueCertificatesOnIssuerReady returns an update handler that enqueues
+// the Certificates referencing an Issuer or ClusterIssuer when its Ready
+// condition transitions to True.
+func enqueueCertificatesOnIssuerReady(log logr.Logger, queue workqueue.Interface, lister cmlisters.CertificateLister) func(oldObj, newObj interface{}) {
+	readyCondition := cmapi.IssuerCondition{Type: cmapi.IssuerConditionReady, Status: cmmeta.ConditionTrue}
+	return func(oldObj, newObj interface{}) {
+		oldIss, ok := oldObj.(cmapi.GenericIssuer)
+		if !ok {
+			return
+		}
+		iss, ok := newObj.(cmapi.GenericIssuer)
+		if !ok {
+			log.Error(nil, "object does not implement GenericIssuer")
+			return
+		}
+		if apiutil.IssuerHasCondition(oldIss, readyCondition) || !apiutil.IssuerHasCondition(iss, readyCondition) {
+			return
+		}
+
+		log := logf.WithResource(log, iss)
+		crts, err := lister.List(labels.Everything())
+		if err != nil {
+			log.Error(err, "failed to list certificates")
+			return
+		}
+
+		_, isClusterIssuer := iss.(*cmapi.ClusterIssuer)
+		for _, crt := range crts {
+			ref := crt.Spec.IssuerRef
+			if ref.Name != iss.GetObjectMeta().Name || (ref.Group != "" && ref.Group != certmanager.GroupName) {
+				continue
+			}
+			if isClusterIssuer && ref.Kind != cmapi.ClusterIssuerKind {
+				continue
+			}
+			if !isClusterIssuer && ((ref.Kind != "" && ref.Kind != cmapi.IssuerKind) || crt.Namespace != iss.GetObjectMeta().Namespace) {
+				continue
+			}
+
+			key, err := controllerpkg.KeyFunc(crt)
+			if err != nil {
+				log.Error(err, "error computing key for resource")
+				continue
+			}
+			log.V(logf.DebugLevel).Info("issuer became ready, re-syncing certificate", "certificate", key)
+			queue.Add(key)
+		}
+	}
+}
+
+// renewalCheckTime returns the time at which the Certificate should next be
+// checked for renewal, or nil if it has no renewal time.
+// This is the renewal time of the Certificate moved earlier by the given
+// renewalJitter (see policies.RenewalTimeWithJitter), but no sooner than
+// policies.MinimumRenewalInterval after the current certificate was issued, as
+// short lived certificates would otherwise be renewed as soon as they have
+// been issued if their renewal time has already passed.
+func renewalCheckTime(crt *cmapi.Certificate, renewalJitter float64) *time.Time {
+	renewalTimePtr := policies.RenewalTimeWithJitter(crt, renewalJitter)
+	if renewalTimePtr == nil {
+		return nil
+	}
+
+	renewalTime := *renewalTimePtr
+	if crt.Status.NotBefore != nil {
+		if earliest := crt.Status.NotBefore.Add(policies.MinimumRenewalInterval); renewalTime.Before(earliest) {
+			renewalTime = earliest
+		}
+	}
+	return &renewalTime
+}
+
 // scheduleRecheckOfCertificateIfRequired will schedule the resource with the
 // given key to be re-queued for processing after the given amount of time
 // has elapsed.
@@ -244,8 +516,14 @@ func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.Rate
 		ctx.SharedInformerFactory,
 		ctx.Recorder,
 		ctx.Clock,
-		policies.NewTriggerPolicyChain(ctx.Clock),
+		policies.NewTriggerPolicyChain(ctx.Clock, ctx.CertificateOptions.RenewalJitter),
+		ctx.CertificateOptions.ResyncOnIssuerReady,
+		ctx.CertificateOptions.DefaultIssuanceTimeout,
+		ctx.CertificateOptions.RenewalJitter,
 	)
+	if ctx.RESTConfig != nil && ctx.RESTConfig.UserAgent != "" {
+		ctrl.fieldManagers = certificates.FieldManagers(ctx.RESTConfig.UserAgent)
+	}
 	c.controller = ctrl
 
 	return queue, mustSync, nil
@@ -254,7 +532,8 @@ func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.Rate
 func init() {
 	controllerpkg.Register(ControllerName, func(ctx *controllerpkg.Context) (controllerpkg.Interface, error) {
 		return controllerpkg.NewBuilder(ctx, ControllerName).
-			For(&controllerWrapper{}).
+			For(certificates.NewWorkerPools(&controllerWrapper{})).
+			Workers(certificates.Workers(ctx.CertificateOptions)).
 			Complete()
 	})
 }
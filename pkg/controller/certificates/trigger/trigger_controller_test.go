@@ -18,20 +18,26 @@ package trigger
 
 import (
 	"context"
+	"sort"
 	"testing"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
 	coretesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
 	fakeclock "k8s.io/utils/clock/testing"
 
 	logtest "github.com/go-logr/logr/testing"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
 	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates/trigger/policies"
 	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/pkg/util"
 	"github.com/jetstack/cert-manager/test/unit/gen"
 	"github.com/stretchr/testify/assert"
 )
@@ -45,6 +51,9 @@ func Test_controller_ProcessItem(t *testing.T) {
 	metaNow := metav1.NewTime(now)
 	forceTriggeredReason := "ForceTriggered"
 	forceTriggeredMessage := "Re-issuance forced by unit test case"
+	// the field manager recorded by the apiserver for writes made without
+	// server-side apply by a client configured like the controller's
+	updateFieldManager := util.FieldManagerFromUserAgent(rest.AddUserAgent(&rest.Config{}, util.CertManagerUserAgent).UserAgent)
 	tests := map[string]struct {
 		// key that should be passed to ProcessItem.
 		// if not set, the 'namespace/name' of the 'Certificate' field will be used.
@@ -61,6 +70,16 @@ func Test_controller_ProcessItem(t *testing.T) {
 		// Request, if set, will exist in the apiserver before the test is run.
 		requests []*cmapi.CertificateRequest
 
+		// Issuer, if set, will exist in the apiserver before the test is run.
+		issuer *cmapi.Issuer
+
+		// resyncOnIssuerReady enables the ResyncOnIssuerReady certificate option.
+		resyncOnIssuerReady bool
+
+		// defaultIssuanceTimeout sets the DefaultIssuanceTimeout certificate
+		// option.
+		defaultIssuanceTimeout time.Duration
+
 		// optional chain of policy functions that should be run, wrapped with
 		// the policyFuncBuilder to allow injecting the sub-test's testing.T.
 		policyFuncs []policyFuncBuilder
@@ -87,6 +106,10 @@ func Test_controller_ProcessItem(t *testing.T) {
 		// If empty, an update to the empty set/nil is expected.
 		expectedConditions []cmapi.CertificateCondition
 
+		// expectedLastFailureTime is the expected LastFailureTime on the
+		// Certificate resource if an Update is made.
+		expectedLastFailureTime *metav1.Time
+
 		// err is the expected error text returned by the controller, if any.
 		err string
 	}{
@@ -110,6 +133,129 @@ func Test_controller_ProcessItem(t *testing.T) {
 				},
 			},
 		},
+		"mark a stalled issuance as failed once spec.issuanceTimeout has elapsed": {
+			certificate: &cmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test"},
+				Spec:       cmapi.CertificateSpec{IssuanceTimeout: &metav1.Duration{Duration: 10 * time.Minute}},
+				Status: cmapi.CertificateStatus{
+					Conditions: []cmapi.CertificateCondition{
+						{
+							Type:               cmapi.CertificateConditionIssuing,
+							Status:             cmmeta.ConditionTrue,
+							Reason:             "Renewing",
+							LastTransitionTime: func(m metav1.Time) *metav1.Time { return &m }(metav1.NewTime(now.Add(-11 * time.Minute))),
+						},
+					},
+				},
+			},
+			expectedEvent:           "Warning IssuanceTimedOut Issuance did not complete within the issuance timeout of 10m0s and will be retried after 1h0m0s",
+			expectedLastFailureTime: &metaNow,
+			expectedConditions: []cmapi.CertificateCondition{
+				{
+					Type:               cmapi.CertificateConditionIssuing,
+					Status:             cmmeta.ConditionFalse,
+					Reason:             IssuanceTimedOutReason,
+					Message:            "Issuance did not complete within the issuance timeout of 10m0s and will be retried after 1h0m0s",
+					LastTransitionTime: &metaNow,
+				},
+			},
+		},
+		"do nothing if an issuance has been in progress for less than spec.issuanceTimeout": {
+			certificate: &cmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test"},
+				Spec:       cmapi.CertificateSpec{IssuanceTimeout: &metav1.Duration{Duration: 10 * time.Minute}},
+				Status: cmapi.CertificateStatus{
+					Conditions: []cmapi.CertificateCondition{
+						{
+							Type:               cmapi.CertificateConditionIssuing,
+							Status:             cmmeta.ConditionTrue,
+							Reason:             "Renewing",
+							LastTransitionTime: func(m metav1.Time) *metav1.Time { return &m }(metav1.NewTime(now.Add(-9 * time.Minute))),
+						},
+					},
+				},
+			},
+		},
+		"mark a stalled issuance as failed once the default issuance timeout has elapsed": {
+			certificate: &cmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test"},
+				Status: cmapi.CertificateStatus{
+					Conditions: []cmapi.CertificateCondition{
+						{
+							Type:               cmapi.CertificateConditionIssuing,
+							Status:             cmmeta.ConditionTrue,
+							Reason:             "Renewing",
+							LastTransitionTime: func(m metav1.Time) *metav1.Time { return &m }(metav1.NewTime(now.Add(-11 * time.Minute))),
+						},
+					},
+				},
+			},
+			defaultIssuanceTimeout:  10 * time.Minute,
+			expectedEvent:           "Warning IssuanceTimedOut Issuance did not complete within the issuance timeout of 10m0s and will be retried after 1h0m0s",
+			expectedLastFailureTime: &metaNow,
+			expectedConditions: []cmapi.CertificateCondition{
+				{
+					Type:               cmapi.CertificateConditionIssuing,
+					Status:             cmmeta.ConditionFalse,
+					Reason:             IssuanceTimedOutReason,
+					Message:            "Issuance did not complete within the issuance timeout of 10m0s and will be retried after 1h0m0s",
+					LastTransitionTime: &metaNow,
+				},
+			},
+		},
+		"prefer spec.issuanceTimeout over the default issuance timeout": {
+			certificate: &cmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test"},
+				Spec:       cmapi.CertificateSpec{IssuanceTimeout: &metav1.Duration{Duration: time.Hour}},
+				Status: cmapi.CertificateStatus{
+					Conditions: []cmapi.CertificateCondition{
+						{
+							Type:               cmapi.CertificateConditionIssuing,
+							Status:             cmmeta.ConditionTrue,
+							Reason:             "Renewing",
+							LastTransitionTime: func(m metav1.Time) *metav1.Time { return &m }(metav1.NewTime(now.Add(-11 * time.Minute))),
+						},
+					},
+				},
+			},
+			defaultIssuanceTimeout: 10 * time.Minute,
+		},
+		"do nothing if a stalled issuance has no issuance timeout": {
+			certificate: &cmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test"},
+				Status: cmapi.CertificateStatus{
+					Conditions: []cmapi.CertificateCondition{
+						{
+							Type:               cmapi.CertificateConditionIssuing,
+							Status:             cmmeta.ConditionTrue,
+							Reason:             "Renewing",
+							LastTransitionTime: func(m metav1.Time) *metav1.Time { return &m }(metav1.NewTime(now.Add(-24 * time.Hour))),
+						},
+					},
+				},
+			},
+		},
+		"do nothing if the Secret of a renamed secretName is waiting to be migrated": {
+			certificate: &cmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "testns",
+					Name:        "test",
+					Annotations: map[string]string{cmapi.MigrateSecretOnRenameAnnotationKey: "true"},
+				},
+				Spec: cmapi.CertificateSpec{
+					SecretName: "new-secret",
+				},
+			},
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "testns",
+					Name:        "old-secret",
+					Annotations: map[string]string{cmapi.CertificateNameKey: "test"},
+				},
+				Data: map[string][]byte{corev1.TLSCertKey: []byte("cert")},
+			},
+			chainShouldTriggerIssuance: true,
+		},
 		"evaluate policy chain with only the Certificate if no Request or Secret exists": {
 			certificate: &cmapi.Certificate{
 				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test"},
@@ -295,6 +441,75 @@ func Test_controller_ProcessItem(t *testing.T) {
 			chainShouldEvaluate:        false,
 			chainShouldTriggerIssuance: false,
 		},
+		"should set the 'Issuing' status condition within the failure back-off if the issuer became ready since the last failure and resyncOnIssuerReady is enabled": {
+			certificate: &cmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test"},
+				Spec:       cmapi.CertificateSpec{IssuerRef: cmmeta.ObjectReference{Name: "test-issuer"}},
+				Status: cmapi.CertificateStatus{
+					LastFailureTime: func(m metav1.Time) *metav1.Time { return &m }(metav1.NewTime(now.Add(-10 * time.Minute))),
+				},
+			},
+			issuer: gen.Issuer("test-issuer",
+				gen.SetIssuerNamespace("testns"),
+				gen.AddIssuerCondition(cmapi.IssuerCondition{
+					Type:               cmapi.IssuerConditionReady,
+					Status:             cmmeta.ConditionTrue,
+					LastTransitionTime: func(m metav1.Time) *metav1.Time { return &m }(metav1.NewTime(now.Add(-5 * time.Minute))),
+				}),
+			),
+			resyncOnIssuerReady:        true,
+			chainShouldEvaluate:        true,
+			chainShouldTriggerIssuance: true,
+			expectedEvent:              "Normal Issuing Re-issuance forced by unit test case",
+			expectedConditions: []cmapi.CertificateCondition{
+				{
+					Type:               cmapi.CertificateConditionIssuing,
+					Status:             cmmeta.ConditionTrue,
+					Reason:             forceTriggeredReason,
+					Message:            forceTriggeredMessage,
+					LastTransitionTime: &metaNow,
+				},
+			},
+		},
+		"should back off if the issuer became ready since the last failure but resyncOnIssuerReady is disabled": {
+			certificate: &cmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test"},
+				Spec:       cmapi.CertificateSpec{IssuerRef: cmmeta.ObjectReference{Name: "test-issuer"}},
+				Status: cmapi.CertificateStatus{
+					LastFailureTime: func(m metav1.Time) *metav1.Time { return &m }(metav1.NewTime(now.Add(-10 * time.Minute))),
+				},
+			},
+			issuer: gen.Issuer("test-issuer",
+				gen.SetIssuerNamespace("testns"),
+				gen.AddIssuerCondition(cmapi.IssuerCondition{
+					Type:               cmapi.IssuerConditionReady,
+					Status:             cmmeta.ConditionTrue,
+					LastTransitionTime: func(m metav1.Time) *metav1.Time { return &m }(metav1.NewTime(now.Add(-5 * time.Minute))),
+				}),
+			),
+			chainShouldEvaluate:        false,
+			chainShouldTriggerIssuance: true,
+		},
+		"should back off if the issuer was already ready at the time of the last failure and resyncOnIssuerReady is enabled": {
+			certificate: &cmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test"},
+				Spec:       cmapi.CertificateSpec{IssuerRef: cmmeta.ObjectReference{Name: "test-issuer"}},
+				Status: cmapi.CertificateStatus{
+					LastFailureTime: func(m metav1.Time) *metav1.Time { return &m }(metav1.NewTime(now.Add(-10 * time.Minute))),
+				},
+			},
+			issuer: gen.Issuer("test-issuer",
+				gen.SetIssuerNamespace("testns"),
+				gen.AddIssuerCondition(cmapi.IssuerCondition{
+					Type:               cmapi.IssuerConditionReady,
+					Status:             cmmeta.ConditionTrue,
+					LastTransitionTime: func(m metav1.Time) *metav1.Time { return &m }(metav1.NewTime(now.Add(-20 * time.Minute))),
+				}),
+			),
+			resyncOnIssuerReady:        true,
+			chainShouldEvaluate:        false,
+			chainShouldTriggerIssuance: true,
+		},
 		"should set the 'Issuing' status condition if the chain indicates an issuance is required if the last failure time is older than the last hour": {
 			certificate: &cmapi.Certificate{
 				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test"},
@@ -315,6 +530,268 @@ func Test_controller_ProcessItem(t *testing.T) {
 				},
 			},
 		},
+		"should set the 'SecretConflict' status condition instead of 'Issuing' if another field manager wrote the Secret data": {
+			certificate: &cmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test"},
+				Spec:       cmapi.CertificateSpec{SecretName: "test-secret"},
+			},
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "testns",
+					Name:      "test-secret",
+					ManagedFields: []metav1.ManagedFieldsEntry{
+						{
+							Manager:    updateFieldManager,
+							Operation:  metav1.ManagedFieldsOperationUpdate,
+							FieldsType: "FieldsV1",
+							FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:annotations":{}},"f:type":{}}`)},
+						},
+						{
+							Manager:    "external-secrets",
+							Operation:  metav1.ManagedFieldsOperationUpdate,
+							FieldsType: "FieldsV1",
+							FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:data":{"f:tls.crt":{},"f:tls.key":{}}}`)},
+						},
+					},
+				},
+			},
+			chainShouldEvaluate:        true,
+			chainShouldTriggerIssuance: true,
+			expectedEvent:              `Warning SecretManagedByAnotherController Secret "test-secret" data was written by field manager "external-secrets", not re-issuing to avoid conflicting writes`,
+			expectedConditions: []cmapi.CertificateCondition{
+				{
+					Type:               cmapi.CertificateConditionSecretConflict,
+					Status:             cmmeta.ConditionTrue,
+					Reason:             "SecretManagedByAnotherController",
+					Message:            `Secret "test-secret" data was written by field manager "external-secrets", not re-issuing to avoid conflicting writes`,
+					LastTransitionTime: &metaNow,
+				},
+			},
+		},
+		"should set the 'Issuing' status condition if the Secret data was written by cert-manager": {
+			certificate: &cmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test"},
+				Spec:       cmapi.CertificateSpec{SecretName: "test-secret"},
+			},
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "testns",
+					Name:      "test-secret",
+					ManagedFields: []metav1.ManagedFieldsEntry{
+						{
+							Manager:    updateFieldManager,
+							Operation:  metav1.ManagedFieldsOperationUpdate,
+							FieldsType: "FieldsV1",
+							FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:data":{"f:tls.crt":{},"f:tls.key":{}}}`)},
+						},
+						{
+							Manager:    "cert-manager",
+							Operation:  metav1.ManagedFieldsOperationApply,
+							FieldsType: "FieldsV1",
+							FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:data":{"f:ca.crt":{},"f:tls.crt":{},"f:tls.key":{}}}`)},
+						},
+					},
+				},
+			},
+			chainShouldEvaluate:        true,
+			chainShouldTriggerIssuance: true,
+			expectedEvent:              "Normal Issuing Re-issuance forced by unit test case",
+			expectedConditions: []cmapi.CertificateCondition{
+				{
+					Type:               cmapi.CertificateConditionIssuing,
+					Status:             cmmeta.ConditionTrue,
+					Reason:             forceTriggeredReason,
+					Message:            forceTriggeredMessage,
+					LastTransitionTime: &metaNow,
+				},
+			},
+		},
+		"should set the 'SecretConflict' status condition instead of 'Issuing' if the Secret is controlled by another resource": {
+			certificate: &cmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test", UID: "cert-uid"},
+				Spec:       cmapi.CertificateSpec{SecretName: "test-secret"},
+			},
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "testns",
+					Name:      "test-secret",
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							APIVersion: "external-secrets.io/v1alpha1",
+							Kind:       "ExternalSecret",
+							Name:       "test-external-secret",
+							UID:        "external-secret-uid",
+							Controller: func(b bool) *bool { return &b }(true),
+						},
+					},
+				},
+			},
+			chainShouldEvaluate:        true,
+			chainShouldTriggerIssuance: true,
+			expectedEvent:              `Warning SecretManagedByAnotherController Secret "test-secret" is controlled by ExternalSecret "test-external-secret", not re-issuing to avoid conflicting writes`,
+			expectedConditions: []cmapi.CertificateCondition{
+				{
+					Type:               cmapi.CertificateConditionSecretConflict,
+					Status:             cmmeta.ConditionTrue,
+					Reason:             "SecretManagedByAnotherController",
+					Message:            `Secret "test-secret" is controlled by ExternalSecret "test-external-secret", not re-issuing to avoid conflicting writes`,
+					LastTransitionTime: &metaNow,
+				},
+			},
+		},
+		"should not update the Certificate if the 'SecretConflict' status condition is already set": {
+			certificate: &cmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test", UID: "cert-uid"},
+				Spec:       cmapi.CertificateSpec{SecretName: "test-secret"},
+				Status: cmapi.CertificateStatus{
+					Conditions: []cmapi.CertificateCondition{
+						{
+							Type:   cmapi.CertificateConditionSecretConflict,
+							Status: cmmeta.ConditionTrue,
+							Reason: "SecretManagedByAnotherController",
+						},
+					},
+				},
+			},
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "testns",
+					Name:      "test-secret",
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							Kind:       "ExternalSecret",
+							Name:       "test-external-secret",
+							UID:        "external-secret-uid",
+							Controller: func(b bool) *bool { return &b }(true),
+						},
+					},
+				},
+			},
+			chainShouldEvaluate:        true,
+			chainShouldTriggerIssuance: true,
+		},
+		"should remove the 'SecretConflict' status condition when setting 'Issuing' if the Secret is no longer managed by another controller": {
+			certificate: &cmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test", UID: "cert-uid"},
+				Spec:       cmapi.CertificateSpec{SecretName: "test-secret"},
+				Status: cmapi.CertificateStatus{
+					Conditions: []cmapi.CertificateCondition{
+						{
+							Type:   cmapi.CertificateConditionSecretConflict,
+							Status: cmmeta.ConditionTrue,
+							Reason: "SecretManagedByAnotherController",
+						},
+					},
+				},
+			},
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "testns",
+					Name:      "test-secret",
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							Kind:       "Certificate",
+							Name:       "test",
+							UID:        "cert-uid",
+							Controller: func(b bool) *bool { return &b }(true),
+						},
+					},
+				},
+			},
+			chainShouldEvaluate:        true,
+			chainShouldTriggerIssuance: true,
+			expectedEvent:              "Normal Issuing Re-issuance forced by unit test case",
+			expectedConditions: []cmapi.CertificateCondition{
+				{
+					Type:               cmapi.CertificateConditionIssuing,
+					Status:             cmmeta.ConditionTrue,
+					Reason:             forceTriggeredReason,
+					Message:            forceTriggeredMessage,
+					LastTransitionTime: &metaNow,
+				},
+			},
+		},
+		"should set the 'Paused' status condition and not evaluate the policy chain if the Certificate is paused": {
+			certificate: &cmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test", Annotations: map[string]string{
+					cmapi.CertificatePausedAnnotationKey: "true",
+				}},
+			},
+			chainShouldTriggerIssuance: true,
+			expectedConditions: []cmapi.CertificateCondition{
+				{
+					Type:               cmapi.CertificateConditionPaused,
+					Status:             cmmeta.ConditionTrue,
+					Reason:             "Paused",
+					Message:            "Certificate is paused using the cert-manager.io/paused annotation",
+					LastTransitionTime: &metaNow,
+				},
+			},
+		},
+		"should not update the Certificate if it is paused and the 'Paused' status condition is already set": {
+			certificate: &cmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test", Annotations: map[string]string{
+					cmapi.CertificatePausedAnnotationKey: "true",
+				}},
+				Status: cmapi.CertificateStatus{
+					Conditions: []cmapi.CertificateCondition{
+						{
+							Type:   cmapi.CertificateConditionPaused,
+							Status: cmmeta.ConditionTrue,
+							Reason: "Paused",
+						},
+					},
+				},
+			},
+			chainShouldTriggerIssuance: true,
+		},
+		"should not time out an issuance in progress if the Certificate is paused": {
+			certificate: &cmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test", Annotations: map[string]string{
+					cmapi.CertificatePausedAnnotationKey: "true",
+				}},
+				Status: cmapi.CertificateStatus{
+					Conditions: []cmapi.CertificateCondition{
+						{
+							Type:               cmapi.CertificateConditionIssuing,
+							Status:             cmmeta.ConditionTrue,
+							LastTransitionTime: &metav1.Time{Time: now.Add(-time.Hour)},
+						},
+						{
+							Type:   cmapi.CertificateConditionPaused,
+							Status: cmmeta.ConditionTrue,
+							Reason: "Paused",
+						},
+					},
+				},
+			},
+			defaultIssuanceTimeout: time.Minute,
+		},
+		"should remove the 'Paused' status condition once the Certificate is no longer paused": {
+			certificate: &cmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test"},
+				Status: cmapi.CertificateStatus{
+					Conditions: []cmapi.CertificateCondition{
+						{
+							Type:   cmapi.CertificateConditionReady,
+							Status: cmmeta.ConditionTrue,
+						},
+						{
+							Type:   cmapi.CertificateConditionPaused,
+							Status: cmmeta.ConditionTrue,
+							Reason: "Paused",
+						},
+					},
+				},
+			},
+			chainShouldTriggerIssuance: true,
+			expectedConditions: []cmapi.CertificateCondition{
+				{
+					Type:   cmapi.CertificateConditionReady,
+					Status: cmmeta.ConditionTrue,
+				},
+			},
+		},
 	}
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -332,7 +809,12 @@ func Test_controller_ProcessItem(t *testing.T) {
 			for _, req := range test.requests {
 				builder.CertManagerObjects = append(builder.CertManagerObjects, req)
 			}
+			if test.issuer != nil {
+				builder.CertManagerObjects = append(builder.CertManagerObjects, test.issuer)
+			}
 			builder.Init()
+			builder.Context.CertificateOptions.ResyncOnIssuerReady = test.resyncOnIssuerReady
+			builder.Context.CertificateOptions.DefaultIssuanceTimeout = test.defaultIssuanceTimeout
 
 			// Register informers used by the controller using the registration wrapper
 			w := &controllerWrapper{}
@@ -363,6 +845,9 @@ func Test_controller_ProcessItem(t *testing.T) {
 				}
 				expectedCert := test.certificate.DeepCopy()
 				expectedCert.Status.Conditions = test.expectedConditions
+				if test.expectedLastFailureTime != nil {
+					expectedCert.Status.LastFailureTime = test.expectedLastFailureTime
+				}
 				builder.ExpectedActions = append(builder.ExpectedActions,
 					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
 						cmapi.SchemeGroupVersion.WithResource("certificates"),
@@ -503,3 +988,123 @@ func Test_shouldBackoffReissuingOnFailure(t *testing.T) {
 		})
 	}
 }
+
+func Test_enqueueCertificatesOnIssuerReady(t *testing.T) {
+	ready := gen.AddIssuerCondition(cmapi.IssuerCondition{Type: cmapi.IssuerConditionReady, Status: cmmeta.ConditionTrue})
+	notReady := gen.AddIssuerCondition(cmapi.IssuerCondition{Type: cmapi.IssuerConditionReady, Status: cmmeta.ConditionFalse})
+
+	certificates := []*cmapi.Certificate{
+		gen.Certificate("issuer-cert", gen.SetCertificateNamespace("testns"),
+			gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "test-issuer"})),
+		gen.Certificate("issuer-kind-cert", gen.SetCertificateNamespace("testns"),
+			gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "test-issuer", Kind: cmapi.IssuerKind, Group: "cert-manager.io"})),
+		gen.Certificate("other-namespace-cert", gen.SetCertificateNamespace("otherns"),
+			gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "test-issuer"})),
+		gen.Certificate("external-issuer-cert", gen.SetCertificateNamespace("testns"),
+			gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "test-issuer", Kind: "Issuer", Group: "example.com"})),
+		gen.Certificate("cluster-issuer-cert", gen.SetCertificateNamespace("otherns"),
+			gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "test-issuer", Kind: cmapi.ClusterIssuerKind})),
+	}
+
+	tests := map[string]struct {
+		oldIssuer, newIssuer cmapi.GenericIssuer
+		expectedKeys         []string
+	}{
+		"should enqueue Certificates referencing an Issuer that becomes ready": {
+			oldIssuer:    gen.Issuer("test-issuer", gen.SetIssuerNamespace("testns"), notReady),
+			newIssuer:    gen.Issuer("test-issuer", gen.SetIssuerNamespace("testns"), ready),
+			expectedKeys: []string{"testns/issuer-cert", "testns/issuer-kind-cert"},
+		},
+		"should enqueue Certificates referencing an Issuer that becomes ready with no previous condition": {
+			oldIssuer:    gen.Issuer("test-issuer", gen.SetIssuerNamespace("testns")),
+			newIssuer:    gen.Issuer("test-issuer", gen.SetIssuerNamespace("testns"), ready),
+			expectedKeys: []string{"testns/issuer-cert", "testns/issuer-kind-cert"},
+		},
+		"should enqueue Certificates referencing a ClusterIssuer that becomes ready": {
+			oldIssuer:    gen.ClusterIssuer("test-issuer", notReady),
+			newIssuer:    gen.ClusterIssuer("test-issuer", ready),
+			expectedKeys: []string{"otherns/cluster-issuer-cert"},
+		},
+		"should not enqueue Certificates if the issuer was already ready": {
+			oldIssuer: gen.Issuer("test-issuer", gen.SetIssuerNamespace("testns"), ready),
+			newIssuer: gen.Issuer("test-issuer", gen.SetIssuerNamespace("testns"), ready),
+		},
+		"should not enqueue Certificates if the issuer becomes not ready": {
+			oldIssuer: gen.Issuer("test-issuer", gen.SetIssuerNamespace("testns"), ready),
+			newIssuer: gen.Issuer("test-issuer", gen.SetIssuerNamespace("testns"), notReady),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			for _, crt := range certificates {
+				if err := indexer.Add(crt); err != nil {
+					t.Fatal(err)
+				}
+			}
+			queue := workqueue.New()
+			defer queue.ShutDown()
+
+			enqueueCertificatesOnIssuerReady(logtest.TestLogger{T: t}, queue, cmlisters.NewCertificateLister(indexer))(test.oldIssuer, test.newIssuer)
+
+			var keys []string
+			for queue.Len() > 0 {
+				item, _ := queue.Get()
+				keys = append(keys, item.(string))
+				queue.Done(item)
+			}
+			sort.Strings(keys)
+			assert.Equal(t, test.expectedKeys, keys)
+		})
+	}
+}
+
+func Test_renewalCheckTime(t *testing.T) {
+	now := time.Date(2020, 11, 20, 16, 05, 00, 0000, time.UTC)
+	timePtr := func(t time.Time) *time.Time { return &t }
+	tests := []struct {
+		name      string
+		givenCert *cmapi.Certificate
+		want      *time.Time
+	}{
+		{
+			name: "no recheck is scheduled if there is no renewal time",
+			givenCert: gen.Certificate("test",
+				gen.SetCertificateNotBefore(metav1.NewTime(now)),
+			),
+			want: nil,
+		},
+		{
+			name: "recheck at the renewal time of a 1 hour certificate",
+			givenCert: gen.Certificate("test",
+				gen.SetCertificateDuration(time.Hour),
+				gen.SetCertificateNotBefore(metav1.NewTime(now)),
+				gen.SetCertificateNotAfter(metav1.NewTime(now.Add(time.Hour))),
+				gen.SetCertificateRenewalTIme(metav1.NewTime(now.Add(40*time.Minute))),
+			),
+			want: timePtr(now.Add(40 * time.Minute)),
+		},
+		{
+			name: "recheck no sooner than the minimum renewal interval if the renewal time is before the certificate was issued",
+			givenCert: gen.Certificate("test",
+				gen.SetCertificateDuration(time.Hour),
+				gen.SetCertificateNotBefore(metav1.NewTime(now)),
+				gen.SetCertificateNotAfter(metav1.NewTime(now.Add(time.Hour))),
+				gen.SetCertificateRenewalTIme(metav1.NewTime(now.Add(-time.Minute))),
+			),
+			want: timePtr(now.Add(policies.MinimumRenewalInterval)),
+		},
+		{
+			name: "recheck at the renewal time if the issue time is not known",
+			givenCert: gen.Certificate("test",
+				gen.SetCertificateRenewalTIme(metav1.NewTime(now.Add(-time.Minute))),
+			),
+			want: timePtr(now.Add(-time.Minute)),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, renewalCheckTime(tt.givenCert, 0))
+		})
+	}
+}
@@ -19,20 +19,39 @@ package certificates
 import (
 	"crypto"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/rsa"
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/sets"
+	corelisters "k8s.io/client-go/listers/core/v1"
 
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	"github.com/jetstack/cert-manager/pkg/util"
 	"github.com/jetstack/cert-manager/pkg/util/pki"
 )
 
+// SecretApplyFieldManager is the field manager used by cert-manager when
+// applying Secret resources with server-side apply.
+const SecretApplyFieldManager = "cert-manager"
+
+// FieldManagers returns the names of the field managers that the apiserver
+// records for writes made by cert-manager using a client with the given user
+// agent.
+// Writes that do not set a field manager are recorded under a name derived
+// from the user agent, whereas Secret resources are applied using
+// SecretApplyFieldManager.
+func FieldManagers(userAgent string) sets.String {
+	return sets.NewString(SecretApplyFieldManager, util.FieldManagerFromUserAgent(userAgent))
+}
+
 func PrivateKeyMatchesSpec(pk crypto.PrivateKey, spec cmapi.CertificateSpec) ([]string, error) {
 	spec = *spec.DeepCopy()
 	if spec.PrivateKey == nil {
@@ -43,6 +62,8 @@ func PrivateKeyMatchesSpec(pk crypto.PrivateKey, spec cmapi.CertificateSpec) ([]
 		return rsaPrivateKeyMatchesSpec(pk, spec)
 	case cmapi.ECDSAKeyAlgorithm:
 		return ecdsaPrivateKeyMatchesSpec(pk, spec)
+	case cmapi.Ed25519KeyAlgorithm:
+		return ed25519PrivateKeyMatchesSpec(pk, spec)
 	default:
 		return nil, fmt.Errorf("unrecognised key algorithm type %q", spec.PrivateKey.Algorithm)
 	}
@@ -90,6 +111,16 @@ func ecdsaPrivateKeyMatchesSpec(pk crypto.PrivateKey, spec cmapi.CertificateSpec
 	return violations, nil
 }
 
+func ed25519PrivateKeyMatchesSpec(pk crypto.PrivateKey, spec cmapi.CertificateSpec) ([]string, error) {
+	_, ok := pk.(ed25519.PrivateKey)
+	if !ok {
+		return []string{"spec.keyAlgorithm"}, nil
+	}
+
+	// Ed25519 keys have a fixed size, so there is nothing more to compare
+	return nil, nil
+}
+
 // RequestMatchesSpec compares a CertificateRequest with a CertificateSpec
 // and returns a list of field names on the Certificate that do not match their
 // counterpart fields on the CertificateRequest.
@@ -105,12 +136,21 @@ func RequestMatchesSpec(req *cmapi.CertificateRequest, spec cmapi.CertificateSpe
 	if spec.Subject == nil {
 		spec.Subject = &cmapi.X509Subject{}
 	}
+	// Unicode DNS names are encoded into their ASCII form in requests.
+	spec.DNSNames, err = pki.DNSNamesToASCII(spec.DNSNames)
+	if err != nil {
+		return nil, err
+	}
 
 	var violations []string
-	if x509req.Subject.CommonName != spec.CommonName {
+	// The common name of a request for a Certificate without a commonName
+	// may have been derived from its first DNS name.
+	if x509req.Subject.CommonName != spec.CommonName &&
+		(len(spec.CommonName) > 0 || x509req.Subject.CommonName != pki.CommonNameFromDNSNames(spec.DNSNames)) {
 		violations = append(violations, "spec.commonName")
 	}
-	if !util.EqualUnsorted(x509req.DNSNames, spec.DNSNames) {
+	if !util.EqualUnsorted(x509req.DNSNames, spec.DNSNames) &&
+		!util.EqualUnsorted(x509req.DNSNames, WithWildcardApexDomains(spec.DNSNames)) {
 		violations = append(violations, "spec.dnsNames")
 	}
 	if !util.EqualUnsorted(pki.IPAddressesToString(x509req.IPAddresses), spec.IPAddresses) {
@@ -122,6 +162,13 @@ func RequestMatchesSpec(req *cmapi.CertificateRequest, spec cmapi.CertificateSpe
 	if !util.EqualUnsorted(x509req.EmailAddresses, spec.EmailAddresses) {
 		violations = append(violations, "spec.emailAddresses")
 	}
+	registeredIDs, err := pki.RegisteredIDsFromExtensions(x509req.Extensions)
+	if err != nil {
+		return nil, err
+	}
+	if !util.EqualUnsorted(pki.ObjectIdentifiersToString(registeredIDs), spec.RegisteredIDs) {
+		violations = append(violations, "spec.registeredIDs")
+	}
 	if x509req.Subject.SerialNumber != spec.Subject.SerialNumber {
 		violations = append(violations, "spec.subject.serialNumber")
 	}
@@ -149,6 +196,19 @@ func RequestMatchesSpec(req *cmapi.CertificateRequest, spec cmapi.CertificateSpe
 	if req.Spec.IsCA != spec.IsCA {
 		violations = append(violations, "spec.isCA")
 	}
+	maxPathLen, maxPathLenZero, err := pki.MaxPathLenFromCSR(x509req)
+	if err != nil {
+		return nil, err
+	}
+	// A path length constraint is only requested for CA certificates.
+	var specMaxPathLen int
+	var specMaxPathLenZero bool
+	if spec.IsCA {
+		specMaxPathLen, specMaxPathLenZero = spec.MaxPathLen, spec.MaxPathLenZero && spec.MaxPathLen == 0
+	}
+	if maxPathLen != specMaxPathLen || maxPathLenZero != specMaxPathLenZero {
+		violations = append(violations, "spec.maxPathLen")
+	}
 	if !util.EqualKeyUsagesUnsorted(req.Spec.Usages, spec.Usages) {
 		violations = append(violations, "spec.usages")
 	}
@@ -159,10 +219,36 @@ func RequestMatchesSpec(req *cmapi.CertificateRequest, spec cmapi.CertificateSpe
 	if !reflect.DeepEqual(spec.IssuerRef, req.Spec.IssuerRef) {
 		violations = append(violations, "spec.issuerRef")
 	}
+	if !reflect.DeepEqual(spec.FallbackIssuerRef, req.Spec.FallbackIssuerRef) {
+		violations = append(violations, "spec.fallbackIssuerRef")
+	}
 
 	return violations, nil
 }
 
+// WithWildcardApexDomains returns the given DNS names with the apex domain of
+// each wildcard DNS name appended, e.g. 'example.com' for '*.example.com',
+// unless it is already present.
+// The apex domains are requested alongside wildcard DNS names for ACME
+// issuers if the controller is configured to do so, and requests or
+// certificates including them are considered to match the DNS names on the
+// Certificate spec.
+func WithWildcardApexDomains(dnsNames []string) []string {
+	names := sets.NewString(dnsNames...)
+	result := append([]string(nil), dnsNames...)
+	for _, name := range dnsNames {
+		if !strings.HasPrefix(name, "*.") {
+			continue
+		}
+		apex := strings.TrimPrefix(name, "*.")
+		if !names.Has(apex) {
+			names.Insert(apex)
+			result = append(result, apex)
+		}
+	}
+	return result
+}
+
 // SecretDataAltNamesMatchSpec will compare a Secret resource containing certificate
 // data to a CertificateSpec and return a list of 'violations' for any fields that
 // do not match their counterparts.
@@ -174,6 +260,12 @@ func SecretDataAltNamesMatchSpec(secret *corev1.Secret, spec cmapi.CertificateSp
 		return nil, err
 	}
 
+	// Unicode DNS names are encoded into their ASCII form in certificates.
+	spec.DNSNames, err = pki.DNSNamesToASCII(spec.DNSNames)
+	if err != nil {
+		return nil, err
+	}
+
 	var violations []string
 
 	// Perform a 'loose' check on the x509 certificate to determine if the
@@ -189,6 +281,11 @@ func SecretDataAltNamesMatchSpec(secret *corev1.Secret, spec cmapi.CertificateSp
 	if x509cert.Subject.CommonName != "" {
 		allDNSNames.Insert(x509cert.Subject.CommonName)
 	}
+	// The apex domains of wildcard DNS names may have been requested in
+	// addition to the DNS names on the spec.
+	if !allDNSNames.Equal(expectedDNSNames) && allDNSNames.Equal(expectedDNSNames.Union(sets.NewString(WithWildcardApexDomains(spec.DNSNames)...))) {
+		expectedDNSNames = allDNSNames
+	}
 	if !allDNSNames.Equal(expectedDNSNames) {
 		// We know a mismatch occurred, so now determine which fields mismatched.
 		if (spec.CommonName != "" && !allDNSNames.Has(spec.CommonName)) || (x509cert.Subject.CommonName != "" && !expectedDNSNames.Has(x509cert.Subject.CommonName)) {
@@ -275,3 +372,152 @@ func RenewBeforeExpiryDuration(notBefore, notAfter time.Time, specRenewBefore *m
 	}
 	return renewBefore
 }
+
+// PrivateKeyPassphrase will fetch the passphrase used to encrypt the private
+// key stored in the Certificate's Secret.
+// It returns nil if the Certificate does not request an encrypted private key.
+func PrivateKeyPassphrase(secretLister corelisters.SecretLister, crt *cmapi.Certificate) ([]byte, error) {
+	if crt.Spec.PrivateKey == nil || crt.Spec.PrivateKey.EncryptionPassphraseSecretRef == nil {
+		return nil, nil
+	}
+
+	ref := crt.Spec.PrivateKey.EncryptionPassphraseSecretRef
+	pwSecret, err := secretLister.Secrets(crt.Namespace).Get(ref.Name)
+	if err != nil {
+		return nil, fmt.Errorf("fetching private key encryption passphrase from Secret: %v", err)
+	}
+	if pwSecret.Data == nil || len(pwSecret.Data[ref.Key]) == 0 {
+		return nil, fmt.Errorf("private key encryption passphrase Secret contains no data for key %q", ref.Key)
+	}
+
+	return pwSecret.Data[ref.Key], nil
+}
+
+// DecryptSecretPrivateKey will return a copy of the given Secret with an
+// encrypted private key replaced by its unencrypted encoding, as it would have
+// been stored had encryption not been requested.
+// If the Secret does not contain an encrypted private key, or the Certificate
+// does not configure a passphrase, the Secret is returned unmodified.
+func DecryptSecretPrivateKey(secretLister corelisters.SecretLister, crt *cmapi.Certificate, secret *corev1.Secret) (*corev1.Secret, error) {
+	if secret == nil || !pki.IsEncryptedPKCS8PrivateKey(secret.Data[corev1.TLSPrivateKeyKey]) {
+		return secret, nil
+	}
+
+	passphrase, err := PrivateKeyPassphrase(secretLister, crt)
+	if err != nil || passphrase == nil {
+		return secret, err
+	}
+
+	pk, err := pki.DecryptPKCS8PrivateKey(secret.Data[corev1.TLSPrivateKeyKey], passphrase)
+	if err != nil {
+		return secret, err
+	}
+
+	pkData, err := pki.EncodePrivateKey(pk, crt.Spec.PrivateKey.Encoding)
+	if err != nil {
+		return secret, err
+	}
+
+	secret = secret.DeepCopy()
+	secret.Data[corev1.TLSPrivateKeyKey] = pkData
+	return secret, nil
+}
+
+// SecretToMigrate returns the Secret that a Certificate stored its certificate
+// and private key in before `spec.secretName` was changed, if it should be
+// migrated to the new Secret name.
+// It returns nil if the Certificate does not set the migrate Secret on rename
+// annotation, if the Secret named `spec.secretName` already exists, or if
+// there is not exactly one other Secret in the namespace that is annotated as
+// belonging to the Certificate and contains a certificate.
+func SecretToMigrate(secretLister corelisters.SecretLister, crt *cmapi.Certificate) (*corev1.Secret, error) {
+	if crt.Annotations[cmapi.MigrateSecretOnRenameAnnotationKey] != "true" {
+		return nil, nil
+	}
+
+	_, err := secretLister.Secrets(crt.Namespace).Get(crt.Spec.SecretName)
+	if err == nil {
+		return nil, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	secrets, err := secretLister.Secrets(crt.Namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	var candidates []*corev1.Secret
+	for _, secret := range secrets {
+		if secret.Annotations[cmapi.CertificateNameKey] != crt.Name {
+			continue
+		}
+		if crt.Status.NextPrivateKeySecretName != nil && secret.Name == *crt.Status.NextPrivateKeySecretName {
+			continue
+		}
+		if len(secret.Data[corev1.TLSCertKey]) == 0 {
+			continue
+		}
+		candidates = append(candidates, secret)
+	}
+	if len(candidates) != 1 {
+		return nil, nil
+	}
+
+	return candidates[0], nil
+}
+
+// PrivateKeyRotationPolicy returns the private key rotation policy of the
+// given Certificate, defaulting to Never if none is set.
+func PrivateKeyRotationPolicy(crt *cmapi.Certificate) cmapi.PrivateKeyRotationPolicy {
+	if crt.Spec.PrivateKey == nil || crt.Spec.PrivateKey.RotationPolicy == "" {
+		return cmapi.RotationPolicyNever
+	}
+	return crt.Spec.PrivateKey.RotationPolicy
+}
+
+// NextPrivateKeyMatchesStoredKey returns false if the Certificate has a
+// rotation policy of Never and the given next private key is not the private
+// key currently stored in the Certificate's Secret.
+// It returns true if there is no valid private key stored in the Secret, or if
+// the stored private key does not match the algorithm and size requested on
+// the Certificate, as a new private key will be generated in that case
+// regardless of policy.
+func NextPrivateKeyMatchesStoredKey(secretLister corelisters.SecretLister, crt *cmapi.Certificate, nextPK crypto.Signer) (bool, error) {
+	if PrivateKeyRotationPolicy(crt) != cmapi.RotationPolicyNever {
+		return true, nil
+	}
+
+	secret, err := secretLister.Secrets(crt.Namespace).Get(crt.Spec.SecretName)
+	if apierrors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if secret.Data == nil || len(secret.Data[corev1.TLSPrivateKeyKey]) == 0 {
+		return true, nil
+	}
+
+	secret, err = DecryptSecretPrivateKey(secretLister, crt, secret)
+	if err != nil {
+		return false, err
+	}
+	storedPK, err := pki.DecodePrivateKeyBytes(secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return true, nil
+	}
+	violations, err := PrivateKeyMatchesSpec(storedPK, crt.Spec)
+	if err != nil || len(violations) > 0 {
+		return true, nil
+	}
+
+	return pki.PublicKeysEqual(storedPK.Public(), nextPK.Public())
+}
+
+// IsPaused returns true if the Certificate has been paused using the
+// CertificatePausedAnnotationKey annotation, in which case it must not be
+// reconciled.
+func IsPaused(crt *cmapi.Certificate) bool {
+	return crt.Annotations[cmapi.CertificatePausedAnnotationKey] == "true"
+}
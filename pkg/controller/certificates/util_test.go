@@ -18,6 +18,9 @@ package certificates
 
 import (
 	"crypto"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"reflect"
 	"testing"
 	"time"
@@ -46,6 +49,14 @@ func mustGenerateECDSA(t *testing.T, keySize int) crypto.PrivateKey {
 	return pk
 }
 
+func mustGenerateEd25519(t *testing.T) crypto.PrivateKey {
+	pk, err := pki.GenerateEd25519PrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pk
+}
+
 func TestPrivateKeyMatchesSpec(t *testing.T) {
 	tests := map[string]struct {
 		key          crypto.PrivateKey
@@ -76,6 +87,15 @@ func TestPrivateKeyMatchesSpec(t *testing.T) {
 			expectedSize: pki.ECCurve521,
 			violations:   []string{"spec.keySize"},
 		},
+		"should match if algorithm is correct (Ed25519)": {
+			key:          mustGenerateEd25519(t),
+			expectedAlgo: cmapi.Ed25519KeyAlgorithm,
+		},
+		"should not match if Ed25519 is expected but the key is ECDSA": {
+			key:          mustGenerateECDSA(t, pki.ECCurve256),
+			expectedAlgo: cmapi.Ed25519KeyAlgorithm,
+			violations:   []string{"spec.keyAlgorithm"},
+		},
 		"should not match if keyAlgorithm is incorrect": {
 			key:          mustGenerateECDSA(t, pki.ECCurve256),
 			expectedAlgo: cmapi.RSAKeyAlgorithm,
@@ -146,6 +166,14 @@ func TestSecretDataAltNamesMatchSpec(t *testing.T) {
 				DNSNames: []string{"at", "least", "one", "cn"},
 			}),
 		},
+		"should match if the certificate includes the apex domain of a wildcard dnsName": {
+			spec: cmapi.CertificateSpec{
+				DNSNames: []string{"*.example.com"},
+			},
+			data: selfSignCertificate(t, cmapi.CertificateSpec{
+				DNSNames: []string{"*.example.com", "example.com"},
+			}),
+		},
 		"should match if commonName is one of the requested requested dnsNames": {
 			spec: cmapi.CertificateSpec{
 				DNSNames: []string{"at", "least", "one"},
@@ -346,3 +374,220 @@ func TestRenewBeforeExpiryDuration(t *testing.T) {
 		})
 	}
 }
+
+func TestRequestMatchesSpecDerivedCommonName(t *testing.T) {
+	pk, err := pki.GenerateRSAPrivateKey(2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	requestWithCommonName := func(t *testing.T, commonName string, dnsNames ...string) *cmapi.CertificateRequest {
+		csrDER, err := pki.EncodeCSR(&x509.CertificateRequest{
+			Subject:  pkix.Name{CommonName: commonName},
+			DNSNames: dnsNames,
+		}, pk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &cmapi.CertificateRequest{
+			Spec: cmapi.CertificateRequestSpec{
+				Request: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER}),
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		requestCommonName string
+		spec              cmapi.CertificateSpec
+		violation         bool
+	}{
+		"common name derived from the first DNS name matches an empty spec.commonName": {
+			requestCommonName: "example.com",
+			spec:              cmapi.CertificateSpec{DNSNames: []string{"example.com", "www.example.com"}},
+		},
+		"common name other than the first DNS name does not match an empty spec.commonName": {
+			requestCommonName: "www.example.com",
+			spec:              cmapi.CertificateSpec{DNSNames: []string{"example.com", "www.example.com"}},
+			violation:         true,
+		},
+		"common name derived from the first DNS name does not match a different spec.commonName": {
+			requestCommonName: "example.com",
+			spec:              cmapi.CertificateSpec{CommonName: "cn", DNSNames: []string{"example.com"}},
+			violation:         true,
+		},
+		"empty common name matches an empty spec.commonName": {
+			spec: cmapi.CertificateSpec{DNSNames: []string{"example.com"}},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := requestWithCommonName(t, test.requestCommonName, test.spec.DNSNames...)
+			violations, err := RequestMatchesSpec(req, test.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			hasViolation := false
+			for _, v := range violations {
+				if v == "spec.commonName" {
+					hasViolation = true
+				}
+			}
+			if hasViolation != test.violation {
+				t.Errorf("expected spec.commonName violation=%t, got violations %v", test.violation, violations)
+			}
+		})
+	}
+}
+
+func TestRequestMatchesSpecMaxPathLen(t *testing.T) {
+	pk, err := pki.GenerateRSAPrivateKey(2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	requestFor := func(t *testing.T, spec cmapi.CertificateSpec) *cmapi.CertificateRequest {
+		csr, err := pki.GenerateCSR(&cmapi.Certificate{Spec: spec})
+		if err != nil {
+			t.Fatal(err)
+		}
+		csrDER, err := pki.EncodeCSR(csr, pk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &cmapi.CertificateRequest{
+			Spec: cmapi.CertificateRequestSpec{
+				Request: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER}),
+				IsCA:    spec.IsCA,
+			},
+		}
+	}
+	ca := func(maxPathLen int, maxPathLenZero bool) cmapi.CertificateSpec {
+		return cmapi.CertificateSpec{CommonName: "ca", IsCA: true, MaxPathLen: maxPathLen, MaxPathLenZero: maxPathLenZero}
+	}
+
+	tests := map[string]struct {
+		requestSpec cmapi.CertificateSpec
+		spec        cmapi.CertificateSpec
+		violation   bool
+	}{
+		"unchanged path length": {
+			requestSpec: ca(1, false),
+			spec:        ca(1, false),
+		},
+		"unchanged zero path length": {
+			requestSpec: ca(0, true),
+			spec:        ca(0, true),
+		},
+		"path length added": {
+			requestSpec: ca(0, false),
+			spec:        ca(0, true),
+			violation:   true,
+		},
+		"path length changed": {
+			requestSpec: ca(1, false),
+			spec:        ca(2, false),
+			violation:   true,
+		},
+		"path length removed": {
+			requestSpec: ca(1, false),
+			spec:        ca(0, false),
+			violation:   true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			violations, err := RequestMatchesSpec(requestFor(t, test.requestSpec), test.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			hasViolation := false
+			for _, v := range violations {
+				if v == "spec.maxPathLen" {
+					hasViolation = true
+				}
+			}
+			if hasViolation != test.violation {
+				t.Errorf("expected spec.maxPathLen violation=%t, got violations %v", test.violation, violations)
+			}
+		})
+	}
+}
+
+func TestWithWildcardApexDomains(t *testing.T) {
+	tests := map[string]struct {
+		dnsNames []string
+		expected []string
+	}{
+		"DNS names without wildcards are unchanged": {
+			dnsNames: []string{"example.com", "www.example.com"},
+			expected: []string{"example.com", "www.example.com"},
+		},
+		"the apex domain of a wildcard DNS name is appended": {
+			dnsNames: []string{"*.example.com", "www.example.org"},
+			expected: []string{"*.example.com", "www.example.org", "example.com"},
+		},
+		"an apex domain that is already requested is not appended": {
+			dnsNames: []string{"example.com", "*.example.com", "*.a.example.net"},
+			expected: []string{"example.com", "*.example.com", "*.a.example.net", "a.example.net"},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := WithWildcardApexDomains(test.dnsNames); !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("expected %v but got %v", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestRequestMatchesSpecWildcardApex(t *testing.T) {
+	pk, err := pki.GenerateRSAPrivateKey(2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		requestDNSNames []string
+		spec            cmapi.CertificateSpec
+		violation       bool
+	}{
+		"request with the apex domain of a wildcard DNS name matches the spec": {
+			requestDNSNames: []string{"*.example.com", "example.com"},
+			spec:            cmapi.CertificateSpec{DNSNames: []string{"*.example.com"}},
+		},
+		"request without the apex domain of a wildcard DNS name matches the spec": {
+			requestDNSNames: []string{"*.example.com"},
+			spec:            cmapi.CertificateSpec{DNSNames: []string{"*.example.com"}},
+		},
+		"request with a DNS name other than the apex domain does not match the spec": {
+			requestDNSNames: []string{"*.example.com", "www.example.com"},
+			spec:            cmapi.CertificateSpec{DNSNames: []string{"*.example.com"}},
+			violation:       true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			csrDER, err := pki.EncodeCSR(&x509.CertificateRequest{DNSNames: test.requestDNSNames}, pk)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req := &cmapi.CertificateRequest{
+				Spec: cmapi.CertificateRequestSpec{
+					Request: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER}),
+				},
+			}
+
+			violations, err := RequestMatchesSpec(req, test.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			hasViolation := false
+			for _, v := range violations {
+				if v == "spec.dnsNames" {
+					hasViolation = true
+				}
+			}
+			if hasViolation != test.violation {
+				t.Errorf("expected spec.dnsNames violation=%t, got violations %v", test.violation, violations)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificates
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	"github.com/jetstack/cert-manager/pkg/issuer"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+)
+
+// queueingController is the interface implemented by the controllers in the
+// certificates packages, as consumed by controllerpkg.Builder.
+type queueingController interface {
+	Register(*controllerpkg.Context) (workqueue.RateLimitingInterface, []cache.InformerSynced, error)
+	ProcessItem(ctx context.Context, key string) error
+}
+
+// Workers returns the number of workers a certificates controller should be
+// run with. These workers process Certificates whose issuer type does not
+// have its own number of workers; the workers dedicated to an issuer type are
+// run by WorkerPools.
+func Workers(opts controllerpkg.CertificateOptions) int {
+	return opts.Workers
+}
+
+// WorkerPools wraps a certificates controller so that the workers processing
+// Certificates are split into pools by issuer type. Each issuer type that has
+// its own number of workers configured gets its own queue, processed by that
+// many workers. All other Certificates are processed by the workers of the
+// wrapped controller, so that a slow issuer type cannot hold up Certificates
+// using other issuer types.
+type WorkerPools struct {
+	queueingController
+
+	certificateLister cmlisters.CertificateLister
+	issuerHelper      issuer.Helper
+
+	// pools are the queues of the issuer types that have their own workers,
+	// keyed by issuer type. pools is nil if no issuer type has its own
+	// workers.
+	pools map[string]workqueue.RateLimitingInterface
+}
+
+// NewWorkerPools wraps the given certificates controller with WorkerPools.
+func NewWorkerPools(ctrl queueingController) *WorkerPools {
+	return &WorkerPools{queueingController: ctrl}
+}
+
+// Register registers the wrapped controller and starts the workers of each
+// issuer type that has its own number of workers. These workers run until
+// the root context is cancelled.
+func (w *WorkerPools) Register(ctx *controllerpkg.Context) (workqueue.RateLimitingInterface, []cache.InformerSynced, error) {
+	queue, mustSync, err := w.queueingController.Register(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	opts := ctx.CertificateOptions
+	if len(opts.WorkersPerIssuerType) == 0 {
+		return queue, mustSync, nil
+	}
+
+	certificateInformer := ctx.SharedInformerFactory.Certmanager().V1().Certificates()
+	issuerInformer := ctx.SharedInformerFactory.Certmanager().V1().Issuers()
+	mustSync = append(mustSync, certificateInformer.Informer().HasSynced, issuerInformer.Informer().HasSynced)
+
+	var clusterIssuerLister cmlisters.ClusterIssuerLister
+	if ctx.Namespace == "" {
+		clusterIssuerInformer := ctx.SharedInformerFactory.Certmanager().V1().ClusterIssuers()
+		mustSync = append(mustSync, clusterIssuerInformer.Informer().HasSynced)
+		clusterIssuerLister = clusterIssuerInformer.Lister()
+	}
+
+	w.certificateLister = certificateInformer.Lister()
+	w.issuerHelper = issuer.NewHelper(issuerInformer.Lister(), clusterIssuerLister)
+
+	log := logf.FromContext(ctx.RootContext)
+	stopCh := ctx.RootContext.Done()
+	w.pools = make(map[string]workqueue.RateLimitingInterface, len(opts.WorkersPerIssuerType))
+	for issuerType, n := range opts.WorkersPerIssuerType {
+		poolQueue := workqueue.NewRateLimitingQueue(controllerpkg.DefaultItemBasedRateLimiter())
+		w.pools[issuerType] = poolQueue
+
+		poolLog := log.WithValues("issuer_type", issuerType)
+		for i := 0; i < n; i++ {
+			go wait.Until(func() { w.worker(ctx.RootContext, poolLog, poolQueue) }, time.Second, stopCh)
+		}
+	}
+
+	go func() {
+		<-stopCh
+		for _, poolQueue := range w.pools {
+			poolQueue.ShutDown()
+		}
+	}()
+
+	return queue, mustSync, nil
+}
+
+// ProcessItem processes the Certificate with the given key using the wrapped
+// controller. If the issuer type of the Certificate has its own workers, the
+// Certificate is instead added to the queue of that issuer type, to be
+// processed by one of its workers.
+func (w *WorkerPools) ProcessItem(ctx context.Context, key string) error {
+	if w.pools == nil {
+		return w.queueingController.ProcessItem(ctx, key)
+	}
+
+	if poolQueue, ok := w.poolFor(key); ok {
+		poolQueue.Add(key)
+		return nil
+	}
+
+	return w.queueingController.ProcessItem(ctx, key)
+}
+
+// worker processes Certificates from the queue of an issuer type using the
+// wrapped controller until the queue is shut down. Certificates that fail to
+// be processed are retried with backoff.
+func (w *WorkerPools) worker(ctx context.Context, log logr.Logger, queue workqueue.RateLimitingInterface) {
+	for {
+		obj, shutdown := queue.Get()
+		if shutdown {
+			return
+		}
+
+		func() {
+			defer queue.Done(obj)
+			key, ok := obj.(string)
+			if !ok {
+				return
+			}
+			log := log.WithValues("key", key)
+			log.V(logf.DebugLevel).Info("syncing item")
+
+			if err := w.queueingController.ProcessItem(ctx, key); err != nil {
+				log.Error(err, "re-queuing item due to error processing")
+				queue.AddRateLimited(obj)
+				return
+			}
+			log.V(logf.DebugLevel).Info("finished processing work item")
+			queue.Forget(obj)
+		}()
+	}
+}
+
+// poolFor returns the queue of the issuer type of the Certificate with the
+// given key, if that issuer type has its own workers. If the Certificate or
+// its issuer cannot be found, false is returned and the wrapped controller is
+// left to handle the error.
+func (w *WorkerPools) poolFor(key string) (workqueue.RateLimitingInterface, bool) {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return nil, false
+	}
+	crt, err := w.certificateLister.Certificates(namespace).Get(name)
+	if err != nil {
+		return nil, false
+	}
+	poolQueue, ok := w.pools[w.issuerType(crt)]
+	return poolQueue, ok
+}
+
+// issuerType returns the type of the issuer referenced by the Certificate,
+// e.g. 'venafi', or the API group of the issuer if it is an external issuer.
+// An empty string is returned if the type cannot be determined.
+func (w *WorkerPools) issuerType(crt *cmapi.Certificate) string {
+	ref := crt.Spec.IssuerRef
+	if ref.Group != "" && ref.Group != certmanager.GroupName {
+		return ref.Group
+	}
+
+	iss, err := w.issuerHelper.GetGenericIssuer(ref, crt.Namespace)
+	if err != nil {
+		return ""
+	}
+	issuerType, err := apiutil.NameForIssuer(iss)
+	if err != nil {
+		return ""
+	}
+	return issuerType
+}
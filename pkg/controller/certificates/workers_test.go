@@ -0,0 +1,171 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificates
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestWorkers(t *testing.T) {
+	tests := map[string]struct {
+		opts     controllerpkg.CertificateOptions
+		expected int
+	}{
+		"no workers configured uses the default of the controller": {
+			expected: 0,
+		},
+		"no per issuer type workers runs the default number of workers": {
+			opts:     controllerpkg.CertificateOptions{Workers: 5},
+			expected: 5,
+		},
+		"per issuer type workers are run separately from the default number of workers": {
+			opts: controllerpkg.CertificateOptions{
+				Workers:              5,
+				WorkersPerIssuerType: map[string]int{"venafi": 2, "acme": 10},
+			},
+			expected: 5,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if workers := Workers(test.opts); workers != test.expected {
+				t.Errorf("expected %d workers but got %d", test.expected, workers)
+			}
+		})
+	}
+}
+
+// fakeQueueingController sends the keys of the items it processes to
+// processed. Processing the keys in blocked waits until unblock is closed.
+type fakeQueueingController struct {
+	queue     workqueue.RateLimitingInterface
+	processed chan string
+	blocked   map[string]bool
+	unblock   chan struct{}
+}
+
+func (f *fakeQueueingController) Register(*controllerpkg.Context) (workqueue.RateLimitingInterface, []cache.InformerSynced, error) {
+	return f.queue, nil, nil
+}
+
+func (f *fakeQueueingController) ProcessItem(_ context.Context, key string) error {
+	if f.blocked[key] {
+		<-f.unblock
+	}
+	f.processed <- key
+	return nil
+}
+
+func TestWorkerPools(t *testing.T) {
+	venafiIssuer := gen.Issuer("venafi", gen.SetIssuerVenafi(cmapi.VenafiIssuer{}))
+	acmeIssuer := gen.Issuer("acme", gen.SetIssuerACME(cmacme.ACMEIssuer{}))
+
+	venafiCrt := gen.Certificate("venafi-crt", gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: venafiIssuer.Name}))
+	acmeCrt := gen.Certificate("acme-crt", gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: acmeIssuer.Name}))
+	externalCrt := gen.Certificate("external-crt", gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "external", Kind: "ExternalIssuer", Group: "example.io"}))
+
+	rootCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	builder := &testpkg.Builder{
+		T:                  t,
+		CertManagerObjects: []runtime.Object{venafiIssuer, acmeIssuer, venafiCrt, acmeCrt, externalCrt},
+		Context: &controllerpkg.Context{
+			RootContext: rootCtx,
+			CertificateOptions: controllerpkg.CertificateOptions{
+				Workers:              1,
+				WorkersPerIssuerType: map[string]int{"venafi": 2, "example.io": 3},
+			},
+		},
+	}
+	builder.Init()
+	defer builder.Stop()
+
+	key := func(crt *cmapi.Certificate) string {
+		k, err := cache.MetaNamespaceKeyFunc(crt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return k
+	}
+
+	ctrl := &fakeQueueingController{
+		queue:     workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		processed: make(chan string, 10),
+		blocked:   map[string]bool{key(venafiCrt): true},
+		unblock:   make(chan struct{}),
+	}
+	w := NewWorkerPools(ctrl)
+	if _, _, err := w.Register(builder.Context); err != nil {
+		t.Fatal(err)
+	}
+	builder.Start()
+
+	for k, expected := range map[string]string{
+		key(venafiCrt):   "venafi",
+		key(acmeCrt):     "",
+		key(externalCrt): "example.io",
+		"missing/crt":    "",
+	} {
+		pool, ok := w.poolFor(k)
+		if expected == "" {
+			if ok {
+				t.Errorf("expected Certificate %q to be processed by the default workers", k)
+			}
+			continue
+		}
+		if !ok || pool != w.pools[expected] {
+			t.Errorf("expected Certificate %q to be processed by the workers for issuer type %q", k, expected)
+		}
+	}
+
+	// the venafi Certificate is handed over to the venafi workers, where it
+	// blocks, without holding up the acme Certificate
+	if err := w.ProcessItem(context.Background(), key(venafiCrt)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.ProcessItem(context.Background(), key(acmeCrt)); err != nil {
+		t.Fatal(err)
+	}
+	if processed := <-ctrl.processed; processed != key(acmeCrt) {
+		t.Errorf("expected %q to be processed first but got %q", key(acmeCrt), processed)
+	}
+
+	close(ctrl.unblock)
+	select {
+	case processed := <-ctrl.processed:
+		if processed != key(venafiCrt) {
+			t.Errorf("expected %q to be processed but got %q", key(venafiCrt), processed)
+		}
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Errorf("timed out waiting for %q to be processed", key(venafiCrt))
+	}
+}
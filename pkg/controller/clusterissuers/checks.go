@@ -66,6 +66,11 @@ func (c *controller) issuersForSecret(secret *corev1.Secret) ([]*v1.ClusterIssue
 					continue
 				}
 			}
+		case iss.Spec.AWSPCA != nil:
+			if iss.Spec.AWSPCA.SecretAccessKey.Name == secret.Name {
+				affected = append(affected, iss)
+				continue
+			}
 		case iss.Spec.Vault != nil:
 			if iss.Spec.Vault.Auth.TokenSecretRef != nil {
 				if iss.Spec.Vault.Auth.TokenSecretRef.Name == secret.Name {
@@ -91,6 +91,25 @@ type IssuerOptions struct {
 	// IssuerAmbientCredentials controls whether an issuer should pick up ambient
 	// credentials, such as those from metadata services, to construct clients.
 	IssuerAmbientCredentials bool
+
+	// MaxConcurrentSignsPerIssuer is the maximum number of CertificateRequests
+	// that may be signed concurrently by a single issuer. Zero means no limit.
+	MaxConcurrentSignsPerIssuer int
+
+	// FallbackIssuerFailureThreshold is the number of times signing a
+	// CertificateRequest with its issuerRef must fail before its
+	// fallbackIssuerRef is used instead.
+	FallbackIssuerFailureThreshold int
+
+	// FallbackIssuerPendingTimeout is how long the issuer referenced by the
+	// issuerRef of a CertificateRequest may be missing or not ready before its
+	// fallbackIssuerRef is used instead.
+	FallbackIssuerPendingTimeout time.Duration
+
+	// EnableVerbatimCSRSigning allows CertificateRequests to request that
+	// their CSR be signed as-is, using the key usages and subject it contains
+	// rather than those set on the CertificateRequest.
+	EnableVerbatimCSRSigning bool
 }
 
 type ACMEOptions struct {
@@ -110,6 +129,10 @@ type ACMEOptions struct {
 	// HTTP01SolverResourceLimitsMemory defines the ACME pod's resource limits Memory size
 	HTTP01SolverResourceLimitsMemory resource.Quantity
 
+	// HTTP01SolverCleanupDelay is the time to wait after an ACME HTTP01
+	// challenge has become valid before deleting the solver resources
+	HTTP01SolverCleanupDelay time.Duration
+
 	// DNS01CheckAuthoritative is a flag for controlling if auth nss are used
 	// for checking propagation of an RR. This is the ideal scenario
 	DNS01CheckAuthoritative bool
@@ -124,6 +147,11 @@ type ACMEOptions struct {
 
 	// DNS01CheckRetryPeriod is the time the controller should wait between checking if a ACME dns entry exists.
 	DNS01CheckRetryPeriod time.Duration
+
+	// IncludeWildcardApex causes the apex domain to be requested alongside
+	// the wildcard DNS names of Certificates issued by ACME issuers, if it is
+	// not already requested.
+	IncludeWildcardApex bool
 }
 
 type IngressShimOptions struct {
@@ -138,6 +166,41 @@ type CertificateOptions struct {
 	// EnableOwnerRef controls whether the certificate is configured as an owner of
 	// secret where the effective TLS certificate is stored.
 	EnableOwnerRef bool
+
+	// ReadinessClockSkewTolerance is the amount of clock skew tolerated when
+	// comparing the current time against the NotBefore and NotAfter times of
+	// a certificate to determine whether it is Ready.
+	ReadinessClockSkewTolerance time.Duration
+
+	// ResyncOnIssuerReady causes Certificates to be re-synced when their
+	// issuer becomes Ready, retrying a failed issuance without waiting for
+	// the failure back-off to elapse.
+	ResyncOnIssuerReady bool
+
+	// DefaultIssuanceTimeout is the amount of time an issuance may remain in
+	// progress before it is marked as failed, for Certificates that do not
+	// set spec.issuanceTimeout. Zero means issuances never time out.
+	DefaultIssuanceTimeout time.Duration
+
+	// RenewalJitter is the maximum fraction of the renewal window by which
+	// the renewal time of each Certificate is moved earlier, to avoid
+	// Certificates issued at the same time all being renewed at once.
+	RenewalJitter float64
+
+	// IssuanceWebhookURL is the URL that a JSON notification is POSTed to
+	// each time a Certificate has been issued. Notifications are disabled if
+	// empty.
+	IssuanceWebhookURL string
+
+	// Workers is the number of workers each certificates controller runs to
+	// process Certificates whose issuer type is not in WorkersPerIssuerType.
+	Workers int
+
+	// WorkersPerIssuerType is the number of workers each certificates
+	// controller runs exclusively for Certificates of a given issuer type,
+	// keyed by issuer type, so that a slow issuer type cannot hold up
+	// Certificates using other issuer types.
+	WorkersPerIssuerType map[string]int
 }
 
 type SchedulerOptions struct {
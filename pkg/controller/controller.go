@@ -51,6 +51,18 @@ func NewController(
 	runDurationFuncs []runDurationFunc,
 	queue workqueue.RateLimitingInterface,
 ) Interface {
+	return newController(ctx, name, metrics, syncFunc, mustSync, runDurationFuncs, queue)
+}
+
+func newController(
+	ctx context.Context,
+	name string,
+	metrics *metrics.Metrics,
+	syncFunc func(ctx context.Context, key string) error,
+	mustSync []cache.InformerSynced,
+	runDurationFuncs []runDurationFunc,
+	queue workqueue.RateLimitingInterface,
+) *controller {
 	return &controller{
 		ctx:              ctx,
 		name:             name,
@@ -89,6 +101,10 @@ type controller struct {
 
 	// metrics is used to expose Prometheus, shared by all controllers
 	metrics *metrics.Metrics
+
+	// workers, if greater than zero, overrides the number of workers passed
+	// to Run
+	workers int
 }
 
 // Run starts the controller loop
@@ -97,7 +113,11 @@ func (c *controller) Run(workers int, stopCh <-chan struct{}) error {
 	defer cancel()
 	log := logf.FromContext(ctx)
 
-	log.V(logf.DebugLevel).Info("starting control loop")
+	if c.workers > 0 {
+		workers = c.workers
+	}
+
+	log.V(logf.DebugLevel).Info("starting control loop", "workers", workers)
 	// wait for all the informer caches we depend on are synced
 	if !cache.WaitForCacheSync(stopCh, c.mustSync...) {
 		return fmt.Errorf("error waiting for informer caches to sync")
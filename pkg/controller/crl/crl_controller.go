@@ -0,0 +1,414 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crl
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/clock"
+
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cminformers "github.com/jetstack/cert-manager/pkg/client/informers/externalversions"
+	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/util/kube"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+const (
+	ControllerName = "crl"
+
+	// defaultCRLDuration is the period a generated CRL is valid for if the
+	// issuer does not configure one.
+	defaultCRLDuration = 24 * time.Hour
+)
+
+// controller publishes a certificate revocation list for CA Issuers and
+// ClusterIssuers that configure one. Certificates are revoked by annotating
+// the CertificateRequest they were issued for with the time of revocation.
+// Issuers are queued using their namespace/name, and ClusterIssuers using
+// their name only.
+type controller struct {
+	issuerLister             cmlisters.IssuerLister
+	clusterIssuerLister      cmlisters.ClusterIssuerLister
+	certificateRequestLister cmlisters.CertificateRequestLister
+	secretLister             corelisters.SecretLister
+	coreClient               kubernetes.Interface
+	recorder                 record.EventRecorder
+	clock                    clock.Clock
+	queue                    workqueue.RateLimitingInterface
+
+	// clusterResourceNamespace is the namespace that the CA and CRL Secrets
+	// of ClusterIssuers are stored in.
+	clusterResourceNamespace string
+}
+
+func NewController(
+	log logr.Logger,
+	coreClient kubernetes.Interface,
+	factory informers.SharedInformerFactory,
+	cmFactory cminformers.SharedInformerFactory,
+	recorder record.EventRecorder,
+	clock clock.Clock,
+	clusterResourceNamespace string,
+) (*controller, workqueue.RateLimitingInterface, []cache.InformerSynced) {
+	// create a queue used to queue up items to be processed
+	queue := workqueue.NewNamedRateLimitingQueue(controllerpkg.DefaultItemBasedRateLimiter(), ControllerName)
+
+	// obtain references to all the informers used by this controller
+	issuerInformer := cmFactory.Certmanager().V1().Issuers()
+	clusterIssuerInformer := cmFactory.Certmanager().V1().ClusterIssuers()
+	certificateRequestInformer := cmFactory.Certmanager().V1().CertificateRequests()
+	secretsInformer := factory.Core().V1().Secrets()
+
+	c := &controller{
+		issuerLister:             issuerInformer.Lister(),
+		clusterIssuerLister:      clusterIssuerInformer.Lister(),
+		certificateRequestLister: certificateRequestInformer.Lister(),
+		secretLister:             secretsInformer.Lister(),
+		coreClient:               coreClient,
+		recorder:                 recorder,
+		clock:                    clock,
+		queue:                    queue,
+		clusterResourceNamespace: clusterResourceNamespace,
+	}
+
+	issuerInformer.Informer().AddEventHandler(&controllerpkg.QueuingEventHandler{Queue: queue})
+	clusterIssuerInformer.Informer().AddEventHandler(&controllerpkg.QueuingEventHandler{Queue: queue})
+	// When a CertificateRequest is revoked, enqueue the issuers that may
+	// have signed it.
+	certificateRequestInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{WorkFunc: c.enqueueIssuersForCertificateRequest})
+
+	// build a list of InformerSynced functions that will be returned by the Register method.
+	// the controller will only begin processing items once all of these informers have synced.
+	mustSync := []cache.InformerSynced{
+		issuerInformer.Informer().HasSynced,
+		clusterIssuerInformer.Informer().HasSynced,
+		certificateRequestInformer.Informer().HasSynced,
+		secretsInformer.Informer().HasSynced,
+	}
+
+	return c, queue, mustSync
+}
+
+func (c *controller) enqueueIssuersForCertificateRequest(obj interface{}) {
+	cr, ok := obj.(*cmapi.CertificateRequest)
+	if !ok {
+		return
+	}
+	for _, ref := range []*cmmeta.ObjectReference{&cr.Spec.IssuerRef, cr.Spec.FallbackIssuerRef} {
+		if ref == nil || (ref.Group != "" && ref.Group != certmanager.GroupName) {
+			continue
+		}
+		switch ref.Kind {
+		case "", cmapi.IssuerKind:
+			c.queue.Add(cr.Namespace + "/" + ref.Name)
+		case cmapi.ClusterIssuerKind:
+			c.queue.Add(ref.Name)
+		}
+	}
+}
+
+func (c *controller) ProcessItem(ctx context.Context, key string) error {
+	log := logf.FromContext(ctx).WithValues("key", key)
+
+	ctx = logf.NewContext(ctx, log)
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		log.Error(err, "invalid resource key passed to ProcessItem")
+		return nil
+	}
+
+	var issuerObj cmapi.GenericIssuer
+	resourceNamespace := namespace
+	if namespace == "" {
+		issuerObj, err = c.clusterIssuerLister.Get(name)
+		resourceNamespace = c.clusterResourceNamespace
+	} else {
+		issuerObj, err = c.issuerLister.Issuers(namespace).Get(name)
+	}
+	if apierrors.IsNotFound(err) {
+		log.V(logf.DebugLevel).Info("issuer not found for key", "error", err.Error())
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	caSpec := issuerObj.GetSpec().CA
+	if caSpec == nil || caSpec.CRL == nil {
+		return nil
+	}
+	crlSpec := caSpec.CRL
+	ctx = logf.NewContext(ctx, logf.WithResource(log, issuerObj))
+
+	duration := defaultCRLDuration
+	if crlSpec.Duration != nil {
+		duration = crlSpec.Duration.Duration
+	}
+
+	caCerts, caKey, err := kube.SecretTLSKeyPair(ctx, c.secretLister, resourceNamespace, caSpec.SecretName)
+	if err != nil {
+		log.Error(err, "failed to get CA key pair")
+		c.recorder.Eventf(issuerObj, corev1.EventTypeWarning, "CRLError", "Failed to get CA key pair from Secret %q: %v", caSpec.SecretName, err)
+		return err
+	}
+	caCert := caCerts[0]
+
+	revoked, err := c.revokedCertificates(ctx, issuerObj, caCert)
+	if err != nil {
+		return err
+	}
+
+	existing, err := c.secretLister.Secrets(resourceNamespace).Get(crlSpec.SecretName)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	now := c.clock.Now()
+	if existing != nil {
+		if refreshAt, ok := crlUpToDate(existing.Data[cmmeta.CRLKey], caCert, revoked, duration); ok && now.Before(refreshAt) {
+			log.V(logf.DebugLevel).Info("CRL is up to date", "refresh_at", refreshAt)
+			c.queue.AddAfter(issuerKey(issuerObj), refreshAt.Sub(now))
+			return nil
+		}
+	}
+
+	thisUpdate := now.UTC().Truncate(time.Second)
+	crlPEM, err := pki.GenerateCRL(caCert, caKey, revoked, big.NewInt(thisUpdate.Unix()), thisUpdate, thisUpdate.Add(duration))
+	if err != nil {
+		log.Error(err, "failed to generate CRL")
+		c.recorder.Eventf(issuerObj, corev1.EventTypeWarning, "CRLError", "Failed to generate CRL: %v", err)
+		// the CA certificate is not able to sign CRLs, so retrying will
+		// not help until the issuer or CA Secret changes
+		return nil
+	}
+
+	if err := c.writeCRL(ctx, existing, resourceNamespace, crlSpec.SecretName, crlPEM); err != nil {
+		return err
+	}
+
+	log.V(logf.InfoLevel).Info("published CRL", "revoked_certificates", len(revoked))
+	c.recorder.Eventf(issuerObj, corev1.EventTypeNormal, "CRLPublished", "Published CRL listing %d revoked certificates to Secret %q", len(revoked), crlSpec.SecretName)
+	c.queue.AddAfter(issuerKey(issuerObj), refreshInterval(duration))
+
+	return nil
+}
+
+// revokedCertificates returns the certificates signed by the given CA that
+// have been revoked by annotating the CertificateRequest they were issued
+// for, sorted by serial number.
+func (c *controller) revokedCertificates(ctx context.Context, issuerObj cmapi.GenericIssuer, caCert *x509.Certificate) ([]pkix.RevokedCertificate, error) {
+	log := logf.FromContext(ctx)
+
+	var (
+		reqs []*cmapi.CertificateRequest
+		err  error
+	)
+	kind := cmapi.IssuerKind
+	if issuerObj.GetObjectMeta().Namespace == "" {
+		kind = cmapi.ClusterIssuerKind
+		reqs, err = c.certificateRequestLister.List(labels.Everything())
+	} else {
+		reqs, err = c.certificateRequestLister.CertificateRequests(issuerObj.GetObjectMeta().Namespace).List(labels.Everything())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var revoked []pkix.RevokedCertificate
+	for _, cr := range reqs {
+		revokedAt, ok := cr.Annotations[cmapi.CertificateRequestRevokedAtAnnotationKey]
+		if !ok || len(cr.Status.Certificate) == 0 || !referencesIssuer(cr, kind, issuerObj.GetObjectMeta().Name) {
+			continue
+		}
+
+		log := logf.WithRelatedResource(log, cr)
+		revocationTime, err := time.Parse(time.RFC3339, revokedAt)
+		if err != nil {
+			log.Error(err, "ignoring CertificateRequest with invalid revocation time")
+			continue
+		}
+
+		cert, err := pki.DecodeX509CertificateBytes(cr.Status.Certificate)
+		if err != nil {
+			log.Error(err, "ignoring CertificateRequest with invalid certificate")
+			continue
+		}
+		// certificates signed by a previous CA are not listed, as the CRL is
+		// only authoritative for certificates signed by its own issuer
+		if err := cert.CheckSignatureFrom(caCert); err != nil {
+			log.V(logf.DebugLevel).Info("ignoring revoked certificate not signed by the current CA")
+			continue
+		}
+
+		revoked = append(revoked, pkix.RevokedCertificate{
+			SerialNumber:   cert.SerialNumber,
+			RevocationTime: revocationTime.UTC(),
+		})
+	}
+
+	sort.Slice(revoked, func(i, j int) bool {
+		return revoked[i].SerialNumber.Cmp(revoked[j].SerialNumber) < 0
+	})
+
+	return revoked, nil
+}
+
+// writeCRL stores the PEM encoded CRL in the given Secret, creating it if it
+// does not exist.
+func (c *controller) writeCRL(ctx context.Context, existing *corev1.Secret, namespace, name string, crlPEM []byte) error {
+	if existing == nil {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      name,
+			},
+			Data: map[string][]byte{
+				cmmeta.CRLKey: crlPEM,
+			},
+		}
+		_, err := c.coreClient.CoreV1().Secrets(namespace).Create(ctx, secret, metav1.CreateOptions{})
+		return err
+	}
+
+	secret := existing.DeepCopy()
+	if secret.Data == nil {
+		secret.Data = make(map[string][]byte)
+	}
+	secret.Data[cmmeta.CRLKey] = crlPEM
+	_, err := c.coreClient.CoreV1().Secrets(namespace).Update(ctx, secret, metav1.UpdateOptions{})
+	return err
+}
+
+// crlUpToDate returns the time at which the given PEM encoded CRL should be
+// refreshed, and whether it was signed by the given CA and lists exactly the
+// given revoked certificates.
+func crlUpToDate(crlPEM []byte, caCert *x509.Certificate, revoked []pkix.RevokedCertificate, duration time.Duration) (time.Time, bool) {
+	if len(crlPEM) == 0 {
+		return time.Time{}, false
+	}
+	crl, err := pki.DecodeX509CRLBytes(crlPEM)
+	if err != nil {
+		return time.Time{}, false
+	}
+	if err := caCert.CheckCRLSignature(crl); err != nil {
+		return time.Time{}, false
+	}
+	if !sameRevokedCertificates(crl.TBSCertList.RevokedCertificates, revoked) {
+		return time.Time{}, false
+	}
+	// regenerate the CRL early if the configured duration has been reduced
+	if crl.TBSCertList.NextUpdate.Sub(crl.TBSCertList.ThisUpdate) != duration {
+		return time.Time{}, false
+	}
+	return crl.TBSCertList.ThisUpdate.Add(refreshInterval(duration)), true
+}
+
+func sameRevokedCertificates(a, b []pkix.RevokedCertificate) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].SerialNumber.Cmp(b[i].SerialNumber) != 0 || !a[i].RevocationTime.Equal(b[i].RevocationTime) {
+			return false
+		}
+	}
+	return true
+}
+
+// refreshInterval returns how long after its thisUpdate time a CRL with the
+// given validity should be regenerated, leaving relying parties a third of
+// its validity to fetch the new CRL before the current one expires.
+func refreshInterval(duration time.Duration) time.Duration {
+	return duration * 2 / 3
+}
+
+func referencesIssuer(cr *cmapi.CertificateRequest, kind, name string) bool {
+	for _, ref := range []*cmmeta.ObjectReference{&cr.Spec.IssuerRef, cr.Spec.FallbackIssuerRef} {
+		if ref == nil || ref.Name != name || (ref.Group != "" && ref.Group != certmanager.GroupName) {
+			continue
+		}
+		refKind := ref.Kind
+		if refKind == "" {
+			refKind = cmapi.IssuerKind
+		}
+		if refKind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func issuerKey(issuerObj cmapi.GenericIssuer) string {
+	meta := issuerObj.GetObjectMeta()
+	if meta.Namespace == "" {
+		return meta.Name
+	}
+	return fmt.Sprintf("%s/%s", meta.Namespace, meta.Name)
+}
+
+// controllerWrapper wraps the `controller` structure to make it implement
+// the controllerpkg.queueingController interface
+type controllerWrapper struct {
+	*controller
+}
+
+func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.RateLimitingInterface, []cache.InformerSynced, error) {
+	// construct a new named logger to be reused throughout the controller
+	log := logf.FromContext(ctx.RootContext, ControllerName)
+
+	ctrl, queue, mustSync := NewController(log,
+		ctx.Client,
+		ctx.KubeSharedInformerFactory,
+		ctx.SharedInformerFactory,
+		ctx.Recorder,
+		ctx.Clock,
+		ctx.IssuerOptions.ClusterResourceNamespace,
+	)
+	c.controller = ctrl
+
+	return queue, mustSync, nil
+}
+
+func init() {
+	controllerpkg.Register(ControllerName, func(ctx *controllerpkg.Context) (controllerpkg.Interface, error) {
+		return controllerpkg.NewBuilder(ctx, ControllerName).
+			For(&controllerWrapper{}).
+			Complete()
+	})
+}
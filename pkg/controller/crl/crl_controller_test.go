@@ -0,0 +1,276 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crl
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	coretesting "k8s.io/client-go/testing"
+	fakeclock "k8s.io/utils/clock/testing"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+func TestProcessItem(t *testing.T) {
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	revokedAt := now.Add(-time.Hour)
+
+	caKey, err := pki.GenerateRSAPrivateKey(2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             now.Add(-24 * time.Hour),
+		NotAfter:              now.Add(365 * 24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		SubjectKeyId:          []byte{1, 2, 3, 4},
+	}
+	caPEM, caCert, err := pki.SignCertificate(caTemplate, caTemplate, caKey.Public(), caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "ca"},
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       caPEM,
+			corev1.TLSPrivateKeyKey: pki.EncodePKCS1PrivateKey(caKey),
+		},
+	}
+
+	leafKey, err := pki.GenerateRSAPrivateKey(2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leafTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(1234),
+		Subject:      pkix.Name{CommonName: "leaf"},
+		NotBefore:    now.Add(-24 * time.Hour),
+		NotAfter:     now.Add(24 * time.Hour),
+	}
+	leafPEM, _, err := pki.SignCertificate(leafTemplate, caCert, leafKey.Public(), caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issuer := func(crl *cmapi.CAIssuerCRL) *cmapi.Issuer {
+		return &cmapi.Issuer{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test-issuer"},
+			Spec: cmapi.IssuerSpec{
+				IssuerConfig: cmapi.IssuerConfig{
+					CA: &cmapi.CAIssuer{SecretName: "ca", CRL: crl},
+				},
+			},
+		}
+	}
+	request := func(issuerName string, annotations map[string]string) *cmapi.CertificateRequest {
+		return &cmapi.CertificateRequest{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test-cr-" + issuerName, Annotations: annotations},
+			Spec: cmapi.CertificateRequestSpec{
+				IssuerRef: cmmeta.ObjectReference{Name: issuerName},
+			},
+			Status: cmapi.CertificateRequestStatus{Certificate: leafPEM},
+		}
+	}
+	revokedAnnotations := map[string]string{cmapi.CertificateRequestRevokedAtAnnotationKey: revokedAt.Format(time.RFC3339)}
+	revoked := []pkix.RevokedCertificate{{SerialNumber: big.NewInt(1234), RevocationTime: revokedAt}}
+
+	generateCRL := func(revoked []pkix.RevokedCertificate, thisUpdate time.Time, duration time.Duration) []byte {
+		crlPEM, err := pki.GenerateCRL(caCert, caKey, revoked, big.NewInt(thisUpdate.Unix()), thisUpdate, thisUpdate.Add(duration))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return crlPEM
+	}
+	crlSecret := func(crlPEM []byte) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "crl"},
+			Data:       map[string][]byte{cmmeta.CRLKey: crlPEM},
+		}
+	}
+
+	tests := map[string]struct {
+		issuer   *cmapi.Issuer
+		requests []runtime.Object
+		secrets  []runtime.Object
+
+		expectedActions []testpkg.Action
+		expectedEvents  []string
+	}{
+		"do nothing if the issuer does not configure a CRL": {
+			issuer:  issuer(nil),
+			secrets: []runtime.Object{caSecret},
+		},
+		"create a CRL Secret listing certificates revoked from the issuer": {
+			issuer: issuer(&cmapi.CAIssuerCRL{SecretName: "crl"}),
+			requests: []runtime.Object{
+				request("test-issuer", revokedAnnotations),
+				request("other-issuer", revokedAnnotations),
+			},
+			secrets: []runtime.Object{caSecret},
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewCreateAction(
+					corev1.SchemeGroupVersion.WithResource("secrets"),
+					"testns",
+					crlSecret(generateCRL(revoked, now, defaultCRLDuration)),
+				)),
+			},
+			expectedEvents: []string{`Normal CRLPublished Published CRL listing 1 revoked certificates to Secret "crl"`},
+		},
+		"do not list certificates that have not been revoked": {
+			issuer:   issuer(&cmapi.CAIssuerCRL{SecretName: "crl"}),
+			requests: []runtime.Object{request("test-issuer", nil)},
+			secrets:  []runtime.Object{caSecret},
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewCreateAction(
+					corev1.SchemeGroupVersion.WithResource("secrets"),
+					"testns",
+					crlSecret(generateCRL(nil, now, defaultCRLDuration)),
+				)),
+			},
+			expectedEvents: []string{`Normal CRLPublished Published CRL listing 0 revoked certificates to Secret "crl"`},
+		},
+		"do nothing if the existing CRL is up to date": {
+			issuer:   issuer(&cmapi.CAIssuerCRL{SecretName: "crl"}),
+			requests: []runtime.Object{request("test-issuer", revokedAnnotations)},
+			secrets:  []runtime.Object{caSecret, crlSecret(generateCRL(revoked, now.Add(-time.Hour), defaultCRLDuration))},
+		},
+		"update the existing CRL if a certificate has been revoked since it was generated": {
+			issuer:   issuer(&cmapi.CAIssuerCRL{SecretName: "crl"}),
+			requests: []runtime.Object{request("test-issuer", revokedAnnotations)},
+			secrets:  []runtime.Object{caSecret, crlSecret(generateCRL(nil, now.Add(-time.Hour), defaultCRLDuration))},
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewUpdateAction(
+					corev1.SchemeGroupVersion.WithResource("secrets"),
+					"testns",
+					crlSecret(generateCRL(revoked, now, defaultCRLDuration)),
+				)),
+			},
+			expectedEvents: []string{`Normal CRLPublished Published CRL listing 1 revoked certificates to Secret "crl"`},
+		},
+		"update the existing CRL once two thirds of its duration has passed": {
+			issuer:  issuer(&cmapi.CAIssuerCRL{SecretName: "crl", Duration: &metav1.Duration{Duration: 3 * time.Hour}}),
+			secrets: []runtime.Object{caSecret, crlSecret(generateCRL(nil, now.Add(-2*time.Hour), 3*time.Hour))},
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewUpdateAction(
+					corev1.SchemeGroupVersion.WithResource("secrets"),
+					"testns",
+					crlSecret(generateCRL(nil, now, 3*time.Hour)),
+				)),
+			},
+			expectedEvents: []string{`Normal CRLPublished Published CRL listing 0 revoked certificates to Secret "crl"`},
+		},
+		"fire an event if the CA key pair cannot be loaded": {
+			issuer:         issuer(&cmapi.CAIssuerCRL{SecretName: "crl"}),
+			expectedEvents: []string{`Warning CRLError Failed to get CA key pair from Secret "ca": secret "ca" not found`},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Create and initialise a new unit test builder
+			builder := &testpkg.Builder{
+				T:                  t,
+				Clock:              fakeclock.NewFakeClock(now),
+				CertManagerObjects: append([]runtime.Object{test.issuer}, test.requests...),
+				KubeObjects:        test.secrets,
+				ExpectedEvents:     test.expectedEvents,
+				ExpectedActions:    test.expectedActions,
+			}
+			builder.Init()
+
+			// Register informers used by the controller using the registration wrapper
+			w := &controllerWrapper{}
+			_, _, err := w.Register(builder.Context)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Start the informers and begin processing updates
+			builder.Start()
+			defer builder.Stop()
+
+			// errors are only checked through the events and actions
+			// expected, as the controller returns an error to retry when
+			// the CA Secret is missing
+			_ = w.controller.ProcessItem(context.Background(), "testns/test-issuer")
+
+			if err := builder.AllEventsCalled(); err != nil {
+				t.Error(err)
+			}
+			if err := builder.AllActionsExecuted(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestReferencesIssuer(t *testing.T) {
+	tests := map[string]struct {
+		ref      cmmeta.ObjectReference
+		fallback *cmmeta.ObjectReference
+		kind     string
+		expected bool
+	}{
+		"issuer with an empty kind": {
+			ref:      cmmeta.ObjectReference{Name: "test"},
+			kind:     cmapi.IssuerKind,
+			expected: true,
+		},
+		"cluster issuer": {
+			ref:      cmmeta.ObjectReference{Name: "test", Kind: cmapi.ClusterIssuerKind},
+			kind:     cmapi.ClusterIssuerKind,
+			expected: true,
+		},
+		"issuer with the same name but a different kind": {
+			ref:  cmmeta.ObjectReference{Name: "test", Kind: cmapi.ClusterIssuerKind},
+			kind: cmapi.IssuerKind,
+		},
+		"external issuer with the same name": {
+			ref:  cmmeta.ObjectReference{Name: "test", Group: "example.com"},
+			kind: cmapi.IssuerKind,
+		},
+		"fallback issuer": {
+			ref:      cmmeta.ObjectReference{Name: "other"},
+			fallback: &cmmeta.ObjectReference{Name: "test"},
+			kind:     cmapi.IssuerKind,
+			expected: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cr := &cmapi.CertificateRequest{
+				Spec: cmapi.CertificateRequestSpec{IssuerRef: test.ref, FallbackIssuerRef: test.fallback},
+			}
+			if got := referencesIssuer(cr, test.kind, "test"); got != test.expected {
+				t.Errorf("expected %t, got %t", test.expected, got)
+			}
+		})
+	}
+}
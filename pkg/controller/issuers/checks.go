@@ -68,6 +68,11 @@ func (c *controller) issuersForSecret(secret *corev1.Secret) ([]*v1.Issuer, erro
 					continue
 				}
 			}
+		case iss.Spec.AWSPCA != nil:
+			if iss.Spec.AWSPCA.SecretAccessKey.Name == secret.Name {
+				affected = append(affected, iss)
+				continue
+			}
 		case iss.Spec.Vault != nil:
 			if iss.Spec.Vault.Auth.TokenSecretRef != nil {
 				if iss.Spec.Vault.Auth.TokenSecretRef.Name == secret.Name {
@@ -17,10 +17,15 @@ limitations under the License.
 package test
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 
 	"github.com/kr/pretty"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	coretesting "k8s.io/client-go/testing"
 )
 
@@ -86,3 +91,42 @@ func (a *action) Matches(act coretesting.Action) error {
 
 	return fmt.Errorf("unexpected difference between actions: %s", pretty.Diff(objExp.GetObject(), objAct.GetObject()))
 }
+
+// NewApplyAction returns an Action that matches a server-side apply patch of
+// the given object. The applied configuration is decoded into an object of
+// the same type as obj and compared with it, ignoring type metadata.
+func NewApplyAction(resource schema.GroupVersionResource, namespace string, obj runtime.Object) Action {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		panic(fmt.Sprintf("object passed to NewApplyAction is missing metadata: %v", err))
+	}
+
+	return NewCustomMatch(
+		coretesting.NewPatchAction(resource, namespace, accessor.GetName(), types.ApplyPatchType, nil),
+		func(exp, act coretesting.Action) error {
+			patchAction, ok := act.(coretesting.PatchAction)
+			if !ok {
+				return fmt.Errorf("expected a patch action, but got %T", act)
+			}
+			if patchAction.GetPatchType() != types.ApplyPatchType {
+				return fmt.Errorf("expected patch type %q, but got %q", types.ApplyPatchType, patchAction.GetPatchType())
+			}
+			if patchAction.GetName() != accessor.GetName() {
+				return fmt.Errorf("expected patch of %q, but got %q", accessor.GetName(), patchAction.GetName())
+			}
+
+			applied := reflect.New(reflect.TypeOf(obj).Elem()).Interface().(runtime.Object)
+			if err := json.Unmarshal(patchAction.GetPatch(), applied); err != nil {
+				return fmt.Errorf("failed to decode applied configuration: %v", err)
+			}
+			applied.GetObjectKind().SetGroupVersionKind(schema.GroupVersionKind{})
+			expected := obj.DeepCopyObject()
+			expected.GetObjectKind().SetGroupVersionKind(schema.GroupVersionKind{})
+
+			if !reflect.DeepEqual(expected, applied) {
+				return fmt.Errorf("unexpected difference between applied configurations: %s", pretty.Diff(expected, applied))
+			}
+			return nil
+		},
+	)
+}
@@ -17,11 +17,15 @@ limitations under the License.
 package test
 
 import (
+	"encoding/json"
 	"reflect"
 	"testing"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	coretesting "k8s.io/client-go/testing"
 )
 
@@ -74,3 +78,52 @@ func ObjectDeletedReactor(t *testing.T, b *Builder, obj runtime.Object) coretest
 		return true, obj, nil
 	}
 }
+
+// ApplyPatchReactor returns a reactor that handles server-side apply patches,
+// which are not supported by the object tracker of the fake clientsets.
+// The applied configuration is created if the object does not exist yet, and
+// is otherwise strategically merged into the existing object. Field ownership
+// is not tracked, so fields omitted from the configuration are never removed.
+func ApplyPatchReactor(tracker coretesting.ObjectTracker, scheme *runtime.Scheme) coretesting.ReactionFunc {
+	return func(action coretesting.Action) (handled bool, ret runtime.Object, err error) {
+		patchAction, ok := action.(coretesting.PatchAction)
+		if !ok || patchAction.GetPatchType() != types.ApplyPatchType {
+			return false, nil, nil
+		}
+
+		gvr, namespace, patch := action.GetResource(), action.GetNamespace(), patchAction.GetPatch()
+
+		var typeMeta metav1.TypeMeta
+		if err := json.Unmarshal(patch, &typeMeta); err != nil {
+			return true, nil, err
+		}
+		obj, err := scheme.New(typeMeta.GroupVersionKind())
+		if err != nil {
+			return true, nil, err
+		}
+
+		existing, err := tracker.Get(gvr, namespace, patchAction.GetName())
+		if apierrors.IsNotFound(err) {
+			if err := json.Unmarshal(patch, obj); err != nil {
+				return true, nil, err
+			}
+			return true, obj, tracker.Create(gvr, obj, namespace)
+		}
+		if err != nil {
+			return true, nil, err
+		}
+
+		existingJSON, err := json.Marshal(existing)
+		if err != nil {
+			return true, nil, err
+		}
+		merged, err := strategicpatch.StrategicMergePatch(existingJSON, patch, obj)
+		if err != nil {
+			return true, nil, err
+		}
+		if err := json.Unmarshal(merged, obj); err != nil {
+			return true, nil, err
+		}
+		return true, obj, tracker.Update(gvr, obj, namespace)
+	}
+}
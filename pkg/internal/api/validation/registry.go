@@ -32,17 +32,26 @@ type Registry struct {
 	scheme                 *runtime.Scheme
 	validateRegister       map[schema.GroupVersionKind]ValidateFunc
 	validateUpdateRegister map[schema.GroupVersionKind]ValidateUpdateFunc
+	warnRegister           map[schema.GroupVersionKind]WarnFunc
+	warnUpdateRegister     map[schema.GroupVersionKind]WarnUpdateFunc
 }
 
 type ValidateFunc func(obj runtime.Object) field.ErrorList
 type ValidateUpdateFunc func(oldObj, obj runtime.Object) field.ErrorList
 
+// WarnFunc and WarnUpdateFunc return human readable warnings about an object.
+// Unlike validation errors, warnings never cause an object to be rejected.
+type WarnFunc func(obj runtime.Object) []string
+type WarnUpdateFunc func(oldObj, obj runtime.Object) []string
+
 // NewRegistry creates a new empty registry, backed by the provided Scheme.
 func NewRegistry(scheme *runtime.Scheme) *Registry {
 	return &Registry{
 		scheme:                 scheme,
 		validateRegister:       make(map[schema.GroupVersionKind]ValidateFunc),
 		validateUpdateRegister: make(map[schema.GroupVersionKind]ValidateUpdateFunc),
+		warnRegister:           make(map[schema.GroupVersionKind]WarnFunc),
+		warnUpdateRegister:     make(map[schema.GroupVersionKind]WarnUpdateFunc),
 	}
 }
 
@@ -86,6 +95,40 @@ func (r *Registry) AddValidateUpdateFunc(obj runtime.Object, fn ValidateUpdateFu
 	return nil
 }
 
+// AddWarnFunc will add a new warning function to the register.
+// The function will be run whenever Warn is called with a requestVersion set
+// to any recognised GroupVersionKinds for this object, following the same
+// rules as AddValidateFunc.
+func (r *Registry) AddWarnFunc(obj runtime.Object, fn WarnFunc) error {
+	gvks, _, err := r.scheme.ObjectKinds(obj)
+	if err != nil {
+		return err
+	}
+
+	for _, gvk := range gvks {
+		r.appendWarn(gvk, fn)
+	}
+
+	return nil
+}
+
+// AddWarnUpdateFunc will add a new update warning function to the register.
+// The function will be run whenever WarnUpdate is called with a
+// requestVersion set to any recognised GroupVersionKinds for this object,
+// following the same rules as AddValidateUpdateFunc.
+func (r *Registry) AddWarnUpdateFunc(obj runtime.Object, fn WarnUpdateFunc) error {
+	gvks, _, err := r.scheme.ObjectKinds(obj)
+	if err != nil {
+		return err
+	}
+
+	for _, gvk := range gvks {
+		r.appendWarnUpdate(gvk, fn)
+	}
+
+	return nil
+}
+
 // Validate will run all validation functions registered for the given object.
 // If the passed obj is *not* of the same version as the provided
 // requestVersion, the registry will attempt to convert the object before
@@ -148,6 +191,61 @@ func (r *Registry) ValidateUpdate(oldObj, obj runtime.Object, requestVersion sch
 	return el
 }
 
+// Warn will run all warning functions registered for the given object.
+// Objects are converted in the same way as in Validate. If the object cannot
+// be converted no warnings are returned, as the error will already be
+// surfaced by Validate.
+func (r *Registry) Warn(obj runtime.Object, requestVersion schema.GroupVersionKind) []string {
+	versioned, internal := r.lookupWarnFuncs(requestVersion)
+	if versioned == nil && internal == nil {
+		return nil
+	}
+
+	targetObj, internalObj, err := r.convert(obj, requestVersion)
+	if err != nil {
+		return nil
+	}
+
+	var warnings []string
+	if versioned != nil {
+		warnings = append(warnings, versioned(targetObj)...)
+	}
+	if internal != nil {
+		warnings = append(warnings, internal(internalObj)...)
+	}
+
+	return warnings
+}
+
+// WarnUpdate will run all update warning functions registered for the given
+// object. Objects are converted in the same way as in ValidateUpdate.
+func (r *Registry) WarnUpdate(oldObj, obj runtime.Object, requestVersion schema.GroupVersionKind) []string {
+	versioned, internal := r.lookupWarnUpdateFuncs(requestVersion)
+	if versioned == nil && internal == nil {
+		return nil
+	}
+
+	targetOldObj, internalOldObj, err := r.convert(oldObj, requestVersion)
+	if err != nil {
+		return nil
+	}
+
+	targetObj, internalObj, err := r.convert(obj, requestVersion)
+	if err != nil {
+		return nil
+	}
+
+	var warnings []string
+	if versioned != nil {
+		warnings = append(warnings, versioned(targetOldObj, targetObj)...)
+	}
+	if internal != nil {
+		warnings = append(warnings, internal(internalOldObj, internalObj)...)
+	}
+
+	return warnings
+}
+
 func (r *Registry) lookupValidateFuncs(gvk schema.GroupVersionKind) (versioned ValidateFunc, internal ValidateFunc) {
 	versioned = r.validateRegister[gvk]
 	gvk.Version = runtime.APIVersionInternal
@@ -162,6 +260,20 @@ func (r *Registry) lookupValidateUpdateFuncs(gvk schema.GroupVersionKind) (versi
 	return versioned, internal
 }
 
+func (r *Registry) lookupWarnFuncs(gvk schema.GroupVersionKind) (versioned WarnFunc, internal WarnFunc) {
+	versioned = r.warnRegister[gvk]
+	gvk.Version = runtime.APIVersionInternal
+	internal = r.warnRegister[gvk]
+	return versioned, internal
+}
+
+func (r *Registry) lookupWarnUpdateFuncs(gvk schema.GroupVersionKind) (versioned WarnUpdateFunc, internal WarnUpdateFunc) {
+	versioned = r.warnUpdateRegister[gvk]
+	gvk.Version = runtime.APIVersionInternal
+	internal = r.warnUpdateRegister[gvk]
+	return versioned, internal
+}
+
 func (r *Registry) appendValidate(gvk schema.GroupVersionKind, fn ValidateFunc) {
 	existing, ok := r.validateRegister[gvk]
 	if !ok {
@@ -186,6 +298,30 @@ func (r *Registry) appendValidateUpdate(gvk schema.GroupVersionKind, fn Validate
 	}
 }
 
+func (r *Registry) appendWarn(gvk schema.GroupVersionKind, fn WarnFunc) {
+	existing, ok := r.warnRegister[gvk]
+	if !ok {
+		r.warnRegister[gvk] = fn
+		return
+	}
+
+	r.warnRegister[gvk] = func(obj runtime.Object) []string {
+		return append(existing(obj), fn(obj)...)
+	}
+}
+
+func (r *Registry) appendWarnUpdate(gvk schema.GroupVersionKind, fn WarnUpdateFunc) {
+	existing, ok := r.warnUpdateRegister[gvk]
+	if !ok {
+		r.warnUpdateRegister[gvk] = fn
+		return
+	}
+
+	r.warnUpdateRegister[gvk] = func(oldObj, obj runtime.Object) []string {
+		return append(existing(oldObj, obj), fn(oldObj, obj)...)
+	}
+}
+
 // convert will convert the given obj into the requestVersion as well as
 // returning the internal representation of the object.
 func (r *Registry) convert(obj runtime.Object, requestVersion schema.GroupVersionKind) (targetObj, internalObj runtime.Object, err error) {
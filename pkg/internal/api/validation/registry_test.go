@@ -197,3 +197,36 @@ func TestValidateUpdateUnrecognisedType(t *testing.T) {
 		t.Errorf("expected to get an error but did not")
 	}
 }
+
+func TestWarnType(t *testing.T) {
+	reg := validation.NewRegistry(scheme)
+	utilruntime.Must(reg.AddWarnFunc(&cmapi.Certificate{}, func(obj runtime.Object) []string {
+		return []string{"external"}
+	}))
+	utilruntime.Must(reg.AddWarnFunc(&cmapiinternal.Certificate{}, func(obj runtime.Object) []string {
+		return []string{"internal"}
+	}))
+	warnings := reg.Warn(&cmapi.Certificate{}, cmapi.SchemeGroupVersion.WithKind("Certificate"))
+	if len(warnings) != 2 || warnings[0] != "external" || warnings[1] != "internal" {
+		t.Errorf("expected warnings from both registered functions but got: %v", warnings)
+	}
+}
+
+func TestWarnUpdateType(t *testing.T) {
+	reg := validation.NewRegistry(scheme)
+	utilruntime.Must(reg.AddWarnUpdateFunc(&cmapiinternal.Certificate{}, func(oldObj, obj runtime.Object) []string {
+		return []string{"internal"}
+	}))
+	warnings := reg.WarnUpdate(&cmapi.Certificate{}, &cmapi.Certificate{}, cmapi.SchemeGroupVersion.WithKind("Certificate"))
+	if len(warnings) != 1 || warnings[0] != "internal" {
+		t.Errorf("expected warning from registered internal function but got: %v", warnings)
+	}
+}
+
+func TestWarnNoneRegistered(t *testing.T) {
+	reg := validation.NewRegistry(scheme)
+	warnings := reg.Warn(&cmapi.Certificate{}, cmapi.SchemeGroupVersion.WithKind("Certificate"))
+	if len(warnings) > 0 {
+		t.Errorf("expected to not get any warnings but got: %v", warnings)
+	}
+}
@@ -19,6 +19,7 @@ package acme
 import (
 	corev1 "k8s.io/api/core/v1"
 	apiext "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	cmmeta "github.com/jetstack/cert-manager/pkg/internal/apis/meta"
 )
@@ -91,6 +92,14 @@ type ACMEIssuer struct {
 	// it it will create an error on the Order.
 	// Defaults to false.
 	EnableDurationFeature bool
+
+	// AllowedDomains is a list of glob patterns, such as '*.example.com', that
+	// restrict the DNS names which may be requested from this issuer.
+	// Orders containing DNS names that do not match any of the patterns will
+	// be marked as errored. A '*' matches any sequence of characters,
+	// including dots.
+	// If not set, all DNS names are allowed.
+	AllowedDomains []string
 }
 
 // ACMEExternalAccountBinding is a reference to a CA external account of the ACME
@@ -152,6 +161,14 @@ type CertificateDNSNameSelector struct {
 	// this challenge solver will apply to.
 	MatchLabels map[string]string
 
+	// A map of annotations that is used to refine the set of certificate's
+	// that this challenge solver will apply to. All annotations must be
+	// present on the certificate with the given values for the solver to be
+	// selected.
+	// If multiple solvers match, annotations that match are counted together
+	// with matchLabels when choosing the most specific solver.
+	MatchAnnotations map[string]string
+
 	// List of DNSNames that this solver will be used to solve.
 	// If specified and a match is found, a dnsNames selector will take
 	// precedence over a dnsZones selector.
@@ -171,8 +188,32 @@ type CertificateDNSNameSelector struct {
 	// If neither has more matches, the solver defined earlier in the list
 	// will be selected.
 	DNSZones []string
+
+	// A label selector that is used to refine the set of certificate's that
+	// this challenge solver will apply to, based on the labels of the
+	// namespace the certificate is in.
+	// If multiple solvers match, namespace labels that match are counted
+	// together with matchLabels when choosing the most specific solver.
+	NamespaceSelector *metav1.LabelSelector
+
+	// IdentifierType restricts this solver to authorizations for identifiers
+	// of the given type, either `dns` for DNS names or `ip` for IP addresses.
+	// If not specified, the solver is used for identifiers of any type.
+	// If multiple solvers match, a matching identifierType is counted
+	// together with matchLabels when choosing the most specific solver.
+	IdentifierType string
 }
 
+const (
+	// ACMEIdentifierTypeDNS is the identifierType of authorizations for DNS
+	// names.
+	ACMEIdentifierTypeDNS = "dns"
+
+	// ACMEIdentifierTypeIP is the identifierType of authorizations for IP
+	// addresses.
+	ACMEIdentifierTypeIP = "ip"
+)
+
 // ACMEChallengeSolverHTTP01 contains configuration detailing how to solve
 // HTTP01 challenges within a Kubernetes cluster.
 // Typically this is accomplished through creating 'routes' of some description
@@ -190,6 +231,12 @@ type ACMEChallengeSolverHTTP01Ingress struct {
 	// Optional service type for Kubernetes solver service
 	ServiceType corev1.ServiceType
 
+	// Optional IP family of the Kubernetes solver service, either `IPv4` or
+	// `IPv6`. This should be set to `IPv6` in IPv6-only clusters, so that the
+	// challenge solver is reachable on an IPv6 cluster IP. If not set, the
+	// cluster's default IP family is used.
+	ServiceIPFamily corev1.IPFamily
+
 	// The ingress class to use when creating Ingress resources to solve ACME
 	// challenges that use this challenge solver.
 	// Only one of 'class' or 'name' may be specified.
@@ -209,6 +256,13 @@ type ACMEChallengeSolverHTTP01Ingress struct {
 	// Optional ingress template used to configure the ACME challenge solver
 	// ingress used for HTTP01 challenges
 	IngressTemplate *ACMEChallengeSolverHTTP01IngressTemplate
+
+	// ReadTimeoutSeconds is the maximum number of seconds to wait for a
+	// response to each self check request made against the challenge
+	// solver before the attempt is considered failed. If not set, requests
+	// are only bound by the overall HTTP01 self check timeout.
+	// Must be between 1 and 300.
+	ReadTimeoutSeconds *int32
 }
 
 type ACMEChallengeSolverHTTP01IngressPodTemplate struct {
@@ -220,8 +274,8 @@ type ACMEChallengeSolverHTTP01IngressPodTemplate struct {
 
 	// PodSpec defines overrides for the HTTP01 challenge solver pod.
 	// Only the 'priorityClassName', 'nodeSelector', 'affinity',
-	// 'serviceAccountName' and 'tolerations' fields are supported currently.
-	// All other fields will be ignored.
+	// 'serviceAccountName', 'tolerations' and 'image' fields are supported
+	// currently. All other fields will be ignored.
 	// +optional
 	Spec ACMEChallengeSolverHTTP01IngressPodSpec
 }
@@ -252,6 +306,10 @@ type ACMEChallengeSolverHTTP01IngressPodSpec struct {
 	// If specified, the pod's service account
 	// +optional
 	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// If specified, the image used for the solver pod's container, overriding
+	// the HTTP01 solver image configured on the controller.
+	Image string
 }
 
 type ACMEChallengeSolverHTTP01IngressTemplate struct {
@@ -278,6 +336,20 @@ type ACMEChallengeSolverDNS01 struct {
 	// records when found in DNS zones.
 	CNAMEStrategy CNAMEStrategy
 
+	// SelfCheckNameservers is a list of nameservers, in host:port form, that
+	// will be queried exclusively when performing the DNS01 self check for
+	// this solver. This is useful in split-horizon DNS environments where the
+	// challenge record is only visible to particular nameservers.
+	// If not set, the nameservers configured for the controller are used.
+	SelfCheckNameservers []string
+
+	// RecordTTL is the TTL, in seconds, of the TXT records created by this
+	// solver. Where the limits of the DNS provider are known, the TTL is
+	// validated to be within the range that the provider accepts.
+	// It is not supported by the acmeDNS, rfc2136 and webhook providers.
+	// If not set, the default TTL of the configured DNS provider is used.
+	RecordTTL *int32
+
 	// Use the Akamai DNS zone management API to manage DNS01 challenge records.
 	Akamai *ACMEIssuerDNS01ProviderAkamai
 
@@ -296,6 +368,12 @@ type ACMEChallengeSolverDNS01 struct {
 	// Use the DigitalOcean DNS API to manage DNS01 challenge records.
 	DigitalOcean *ACMEIssuerDNS01ProviderDigitalOcean
 
+	// Use the Gandi LiveDNS API to manage DNS01 challenge records.
+	Gandi *ACMEIssuerDNS01ProviderGandi
+
+	// Use the Porkbun DNS API to manage DNS01 challenge records.
+	Porkbun *ACMEIssuerDNS01ProviderPorkbun
+
 	// Use the 'ACME DNS' (https://github.com/joohoi/acme-dns) API to manage
 	// DNS01 challenge records.
 	AcmeDNS *ACMEIssuerDNS01ProviderAcmeDNS
@@ -366,6 +444,19 @@ type ACMEIssuerDNS01ProviderDigitalOcean struct {
 	Token cmmeta.SecretKeySelector
 }
 
+// ACMEIssuerDNS01ProviderGandi is a structure containing the DNS
+// configuration for Gandi LiveDNS
+type ACMEIssuerDNS01ProviderGandi struct {
+	Token cmmeta.SecretKeySelector
+}
+
+// ACMEIssuerDNS01ProviderPorkbun is a structure containing the DNS
+// configuration for Porkbun
+type ACMEIssuerDNS01ProviderPorkbun struct {
+	APIKey       cmmeta.SecretKeySelector
+	SecretAPIKey cmmeta.SecretKeySelector
+}
+
 // ACMEIssuerDNS01ProviderRoute53 is a structure containing the Route 53
 // configuration for AWS
 type ACMEIssuerDNS01ProviderRoute53 struct {
@@ -0,0 +1,126 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package install
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/rand"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/apitesting/fuzzer"
+	metafuzzer "k8s.io/apimachinery/pkg/apis/meta/fuzzer"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmapiv1alpha2 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	"github.com/jetstack/cert-manager/pkg/internal/apis/certmanager"
+	cmfuzzer "github.com/jetstack/cert-manager/pkg/internal/apis/certmanager/fuzzer"
+)
+
+// convertThroughVersions converts a v1 Certificate to the internal version,
+// on to v1alpha2, and back through the internal version to v1.
+func convertThroughVersions(t *testing.T, scheme *runtime.Scheme, in *cmapi.Certificate) *cmapi.Certificate {
+	internal := &certmanager.Certificate{}
+	if err := scheme.Convert(in, internal, nil); err != nil {
+		t.Fatalf("converting v1 to internal: %v", err)
+	}
+	v1alpha2 := &cmapiv1alpha2.Certificate{}
+	if err := scheme.Convert(internal, v1alpha2, nil); err != nil {
+		t.Fatalf("converting internal to v1alpha2: %v", err)
+	}
+	internal = &certmanager.Certificate{}
+	if err := scheme.Convert(v1alpha2, internal, nil); err != nil {
+		t.Fatalf("converting v1alpha2 to internal: %v", err)
+	}
+	out := &cmapi.Certificate{}
+	if err := scheme.Convert(internal, out, nil); err != nil {
+		t.Fatalf("converting internal to v1: %v", err)
+	}
+	return out
+}
+
+func assertMetadataByteStable(t *testing.T, in, out metav1.ObjectMeta) {
+	inJSON, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outJSON, err := json.Marshal(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(inJSON, outJSON) {
+		t.Errorf("metadata changed during conversion:\nbefore: %s\nafter:  %s", inJSON, outJSON)
+	}
+}
+
+func TestCertificateConversionPreservesMetadata(t *testing.T) {
+	scheme := runtime.NewScheme()
+	Install(scheme)
+	codecs := serializer.NewCodecFactory(scheme)
+
+	seed := rand.Int63()
+	t.Logf("using fuzzer seed %d", seed)
+	f := fuzzer.FuzzerFor(fuzzer.MergeFuzzerFuncs(metafuzzer.Funcs, cmfuzzer.Funcs), rand.NewSource(seed), codecs).
+		NilChance(0).NumElements(1, 5)
+
+	for i := 0; i < 100; i++ {
+		internal := &certmanager.Certificate{}
+		f.Fuzz(internal)
+		in := &cmapi.Certificate{}
+		if err := scheme.Convert(internal, in, nil); err != nil {
+			t.Fatalf("converting fuzzed internal Certificate to v1: %v", err)
+		}
+
+		out := convertThroughVersions(t, scheme, in)
+		assertMetadataByteStable(t, in.ObjectMeta, out.ObjectMeta)
+	}
+}
+
+func TestCertificateConversionPreservesUnusualAnnotations(t *testing.T) {
+	scheme := runtime.NewScheme()
+	Install(scheme)
+
+	in := &cmapi.Certificate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+			Labels: map[string]string{
+				"app.kubernetes.io/name": "test",
+				"empty":                  "",
+			},
+			Annotations: map[string]string{
+				"":                       "empty key",
+				"empty-value":            "",
+				"example.com/json":       `{"nested":{"list":[1,"two",null]},"quoted":"\"value\""}`,
+				"example.com/multi-line": "first line\nsecond line\n",
+				"example.com/unicode":    "héllo wörld ✓",
+				"kubectl.kubernetes.io/last-applied-configuration": `{"apiVersion":"cert-manager.io/v1","kind":"Certificate"}`,
+				cmapi.IssuerNameAnnotationKey:                      "issuer",
+			},
+		},
+		Spec: cmapi.CertificateSpec{
+			SecretName: "test",
+			DNSNames:   []string{"example.com"},
+		},
+	}
+
+	out := convertThroughVersions(t, scheme, in)
+	assertMetadataByteStable(t, in.ObjectMeta, out.ObjectMeta)
+}
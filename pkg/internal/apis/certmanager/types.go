@@ -76,6 +76,13 @@ const (
 
 	// Annotation to declare the CertificateRequest "revision", belonging to a Certificate Resource
 	CertificateRequestRevisionAnnotationKey = "cert-manager.io/certificate-revision"
+
+	// Annotation that can be added to CertificateRequest resources to request
+	// that the CSR be signed as-is, using the key usages and subject it
+	// contains even if they differ from those set on the spec.
+	// It is only honoured by the CA and Vault issuers, and only if verbatim
+	// CSR signing has been enabled on the controller.
+	CertificateRequestSignCSRVerbatimAnnotationKey = "cert-manager.io/sign-csr-verbatim"
 )
 
 const (
@@ -127,6 +134,12 @@ const (
 	// The value is an array with objects containing the name and value keys
 	// for example: `[{"name": "custom-field", "value": "custom-value"}]`
 	VenafiCustomFieldsAnnotationKey = "venafi.cert-manager.io/custom-fields"
+
+	// RequireCommonNameAnnotationKey can be set to "true" on an Issuer or
+	// ClusterIssuer whose CA requires a non-empty common name. Certificates
+	// referencing such an issuer will be rejected if they only request
+	// subject alternative names.
+	RequireCommonNameAnnotationKey = "cert-manager.io/require-common-name"
 )
 
 // KeyUsage specifies valid usage contexts for keys.
@@ -57,6 +57,9 @@ const (
 
 	// Denotes the ECDSA private key type.
 	ECDSAKeyAlgorithm PrivateKeyAlgorithm = "ECDSA"
+
+	// Denotes the Ed25519 private key type.
+	Ed25519KeyAlgorithm PrivateKeyAlgorithm = "Ed25519"
 )
 
 type PrivateKeyEncoding string
@@ -64,8 +67,9 @@ type PrivateKeyEncoding string
 const (
 	// PKCS1 key encoding will produce PEM files that include the type of
 	// private key as part of the PEM header, e.g. `BEGIN RSA PRIVATE KEY`.
-	// If the keyAlgorithm is set to 'ECDSA', this will produce private keys
-	// that use the `BEGIN EC PRIVATE KEY` header.
+	// PKCS#1 only defines an encoding for RSA keys, so it cannot be used
+	// with the 'ECDSA' or 'Ed25519' keyAlgorithm. If no encoding is set,
+	// 'ECDSA' private keys use the `BEGIN EC PRIVATE KEY` header instead.
 	PKCS1 PrivateKeyEncoding = "PKCS1"
 
 	// PKCS8 key encoding will produce PEM files with the `BEGIN PRIVATE KEY`
@@ -74,6 +78,23 @@ const (
 	PKCS8 PrivateKeyEncoding = "PKCS8"
 )
 
+type PrivateKeySignatureHash string
+
+const (
+	// SHA1SignatureHash signs with SHA-1. It is only intended for legacy
+	// issuers that do not support stronger hash functions.
+	SHA1SignatureHash PrivateKeySignatureHash = "SHA1"
+
+	// SHA256SignatureHash signs with SHA-256.
+	SHA256SignatureHash PrivateKeySignatureHash = "SHA256"
+
+	// SHA384SignatureHash signs with SHA-384.
+	SHA384SignatureHash PrivateKeySignatureHash = "SHA384"
+
+	// SHA512SignatureHash signs with SHA-512.
+	SHA512SignatureHash PrivateKeySignatureHash = "SHA512"
+)
+
 // CertificateSpec defines the desired state of Certificate.
 // A valid Certificate requires at least one of a CommonName, DNSName, or
 // URISAN to be valid.
@@ -102,6 +123,13 @@ type CertificateSpec struct {
 	// the way through the certificate's duration.
 	RenewBefore *metav1.Duration
 
+	// IssuanceTimeout is the maximum amount of time an issuance of this
+	// Certificate may remain in progress before it is marked as failed. Once
+	// timed out, the issuance is retried after the usual failure back-off.
+	// If not set, the controller's default issuance timeout is used, and
+	// issuances never time out if that is not set either.
+	IssuanceTimeout *metav1.Duration
+
 	// DNSNames is a list of DNS subjectAltNames to be set on the Certificate.
 	DNSNames []string
 
@@ -112,8 +140,15 @@ type CertificateSpec struct {
 	URISANs []string
 
 	// EmailSANs is a list of email subjectAltNames to be set on the Certificate.
+	// They are encoded as rfc822Name subjectAltNames, so must only contain
+	// ASCII characters.
 	EmailSANs []string
 
+	// RegisteredIDs is a list of registeredID subjectAltNames to be set on the
+	// Certificate. Each entry must be an object identifier in dotted-decimal
+	// notation, for example 1.2.3.4.
+	RegisteredIDs []string
+
 	// SecretName is the name of the secret resource that will be automatically
 	// created and managed by this Certificate resource.
 	// It will be populated with a private key and certificate, signed by the
@@ -124,6 +159,11 @@ type CertificateSpec struct {
 	// `secretName` Secret resource.
 	Keystores *CertificateKeystores
 
+	// AdditionalOutputFormats defines extra output formats of the private key
+	// and signed certificate chain to be written to this Certificate's target
+	// Secret.
+	AdditionalOutputFormats []CertificateAdditionalOutputFormat
+
 	// IssuerRef is a reference to the issuer for this certificate.
 	// If the `kind` field is not set, or set to `Issuer`, an Issuer resource
 	// with the given name in the same namespace as the Certificate will be used.
@@ -132,10 +172,28 @@ type CertificateSpec struct {
 	// The `name` field in this stanza is required at all times.
 	IssuerRef cmmeta.ObjectReference
 
+	// FallbackIssuerRef is a reference to an issuer that will be used to
+	// sign the certificate if signing with the issuer referenced by `issuerRef`
+	// repeatedly fails, e.g. because the CA is unavailable.
+	// It has the same format as `issuerRef`.
+	FallbackIssuerRef *cmmeta.ObjectReference
+
 	// IsCA will mark this Certificate as valid for certificate signing.
 	// This will automatically add the `cert sign` usage to the list of `usages`.
 	IsCA bool
 
+	// MaxPathLen is the maximum number of non-self-issued intermediate CA
+	// certificates that may follow this certificate in a valid certification
+	// path, encoded in its BasicConstraints extension. It may only be set if
+	// `isCA` is true. A value of 0 is treated as unset unless `maxPathLenZero`
+	// is also true.
+	MaxPathLen int
+
+	// MaxPathLenZero indicates that a `maxPathLen` of 0 is set, meaning this
+	// CA certificate may only be used to sign end-entity certificates. It may
+	// only be set if `isCA` is true and `maxPathLen` is unset or 0.
+	MaxPathLenZero bool
+
 	// Usages is the set of x509 usages that are requested for the certificate.
 	// Defaults to `digital signature` and `key encipherment` if not specified.
 	Usages []KeyUsage
@@ -146,6 +204,13 @@ type CertificateSpec struct {
 	// EncodeUsagesInRequest controls whether key usages should be present
 	// in the CertificateRequest
 	EncodeUsagesInRequest *bool
+
+	// TemplateRef is a reference to a ConfigMap in the same namespace as the
+	// Certificate holding common fields of a Certificate's spec, such as
+	// usages, duration and keystores, in YAML under the `template` key.
+	// When the Certificate is created or updated, any of these fields that
+	// are not set on the Certificate are set from the template.
+	TemplateRef *cmmeta.LocalObjectReference
 }
 
 // CertificatePrivateKey contains configuration options for private keys
@@ -171,7 +236,8 @@ type CertificatePrivateKey struct {
 	Encoding PrivateKeyEncoding
 
 	// Algorithm is the private key algorithm of the corresponding private key
-	// for this certificate. If provided, allowed values are either `RSA` or `ECDSA`
+	// for this certificate. If provided, allowed values are either `RSA`,
+	// `ECDSA` or `Ed25519`.
 	// If `algorithm` is specified and `size` is not provided,
 	// key size of `256` will be used for `ECDSA` key algorithm and
 	// key size of `2048` will be used for `RSA` key algorithm.
@@ -182,8 +248,27 @@ type CertificatePrivateKey struct {
 	// and will default to `2048` if not specified.
 	// If `algorithm` is set to `ECDSA`, valid values are `256`, `384` or `521`,
 	// and will default to `256` if not specified.
+	// If `algorithm` is set to `Ed25519`, size must not be specified.
 	// No other values are allowed.
 	Size int
+
+	// EncryptionPassphraseSecretRef is a reference to a key in a Secret
+	// resource containing a passphrase used to encrypt the private key
+	// written to the `tls.key` field of the `spec.secretName` Secret.
+	// If set, the private key is stored as a PEM encoded, encrypted PKCS#8
+	// key, and applications consuming the Secret must decrypt it before use.
+	// This is unusual and most applications are not able to read encrypted
+	// keys.
+	EncryptionPassphraseSecretRef *cmmeta.SecretKeySelector
+
+	// SignatureHash is the hash function used to sign the CertificateRequest
+	// with the private key. If provided, allowed values are `SHA1`, `SHA256`,
+	// `SHA384` or `SHA512`. It can only be set for `RSA` and `ECDSA` keys.
+	// If not specified, the hash function is chosen based on the key size:
+	// SHA-256 for RSA keys smaller than 3072 bits and ECDSA P-256 keys,
+	// SHA-384 for RSA keys smaller than 4096 bits and ECDSA P-384 keys,
+	// and SHA-512 otherwise.
+	SignatureHash PrivateKeySignatureHash
 }
 
 // Denotes how private keys should be generated or sourced when a Certificate
@@ -222,6 +307,25 @@ type X509Subject struct {
 	SerialNumber string
 }
 
+// CertificateOutputFormatType specifies which additional output formats should
+// be written to the Certificate's target Secret.
+type CertificateOutputFormatType string
+
+const (
+	// CertificateOutputFormatDER writes the leaf certificate as raw DER
+	// encoded bytes to the `tls.der` key of the target Secret.
+	CertificateOutputFormatDER CertificateOutputFormatType = "DER"
+)
+
+// CertificateAdditionalOutputFormat defines an additional output format of a
+// Certificate resource. These contain supplementary data formats of the signed
+// certificate chain and paired private key.
+type CertificateAdditionalOutputFormat struct {
+	// Type is the name of the format type that should be written to the
+	// Certificate's target Secret.
+	Type CertificateOutputFormatType
+}
+
 // CertificateKeystores configures additional keystore output formats to be
 // created in the Certificate's output Secret.
 type CertificateKeystores struct {
@@ -262,6 +366,16 @@ type PKCS12Keystore struct {
 	// PasswordSecretRef is a reference to a key in a Secret resource
 	// containing the password used to encrypt the PKCS12 keystore.
 	PasswordSecretRef cmmeta.SecretKeySelector
+
+	// IncludeCAChain controls whether the complete CA chain is stored in the
+	// PKCS12 files.
+	// If true, the deduplicated certificate chain (every certificate in
+	// `tls.crt` and `ca.crt`) is stored alongside the private key in
+	// `keystore.p12`, and every CA certificate in that chain is added as a
+	// trusted entry to `truststore.p12`, rather than only the first
+	// certificate in `ca.crt`.
+	// May only be set if `create` is true.
+	IncludeCAChain bool
 }
 
 // CertificateStatus defines the observed state of Certificate
@@ -365,4 +479,17 @@ const (
 	//
 	// It will be removed by the 'issuing' controller upon completing issuance.
 	CertificateConditionIssuing CertificateConditionType = "Issuing"
+
+	// CertificateConditionSecretConflict is added to Certificate resources
+	// when the target Secret appears to be managed by another controller, for
+	// example because it has a foreign controller owner reference or because
+	// another field manager has written the certificate data.
+	// While this condition is True, re-issuance is not triggered for the
+	// Certificate to avoid repeatedly overwriting the other controller's data.
+	CertificateConditionSecretConflict CertificateConditionType = "SecretConflict"
+
+	// CertificateConditionPaused is added to Certificate resources that have
+	// been paused using the `cert-manager.io/paused` annotation.
+	// While this condition is True, the Certificate is not reconciled.
+	CertificateConditionPaused CertificateConditionType = "Paused"
 )
@@ -82,6 +82,12 @@ type CertificateRequestSpec struct {
 	// issuer which defaults to `cert-manager.io` if empty.
 	IssuerRef cmmeta.ObjectReference
 
+	// FallbackIssuerRef is a reference to an issuer that will be used to
+	// sign this CertificateRequest once signing with the issuer referenced by
+	// `issuerRef` has failed a number of times. It has the same format as
+	// `issuerRef`.
+	FallbackIssuerRef *cmmeta.ObjectReference
+
 	// The PEM-encoded x509 certificate signing request to be submitted to the
 	// CA for signing.
 	Request []byte
@@ -156,4 +162,12 @@ const (
 	// parameters being invalid. Additional information about why the request
 	// was rejected can be found in the `reason` and `message` fields.
 	CertificateRequestConditionInvalidRequest CertificateRequestConditionType = "InvalidRequest"
+
+	// CertificateRequestConditionApproved indicates that a certificate request
+	// has been approved to be signed by an issuer.
+	CertificateRequestConditionApproved CertificateRequestConditionType = "Approved"
+
+	// CertificateRequestConditionDenied indicates that a certificate request
+	// has been denied, and must not be signed by an issuer.
+	CertificateRequestConditionDenied CertificateRequestConditionType = "Denied"
 )
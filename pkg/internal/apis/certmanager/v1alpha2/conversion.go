@@ -46,6 +46,8 @@ func Convert_v1alpha2_CertificateSpec_To_certmanager_CertificateSpec(in *v1alpha
 			out.PrivateKey.Algorithm = certmanager.ECDSAKeyAlgorithm
 		case v1alpha2.RSAKeyAlgorithm:
 			out.PrivateKey.Algorithm = certmanager.RSAKeyAlgorithm
+		case v1alpha2.Ed25519KeyAlgorithm:
+			out.PrivateKey.Algorithm = certmanager.Ed25519KeyAlgorithm
 		default:
 			out.PrivateKey.Algorithm = certmanager.PrivateKeyAlgorithm(in.KeyAlgorithm)
 		}
@@ -82,6 +84,8 @@ func Convert_certmanager_CertificateSpec_To_v1alpha2_CertificateSpec(in *certman
 			out.KeyAlgorithm = v1alpha2.ECDSAKeyAlgorithm
 		case certmanager.RSAKeyAlgorithm:
 			out.KeyAlgorithm = v1alpha2.RSAKeyAlgorithm
+		case certmanager.Ed25519KeyAlgorithm:
+			out.KeyAlgorithm = v1alpha2.Ed25519KeyAlgorithm
 		default:
 			out.KeyAlgorithm = v1alpha2.KeyAlgorithm(in.PrivateKey.Algorithm)
 		}
@@ -38,6 +38,8 @@ func Convert_v1alpha3_CertificateSpec_To_certmanager_CertificateSpec(in *v1alpha
 			out.PrivateKey.Algorithm = certmanager.ECDSAKeyAlgorithm
 		case v1alpha3.RSAKeyAlgorithm:
 			out.PrivateKey.Algorithm = certmanager.RSAKeyAlgorithm
+		case v1alpha3.Ed25519KeyAlgorithm:
+			out.PrivateKey.Algorithm = certmanager.Ed25519KeyAlgorithm
 		default:
 			out.PrivateKey.Algorithm = certmanager.PrivateKeyAlgorithm(in.KeyAlgorithm)
 		}
@@ -68,6 +70,8 @@ func Convert_certmanager_CertificateSpec_To_v1alpha3_CertificateSpec(in *certman
 			out.KeyAlgorithm = v1alpha3.ECDSAKeyAlgorithm
 		case certmanager.RSAKeyAlgorithm:
 			out.KeyAlgorithm = v1alpha3.RSAKeyAlgorithm
+		case certmanager.Ed25519KeyAlgorithm:
+			out.KeyAlgorithm = v1alpha3.Ed25519KeyAlgorithm
 		default:
 			out.KeyAlgorithm = v1alpha3.KeyAlgorithm(in.PrivateKey.Algorithm)
 		}
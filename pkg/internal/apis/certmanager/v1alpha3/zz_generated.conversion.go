@@ -41,6 +41,16 @@ func init() {
 // RegisterConversions adds conversion functions to the given scheme.
 // Public to allow building arbitrary schemes.
 func RegisterConversions(s *runtime.Scheme) error {
+	if err := s.AddGeneratedConversionFunc((*v1alpha3.AWSPCAIssuer)(nil), (*certmanager.AWSPCAIssuer)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_AWSPCAIssuer_To_certmanager_AWSPCAIssuer(a.(*v1alpha3.AWSPCAIssuer), b.(*certmanager.AWSPCAIssuer), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.AWSPCAIssuer)(nil), (*v1alpha3.AWSPCAIssuer)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_AWSPCAIssuer_To_v1alpha3_AWSPCAIssuer(a.(*certmanager.AWSPCAIssuer), b.(*v1alpha3.AWSPCAIssuer), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1alpha3.CAIssuer)(nil), (*certmanager.CAIssuer)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_CAIssuer_To_certmanager_CAIssuer(a.(*v1alpha3.CAIssuer), b.(*certmanager.CAIssuer), scope)
 	}); err != nil {
@@ -51,6 +61,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha3.CAIssuerCRL)(nil), (*certmanager.CAIssuerCRL)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_CAIssuerCRL_To_certmanager_CAIssuerCRL(a.(*v1alpha3.CAIssuerCRL), b.(*certmanager.CAIssuerCRL), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CAIssuerCRL)(nil), (*v1alpha3.CAIssuerCRL)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CAIssuerCRL_To_v1alpha3_CAIssuerCRL(a.(*certmanager.CAIssuerCRL), b.(*v1alpha3.CAIssuerCRL), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1alpha3.Certificate)(nil), (*certmanager.Certificate)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_Certificate_To_certmanager_Certificate(a.(*v1alpha3.Certificate), b.(*certmanager.Certificate), scope)
 	}); err != nil {
@@ -61,6 +81,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha3.CertificateAdditionalOutputFormat)(nil), (*certmanager.CertificateAdditionalOutputFormat)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_CertificateAdditionalOutputFormat_To_certmanager_CertificateAdditionalOutputFormat(a.(*v1alpha3.CertificateAdditionalOutputFormat), b.(*certmanager.CertificateAdditionalOutputFormat), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CertificateAdditionalOutputFormat)(nil), (*v1alpha3.CertificateAdditionalOutputFormat)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CertificateAdditionalOutputFormat_To_v1alpha3_CertificateAdditionalOutputFormat(a.(*certmanager.CertificateAdditionalOutputFormat), b.(*v1alpha3.CertificateAdditionalOutputFormat), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1alpha3.CertificateCondition)(nil), (*certmanager.CertificateCondition)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_CertificateCondition_To_certmanager_CertificateCondition(a.(*v1alpha3.CertificateCondition), b.(*certmanager.CertificateCondition), scope)
 	}); err != nil {
@@ -306,6 +336,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha3.VenafiCustomField)(nil), (*certmanager.VenafiCustomField)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_VenafiCustomField_To_certmanager_VenafiCustomField(a.(*v1alpha3.VenafiCustomField), b.(*certmanager.VenafiCustomField), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.VenafiCustomField)(nil), (*v1alpha3.VenafiCustomField)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_VenafiCustomField_To_v1alpha3_VenafiCustomField(a.(*certmanager.VenafiCustomField), b.(*v1alpha3.VenafiCustomField), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1alpha3.VenafiIssuer)(nil), (*certmanager.VenafiIssuer)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_VenafiIssuer_To_certmanager_VenafiIssuer(a.(*v1alpha3.VenafiIssuer), b.(*certmanager.VenafiIssuer), scope)
 	}); err != nil {
@@ -364,10 +404,50 @@ func RegisterConversions(s *runtime.Scheme) error {
 	return nil
 }
 
+func autoConvert_v1alpha3_AWSPCAIssuer_To_certmanager_AWSPCAIssuer(in *v1alpha3.AWSPCAIssuer, out *certmanager.AWSPCAIssuer, s conversion.Scope) error {
+	out.ARN = in.ARN
+	out.Region = in.Region
+	out.AccessKeyID = in.AccessKeyID
+	// TODO: Inefficient conversion - can we improve it?
+	if err := s.Convert(&in.SecretAccessKey, &out.SecretAccessKey, 0); err != nil {
+		return err
+	}
+	out.Role = in.Role
+	return nil
+}
+
+// Convert_v1alpha3_AWSPCAIssuer_To_certmanager_AWSPCAIssuer is an autogenerated conversion function.
+func Convert_v1alpha3_AWSPCAIssuer_To_certmanager_AWSPCAIssuer(in *v1alpha3.AWSPCAIssuer, out *certmanager.AWSPCAIssuer, s conversion.Scope) error {
+	return autoConvert_v1alpha3_AWSPCAIssuer_To_certmanager_AWSPCAIssuer(in, out, s)
+}
+
+func autoConvert_certmanager_AWSPCAIssuer_To_v1alpha3_AWSPCAIssuer(in *certmanager.AWSPCAIssuer, out *v1alpha3.AWSPCAIssuer, s conversion.Scope) error {
+	out.ARN = in.ARN
+	out.Region = in.Region
+	out.AccessKeyID = in.AccessKeyID
+	// TODO: Inefficient conversion - can we improve it?
+	if err := s.Convert(&in.SecretAccessKey, &out.SecretAccessKey, 0); err != nil {
+		return err
+	}
+	out.Role = in.Role
+	return nil
+}
+
+// Convert_certmanager_AWSPCAIssuer_To_v1alpha3_AWSPCAIssuer is an autogenerated conversion function.
+func Convert_certmanager_AWSPCAIssuer_To_v1alpha3_AWSPCAIssuer(in *certmanager.AWSPCAIssuer, out *v1alpha3.AWSPCAIssuer, s conversion.Scope) error {
+	return autoConvert_certmanager_AWSPCAIssuer_To_v1alpha3_AWSPCAIssuer(in, out, s)
+}
+
 func autoConvert_v1alpha3_CAIssuer_To_certmanager_CAIssuer(in *v1alpha3.CAIssuer, out *certmanager.CAIssuer, s conversion.Scope) error {
 	out.SecretName = in.SecretName
 	out.CRLDistributionPoints = *(*[]string)(unsafe.Pointer(&in.CRLDistributionPoints))
 	out.OCSPServers = *(*[]string)(unsafe.Pointer(&in.OCSPServers))
+	out.IssuingCertificateURLs = *(*[]string)(unsafe.Pointer(&in.IssuingCertificateURLs))
+	out.FreshestCRLDistributionPoints = *(*[]string)(unsafe.Pointer(&in.FreshestCRLDistributionPoints))
+	out.PreferredChain = in.PreferredChain
+	out.AuthorityKeyIdentifier = in.AuthorityKeyIdentifier
+	out.SubjectKeyIdentifierMethod = certmanager.SubjectKeyIdentifierMethod(in.SubjectKeyIdentifierMethod)
+	out.CRL = (*certmanager.CAIssuerCRL)(unsafe.Pointer(in.CRL))
 	return nil
 }
 
@@ -380,6 +460,12 @@ func autoConvert_certmanager_CAIssuer_To_v1alpha3_CAIssuer(in *certmanager.CAIss
 	out.SecretName = in.SecretName
 	out.CRLDistributionPoints = *(*[]string)(unsafe.Pointer(&in.CRLDistributionPoints))
 	out.OCSPServers = *(*[]string)(unsafe.Pointer(&in.OCSPServers))
+	out.IssuingCertificateURLs = *(*[]string)(unsafe.Pointer(&in.IssuingCertificateURLs))
+	out.FreshestCRLDistributionPoints = *(*[]string)(unsafe.Pointer(&in.FreshestCRLDistributionPoints))
+	out.PreferredChain = in.PreferredChain
+	out.AuthorityKeyIdentifier = in.AuthorityKeyIdentifier
+	out.SubjectKeyIdentifierMethod = v1alpha3.SubjectKeyIdentifierMethod(in.SubjectKeyIdentifierMethod)
+	out.CRL = (*v1alpha3.CAIssuerCRL)(unsafe.Pointer(in.CRL))
 	return nil
 }
 
@@ -388,6 +474,28 @@ func Convert_certmanager_CAIssuer_To_v1alpha3_CAIssuer(in *certmanager.CAIssuer,
 	return autoConvert_certmanager_CAIssuer_To_v1alpha3_CAIssuer(in, out, s)
 }
 
+func autoConvert_v1alpha3_CAIssuerCRL_To_certmanager_CAIssuerCRL(in *v1alpha3.CAIssuerCRL, out *certmanager.CAIssuerCRL, s conversion.Scope) error {
+	out.SecretName = in.SecretName
+	out.Duration = (*v1.Duration)(unsafe.Pointer(in.Duration))
+	return nil
+}
+
+// Convert_v1alpha3_CAIssuerCRL_To_certmanager_CAIssuerCRL is an autogenerated conversion function.
+func Convert_v1alpha3_CAIssuerCRL_To_certmanager_CAIssuerCRL(in *v1alpha3.CAIssuerCRL, out *certmanager.CAIssuerCRL, s conversion.Scope) error {
+	return autoConvert_v1alpha3_CAIssuerCRL_To_certmanager_CAIssuerCRL(in, out, s)
+}
+
+func autoConvert_certmanager_CAIssuerCRL_To_v1alpha3_CAIssuerCRL(in *certmanager.CAIssuerCRL, out *v1alpha3.CAIssuerCRL, s conversion.Scope) error {
+	out.SecretName = in.SecretName
+	out.Duration = (*v1.Duration)(unsafe.Pointer(in.Duration))
+	return nil
+}
+
+// Convert_certmanager_CAIssuerCRL_To_v1alpha3_CAIssuerCRL is an autogenerated conversion function.
+func Convert_certmanager_CAIssuerCRL_To_v1alpha3_CAIssuerCRL(in *certmanager.CAIssuerCRL, out *v1alpha3.CAIssuerCRL, s conversion.Scope) error {
+	return autoConvert_certmanager_CAIssuerCRL_To_v1alpha3_CAIssuerCRL(in, out, s)
+}
+
 func autoConvert_v1alpha3_Certificate_To_certmanager_Certificate(in *v1alpha3.Certificate, out *certmanager.Certificate, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha3_CertificateSpec_To_certmanager_CertificateSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -420,6 +528,26 @@ func Convert_certmanager_Certificate_To_v1alpha3_Certificate(in *certmanager.Cer
 	return autoConvert_certmanager_Certificate_To_v1alpha3_Certificate(in, out, s)
 }
 
+func autoConvert_v1alpha3_CertificateAdditionalOutputFormat_To_certmanager_CertificateAdditionalOutputFormat(in *v1alpha3.CertificateAdditionalOutputFormat, out *certmanager.CertificateAdditionalOutputFormat, s conversion.Scope) error {
+	out.Type = certmanager.CertificateOutputFormatType(in.Type)
+	return nil
+}
+
+// Convert_v1alpha3_CertificateAdditionalOutputFormat_To_certmanager_CertificateAdditionalOutputFormat is an autogenerated conversion function.
+func Convert_v1alpha3_CertificateAdditionalOutputFormat_To_certmanager_CertificateAdditionalOutputFormat(in *v1alpha3.CertificateAdditionalOutputFormat, out *certmanager.CertificateAdditionalOutputFormat, s conversion.Scope) error {
+	return autoConvert_v1alpha3_CertificateAdditionalOutputFormat_To_certmanager_CertificateAdditionalOutputFormat(in, out, s)
+}
+
+func autoConvert_certmanager_CertificateAdditionalOutputFormat_To_v1alpha3_CertificateAdditionalOutputFormat(in *certmanager.CertificateAdditionalOutputFormat, out *v1alpha3.CertificateAdditionalOutputFormat, s conversion.Scope) error {
+	out.Type = v1alpha3.CertificateOutputFormatType(in.Type)
+	return nil
+}
+
+// Convert_certmanager_CertificateAdditionalOutputFormat_To_v1alpha3_CertificateAdditionalOutputFormat is an autogenerated conversion function.
+func Convert_certmanager_CertificateAdditionalOutputFormat_To_v1alpha3_CertificateAdditionalOutputFormat(in *certmanager.CertificateAdditionalOutputFormat, out *v1alpha3.CertificateAdditionalOutputFormat, s conversion.Scope) error {
+	return autoConvert_certmanager_CertificateAdditionalOutputFormat_To_v1alpha3_CertificateAdditionalOutputFormat(in, out, s)
+}
+
 func autoConvert_v1alpha3_CertificateCondition_To_certmanager_CertificateCondition(in *v1alpha3.CertificateCondition, out *certmanager.CertificateCondition, s conversion.Scope) error {
 	out.Type = certmanager.CertificateConditionType(in.Type)
 	out.Status = meta.ConditionStatus(in.Status)
@@ -514,6 +642,8 @@ func Convert_certmanager_CertificateList_To_v1alpha3_CertificateList(in *certman
 
 func autoConvert_v1alpha3_CertificatePrivateKey_To_certmanager_CertificatePrivateKey(in *v1alpha3.CertificatePrivateKey, out *certmanager.CertificatePrivateKey, s conversion.Scope) error {
 	out.RotationPolicy = certmanager.PrivateKeyRotationPolicy(in.RotationPolicy)
+	out.EncryptionPassphraseSecretRef = (*meta.SecretKeySelector)(unsafe.Pointer(in.EncryptionPassphraseSecretRef))
+	out.SignatureHash = certmanager.PrivateKeySignatureHash(in.SignatureHash)
 	return nil
 }
 
@@ -527,6 +657,8 @@ func autoConvert_certmanager_CertificatePrivateKey_To_v1alpha3_CertificatePrivat
 	// WARNING: in.Encoding requires manual conversion: does not exist in peer-type
 	// WARNING: in.Algorithm requires manual conversion: does not exist in peer-type
 	// WARNING: in.Size requires manual conversion: does not exist in peer-type
+	out.EncryptionPassphraseSecretRef = (*metav1.SecretKeySelector)(unsafe.Pointer(in.EncryptionPassphraseSecretRef))
+	out.SignatureHash = v1alpha3.PrivateKeySignatureHash(in.SignatureHash)
 	return nil
 }
 
@@ -638,6 +770,7 @@ func autoConvert_v1alpha3_CertificateRequestSpec_To_certmanager_CertificateReque
 	if err := s.Convert(&in.IssuerRef, &out.IssuerRef, 0); err != nil {
 		return err
 	}
+	out.FallbackIssuerRef = (*meta.ObjectReference)(unsafe.Pointer(in.FallbackIssuerRef))
 	// WARNING: in.CSRPEM requires manual conversion: does not exist in peer-type
 	out.IsCA = in.IsCA
 	out.Usages = *(*[]certmanager.KeyUsage)(unsafe.Pointer(&in.Usages))
@@ -650,6 +783,7 @@ func autoConvert_certmanager_CertificateRequestSpec_To_v1alpha3_CertificateReque
 	if err := s.Convert(&in.IssuerRef, &out.IssuerRef, 0); err != nil {
 		return err
 	}
+	out.FallbackIssuerRef = (*metav1.ObjectReference)(unsafe.Pointer(in.FallbackIssuerRef))
 	// WARNING: in.Request requires manual conversion: does not exist in peer-type
 	out.IsCA = in.IsCA
 	out.Usages = *(*[]v1alpha3.KeyUsage)(unsafe.Pointer(&in.Usages))
@@ -695,17 +829,23 @@ func autoConvert_v1alpha3_CertificateSpec_To_certmanager_CertificateSpec(in *v1a
 	out.CommonName = in.CommonName
 	out.Duration = (*v1.Duration)(unsafe.Pointer(in.Duration))
 	out.RenewBefore = (*v1.Duration)(unsafe.Pointer(in.RenewBefore))
+	out.IssuanceTimeout = (*v1.Duration)(unsafe.Pointer(in.IssuanceTimeout))
 	out.DNSNames = *(*[]string)(unsafe.Pointer(&in.DNSNames))
 	out.IPAddresses = *(*[]string)(unsafe.Pointer(&in.IPAddresses))
 	out.URISANs = *(*[]string)(unsafe.Pointer(&in.URISANs))
 	out.EmailSANs = *(*[]string)(unsafe.Pointer(&in.EmailSANs))
+	out.RegisteredIDs = *(*[]string)(unsafe.Pointer(&in.RegisteredIDs))
 	out.SecretName = in.SecretName
 	out.Keystores = (*certmanager.CertificateKeystores)(unsafe.Pointer(in.Keystores))
+	out.AdditionalOutputFormats = *(*[]certmanager.CertificateAdditionalOutputFormat)(unsafe.Pointer(&in.AdditionalOutputFormats))
 	// TODO: Inefficient conversion - can we improve it?
 	if err := s.Convert(&in.IssuerRef, &out.IssuerRef, 0); err != nil {
 		return err
 	}
+	out.FallbackIssuerRef = (*meta.ObjectReference)(unsafe.Pointer(in.FallbackIssuerRef))
 	out.IsCA = in.IsCA
+	out.MaxPathLen = in.MaxPathLen
+	out.MaxPathLenZero = in.MaxPathLenZero
 	out.Usages = *(*[]certmanager.KeyUsage)(unsafe.Pointer(&in.Usages))
 	// WARNING: in.KeySize requires manual conversion: does not exist in peer-type
 	// WARNING: in.KeyAlgorithm requires manual conversion: does not exist in peer-type
@@ -720,6 +860,7 @@ func autoConvert_v1alpha3_CertificateSpec_To_certmanager_CertificateSpec(in *v1a
 		out.PrivateKey = nil
 	}
 	out.EncodeUsagesInRequest = (*bool)(unsafe.Pointer(in.EncodeUsagesInRequest))
+	out.TemplateRef = (*meta.LocalObjectReference)(unsafe.Pointer(in.TemplateRef))
 	return nil
 }
 
@@ -736,17 +877,23 @@ func autoConvert_certmanager_CertificateSpec_To_v1alpha3_CertificateSpec(in *cer
 	out.CommonName = in.CommonName
 	out.Duration = (*v1.Duration)(unsafe.Pointer(in.Duration))
 	out.RenewBefore = (*v1.Duration)(unsafe.Pointer(in.RenewBefore))
+	out.IssuanceTimeout = (*v1.Duration)(unsafe.Pointer(in.IssuanceTimeout))
 	out.DNSNames = *(*[]string)(unsafe.Pointer(&in.DNSNames))
 	out.IPAddresses = *(*[]string)(unsafe.Pointer(&in.IPAddresses))
 	out.URISANs = *(*[]string)(unsafe.Pointer(&in.URISANs))
 	out.EmailSANs = *(*[]string)(unsafe.Pointer(&in.EmailSANs))
+	out.RegisteredIDs = *(*[]string)(unsafe.Pointer(&in.RegisteredIDs))
 	out.SecretName = in.SecretName
 	out.Keystores = (*v1alpha3.CertificateKeystores)(unsafe.Pointer(in.Keystores))
+	out.AdditionalOutputFormats = *(*[]v1alpha3.CertificateAdditionalOutputFormat)(unsafe.Pointer(&in.AdditionalOutputFormats))
 	// TODO: Inefficient conversion - can we improve it?
 	if err := s.Convert(&in.IssuerRef, &out.IssuerRef, 0); err != nil {
 		return err
 	}
+	out.FallbackIssuerRef = (*metav1.ObjectReference)(unsafe.Pointer(in.FallbackIssuerRef))
 	out.IsCA = in.IsCA
+	out.MaxPathLen = in.MaxPathLen
+	out.MaxPathLenZero = in.MaxPathLenZero
 	out.Usages = *(*[]v1alpha3.KeyUsage)(unsafe.Pointer(&in.Usages))
 	if in.PrivateKey != nil {
 		in, out := &in.PrivateKey, &out.PrivateKey
@@ -758,6 +905,7 @@ func autoConvert_certmanager_CertificateSpec_To_v1alpha3_CertificateSpec(in *cer
 		out.PrivateKey = nil
 	}
 	out.EncodeUsagesInRequest = (*bool)(unsafe.Pointer(in.EncodeUsagesInRequest))
+	out.TemplateRef = (*metav1.LocalObjectReference)(unsafe.Pointer(in.TemplateRef))
 	return nil
 }
 
@@ -913,6 +1061,7 @@ func autoConvert_v1alpha3_IssuerConfig_To_certmanager_IssuerConfig(in *v1alpha3.
 	out.Vault = (*certmanager.VaultIssuer)(unsafe.Pointer(in.Vault))
 	out.SelfSigned = (*certmanager.SelfSignedIssuer)(unsafe.Pointer(in.SelfSigned))
 	out.Venafi = (*certmanager.VenafiIssuer)(unsafe.Pointer(in.Venafi))
+	out.AWSPCA = (*certmanager.AWSPCAIssuer)(unsafe.Pointer(in.AWSPCA))
 	return nil
 }
 
@@ -927,6 +1076,7 @@ func autoConvert_certmanager_IssuerConfig_To_v1alpha3_IssuerConfig(in *certmanag
 	out.Vault = (*v1alpha3.VaultIssuer)(unsafe.Pointer(in.Vault))
 	out.SelfSigned = (*v1alpha3.SelfSignedIssuer)(unsafe.Pointer(in.SelfSigned))
 	out.Venafi = (*v1alpha3.VenafiIssuer)(unsafe.Pointer(in.Venafi))
+	out.AWSPCA = (*v1alpha3.AWSPCAIssuer)(unsafe.Pointer(in.AWSPCA))
 	return nil
 }
 
@@ -1037,6 +1187,7 @@ func autoConvert_v1alpha3_PKCS12Keystore_To_certmanager_PKCS12Keystore(in *v1alp
 	if err := s.Convert(&in.PasswordSecretRef, &out.PasswordSecretRef, 0); err != nil {
 		return err
 	}
+	out.IncludeCAChain = in.IncludeCAChain
 	return nil
 }
 
@@ -1051,6 +1202,7 @@ func autoConvert_certmanager_PKCS12Keystore_To_v1alpha3_PKCS12Keystore(in *certm
 	if err := s.Convert(&in.PasswordSecretRef, &out.PasswordSecretRef, 0); err != nil {
 		return err
 	}
+	out.IncludeCAChain = in.IncludeCAChain
 	return nil
 }
 
@@ -1223,10 +1375,33 @@ func Convert_certmanager_VenafiCloud_To_v1alpha3_VenafiCloud(in *certmanager.Ven
 	return autoConvert_certmanager_VenafiCloud_To_v1alpha3_VenafiCloud(in, out, s)
 }
 
+func autoConvert_v1alpha3_VenafiCustomField_To_certmanager_VenafiCustomField(in *v1alpha3.VenafiCustomField, out *certmanager.VenafiCustomField, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Value = in.Value
+	return nil
+}
+
+// Convert_v1alpha3_VenafiCustomField_To_certmanager_VenafiCustomField is an autogenerated conversion function.
+func Convert_v1alpha3_VenafiCustomField_To_certmanager_VenafiCustomField(in *v1alpha3.VenafiCustomField, out *certmanager.VenafiCustomField, s conversion.Scope) error {
+	return autoConvert_v1alpha3_VenafiCustomField_To_certmanager_VenafiCustomField(in, out, s)
+}
+
+func autoConvert_certmanager_VenafiCustomField_To_v1alpha3_VenafiCustomField(in *certmanager.VenafiCustomField, out *v1alpha3.VenafiCustomField, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Value = in.Value
+	return nil
+}
+
+// Convert_certmanager_VenafiCustomField_To_v1alpha3_VenafiCustomField is an autogenerated conversion function.
+func Convert_certmanager_VenafiCustomField_To_v1alpha3_VenafiCustomField(in *certmanager.VenafiCustomField, out *v1alpha3.VenafiCustomField, s conversion.Scope) error {
+	return autoConvert_certmanager_VenafiCustomField_To_v1alpha3_VenafiCustomField(in, out, s)
+}
+
 func autoConvert_v1alpha3_VenafiIssuer_To_certmanager_VenafiIssuer(in *v1alpha3.VenafiIssuer, out *certmanager.VenafiIssuer, s conversion.Scope) error {
 	out.Zone = in.Zone
 	out.TPP = (*certmanager.VenafiTPP)(unsafe.Pointer(in.TPP))
 	out.Cloud = (*certmanager.VenafiCloud)(unsafe.Pointer(in.Cloud))
+	out.CustomFields = *(*[]certmanager.VenafiCustomField)(unsafe.Pointer(&in.CustomFields))
 	return nil
 }
 
@@ -1239,6 +1414,7 @@ func autoConvert_certmanager_VenafiIssuer_To_v1alpha3_VenafiIssuer(in *certmanag
 	out.Zone = in.Zone
 	out.TPP = (*v1alpha3.VenafiTPP)(unsafe.Pointer(in.TPP))
 	out.Cloud = (*v1alpha3.VenafiCloud)(unsafe.Pointer(in.Cloud))
+	out.CustomFields = *(*[]v1alpha3.VenafiCustomField)(unsafe.Pointer(&in.CustomFields))
 	return nil
 }
 
@@ -41,6 +41,16 @@ func init() {
 // RegisterConversions adds conversion functions to the given scheme.
 // Public to allow building arbitrary schemes.
 func RegisterConversions(s *runtime.Scheme) error {
+	if err := s.AddGeneratedConversionFunc((*v1beta1.AWSPCAIssuer)(nil), (*certmanager.AWSPCAIssuer)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_AWSPCAIssuer_To_certmanager_AWSPCAIssuer(a.(*v1beta1.AWSPCAIssuer), b.(*certmanager.AWSPCAIssuer), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.AWSPCAIssuer)(nil), (*v1beta1.AWSPCAIssuer)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_AWSPCAIssuer_To_v1beta1_AWSPCAIssuer(a.(*certmanager.AWSPCAIssuer), b.(*v1beta1.AWSPCAIssuer), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1beta1.CAIssuer)(nil), (*certmanager.CAIssuer)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_CAIssuer_To_certmanager_CAIssuer(a.(*v1beta1.CAIssuer), b.(*certmanager.CAIssuer), scope)
 	}); err != nil {
@@ -51,6 +61,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.CAIssuerCRL)(nil), (*certmanager.CAIssuerCRL)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_CAIssuerCRL_To_certmanager_CAIssuerCRL(a.(*v1beta1.CAIssuerCRL), b.(*certmanager.CAIssuerCRL), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CAIssuerCRL)(nil), (*v1beta1.CAIssuerCRL)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CAIssuerCRL_To_v1beta1_CAIssuerCRL(a.(*certmanager.CAIssuerCRL), b.(*v1beta1.CAIssuerCRL), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1beta1.Certificate)(nil), (*certmanager.Certificate)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_Certificate_To_certmanager_Certificate(a.(*v1beta1.Certificate), b.(*certmanager.Certificate), scope)
 	}); err != nil {
@@ -61,6 +81,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.CertificateAdditionalOutputFormat)(nil), (*certmanager.CertificateAdditionalOutputFormat)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_CertificateAdditionalOutputFormat_To_certmanager_CertificateAdditionalOutputFormat(a.(*v1beta1.CertificateAdditionalOutputFormat), b.(*certmanager.CertificateAdditionalOutputFormat), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CertificateAdditionalOutputFormat)(nil), (*v1beta1.CertificateAdditionalOutputFormat)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CertificateAdditionalOutputFormat_To_v1beta1_CertificateAdditionalOutputFormat(a.(*certmanager.CertificateAdditionalOutputFormat), b.(*v1beta1.CertificateAdditionalOutputFormat), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1beta1.CertificateCondition)(nil), (*certmanager.CertificateCondition)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_CertificateCondition_To_certmanager_CertificateCondition(a.(*v1beta1.CertificateCondition), b.(*certmanager.CertificateCondition), scope)
 	}); err != nil {
@@ -331,6 +361,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.VenafiCustomField)(nil), (*certmanager.VenafiCustomField)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_VenafiCustomField_To_certmanager_VenafiCustomField(a.(*v1beta1.VenafiCustomField), b.(*certmanager.VenafiCustomField), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.VenafiCustomField)(nil), (*v1beta1.VenafiCustomField)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_VenafiCustomField_To_v1beta1_VenafiCustomField(a.(*certmanager.VenafiCustomField), b.(*v1beta1.VenafiCustomField), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1beta1.VenafiIssuer)(nil), (*certmanager.VenafiIssuer)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_VenafiIssuer_To_certmanager_VenafiIssuer(a.(*v1beta1.VenafiIssuer), b.(*certmanager.VenafiIssuer), scope)
 	}); err != nil {
@@ -364,10 +404,50 @@ func RegisterConversions(s *runtime.Scheme) error {
 	return nil
 }
 
+func autoConvert_v1beta1_AWSPCAIssuer_To_certmanager_AWSPCAIssuer(in *v1beta1.AWSPCAIssuer, out *certmanager.AWSPCAIssuer, s conversion.Scope) error {
+	out.ARN = in.ARN
+	out.Region = in.Region
+	out.AccessKeyID = in.AccessKeyID
+	// TODO: Inefficient conversion - can we improve it?
+	if err := s.Convert(&in.SecretAccessKey, &out.SecretAccessKey, 0); err != nil {
+		return err
+	}
+	out.Role = in.Role
+	return nil
+}
+
+// Convert_v1beta1_AWSPCAIssuer_To_certmanager_AWSPCAIssuer is an autogenerated conversion function.
+func Convert_v1beta1_AWSPCAIssuer_To_certmanager_AWSPCAIssuer(in *v1beta1.AWSPCAIssuer, out *certmanager.AWSPCAIssuer, s conversion.Scope) error {
+	return autoConvert_v1beta1_AWSPCAIssuer_To_certmanager_AWSPCAIssuer(in, out, s)
+}
+
+func autoConvert_certmanager_AWSPCAIssuer_To_v1beta1_AWSPCAIssuer(in *certmanager.AWSPCAIssuer, out *v1beta1.AWSPCAIssuer, s conversion.Scope) error {
+	out.ARN = in.ARN
+	out.Region = in.Region
+	out.AccessKeyID = in.AccessKeyID
+	// TODO: Inefficient conversion - can we improve it?
+	if err := s.Convert(&in.SecretAccessKey, &out.SecretAccessKey, 0); err != nil {
+		return err
+	}
+	out.Role = in.Role
+	return nil
+}
+
+// Convert_certmanager_AWSPCAIssuer_To_v1beta1_AWSPCAIssuer is an autogenerated conversion function.
+func Convert_certmanager_AWSPCAIssuer_To_v1beta1_AWSPCAIssuer(in *certmanager.AWSPCAIssuer, out *v1beta1.AWSPCAIssuer, s conversion.Scope) error {
+	return autoConvert_certmanager_AWSPCAIssuer_To_v1beta1_AWSPCAIssuer(in, out, s)
+}
+
 func autoConvert_v1beta1_CAIssuer_To_certmanager_CAIssuer(in *v1beta1.CAIssuer, out *certmanager.CAIssuer, s conversion.Scope) error {
 	out.SecretName = in.SecretName
 	out.CRLDistributionPoints = *(*[]string)(unsafe.Pointer(&in.CRLDistributionPoints))
 	out.OCSPServers = *(*[]string)(unsafe.Pointer(&in.OCSPServers))
+	out.IssuingCertificateURLs = *(*[]string)(unsafe.Pointer(&in.IssuingCertificateURLs))
+	out.FreshestCRLDistributionPoints = *(*[]string)(unsafe.Pointer(&in.FreshestCRLDistributionPoints))
+	out.PreferredChain = in.PreferredChain
+	out.AuthorityKeyIdentifier = in.AuthorityKeyIdentifier
+	out.SubjectKeyIdentifierMethod = certmanager.SubjectKeyIdentifierMethod(in.SubjectKeyIdentifierMethod)
+	out.CRL = (*certmanager.CAIssuerCRL)(unsafe.Pointer(in.CRL))
 	return nil
 }
 
@@ -380,6 +460,12 @@ func autoConvert_certmanager_CAIssuer_To_v1beta1_CAIssuer(in *certmanager.CAIssu
 	out.SecretName = in.SecretName
 	out.CRLDistributionPoints = *(*[]string)(unsafe.Pointer(&in.CRLDistributionPoints))
 	out.OCSPServers = *(*[]string)(unsafe.Pointer(&in.OCSPServers))
+	out.IssuingCertificateURLs = *(*[]string)(unsafe.Pointer(&in.IssuingCertificateURLs))
+	out.FreshestCRLDistributionPoints = *(*[]string)(unsafe.Pointer(&in.FreshestCRLDistributionPoints))
+	out.PreferredChain = in.PreferredChain
+	out.AuthorityKeyIdentifier = in.AuthorityKeyIdentifier
+	out.SubjectKeyIdentifierMethod = v1beta1.SubjectKeyIdentifierMethod(in.SubjectKeyIdentifierMethod)
+	out.CRL = (*v1beta1.CAIssuerCRL)(unsafe.Pointer(in.CRL))
 	return nil
 }
 
@@ -388,6 +474,28 @@ func Convert_certmanager_CAIssuer_To_v1beta1_CAIssuer(in *certmanager.CAIssuer,
 	return autoConvert_certmanager_CAIssuer_To_v1beta1_CAIssuer(in, out, s)
 }
 
+func autoConvert_v1beta1_CAIssuerCRL_To_certmanager_CAIssuerCRL(in *v1beta1.CAIssuerCRL, out *certmanager.CAIssuerCRL, s conversion.Scope) error {
+	out.SecretName = in.SecretName
+	out.Duration = (*v1.Duration)(unsafe.Pointer(in.Duration))
+	return nil
+}
+
+// Convert_v1beta1_CAIssuerCRL_To_certmanager_CAIssuerCRL is an autogenerated conversion function.
+func Convert_v1beta1_CAIssuerCRL_To_certmanager_CAIssuerCRL(in *v1beta1.CAIssuerCRL, out *certmanager.CAIssuerCRL, s conversion.Scope) error {
+	return autoConvert_v1beta1_CAIssuerCRL_To_certmanager_CAIssuerCRL(in, out, s)
+}
+
+func autoConvert_certmanager_CAIssuerCRL_To_v1beta1_CAIssuerCRL(in *certmanager.CAIssuerCRL, out *v1beta1.CAIssuerCRL, s conversion.Scope) error {
+	out.SecretName = in.SecretName
+	out.Duration = (*v1.Duration)(unsafe.Pointer(in.Duration))
+	return nil
+}
+
+// Convert_certmanager_CAIssuerCRL_To_v1beta1_CAIssuerCRL is an autogenerated conversion function.
+func Convert_certmanager_CAIssuerCRL_To_v1beta1_CAIssuerCRL(in *certmanager.CAIssuerCRL, out *v1beta1.CAIssuerCRL, s conversion.Scope) error {
+	return autoConvert_certmanager_CAIssuerCRL_To_v1beta1_CAIssuerCRL(in, out, s)
+}
+
 func autoConvert_v1beta1_Certificate_To_certmanager_Certificate(in *v1beta1.Certificate, out *certmanager.Certificate, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1beta1_CertificateSpec_To_certmanager_CertificateSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -420,6 +528,26 @@ func Convert_certmanager_Certificate_To_v1beta1_Certificate(in *certmanager.Cert
 	return autoConvert_certmanager_Certificate_To_v1beta1_Certificate(in, out, s)
 }
 
+func autoConvert_v1beta1_CertificateAdditionalOutputFormat_To_certmanager_CertificateAdditionalOutputFormat(in *v1beta1.CertificateAdditionalOutputFormat, out *certmanager.CertificateAdditionalOutputFormat, s conversion.Scope) error {
+	out.Type = certmanager.CertificateOutputFormatType(in.Type)
+	return nil
+}
+
+// Convert_v1beta1_CertificateAdditionalOutputFormat_To_certmanager_CertificateAdditionalOutputFormat is an autogenerated conversion function.
+func Convert_v1beta1_CertificateAdditionalOutputFormat_To_certmanager_CertificateAdditionalOutputFormat(in *v1beta1.CertificateAdditionalOutputFormat, out *certmanager.CertificateAdditionalOutputFormat, s conversion.Scope) error {
+	return autoConvert_v1beta1_CertificateAdditionalOutputFormat_To_certmanager_CertificateAdditionalOutputFormat(in, out, s)
+}
+
+func autoConvert_certmanager_CertificateAdditionalOutputFormat_To_v1beta1_CertificateAdditionalOutputFormat(in *certmanager.CertificateAdditionalOutputFormat, out *v1beta1.CertificateAdditionalOutputFormat, s conversion.Scope) error {
+	out.Type = v1beta1.CertificateOutputFormatType(in.Type)
+	return nil
+}
+
+// Convert_certmanager_CertificateAdditionalOutputFormat_To_v1beta1_CertificateAdditionalOutputFormat is an autogenerated conversion function.
+func Convert_certmanager_CertificateAdditionalOutputFormat_To_v1beta1_CertificateAdditionalOutputFormat(in *certmanager.CertificateAdditionalOutputFormat, out *v1beta1.CertificateAdditionalOutputFormat, s conversion.Scope) error {
+	return autoConvert_certmanager_CertificateAdditionalOutputFormat_To_v1beta1_CertificateAdditionalOutputFormat(in, out, s)
+}
+
 func autoConvert_v1beta1_CertificateCondition_To_certmanager_CertificateCondition(in *v1beta1.CertificateCondition, out *certmanager.CertificateCondition, s conversion.Scope) error {
 	out.Type = certmanager.CertificateConditionType(in.Type)
 	out.Status = meta.ConditionStatus(in.Status)
@@ -497,6 +625,8 @@ func autoConvert_v1beta1_CertificatePrivateKey_To_certmanager_CertificatePrivate
 	out.Encoding = certmanager.PrivateKeyEncoding(in.Encoding)
 	out.Algorithm = certmanager.PrivateKeyAlgorithm(in.Algorithm)
 	out.Size = in.Size
+	out.EncryptionPassphraseSecretRef = (*meta.SecretKeySelector)(unsafe.Pointer(in.EncryptionPassphraseSecretRef))
+	out.SignatureHash = certmanager.PrivateKeySignatureHash(in.SignatureHash)
 	return nil
 }
 
@@ -510,6 +640,8 @@ func autoConvert_certmanager_CertificatePrivateKey_To_v1beta1_CertificatePrivate
 	out.Encoding = v1beta1.PrivateKeyEncoding(in.Encoding)
 	out.Algorithm = v1beta1.PrivateKeyAlgorithm(in.Algorithm)
 	out.Size = in.Size
+	out.EncryptionPassphraseSecretRef = (*metav1.SecretKeySelector)(unsafe.Pointer(in.EncryptionPassphraseSecretRef))
+	out.SignatureHash = v1beta1.PrivateKeySignatureHash(in.SignatureHash)
 	return nil
 }
 
@@ -606,6 +738,7 @@ func autoConvert_v1beta1_CertificateRequestSpec_To_certmanager_CertificateReques
 	if err := s.Convert(&in.IssuerRef, &out.IssuerRef, 0); err != nil {
 		return err
 	}
+	out.FallbackIssuerRef = (*meta.ObjectReference)(unsafe.Pointer(in.FallbackIssuerRef))
 	out.Request = *(*[]byte)(unsafe.Pointer(&in.Request))
 	out.IsCA = in.IsCA
 	out.Usages = *(*[]certmanager.KeyUsage)(unsafe.Pointer(&in.Usages))
@@ -623,6 +756,7 @@ func autoConvert_certmanager_CertificateRequestSpec_To_v1beta1_CertificateReques
 	if err := s.Convert(&in.IssuerRef, &out.IssuerRef, 0); err != nil {
 		return err
 	}
+	out.FallbackIssuerRef = (*metav1.ObjectReference)(unsafe.Pointer(in.FallbackIssuerRef))
 	out.Request = *(*[]byte)(unsafe.Pointer(&in.Request))
 	out.IsCA = in.IsCA
 	out.Usages = *(*[]v1beta1.KeyUsage)(unsafe.Pointer(&in.Usages))
@@ -665,20 +799,27 @@ func autoConvert_v1beta1_CertificateSpec_To_certmanager_CertificateSpec(in *v1be
 	out.CommonName = in.CommonName
 	out.Duration = (*v1.Duration)(unsafe.Pointer(in.Duration))
 	out.RenewBefore = (*v1.Duration)(unsafe.Pointer(in.RenewBefore))
+	out.IssuanceTimeout = (*v1.Duration)(unsafe.Pointer(in.IssuanceTimeout))
 	out.DNSNames = *(*[]string)(unsafe.Pointer(&in.DNSNames))
 	out.IPAddresses = *(*[]string)(unsafe.Pointer(&in.IPAddresses))
 	out.URISANs = *(*[]string)(unsafe.Pointer(&in.URISANs))
 	out.EmailSANs = *(*[]string)(unsafe.Pointer(&in.EmailSANs))
+	out.RegisteredIDs = *(*[]string)(unsafe.Pointer(&in.RegisteredIDs))
 	out.SecretName = in.SecretName
 	out.Keystores = (*certmanager.CertificateKeystores)(unsafe.Pointer(in.Keystores))
+	out.AdditionalOutputFormats = *(*[]certmanager.CertificateAdditionalOutputFormat)(unsafe.Pointer(&in.AdditionalOutputFormats))
 	// TODO: Inefficient conversion - can we improve it?
 	if err := s.Convert(&in.IssuerRef, &out.IssuerRef, 0); err != nil {
 		return err
 	}
+	out.FallbackIssuerRef = (*meta.ObjectReference)(unsafe.Pointer(in.FallbackIssuerRef))
 	out.IsCA = in.IsCA
+	out.MaxPathLen = in.MaxPathLen
+	out.MaxPathLenZero = in.MaxPathLenZero
 	out.Usages = *(*[]certmanager.KeyUsage)(unsafe.Pointer(&in.Usages))
 	out.PrivateKey = (*certmanager.CertificatePrivateKey)(unsafe.Pointer(in.PrivateKey))
 	out.EncodeUsagesInRequest = (*bool)(unsafe.Pointer(in.EncodeUsagesInRequest))
+	out.TemplateRef = (*meta.LocalObjectReference)(unsafe.Pointer(in.TemplateRef))
 	return nil
 }
 
@@ -692,20 +833,27 @@ func autoConvert_certmanager_CertificateSpec_To_v1beta1_CertificateSpec(in *cert
 	out.CommonName = in.CommonName
 	out.Duration = (*v1.Duration)(unsafe.Pointer(in.Duration))
 	out.RenewBefore = (*v1.Duration)(unsafe.Pointer(in.RenewBefore))
+	out.IssuanceTimeout = (*v1.Duration)(unsafe.Pointer(in.IssuanceTimeout))
 	out.DNSNames = *(*[]string)(unsafe.Pointer(&in.DNSNames))
 	out.IPAddresses = *(*[]string)(unsafe.Pointer(&in.IPAddresses))
 	out.URISANs = *(*[]string)(unsafe.Pointer(&in.URISANs))
 	out.EmailSANs = *(*[]string)(unsafe.Pointer(&in.EmailSANs))
+	out.RegisteredIDs = *(*[]string)(unsafe.Pointer(&in.RegisteredIDs))
 	out.SecretName = in.SecretName
 	out.Keystores = (*v1beta1.CertificateKeystores)(unsafe.Pointer(in.Keystores))
+	out.AdditionalOutputFormats = *(*[]v1beta1.CertificateAdditionalOutputFormat)(unsafe.Pointer(&in.AdditionalOutputFormats))
 	// TODO: Inefficient conversion - can we improve it?
 	if err := s.Convert(&in.IssuerRef, &out.IssuerRef, 0); err != nil {
 		return err
 	}
+	out.FallbackIssuerRef = (*metav1.ObjectReference)(unsafe.Pointer(in.FallbackIssuerRef))
 	out.IsCA = in.IsCA
+	out.MaxPathLen = in.MaxPathLen
+	out.MaxPathLenZero = in.MaxPathLenZero
 	out.Usages = *(*[]v1beta1.KeyUsage)(unsafe.Pointer(&in.Usages))
 	out.PrivateKey = (*v1beta1.CertificatePrivateKey)(unsafe.Pointer(in.PrivateKey))
 	out.EncodeUsagesInRequest = (*bool)(unsafe.Pointer(in.EncodeUsagesInRequest))
+	out.TemplateRef = (*metav1.LocalObjectReference)(unsafe.Pointer(in.TemplateRef))
 	return nil
 }
 
@@ -866,6 +1014,7 @@ func autoConvert_v1beta1_IssuerConfig_To_certmanager_IssuerConfig(in *v1beta1.Is
 	out.Vault = (*certmanager.VaultIssuer)(unsafe.Pointer(in.Vault))
 	out.SelfSigned = (*certmanager.SelfSignedIssuer)(unsafe.Pointer(in.SelfSigned))
 	out.Venafi = (*certmanager.VenafiIssuer)(unsafe.Pointer(in.Venafi))
+	out.AWSPCA = (*certmanager.AWSPCAIssuer)(unsafe.Pointer(in.AWSPCA))
 	return nil
 }
 
@@ -880,6 +1029,7 @@ func autoConvert_certmanager_IssuerConfig_To_v1beta1_IssuerConfig(in *certmanage
 	out.Vault = (*v1beta1.VaultIssuer)(unsafe.Pointer(in.Vault))
 	out.SelfSigned = (*v1beta1.SelfSignedIssuer)(unsafe.Pointer(in.SelfSigned))
 	out.Venafi = (*v1beta1.VenafiIssuer)(unsafe.Pointer(in.Venafi))
+	out.AWSPCA = (*v1beta1.AWSPCAIssuer)(unsafe.Pointer(in.AWSPCA))
 	return nil
 }
 
@@ -990,6 +1140,7 @@ func autoConvert_v1beta1_PKCS12Keystore_To_certmanager_PKCS12Keystore(in *v1beta
 	if err := s.Convert(&in.PasswordSecretRef, &out.PasswordSecretRef, 0); err != nil {
 		return err
 	}
+	out.IncludeCAChain = in.IncludeCAChain
 	return nil
 }
 
@@ -1004,6 +1155,7 @@ func autoConvert_certmanager_PKCS12Keystore_To_v1beta1_PKCS12Keystore(in *certma
 	if err := s.Convert(&in.PasswordSecretRef, &out.PasswordSecretRef, 0); err != nil {
 		return err
 	}
+	out.IncludeCAChain = in.IncludeCAChain
 	return nil
 }
 
@@ -1176,10 +1328,33 @@ func Convert_certmanager_VenafiCloud_To_v1beta1_VenafiCloud(in *certmanager.Vena
 	return autoConvert_certmanager_VenafiCloud_To_v1beta1_VenafiCloud(in, out, s)
 }
 
+func autoConvert_v1beta1_VenafiCustomField_To_certmanager_VenafiCustomField(in *v1beta1.VenafiCustomField, out *certmanager.VenafiCustomField, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Value = in.Value
+	return nil
+}
+
+// Convert_v1beta1_VenafiCustomField_To_certmanager_VenafiCustomField is an autogenerated conversion function.
+func Convert_v1beta1_VenafiCustomField_To_certmanager_VenafiCustomField(in *v1beta1.VenafiCustomField, out *certmanager.VenafiCustomField, s conversion.Scope) error {
+	return autoConvert_v1beta1_VenafiCustomField_To_certmanager_VenafiCustomField(in, out, s)
+}
+
+func autoConvert_certmanager_VenafiCustomField_To_v1beta1_VenafiCustomField(in *certmanager.VenafiCustomField, out *v1beta1.VenafiCustomField, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Value = in.Value
+	return nil
+}
+
+// Convert_certmanager_VenafiCustomField_To_v1beta1_VenafiCustomField is an autogenerated conversion function.
+func Convert_certmanager_VenafiCustomField_To_v1beta1_VenafiCustomField(in *certmanager.VenafiCustomField, out *v1beta1.VenafiCustomField, s conversion.Scope) error {
+	return autoConvert_certmanager_VenafiCustomField_To_v1beta1_VenafiCustomField(in, out, s)
+}
+
 func autoConvert_v1beta1_VenafiIssuer_To_certmanager_VenafiIssuer(in *v1beta1.VenafiIssuer, out *certmanager.VenafiIssuer, s conversion.Scope) error {
 	out.Zone = in.Zone
 	out.TPP = (*certmanager.VenafiTPP)(unsafe.Pointer(in.TPP))
 	out.Cloud = (*certmanager.VenafiCloud)(unsafe.Pointer(in.Cloud))
+	out.CustomFields = *(*[]certmanager.VenafiCustomField)(unsafe.Pointer(&in.CustomFields))
 	return nil
 }
 
@@ -1192,6 +1367,7 @@ func autoConvert_certmanager_VenafiIssuer_To_v1beta1_VenafiIssuer(in *certmanage
 	out.Zone = in.Zone
 	out.TPP = (*v1beta1.VenafiTPP)(unsafe.Pointer(in.TPP))
 	out.Cloud = (*v1beta1.VenafiCloud)(unsafe.Pointer(in.Cloud))
+	out.CustomFields = *(*[]v1beta1.VenafiCustomField)(unsafe.Pointer(&in.CustomFields))
 	return nil
 }
 
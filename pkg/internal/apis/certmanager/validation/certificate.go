@@ -20,14 +20,20 @@ import (
 	"fmt"
 	"net"
 	"net/mail"
+	"strings"
+	"unicode"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"github.com/jetstack/cert-manager/pkg/api/util"
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	internalcmapi "github.com/jetstack/cert-manager/pkg/internal/apis/certmanager"
 	cmmeta "github.com/jetstack/cert-manager/pkg/internal/apis/meta"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
 )
 
 // Validation functions for cert-manager Certificate types
@@ -39,9 +45,11 @@ func ValidateCertificateSpec(crt *internalcmapi.CertificateSpec, fldPath *field.
 	}
 
 	el = append(el, validateIssuerRef(crt.IssuerRef, fldPath)...)
+	el = append(el, validateFallbackIssuerRef(crt.IssuerRef, crt.FallbackIssuerRef, fldPath)...)
+	el = append(el, validateTemplateRef(crt.TemplateRef, fldPath)...)
 
-	if len(crt.CommonName) == 0 && len(crt.DNSNames) == 0 && len(crt.URISANs) == 0 && len(crt.EmailSANs) == 0 && len(crt.IPAddresses) == 0 {
-		el = append(el, field.Invalid(fldPath, "", "at least one of commonName, dnsNames, uris ipAddresses, or emailAddresses must be set"))
+	if len(crt.CommonName) == 0 && len(crt.DNSNames) == 0 && len(crt.URISANs) == 0 && len(crt.EmailSANs) == 0 && len(crt.IPAddresses) == 0 && len(crt.RegisteredIDs) == 0 {
+		el = append(el, field.Required(fldPath, "at least one of commonName, dnsNames, uris ipAddresses, emailAddresses or registeredIDs must be set"))
 	}
 
 	// if a common name has been specified, ensure it is no longer than 64 chars
@@ -49,6 +57,17 @@ func ValidateCertificateSpec(crt *internalcmapi.CertificateSpec, fldPath *field.
 		el = append(el, field.TooLong(fldPath.Child("commonName"), crt.CommonName, 64))
 	}
 
+	// the subject serialNumber attribute is limited to 64 characters (ub-serial-number in RFC 5280)
+	if crt.Subject != nil && len(crt.Subject.SerialNumber) > 64 {
+		el = append(el, field.TooLong(fldPath.Child("subject", "serialNumber"), crt.Subject.SerialNumber, 64))
+	}
+
+	for i, name := range crt.DNSNames {
+		if _, err := pki.DNSNameToASCII(name); err != nil {
+			el = append(el, field.Invalid(fldPath.Child("dnsNames").Index(i), name, "internationalized DNS name cannot be converted to ASCII (punycode) form"))
+		}
+	}
+
 	if len(crt.IPAddresses) > 0 {
 		el = append(el, validateIPAddresses(crt, fldPath)...)
 	}
@@ -57,18 +76,46 @@ func ValidateCertificateSpec(crt *internalcmapi.CertificateSpec, fldPath *field.
 		el = append(el, validateEmailAddresses(crt, fldPath)...)
 	}
 
+	for i, id := range crt.RegisteredIDs {
+		if _, err := pki.ParseObjectIdentifier(id); err != nil {
+			el = append(el, field.Invalid(fldPath.Child("registeredIDs").Index(i), id, err.Error()))
+		}
+	}
+
 	if crt.PrivateKey != nil {
-		switch crt.PrivateKey.Algorithm {
-		case "", internalcmapi.RSAKeyAlgorithm:
-			if crt.PrivateKey.Size > 0 && (crt.PrivateKey.Size < 2048 || crt.PrivateKey.Size > 8192) {
-				el = append(el, field.Invalid(fldPath.Child("privateKey", "size"), crt.PrivateKey.Size, "must be between 2048 & 8192 for rsa keyAlgorithm"))
-			}
-		case internalcmapi.ECDSAKeyAlgorithm:
-			if crt.PrivateKey.Size > 0 && crt.PrivateKey.Size != 256 && crt.PrivateKey.Size != 384 && crt.PrivateKey.Size != 521 {
-				el = append(el, field.NotSupported(fldPath.Child("privateKey", "size"), crt.PrivateKey.Size, []string{"256", "384", "521"}))
-			}
+		if err := validatePrivateKeyParams(crt.PrivateKey, fldPath.Child("privateKey")); err != nil {
+			el = append(el, err)
+		}
+		if crt.PrivateKey.EncryptionPassphraseSecretRef != nil {
+			el = append(el, ValidateSecretKeySelector(crt.PrivateKey.EncryptionPassphraseSecretRef, fldPath.Child("privateKey", "encryptionPassphraseSecretRef"))...)
+		}
+	}
+
+	seenFormats := make(map[internalcmapi.CertificateOutputFormatType]bool)
+	for i, f := range crt.AdditionalOutputFormats {
+		switch f.Type {
+		case internalcmapi.CertificateOutputFormatDER:
 		default:
-			el = append(el, field.Invalid(fldPath.Child("privateKey", "algorithm"), crt.PrivateKey.Algorithm, "must be either empty or one of rsa or ecdsa"))
+			el = append(el, field.NotSupported(fldPath.Child("additionalOutputFormats").Index(i).Child("type"), f.Type, []string{string(internalcmapi.CertificateOutputFormatDER)}))
+		}
+		// every occurrence of a format after the first is reported, so that
+		// all of them can be removed in one go
+		if seenFormats[f.Type] {
+			el = append(el, field.Duplicate(fldPath.Child("additionalOutputFormats").Index(i).Child("type"), f.Type))
+		}
+		seenFormats[f.Type] = true
+	}
+
+	// the JKS password Secret is used to encrypt both the keystore and the
+	// truststore
+	if crt.Keystores != nil && crt.Keystores.JKS != nil && crt.Keystores.JKS.Create {
+		el = append(el, ValidateSecretKeySelector(&crt.Keystores.JKS.PasswordSecretRef, fldPath.Child("keystores", "jks", "passwordSecretRef"))...)
+	}
+
+	if crt.Keystores != nil && crt.Keystores.PKCS12 != nil {
+		pkcs12 := crt.Keystores.PKCS12
+		if pkcs12.IncludeCAChain && !pkcs12.Create {
+			el = append(el, field.Invalid(fldPath.Child("keystores", "pkcs12", "includeCAChain"), pkcs12.IncludeCAChain, "may only be set if create is true"))
 		}
 	}
 
@@ -78,25 +125,400 @@ func ValidateCertificateSpec(crt *internalcmapi.CertificateSpec, fldPath *field.
 	if len(crt.Usages) > 0 {
 		el = append(el, validateUsages(crt, fldPath)...)
 	}
+	if crt.IssuanceTimeout != nil && crt.IssuanceTimeout.Duration < cmapi.MinimumIssuanceTimeout {
+		el = append(el, field.Invalid(fldPath.Child("issuanceTimeout"), crt.IssuanceTimeout.Duration, fmt.Sprintf("certificate issuanceTimeout must be at least %s", cmapi.MinimumIssuanceTimeout)))
+	}
+	el = append(el, validateMaxPathLen(crt, fldPath)...)
+	return el
+}
+
+// validateMaxPathLen checks that a path length constraint is only set on CA
+// certificates, and that maxPathLen and maxPathLenZero do not conflict.
+func validateMaxPathLen(crt *internalcmapi.CertificateSpec, fldPath *field.Path) field.ErrorList {
+	var el field.ErrorList
+	if crt.MaxPathLen < 0 {
+		el = append(el, field.Invalid(fldPath.Child("maxPathLen"), crt.MaxPathLen, "must not be negative"))
+	}
+	if crt.MaxPathLen != 0 && !crt.IsCA {
+		el = append(el, field.Invalid(fldPath.Child("maxPathLen"), crt.MaxPathLen, "may only be set if isCA is true"))
+	}
+	if crt.MaxPathLenZero && !crt.IsCA {
+		el = append(el, field.Invalid(fldPath.Child("maxPathLenZero"), crt.MaxPathLenZero, "may only be set if isCA is true"))
+	}
+	if crt.MaxPathLenZero && crt.MaxPathLen > 0 {
+		el = append(el, field.Invalid(fldPath.Child("maxPathLenZero"), crt.MaxPathLenZero, "must not be set if maxPathLen is greater than 0"))
+	}
 	return el
 }
 
+// validatePrivateKeyParams checks that the algorithm, size, encoding and
+// signature hash of a private key are valid in combination with each other.
+// Only the most specific error is returned: an unknown algorithm is reported
+// before an invalid size, and an invalid size before an unsupported encoding
+// or signature hash.
+func validatePrivateKeyParams(pk *internalcmapi.CertificatePrivateKey, fldPath *field.Path) *field.Error {
+	switch pk.Algorithm {
+	case "", internalcmapi.RSAKeyAlgorithm:
+		if pk.Size > 0 && (pk.Size < 2048 || pk.Size > 8192) {
+			return field.Invalid(fldPath.Child("size"), pk.Size, "must be between 2048 & 8192 for rsa keyAlgorithm")
+		}
+	case internalcmapi.ECDSAKeyAlgorithm:
+		if pk.Size > 0 && pk.Size != 256 && pk.Size != 384 && pk.Size != 521 {
+			return field.NotSupported(fldPath.Child("size"), pk.Size, []string{"256", "384", "521"})
+		}
+		// PKCS#1 only defines an encoding for RSA keys
+		if pk.Encoding == internalcmapi.PKCS1 {
+			return field.Invalid(fldPath.Child("encoding"), pk.Encoding, "PKCS1 encoding is not supported for ecdsa keyAlgorithm, use PKCS8")
+		}
+	case internalcmapi.Ed25519KeyAlgorithm:
+		// Ed25519 keys have a fixed size
+		if pk.Size != 0 {
+			return field.Invalid(fldPath.Child("size"), pk.Size, "must not be set for ed25519 keyAlgorithm")
+		}
+		if pk.Encoding == internalcmapi.PKCS1 {
+			return field.Invalid(fldPath.Child("encoding"), pk.Encoding, "PKCS1 encoding is not supported for ed25519 keyAlgorithm, use PKCS8")
+		}
+		// Ed25519 signs the message itself rather than a digest of it
+		if pk.SignatureHash != "" {
+			return field.Invalid(fldPath.Child("signatureHash"), pk.SignatureHash, "must not be set for ed25519 keyAlgorithm")
+		}
+	default:
+		return field.Invalid(fldPath.Child("algorithm"), pk.Algorithm, "must be either empty or one of rsa, ecdsa or ed25519")
+	}
+
+	switch pk.Encoding {
+	case "", internalcmapi.PKCS1, internalcmapi.PKCS8:
+	default:
+		return field.NotSupported(fldPath.Child("encoding"), pk.Encoding, []string{string(internalcmapi.PKCS1), string(internalcmapi.PKCS8)})
+	}
+
+	switch pk.SignatureHash {
+	case "", internalcmapi.SHA1SignatureHash, internalcmapi.SHA256SignatureHash, internalcmapi.SHA384SignatureHash, internalcmapi.SHA512SignatureHash:
+	default:
+		return field.NotSupported(fldPath.Child("signatureHash"), pk.SignatureHash, []string{
+			string(internalcmapi.SHA1SignatureHash), string(internalcmapi.SHA256SignatureHash),
+			string(internalcmapi.SHA384SignatureHash), string(internalcmapi.SHA512SignatureHash),
+		})
+	}
+
+	return nil
+}
+
 func ValidateCertificate(obj runtime.Object) field.ErrorList {
 	crt := obj.(*internalcmapi.Certificate)
 	allErrs := ValidateCertificateSpec(&crt.Spec, field.NewPath("spec"))
+	allErrs = append(allErrs, validateCertificateAnnotations(crt.Annotations, field.NewPath("metadata", "annotations"))...)
+	allErrs = append(allErrs, validateCAKeyRotation(crt, field.NewPath("spec"))...)
 	return allErrs
 }
 
 func ValidateUpdateCertificate(oldObj, obj runtime.Object) field.ErrorList {
+	oldCrt := oldObj.(*internalcmapi.Certificate)
 	crt := obj.(*internalcmapi.Certificate)
 	allErrs := ValidateCertificateSpec(&crt.Spec, field.NewPath("spec"))
+	allErrs = append(allErrs, validateCertificateAnnotations(crt.Annotations, field.NewPath("metadata", "annotations"))...)
+	// Certificates that already rotate the key of a CA are not rejected, so
+	// that they can still be updated to fix the rotation policy.
+	if !rotatesCAKey(&oldCrt.Spec) {
+		allErrs = append(allErrs, validateCAKeyRotation(crt, field.NewPath("spec"))...)
+	}
 	return allErrs
 }
 
+// validateCAKeyRotation rejects CA Certificates that regenerate their private
+// key on every issuance, unless the Certificate has been annotated to allow
+// it. Rotating the key of an intermediate CA breaks the chain of every
+// certificate it has already issued.
+func validateCAKeyRotation(crt *internalcmapi.Certificate, fldPath *field.Path) field.ErrorList {
+	if !rotatesCAKey(&crt.Spec) || crt.Annotations[cmapi.AllowCAKeyRotationAnnotationKey] == "true" {
+		return nil
+	}
+	return field.ErrorList{
+		field.Invalid(fldPath.Child("privateKey", "rotationPolicy"), crt.Spec.PrivateKey.RotationPolicy,
+			fmt.Sprintf("must not be Always for a certificate with isCA set, as rotating the private key of a CA breaks every certificate it has issued; set the %q annotation to \"true\" to allow it", cmapi.AllowCAKeyRotationAnnotationKey)),
+	}
+}
+
+// rotatesCAKey returns true if the spec is for a CA certificate whose private
+// key is regenerated on every issuance.
+func rotatesCAKey(spec *internalcmapi.CertificateSpec) bool {
+	return spec.IsCA && spec.PrivateKey != nil && spec.PrivateKey.RotationPolicy == internalcmapi.RotationPolicyAlways
+}
+
+func validateCertificateAnnotations(annotations map[string]string, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+	if _, err := util.ACMEChallengeTypePreference(annotations); err != nil {
+		key := cmacme.ACMEChallengeTypePreferenceAnnotationKey
+		el = append(el, field.Invalid(fldPath.Key(key), annotations[key], err.Error()))
+	}
+	return el
+}
+
+// WarnCertificateSpec returns warnings about a Certificate spec that is valid
+// but unusually configured.
+func WarnCertificateSpec(crt *internalcmapi.CertificateSpec, fldPath *field.Path) []string {
+	var warnings []string
+
+	// CA certificates rarely need subject alternative names, so setting them
+	// usually indicates that isCA was set on what should be a leaf certificate
+	if crt.IsCA && (len(crt.DNSNames) > 0 || len(crt.URISANs) > 0 || len(crt.IPAddresses) > 0 || len(crt.EmailSANs) > 0) {
+		warnings = append(warnings, fmt.Sprintf("%s: certificate is a CA but also sets dnsNames, uris, ipAddresses or emailAddresses, which CA certificates rarely need", fldPath.Child("isCA")))
+	}
+
+	// Duplicate subject alternative names are deduplicated by most issuers,
+	// but may still use up an authorization and make diffs confusing. DNS
+	// names are case-insensitive, and IP addresses may be written in more
+	// than one form.
+	warnings = append(warnings, warnDuplicates(crt.DNSNames, strings.ToLower, fldPath.Child("dnsNames"))...)
+	warnings = append(warnings, warnDuplicates(crt.IPAddresses, normalizeIP, fldPath.Child("ipAddresses"))...)
+	warnings = append(warnings, warnDuplicates(crt.URISANs, nil, fldPath.Child("uris"))...)
+	warnings = append(warnings, warnDuplicates(crt.EmailSANs, nil, fldPath.Child("emailAddresses"))...)
+
+	// Most applications expect an unencrypted private key in tls.key
+	if crt.PrivateKey != nil && crt.PrivateKey.EncryptionPassphraseSecretRef != nil {
+		warnings = append(warnings, fmt.Sprintf("%s: the private key will be stored encrypted, and applications consuming the Secret must decrypt it before use", fldPath.Child("privateKey", "encryptionPassphraseSecretRef")))
+	}
+
+	// Generating large RSA keys can take from seconds to minutes, delaying
+	// every issuance of the certificate. RSA is used if no algorithm is set.
+	if crt.PrivateKey != nil && crt.PrivateKey.Size > 4096 {
+		switch crt.PrivateKey.Algorithm {
+		case "", internalcmapi.RSAKeyAlgorithm:
+			warnings = append(warnings, fmt.Sprintf("%s: generating rsa keys larger than 4096 bits can take a long time, delaying issuance and renewal of the certificate", fldPath.Child("privateKey", "size")))
+		}
+	}
+
+	// SHA-1 signatures are no longer considered secure, and are rejected by
+	// many issuers
+	if crt.PrivateKey != nil && crt.PrivateKey.SignatureHash == internalcmapi.SHA1SignatureHash {
+		warnings = append(warnings, fmt.Sprintf("%s: SHA1 is no longer considered secure and should only be used with issuers that require it", fldPath.Child("privateKey", "signatureHash")))
+	}
+
+	// Large keystores and output formats are all stored in the one Secret,
+	// which is rejected by the apiserver if it grows too large
+	if size := estimateSecretSize(crt); size > secretSizeWarningThreshold {
+		warnings = append(warnings, fmt.Sprintf("%s: the Secret is estimated to be around %d bytes, which is close to or above the %d byte limit on Secret size; consider reducing the number of subject alternative names, the key size or the number of output formats", fldPath.Child("secretName"), size, corev1.MaxSecretSize))
+	}
+
+	return warnings
+}
+
+// warnDuplicates returns a warning for each of the given values that
+// duplicates an earlier value. If normalize is set, values are compared after
+// being normalized.
+func warnDuplicates(values []string, normalize func(string) string, fldPath *field.Path) []string {
+	var warnings []string
+	seen := make(map[string]int, len(values))
+	for i, value := range values {
+		key := value
+		if normalize != nil {
+			key = normalize(value)
+		}
+		if j, ok := seen[key]; ok {
+			warnings = append(warnings, fmt.Sprintf("%s: %q is a duplicate of %s", fldPath.Index(i), value, fldPath.Index(j)))
+			continue
+		}
+		seen[key] = i
+	}
+	return warnings
+}
+
+// normalizeIP returns the canonical form of the given IP address, or the
+// address unchanged if it cannot be parsed.
+func normalizeIP(ip string) string {
+	if parsed := net.ParseIP(ip); parsed != nil {
+		return parsed.String()
+	}
+	return ip
+}
+
+const (
+	// secretSizeWarningThreshold is the estimated Secret size above which a
+	// warning is returned for a Certificate.
+	secretSizeWarningThreshold = corev1.MaxSecretSize * 8 / 10
+
+	// estimatedCertificateOverhead is a generous estimate of the size of a
+	// DER encoded certificate, excluding its public key and subject
+	// alternative names.
+	estimatedCertificateOverhead = 1024
+
+	// estimatedCAChainSize is an estimate of the size of the DER encoded CA
+	// certificates returned alongside the leaf certificate.
+	estimatedCAChainSize = 2048
+
+	// estimatedKeystoreOverhead is an estimate of the fixed overhead of a JKS
+	// or PKCS#12 keystore.
+	estimatedKeystoreOverhead = 512
+)
+
+// estimateSecretSize returns a best-effort estimate of the size in bytes of
+// the Secret that will be written for the given Certificate, based on the key
+// size, the subject alternative names and the enabled output formats.
+func estimateSecretSize(crt *internalcmapi.CertificateSpec) int {
+	keySize, publicKeySize := estimatePrivateKeySize(crt.PrivateKey)
+
+	certSize := estimatedCertificateOverhead + publicKeySize + len(crt.CommonName)
+	for _, name := range crt.DNSNames {
+		certSize += len(name) + 4
+	}
+	for _, uri := range crt.URISANs {
+		certSize += len(uri) + 4
+	}
+	for _, email := range crt.EmailSANs {
+		certSize += len(email) + 4
+	}
+	certSize += len(crt.IPAddresses) * 20
+
+	// tls.crt, tls.key and ca.crt are PEM encoded, which grows them by a
+	// third
+	size := pemSize(certSize+estimatedCAChainSize) + pemSize(keySize) + pemSize(estimatedCAChainSize)
+
+	// each keystore holds the private key, the certificate and the CA chain,
+	// alongside a copy of the CA chain as a truststore
+	keystoreSize := estimatedKeystoreOverhead + keySize + certSize + 2*estimatedCAChainSize
+	if crt.Keystores != nil {
+		if crt.Keystores.JKS != nil && crt.Keystores.JKS.Create {
+			size += 2 * keystoreSize
+		}
+		if crt.Keystores.PKCS12 != nil && crt.Keystores.PKCS12.Create {
+			size += 2 * keystoreSize
+		}
+	}
+
+	for _, f := range crt.AdditionalOutputFormats {
+		if f.Type == internalcmapi.CertificateOutputFormatDER {
+			size += certSize
+		}
+	}
+
+	return size
+}
+
+// estimatePrivateKeySize returns an estimate of the size of the DER encoded
+// private key and public key for the given private key options.
+func estimatePrivateKeySize(pk *internalcmapi.CertificatePrivateKey) (keySize, publicKeySize int) {
+	algorithm, size := internalcmapi.RSAKeyAlgorithm, 0
+	if pk != nil {
+		if pk.Algorithm != "" {
+			algorithm = pk.Algorithm
+		}
+		size = pk.Size
+	}
+
+	if algorithm == internalcmapi.Ed25519KeyAlgorithm {
+		// the PKCS#8 wrapped seed and the raw public key
+		return 48, 44
+	}
+
+	if algorithm == internalcmapi.ECDSAKeyAlgorithm {
+		if size == 0 {
+			size = 256
+		}
+		// the private scalar and the uncompressed public point
+		return 3*size/8 + 64, 2*size/8 + 32
+	}
+
+	if size == 0 {
+		size = 2048
+	}
+	// an RSA private key holds the modulus and roughly 4 more values of the
+	// same size
+	return 5*size/8 + 64, size/8 + 32
+}
+
+func pemSize(derSize int) int {
+	return derSize*4/3 + 64
+}
+
+func WarnCertificate(obj runtime.Object) []string {
+	crt := obj.(*internalcmapi.Certificate)
+	return WarnCertificateSpec(&crt.Spec, field.NewPath("spec"))
+}
+
+func WarnUpdateCertificate(oldObj, obj runtime.Object) []string {
+	oldCrt := oldObj.(*internalcmapi.Certificate)
+	crt := obj.(*internalcmapi.Certificate)
+	warnings := WarnCertificateSpec(&crt.Spec, field.NewPath("spec"))
+	warnings = append(warnings, warnKeystoresUpdate(oldCrt.Spec.Keystores, crt.Spec.Keystores, field.NewPath("spec", "keystores"))...)
+	return warnings
+}
+
+// warnKeystoresUpdate returns warnings for keystores whose password Secret
+// reference has changed, as keystores will be encrypted with the new password
+// and consumers must be updated to use it.
+func warnKeystoresUpdate(oldKeystores, keystores *internalcmapi.CertificateKeystores, fldPath *field.Path) []string {
+	if oldKeystores == nil || keystores == nil {
+		return nil
+	}
+
+	var warnings []string
+	if oldKeystores.JKS != nil && keystores.JKS != nil && oldKeystores.JKS.PasswordSecretRef != keystores.JKS.PasswordSecretRef {
+		warnings = append(warnings, fmt.Sprintf("%s: changing the password Secret means the JKS keystore will be encrypted with a new password, consumers must re-import it", fldPath.Child("jks", "passwordSecretRef")))
+	}
+	if oldKeystores.PKCS12 != nil && keystores.PKCS12 != nil && oldKeystores.PKCS12.PasswordSecretRef != keystores.PKCS12.PasswordSecretRef {
+		warnings = append(warnings, fmt.Sprintf("%s: changing the password Secret means the PKCS12 keystore will be encrypted with a new password, consumers must re-import it", fldPath.Child("pkcs12", "passwordSecretRef")))
+	}
+
+	return warnings
+}
+
 func validateIssuerRef(issuerRef cmmeta.ObjectReference, fldPath *field.Path) field.ErrorList {
+	return validateIssuerReference(issuerRef, fldPath.Child("issuerRef"))
+}
+
+// validateFallbackIssuerRef validates that the fallbackIssuerRef, if set, is a
+// valid issuer reference that refers to a different issuer than issuerRef.
+func validateFallbackIssuerRef(issuerRef cmmeta.ObjectReference, fallbackIssuerRef *cmmeta.ObjectReference, fldPath *field.Path) field.ErrorList {
+	if fallbackIssuerRef == nil {
+		return nil
+	}
+
+	fallbackIssuerRefPath := fldPath.Child("fallbackIssuerRef")
+	el := validateIssuerReference(*fallbackIssuerRef, fallbackIssuerRefPath)
+	if issuerRefsEqual(issuerRef, *fallbackIssuerRef) {
+		el = append(el, field.Invalid(fallbackIssuerRefPath, *fallbackIssuerRef, "must not refer to the same issuer as issuerRef"))
+	}
+	return el
+}
+
+// validateTemplateRef checks that the templateRef, if set, names a ConfigMap.
+func validateTemplateRef(templateRef *cmmeta.LocalObjectReference, fldPath *field.Path) field.ErrorList {
+	if templateRef == nil {
+		return nil
+	}
+
+	namePath := fldPath.Child("templateRef", "name")
+	if templateRef.Name == "" {
+		return field.ErrorList{field.Required(namePath, "must be specified")}
+	}
+	el := field.ErrorList{}
+	for _, msg := range validation.IsDNS1123Subdomain(templateRef.Name) {
+		el = append(el, field.Invalid(namePath, templateRef.Name, msg))
+	}
+	return el
+}
+
+// issuerRefsEqual returns true if the two references refer to the same
+// issuer, taking into account the defaulting of the kind and group fields.
+func issuerRefsEqual(a, b cmmeta.ObjectReference) bool {
+	kind := func(ref cmmeta.ObjectReference) string {
+		if ref.Kind == "" {
+			return internalcmapi.IssuerKind
+		}
+		return ref.Kind
+	}
+	group := func(ref cmmeta.ObjectReference) string {
+		if ref.Group == "" {
+			return internalcmapi.SchemeGroupVersion.Group
+		}
+		return ref.Group
+	}
+	return a.Name == b.Name && kind(a) == kind(b) && group(a) == group(b)
+}
+
+func validateIssuerReference(issuerRef cmmeta.ObjectReference, issuerRefPath *field.Path) field.ErrorList {
 	el := field.ErrorList{}
 
-	issuerRefPath := fldPath.Child("issuerRef")
 	if issuerRef.Name == "" {
 		el = append(el, field.Required(issuerRefPath.Child("name"), "must be specified"))
 	}
@@ -138,11 +560,25 @@ func validateEmailAddresses(a *internalcmapi.CertificateSpec, fldPath *field.Pat
 			// Go accepts email names as per RFC 5322 (name <email>)
 			// This checks if the supplied value only contains the email address and nothing else
 			el = append(el, field.Invalid(fldPath.Child("emailAddresses").Index(i), d, "invalid email address: make sure the supplied value only contains the email address itself"))
+		} else if !isASCII(d) {
+			// Email addresses are encoded as rfc822Name SANs, which only
+			// support ASCII. Internationalized mailboxes need the
+			// SmtpUTF8Mailbox otherName of RFC 8398, which is not supported.
+			el = append(el, field.Invalid(fldPath.Child("emailAddresses").Index(i), d, "invalid email address: must only contain ASCII characters to be encoded as an rfc822Name"))
 		}
 	}
 	return el
 }
 
+func isASCII(s string) bool {
+	for _, r := range s {
+		if r > unicode.MaxASCII {
+			return false
+		}
+	}
+	return true
+}
+
 func validateUsages(a *internalcmapi.CertificateSpec, fldPath *field.Path) field.ErrorList {
 	el := field.ErrorList{}
 	for i, u := range a.Usages {
@@ -166,6 +602,9 @@ func ValidateDuration(crt *internalcmapi.CertificateSpec, fldPath *field.Path) f
 	if duration < cmapi.MinimumCertificateDuration {
 		el = append(el, field.Invalid(fldPath.Child("duration"), duration, fmt.Sprintf("certificate duration must be greater than %s", cmapi.MinimumCertificateDuration)))
 	}
+	if duration > cmapi.MaximumCertificateDuration {
+		el = append(el, field.Invalid(fldPath.Child("duration"), duration, fmt.Sprintf("certificate duration must not be greater than %s", cmapi.MaximumCertificateDuration)))
+	}
 	if renewBefore < cmapi.MinimumRenewBefore {
 		el = append(el, field.Invalid(fldPath.Child("renewBefore"), renewBefore, fmt.Sprintf("certificate renewBefore must be greater than %s", cmapi.MinimumRenewBefore)))
 	}
@@ -29,6 +29,8 @@ func ValidateCertificateForIssuer(crt *cmapi.Certificate, issuerObj cmapi.Generi
 
 	path := field.NewPath("spec")
 
+	el = append(el, ValidateCertificateCommonNameForIssuer(&crt.Spec, issuerObj, path)...)
+
 	switch {
 	case issuerObj.GetSpec().ACME != nil:
 		el = append(el, ValidateCertificateForACMEIssuer(&crt.Spec, issuerObj.GetSpec(), path)...)
@@ -37,6 +39,7 @@ func ValidateCertificateForIssuer(crt *cmapi.Certificate, issuerObj cmapi.Generi
 		el = append(el, ValidateCertificateForVaultIssuer(&crt.Spec, issuerObj.GetSpec(), path)...)
 	case issuerObj.GetSpec().SelfSigned != nil:
 	case issuerObj.GetSpec().Venafi != nil:
+	case issuerObj.GetSpec().AWSPCA != nil:
 	default:
 		el = append(el, field.Invalid(path, "", fmt.Sprintf("no issuer specified for Issuer '%s/%s'", issuerObj.GetObjectMeta().Namespace, issuerObj.GetObjectMeta().Name)))
 	}
@@ -44,6 +47,18 @@ func ValidateCertificateForIssuer(crt *cmapi.Certificate, issuerObj cmapi.Generi
 	return el
 }
 
+// ValidateCertificateCommonNameForIssuer returns an error if the issuer
+// requires a common name to be set and the Certificate does not set one.
+func ValidateCertificateCommonNameForIssuer(crt *cmapi.CertificateSpec, issuerObj cmapi.GenericIssuer, specPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+
+	if issuerObj.GetObjectMeta().Annotations[cmapi.RequireCommonNameAnnotationKey] == "true" && len(crt.CommonName) == 0 {
+		el = append(el, field.Required(specPath.Child("commonName"), fmt.Sprintf("issuer '%s' requires a commonName to be set", issuerObj.GetObjectMeta().Name)))
+	}
+
+	return el
+}
+
 func ValidateCertificateForACMEIssuer(crt *cmapi.CertificateSpec, issuer *cmapi.IssuerSpec, specPath *field.Path) field.ErrorList {
 	el := field.ErrorList{}
 
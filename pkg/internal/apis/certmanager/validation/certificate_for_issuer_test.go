@@ -131,6 +131,31 @@ func TestValidateCertificateForIssuer(t *testing.T) {
 			issuer: acmeIssuer,
 			errs:   []*field.Error{},
 		},
+		"san only certificate for issuer requiring a common name": {
+			crt: &cmapi.Certificate{
+				Spec: cmapi.CertificateSpec{
+					DNSNames:  []string{"example.com"},
+					IssuerRef: validIssuerRef,
+				},
+			},
+			issuer: &cmapi.Issuer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      defaultTestIssuerName,
+					Namespace: defaultTestNamespace,
+					Annotations: map[string]string{
+						cmapi.RequireCommonNameAnnotationKey: "true",
+					},
+				},
+				Spec: cmapi.IssuerSpec{
+					IssuerConfig: cmapi.IssuerConfig{
+						CA: &cmapi.CAIssuer{},
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.Required(fldPath.Child("commonName"), "issuer 'test-issuer' requires a commonName to be set"),
+			},
+		},
 		"certificate with unspecified issuer type": {
 			crt: &cmapi.Certificate{
 				Spec: cmapi.CertificateSpec{
@@ -0,0 +1,104 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"regexp"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/jetstack/cert-manager/pkg/internal/api/validation"
+	internalcmapi "github.com/jetstack/cert-manager/pkg/internal/apis/certmanager"
+)
+
+// CertificatePolicy holds the requirements that the webhook has been
+// configured to enforce on every Certificate, in addition to those checked by
+// ValidateCertificate. The zero value enforces no additional requirements.
+type CertificatePolicy struct {
+	// SecretNamePattern must match the spec.secretName of every Certificate.
+	// If nil, any secretName is allowed.
+	SecretNamePattern *regexp.Regexp
+
+	// MinRSAKeySize and MinECDSAKeySize are the minimum sizes of the RSA and
+	// ECDSA private keys of every Certificate. Keys that do not specify a
+	// size are checked against their default size. A minimum of 0 disables
+	// the check for that algorithm.
+	MinRSAKeySize   int
+	MinECDSAKeySize int
+
+	// MaxSubjectAltNames is the maximum total number of subject alternative
+	// names a Certificate may request. If 0, DefaultACMEMaxSubjectAltNames
+	// is enforced for Certificates whose issuer resolves to an ACME issuer.
+	MaxSubjectAltNames int
+
+	// IssuerResolver resolves the issuer referenced by a Certificate, so that
+	// the Certificate can be checked against the requirements of its issuer.
+	// If nil, or if the issuer cannot be resolved, these checks are skipped.
+	IssuerResolver IssuerResolver
+}
+
+// AddToValidationRegistry registers the Certificate checks of the policy
+// with the given registry.
+func (p *CertificatePolicy) AddToValidationRegistry(reg *validation.Registry) error {
+	if err := reg.AddValidateFunc(&internalcmapi.Certificate{}, p.ValidateCertificate); err != nil {
+		return err
+	}
+	if err := reg.AddValidateUpdateFunc(&internalcmapi.Certificate{}, p.ValidateUpdateCertificate); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (p *CertificatePolicy) ValidateCertificate(obj runtime.Object) field.ErrorList {
+	crt := obj.(*internalcmapi.Certificate)
+	allErrs := validateSecretNamePolicy(p.SecretNamePattern, &crt.Spec, field.NewPath("spec"))
+	allErrs = append(allErrs, validateKeySizePolicy(p.MinRSAKeySize, p.MinECDSAKeySize, &crt.Spec, field.NewPath("spec"))...)
+	allErrs = append(allErrs, p.validateForResolvedIssuer(crt)...)
+	return allErrs
+}
+
+func (p *CertificatePolicy) ValidateUpdateCertificate(oldObj, obj runtime.Object) field.ErrorList {
+	oldCrt := oldObj.(*internalcmapi.Certificate)
+	crt := obj.(*internalcmapi.Certificate)
+	allErrs := p.validateForResolvedIssuer(crt)
+	// Certificates created before the secret name policy was configured are
+	// only rejected if their secretName is changed.
+	if oldCrt.Spec.SecretName != crt.Spec.SecretName {
+		allErrs = append(allErrs, validateSecretNamePolicy(p.SecretNamePattern, &crt.Spec, field.NewPath("spec"))...)
+	}
+	// Likewise, Certificates created before a minimum key size was configured
+	// are only rejected if their private key algorithm or size is changed.
+	oldAlgorithm, oldSize := privateKeyAlgorithmAndSize(oldCrt.Spec.PrivateKey)
+	algorithm, size := privateKeyAlgorithmAndSize(crt.Spec.PrivateKey)
+	if oldAlgorithm != algorithm || oldSize != size {
+		allErrs = append(allErrs, validateKeySizePolicy(p.MinRSAKeySize, p.MinECDSAKeySize, &crt.Spec, field.NewPath("spec"))...)
+	}
+	return allErrs
+}
+
+// validateForResolvedIssuer validates the Certificate against the
+// requirements of the issuer it references. If the issuer cannot be resolved
+// the issuer specific checks are skipped.
+func (p *CertificatePolicy) validateForResolvedIssuer(crt *internalcmapi.Certificate) field.ErrorList {
+	issuerObj := p.resolveIssuer(crt.Namespace, crt.Spec.IssuerRef)
+	el := validateSubjectAltNameCount(p.MaxSubjectAltNames, &crt.Spec, issuerObj, field.NewPath("spec"))
+	if issuerObj == nil {
+		return el
+	}
+	return append(el, ValidateCertificateCommonNameForIssuer(&crt.Spec, issuerObj, field.NewPath("spec"))...)
+}
@@ -17,15 +17,21 @@ limitations under the License.
 package validation
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"regexp"
 	"testing"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	internalcmacme "github.com/jetstack/cert-manager/pkg/internal/apis/acme"
 	internalcmapi "github.com/jetstack/cert-manager/pkg/internal/apis/certmanager"
 	cmmeta "github.com/jetstack/cert-manager/pkg/internal/apis/meta"
 )
@@ -125,7 +131,7 @@ func TestValidateCertificate(t *testing.T) {
 				},
 			},
 			errs: []*field.Error{
-				field.Invalid(fldPath, "", "at least one of commonName, dnsNames, uris ipAddresses, or emailAddresses must be set"),
+				field.Required(fldPath, "at least one of commonName, dnsNames, uris ipAddresses, emailAddresses or registeredIDs must be set"),
 			},
 		},
 		"certificate with no issuerRef": {
@@ -148,6 +154,56 @@ func TestValidateCertificate(t *testing.T) {
 				},
 			},
 		},
+		"valid certificate with only uris": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					URISANs:    []string{"spiffe://cluster.local/ns/sandbox/sa/foo"},
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+				},
+			},
+		},
+		"valid certificate with only ipAddresses": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					IPAddresses: []string{"127.0.0.1"},
+					SecretName:  "abc",
+					IssuerRef:   validIssuerRef,
+				},
+			},
+		},
+		"valid certificate with only emailAddresses": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					EmailSANs:  []string{"alice@example.com"},
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+				},
+			},
+		},
+		"valid certificate with only registeredIDs": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					RegisteredIDs: []string{"1.2.3.4", "2.999.1"},
+					SecretName:    "abc",
+					IssuerRef:     validIssuerRef,
+				},
+			},
+		},
+		"certificate with invalid registeredIDs": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName:    "testcn",
+					RegisteredIDs: []string{"1.2.3.4", "1.2.a", "3.1"},
+					SecretName:    "abc",
+					IssuerRef:     validIssuerRef,
+				},
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("registeredIDs").Index(1), "1.2.a", `invalid object identifier "1.2.a": arc "a" is not a non-negative integer`),
+				field.Invalid(fldPath.Child("registeredIDs").Index(2), "3.1", `invalid object identifier "3.1": asn1: structure error: invalid object identifier`),
+			},
+		},
 		"valid certificate with rsa keyAlgorithm specified and no keySize": {
 			cfg: &internalcmapi.Certificate{
 				Spec: internalcmapi.CertificateSpec{
@@ -278,6 +334,148 @@ func TestValidateCertificate(t *testing.T) {
 				field.Invalid(fldPath.Child("privateKey", "size"), 1024, "must be between 2048 & 8192 for rsa keyAlgorithm"),
 			},
 		},
+		"certificate with a DER additional output format": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "testcn",
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+					AdditionalOutputFormats: []internalcmapi.CertificateAdditionalOutputFormat{
+						{Type: internalcmapi.CertificateOutputFormatDER},
+					},
+				},
+			},
+		},
+		"certificate with an unknown additional output format": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "testcn",
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+					AdditionalOutputFormats: []internalcmapi.CertificateAdditionalOutputFormat{
+						{Type: internalcmapi.CertificateOutputFormatDER},
+						{Type: "PEM"},
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.NotSupported(fldPath.Child("additionalOutputFormats").Index(1).Child("type"), internalcmapi.CertificateOutputFormatType("PEM"), []string{"DER"}),
+			},
+		},
+		"certificate with an additional output format repeated three times": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "testcn",
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+					AdditionalOutputFormats: []internalcmapi.CertificateAdditionalOutputFormat{
+						{Type: internalcmapi.CertificateOutputFormatDER},
+						{Type: internalcmapi.CertificateOutputFormatDER},
+						{Type: internalcmapi.CertificateOutputFormatDER},
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.Duplicate(fldPath.Child("additionalOutputFormats").Index(1).Child("type"), internalcmapi.CertificateOutputFormatDER),
+				field.Duplicate(fldPath.Child("additionalOutputFormats").Index(2).Child("type"), internalcmapi.CertificateOutputFormatDER),
+			},
+		},
+		"certificate with a PKCS12 keystore including the CA chain": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "testcn",
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+					Keystores: &internalcmapi.CertificateKeystores{
+						PKCS12: &internalcmapi.PKCS12Keystore{Create: true, IncludeCAChain: true},
+					},
+				},
+			},
+		},
+		"certificate with a JKS keystore and truststore": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "testcn",
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+					Keystores: &internalcmapi.CertificateKeystores{
+						JKS: &internalcmapi.JKSKeystore{
+							Create: true,
+							PasswordSecretRef: cmmeta.SecretKeySelector{
+								LocalObjectReference: cmmeta.LocalObjectReference{Name: "jks-password"},
+								Key:                  "password",
+							},
+						},
+					},
+				},
+			},
+		},
+		"certificate with a JKS keystore missing the password Secret key": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "testcn",
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+					Keystores: &internalcmapi.CertificateKeystores{
+						JKS: &internalcmapi.JKSKeystore{
+							Create: true,
+							PasswordSecretRef: cmmeta.SecretKeySelector{
+								LocalObjectReference: cmmeta.LocalObjectReference{Name: "jks-password"},
+							},
+						},
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.Required(fldPath.Child("keystores", "jks", "passwordSecretRef", "key"), "secret key is required"),
+			},
+		},
+		"certificate with includeCAChain set on a PKCS12 keystore that is not created": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "testcn",
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+					Keystores: &internalcmapi.CertificateKeystores{
+						PKCS12: &internalcmapi.PKCS12Keystore{IncludeCAChain: true},
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("keystores", "pkcs12", "includeCAChain"), true, "may only be set if create is true"),
+			},
+		},
+		"certificate with a complete encryptionPassphraseSecretRef": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "testcn",
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+					PrivateKey: &internalcmapi.CertificatePrivateKey{
+						EncryptionPassphraseSecretRef: &cmmeta.SecretKeySelector{
+							LocalObjectReference: cmmeta.LocalObjectReference{Name: "passphrase"},
+							Key:                  "key",
+						},
+					},
+				},
+			},
+		},
+		"certificate with an encryptionPassphraseSecretRef missing name and key": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "testcn",
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+					PrivateKey: &internalcmapi.CertificatePrivateKey{
+						EncryptionPassphraseSecretRef: &cmmeta.SecretKeySelector{},
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.Required(fldPath.Child("privateKey", "encryptionPassphraseSecretRef", "name"), "secret name is required"),
+				field.Required(fldPath.Child("privateKey", "encryptionPassphraseSecretRef", "key"), "secret key is required"),
+			},
+		},
 		"certificate with rsa keyAlgorithm specified and invalid keysize 8196": {
 			cfg: &internalcmapi.Certificate{
 				Spec: internalcmapi.CertificateSpec{
@@ -322,7 +520,7 @@ func TestValidateCertificate(t *testing.T) {
 				},
 			},
 			errs: []*field.Error{
-				field.Invalid(fldPath.Child("privateKey", "algorithm"), internalcmapi.PrivateKeyAlgorithm("blah"), "must be either empty or one of rsa or ecdsa"),
+				field.Invalid(fldPath.Child("privateKey", "algorithm"), internalcmapi.PrivateKeyAlgorithm("blah"), "must be either empty or one of rsa, ecdsa or ed25519"),
 			},
 		},
 		"valid certificate with ipAddresses": {
@@ -348,6 +546,108 @@ func TestValidateCertificate(t *testing.T) {
 				field.Invalid(fldPath.Child("ipAddresses").Index(0), "blah", "invalid IP address"),
 			},
 		},
+		"valid certificate with a fallbackIssuerRef": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "testcn",
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+					FallbackIssuerRef: &cmmeta.ObjectReference{
+						Name: "fallback",
+					},
+				},
+			},
+		},
+		"certificate with an invalid fallbackIssuerRef": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "testcn",
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+					FallbackIssuerRef: &cmmeta.ObjectReference{
+						Kind: "Secret",
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.Required(fldPath.Child("fallbackIssuerRef", "name"), "must be specified"),
+				field.Invalid(fldPath.Child("fallbackIssuerRef", "kind"), "Secret", "must be one of Issuer or ClusterIssuer"),
+			},
+		},
+		"certificate with a fallbackIssuerRef referring to the same issuer as issuerRef": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "testcn",
+					SecretName: "abc",
+					IssuerRef:  cmmeta.ObjectReference{Name: "primary"},
+					FallbackIssuerRef: &cmmeta.ObjectReference{
+						Name:  "primary",
+						Kind:  "Issuer",
+						Group: "cert-manager.io",
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("fallbackIssuerRef"), cmmeta.ObjectReference{Name: "primary", Kind: "Issuer", Group: "cert-manager.io"}, "must not refer to the same issuer as issuerRef"),
+			},
+		},
+		"valid certificate with a templateRef": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName:  "testcn",
+					SecretName:  "abc",
+					IssuerRef:   validIssuerRef,
+					TemplateRef: &cmmeta.LocalObjectReference{Name: "common-template"},
+				},
+			},
+		},
+		"certificate with a templateRef without a name": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName:  "testcn",
+					SecretName:  "abc",
+					IssuerRef:   validIssuerRef,
+					TemplateRef: &cmmeta.LocalObjectReference{},
+				},
+			},
+			errs: []*field.Error{
+				field.Required(fldPath.Child("templateRef", "name"), "must be specified"),
+			},
+		},
+		"certificate with a templateRef with an invalid name": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName:  "testcn",
+					SecretName:  "abc",
+					IssuerRef:   validIssuerRef,
+					TemplateRef: &cmmeta.LocalObjectReference{Name: "Common_Template"},
+				},
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("templateRef", "name"), "Common_Template", validation.IsDNS1123Subdomain("Common_Template")[0]),
+			},
+		},
+		"valid certificate with a Unicode dnsName": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					DNSNames:   []string{"例え.jp", "*.例え.jp"},
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+				},
+			},
+		},
+		"certificate with a Unicode dnsName that cannot be converted to ASCII": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					DNSNames:   []string{"example.com", "例え_.jp"},
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+				},
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("dnsNames").Index(1), "例え_.jp", "internationalized DNS name cannot be converted to ASCII (punycode) form"),
+			},
+		},
 		"valid certificate with commonName exactly 64 bytes": {
 			cfg: &internalcmapi.Certificate{
 				Spec: internalcmapi.CertificateSpec{
@@ -370,6 +670,29 @@ func TestValidateCertificate(t *testing.T) {
 				field.TooLong(fldPath.Child("commonName"), "this-is-a-big-long-string-which-has-exactly-sixty-five-characters", 64),
 			},
 		},
+		"valid certificate with subject serialNumber": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "device",
+					Subject:    &internalcmapi.X509Subject{SerialNumber: "DEVICE-0123456789"},
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+				},
+			},
+		},
+		"invalid certificate with subject serialNumber longer than 64 bytes": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "device",
+					Subject:    &internalcmapi.X509Subject{SerialNumber: "this-is-a-big-long-string-which-has-exactly-sixty-five-characters"},
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+				},
+			},
+			errs: []*field.Error{
+				field.TooLong(fldPath.Child("subject", "serialNumber"), "this-is-a-big-long-string-which-has-exactly-sixty-five-characters", 64),
+			},
+		},
 		"valid certificate with no commonName and second dnsName longer than 64 bytes": {
 			cfg: &internalcmapi.Certificate{
 				Spec: internalcmapi.CertificateSpec{
@@ -484,50 +807,284 @@ func TestValidateCertificate(t *testing.T) {
 				field.Invalid(fldPath.Child("emailAddresses").Index(0), "mailto:alice@example.com", "invalid email address: mail: expected comma"),
 			},
 		},
-	}
-	for n, s := range scenarios {
-		t.Run(n, func(t *testing.T) {
-			errs := ValidateCertificate(s.cfg)
-			if len(errs) != len(s.errs) {
-				t.Errorf("Expected %v but got %v", s.errs, errs)
-				return
-			}
-			for i, e := range errs {
-				expectedErr := s.errs[i]
-				if !reflect.DeepEqual(e, expectedErr) {
-					t.Errorf("Expected %v but got %v", expectedErr, e)
-				}
-			}
-		})
-	}
-}
-
-func TestValidateDuration(t *testing.T) {
-	usefulDurations := map[string]*metav1.Duration{
-		"one second":  {Duration: time.Second},
-		"ten minutes": {Duration: time.Minute * 10},
-		"half hour":   {Duration: time.Minute * 30},
-		"one hour":    {Duration: time.Hour},
-		"one month":   {Duration: time.Hour * 24 * 30},
-		"half year":   {Duration: time.Hour * 24 * 180},
-		"one year":    {Duration: time.Hour * 24 * 365},
-		"ten years":   {Duration: time.Hour * 24 * 365 * 10},
-	}
-
-	fldPath := field.NewPath("spec")
-	scenarios := map[string]struct {
-		cfg  *internalcmapi.Certificate
-		errs []*field.Error
-	}{
-		"default duration and renewBefore": {
+		"invalid certificate with internationalized email": {
 			cfg: &internalcmapi.Certificate{
 				Spec: internalcmapi.CertificateSpec{
-					CommonName: "testcn",
+					EmailSANs:  []string{"alice@example.com", "álice@example.com"},
 					SecretName: "abc",
 					IssuerRef:  validIssuerRef,
 				},
 			},
-		},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("emailAddresses").Index(1), "álice@example.com", "invalid email address: must only contain ASCII characters to be encoded as an rfc822Name"),
+			},
+		},
+		"valid with ACME challenge type preference annotation": {
+			cfg: &internalcmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						cmacme.ACMEChallengeTypePreferenceAnnotationKey: "dns-01,http-01",
+					},
+				},
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "testcn",
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+				},
+			},
+		},
+		"invalid ACME challenge type preference annotation": {
+			cfg: &internalcmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						cmacme.ACMEChallengeTypePreferenceAnnotationKey: "dns-01,tls-alpn-01",
+					},
+				},
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "testcn",
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+				},
+			},
+			errs: []*field.Error{
+				field.Invalid(field.NewPath("metadata", "annotations").Key(cmacme.ACMEChallengeTypePreferenceAnnotationKey), "dns-01,tls-alpn-01", `unsupported challenge type "tls-alpn-01", must be one of "http-01" or "dns-01"`),
+			},
+		},
+		"invalid CA certificate with Always rotation policy": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "testcn",
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+					IsCA:       true,
+					PrivateKey: &internalcmapi.CertificatePrivateKey{
+						RotationPolicy: internalcmapi.RotationPolicyAlways,
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("privateKey", "rotationPolicy"), internalcmapi.RotationPolicyAlways, `must not be Always for a certificate with isCA set, as rotating the private key of a CA breaks every certificate it has issued; set the "cert-manager.io/allow-ca-key-rotation" annotation to "true" to allow it`),
+			},
+		},
+		"valid CA certificate with Always rotation policy and the allow annotation": {
+			cfg: &internalcmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						cmapi.AllowCAKeyRotationAnnotationKey: "true",
+					},
+				},
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "testcn",
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+					IsCA:       true,
+					PrivateKey: &internalcmapi.CertificatePrivateKey{
+						RotationPolicy: internalcmapi.RotationPolicyAlways,
+					},
+				},
+			},
+		},
+		"valid CA certificate with Never rotation policy": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "testcn",
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+					IsCA:       true,
+					PrivateKey: &internalcmapi.CertificatePrivateKey{
+						RotationPolicy: internalcmapi.RotationPolicyNever,
+					},
+				},
+			},
+		},
+		"valid non-CA certificate with Always rotation policy": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "testcn",
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+					PrivateKey: &internalcmapi.CertificatePrivateKey{
+						RotationPolicy: internalcmapi.RotationPolicyAlways,
+					},
+				},
+			},
+		},
+	}
+	for n, s := range scenarios {
+		t.Run(n, func(t *testing.T) {
+			errs := ValidateCertificate(s.cfg)
+			if len(errs) != len(s.errs) {
+				t.Errorf("Expected %v but got %v", s.errs, errs)
+				return
+			}
+			for i, e := range errs {
+				expectedErr := s.errs[i]
+				if !reflect.DeepEqual(e, expectedErr) {
+					t.Errorf("Expected %v but got %v", expectedErr, e)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateUpdateCertificateCAKeyRotation(t *testing.T) {
+	caCrt := func(policy internalcmapi.PrivateKeyRotationPolicy) *internalcmapi.Certificate {
+		return &internalcmapi.Certificate{
+			Spec: internalcmapi.CertificateSpec{
+				CommonName: "testcn",
+				SecretName: "abc",
+				IssuerRef:  validIssuerRef,
+				IsCA:       true,
+				PrivateKey: &internalcmapi.CertificatePrivateKey{
+					RotationPolicy: policy,
+				},
+			},
+		}
+	}
+
+	scenarios := map[string]struct {
+		old, new *internalcmapi.Certificate
+		errs     field.ErrorList
+	}{
+		"changing the rotation policy of a CA certificate to Always is rejected": {
+			old: caCrt(internalcmapi.RotationPolicyNever),
+			new: caCrt(internalcmapi.RotationPolicyAlways),
+			errs: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "privateKey", "rotationPolicy"), internalcmapi.RotationPolicyAlways, `must not be Always for a certificate with isCA set, as rotating the private key of a CA breaks every certificate it has issued; set the "cert-manager.io/allow-ca-key-rotation" annotation to "true" to allow it`),
+			},
+		},
+		"CA certificates that already rotate their key can still be updated": {
+			old: caCrt(internalcmapi.RotationPolicyAlways),
+			new: caCrt(internalcmapi.RotationPolicyAlways),
+		},
+	}
+	for n, s := range scenarios {
+		t.Run(n, func(t *testing.T) {
+			errs := ValidateUpdateCertificate(s.old, s.new)
+			if len(errs) != len(s.errs) || (len(errs) > 0 && !reflect.DeepEqual(errs, s.errs)) {
+				t.Errorf("Expected %v but got %v", s.errs, errs)
+			}
+		})
+	}
+}
+
+func TestValidatePrivateKeyParams(t *testing.T) {
+	fldPath := field.NewPath("spec", "privateKey")
+
+	tests := map[string]struct {
+		pk  *internalcmapi.CertificatePrivateKey
+		err *field.Error
+	}{
+		"defaults are valid": {
+			pk: &internalcmapi.CertificatePrivateKey{},
+		},
+		"rsa with valid size and PKCS1 encoding": {
+			pk: &internalcmapi.CertificatePrivateKey{Algorithm: internalcmapi.RSAKeyAlgorithm, Size: 2048, Encoding: internalcmapi.PKCS1},
+		},
+		"rsa with valid size and PKCS8 encoding": {
+			pk: &internalcmapi.CertificatePrivateKey{Algorithm: internalcmapi.RSAKeyAlgorithm, Size: 4096, Encoding: internalcmapi.PKCS8},
+		},
+		"rsa with invalid size": {
+			pk:  &internalcmapi.CertificatePrivateKey{Algorithm: internalcmapi.RSAKeyAlgorithm, Size: 1024, Encoding: internalcmapi.PKCS8},
+			err: field.Invalid(fldPath.Child("size"), 1024, "must be between 2048 & 8192 for rsa keyAlgorithm"),
+		},
+		"rsa with invalid size and unknown encoding reports the size": {
+			pk:  &internalcmapi.CertificatePrivateKey{Algorithm: internalcmapi.RSAKeyAlgorithm, Size: 1024, Encoding: "DER"},
+			err: field.Invalid(fldPath.Child("size"), 1024, "must be between 2048 & 8192 for rsa keyAlgorithm"),
+		},
+		"rsa with unknown encoding": {
+			pk:  &internalcmapi.CertificatePrivateKey{Algorithm: internalcmapi.RSAKeyAlgorithm, Encoding: "DER"},
+			err: field.NotSupported(fldPath.Child("encoding"), internalcmapi.PrivateKeyEncoding("DER"), []string{"PKCS1", "PKCS8"}),
+		},
+		"ecdsa with valid size and PKCS8 encoding": {
+			pk: &internalcmapi.CertificatePrivateKey{Algorithm: internalcmapi.ECDSAKeyAlgorithm, Size: 384, Encoding: internalcmapi.PKCS8},
+		},
+		"ecdsa with default encoding": {
+			pk: &internalcmapi.CertificatePrivateKey{Algorithm: internalcmapi.ECDSAKeyAlgorithm, Size: 256},
+		},
+		"ecdsa with PKCS1 encoding": {
+			pk:  &internalcmapi.CertificatePrivateKey{Algorithm: internalcmapi.ECDSAKeyAlgorithm, Size: 256, Encoding: internalcmapi.PKCS1},
+			err: field.Invalid(fldPath.Child("encoding"), internalcmapi.PKCS1, "PKCS1 encoding is not supported for ecdsa keyAlgorithm, use PKCS8"),
+		},
+		"ecdsa with invalid size and PKCS1 encoding reports the size": {
+			pk:  &internalcmapi.CertificatePrivateKey{Algorithm: internalcmapi.ECDSAKeyAlgorithm, Size: 2048, Encoding: internalcmapi.PKCS1},
+			err: field.NotSupported(fldPath.Child("size"), 2048, []string{"256", "384", "521"}),
+		},
+		"ecdsa with unknown encoding": {
+			pk:  &internalcmapi.CertificatePrivateKey{Algorithm: internalcmapi.ECDSAKeyAlgorithm, Encoding: "DER"},
+			err: field.NotSupported(fldPath.Child("encoding"), internalcmapi.PrivateKeyEncoding("DER"), []string{"PKCS1", "PKCS8"}),
+		},
+		"ed25519 with PKCS8 encoding": {
+			pk: &internalcmapi.CertificatePrivateKey{Algorithm: internalcmapi.Ed25519KeyAlgorithm, Encoding: internalcmapi.PKCS8},
+		},
+		"ed25519 with default encoding": {
+			pk: &internalcmapi.CertificatePrivateKey{Algorithm: internalcmapi.Ed25519KeyAlgorithm},
+		},
+		"ed25519 with size set": {
+			pk:  &internalcmapi.CertificatePrivateKey{Algorithm: internalcmapi.Ed25519KeyAlgorithm, Size: 256},
+			err: field.Invalid(fldPath.Child("size"), 256, "must not be set for ed25519 keyAlgorithm"),
+		},
+		"ed25519 with PKCS1 encoding": {
+			pk:  &internalcmapi.CertificatePrivateKey{Algorithm: internalcmapi.Ed25519KeyAlgorithm, Encoding: internalcmapi.PKCS1},
+			err: field.Invalid(fldPath.Child("encoding"), internalcmapi.PKCS1, "PKCS1 encoding is not supported for ed25519 keyAlgorithm, use PKCS8"),
+		},
+		"rsa with SHA1 signature hash": {
+			pk: &internalcmapi.CertificatePrivateKey{Algorithm: internalcmapi.RSAKeyAlgorithm, SignatureHash: internalcmapi.SHA1SignatureHash},
+		},
+		"ecdsa with SHA512 signature hash": {
+			pk: &internalcmapi.CertificatePrivateKey{Algorithm: internalcmapi.ECDSAKeyAlgorithm, Size: 256, SignatureHash: internalcmapi.SHA512SignatureHash},
+		},
+		"ed25519 with signature hash set": {
+			pk:  &internalcmapi.CertificatePrivateKey{Algorithm: internalcmapi.Ed25519KeyAlgorithm, SignatureHash: internalcmapi.SHA256SignatureHash},
+			err: field.Invalid(fldPath.Child("signatureHash"), internalcmapi.SHA256SignatureHash, "must not be set for ed25519 keyAlgorithm"),
+		},
+		"rsa with unknown signature hash": {
+			pk:  &internalcmapi.CertificatePrivateKey{SignatureHash: "MD5"},
+			err: field.NotSupported(fldPath.Child("signatureHash"), internalcmapi.PrivateKeySignatureHash("MD5"), []string{"SHA1", "SHA256", "SHA384", "SHA512"}),
+		},
+		"unknown algorithm is reported before size and encoding": {
+			pk:  &internalcmapi.CertificatePrivateKey{Algorithm: "DSA", Size: 256, Encoding: internalcmapi.PKCS1},
+			err: field.Invalid(fldPath.Child("algorithm"), internalcmapi.PrivateKeyAlgorithm("DSA"), "must be either empty or one of rsa, ecdsa or ed25519"),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := validatePrivateKeyParams(test.pk, fldPath)
+			if !reflect.DeepEqual(err, test.err) {
+				t.Errorf("expected error %v but got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestValidateDuration(t *testing.T) {
+	usefulDurations := map[string]*metav1.Duration{
+		"one second":  {Duration: time.Second},
+		"ten minutes": {Duration: time.Minute * 10},
+		"half hour":   {Duration: time.Minute * 30},
+		"one hour":    {Duration: time.Hour},
+		"one month":   {Duration: time.Hour * 24 * 30},
+		"half year":   {Duration: time.Hour * 24 * 180},
+		"one year":    {Duration: time.Hour * 24 * 365},
+		"ten years":   {Duration: time.Hour * 24 * 365 * 10},
+		"200 years":   {Duration: time.Hour * 24 * 365 * 200},
+	}
+
+	fldPath := field.NewPath("spec")
+	scenarios := map[string]struct {
+		cfg  *internalcmapi.Certificate
+		errs []*field.Error
+	}{
+		"default duration and renewBefore": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "testcn",
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+				},
+			},
+		},
 		"valid duration and renewBefore": {
 			cfg: &internalcmapi.Certificate{
 				Spec: internalcmapi.CertificateSpec{
@@ -616,6 +1173,27 @@ func TestValidateDuration(t *testing.T) {
 			},
 			errs: []*field.Error{field.Invalid(fldPath.Child("duration"), usefulDurations["half hour"].Duration, fmt.Sprintf("certificate duration must be greater than %s", cmapi.MinimumCertificateDuration))},
 		},
+		"duration of ten years is valid": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					Duration:   usefulDurations["ten years"],
+					CommonName: "testcn",
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+				},
+			},
+		},
+		"duration is greater than the maximum permitted value": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					Duration:   usefulDurations["200 years"],
+					CommonName: "testcn",
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+				},
+			},
+			errs: []*field.Error{field.Invalid(fldPath.Child("duration"), usefulDurations["200 years"].Duration, fmt.Sprintf("certificate duration must not be greater than %s", cmapi.MaximumCertificateDuration))},
+		},
 	}
 	for n, s := range scenarios {
 		t.Run(n, func(t *testing.T) {
@@ -633,3 +1211,834 @@ func TestValidateDuration(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateDurationUnits(t *testing.T) {
+	ninetyDays := ValidateDuration(&internalcmapi.CertificateSpec{
+		Duration: &metav1.Duration{Duration: time.Hour * 24 * 90},
+	}, field.NewPath("spec"))
+
+	scenarios := map[string]struct {
+		duration  string
+		expectErr bool
+	}{
+		"90 days expressed in hours": {
+			duration: "2160h",
+		},
+		"90 days expressed in mixed units": {
+			duration: "2159h59m60s",
+		},
+		"90 days expressed as the canonical string": {
+			duration: "2160h0m0s",
+		},
+		"90 days expressed with an unsupported day unit": {
+			duration:  "90d",
+			expectErr: true,
+		},
+	}
+	for n, s := range scenarios {
+		t.Run(n, func(t *testing.T) {
+			var d metav1.Duration
+			err := json.Unmarshal([]byte(fmt.Sprintf("%q", s.duration)), &d)
+			if s.expectErr != (err != nil) {
+				t.Fatalf("expected error=%t but got: %v", s.expectErr, err)
+			}
+			if err != nil {
+				return
+			}
+
+			errs := ValidateDuration(&internalcmapi.CertificateSpec{Duration: &d}, field.NewPath("spec"))
+			if !reflect.DeepEqual(errs, ninetyDays) {
+				t.Errorf("Expected %q to validate the same as 90 days, got %v but expected %v", s.duration, errs, ninetyDays)
+			}
+		})
+	}
+}
+
+func TestValidateIssuanceTimeout(t *testing.T) {
+	fldPath := field.NewPath("spec")
+	scenarios := map[string]struct {
+		issuanceTimeout *metav1.Duration
+		errs            field.ErrorList
+	}{
+		"unset issuanceTimeout": {},
+		"valid issuanceTimeout": {
+			issuanceTimeout: &metav1.Duration{Duration: time.Hour},
+		},
+		"issuanceTimeout equal to the minimum": {
+			issuanceTimeout: &metav1.Duration{Duration: cmapi.MinimumIssuanceTimeout},
+		},
+		"issuanceTimeout below the minimum": {
+			issuanceTimeout: &metav1.Duration{Duration: time.Second * 30},
+			errs: field.ErrorList{
+				field.Invalid(fldPath.Child("issuanceTimeout"), time.Second*30, "certificate issuanceTimeout must be at least 1m0s"),
+			},
+		},
+		"zero issuanceTimeout": {
+			issuanceTimeout: &metav1.Duration{},
+			errs: field.ErrorList{
+				field.Invalid(fldPath.Child("issuanceTimeout"), time.Duration(0), "certificate issuanceTimeout must be at least 1m0s"),
+			},
+		},
+		"negative issuanceTimeout": {
+			issuanceTimeout: &metav1.Duration{Duration: -time.Hour},
+			errs: field.ErrorList{
+				field.Invalid(fldPath.Child("issuanceTimeout"), -time.Hour, "certificate issuanceTimeout must be at least 1m0s"),
+			},
+		},
+	}
+	for n, s := range scenarios {
+		t.Run(n, func(t *testing.T) {
+			spec := &internalcmapi.CertificateSpec{
+				CommonName:      "testcn",
+				SecretName:      "abc",
+				IssuerRef:       validIssuerRef,
+				IssuanceTimeout: s.issuanceTimeout,
+			}
+			errs := ValidateCertificateSpec(spec, fldPath)
+			if len(errs) != len(s.errs) {
+				t.Fatalf("Expected errors %v but got %v", s.errs, errs)
+			}
+			for i, e := range errs {
+				if !reflect.DeepEqual(e, s.errs[i]) {
+					t.Errorf("Expected %v but got %v", s.errs[i], e)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateMaxPathLen(t *testing.T) {
+	fldPath := field.NewPath("spec")
+	scenarios := map[string]struct {
+		isCA           bool
+		maxPathLen     int
+		maxPathLenZero bool
+		errs           field.ErrorList
+	}{
+		"unset on a leaf certificate": {},
+		"unset on a CA certificate": {
+			isCA: true,
+		},
+		"maxPathLen on a CA certificate": {
+			isCA:       true,
+			maxPathLen: 2,
+		},
+		"maxPathLenZero on a CA certificate": {
+			isCA:           true,
+			maxPathLenZero: true,
+		},
+		"maxPathLen on a leaf certificate": {
+			maxPathLen: 1,
+			errs: field.ErrorList{
+				field.Invalid(fldPath.Child("maxPathLen"), 1, "may only be set if isCA is true"),
+			},
+		},
+		"maxPathLenZero on a leaf certificate": {
+			maxPathLenZero: true,
+			errs: field.ErrorList{
+				field.Invalid(fldPath.Child("maxPathLenZero"), true, "may only be set if isCA is true"),
+			},
+		},
+		"negative maxPathLen": {
+			isCA:       true,
+			maxPathLen: -1,
+			errs: field.ErrorList{
+				field.Invalid(fldPath.Child("maxPathLen"), -1, "must not be negative"),
+			},
+		},
+		"maxPathLenZero with a non-zero maxPathLen": {
+			isCA:           true,
+			maxPathLen:     1,
+			maxPathLenZero: true,
+			errs: field.ErrorList{
+				field.Invalid(fldPath.Child("maxPathLenZero"), true, "must not be set if maxPathLen is greater than 0"),
+			},
+		},
+	}
+	for n, s := range scenarios {
+		t.Run(n, func(t *testing.T) {
+			spec := &internalcmapi.CertificateSpec{
+				CommonName:     "testcn",
+				SecretName:     "abc",
+				IssuerRef:      validIssuerRef,
+				IsCA:           s.isCA,
+				MaxPathLen:     s.maxPathLen,
+				MaxPathLenZero: s.maxPathLenZero,
+			}
+			errs := ValidateCertificateSpec(spec, fldPath)
+			if len(errs) != len(s.errs) {
+				t.Fatalf("Expected errors %v but got %v", s.errs, errs)
+			}
+			for i, e := range errs {
+				if !reflect.DeepEqual(e, s.errs[i]) {
+					t.Errorf("Expected %v but got %v", s.errs[i], e)
+				}
+			}
+		})
+	}
+}
+
+func TestWarnCertificate(t *testing.T) {
+	scenarios := map[string]struct {
+		cfg      *internalcmapi.Certificate
+		warnings []string
+	}{
+		"CA certificate with dnsNames": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "testcn",
+					DNSNames:   []string{"example.com"},
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+					IsCA:       true,
+				},
+			},
+			warnings: []string{"spec.isCA: certificate is a CA but also sets dnsNames, uris, ipAddresses or emailAddresses, which CA certificates rarely need"},
+		},
+		"certificate with an 8192 bit rsa private key": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "testcn",
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+					PrivateKey: &internalcmapi.CertificatePrivateKey{
+						Algorithm: internalcmapi.RSAKeyAlgorithm,
+						Size:      8192,
+					},
+				},
+			},
+			warnings: []string{"spec.privateKey.size: generating rsa keys larger than 4096 bits can take a long time, delaying issuance and renewal of the certificate"},
+		},
+		"certificate signing with SHA1": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "testcn",
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+					PrivateKey: &internalcmapi.CertificatePrivateKey{
+						SignatureHash: internalcmapi.SHA1SignatureHash,
+					},
+				},
+			},
+			warnings: []string{"spec.privateKey.signatureHash: SHA1 is no longer considered secure and should only be used with issuers that require it"},
+		},
+		"certificate with an 8192 bit private key and no algorithm set": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "testcn",
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+					PrivateKey: &internalcmapi.CertificatePrivateKey{
+						Size: 8192,
+					},
+				},
+			},
+			warnings: []string{"spec.privateKey.size: generating rsa keys larger than 4096 bits can take a long time, delaying issuance and renewal of the certificate"},
+		},
+		"certificate with a 4096 bit rsa private key": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "testcn",
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+					PrivateKey: &internalcmapi.CertificatePrivateKey{
+						Algorithm: internalcmapi.RSAKeyAlgorithm,
+						Size:      4096,
+					},
+				},
+			},
+		},
+		"CA certificate without SANs": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "testcn",
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+					IsCA:       true,
+				},
+			},
+		},
+		"certificate with an encrypted private key": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "testcn",
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+					PrivateKey: &internalcmapi.CertificatePrivateKey{
+						EncryptionPassphraseSecretRef: &cmmeta.SecretKeySelector{
+							LocalObjectReference: cmmeta.LocalObjectReference{Name: "passphrase"},
+							Key:                  "key",
+						},
+					},
+				},
+			},
+			warnings: []string{"spec.privateKey.encryptionPassphraseSecretRef: the private key will be stored encrypted, and applications consuming the Secret must decrypt it before use"},
+		},
+		"leaf certificate with dnsNames": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					DNSNames:   []string{"example.com"},
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+				},
+			},
+		},
+		"certificate with exact duplicate dnsNames": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					DNSNames:   []string{"example.com", "www.example.com", "example.com"},
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+				},
+			},
+			warnings: []string{`spec.dnsNames[2]: "example.com" is a duplicate of spec.dnsNames[0]`},
+		},
+		"certificate with dnsNames that only differ in case": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					DNSNames:   []string{"example.com", "Example.COM", "EXAMPLE.com"},
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+				},
+			},
+			warnings: []string{
+				`spec.dnsNames[1]: "Example.COM" is a duplicate of spec.dnsNames[0]`,
+				`spec.dnsNames[2]: "EXAMPLE.com" is a duplicate of spec.dnsNames[0]`,
+			},
+		},
+		"certificate with duplicate ipAddresses, uris and emailAddresses": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					IPAddresses: []string{"::1", "0:0::1", "127.0.0.1"},
+					URISANs:     []string{"spiffe://example.com/a", "spiffe://example.com/a"},
+					EmailSANs:   []string{"alice@example.com", "alice@example.com"},
+					SecretName:  "abc",
+					IssuerRef:   validIssuerRef,
+				},
+			},
+			warnings: []string{
+				`spec.ipAddresses[1]: "0:0::1" is a duplicate of spec.ipAddresses[0]`,
+				`spec.uris[1]: "spiffe://example.com/a" is a duplicate of spec.uris[0]`,
+				`spec.emailAddresses[1]: "alice@example.com" is a duplicate of spec.emailAddresses[0]`,
+			},
+		},
+		"certificate with email addresses that only differ in case": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					EmailSANs:  []string{"alice@example.com", "Alice@example.com"},
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+				},
+			},
+		},
+	}
+	for n, s := range scenarios {
+		t.Run(n, func(t *testing.T) {
+			warnings := WarnCertificate(s.cfg)
+			if !reflect.DeepEqual(warnings, s.warnings) {
+				t.Errorf("Expected warnings %v but got %v", s.warnings, warnings)
+			}
+		})
+	}
+}
+
+func TestWarnCertificateSecretSize(t *testing.T) {
+	allFormats := func(spec internalcmapi.CertificateSpec) *internalcmapi.Certificate {
+		spec.SecretName = "abc"
+		spec.IssuerRef = validIssuerRef
+		spec.Keystores = &internalcmapi.CertificateKeystores{
+			JKS:    &internalcmapi.JKSKeystore{Create: true},
+			PKCS12: &internalcmapi.PKCS12Keystore{Create: true},
+		}
+		spec.AdditionalOutputFormats = []internalcmapi.CertificateAdditionalOutputFormat{
+			{Type: internalcmapi.CertificateOutputFormatDER},
+		}
+		return &internalcmapi.Certificate{Spec: spec}
+	}
+
+	var manyDNSNames []string
+	for i := 0; i < 2500; i++ {
+		manyDNSNames = append(manyDNSNames, fmt.Sprintf("host-%04d.a-long-subdomain-name-used-for-testing.example.com", i))
+	}
+
+	scenarios := map[string]struct {
+		cfg           *internalcmapi.Certificate
+		expectWarning bool
+	}{
+		"small certificate with all output formats": {
+			cfg: allFormats(internalcmapi.CertificateSpec{
+				DNSNames: []string{"example.com"},
+			}),
+		},
+		"large key certificate with all output formats": {
+			cfg: allFormats(internalcmapi.CertificateSpec{
+				DNSNames: manyDNSNames,
+				PrivateKey: &internalcmapi.CertificatePrivateKey{
+					Algorithm: internalcmapi.RSAKeyAlgorithm,
+					Size:      8192,
+				},
+			}),
+			expectWarning: true,
+		},
+	}
+	for n, s := range scenarios {
+		t.Run(n, func(t *testing.T) {
+			var expected []string
+			if pk := s.cfg.Spec.PrivateKey; pk != nil && pk.Size > 4096 {
+				expected = append(expected, "spec.privateKey.size: generating rsa keys larger than 4096 bits can take a long time, delaying issuance and renewal of the certificate")
+			}
+			if s.expectWarning {
+				size := estimateSecretSize(&s.cfg.Spec)
+				expected = append(expected, fmt.Sprintf("spec.secretName: the Secret is estimated to be around %d bytes, which is close to or above the %d byte limit on Secret size; consider reducing the number of subject alternative names, the key size or the number of output formats", size, corev1.MaxSecretSize))
+			}
+			warnings := WarnCertificate(s.cfg)
+			if !reflect.DeepEqual(warnings, expected) {
+				t.Errorf("Expected warnings %v but got %v", expected, warnings)
+			}
+		})
+	}
+}
+
+func TestValidateCertificateWithIssuerResolver(t *testing.T) {
+	fldPath := field.NewPath("spec")
+	requireCNIssuer := &internalcmapi.ClusterIssuer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "name",
+			Annotations: map[string]string{
+				internalcmapi.RequireCommonNameAnnotationKey: "true",
+			},
+		},
+	}
+	resolverFor := func(issuerObj internalcmapi.GenericIssuer, err error) IssuerResolver {
+		return func(string, cmmeta.ObjectReference) (internalcmapi.GenericIssuer, error) {
+			return issuerObj, err
+		}
+	}
+
+	scenarios := map[string]struct {
+		resolver IssuerResolver
+		spec     internalcmapi.CertificateSpec
+		errs     field.ErrorList
+	}{
+		"san only certificate for issuer requiring a common name": {
+			resolver: resolverFor(requireCNIssuer, nil),
+			spec: internalcmapi.CertificateSpec{
+				DNSNames:   []string{"example.com"},
+				SecretName: "abc",
+				IssuerRef:  validIssuerRef,
+			},
+			errs: field.ErrorList{
+				field.Required(fldPath.Child("commonName"), "issuer 'name' requires a commonName to be set"),
+			},
+		},
+		"certificate with common name for issuer requiring a common name": {
+			resolver: resolverFor(requireCNIssuer, nil),
+			spec: internalcmapi.CertificateSpec{
+				CommonName: "example.com",
+				DNSNames:   []string{"example.com"},
+				SecretName: "abc",
+				IssuerRef:  validIssuerRef,
+			},
+			errs: field.ErrorList{},
+		},
+		"san only certificate for issuer not requiring a common name": {
+			resolver: resolverFor(&internalcmapi.ClusterIssuer{ObjectMeta: metav1.ObjectMeta{Name: "name"}}, nil),
+			spec: internalcmapi.CertificateSpec{
+				DNSNames:   []string{"example.com"},
+				SecretName: "abc",
+				IssuerRef:  validIssuerRef,
+			},
+			errs: field.ErrorList{},
+		},
+		"issuer that cannot be resolved is skipped": {
+			resolver: resolverFor(nil, fmt.Errorf("not found")),
+			spec: internalcmapi.CertificateSpec{
+				DNSNames:   []string{"example.com"},
+				SecretName: "abc",
+				IssuerRef:  validIssuerRef,
+			},
+			errs: field.ErrorList{},
+		},
+		"no resolver set is skipped": {
+			spec: internalcmapi.CertificateSpec{
+				DNSNames:   []string{"example.com"},
+				SecretName: "abc",
+				IssuerRef:  validIssuerRef,
+			},
+			errs: field.ErrorList{},
+		},
+	}
+	for n, s := range scenarios {
+		t.Run(n, func(t *testing.T) {
+			p := &CertificatePolicy{IssuerResolver: s.resolver}
+
+			errs := p.ValidateCertificate(&internalcmapi.Certificate{Spec: s.spec})
+			if len(errs) != len(s.errs) {
+				t.Errorf("Expected %v but got %v", s.errs, errs)
+				return
+			}
+			for i, e := range errs {
+				expectedErr := s.errs[i]
+				if !reflect.DeepEqual(e, expectedErr) {
+					t.Errorf("Expected %v but got %v", expectedErr, e)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateCertificateMaxSubjectAltNames(t *testing.T) {
+	fldPath := field.NewPath("spec")
+	acmeIssuer := &internalcmapi.ClusterIssuer{
+		ObjectMeta: metav1.ObjectMeta{Name: "name"},
+		Spec: internalcmapi.IssuerSpec{
+			IssuerConfig: internalcmapi.IssuerConfig{
+				ACME: &internalcmacme.ACMEIssuer{},
+			},
+		},
+	}
+	caIssuer := &internalcmapi.ClusterIssuer{
+		ObjectMeta: metav1.ObjectMeta{Name: "name"},
+		Spec: internalcmapi.IssuerSpec{
+			IssuerConfig: internalcmapi.IssuerConfig{
+				CA: &internalcmapi.CAIssuer{},
+			},
+		},
+	}
+	resolverFor := func(issuerObj internalcmapi.GenericIssuer) IssuerResolver {
+		return func(string, cmmeta.ObjectReference) (internalcmapi.GenericIssuer, error) {
+			return issuerObj, nil
+		}
+	}
+	dnsNames := func(n int) []string {
+		var names []string
+		for i := 0; i < n; i++ {
+			names = append(names, fmt.Sprintf("%d.example.com", i))
+		}
+		return names
+	}
+	tooManyErr := func(count, max int) *field.Error {
+		return field.Invalid(fldPath, count, fmt.Sprintf("the total number of dnsNames, ipAddresses, uris and emailAddresses must be no more than %d", max))
+	}
+
+	scenarios := map[string]struct {
+		resolver IssuerResolver
+		max      int
+		spec     internalcmapi.CertificateSpec
+		errs     field.ErrorList
+	}{
+		"100 dnsNames for an ACME issuer": {
+			resolver: resolverFor(acmeIssuer),
+			spec: internalcmapi.CertificateSpec{
+				DNSNames:   dnsNames(100),
+				SecretName: "abc",
+				IssuerRef:  validIssuerRef,
+			},
+			errs: field.ErrorList{},
+		},
+		"101 dnsNames for an ACME issuer": {
+			resolver: resolverFor(acmeIssuer),
+			spec: internalcmapi.CertificateSpec{
+				DNSNames:   dnsNames(101),
+				SecretName: "abc",
+				IssuerRef:  validIssuerRef,
+			},
+			errs: field.ErrorList{tooManyErr(101, 100)},
+		},
+		"100 dnsNames and an email address for an ACME issuer": {
+			resolver: resolverFor(acmeIssuer),
+			spec: internalcmapi.CertificateSpec{
+				DNSNames:   dnsNames(100),
+				EmailSANs:  []string{"alice@example.com"},
+				SecretName: "abc",
+				IssuerRef:  validIssuerRef,
+			},
+			errs: field.ErrorList{tooManyErr(101, 100)},
+		},
+		"101 dnsNames for a non-ACME issuer": {
+			resolver: resolverFor(caIssuer),
+			spec: internalcmapi.CertificateSpec{
+				DNSNames:   dnsNames(101),
+				SecretName: "abc",
+				IssuerRef:  validIssuerRef,
+			},
+			errs: field.ErrorList{},
+		},
+		"101 dnsNames when the issuer cannot be resolved": {
+			spec: internalcmapi.CertificateSpec{
+				DNSNames:   dnsNames(101),
+				SecretName: "abc",
+				IssuerRef:  validIssuerRef,
+			},
+			errs: field.ErrorList{},
+		},
+		"configured maximum is enforced without resolving the issuer": {
+			max: 2,
+			spec: internalcmapi.CertificateSpec{
+				DNSNames:    dnsNames(2),
+				IPAddresses: []string{"127.0.0.1"},
+				SecretName:  "abc",
+				IssuerRef:   validIssuerRef,
+			},
+			errs: field.ErrorList{tooManyErr(3, 2)},
+		},
+		"configured maximum overrides the ACME default": {
+			resolver: resolverFor(acmeIssuer),
+			max:      200,
+			spec: internalcmapi.CertificateSpec{
+				DNSNames:   dnsNames(101),
+				SecretName: "abc",
+				IssuerRef:  validIssuerRef,
+			},
+			errs: field.ErrorList{},
+		},
+	}
+	for n, s := range scenarios {
+		t.Run(n, func(t *testing.T) {
+			p := &CertificatePolicy{MaxSubjectAltNames: s.max, IssuerResolver: s.resolver}
+
+			errs := p.ValidateCertificate(&internalcmapi.Certificate{Spec: s.spec})
+			if len(errs) != len(s.errs) {
+				t.Errorf("Expected %v but got %v", s.errs, errs)
+				return
+			}
+			for i, e := range errs {
+				expectedErr := s.errs[i]
+				if !reflect.DeepEqual(e, expectedErr) {
+					t.Errorf("Expected %v but got %v", expectedErr, e)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateCertificateSecretNamePolicy(t *testing.T) {
+	fldPath := field.NewPath("spec", "secretName")
+	pattern := regexp.MustCompile(`^(?:[a-z0-9-]+-tls)$`)
+	certificate := func(secretName string) *internalcmapi.Certificate {
+		return &internalcmapi.Certificate{
+			Spec: internalcmapi.CertificateSpec{
+				CommonName: "testcn",
+				SecretName: secretName,
+				IssuerRef:  validIssuerRef,
+			},
+		}
+	}
+
+	scenarios := map[string]struct {
+		pattern *regexp.Regexp
+		old     *internalcmapi.Certificate
+		crt     *internalcmapi.Certificate
+		errs    field.ErrorList
+	}{
+		"secretName matching the pattern is accepted": {
+			pattern: pattern,
+			crt:     certificate("my-app-tls"),
+		},
+		"secretName not matching the pattern is rejected": {
+			pattern: pattern,
+			crt:     certificate("my-app"),
+			errs:    field.ErrorList{field.Invalid(fldPath, "my-app", `must match the pattern "^(?:[a-z0-9-]+-tls)$"`)},
+		},
+		"secretName only partially matching the pattern is rejected": {
+			pattern: pattern,
+			crt:     certificate("my-app-tls-old"),
+			errs:    field.ErrorList{field.Invalid(fldPath, "my-app-tls-old", `must match the pattern "^(?:[a-z0-9-]+-tls)$"`)},
+		},
+		"any secretName is accepted if no pattern is configured": {
+			crt: certificate("my-app"),
+		},
+		"updating a Certificate without changing a non-matching secretName is accepted": {
+			pattern: pattern,
+			old:     certificate("my-app"),
+			crt:     certificate("my-app"),
+		},
+		"updating a Certificate to a non-matching secretName is rejected": {
+			pattern: pattern,
+			old:     certificate("my-app-tls"),
+			crt:     certificate("my-app"),
+			errs:    field.ErrorList{field.Invalid(fldPath, "my-app", `must match the pattern "^(?:[a-z0-9-]+-tls)$"`)},
+		},
+	}
+	for n, s := range scenarios {
+		t.Run(n, func(t *testing.T) {
+			p := &CertificatePolicy{SecretNamePattern: s.pattern}
+
+			var errs field.ErrorList
+			if s.old != nil {
+				errs = p.ValidateUpdateCertificate(s.old, s.crt)
+			} else {
+				errs = p.ValidateCertificate(s.crt)
+			}
+			if len(errs) != len(s.errs) {
+				t.Errorf("Expected %v but got %v", s.errs, errs)
+				return
+			}
+			for i, e := range errs {
+				expectedErr := s.errs[i]
+				if !reflect.DeepEqual(e, expectedErr) {
+					t.Errorf("Expected %v but got %v", expectedErr, e)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateCertificateKeySizePolicy(t *testing.T) {
+	fldPath := field.NewPath("spec", "privateKey", "size")
+	certificate := func(pk *internalcmapi.CertificatePrivateKey) *internalcmapi.Certificate {
+		return &internalcmapi.Certificate{
+			Spec: internalcmapi.CertificateSpec{
+				CommonName: "testcn",
+				SecretName: "abc",
+				IssuerRef:  validIssuerRef,
+				PrivateKey: pk,
+			},
+		}
+	}
+	rsaKey := func(size int) *internalcmapi.CertificatePrivateKey {
+		return &internalcmapi.CertificatePrivateKey{Algorithm: internalcmapi.RSAKeyAlgorithm, Size: size}
+	}
+	ecdsaKey := func(size int) *internalcmapi.CertificatePrivateKey {
+		return &internalcmapi.CertificatePrivateKey{Algorithm: internalcmapi.ECDSAKeyAlgorithm, Size: size}
+	}
+
+	scenarios := map[string]struct {
+		minRSA, minECDSA int
+		old              *internalcmapi.Certificate
+		crt              *internalcmapi.Certificate
+		errs             field.ErrorList
+	}{
+		"rsa key at the minimum size is accepted": {
+			minRSA: 3072,
+			crt:    certificate(rsaKey(3072)),
+		},
+		"rsa key below the minimum size is rejected": {
+			minRSA: 3072,
+			crt:    certificate(rsaKey(2048)),
+			errs:   field.ErrorList{field.Invalid(fldPath, 2048, "must be at least 3072 for rsa keyAlgorithm (defaults to 2048 if not set)")},
+		},
+		"rsa key without a size is checked against the default size": {
+			minRSA: 3072,
+			crt:    certificate(nil),
+			errs:   field.ErrorList{field.Invalid(fldPath, 0, "must be at least 3072 for rsa keyAlgorithm (defaults to 2048 if not set)")},
+		},
+		"ecdsa key is not checked against the minimum rsa key size": {
+			minRSA: 3072,
+			crt:    certificate(ecdsaKey(256)),
+		},
+		"ecdsa key below the minimum curve is rejected": {
+			minECDSA: 384,
+			crt:      certificate(ecdsaKey(256)),
+			errs:     field.ErrorList{field.Invalid(fldPath, 256, "must be at least 384 for ecdsa keyAlgorithm (defaults to 256 if not set)")},
+		},
+		"ecdsa key at the minimum curve is accepted": {
+			minECDSA: 384,
+			crt:      certificate(ecdsaKey(521)),
+		},
+		"ed25519 key is not checked": {
+			minRSA:   3072,
+			minECDSA: 384,
+			crt:      certificate(&internalcmapi.CertificatePrivateKey{Algorithm: internalcmapi.Ed25519KeyAlgorithm}),
+		},
+		"any key size is accepted if no minimum is configured": {
+			crt: certificate(rsaKey(2048)),
+		},
+		"updating a Certificate without changing a small key is accepted": {
+			minRSA: 3072,
+			old:    certificate(rsaKey(2048)),
+			crt:    certificate(rsaKey(2048)),
+		},
+		"updating a Certificate to a key below the minimum size is rejected": {
+			minRSA: 3072,
+			old:    certificate(rsaKey(4096)),
+			crt:    certificate(rsaKey(2048)),
+			errs:   field.ErrorList{field.Invalid(fldPath, 2048, "must be at least 3072 for rsa keyAlgorithm (defaults to 2048 if not set)")},
+		},
+	}
+	for n, s := range scenarios {
+		t.Run(n, func(t *testing.T) {
+			p := &CertificatePolicy{MinRSAKeySize: s.minRSA, MinECDSAKeySize: s.minECDSA}
+
+			var errs field.ErrorList
+			if s.old != nil {
+				errs = p.ValidateUpdateCertificate(s.old, s.crt)
+			} else {
+				errs = p.ValidateCertificate(s.crt)
+			}
+			if len(errs) != len(s.errs) {
+				t.Errorf("Expected %v but got %v", s.errs, errs)
+				return
+			}
+			for i, e := range errs {
+				expectedErr := s.errs[i]
+				if !reflect.DeepEqual(e, expectedErr) {
+					t.Errorf("Expected %v but got %v", expectedErr, e)
+				}
+			}
+		})
+	}
+}
+
+func TestWarnUpdateCertificate(t *testing.T) {
+	keystores := func(jksSecret, pkcs12Secret string) *internalcmapi.CertificateKeystores {
+		return &internalcmapi.CertificateKeystores{
+			JKS: &internalcmapi.JKSKeystore{
+				Create: true,
+				PasswordSecretRef: cmmeta.SecretKeySelector{
+					LocalObjectReference: cmmeta.LocalObjectReference{Name: jksSecret},
+					Key:                  "password",
+				},
+			},
+			PKCS12: &internalcmapi.PKCS12Keystore{
+				Create: true,
+				PasswordSecretRef: cmmeta.SecretKeySelector{
+					LocalObjectReference: cmmeta.LocalObjectReference{Name: pkcs12Secret},
+					Key:                  "password",
+				},
+			},
+		}
+	}
+	crtWithKeystores := func(ks *internalcmapi.CertificateKeystores) *internalcmapi.Certificate {
+		return &internalcmapi.Certificate{
+			Spec: internalcmapi.CertificateSpec{
+				CommonName: "testcn",
+				SecretName: "abc",
+				IssuerRef:  validIssuerRef,
+				Keystores:  ks,
+			},
+		}
+	}
+
+	scenarios := map[string]struct {
+		old, new *internalcmapi.Certificate
+		warnings []string
+	}{
+		"unchanged keystore password Secret refs": {
+			old: crtWithKeystores(keystores("jks", "pkcs12")),
+			new: crtWithKeystores(keystores("jks", "pkcs12")),
+		},
+		"changed JKS keystore password Secret ref": {
+			old:      crtWithKeystores(keystores("jks", "pkcs12")),
+			new:      crtWithKeystores(keystores("jks-new", "pkcs12")),
+			warnings: []string{"spec.keystores.jks.passwordSecretRef: changing the password Secret means the JKS keystore will be encrypted with a new password, consumers must re-import it"},
+		},
+		"changed PKCS12 keystore password Secret ref": {
+			old:      crtWithKeystores(keystores("jks", "pkcs12")),
+			new:      crtWithKeystores(keystores("jks", "pkcs12-new")),
+			warnings: []string{"spec.keystores.pkcs12.passwordSecretRef: changing the password Secret means the PKCS12 keystore will be encrypted with a new password, consumers must re-import it"},
+		},
+		"keystores added": {
+			old: crtWithKeystores(nil),
+			new: crtWithKeystores(keystores("jks", "pkcs12")),
+		},
+	}
+	for n, s := range scenarios {
+		t.Run(n, func(t *testing.T) {
+			warnings := WarnUpdateCertificate(s.old, s.new)
+			if !reflect.DeepEqual(warnings, s.warnings) {
+				t.Errorf("Expected warnings %v but got %v", s.warnings, warnings)
+			}
+		})
+	}
+}
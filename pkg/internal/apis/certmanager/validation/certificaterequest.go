@@ -38,7 +38,11 @@ var defaultInternalKeyUsages = []cmapi.KeyUsage{cmapi.UsageDigitalSignature, cma
 
 func ValidateCertificateRequest(obj runtime.Object) field.ErrorList {
 	cr := obj.(*cmapi.CertificateRequest)
-	allErrs := ValidateCertificateRequestSpec(&cr.Spec, field.NewPath("spec"), true)
+	// the usages of a CSR that is to be signed verbatim are allowed to differ
+	// from those on the spec
+	validateCSRContent := cr.Annotations[cmapi.CertificateRequestSignCSRVerbatimAnnotationKey] != "true"
+	allErrs := ValidateCertificateRequestSpec(&cr.Spec, field.NewPath("spec"), validateCSRContent)
+	allErrs = append(allErrs, ValidateCertificateRequestStatus(&cr.Status, field.NewPath("status"))...)
 	return allErrs
 }
 
@@ -46,13 +50,45 @@ func ValidateUpdateCertificateRequest(oldObj, obj runtime.Object) field.ErrorLis
 	cr := obj.(*cmapi.CertificateRequest)
 	// do not check the CSR content here not to break existing resources on upgrade
 	allErrs := ValidateCertificateRequestSpec(&cr.Spec, field.NewPath("spec"), false)
+	allErrs = append(allErrs, ValidateCertificateRequestStatus(&cr.Status, field.NewPath("status"))...)
 	return allErrs
 }
 
+var supportedCertificateRequestConditionTypes = []string{
+	string(cmapi.CertificateRequestConditionReady),
+	string(cmapi.CertificateRequestConditionApproved),
+	string(cmapi.CertificateRequestConditionDenied),
+	string(cmapi.CertificateRequestConditionInvalidRequest),
+}
+
+// ValidateCertificateRequestStatus validates that all conditions on the
+// CertificateRequest status are of a known type.
+func ValidateCertificateRequestStatus(crStatus *cmapi.CertificateRequestStatus, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+
+	for i, c := range crStatus.Conditions {
+		if !isSupportedCertificateRequestConditionType(c.Type) {
+			el = append(el, field.NotSupported(fldPath.Child("conditions").Index(i).Child("type"), c.Type, supportedCertificateRequestConditionTypes))
+		}
+	}
+
+	return el
+}
+
+func isSupportedCertificateRequestConditionType(t cmapi.CertificateRequestConditionType) bool {
+	for _, supported := range supportedCertificateRequestConditionTypes {
+		if string(t) == supported {
+			return true
+		}
+	}
+	return false
+}
+
 func ValidateCertificateRequestSpec(crSpec *cmapi.CertificateRequestSpec, fldPath *field.Path, validateCSRContent bool) field.ErrorList {
 	el := field.ErrorList{}
 
 	el = append(el, validateIssuerRef(crSpec.IssuerRef, fldPath)...)
+	el = append(el, validateFallbackIssuerRef(crSpec.IssuerRef, crSpec.FallbackIssuerRef, fldPath)...)
 
 	if len(crSpec.Request) == 0 {
 		el = append(el, field.Required(fldPath.Child("request"), "must be specified"))
@@ -26,6 +26,7 @@ import (
 
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	cminternal "github.com/jetstack/cert-manager/pkg/internal/apis/certmanager"
+	cmmeta "github.com/jetstack/cert-manager/pkg/internal/apis/meta"
 	"github.com/jetstack/cert-manager/pkg/util/pki"
 	utilpki "github.com/jetstack/cert-manager/pkg/util/pki"
 	"github.com/jetstack/cert-manager/test/unit/gen"
@@ -153,6 +154,70 @@ func TestValidateCertificateRequestSpec(t *testing.T) {
 	}
 }
 
+func TestValidateCertificateRequestSignCSRVerbatim(t *testing.T) {
+	mismatchedCR := func(annotations map[string]string) *cminternal.CertificateRequest {
+		cr := &cminternal.CertificateRequest{
+			Spec: cminternal.CertificateRequestSpec{
+				Request:   mustGenerateCSR(t, gen.Certificate("test", gen.SetCertificateDNSNames("example.com"), gen.SetCertificateKeyUsages(cmapi.UsageDigitalSignature, cmapi.UsageCodeSigning))),
+				IssuerRef: validIssuerRef,
+				Usages:    []cminternal.KeyUsage{cminternal.UsageDigitalSignature, cminternal.UsageServerAuth},
+			},
+		}
+		cr.Annotations = annotations
+		return cr
+	}
+
+	if errs := ValidateCertificateRequest(mismatchedCR(nil)); len(errs) != 1 {
+		t.Errorf("expected mismatched usages to be rejected, got errors: %v", errs)
+	}
+
+	errs := ValidateCertificateRequest(mismatchedCR(map[string]string{
+		cminternal.CertificateRequestSignCSRVerbatimAnnotationKey: "true",
+	}))
+	if len(errs) != 0 {
+		t.Errorf("expected mismatched usages to be allowed when signing verbatim, got errors: %v", errs)
+	}
+}
+
+func TestValidateCertificateRequestStatus(t *testing.T) {
+	fldPath := field.NewPath("status")
+
+	tests := map[string]struct {
+		conditions []cminternal.CertificateRequestCondition
+		want       field.ErrorList
+	}{
+		"no conditions": {
+			want: field.ErrorList{},
+		},
+		"known condition types": {
+			conditions: []cminternal.CertificateRequestCondition{
+				{Type: cminternal.CertificateRequestConditionReady, Status: cmmeta.ConditionTrue},
+				{Type: cminternal.CertificateRequestConditionApproved, Status: cmmeta.ConditionTrue},
+				{Type: cminternal.CertificateRequestConditionDenied, Status: cmmeta.ConditionFalse},
+				{Type: cminternal.CertificateRequestConditionInvalidRequest, Status: cmmeta.ConditionFalse},
+			},
+			want: field.ErrorList{},
+		},
+		"unknown condition type": {
+			conditions: []cminternal.CertificateRequestCondition{
+				{Type: cminternal.CertificateRequestConditionReady, Status: cmmeta.ConditionTrue},
+				{Type: "Unknown", Status: cmmeta.ConditionTrue},
+			},
+			want: field.ErrorList{
+				field.NotSupported(fldPath.Child("conditions").Index(1).Child("type"), cminternal.CertificateRequestConditionType("Unknown"), supportedCertificateRequestConditionTypes),
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := ValidateCertificateRequestStatus(&cminternal.CertificateRequestStatus{Conditions: tt.conditions}, fldPath)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidateCertificateRequestStatus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func mustGenerateCSR(t *testing.T, crt *cmapi.Certificate) []byte {
 	// Create a new private key
 	pk, err := utilpki.GenerateRSAPrivateKey(2048)
@@ -28,11 +28,26 @@ import (
 func ValidateClusterIssuer(obj runtime.Object) field.ErrorList {
 	iss := obj.(*cmapi.ClusterIssuer)
 	allErrs := ValidateIssuerSpec(&iss.Spec, field.NewPath("spec"))
+	allErrs = append(allErrs, validateACMEServerScheme(iss.Annotations, &iss.Spec, field.NewPath("spec"))...)
 	return allErrs
 }
 
 func ValidateUpdateClusterIssuer(oldObj, obj runtime.Object) field.ErrorList {
 	iss := obj.(*cmapi.ClusterIssuer)
 	allErrs := ValidateIssuerSpec(&iss.Spec, field.NewPath("spec"))
+	allErrs = append(allErrs, validateACMEServerScheme(iss.Annotations, &iss.Spec, field.NewPath("spec"))...)
 	return allErrs
 }
+
+func WarnClusterIssuer(obj runtime.Object) []string {
+	iss := obj.(*cmapi.ClusterIssuer)
+	return WarnIssuerSpec(&iss.Spec, field.NewPath("spec"))
+}
+
+func WarnUpdateClusterIssuer(oldObj, obj runtime.Object) []string {
+	oldIss := oldObj.(*cmapi.ClusterIssuer)
+	iss := obj.(*cmapi.ClusterIssuer)
+	warnings := WarnIssuerSpec(&iss.Spec, field.NewPath("spec"))
+	warnings = append(warnings, WarnUpdateIssuerSpec(&oldIss.Spec, &iss.Spec, field.NewPath("spec"))...)
+	return warnings
+}
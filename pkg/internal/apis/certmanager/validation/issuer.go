@@ -18,33 +18,167 @@ package validation
 
 import (
 	"crypto/x509"
+	"encoding/hex"
 	"fmt"
+	"math"
+	"net"
+	"net/url"
+	"path"
+	"strconv"
 	"strings"
+	"time"
+	"unicode"
 
+	"github.com/aws/aws-sdk-go/aws/arn"
 	corev1 "k8s.io/api/core/v1"
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	cmacme "github.com/jetstack/cert-manager/pkg/internal/apis/acme"
 	"github.com/jetstack/cert-manager/pkg/internal/apis/certmanager"
 	"github.com/jetstack/cert-manager/pkg/internal/apis/certmanager/validation/util"
 	cmmeta "github.com/jetstack/cert-manager/pkg/internal/apis/meta"
 )
 
+// supportedEABKeyAlgorithms are the HMAC algorithms that may be used to sign
+// the external account binding JWS.
+var supportedEABKeyAlgorithms = []string{
+	string(cmacme.HS256),
+	string(cmacme.HS384),
+	string(cmacme.HS512),
+}
+
+// supportedSubjectKeyIdentifierMethods are the methods a CA issuer may use to
+// derive the subject key identifier of the certificates it signs.
+var supportedSubjectKeyIdentifierMethods = []string{
+	string(certmanager.SubjectKeyIdentifierMethodRFC5280),
+	string(certmanager.SubjectKeyIdentifierMethodRFC7093Method1),
+}
+
+// vaultSignPathSegments are the Vault PKI endpoints that a Vault issuer's
+// path is expected to name, followed by the name of a role.
+var vaultSignPathSegments = []string{"sign", "issue", "sign-verbatim"}
+
 // Validation functions for cert-manager v1alpha2 Issuer types
 
+// maxHTTP01ReadTimeoutSeconds is the maximum value that may be set for
+// an HTTP01 ingress solver's readTimeoutSeconds.
+const maxHTTP01ReadTimeoutSeconds = 300
+
+// caAuthorityKeyIdentifierLength is the required length in bytes of a CA
+// issuer's authorityKeyIdentifier, matching a SHA-1 key identifier as
+// described in RFC 5280, section 4.2.1.2.
+const caAuthorityKeyIdentifierLength = 20
+
+// caCRLMinimumDuration is the minimum validity period of a CRL published by
+// a CA issuer.
+const caCRLMinimumDuration = time.Hour
+
 func ValidateIssuer(obj runtime.Object) field.ErrorList {
 	iss := obj.(*certmanager.Issuer)
 	allErrs := ValidateIssuerSpec(&iss.Spec, field.NewPath("spec"))
+	allErrs = append(allErrs, validateACMEServerScheme(iss.Annotations, &iss.Spec, field.NewPath("spec"))...)
 	return allErrs
 }
 
 func ValidateUpdateIssuer(oldObj, obj runtime.Object) field.ErrorList {
 	iss := obj.(*certmanager.Issuer)
 	allErrs := ValidateIssuerSpec(&iss.Spec, field.NewPath("spec"))
+	allErrs = append(allErrs, validateACMEServerScheme(iss.Annotations, &iss.Spec, field.NewPath("spec"))...)
 	return allErrs
 }
 
+// validateACMEServerScheme returns an error if the ACME server of an Issuer
+// or ClusterIssuer is not a HTTPS URL, unless insecure ACME servers have been
+// allowed with an annotation on the issuer.
+func validateACMEServerScheme(annotations map[string]string, iss *certmanager.IssuerSpec, fldPath *field.Path) field.ErrorList {
+	if iss.ACME == nil || len(iss.ACME.Server) == 0 || annotations[cmapi.AllowInsecureACMEServerAnnotationKey] == "true" {
+		return nil
+	}
+	if u, err := url.Parse(iss.ACME.Server); err == nil && u.Scheme == "https" && len(u.Host) > 0 {
+		return nil
+	}
+	return field.ErrorList{field.Invalid(fldPath.Child("acme", "server"), iss.ACME.Server,
+		fmt.Sprintf("must be a HTTPS URL; set the %q annotation to \"true\" to allow an insecure ACME server", cmapi.AllowInsecureACMEServerAnnotationKey))}
+}
+
+func WarnIssuer(obj runtime.Object) []string {
+	iss := obj.(*certmanager.Issuer)
+	return WarnIssuerSpec(&iss.Spec, field.NewPath("spec"))
+}
+
+func WarnUpdateIssuer(oldObj, obj runtime.Object) []string {
+	oldIss := oldObj.(*certmanager.Issuer)
+	iss := obj.(*certmanager.Issuer)
+	warnings := WarnIssuerSpec(&iss.Spec, field.NewPath("spec"))
+	warnings = append(warnings, WarnUpdateIssuerSpec(&oldIss.Spec, &iss.Spec, field.NewPath("spec"))...)
+	return warnings
+}
+
+// WarnIssuerSpec returns warnings about an Issuer spec that is valid but
+// likely to be misconfigured.
+func WarnIssuerSpec(iss *certmanager.IssuerSpec, fldPath *field.Path) []string {
+	var warnings []string
+
+	// The Vault path must name a sign or issue endpoint of a PKI secrets
+	// engine, but Vault mounts may be named anything so this is only a warning
+	if iss.Vault != nil && len(iss.Vault.Path) > 0 && !isVaultSignPath(iss.Vault.Path) {
+		warnings = append(warnings, fmt.Sprintf("%s: %q does not look like a Vault PKI sign or issue endpoint such as \"pki/sign/<role>\", which may cause signing to fail", fldPath.Child("vault", "path"), iss.Vault.Path))
+	}
+
+	return warnings
+}
+
+// isVaultSignPath returns true if the given Vault path contains one of the
+// vaultSignPathSegments followed by a role name.
+func isVaultSignPath(vaultPath string) bool {
+	segments := strings.Split(strings.Trim(vaultPath, "/"), "/")
+	for i, segment := range segments[:len(segments)-1] {
+		for _, sign := range vaultSignPathSegments {
+			if segment == sign && len(segments[i+1]) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// WarnUpdateIssuerSpec returns warnings about changes to an Issuer spec that
+// are valid but may have unexpected side effects.
+func WarnUpdateIssuerSpec(oldIss, iss *certmanager.IssuerSpec, fldPath *field.Path) []string {
+	if oldIss.ACME == nil || iss.ACME == nil {
+		return nil
+	}
+	return warnACMESolversUpdate(oldIss.ACME.Solvers, iss.ACME.Solvers, fldPath.Child("acme", "solvers"))
+}
+
+// warnACMESolversUpdate returns warnings for HTTP01 solvers whose ingress
+// name has changed, as solver routes added to the previously named ingress
+// for in-progress challenges will not be cleaned up.
+func warnACMESolversUpdate(oldSolvers, solvers []cmacme.ACMEChallengeSolver, fldPath *field.Path) []string {
+	var warnings []string
+	for i := range solvers {
+		if i >= len(oldSolvers) {
+			break
+		}
+		oldName, name := http01IngressName(oldSolvers[i]), http01IngressName(solvers[i])
+		if oldName != "" && oldName != name {
+			warnings = append(warnings, fmt.Sprintf("%s: changing the ingress name may leave stale challenge solver routes on ingress %q, which must be removed manually", fldPath.Index(i).Child("http01", "ingress", "name"), oldName))
+		}
+	}
+	return warnings
+}
+
+func http01IngressName(solver cmacme.ACMEChallengeSolver) string {
+	if solver.HTTP01 == nil || solver.HTTP01.Ingress == nil {
+		return ""
+	}
+	return solver.HTTP01.Ingress.Name
+}
+
 func ValidateIssuerSpec(iss *certmanager.IssuerSpec, fldPath *field.Path) field.ErrorList {
 	return ValidateIssuerConfig(&iss.IssuerConfig, fldPath)
 }
@@ -92,6 +226,14 @@ func ValidateIssuerConfig(iss *certmanager.IssuerConfig, fldPath *field.Path) fi
 			el = append(el, ValidateVenafiIssuerConfig(iss.Venafi, fldPath.Child("venafi"))...)
 		}
 	}
+	if iss.AWSPCA != nil {
+		if numConfigs > 0 {
+			el = append(el, field.Forbidden(fldPath.Child("awsPCA"), "may not specify more than one issuer type"))
+		} else {
+			numConfigs++
+			el = append(el, ValidateAWSPCAIssuerConfig(iss.AWSPCA, fldPath.Child("awsPCA"))...)
+		}
+	}
 	if numConfigs == 0 {
 		el = append(el, field.Required(fldPath, "at least one issuer must be configured"))
 	}
@@ -116,8 +258,12 @@ func ValidateACMEIssuerConfig(iss *cmacme.ACMEIssuer, fldPath *field.Path) field
 
 		el = append(el, ValidateSecretKeySelector(&eab.Key, eabFldPath.Child("keySecretRef"))...)
 
-		if len(eab.KeyAlgorithm) == 0 {
+		switch eab.KeyAlgorithm {
+		case "":
 			el = append(el, field.Required(eabFldPath.Child("keyAlgorithm"), "the keyAlgorithm field is required when using externalAccountBinding"))
+		case cmacme.HS256, cmacme.HS384, cmacme.HS512:
+		default:
+			el = append(el, field.NotSupported(eabFldPath.Child("keyAlgorithm"), eab.KeyAlgorithm, supportedEABKeyAlgorithms))
 		}
 	}
 
@@ -125,6 +271,16 @@ func ValidateACMEIssuerConfig(iss *cmacme.ACMEIssuer, fldPath *field.Path) field
 		el = append(el, ValidateACMEIssuerChallengeSolverConfig(&sol, fldPath.Child("solvers").Index(i))...)
 	}
 
+	for i, domain := range iss.AllowedDomains {
+		if len(domain) == 0 {
+			el = append(el, field.Invalid(fldPath.Child("allowedDomains").Index(i), domain, "must not be empty"))
+			continue
+		}
+		if _, err := path.Match(domain, ""); err != nil {
+			el = append(el, field.Invalid(fldPath.Child("allowedDomains").Index(i), domain, fmt.Sprintf("invalid glob pattern: %v", err)))
+		}
+	}
+
 	return el
 }
 
@@ -147,6 +303,21 @@ func ValidateACMEIssuerChallengeSolverConfig(sol *cmacme.ACMEChallengeSolver, fl
 	if numProviders == 0 {
 		el = append(el, field.Required(fldPath, "no solver type configured"))
 	}
+	if sol.Selector != nil && sol.Selector.NamespaceSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(sol.Selector.NamespaceSelector); err != nil {
+			el = append(el, field.Invalid(fldPath.Child("selector", "namespaceSelector"), sol.Selector.NamespaceSelector, err.Error()))
+		}
+	}
+	if sol.Selector != nil && len(sol.Selector.MatchAnnotations) > 0 {
+		el = append(el, apimachineryvalidation.ValidateAnnotations(sol.Selector.MatchAnnotations, fldPath.Child("selector", "matchAnnotations"))...)
+	}
+	if sol.Selector != nil && sol.Selector.IdentifierType != "" {
+		switch sol.Selector.IdentifierType {
+		case cmacme.ACMEIdentifierTypeDNS, cmacme.ACMEIdentifierTypeIP:
+		default:
+			el = append(el, field.NotSupported(fldPath.Child("selector", "identifierType"), sol.Selector.IdentifierType, []string{cmacme.ACMEIdentifierTypeDNS, cmacme.ACMEIdentifierTypeIP}))
+		}
+	}
 
 	return el
 }
@@ -177,6 +348,24 @@ func ValidateACMEIssuerChallengeSolverHTTP01IngressConfig(ingress *cmacme.ACMECh
 	default:
 		el = append(el, field.Invalid(fldPath.Child("serviceType"), ingress.ServiceType, `must be empty, "ClusterIP" or "NodePort"`))
 	}
+	switch ingress.ServiceIPFamily {
+	case "", corev1.IPv4Protocol, corev1.IPv6Protocol:
+	default:
+		el = append(el, field.NotSupported(fldPath.Child("serviceIPFamily"), ingress.ServiceIPFamily, []string{string(corev1.IPv4Protocol), string(corev1.IPv6Protocol)}))
+	}
+	if ingress.ReadTimeoutSeconds != nil {
+		if t := *ingress.ReadTimeoutSeconds; t <= 0 || t > maxHTTP01ReadTimeoutSeconds {
+			el = append(el, field.Invalid(fldPath.Child("readTimeoutSeconds"), t, fmt.Sprintf("must be between 1 and %d", maxHTTP01ReadTimeoutSeconds)))
+		}
+	}
+	if ingress.PodTemplate != nil {
+		// an empty image falls back to the solver image configured on the
+		// controller, so only reject values that cannot be an image reference
+		if image := ingress.PodTemplate.Spec.Image; len(image) > 0 && strings.IndexFunc(image, unicode.IsSpace) >= 0 {
+			el = append(el, field.Invalid(fldPath.Child("podTemplate", "spec", "image"), image, "must be a non-empty image reference that does not contain whitespace"))
+		}
+		el = append(el, validateHTTP01SolverPodScheduling(&ingress.PodTemplate.Spec, fldPath.Child("podTemplate", "spec"))...)
+	}
 
 	return el
 }
@@ -187,15 +376,89 @@ func ValidateCAIssuerConfig(iss *certmanager.CAIssuer, fldPath *field.Path) fiel
 		el = append(el, field.Required(fldPath.Child("secretName"), ""))
 	}
 	for i, ocspURL := range iss.OCSPServers {
-		if ocspURL == "" {
+		if invalidURLReason(ocspURL, "http", "https") != "" {
 			el = append(el, field.Invalid(fldPath.Child("ocspServer").Index(i), ocspURL, "must be a valid URL, e.g., http://ocsp.int-x3.letsencrypt.org"))
 		}
 	}
+	el = append(el, validateURLs(iss.IssuingCertificateURLs, fldPath.Child("issuingCertificateURLs"), "http://ca.example.com/ca.crt", "http", "https", "ldap")...)
+	el = append(el, validateURLs(iss.CRLDistributionPoints, fldPath.Child("crlDistributionPoints"), "http://crl.example.com/ca.crl", "http", "https", "ldap")...)
+	el = append(el, validateURLs(iss.FreshestCRLDistributionPoints, fldPath.Child("freshestCRLDistributionPoints"), "http://crl.example.com/delta.crl", "http", "https", "ldap")...)
+	if len(iss.AuthorityKeyIdentifier) > 0 {
+		aki, err := hex.DecodeString(iss.AuthorityKeyIdentifier)
+		if err != nil {
+			el = append(el, field.Invalid(fldPath.Child("authorityKeyIdentifier"), iss.AuthorityKeyIdentifier, fmt.Sprintf("must be hex encoded: %v", err)))
+		} else if len(aki) != caAuthorityKeyIdentifierLength {
+			el = append(el, field.Invalid(fldPath.Child("authorityKeyIdentifier"), iss.AuthorityKeyIdentifier, fmt.Sprintf("must be %d bytes long, got %d", caAuthorityKeyIdentifierLength, len(aki))))
+		}
+	}
+	if iss.CRL != nil {
+		el = append(el, validateCAIssuerCRL(iss.CRL, iss.SecretName, fldPath.Child("crl"))...)
+	}
+	switch iss.SubjectKeyIdentifierMethod {
+	case "", certmanager.SubjectKeyIdentifierMethodRFC5280, certmanager.SubjectKeyIdentifierMethodRFC7093Method1:
+	default:
+		el = append(el, field.NotSupported(fldPath.Child("subjectKeyIdentifierMethod"), iss.SubjectKeyIdentifierMethod, supportedSubjectKeyIdentifierMethods))
+	}
+	return el
+}
+
+// validateCAIssuerCRL checks that the CRL Secret does not overwrite the CA
+// Secret, and that the CRL is valid for long enough for relying parties to
+// fetch it.
+func validateCAIssuerCRL(crl *certmanager.CAIssuerCRL, caSecretName string, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+	if len(crl.SecretName) == 0 {
+		el = append(el, field.Required(fldPath.Child("secretName"), ""))
+	} else if crl.SecretName == caSecretName {
+		el = append(el, field.Invalid(fldPath.Child("secretName"), crl.SecretName, "must not be the same as the CA secretName"))
+	}
+	if crl.Duration != nil && crl.Duration.Duration < caCRLMinimumDuration {
+		el = append(el, field.Invalid(fldPath.Child("duration"), crl.Duration.Duration, fmt.Sprintf("must be at least %s", caCRLMinimumDuration)))
+	}
 	return el
 }
 
 func ValidateSelfSignedIssuerConfig(iss *certmanager.SelfSignedIssuer, fldPath *field.Path) field.ErrorList {
-	return nil
+	return validateURLs(iss.CRLDistributionPoints, fldPath.Child("crlDistributionPoints"), "http://crl.example.com/ca.crl", "http", "https", "ldap")
+}
+
+// validateURLs checks that each of the given values is an absolute URL using
+// one of the given schemes, such that relying parties are able to fetch the
+// resource it refers to. The example is included in any error messages.
+func validateURLs(values []string, fldPath *field.Path, example string, schemes ...string) field.ErrorList {
+	el := field.ErrorList{}
+	for i, value := range values {
+		if reason := invalidURLReason(value, schemes...); reason != "" {
+			el = append(el, field.Invalid(fldPath.Index(i), value, fmt.Sprintf("%s, e.g., %s", reason, example)))
+		}
+	}
+	return el
+}
+
+// invalidURLReason returns the reason that value is not an absolute URL using
+// one of the given schemes, or an empty string if it is valid. URLs using the
+// http or https schemes must also include a host.
+func invalidURLReason(value string, schemes ...string) string {
+	u, err := url.Parse(value)
+	if err != nil {
+		return fmt.Sprintf("must be a valid URL: %v", err)
+	}
+
+	supported := false
+	for _, scheme := range schemes {
+		if u.Scheme == scheme {
+			supported = true
+		}
+	}
+	if !supported {
+		return fmt.Sprintf("must be an %s or %s URL", strings.Join(schemes[:len(schemes)-1], ", "), schemes[len(schemes)-1])
+	}
+
+	if (u.Scheme == "http" || u.Scheme == "https") && u.Host == "" {
+		return "must include a host"
+	}
+
+	return ""
 }
 
 func ValidateVaultIssuerConfig(iss *certmanager.VaultIssuer, fldPath *field.Path) field.ErrorList {
@@ -253,6 +516,44 @@ func ValidateVenafiIssuerConfig(iss *certmanager.VenafiIssuer, fldPath *field.Pa
 		el = append(el, field.Forbidden(fldPath, "please supply one of: tpp, cloud"))
 	}
 
+	for i, f := range iss.CustomFields {
+		if f.Name == "" {
+			el = append(el, field.Required(fldPath.Child("customFields").Index(i).Child("name"), "must be specified"))
+		}
+	}
+
+	return el
+}
+
+func ValidateAWSPCAIssuerConfig(iss *certmanager.AWSPCAIssuer, fldPath *field.Path) (el field.ErrorList) {
+	if len(iss.ARN) == 0 {
+		el = append(el, field.Required(fldPath.Child("arn"), ""))
+		return el
+	}
+
+	caARN, err := arn.Parse(iss.ARN)
+	if err != nil {
+		el = append(el, field.Invalid(fldPath.Child("arn"), iss.ARN, err.Error()))
+		return el
+	}
+	if caARN.Service != "acm-pca" || !strings.HasPrefix(caARN.Resource, "certificate-authority/") {
+		el = append(el, field.Invalid(fldPath.Child("arn"), iss.ARN, "must be the ARN of an ACM PCA certificate authority"))
+	}
+	if len(caARN.Region) == 0 {
+		el = append(el, field.Invalid(fldPath.Child("arn"), iss.ARN, "must contain a region"))
+	} else if len(iss.Region) > 0 && iss.Region != caARN.Region {
+		el = append(el, field.Invalid(fldPath.Child("region"), iss.Region, fmt.Sprintf("must match the region of the ARN (%s)", caARN.Region)))
+	}
+
+	// ambient credentials may be used instead, but if either of the
+	// access key ID or secret access key are set then both must be
+	if len(iss.AccessKeyID) > 0 || len(iss.SecretAccessKey.Name) > 0 {
+		if len(iss.AccessKeyID) == 0 {
+			el = append(el, field.Required(fldPath.Child("accessKeyID"), "must be set when secretAccessKeySecretRef is set"))
+		}
+		el = append(el, ValidateSecretKeySelector(&iss.SecretAccessKey, fldPath.Child("secretAccessKeySecretRef"))...)
+	}
+
 	return el
 }
 
@@ -277,6 +578,12 @@ func ValidateACMEChallengeSolverDNS01(p *cmacme.ACMEChallengeSolverDNS01, fldPat
 			el = append(el, field.Invalid(fldPath.Child("cnameStrategy"), p.CNAMEStrategy, fmt.Sprintf("must be one of %q or %q", cmacme.NoneStrategy, cmacme.FollowStrategy)))
 		}
 	}
+	for i, ns := range p.SelfCheckNameservers {
+		if !isValidHostPort(ns) {
+			el = append(el, field.Invalid(fldPath.Child("selfCheckNameservers").Index(i), ns, "nameserver must be set in the form host:port where host is an IPv4 address, an enclosed IPv6 address or a hostname and port is a port number"))
+		}
+	}
+	el = append(el, validateDNS01RecordTTL(p, fldPath.Child("recordTTL"))...)
 	numProviders := 0
 	if p.Akamai != nil {
 		numProviders++
@@ -387,6 +694,23 @@ func ValidateACMEChallengeSolverDNS01(p *cmacme.ACMEChallengeSolverDNS01, fldPat
 			el = append(el, ValidateSecretKeySelector(&p.DigitalOcean.Token, fldPath.Child("digitalocean", "tokenSecretRef"))...)
 		}
 	}
+	if p.Gandi != nil {
+		if numProviders > 0 {
+			el = append(el, field.Forbidden(fldPath.Child("gandi"), "may not specify more than one provider type"))
+		} else {
+			numProviders++
+			el = append(el, ValidateSecretKeySelector(&p.Gandi.Token, fldPath.Child("gandi", "tokenSecretRef"))...)
+		}
+	}
+	if p.Porkbun != nil {
+		if numProviders > 0 {
+			el = append(el, field.Forbidden(fldPath.Child("porkbun"), "may not specify more than one provider type"))
+		} else {
+			numProviders++
+			el = append(el, ValidateSecretKeySelector(&p.Porkbun.APIKey, fldPath.Child("porkbun", "apiKeySecretRef"))...)
+			el = append(el, ValidateSecretKeySelector(&p.Porkbun.SecretAPIKey, fldPath.Child("porkbun", "secretApiKeySecretRef"))...)
+		}
+	}
 	if p.RFC2136 != nil {
 		if numProviders > 0 {
 			el = append(el, field.Forbidden(fldPath.Child("rfc2136"), "may not specify more than one provider type"))
@@ -431,6 +755,11 @@ func ValidateACMEChallengeSolverDNS01(p *cmacme.ACMEChallengeSolverDNS01, fldPat
 			if len(p.Webhook.SolverName) == 0 {
 				el = append(el, field.Required(fldPath.Child("webhook", "solverName"), "solver name must be specified"))
 			}
+			if validate := webhookConfigValidatorFor(p.Webhook.GroupName, p.Webhook.SolverName); validate != nil {
+				if err := validate(p.Webhook.Config); err != nil {
+					el = append(el, field.Invalid(fldPath.Child("webhook", "config"), p.Webhook.Config, err.Error()))
+				}
+			}
 		}
 	}
 	if numProviders == 0 {
@@ -440,6 +769,50 @@ func ValidateACMEChallengeSolverDNS01(p *cmacme.ACMEChallengeSolverDNS01, fldPat
 	return el
 }
 
+// validateDNS01RecordTTL validates that the recordTTL of a DNS01 solver is
+// within the range of TTLs accepted by the configured provider, where it is
+// known.
+func validateDNS01RecordTTL(p *cmacme.ACMEChallengeSolverDNS01, fldPath *field.Path) field.ErrorList {
+	if p.RecordTTL == nil {
+		return nil
+	}
+
+	min, max := int32(1), int32(math.MaxInt32)
+	switch {
+	case p.AcmeDNS != nil, p.RFC2136 != nil, p.Webhook != nil:
+		return field.ErrorList{field.Forbidden(fldPath, "recordTTL is not supported by the acmeDNS, rfc2136 and webhook providers")}
+	case p.Cloudflare != nil:
+		// TTLs below 60 seconds are only accepted for enterprise zones
+		min, max = 30, 86400
+	case p.DigitalOcean != nil:
+		min = 30
+	case p.Gandi != nil:
+		min, max = 300, 2592000
+	case p.Porkbun != nil:
+		min = 600
+	}
+
+	if ttl := *p.RecordTTL; ttl < min || ttl > max {
+		return field.ErrorList{field.Invalid(fldPath, ttl, fmt.Sprintf("must be between %d and %d seconds for the configured provider", min, max))}
+	}
+
+	return nil
+}
+
+// isValidHostPort returns true if the given address is of the form host:port,
+// with a non-empty host and a port number between 1 and 65535.
+func isValidHostPort(addr string) bool {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil || host == "" || strings.IndexFunc(host, unicode.IsSpace) >= 0 {
+		return false
+	}
+	p, err := strconv.Atoi(port)
+	if err != nil {
+		return false
+	}
+	return p > 0 && p <= 65535
+}
+
 func ValidateSecretKeySelector(sks *cmmeta.SecretKeySelector, fldPath *field.Path) field.ErrorList {
 	el := field.ErrorList{}
 	if sks.Name == "" {
@@ -0,0 +1,43 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+// This file defines a hook that allows the issuer referenced by a resource to
+// be resolved during validation, so that issuer level requirements can be
+// enforced.
+
+import (
+	internalcmapi "github.com/jetstack/cert-manager/pkg/internal/apis/certmanager"
+	cmmeta "github.com/jetstack/cert-manager/pkg/internal/apis/meta"
+)
+
+// IssuerResolver returns the issuer referenced by ref from a resource in the
+// given namespace, or an error if the issuer cannot be resolved.
+type IssuerResolver func(namespace string, ref cmmeta.ObjectReference) (internalcmapi.GenericIssuer, error)
+
+// resolveIssuer returns the issuer referenced by ref, or nil if the policy has
+// no resolver or the issuer cannot be resolved.
+func (p *CertificatePolicy) resolveIssuer(namespace string, ref cmmeta.ObjectReference) internalcmapi.GenericIssuer {
+	if p.IssuerResolver == nil {
+		return nil
+	}
+	issuerObj, err := p.IssuerResolver(namespace, ref)
+	if err != nil {
+		return nil
+	}
+	return issuerObj
+}
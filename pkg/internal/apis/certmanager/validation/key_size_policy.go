@@ -0,0 +1,69 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	internalcmapi "github.com/jetstack/cert-manager/pkg/internal/apis/certmanager"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+func validateKeySizePolicy(minRSA, minECDSA int, crt *internalcmapi.CertificateSpec, fldPath *field.Path) field.ErrorList {
+	algorithm, size := privateKeyAlgorithmAndSize(crt.PrivateKey)
+	var specSize int
+	if crt.PrivateKey != nil {
+		specSize = crt.PrivateKey.Size
+	}
+
+	switch {
+	case algorithm == internalcmapi.RSAKeyAlgorithm && minRSA > 0 && size < minRSA:
+		return field.ErrorList{
+			field.Invalid(fldPath.Child("privateKey", "size"), specSize, fmt.Sprintf("must be at least %d for rsa keyAlgorithm (defaults to %d if not set)", minRSA, pki.MinRSAKeySize)),
+		}
+	case algorithm == internalcmapi.ECDSAKeyAlgorithm && minECDSA > 0 && size < minECDSA:
+		return field.ErrorList{
+			field.Invalid(fldPath.Child("privateKey", "size"), specSize, fmt.Sprintf("must be at least %d for ecdsa keyAlgorithm (defaults to %d if not set)", minECDSA, pki.ECCurve256)),
+		}
+	}
+	return nil
+}
+
+// privateKeyAlgorithmAndSize returns the algorithm and size of the private key
+// that will be generated for the given private key parameters, taking the
+// defaults into account.
+func privateKeyAlgorithmAndSize(pk *internalcmapi.CertificatePrivateKey) (internalcmapi.PrivateKeyAlgorithm, int) {
+	algorithm, size := internalcmapi.RSAKeyAlgorithm, 0
+	if pk != nil {
+		size = pk.Size
+		if pk.Algorithm != "" {
+			algorithm = pk.Algorithm
+		}
+	}
+
+	if size == 0 {
+		switch algorithm {
+		case internalcmapi.RSAKeyAlgorithm:
+			size = pki.MinRSAKeySize
+		case internalcmapi.ECDSAKeyAlgorithm:
+			size = pki.ECCurve256
+		}
+	}
+	return algorithm, size
+}
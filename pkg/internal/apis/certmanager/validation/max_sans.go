@@ -0,0 +1,51 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	internalcmapi "github.com/jetstack/cert-manager/pkg/internal/apis/certmanager"
+)
+
+// DefaultACMEMaxSubjectAltNames is the maximum number of subject alternative
+// names enforced for Certificates that reference an ACME issuer when no
+// maximum has been configured. It matches the limit imposed by Let's Encrypt.
+const DefaultACMEMaxSubjectAltNames = 100
+
+// validateSubjectAltNameCount returns an error if the Certificate requests
+// more than max subject alternative names. If max is 0,
+// DefaultACMEMaxSubjectAltNames is enforced if issuerObj is an ACME issuer.
+// issuerObj may be nil if the referenced issuer could not be resolved.
+func validateSubjectAltNameCount(max int, crt *internalcmapi.CertificateSpec, issuerObj internalcmapi.GenericIssuer, fldPath *field.Path) field.ErrorList {
+	if max <= 0 && issuerObj != nil && issuerObj.GetSpec().ACME != nil {
+		max = DefaultACMEMaxSubjectAltNames
+	}
+	if max <= 0 {
+		return nil
+	}
+
+	count := len(crt.DNSNames) + len(crt.IPAddresses) + len(crt.URISANs) + len(crt.EmailSANs)
+	if count > max {
+		return field.ErrorList{
+			field.Invalid(fldPath, count, fmt.Sprintf("the total number of dnsNames, ipAddresses, uris and emailAddresses must be no more than %d", max)),
+		}
+	}
+	return nil
+}
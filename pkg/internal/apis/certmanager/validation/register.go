@@ -28,6 +28,12 @@ func AddToValidationRegistry(reg *validation.Registry) error {
 	if err := reg.AddValidateUpdateFunc(&cmapi.Certificate{}, ValidateUpdateCertificate); err != nil {
 		return err
 	}
+	if err := reg.AddWarnFunc(&cmapi.Certificate{}, WarnCertificate); err != nil {
+		return err
+	}
+	if err := reg.AddWarnUpdateFunc(&cmapi.Certificate{}, WarnUpdateCertificate); err != nil {
+		return err
+	}
 
 	if err := reg.AddValidateFunc(&cmapi.CertificateRequest{}, ValidateCertificateRequest); err != nil {
 		return err
@@ -42,6 +48,12 @@ func AddToValidationRegistry(reg *validation.Registry) error {
 	if err := reg.AddValidateUpdateFunc(&cmapi.ClusterIssuer{}, ValidateUpdateClusterIssuer); err != nil {
 		return err
 	}
+	if err := reg.AddWarnFunc(&cmapi.ClusterIssuer{}, WarnClusterIssuer); err != nil {
+		return err
+	}
+	if err := reg.AddWarnUpdateFunc(&cmapi.ClusterIssuer{}, WarnUpdateClusterIssuer); err != nil {
+		return err
+	}
 
 	if err := reg.AddValidateFunc(&cmapi.Issuer{}, ValidateIssuer); err != nil {
 		return err
@@ -49,5 +61,11 @@ func AddToValidationRegistry(reg *validation.Registry) error {
 	if err := reg.AddValidateUpdateFunc(&cmapi.Issuer{}, ValidateUpdateIssuer); err != nil {
 		return err
 	}
+	if err := reg.AddWarnFunc(&cmapi.Issuer{}, WarnIssuer); err != nil {
+		return err
+	}
+	if err := reg.AddWarnUpdateFunc(&cmapi.Issuer{}, WarnUpdateIssuer); err != nil {
+		return err
+	}
 	return nil
 }
@@ -0,0 +1,39 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"fmt"
+	"regexp"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	internalcmapi "github.com/jetstack/cert-manager/pkg/internal/apis/certmanager"
+)
+
+func validateSecretNamePolicy(pattern *regexp.Regexp, crt *internalcmapi.CertificateSpec, fldPath *field.Path) field.ErrorList {
+	if pattern == nil || len(crt.SecretName) == 0 {
+		return nil
+	}
+
+	if !pattern.MatchString(crt.SecretName) {
+		return field.ErrorList{
+			field.Invalid(fldPath.Child("secretName"), crt.SecretName, fmt.Sprintf("must match the pattern %q", pattern.String())),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/internal/apis/acme"
+)
+
+var (
+	supportedTolerationOperators = []string{string(corev1.TolerationOpEqual), string(corev1.TolerationOpExists)}
+	supportedTaintEffects        = []string{string(corev1.TaintEffectNoSchedule), string(corev1.TaintEffectPreferNoSchedule), string(corev1.TaintEffectNoExecute)}
+	supportedNodeSelectorOps     = []string{
+		string(corev1.NodeSelectorOpIn), string(corev1.NodeSelectorOpNotIn),
+		string(corev1.NodeSelectorOpExists), string(corev1.NodeSelectorOpDoesNotExist),
+		string(corev1.NodeSelectorOpGt), string(corev1.NodeSelectorOpLt),
+	}
+)
+
+// validateHTTP01SolverPodScheduling validates the nodeSelector, tolerations
+// and affinity of a HTTP01 solver pod template, so that a misconfiguration is
+// reported when the issuer is created rather than when the solver pod is.
+func validateHTTP01SolverPodScheduling(spec *cmacme.ACMEChallengeSolverHTTP01IngressPodSpec, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+	el = append(el, metav1validation.ValidateLabels(spec.NodeSelector, fldPath.Child("nodeSelector"))...)
+	for i, toleration := range spec.Tolerations {
+		el = append(el, validateToleration(toleration, fldPath.Child("tolerations").Index(i))...)
+	}
+	if spec.Affinity != nil {
+		el = append(el, validateAffinity(spec.Affinity, fldPath.Child("affinity"))...)
+	}
+	return el
+}
+
+func validateToleration(toleration corev1.Toleration, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+	if len(toleration.Key) > 0 {
+		for _, msg := range validation.IsQualifiedName(toleration.Key) {
+			el = append(el, field.Invalid(fldPath.Child("key"), toleration.Key, msg))
+		}
+	}
+
+	switch toleration.Operator {
+	case corev1.TolerationOpEqual, "":
+		if len(toleration.Key) == 0 {
+			el = append(el, field.Invalid(fldPath.Child("operator"), toleration.Operator, "operator must be Exists when key is empty"))
+		}
+		for _, msg := range validation.IsValidLabelValue(toleration.Value) {
+			el = append(el, field.Invalid(fldPath.Child("value"), toleration.Value, msg))
+		}
+	case corev1.TolerationOpExists:
+		if len(toleration.Value) > 0 {
+			el = append(el, field.Invalid(fldPath.Child("value"), toleration.Value, "value must be empty when operator is Exists"))
+		}
+	default:
+		el = append(el, field.NotSupported(fldPath.Child("operator"), toleration.Operator, supportedTolerationOperators))
+	}
+
+	switch toleration.Effect {
+	case "", corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+	default:
+		el = append(el, field.NotSupported(fldPath.Child("effect"), toleration.Effect, supportedTaintEffects))
+	}
+	if toleration.TolerationSeconds != nil && toleration.Effect != corev1.TaintEffectNoExecute {
+		el = append(el, field.Invalid(fldPath.Child("effect"), toleration.Effect, "effect must be NoExecute when tolerationSeconds is set"))
+	}
+	return el
+}
+
+func validateAffinity(affinity *corev1.Affinity, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+	if na := affinity.NodeAffinity; na != nil {
+		naPath := fldPath.Child("nodeAffinity")
+		if required := na.RequiredDuringSchedulingIgnoredDuringExecution; required != nil {
+			termsPath := naPath.Child("requiredDuringSchedulingIgnoredDuringExecution", "nodeSelectorTerms")
+			if len(required.NodeSelectorTerms) == 0 {
+				el = append(el, field.Required(termsPath, "must have at least one node selector term"))
+			}
+			for i, term := range required.NodeSelectorTerms {
+				el = append(el, validateNodeSelectorTerm(term, termsPath.Index(i))...)
+			}
+		}
+		for i, term := range na.PreferredDuringSchedulingIgnoredDuringExecution {
+			termPath := naPath.Child("preferredDuringSchedulingIgnoredDuringExecution").Index(i)
+			el = append(el, validateWeight(term.Weight, termPath.Child("weight"))...)
+			el = append(el, validateNodeSelectorTerm(term.Preference, termPath.Child("preference"))...)
+		}
+	}
+	if pa := affinity.PodAffinity; pa != nil {
+		el = append(el, validatePodAffinityTerms(pa.RequiredDuringSchedulingIgnoredDuringExecution, pa.PreferredDuringSchedulingIgnoredDuringExecution, fldPath.Child("podAffinity"))...)
+	}
+	if paa := affinity.PodAntiAffinity; paa != nil {
+		el = append(el, validatePodAffinityTerms(paa.RequiredDuringSchedulingIgnoredDuringExecution, paa.PreferredDuringSchedulingIgnoredDuringExecution, fldPath.Child("podAntiAffinity"))...)
+	}
+	return el
+}
+
+func validateNodeSelectorTerm(term corev1.NodeSelectorTerm, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+	for i, req := range term.MatchExpressions {
+		reqPath := fldPath.Child("matchExpressions").Index(i)
+		for _, msg := range validation.IsQualifiedName(req.Key) {
+			el = append(el, field.Invalid(reqPath.Child("key"), req.Key, msg))
+		}
+		switch req.Operator {
+		case corev1.NodeSelectorOpIn, corev1.NodeSelectorOpNotIn:
+			if len(req.Values) == 0 {
+				el = append(el, field.Required(reqPath.Child("values"), "must be specified when operator is In or NotIn"))
+			}
+		case corev1.NodeSelectorOpExists, corev1.NodeSelectorOpDoesNotExist:
+			if len(req.Values) > 0 {
+				el = append(el, field.Forbidden(reqPath.Child("values"), "may not be specified when operator is Exists or DoesNotExist"))
+			}
+		case corev1.NodeSelectorOpGt, corev1.NodeSelectorOpLt:
+			if len(req.Values) != 1 {
+				el = append(el, field.Required(reqPath.Child("values"), "must be specified with a single value when operator is Gt or Lt"))
+			}
+		default:
+			el = append(el, field.NotSupported(reqPath.Child("operator"), req.Operator, supportedNodeSelectorOps))
+		}
+	}
+	return el
+}
+
+func validatePodAffinityTerms(required []corev1.PodAffinityTerm, preferred []corev1.WeightedPodAffinityTerm, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+	for i, term := range required {
+		el = append(el, validatePodAffinityTerm(term, fldPath.Child("requiredDuringSchedulingIgnoredDuringExecution").Index(i))...)
+	}
+	for i, term := range preferred {
+		termPath := fldPath.Child("preferredDuringSchedulingIgnoredDuringExecution").Index(i)
+		el = append(el, validateWeight(term.Weight, termPath.Child("weight"))...)
+		el = append(el, validatePodAffinityTerm(term.PodAffinityTerm, termPath.Child("podAffinityTerm"))...)
+	}
+	return el
+}
+
+func validatePodAffinityTerm(term corev1.PodAffinityTerm, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+	el = append(el, metav1validation.ValidateLabelSelector(term.LabelSelector, fldPath.Child("labelSelector"))...)
+	if len(term.TopologyKey) == 0 {
+		el = append(el, field.Required(fldPath.Child("topologyKey"), "can not be empty"))
+	} else {
+		for _, msg := range validation.IsQualifiedName(term.TopologyKey) {
+			el = append(el, field.Invalid(fldPath.Child("topologyKey"), term.TopologyKey, msg))
+		}
+	}
+	for i, ns := range term.Namespaces {
+		for _, msg := range validation.IsDNS1123Label(ns) {
+			el = append(el, field.Invalid(fldPath.Child("namespaces").Index(i), ns, msg))
+		}
+	}
+	return el
+}
+
+func validateWeight(weight int32, fldPath *field.Path) field.ErrorList {
+	if weight < 1 || weight > 100 {
+		return field.ErrorList{field.Invalid(fldPath, weight, "must be in the range 1-100")}
+	}
+	return nil
+}
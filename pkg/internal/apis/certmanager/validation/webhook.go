@@ -0,0 +1,60 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+// This file defines types for DNS01 webhook solvers to register a validator
+// for their config with the validation package.
+
+import (
+	"sync"
+
+	apiext "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+)
+
+// WebhookConfigValidator validates the config of a DNS01 webhook solver
+// against the solver's schema. It returns an error describing why the config
+// does not conform to the schema, or nil if it does.
+// The config is nil if no config is specified on the solver.
+type WebhookConfigValidator func(config *apiext.JSON) error
+
+type webhookSolverKey struct {
+	groupName  string
+	solverName string
+}
+
+var (
+	webhookConfigValidatorsLock sync.RWMutex
+	webhookConfigValidators     = make(map[webhookSolverKey]WebhookConfigValidator)
+)
+
+// RegisterWebhookConfigValidator registers a validator for the config of the
+// DNS01 webhook solver with the given group name and solver name. Issuers
+// using that solver will have their config validated by fn.
+func RegisterWebhookConfigValidator(groupName, solverName string, fn WebhookConfigValidator) {
+	webhookConfigValidatorsLock.Lock()
+	defer webhookConfigValidatorsLock.Unlock()
+	webhookConfigValidators[webhookSolverKey{groupName: groupName, solverName: solverName}] = fn
+}
+
+// webhookConfigValidatorFor returns the validator registered for the DNS01
+// webhook solver with the given group name and solver name, or nil if none
+// has been registered.
+func webhookConfigValidatorFor(groupName, solverName string) WebhookConfigValidator {
+	webhookConfigValidatorsLock.RLock()
+	defer webhookConfigValidatorsLock.RUnlock()
+	return webhookConfigValidators[webhookSolverKey{groupName: groupName, solverName: solverName}]
+}
@@ -26,8 +26,9 @@ import (
 )
 
 type Vault struct {
-	NewFn  func(string, corelisters.SecretLister, v1.GenericIssuer) (*Vault, error)
-	SignFn func([]byte, time.Duration) ([]byte, []byte, error)
+	NewFn          func(string, corelisters.SecretLister, v1.GenericIssuer) (*Vault, error)
+	SignFn         func([]byte, time.Duration) ([]byte, []byte, error)
+	SignVerbatimFn func([]byte, time.Duration) ([]byte, []byte, error)
 }
 
 func New() *Vault {
@@ -35,6 +36,9 @@ func New() *Vault {
 		SignFn: func([]byte, time.Duration) ([]byte, []byte, error) {
 			return nil, nil, nil
 		},
+		SignVerbatimFn: func([]byte, time.Duration) ([]byte, []byte, error) {
+			return nil, nil, nil
+		},
 	}
 
 	v.NewFn = func(string, corelisters.SecretLister, v1.GenericIssuer) (*Vault, error) {
@@ -48,6 +52,17 @@ func (v *Vault) Sign(csrPEM []byte, duration time.Duration) ([]byte, []byte, err
 	return v.SignFn(csrPEM, duration)
 }
 
+func (v *Vault) SignVerbatim(csrPEM []byte, duration time.Duration) ([]byte, []byte, error) {
+	return v.SignVerbatimFn(csrPEM, duration)
+}
+
+func (v *Vault) WithSignVerbatim(certPEM, caPEM []byte, err error) *Vault {
+	v.SignVerbatimFn = func([]byte, time.Duration) ([]byte, []byte, error) {
+		return certPEM, caPEM, err
+	}
+	return v
+}
+
 func (v *Vault) WithSign(certPEM, caPEM []byte, err error) *Vault {
 	v.SignFn = func([]byte, time.Duration) ([]byte, []byte, error) {
 		return certPEM, caPEM, err
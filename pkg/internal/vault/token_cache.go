@@ -0,0 +1,305 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	vault "github.com/hashicorp/vault/api"
+	"k8s.io/utils/clock"
+)
+
+const (
+	// tokenRenewPath is the Vault API path used to renew the token that is
+	// currently set on a client.
+	tokenRenewPath = "/v1/auth/token/renew-self"
+
+	// tokenRenewNumerator and tokenRenewDenominator define the fraction of a
+	// token's lease after which the token will be proactively renewed.
+	tokenRenewNumerator   = 2
+	tokenRenewDenominator = 3
+
+	// tokenCacheIdleTimeout is how long a token is kept after it was last
+	// used. This removes tokens of issuers that have been deleted, including
+	// tokens that never expire.
+	tokenCacheIdleTimeout = time.Hour
+
+	// maxTokenCacheSize is the maximum number of tokens kept in a cache. Once
+	// reached, the least recently used token is removed to make room.
+	maxTokenCacheSize = 1024
+)
+
+// defaultTokenCache is shared between all Vault clients created with New so
+// that tokens survive across individual signing requests.
+var defaultTokenCache = NewTokenCache(clock.RealClock{})
+
+// tokenLease is a Vault client token along with the lease information that
+// was returned when it was obtained or last renewed.
+type tokenLease struct {
+	token     string
+	ttl       time.Duration
+	renewable bool
+}
+
+// cachedTokenLease is a tokenLease along with the issuer configuration it was
+// obtained with, the times at which it should be renewed and at which it
+// expires, and the time it was last used.
+type cachedTokenLease struct {
+	*tokenLease
+
+	config    string
+	renewAt   time.Time
+	expiresAt time.Time
+	lastUsed  time.Time
+}
+
+// expired returns true if the token should no longer be used or kept.
+func (c *cachedTokenLease) expired(now time.Time) bool {
+	if !c.expiresAt.IsZero() && !now.Before(c.expiresAt) {
+		return true
+	}
+	return !now.Before(c.lastUsed.Add(tokenCacheIdleTimeout))
+}
+
+// TokenCache stores tokens obtained by logging in to Vault, keyed by issuer,
+// so that a login is only performed when no valid token is available.
+// Tokens are renewed once 2/3 of their lease has elapsed. If renewal fails,
+// a new login is performed.
+// Only one token is kept per issuer, and it is replaced as soon as the
+// issuer's Vault configuration changes. Tokens are removed once they expire
+// or have not been used for tokenCacheIdleTimeout, and at most
+// maxTokenCacheSize tokens are kept.
+type TokenCache struct {
+	clock clock.Clock
+
+	lock   sync.Mutex
+	leases map[string]*cachedTokenLease
+}
+
+// NewTokenCache returns an empty TokenCache that uses the given clock to
+// determine when tokens need to be renewed.
+func NewTokenCache(c clock.Clock) *TokenCache {
+	return &TokenCache{
+		clock:  c,
+		leases: make(map[string]*cachedTokenLease),
+	}
+}
+
+// Token returns a valid token for the given key, which identifies an issuer.
+// A cached token is only used if it was obtained with the same config. It is
+// returned as-is if it is not yet due for renewal, otherwise it is renewed
+// using the given client. If no token is cached, the token has expired or
+// renewal fails, login is called to obtain a new token.
+func (c *TokenCache) Token(client Client, key, config string, login func() (*tokenLease, error)) (string, error) {
+	now := c.clock.Now()
+	cached := c.get(key, config, now)
+
+	if cached != nil {
+		if cached.renewAt.IsZero() || now.Before(cached.renewAt) {
+			return cached.token, nil
+		}
+
+		if cached.renewable {
+			lease, err := renewToken(client, cached.token)
+			if err == nil && lease.ttl > 0 {
+				c.set(key, config, lease, now)
+				return lease.token, nil
+			}
+		}
+	}
+
+	c.Forget(key)
+
+	lease, err := login()
+	if err != nil {
+		return "", err
+	}
+
+	c.set(key, config, lease, now)
+
+	return lease.token, nil
+}
+
+// Forget removes any token cached for the given key.
+func (c *TokenCache) Forget(key string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	delete(c.leases, key)
+}
+
+// get returns the token cached for the given key if it was obtained with the
+// given config and has not expired, and records that it has been used.
+// Otherwise, nil is returned and any cached token for the key is removed.
+func (c *TokenCache) get(key, config string, now time.Time) *cachedTokenLease {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	cached, ok := c.leases[key]
+	if !ok {
+		return nil
+	}
+	if cached.config != config || cached.expired(now) {
+		delete(c.leases, key)
+		return nil
+	}
+
+	cached.lastUsed = now
+
+	return cached
+}
+
+func (c *TokenCache) set(key, config string, lease *tokenLease, issuedAt time.Time) {
+	cached := &cachedTokenLease{
+		tokenLease: lease,
+		config:     config,
+		lastUsed:   issuedAt,
+	}
+
+	// A zero TTL means the token does not expire, so it never needs renewing.
+	if lease.ttl > 0 {
+		cached.renewAt = issuedAt.Add(lease.ttl * tokenRenewNumerator / tokenRenewDenominator)
+		cached.expiresAt = issuedAt.Add(lease.ttl)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.prune(issuedAt)
+	c.leases[key] = cached
+}
+
+// prune removes all expired tokens, and then the least recently used tokens
+// until there is room for another token. It must be called with the lock
+// held.
+func (c *TokenCache) prune(now time.Time) {
+	for key, cached := range c.leases {
+		if cached.expired(now) {
+			delete(c.leases, key)
+		}
+	}
+
+	for len(c.leases) >= maxTokenCacheSize {
+		var oldestKey string
+		var oldest *cachedTokenLease
+		for key, cached := range c.leases {
+			if oldest == nil || cached.lastUsed.Before(oldest.lastUsed) {
+				oldestKey, oldest = key, cached
+			}
+		}
+		delete(c.leases, oldestKey)
+	}
+}
+
+// cachedToken returns a token obtained by calling login, reusing a
+// previously obtained token for this issuer if a token cache is configured.
+func (v *Vault) cachedToken(client Client, login func() (*tokenLease, error)) (string, error) {
+	if v.tokenCache == nil {
+		lease, err := login()
+		if err != nil {
+			return "", err
+		}
+
+		return lease.token, nil
+	}
+
+	config, err := v.tokenCacheConfig()
+	if err != nil {
+		return "", err
+	}
+
+	return v.tokenCache.Token(client, v.tokenCacheKey(), config, login)
+}
+
+// forgetCachedToken removes any token cached for this issuer.
+func (v *Vault) forgetCachedToken() {
+	if v.tokenCache == nil {
+		return
+	}
+
+	v.tokenCache.Forget(v.tokenCacheKey())
+}
+
+// tokenCacheKey returns the key used to cache tokens for this issuer.
+func (v *Vault) tokenCacheKey() string {
+	meta := v.issuer.GetObjectMeta()
+
+	return fmt.Sprintf("%s/%s/%s/%s", v.namespace, meta.Namespace, meta.Name, meta.UID)
+}
+
+// tokenCacheConfig returns the Vault server and auth configuration of this
+// issuer, so that any change to them replaces the cached token with a new
+// login.
+func (v *Vault) tokenCacheConfig() (string, error) {
+	vaultIssuer := v.issuer.GetSpec().Vault
+
+	auth, err := json.Marshal(vaultIssuer.Auth)
+	if err != nil {
+		return "", fmt.Errorf("failed to build Vault token cache key: %s", err)
+	}
+
+	return fmt.Sprintf("%s/%s", vaultIssuer.Server, auth), nil
+}
+
+// renewToken renews the given token using the renew-self endpoint.
+func renewToken(client Client, token string) (*tokenLease, error) {
+	client.SetToken(token)
+
+	request := client.NewRequest("POST", tokenRenewPath)
+
+	resp, err := client.RawRequest(request)
+	if err != nil {
+		return nil, fmt.Errorf("error renewing Vault token: %s", err.Error())
+	}
+
+	defer resp.Body.Close()
+
+	vaultResult := vault.Secret{}
+	if err := resp.DecodeJSON(&vaultResult); err != nil {
+		return nil, fmt.Errorf("unable to decode JSON payload: %s", err.Error())
+	}
+
+	return newTokenLease(&vaultResult)
+}
+
+// newTokenLease builds a tokenLease from the auth information in a Vault
+// login or renewal response.
+func newTokenLease(secret *vault.Secret) (*tokenLease, error) {
+	token, err := secret.TokenID()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read token: %s", err.Error())
+	}
+
+	ttl, err := secret.TokenTTL()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read token TTL: %s", err.Error())
+	}
+
+	renewable, err := secret.TokenIsRenewable()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read token renewability: %s", err.Error())
+	}
+
+	return &tokenLease{
+		token:     token,
+		ttl:       ttl,
+		renewable: renewable,
+	}, nil
+}
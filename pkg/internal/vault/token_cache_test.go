@@ -0,0 +1,300 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	vault "github.com/hashicorp/vault/api"
+	corev1 "k8s.io/api/core/v1"
+	fakeclock "k8s.io/utils/clock/testing"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+	"github.com/jetstack/cert-manager/test/unit/listers"
+)
+
+// fakeVaultServer is a Client that records login and token renewal calls.
+// Tokens are issued with the configured lease duration.
+type fakeVaultServer struct {
+	token string
+
+	leaseSeconds int
+	renewErr     error
+
+	logins   int
+	renewals int
+}
+
+func (f *fakeVaultServer) NewRequest(method, requestPath string) *vault.Request {
+	return &vault.Request{
+		Method: method,
+		URL:    &url.URL{Path: requestPath},
+		Params: make(url.Values),
+	}
+}
+
+func (f *fakeVaultServer) RawRequest(r *vault.Request) (*vault.Response, error) {
+	var token string
+	switch {
+	case r.URL.Path == tokenRenewPath:
+		if f.renewErr != nil {
+			return nil, f.renewErr
+		}
+		f.renewals++
+		token = f.token
+	case strings.HasSuffix(r.URL.Path, "/login"):
+		f.logins++
+		token = fmt.Sprintf("token-%d", f.logins)
+	default:
+		return nil, fmt.Errorf("unexpected request to %q", r.URL.Path)
+	}
+
+	body := fmt.Sprintf(`{"auth":{"client_token":%q,"lease_duration":%d,"renewable":true}}`, token, f.leaseSeconds)
+	return &vault.Response{
+		Response: &http.Response{
+			Body: ioutil.NopCloser(strings.NewReader(body)),
+		},
+	}, nil
+}
+
+func (f *fakeVaultServer) SetToken(v string) {
+	f.token = v
+}
+
+func (f *fakeVaultServer) Token() string {
+	return f.token
+}
+
+func (f *fakeVaultServer) Sys() *vault.Sys {
+	return nil
+}
+
+func TestTokenCache(t *testing.T) {
+	const lease = time.Minute
+	start := time.Date(2020, 11, 20, 16, 05, 00, 0000, time.UTC)
+
+	appRoleIssuer := gen.Issuer("vault-issuer",
+		gen.SetIssuerNamespace("test-namespace"),
+		gen.SetIssuerVault(cmapi.VaultIssuer{
+			Server: "https://vault.example.com",
+			Path:   "pki/sign/example",
+			Auth: cmapi.VaultAuth{
+				AppRole: &cmapi.VaultAppRole{
+					Path:   "approle",
+					RoleId: "my-role-id",
+					SecretRef: cmmeta.SecretKeySelector{
+						LocalObjectReference: cmmeta.LocalObjectReference{
+							Name: "secret-ref-name",
+						},
+						Key: "my-role-key",
+					},
+				},
+			},
+		}),
+	)
+	appRoleSecretLister := listers.FakeSecretListerFrom(listers.NewFakeSecretLister(),
+		listers.SetFakeSecretNamespaceListerGet(&corev1.Secret{
+			Data: map[string][]byte{
+				"my-role-key": []byte("my-secret-id"),
+			},
+		}, nil),
+	)
+
+	type step struct {
+		// advance is how far to move the clock forward before calling setToken
+		advance time.Duration
+		// renewErr is returned by the fake server for renewal requests
+		renewErr error
+
+		expectedToken    string
+		expectedLogins   int
+		expectedRenewals int
+	}
+
+	tests := map[string][]step{
+		"cached token is reused before it is due for renewal": {
+			{expectedToken: "token-1", expectedLogins: 1},
+			{advance: 10 * time.Second, expectedToken: "token-1", expectedLogins: 1},
+			{advance: 29 * time.Second, expectedToken: "token-1", expectedLogins: 1},
+		},
+		"token is renewed once two thirds of its lease has elapsed": {
+			{expectedToken: "token-1", expectedLogins: 1},
+			{advance: 41 * time.Second, expectedToken: "token-1", expectedLogins: 1, expectedRenewals: 1},
+			// the renewed lease runs from the time of renewal
+			{advance: 30 * time.Second, expectedToken: "token-1", expectedLogins: 1, expectedRenewals: 1},
+			{advance: 11 * time.Second, expectedToken: "token-1", expectedLogins: 1, expectedRenewals: 2},
+		},
+		"login is performed again if renewal fails": {
+			{expectedToken: "token-1", expectedLogins: 1},
+			{advance: 41 * time.Second, renewErr: errors.New("permission denied"), expectedToken: "token-2", expectedLogins: 2},
+		},
+		"login is performed again once the token has expired": {
+			{expectedToken: "token-1", expectedLogins: 1},
+			{advance: lease + time.Second, expectedToken: "token-2", expectedLogins: 2},
+		},
+	}
+
+	for name, steps := range tests {
+		t.Run(name, func(t *testing.T) {
+			clock := fakeclock.NewFakeClock(start)
+			server := &fakeVaultServer{leaseSeconds: int(lease.Seconds())}
+			v := &Vault{
+				namespace:     "test-namespace",
+				secretsLister: appRoleSecretLister,
+				issuer:        appRoleIssuer,
+				tokenCache:    NewTokenCache(clock),
+			}
+
+			for i, s := range steps {
+				clock.Step(s.advance)
+				server.renewErr = s.renewErr
+
+				if err := v.setToken(server); err != nil {
+					t.Fatalf("step %d: unexpected error: %v", i, err)
+				}
+				if server.Token() != s.expectedToken {
+					t.Errorf("step %d: unexpected token, exp=%s got=%s", i, s.expectedToken, server.Token())
+				}
+				if server.logins != s.expectedLogins {
+					t.Errorf("step %d: unexpected number of logins, exp=%d got=%d", i, s.expectedLogins, server.logins)
+				}
+				if server.renewals != s.expectedRenewals {
+					t.Errorf("step %d: unexpected number of renewals, exp=%d got=%d", i, s.expectedRenewals, server.renewals)
+				}
+			}
+		})
+	}
+}
+
+func TestTokenCacheKeyChangesWithAuth(t *testing.T) {
+	server := &fakeVaultServer{leaseSeconds: 60}
+	cache := NewTokenCache(fakeclock.NewFakeClock(time.Now()))
+	secretLister := listers.FakeSecretListerFrom(listers.NewFakeSecretLister(),
+		listers.SetFakeSecretNamespaceListerGet(&corev1.Secret{
+			Data: map[string][]byte{
+				"my-role-key": []byte("my-secret-id"),
+			},
+		}, nil),
+	)
+
+	newVault := func(roleID string) *Vault {
+		return &Vault{
+			namespace:     "test-namespace",
+			secretsLister: secretLister,
+			tokenCache:    cache,
+			issuer: gen.Issuer("vault-issuer",
+				gen.SetIssuerVault(cmapi.VaultIssuer{
+					Server: "https://vault.example.com",
+					Auth: cmapi.VaultAuth{
+						AppRole: &cmapi.VaultAppRole{
+							RoleId: roleID,
+							SecretRef: cmmeta.SecretKeySelector{
+								LocalObjectReference: cmmeta.LocalObjectReference{
+									Name: "secret-ref-name",
+								},
+								Key: "my-role-key",
+							},
+						},
+					},
+				}),
+			),
+		}
+	}
+
+	for _, roleID := range []string{"role-a", "role-a", "role-b"} {
+		if err := newVault(roleID).setToken(server); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if server.logins != 2 {
+		t.Errorf("expected a login for each distinct auth configuration, exp=2 got=%d", server.logins)
+	}
+	if len(cache.leases) != 1 {
+		t.Errorf("expected the token of the previous auth configuration to be replaced, got %d cached tokens", len(cache.leases))
+	}
+}
+
+func TestTokenCacheEviction(t *testing.T) {
+	login := func(server *fakeVaultServer) func() (*tokenLease, error) {
+		return func() (*tokenLease, error) {
+			server.logins++
+			// a zero TTL means the token never expires
+			return &tokenLease{token: fmt.Sprintf("token-%d", server.logins)}, nil
+		}
+	}
+
+	t.Run("tokens that have not been used for the idle timeout are removed", func(t *testing.T) {
+		clock := fakeclock.NewFakeClock(time.Now())
+		cache := NewTokenCache(clock)
+		server := &fakeVaultServer{}
+
+		if _, err := cache.Token(server, "deleted-issuer", "config", login(server)); err != nil {
+			t.Fatal(err)
+		}
+		clock.Step(tokenCacheIdleTimeout)
+		if _, err := cache.Token(server, "other-issuer", "config", login(server)); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, ok := cache.leases["deleted-issuer"]; ok {
+			t.Errorf("expected idle token to be removed")
+		}
+		if len(cache.leases) != 1 {
+			t.Errorf("unexpected number of cached tokens, exp=1 got=%d", len(cache.leases))
+		}
+	})
+
+	t.Run("the least recently used token is removed once the cache is full", func(t *testing.T) {
+		clock := fakeclock.NewFakeClock(time.Now())
+		cache := NewTokenCache(clock)
+		server := &fakeVaultServer{}
+
+		for i := 0; i < maxTokenCacheSize; i++ {
+			if _, err := cache.Token(server, fmt.Sprintf("issuer-%d", i), "config", login(server)); err != nil {
+				t.Fatal(err)
+			}
+			clock.Step(time.Millisecond)
+		}
+		// use the oldest token so that the second oldest is removed instead
+		if _, err := cache.Token(server, "issuer-0", "config", login(server)); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := cache.Token(server, "new-issuer", "config", login(server)); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(cache.leases) != maxTokenCacheSize {
+			t.Errorf("unexpected number of cached tokens, exp=%d got=%d", maxTokenCacheSize, len(cache.leases))
+		}
+		if _, ok := cache.leases["issuer-0"]; !ok {
+			t.Errorf("expected recently used token to be kept")
+		}
+		if _, ok := cache.leases["issuer-1"]; ok {
+			t.Errorf("expected least recently used token to be removed")
+		}
+	})
+}
@@ -41,6 +41,7 @@ type VaultClientBuilder func(namespace string, secretsLister corelisters.SecretL
 
 type Interface interface {
 	Sign(csrPEM []byte, duration time.Duration) (certPEM []byte, caPEM []byte, err error)
+	SignVerbatim(csrPEM []byte, duration time.Duration) (certPEM []byte, caPEM []byte, err error)
 	Sys() *vault.Sys
 }
 
@@ -58,6 +59,10 @@ type Vault struct {
 	namespace     string
 
 	client Client
+
+	// tokenCache is used to reuse tokens obtained by logging in to Vault
+	// across calls. If nil, a new login is performed every time.
+	tokenCache *TokenCache
 }
 
 func New(namespace string, secretsLister corelisters.SecretLister,
@@ -66,6 +71,7 @@ func New(namespace string, secretsLister corelisters.SecretLister,
 		secretsLister: secretsLister,
 		namespace:     namespace,
 		issuer:        issuer,
+		tokenCache:    defaultTokenCache,
 	}
 
 	cfg, err := v.newConfig()
@@ -104,8 +110,44 @@ func (v *Vault) Sign(csrPEM []byte, duration time.Duration) (cert []byte, ca []b
 		"exclude_cn_from_sans": "true",
 	}
 
+	return v.requestSign(v.issuer.GetSpec().Vault.Path, parameters)
+}
+
+// SignVerbatim signs the given CSR using the sign-verbatim endpoint of the
+// PKI secrets engine configured on the issuer, so that the subject and key
+// usages requested by the CSR are used as-is.
+func (v *Vault) SignVerbatim(csrPEM []byte, duration time.Duration) (cert []byte, ca []byte, err error) {
+	if _, err := pki.DecodeX509CertificateRequestBytes(csrPEM); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode CSR for signing: %s", err)
+	}
+
+	signPath, err := signVerbatimPath(v.issuer.GetSpec().Vault.Path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	parameters := map[string]string{
+		"ttl": duration.String(),
+		"csr": string(csrPEM),
+	}
+
+	return v.requestSign(signPath, parameters)
+}
+
+// signVerbatimPath converts a path to a PKI secrets engine sign endpoint, of
+// the form <mount>/sign/<role>, into the equivalent sign-verbatim endpoint.
+func signVerbatimPath(signPath string) (string, error) {
+	i := strings.LastIndex(signPath, "/sign/")
+	if i < 0 {
+		return "", fmt.Errorf("vault path %q is not of the form <mount>/sign/<role>, cannot sign verbatim", signPath)
+	}
+
+	return signPath[:i] + "/sign-verbatim/" + signPath[i+len("/sign/"):], nil
+}
+
+func (v *Vault) requestSign(signPath string, parameters map[string]string) ([]byte, []byte, error) {
 	vaultIssuer := v.issuer.GetSpec().Vault
-	url := path.Join("/v1", vaultIssuer.Path)
+	url := path.Join("/v1", signPath)
 
 	request := v.client.NewRequest("POST", url)
 
@@ -121,6 +163,9 @@ func (v *Vault) Sign(csrPEM []byte, duration time.Duration) (cert []byte, ca []b
 
 	resp, err := v.client.RawRequest(request)
 	if err != nil {
+		// The cached token may have been revoked, so force a new login
+		// on the next attempt.
+		v.forgetCachedToken()
 		return nil, nil, fmt.Errorf("failed to sign certificate by vault: %s", err)
 	}
 
@@ -149,7 +194,9 @@ func (v *Vault) setToken(client Client) error {
 
 	appRole := v.issuer.GetSpec().Vault.Auth.AppRole
 	if appRole != nil {
-		token, err := v.requestTokenWithAppRoleRef(client, appRole)
+		token, err := v.cachedToken(client, func() (*tokenLease, error) {
+			return v.loginWithAppRoleRef(client, appRole)
+		})
 		if err != nil {
 			return err
 		}
@@ -160,7 +207,9 @@ func (v *Vault) setToken(client Client) error {
 
 	kubernetesAuth := v.issuer.GetSpec().Vault.Auth.Kubernetes
 	if kubernetesAuth != nil {
-		token, err := v.requestTokenWithKubernetesAuth(client, kubernetesAuth)
+		token, err := v.cachedToken(client, func() (*tokenLease, error) {
+			return v.loginWithKubernetesAuth(client, kubernetesAuth)
+		})
 		if err != nil {
 			return fmt.Errorf("error reading Kubernetes service account token from %s: %s", kubernetesAuth.SecretRef.Name, err.Error())
 		}
@@ -233,10 +282,10 @@ func (v *Vault) appRoleRef(appRole *v1.VaultAppRole) (roleId, secretId string, e
 	return roleId, secretId, nil
 }
 
-func (v *Vault) requestTokenWithAppRoleRef(client Client, appRole *v1.VaultAppRole) (string, error) {
+func (v *Vault) loginWithAppRoleRef(client Client, appRole *v1.VaultAppRole) (*tokenLease, error) {
 	roleId, secretId, err := v.appRoleRef(appRole)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	parameters := map[string]string{
@@ -255,37 +304,37 @@ func (v *Vault) requestTokenWithAppRoleRef(client Client, appRole *v1.VaultAppRo
 
 	err = request.SetJSONBody(parameters)
 	if err != nil {
-		return "", fmt.Errorf("error encoding Vault parameters: %s", err.Error())
+		return nil, fmt.Errorf("error encoding Vault parameters: %s", err.Error())
 	}
 
 	resp, err := client.RawRequest(request)
 	if err != nil {
-		return "", fmt.Errorf("error logging in to Vault server: %s", err.Error())
+		return nil, fmt.Errorf("error logging in to Vault server: %s", err.Error())
 	}
 
 	defer resp.Body.Close()
 
 	vaultResult := vault.Secret{}
 	if err := resp.DecodeJSON(&vaultResult); err != nil {
-		return "", fmt.Errorf("unable to decode JSON payload: %s", err.Error())
+		return nil, fmt.Errorf("unable to decode JSON payload: %s", err.Error())
 	}
 
-	token, err := vaultResult.TokenID()
+	lease, err := newTokenLease(&vaultResult)
 	if err != nil {
-		return "", fmt.Errorf("unable to read token: %s", err.Error())
+		return nil, err
 	}
 
-	if token == "" {
-		return "", errors.New("no token returned")
+	if lease.token == "" {
+		return nil, errors.New("no token returned")
 	}
 
-	return token, nil
+	return lease, nil
 }
 
-func (v *Vault) requestTokenWithKubernetesAuth(client Client, kubernetesAuth *v1.VaultKubernetesAuth) (string, error) {
+func (v *Vault) loginWithKubernetesAuth(client Client, kubernetesAuth *v1.VaultKubernetesAuth) (*tokenLease, error) {
 	secret, err := v.secretsLister.Secrets(v.namespace).Get(kubernetesAuth.SecretRef.Name)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	key := kubernetesAuth.SecretRef.Key
@@ -295,7 +344,7 @@ func (v *Vault) requestTokenWithKubernetesAuth(client Client, kubernetesAuth *v1
 
 	keyBytes, ok := secret.Data[key]
 	if !ok {
-		return "", fmt.Errorf("no data for %q in secret '%s/%s'", key, v.namespace, kubernetesAuth.SecretRef.Name)
+		return nil, fmt.Errorf("no data for %q in secret '%s/%s'", key, v.namespace, kubernetesAuth.SecretRef.Name)
 	}
 
 	jwt := string(keyBytes)
@@ -314,27 +363,22 @@ func (v *Vault) requestTokenWithKubernetesAuth(client Client, kubernetesAuth *v1
 	request := client.NewRequest("POST", url)
 	err = request.SetJSONBody(parameters)
 	if err != nil {
-		return "", fmt.Errorf("error encoding Vault parameters: %s", err.Error())
+		return nil, fmt.Errorf("error encoding Vault parameters: %s", err.Error())
 	}
 
 	resp, err := client.RawRequest(request)
 	if err != nil {
-		return "", fmt.Errorf("error calling Vault server: %s", err.Error())
+		return nil, fmt.Errorf("error calling Vault server: %s", err.Error())
 	}
 
 	defer resp.Body.Close()
 	vaultResult := vault.Secret{}
 	err = resp.DecodeJSON(&vaultResult)
 	if err != nil {
-		return "", fmt.Errorf("unable to decode JSON payload: %s", err.Error())
+		return nil, fmt.Errorf("unable to decode JSON payload: %s", err.Error())
 	}
 
-	token, err := vaultResult.TokenID()
-	if err != nil {
-		return "", fmt.Errorf("unable to read token: %s", err.Error())
-	}
-
-	return token, nil
+	return newTokenLease(&vaultResult)
 }
 
 func (v *Vault) Sys() *vault.Sys {
@@ -308,6 +308,91 @@ func TestSign(t *testing.T) {
 	}
 }
 
+func TestSignVerbatim(t *testing.T) {
+	privatekey := generateRSAPrivateKey(t)
+	csrPEM := generateCSR(t, privatekey)
+
+	bundleData, err := bundlePEM(testIntermediateCa)
+	if err != nil {
+		t.Errorf("failed to encode bundle for testing: %s", err)
+		t.FailNow()
+	}
+
+	tests := map[string]struct {
+		path        string
+		fakeClient  *vaultfake.Client
+		expectedErr error
+		expectedCA  string
+	}{
+		"a sign path should be signed verbatim": {
+			path: "pki/sign/example",
+			fakeClient: vaultfake.NewFakeClient().WithRawRequest(&vault.Response{
+				Response: &http.Response{
+					Body: ioutil.NopCloser(bytes.NewReader(bundleData))},
+			}, nil),
+			expectedCA: testIntermediateCa,
+		},
+		"a path that is not a sign path should error": {
+			path:        "pki/issue/example",
+			fakeClient:  vaultfake.NewFakeClient(),
+			expectedErr: errors.New(`vault path "pki/issue/example" is not of the form <mount>/sign/<role>, cannot sign verbatim`),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			v := &Vault{
+				namespace: "test-namespace",
+				issuer: gen.Issuer("vault-issuer",
+					gen.SetIssuerVault(cmapi.VaultIssuer{Path: test.path}),
+				),
+				client: test.fakeClient,
+			}
+
+			_, ca, err := v.SignVerbatim(csrPEM, time.Minute)
+			if test.expectedErr != nil {
+				if err == nil || err.Error() != test.expectedErr.Error() {
+					t.Errorf("unexpected error, exp=%v got=%v", test.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if test.expectedCA != string(ca) {
+				t.Errorf("unexpected ca in response bundle, exp=%s got=%s", test.expectedCA, ca)
+			}
+		})
+	}
+}
+
+func TestSignVerbatimPath(t *testing.T) {
+	tests := map[string]struct {
+		path      string
+		expected  string
+		expectErr bool
+	}{
+		"a sign path is converted":             {path: "pki/sign/example", expected: "pki/sign-verbatim/example"},
+		"a nested mount is converted":          {path: "a/b/pki/sign/example", expected: "a/b/pki/sign-verbatim/example"},
+		"a role named sign is converted":       {path: "pki/sign/sign", expected: "pki/sign-verbatim/sign"},
+		"a sign-intermediate path is rejected": {path: "pki/root/sign-intermediate", expectErr: true},
+		"an issue path is rejected":            {path: "pki/issue/example", expectErr: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := signVerbatimPath(test.path)
+			if (err != nil) != test.expectErr {
+				t.Fatalf("unexpected error, expectErr=%t got=%v", test.expectErr, err)
+			}
+			if got != test.expected {
+				t.Errorf("unexpected path, exp=%q got=%q", test.expected, got)
+			}
+		})
+	}
+}
+
 type testExtractCertificatesFromVaultCertT struct {
 	secret       *certutil.Secret
 	expectedCert []string
@@ -932,7 +1017,7 @@ func TestNewConfig(t *testing.T) {
 	}
 }
 
-type requestTokenWithAppRoleRefT struct {
+type loginWithAppRoleRefT struct {
 	client  Client
 	appRole *cmapi.VaultAppRole
 
@@ -942,7 +1027,7 @@ type requestTokenWithAppRoleRefT struct {
 	expectedErr   error
 }
 
-func TestRequestTokenWithAppRoleRef(t *testing.T) {
+func TestLoginWithAppRoleRef(t *testing.T) {
 	basicAppRoleRef := &cmapi.VaultAppRole{
 		RoleId: "test-role-id",
 		SecretRef: cmmeta.SecretKeySelector{
@@ -962,7 +1047,7 @@ func TestRequestTokenWithAppRoleRef(t *testing.T) {
 			}, nil),
 	)
 
-	tests := map[string]requestTokenWithAppRoleRefT{
+	tests := map[string]loginWithAppRoleRefT{
 		"a secret reference that does not exist should error": {
 			client:  vaultfake.NewFakeClient(),
 			appRole: basicAppRoleRef,
@@ -1042,13 +1127,17 @@ func TestRequestTokenWithAppRoleRef(t *testing.T) {
 				issuer:        nil,
 			}
 
-			token, err := v.requestTokenWithAppRoleRef(test.client, test.appRole)
+			var token string
+			lease, err := v.loginWithAppRoleRef(test.client, test.appRole)
 			if ((test.expectedErr == nil) != (err == nil)) &&
 				test.expectedErr != nil &&
 				test.expectedErr.Error() != err.Error() {
 				t.Errorf("unexpected error, exp=%v got=%v",
 					test.expectedErr, err)
 			}
+			if lease != nil {
+				token = lease.token
+			}
 
 			if test.expectedToken != token {
 				t.Errorf("got unexpected token, exp=%s got=%s",
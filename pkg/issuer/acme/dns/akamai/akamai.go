@@ -48,17 +48,21 @@ type DNSProvider struct {
 	transport              http.RoundTripper
 	findHostedDomainByFqdn func(string, []string) (string, error)
 	log                    logr.Logger
+
+	// recordTTL overrides the TTL of the TXT records created by the provider
+	// if it is greater than zero.
+	recordTTL int
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for Akamai.
 func NewDNSProvider(serviceConsumerDomain, clientToken, clientSecret, accessToken string, dns01Nameservers []string) (*DNSProvider, error) {
 	return &DNSProvider{
-		dns01Nameservers,
-		serviceConsumerDomain,
-		NewEdgeGridAuth(clientToken, clientSecret, accessToken),
-		http.DefaultTransport,
-		findHostedDomainByFqdn,
-		logf.Log.WithName("akamai-dns"),
+		dns01Nameservers:       dns01Nameservers,
+		serviceConsumerDomain:  serviceConsumerDomain,
+		auth:                   NewEdgeGridAuth(clientToken, clientSecret, accessToken),
+		transport:              http.DefaultTransport,
+		findHostedDomainByFqdn: findHostedDomainByFqdn,
+		log:                    logf.Log.WithName("akamai-dns"),
 	}, nil
 }
 
@@ -73,7 +77,20 @@ func findHostedDomainByFqdn(fqdn string, ns []string) (string, error) {
 
 // Present creates a TXT record to fulfil the dns-01 challenge
 func (a *DNSProvider) Present(domain, fqdn, value string) error {
-	return a.setTxtRecord(fqdn, &dns01Record{value, 60})
+	return a.setTxtRecord(fqdn, &dns01Record{value, a.ttl()})
+}
+
+// SetRecordTTL sets the TTL, in seconds, of the TXT records created by the
+// provider.
+func (a *DNSProvider) SetRecordTTL(ttl int) {
+	a.recordTTL = ttl
+}
+
+func (a *DNSProvider) ttl() int {
+	if a.recordTTL > 0 {
+		return a.recordTTL
+	}
+	return 60
 }
 
 // CleanUp removes the TXT record matching the specified parameters
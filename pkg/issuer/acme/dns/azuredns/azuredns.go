@@ -35,6 +35,10 @@ type DNSProvider struct {
 	resourceGroupName string
 	zoneName          string
 	log               logr.Logger
+
+	// recordTTL overrides the TTL of the TXT records created by the provider
+	// if it is greater than zero.
+	recordTTL int
 }
 
 // NewDNSProviderCredentials returns a DNSProvider instance configured for the Azure
@@ -101,7 +105,20 @@ func getAuthorization(env azure.Environment, clientID, clientSecret, subscriptio
 
 // Present creates a TXT record using the specified parameters
 func (c *DNSProvider) Present(domain, fqdn, value string) error {
-	return c.createRecord(fqdn, value, 60)
+	return c.createRecord(fqdn, value, c.ttl())
+}
+
+// SetRecordTTL sets the TTL, in seconds, of the TXT records created by the
+// provider.
+func (c *DNSProvider) SetRecordTTL(ttl int) {
+	c.recordTTL = ttl
+}
+
+func (c *DNSProvider) ttl() int {
+	if c.recordTTL > 0 {
+		return c.recordTTL
+	}
+	return 60
 }
 
 // CleanUp removes the TXT record matching the specified parameters
@@ -35,6 +35,10 @@ type DNSProvider struct {
 	project          string
 	client           *dns.Service
 	log              logr.Logger
+
+	// recordTTL overrides the TTL of the TXT records created by the provider
+	// if it is greater than zero.
+	recordTTL int
 }
 
 func NewDNSProvider(project string, saBytes []byte, dns01Nameservers []string, ambient bool, hostedZoneName string) (*DNSProvider, error) {
@@ -152,7 +156,7 @@ func (c *DNSProvider) Present(domain, fqdn, value string) error {
 	rec := &dns.ResourceRecordSet{
 		Name:    fqdn,
 		Rrdatas: []string{value},
-		Ttl:     int64(60),
+		Ttl:     int64(c.ttl()),
 		Type:    "TXT",
 	}
 	change := &dns.Change{
@@ -187,6 +191,19 @@ func (c *DNSProvider) Present(domain, fqdn, value string) error {
 	return nil
 }
 
+// SetRecordTTL sets the TTL, in seconds, of the TXT records created by the
+// provider.
+func (c *DNSProvider) SetRecordTTL(ttl int) {
+	c.recordTTL = ttl
+}
+
+func (c *DNSProvider) ttl() int {
+	if c.recordTTL > 0 {
+		return c.recordTTL
+	}
+	return 60
+}
+
 // CleanUp removes the TXT record matching the specified parameters.
 func (c *DNSProvider) CleanUp(domain, fqdn, value string) error {
 	zone, err := c.getHostedZone(fqdn)
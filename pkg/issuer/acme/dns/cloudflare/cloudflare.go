@@ -34,6 +34,10 @@ type DNSProvider struct {
 	authEmail        string
 	authKey          string
 	authToken        string
+
+	// recordTTL overrides the TTL of the TXT records created by the provider
+	// if it is greater than zero.
+	recordTTL int
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for cloudflare.
@@ -104,7 +108,7 @@ func (c *DNSProvider) Present(domain, fqdn, value string) error {
 		Type:    "TXT",
 		Name:    util.UnFqdn(fqdn),
 		Content: value,
-		TTL:     120,
+		TTL:     c.ttl(),
 	}
 
 	body, err := json.Marshal(rec)
@@ -120,6 +124,19 @@ func (c *DNSProvider) Present(domain, fqdn, value string) error {
 	return nil
 }
 
+// SetRecordTTL sets the TTL, in seconds, of the TXT records created by the
+// provider.
+func (c *DNSProvider) SetRecordTTL(ttl int) {
+	c.recordTTL = ttl
+}
+
+func (c *DNSProvider) ttl() int {
+	if c.recordTTL > 0 {
+		return c.recordTTL
+	}
+	return 120
+}
+
 // CleanUp removes the TXT record matching the specified parameters
 func (c *DNSProvider) CleanUp(domain, fqdn, value string) error {
 	record, err := c.findTxtRecord(fqdn)
@@ -33,6 +33,10 @@ import (
 type DNSProvider struct {
 	dns01Nameservers []string
 	client           *godo.Client
+
+	// recordTTL overrides the TTL of the TXT records created by the provider
+	// if it is greater than zero.
+	recordTTL int
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for digitalocean.
@@ -85,7 +89,7 @@ func (c *DNSProvider) Present(domain, fqdn, value string) error {
 		Type: "TXT",
 		Name: fqdn,
 		Data: value,
-		TTL:  60,
+		TTL:  c.ttl(),
 	}
 
 	_, _, err = c.client.Domains.CreateRecord(
@@ -101,6 +105,19 @@ func (c *DNSProvider) Present(domain, fqdn, value string) error {
 	return nil
 }
 
+// SetRecordTTL sets the TTL, in seconds, of the TXT records created by the
+// provider.
+func (c *DNSProvider) SetRecordTTL(ttl int) {
+	c.recordTTL = ttl
+}
+
+func (c *DNSProvider) ttl() int {
+	if c.recordTTL > 0 {
+		return c.recordTTL
+	}
+	return 60
+}
+
 // CleanUp removes the TXT record matching the specified parameters
 func (c *DNSProvider) CleanUp(domain, fqdn, value string) error {
 	zoneName, err := util.FindZoneByFqdn(fqdn, c.dns01Nameservers)
@@ -39,6 +39,8 @@ import (
 	"github.com/jetstack/cert-manager/pkg/issuer/acme/dns/clouddns"
 	"github.com/jetstack/cert-manager/pkg/issuer/acme/dns/cloudflare"
 	"github.com/jetstack/cert-manager/pkg/issuer/acme/dns/digitalocean"
+	"github.com/jetstack/cert-manager/pkg/issuer/acme/dns/gandi"
+	"github.com/jetstack/cert-manager/pkg/issuer/acme/dns/porkbun"
 	"github.com/jetstack/cert-manager/pkg/issuer/acme/dns/rfc2136"
 	"github.com/jetstack/cert-manager/pkg/issuer/acme/dns/route53"
 	"github.com/jetstack/cert-manager/pkg/issuer/acme/dns/util"
@@ -53,6 +55,12 @@ type solver interface {
 	CleanUp(domain, fqdn, value string) error
 }
 
+// recordTTLSetter is implemented by solvers that support overriding the TTL
+// of the TXT records that they create.
+type recordTTLSetter interface {
+	SetRecordTTL(ttl int)
+}
+
 // dnsProviderConstructors defines how each provider may be constructed.
 // It is useful for mocking out a given provider since an alternate set of
 // constructors may be set.
@@ -63,6 +71,8 @@ type dnsProviderConstructors struct {
 	azureDNS     func(environment, clientID, clientSecret, subscriptionID, tenantID, resourceGroupName, hostedZoneName string, dns01Nameservers []string, ambient bool) (*azuredns.DNSProvider, error)
 	acmeDNS      func(host string, accountJson []byte, dns01Nameservers []string) (*acmedns.DNSProvider, error)
 	digitalOcean func(token string, dns01Nameservers []string) (*digitalocean.DNSProvider, error)
+	gandi        func(token string, dns01Nameservers []string) (*gandi.DNSProvider, error)
+	porkbun      func(apiKey, secretAPIKey string, dns01Nameservers []string) (*porkbun.DNSProvider, error)
 }
 
 // Solver is a solver for the acme dns01 challenge.
@@ -113,10 +123,11 @@ func (s *Solver) Check(ctx context.Context, issuer v1.GenericIssuer, ch *cmacme.
 		return err
 	}
 
-	log.V(logf.DebugLevel).Info("checking DNS propagation", "nameservers", s.Context.DNS01Nameservers)
+	nameservers, checkAuthoritative := s.selfCheckNameservers(ch)
+
+	log.V(logf.DebugLevel).Info("checking DNS propagation", "nameservers", nameservers)
 
-	ok, err := util.PreCheckDNS(fqdn, ch.Spec.Key, s.Context.DNS01Nameservers,
-		s.Context.DNS01CheckAuthoritative)
+	ok, err := util.PreCheckDNS(fqdn, ch.Spec.Key, nameservers, checkAuthoritative)
 	if err != nil {
 		return err
 	}
@@ -160,6 +171,19 @@ func (s *Solver) CleanUp(ctx context.Context, issuer v1.GenericIssuer, ch *cmacm
 	return slv.CleanUp(ch.Spec.DNSName, fqdn, ch.Spec.Key)
 }
 
+// selfCheckNameservers returns the nameservers that should be queried when
+// checking DNS propagation for the given challenge, and whether the
+// authoritative nameservers for the zone should be looked up and queried
+// instead.
+// If the challenge's solver configures selfCheckNameservers, only those
+// nameservers will be queried.
+func (s *Solver) selfCheckNameservers(ch *cmacme.Challenge) ([]string, bool) {
+	if ch.Spec.Solver.DNS01 != nil && len(ch.Spec.Solver.DNS01.SelfCheckNameservers) > 0 {
+		return ch.Spec.Solver.DNS01.SelfCheckNameservers, false
+	}
+	return s.Context.DNS01Nameservers, s.Context.DNS01CheckAuthoritative
+}
+
 func followCNAME(strategy cmacme.CNAMEStrategy) bool {
 	if strategy == cmacme.FollowStrategy {
 		return true
@@ -178,6 +202,9 @@ func extractChallengeSolverConfig(ch *cmacme.Challenge) (*cmacme.ACMEChallengeSo
 // solverForChallenge returns a Solver for the given providerName.
 // The providerName is the name of an ACME DNS-01 challenge provider as
 // specified on the Issuer resource for the Solver.
+// Provider credentials are read from their Secret resources each time this is
+// called rather than being cached for the lifetime of the issuer, so rotated
+// credentials are used by the next Present, Check or CleanUp call.
 func (s *Solver) solverForChallenge(ctx context.Context, issuer v1.GenericIssuer, ch *cmacme.Challenge) (solver, *cmacme.ACMEChallengeSolverDNS01, error) {
 	log := logf.FromContext(ctx, "solverForChallenge")
 	dbg := log.V(logf.DebugLevel)
@@ -293,6 +320,37 @@ func (s *Solver) solverForChallenge(ctx context.Context, issuer v1.GenericIssuer
 		if err != nil {
 			return nil, nil, fmt.Errorf("error instantiating digitalocean challenge solver: %s", err.Error())
 		}
+	case providerConfig.Gandi != nil:
+		dbg.Info("preparing to create Gandi provider")
+		tokenSecret, err := s.secretLister.Secrets(resourceNamespace).Get(providerConfig.Gandi.Token.Name)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error getting gandi token: %s", err)
+		}
+
+		token := string(tokenSecret.Data[providerConfig.Gandi.Token.Key])
+
+		impl, err = s.dnsProviderConstructors.gandi(strings.TrimSpace(token), s.DNS01Nameservers)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error instantiating gandi challenge solver: %s", err.Error())
+		}
+	case providerConfig.Porkbun != nil:
+		dbg.Info("preparing to create Porkbun provider")
+		apiKeySecret, err := s.secretLister.Secrets(resourceNamespace).Get(providerConfig.Porkbun.APIKey.Name)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error getting porkbun api key: %s", err)
+		}
+		secretAPIKeySecret, err := s.secretLister.Secrets(resourceNamespace).Get(providerConfig.Porkbun.SecretAPIKey.Name)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error getting porkbun secret api key: %s", err)
+		}
+
+		apiKey := string(apiKeySecret.Data[providerConfig.Porkbun.APIKey.Key])
+		secretAPIKey := string(secretAPIKeySecret.Data[providerConfig.Porkbun.SecretAPIKey.Key])
+
+		impl, err = s.dnsProviderConstructors.porkbun(strings.TrimSpace(apiKey), strings.TrimSpace(secretAPIKey), s.DNS01Nameservers)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error instantiating porkbun challenge solver: %s", err.Error())
+		}
 	case providerConfig.Route53 != nil:
 		dbg.Info("preparing to create Route53 provider")
 		secretAccessKey := ""
@@ -376,6 +434,15 @@ func (s *Solver) solverForChallenge(ctx context.Context, issuer v1.GenericIssuer
 		return nil, providerConfig, fmt.Errorf("no dns provider config specified for challenge")
 	}
 
+	if providerConfig.RecordTTL != nil {
+		setter, ok := impl.(recordTTLSetter)
+		if !ok {
+			return nil, providerConfig, fmt.Errorf("recordTTL is not supported by the configured dns provider")
+		}
+		dbg.Info("overriding TXT record TTL", "ttl", *providerConfig.RecordTTL)
+		setter.SetRecordTTL(int(*providerConfig.RecordTTL))
+	}
+
 	return impl, providerConfig, nil
 }
 
@@ -481,6 +548,8 @@ func NewSolver(ctx *controller.Context) (*Solver, error) {
 			azuredns.NewDNSProviderCredentials,
 			acmedns.NewDNSProviderHostBytes,
 			digitalocean.NewDNSProviderCredentials,
+			gandi.NewDNSProviderCredentials,
+			porkbun.NewDNSProviderCredentials,
 		},
 		webhookSolvers: initialized,
 	}, nil
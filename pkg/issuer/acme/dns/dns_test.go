@@ -24,6 +24,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
 
 	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
 	v1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
@@ -277,6 +278,38 @@ func TestSolverFor(t *testing.T) {
 			domain:             "example.com",
 			expectedSolverType: reflect.TypeOf(&acmedns.DNSProvider{}),
 		},
+		"fails to load an acmedns provider with a record ttl": {
+			solverFixture: &solverFixture{
+				Builder: &test.Builder{
+					KubeObjects: []runtime.Object{
+						newSecret("acmedns-key", "default", map[string][]byte{
+							"acmedns.json": []byte("{}"),
+						}),
+					},
+				},
+				Issuer: newIssuer("test", "default"),
+				Challenge: &cmacme.Challenge{
+					Spec: cmacme.ChallengeSpec{
+						Solver: cmacme.ACMEChallengeSolver{
+							DNS01: &cmacme.ACMEChallengeSolverDNS01{
+								RecordTTL: pointer.Int32Ptr(300),
+								AcmeDNS: &cmacme.ACMEIssuerDNS01ProviderAcmeDNS{
+									Host: "http://127.0.0.1/",
+									AccountSecret: cmmeta.SecretKeySelector{
+										LocalObjectReference: cmmeta.LocalObjectReference{
+											Name: "acmedns-key",
+										},
+										Key: "acmedns.json",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			domain:    "example.com",
+			expectErr: true,
+		},
 	}
 	testFn := func(test testT) func(*testing.T) {
 		return func(t *testing.T) {
@@ -351,6 +384,115 @@ func TestSolveForDigitalOcean(t *testing.T) {
 
 }
 
+func TestSolveForGandi(t *testing.T) {
+	f := &solverFixture{
+		Builder: &test.Builder{
+			KubeObjects: []runtime.Object{
+				newSecret("gandi", "default", map[string][]byte{
+					"token": []byte("FAKE-TOKEN\n"),
+				}),
+			},
+		},
+		Issuer: newIssuer("test", "default"),
+		Challenge: &cmacme.Challenge{
+			Spec: cmacme.ChallengeSpec{
+				Solver: cmacme.ACMEChallengeSolver{
+					DNS01: &cmacme.ACMEChallengeSolverDNS01{
+						Gandi: &cmacme.ACMEIssuerDNS01ProviderGandi{
+							Token: cmmeta.SecretKeySelector{
+								LocalObjectReference: cmmeta.LocalObjectReference{
+									Name: "gandi",
+								},
+								Key: "token",
+							},
+						},
+					},
+				},
+			},
+		},
+		dnsProviders: newFakeDNSProviders(),
+	}
+
+	f.Setup(t)
+	defer f.Finish(t)
+
+	s := f.Solver
+	_, _, err := s.solverForChallenge(context.Background(), f.Issuer, f.Challenge)
+	if err != nil {
+		t.Fatalf("expected solverFor to not error, but got: %s", err)
+	}
+
+	expectedGandiCall := []fakeDNSProviderCall{
+		{
+			name: "gandi",
+			args: []interface{}{"FAKE-TOKEN", util.RecursiveNameservers},
+		},
+	}
+
+	if !reflect.DeepEqual(expectedGandiCall, f.dnsProviders.calls) {
+		t.Fatalf("expected %+v == %+v", expectedGandiCall, f.dnsProviders.calls)
+	}
+
+}
+
+func TestSolveForPorkbun(t *testing.T) {
+	f := &solverFixture{
+		Builder: &test.Builder{
+			KubeObjects: []runtime.Object{
+				newSecret("porkbun", "default", map[string][]byte{
+					"api-key":        []byte("FAKE-API-KEY\n"),
+					"secret-api-key": []byte("FAKE-SECRET-API-KEY\n"),
+				}),
+			},
+		},
+		Issuer: newIssuer("test", "default"),
+		Challenge: &cmacme.Challenge{
+			Spec: cmacme.ChallengeSpec{
+				Solver: cmacme.ACMEChallengeSolver{
+					DNS01: &cmacme.ACMEChallengeSolverDNS01{
+						Porkbun: &cmacme.ACMEIssuerDNS01ProviderPorkbun{
+							APIKey: cmmeta.SecretKeySelector{
+								LocalObjectReference: cmmeta.LocalObjectReference{
+									Name: "porkbun",
+								},
+								Key: "api-key",
+							},
+							SecretAPIKey: cmmeta.SecretKeySelector{
+								LocalObjectReference: cmmeta.LocalObjectReference{
+									Name: "porkbun",
+								},
+								Key: "secret-api-key",
+							},
+						},
+					},
+				},
+			},
+		},
+		dnsProviders: newFakeDNSProviders(),
+	}
+
+	f.Setup(t)
+	defer f.Finish(t)
+
+	s := f.Solver
+	_, _, err := s.solverForChallenge(context.Background(), f.Issuer, f.Challenge)
+	if err != nil {
+		t.Fatalf("expected solverFor to not error, but got: %s", err)
+	}
+
+	expectedPorkbunCall := []fakeDNSProviderCall{
+		{
+			name: "porkbun",
+			args: []interface{}{"FAKE-API-KEY", "FAKE-SECRET-API-KEY", util.RecursiveNameservers},
+		},
+	}
+
+	if !reflect.DeepEqual(expectedPorkbunCall, f.dnsProviders.calls) {
+		t.Fatalf("expected %+v == %+v", expectedPorkbunCall, f.dnsProviders.calls)
+	}
+
+}
+
 func TestRoute53TrimCreds(t *testing.T) {
 	f := &solverFixture{
 		Builder: &test.Builder{
@@ -584,3 +726,157 @@ func TestRoute53AssumeRole(t *testing.T) {
 		}
 	}
 }
+
+func TestCheckSelfCheckNameservers(t *testing.T) {
+	tests := map[string]struct {
+		selfCheckNameservers  []string
+		expectedNameservers   []string
+		expectedAuthoritative bool
+	}{
+		"uses the controller nameservers if none are configured on the solver": {
+			expectedNameservers:   []string{"10.0.0.1:53"},
+			expectedAuthoritative: true,
+		},
+		"queries only the nameservers configured on the solver": {
+			selfCheckNameservers:  []string{"192.168.0.53:53", "internal-ns.example.com:5353"},
+			expectedNameservers:   []string{"192.168.0.53:53", "internal-ns.example.com:5353"},
+			expectedAuthoritative: false,
+		},
+	}
+
+	origPreCheckDNS := util.PreCheckDNS
+	defer func() { util.PreCheckDNS = origPreCheckDNS }()
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := &solverFixture{
+				Challenge: &cmacme.Challenge{
+					Spec: cmacme.ChallengeSpec{
+						DNSName: "example.com",
+						Key:     "key",
+						Solver: cmacme.ACMEChallengeSolver{
+							DNS01: &cmacme.ACMEChallengeSolverDNS01{
+								SelfCheckNameservers: test.selfCheckNameservers,
+							},
+						},
+					},
+				},
+			}
+			f.Setup(t)
+			defer f.Finish(t)
+
+			f.Solver.Context.DNS01Nameservers = []string{"10.0.0.1:53"}
+			f.Solver.Context.DNS01CheckAuthoritative = true
+
+			var queried [][]string
+			util.PreCheckDNS = func(fqdn, value string, nameservers []string, useAuthoritative bool) (bool, error) {
+				queried = append(queried, nameservers)
+				if useAuthoritative != test.expectedAuthoritative {
+					t.Errorf("expected useAuthoritative=%t but got %t", test.expectedAuthoritative, useAuthoritative)
+				}
+				return false, nil
+			}
+
+			if err := f.Solver.Check(context.Background(), f.Issuer, f.Challenge); err == nil {
+				t.Errorf("expected an error as the record has not propagated")
+			}
+
+			if !reflect.DeepEqual([][]string{test.expectedNameservers}, queried) {
+				t.Errorf("expected self check to query %v but got %v", test.expectedNameservers, queried)
+			}
+		})
+	}
+}
+
+func TestSolverForUsesRotatedCredentials(t *testing.T) {
+	secretRef := func(name, key string) cmmeta.SecretKeySelector {
+		return cmmeta.SecretKeySelector{
+			LocalObjectReference: cmmeta.LocalObjectReference{Name: name},
+			Key:                  key,
+		}
+	}
+	cloudflareTokenRef := secretRef("cloudflare", "api-token")
+
+	tests := map[string]struct {
+		secretName   string
+		secretKey    string
+		solver       *cmacme.ACMEChallengeSolverDNS01
+		expectedCall func(credential string) fakeDNSProviderCall
+	}{
+		"digitalocean": {
+			secretName: "digitalocean",
+			secretKey:  "token",
+			solver: &cmacme.ACMEChallengeSolverDNS01{
+				DigitalOcean: &cmacme.ACMEIssuerDNS01ProviderDigitalOcean{
+					Token: secretRef("digitalocean", "token"),
+				},
+			},
+			expectedCall: func(credential string) fakeDNSProviderCall {
+				return fakeDNSProviderCall{name: "digitalocean", args: []interface{}{credential, util.RecursiveNameservers}}
+			},
+		},
+		"cloudflare": {
+			secretName: "cloudflare",
+			secretKey:  "api-token",
+			solver: &cmacme.ACMEChallengeSolverDNS01{
+				Cloudflare: &cmacme.ACMEIssuerDNS01ProviderCloudflare{
+					Email:    "test@example.com",
+					APIToken: &cloudflareTokenRef,
+				},
+			},
+			expectedCall: func(credential string) fakeDNSProviderCall {
+				return fakeDNSProviderCall{name: "cloudflare", args: []interface{}{"test@example.com", "", credential, util.RecursiveNameservers}}
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := &solverFixture{
+				Builder: &test.Builder{
+					KubeObjects: []runtime.Object{
+						newSecret(tc.secretName, "default", map[string][]byte{
+							tc.secretKey: []byte("OLD-CREDENTIAL"),
+						}),
+					},
+				},
+				Issuer: newIssuer("test", "default"),
+				Challenge: &cmacme.Challenge{
+					Spec: cmacme.ChallengeSpec{
+						Solver: cmacme.ACMEChallengeSolver{DNS01: tc.solver},
+					},
+				},
+				dnsProviders: newFakeDNSProviders(),
+			}
+
+			f.Setup(t)
+			defer f.Finish(t)
+
+			s := f.Solver
+			if _, _, err := s.solverForChallenge(context.Background(), f.Issuer, f.Challenge); err != nil {
+				t.Fatalf("expected solverFor to not error, but got: %s", err)
+			}
+
+			// rotate the credentials whilst the issuer keeps running
+			rotated := newSecret(tc.secretName, "default", map[string][]byte{
+				tc.secretKey: []byte("NEW-CREDENTIAL"),
+			})
+			if _, err := f.Builder.FakeKubeClient().CoreV1().Secrets("default").Update(context.Background(), rotated, metav1.UpdateOptions{}); err != nil {
+				t.Fatalf("failed to update Secret: %s", err)
+			}
+			f.Builder.Sync()
+
+			if _, _, err := s.solverForChallenge(context.Background(), f.Issuer, f.Challenge); err != nil {
+				t.Fatalf("expected solverFor to not error, but got: %s", err)
+			}
+
+			expectedCalls := []fakeDNSProviderCall{
+				tc.expectedCall("OLD-CREDENTIAL"),
+				tc.expectedCall("NEW-CREDENTIAL"),
+			}
+			if !reflect.DeepEqual(expectedCalls, f.dnsProviders.calls) {
+				t.Fatalf("expected %+v == %+v", expectedCalls, f.dnsProviders.calls)
+			}
+		})
+	}
+}
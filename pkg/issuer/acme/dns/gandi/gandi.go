@@ -0,0 +1,277 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package gandi implements a DNS provider for solving the DNS-01 challenge
+// using the Gandi LiveDNS API.
+package gandi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jetstack/cert-manager/pkg/issuer/acme/dns/util"
+	pkgutil "github.com/jetstack/cert-manager/pkg/util"
+)
+
+// LiveDNSAPIURL is the base URL of the Gandi LiveDNS API.
+const LiveDNSAPIURL = "https://api.gandi.net/v5/livedns"
+
+// minTTL is the smallest TTL that Gandi LiveDNS accepts for a record set.
+const minTTL = 300
+
+// DNSProvider is an implementation of the acme.ChallengeProvider interface
+type DNSProvider struct {
+	dns01Nameservers []string
+	token            string
+	apiURL           string
+	client           *http.Client
+
+	// findZoneByFqdn is used to determine the zone that a record belongs to.
+	// It is overridden in tests to avoid performing DNS lookups.
+	findZoneByFqdn func(fqdn string, nameservers []string) (string, error)
+
+	// recordTTL overrides the TTL of the TXT record sets updated by the
+	// provider if it is greater than zero.
+	recordTTL int
+}
+
+// NewDNSProvider returns a DNSProvider instance configured for Gandi.
+// The personal access token must be passed in the environment variable
+// GANDI_TOKEN.
+func NewDNSProvider(dns01Nameservers []string) (*DNSProvider, error) {
+	token := os.Getenv("GANDI_TOKEN")
+	return NewDNSProviderCredentials(token, dns01Nameservers)
+}
+
+// NewDNSProviderCredentials uses the supplied personal access token to return
+// a DNSProvider instance configured for Gandi.
+func NewDNSProviderCredentials(token string, dns01Nameservers []string) (*DNSProvider, error) {
+	if token == "" {
+		return nil, fmt.Errorf("Gandi personal access token missing")
+	}
+	if strings.ContainsAny(token, "\r\n") {
+		return nil, fmt.Errorf("Gandi personal access token invalid (does the token contain a newline?)")
+	}
+
+	return &DNSProvider{
+		dns01Nameservers: dns01Nameservers,
+		token:            token,
+		apiURL:           LiveDNSAPIURL,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		findZoneByFqdn: util.FindZoneByFqdn,
+	}, nil
+}
+
+// Present adds a TXT record to fulfil the dns-01 challenge. Gandi replaces
+// the whole record set when it is updated, so any existing values in the
+// record set are preserved.
+func (c *DNSProvider) Present(domain, fqdn, value string) error {
+	zone, name, err := c.zoneAndName(fqdn)
+	if err != nil {
+		return err
+	}
+
+	rrset, err := c.getTXTRecordSet(zone, name)
+	if err != nil {
+		return err
+	}
+	if rrset == nil {
+		rrset = &recordSet{TTL: minTTL}
+	}
+	if c.recordTTL > 0 {
+		rrset.TTL = c.recordTTL
+	}
+
+	if rrset.contains(value) {
+		// the record set already contains the desired value
+		return nil
+	}
+	rrset.Values = append(rrset.Values, quote(value))
+
+	return c.putTXTRecordSet(zone, name, rrset)
+}
+
+// SetRecordTTL sets the TTL, in seconds, of the TXT records created by the
+// provider.
+func (c *DNSProvider) SetRecordTTL(ttl int) {
+	c.recordTTL = ttl
+}
+
+// CleanUp removes the given value from the TXT record set, deleting the
+// record set entirely if no other values remain.
+func (c *DNSProvider) CleanUp(domain, fqdn, value string) error {
+	zone, name, err := c.zoneAndName(fqdn)
+	if err != nil {
+		return err
+	}
+
+	rrset, err := c.getTXTRecordSet(zone, name)
+	if err != nil {
+		return err
+	}
+	// Nothing to cleanup
+	if rrset == nil || !rrset.contains(value) {
+		return nil
+	}
+
+	var remaining []string
+	for _, v := range rrset.Values {
+		if unquote(v) != value {
+			remaining = append(remaining, v)
+		}
+	}
+
+	if len(remaining) == 0 {
+		_, err := c.makeRequest("DELETE", recordSetPath(zone, name), nil)
+		return err
+	}
+
+	rrset.Values = remaining
+	return c.putTXTRecordSet(zone, name, rrset)
+}
+
+// zoneAndName returns the zone that the given fqdn belongs to, and the name
+// of the record relative to that zone, both without a trailing dot.
+func (c *DNSProvider) zoneAndName(fqdn string) (string, string, error) {
+	zone, err := c.findZoneByFqdn(fqdn, c.dns01Nameservers)
+	if err != nil {
+		return "", "", err
+	}
+
+	name := strings.TrimSuffix(util.UnFqdn(fqdn), "."+util.UnFqdn(zone))
+	if name == util.UnFqdn(fqdn) {
+		return "", "", fmt.Errorf("record %s is not in zone %s", fqdn, zone)
+	}
+
+	return util.UnFqdn(zone), name, nil
+}
+
+func (c *DNSProvider) getTXTRecordSet(zone, name string) (*recordSet, error) {
+	result, err := c.makeRequest("GET", recordSetPath(zone, name), nil)
+	if err == errRecordSetNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var rrset recordSet
+	if err := json.Unmarshal(result, &rrset); err != nil {
+		return nil, fmt.Errorf("failed to decode Gandi record set: %v", err)
+	}
+
+	return &rrset, nil
+}
+
+func (c *DNSProvider) putTXTRecordSet(zone, name string, rrset *recordSet) error {
+	if rrset.TTL < minTTL {
+		rrset.TTL = minTTL
+	}
+
+	body, err := json.Marshal(recordSet{TTL: rrset.TTL, Values: rrset.Values})
+	if err != nil {
+		return err
+	}
+
+	_, err = c.makeRequest("PUT", recordSetPath(zone, name), bytes.NewReader(body))
+	return err
+}
+
+var errRecordSetNotFound = errors.New("Gandi record set not found")
+
+func (c *DNSProvider) makeRequest(method, uri string, body io.Reader) (json.RawMessage, error) {
+	// APIError contains error details for failed requests
+	type APIError struct {
+		Code    int    `json:"code,omitempty"`
+		Message string `json:"message,omitempty"`
+		Cause   string `json:"cause,omitempty"`
+	}
+
+	req, err := http.NewRequest(method, c.apiURL+uri, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", pkgutil.CertManagerUserAgent)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Error querying Gandi API for %s %q -> %v", method, uri, err)
+	}
+	defer resp.Body.Close()
+
+	result, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading Gandi API response for %s %q -> %v", method, uri, err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound && method == "GET" {
+		return nil, errRecordSetNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr APIError
+		if err := json.Unmarshal(result, &apiErr); err == nil && apiErr.Message != "" {
+			return nil, fmt.Errorf("Gandi API error for %s %q: %d: %s", method, uri, resp.StatusCode, apiErr.Message)
+		}
+		return nil, fmt.Errorf("Gandi API error for %s %q: %d", method, uri, resp.StatusCode)
+	}
+
+	return result, nil
+}
+
+func recordSetPath(zone, name string) string {
+	return fmt.Sprintf("/domains/%s/records/%s/TXT", zone, name)
+}
+
+// recordSet represents a Gandi LiveDNS record set
+type recordSet struct {
+	TTL    int      `json:"rrset_ttl,omitempty"`
+	Values []string `json:"rrset_values"`
+}
+
+// contains returns true if the record set contains the given value. Gandi
+// returns TXT values enclosed in quotes, so values are compared unquoted.
+func (r *recordSet) contains(value string) bool {
+	for _, v := range r.Values {
+		if unquote(v) == value {
+			return true
+		}
+	}
+	return false
+}
+
+func quote(value string) string {
+	return `"` + value + `"`
+}
+
+func unquote(value string) string {
+	if len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
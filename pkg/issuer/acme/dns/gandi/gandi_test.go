@@ -0,0 +1,280 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gandi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jetstack/cert-manager/pkg/issuer/acme/dns/util"
+)
+
+var (
+	gandiLiveTest bool
+	gandiToken    string
+	gandiDomain   string
+)
+
+func init() {
+	gandiToken = os.Getenv("GANDI_TOKEN")
+	gandiDomain = os.Getenv("GANDI_DOMAIN")
+	if len(gandiToken) > 0 && len(gandiDomain) > 0 {
+		gandiLiveTest = true
+	}
+}
+
+func restoreEnv() {
+	os.Setenv("GANDI_TOKEN", gandiToken)
+}
+
+// mockLiveDNS is an in-memory implementation of the parts of the Gandi
+// LiveDNS API used by the DNSProvider.
+type mockLiveDNS struct {
+	t *testing.T
+
+	lock     sync.Mutex
+	rrsets   map[string]recordSet
+	requests []string
+}
+
+func newMockLiveDNS(t *testing.T, rrsets map[string]recordSet) (*mockLiveDNS, *httptest.Server) {
+	m := &mockLiveDNS{t: t, rrsets: rrsets}
+	if m.rrsets == nil {
+		m.rrsets = make(map[string]recordSet)
+	}
+	return m, httptest.NewServer(m)
+}
+
+func (m *mockLiveDNS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.requests = append(m.requests, r.Method+" "+r.URL.Path)
+
+	if r.Header.Get("Authorization") != "Bearer token" {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"code":403,"message":"Access was denied to this resource."}`))
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		rrset, ok := m.rrsets[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code":404,"message":"Can't find the DNS record.","object":"dns-record","cause":"Not Found"}`))
+			return
+		}
+		json.NewEncoder(w).Encode(rrset)
+	case "PUT":
+		var rrset recordSet
+		if err := json.NewDecoder(r.Body).Decode(&rrset); err != nil {
+			m.t.Errorf("failed to decode request body: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		m.rrsets[r.URL.Path] = rrset
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"message":"DNS Record Created"}`))
+	case "DELETE":
+		delete(m.rrsets, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func newTestProvider(t *testing.T, token, apiURL string) *DNSProvider {
+	provider, err := NewDNSProviderCredentials(token, util.RecursiveNameservers)
+	require.NoError(t, err)
+	provider.apiURL = apiURL
+	provider.findZoneByFqdn = func(fqdn string, nameservers []string) (string, error) {
+		return "example.com.", nil
+	}
+	return provider
+}
+
+const recordPath = "/domains/example.com/records/_acme-challenge/TXT"
+
+func TestNewDNSProviderValid(t *testing.T) {
+	os.Setenv("GANDI_TOKEN", "")
+	_, err := NewDNSProviderCredentials("123", util.RecursiveNameservers)
+	assert.NoError(t, err)
+	restoreEnv()
+}
+
+func TestNewDNSProviderValidEnv(t *testing.T) {
+	os.Setenv("GANDI_TOKEN", "123")
+	_, err := NewDNSProvider(util.RecursiveNameservers)
+	assert.NoError(t, err)
+	restoreEnv()
+}
+
+func TestNewDNSProviderMissingCredErr(t *testing.T) {
+	os.Setenv("GANDI_TOKEN", "")
+	_, err := NewDNSProvider(util.RecursiveNameservers)
+	assert.EqualError(t, err, "Gandi personal access token missing")
+	restoreEnv()
+}
+
+func TestNewDNSProviderInvalidToken(t *testing.T) {
+	_, err := NewDNSProviderCredentials("123\n", util.RecursiveNameservers)
+	assert.Error(t, err)
+}
+
+func TestGandiPresentCreatesRecordSet(t *testing.T) {
+	m, ts := newMockLiveDNS(t, nil)
+	defer ts.Close()
+
+	provider := newTestProvider(t, "token", ts.URL)
+	err := provider.Present("example.com", "_acme-challenge.example.com.", "123d==")
+	require.NoError(t, err)
+
+	assert.Equal(t, recordSet{TTL: 300, Values: []string{`"123d=="`}}, m.rrsets[recordPath])
+}
+
+func TestGandiPresentMergesExistingValues(t *testing.T) {
+	m, ts := newMockLiveDNS(t, map[string]recordSet{
+		recordPath: {TTL: 600, Values: []string{`"existing"`}},
+	})
+	defer ts.Close()
+
+	provider := newTestProvider(t, "token", ts.URL)
+	err := provider.Present("example.com", "_acme-challenge.example.com.", "123d==")
+	require.NoError(t, err)
+
+	assert.Equal(t, recordSet{TTL: 600, Values: []string{`"existing"`, `"123d=="`}}, m.rrsets[recordPath])
+}
+
+func TestGandiPresentIsIdempotent(t *testing.T) {
+	m, ts := newMockLiveDNS(t, map[string]recordSet{
+		recordPath: {TTL: 300, Values: []string{`"existing"`, `"123d=="`}},
+	})
+	defer ts.Close()
+
+	provider := newTestProvider(t, "token", ts.URL)
+	err := provider.Present("example.com", "_acme-challenge.example.com.", "123d==")
+	require.NoError(t, err)
+
+	assert.Equal(t, recordSet{TTL: 300, Values: []string{`"existing"`, `"123d=="`}}, m.rrsets[recordPath])
+	assert.Equal(t, []string{"GET " + recordPath}, m.requests)
+}
+
+func TestGandiPresentSubdomainZone(t *testing.T) {
+	m, ts := newMockLiveDNS(t, nil)
+	defer ts.Close()
+
+	provider := newTestProvider(t, "token", ts.URL)
+	err := provider.Present("www.example.com", "_acme-challenge.www.example.com.", "123d==")
+	require.NoError(t, err)
+
+	assert.Contains(t, m.rrsets, "/domains/example.com/records/_acme-challenge.www/TXT")
+}
+
+func TestGandiCleanUpKeepsOtherValues(t *testing.T) {
+	m, ts := newMockLiveDNS(t, map[string]recordSet{
+		recordPath: {TTL: 300, Values: []string{`"existing"`, `"123d=="`}},
+	})
+	defer ts.Close()
+
+	provider := newTestProvider(t, "token", ts.URL)
+	err := provider.CleanUp("example.com", "_acme-challenge.example.com.", "123d==")
+	require.NoError(t, err)
+
+	assert.Equal(t, recordSet{TTL: 300, Values: []string{`"existing"`}}, m.rrsets[recordPath])
+}
+
+func TestGandiCleanUpDeletesEmptyRecordSet(t *testing.T) {
+	m, ts := newMockLiveDNS(t, map[string]recordSet{
+		recordPath: {TTL: 300, Values: []string{`"123d=="`}},
+	})
+	defer ts.Close()
+
+	provider := newTestProvider(t, "token", ts.URL)
+	err := provider.CleanUp("example.com", "_acme-challenge.example.com.", "123d==")
+	require.NoError(t, err)
+
+	assert.NotContains(t, m.rrsets, recordPath)
+	assert.Equal(t, []string{"GET " + recordPath, "DELETE " + recordPath}, m.requests)
+}
+
+func TestGandiCleanUpMissingRecordSet(t *testing.T) {
+	m, ts := newMockLiveDNS(t, nil)
+	defer ts.Close()
+
+	provider := newTestProvider(t, "token", ts.URL)
+	err := provider.CleanUp("example.com", "_acme-challenge.example.com.", "123d==")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"GET " + recordPath}, m.requests)
+}
+
+func TestGandiCleanUpMissingValue(t *testing.T) {
+	m, ts := newMockLiveDNS(t, map[string]recordSet{
+		recordPath: {TTL: 300, Values: []string{`"existing"`}},
+	})
+	defer ts.Close()
+
+	provider := newTestProvider(t, "token", ts.URL)
+	err := provider.CleanUp("example.com", "_acme-challenge.example.com.", "123d==")
+	require.NoError(t, err)
+
+	assert.Equal(t, recordSet{TTL: 300, Values: []string{`"existing"`}}, m.rrsets[recordPath])
+	assert.Equal(t, []string{"GET " + recordPath}, m.requests)
+}
+
+func TestGandiAPIError(t *testing.T) {
+	_, ts := newMockLiveDNS(t, nil)
+	defer ts.Close()
+
+	provider := newTestProvider(t, "wrong-token", ts.URL)
+	err := provider.Present("example.com", "_acme-challenge.example.com.", "123d==")
+	require.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "Access was denied to this resource."), err.Error())
+}
+
+func TestGandiPresent(t *testing.T) {
+	if !gandiLiveTest {
+		t.Skip("skipping live test")
+	}
+
+	provider, err := NewDNSProviderCredentials(gandiToken, util.RecursiveNameservers)
+	assert.NoError(t, err)
+
+	err = provider.Present(gandiDomain, "_acme-challenge."+gandiDomain+".", "123d==")
+	assert.NoError(t, err)
+}
+
+func TestGandiCleanUp(t *testing.T) {
+	if !gandiLiveTest {
+		t.Skip("skipping live test")
+	}
+
+	provider, err := NewDNSProviderCredentials(gandiToken, util.RecursiveNameservers)
+	assert.NoError(t, err)
+
+	err = provider.CleanUp(gandiDomain, "_acme-challenge."+gandiDomain+".", "123d==")
+	assert.NoError(t, err)
+}
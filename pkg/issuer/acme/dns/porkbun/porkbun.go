@@ -0,0 +1,251 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package porkbun implements a DNS provider for solving the DNS-01 challenge
+// using the Porkbun DNS API.
+package porkbun
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jetstack/cert-manager/pkg/issuer/acme/dns/util"
+	pkgutil "github.com/jetstack/cert-manager/pkg/util"
+)
+
+// APIURL is the base URL of the Porkbun API.
+const APIURL = "https://api.porkbun.com/api/json/v3"
+
+// minTTL is the smallest TTL that Porkbun accepts for a record.
+const minTTL = 600
+
+// DNSProvider is an implementation of the acme.ChallengeProvider interface
+type DNSProvider struct {
+	dns01Nameservers []string
+	apiKey           string
+	secretAPIKey     string
+	apiURL           string
+	client           *http.Client
+
+	// findZoneByFqdn is used to determine the zone that a record belongs to.
+	// It is overridden in tests to avoid performing DNS lookups.
+	findZoneByFqdn func(fqdn string, nameservers []string) (string, error)
+
+	// recordTTL overrides the TTL of the TXT records created by the provider
+	// if it is greater than zero.
+	recordTTL int
+}
+
+// NewDNSProvider returns a DNSProvider instance configured for Porkbun.
+// The API key and secret API key must be passed in the environment variables
+// PORKBUN_API_KEY and PORKBUN_SECRET_API_KEY.
+func NewDNSProvider(dns01Nameservers []string) (*DNSProvider, error) {
+	apiKey := os.Getenv("PORKBUN_API_KEY")
+	secretAPIKey := os.Getenv("PORKBUN_SECRET_API_KEY")
+	return NewDNSProviderCredentials(apiKey, secretAPIKey, dns01Nameservers)
+}
+
+// NewDNSProviderCredentials uses the supplied API key and secret API key to
+// return a DNSProvider instance configured for Porkbun.
+func NewDNSProviderCredentials(apiKey, secretAPIKey string, dns01Nameservers []string) (*DNSProvider, error) {
+	if apiKey == "" || secretAPIKey == "" {
+		return nil, fmt.Errorf("Porkbun credentials missing")
+	}
+	if strings.ContainsAny(apiKey+secretAPIKey, "\r\n") {
+		return nil, fmt.Errorf("Porkbun credentials invalid (does the API key or secret API key contain a newline?)")
+	}
+
+	return &DNSProvider{
+		dns01Nameservers: dns01Nameservers,
+		apiKey:           apiKey,
+		secretAPIKey:     secretAPIKey,
+		apiURL:           APIURL,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		findZoneByFqdn: util.FindZoneByFqdn,
+	}, nil
+}
+
+// Present creates a TXT record to fulfil the dns-01 challenge.
+func (c *DNSProvider) Present(domain, fqdn, value string) error {
+	zone, name, err := c.zoneAndName(fqdn)
+	if err != nil {
+		return err
+	}
+
+	records, err := c.getTXTRecords(zone, name)
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		if record.Content == value {
+			// the record already exists
+			return nil
+		}
+	}
+
+	_, err = c.makeRequest(fmt.Sprintf("/dns/create/%s", zone), map[string]string{
+		"name":    name,
+		"type":    "TXT",
+		"content": value,
+		"ttl":     fmt.Sprint(c.ttl()),
+	})
+	return err
+}
+
+// SetRecordTTL sets the TTL, in seconds, of the TXT records created by the
+// provider.
+func (c *DNSProvider) SetRecordTTL(ttl int) {
+	c.recordTTL = ttl
+}
+
+func (c *DNSProvider) ttl() int {
+	if c.recordTTL > 0 {
+		return c.recordTTL
+	}
+	return minTTL
+}
+
+// CleanUp removes the TXT record matching the specified parameters.
+func (c *DNSProvider) CleanUp(domain, fqdn, value string) error {
+	zone, name, err := c.zoneAndName(fqdn)
+	if err != nil {
+		return err
+	}
+
+	records, err := c.getTXTRecords(zone, name)
+	if err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		if record.Content != value {
+			continue
+		}
+		_, err := c.makeRequest(fmt.Sprintf("/dns/delete/%s/%s", zone, record.ID), nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// zoneAndName returns the root domain that the given fqdn belongs to, and
+// the name of the record relative to that domain, both without a trailing
+// dot. Porkbun requires records to be addressed relative to the root domain.
+func (c *DNSProvider) zoneAndName(fqdn string) (string, string, error) {
+	zone, err := c.findZoneByFqdn(fqdn, c.dns01Nameservers)
+	if err != nil {
+		return "", "", err
+	}
+
+	name := strings.TrimSuffix(util.UnFqdn(fqdn), "."+util.UnFqdn(zone))
+	if name == util.UnFqdn(fqdn) {
+		return "", "", fmt.Errorf("record %s is not in zone %s", fqdn, zone)
+	}
+
+	return util.UnFqdn(zone), name, nil
+}
+
+func (c *DNSProvider) getTXTRecords(zone, name string) ([]dnsRecord, error) {
+	result, err := c.makeRequest(fmt.Sprintf("/dns/retrieveByNameType/%s/TXT/%s", zone, name), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Records []dnsRecord `json:"records"`
+	}
+	if err := json.Unmarshal(result, &resp); err != nil {
+		return nil, fmt.Errorf("failed to decode Porkbun records: %v", err)
+	}
+
+	return resp.Records, nil
+}
+
+// makeRequest performs a request against the Porkbun API. All Porkbun API
+// calls are POST requests with the credentials included in the JSON body.
+func (c *DNSProvider) makeRequest(uri string, params map[string]string) (json.RawMessage, error) {
+	// APIResponse contains the status of a request, and error details for
+	// failed requests
+	type APIResponse struct {
+		Status  string `json:"status"`
+		Message string `json:"message,omitempty"`
+	}
+
+	body := map[string]string{
+		"apikey":       c.apiKey,
+		"secretapikey": c.secretAPIKey,
+	}
+	for k, v := range params {
+		body[k] = v
+	}
+	reqBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", c.apiURL+uri, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", pkgutil.CertManagerUserAgent)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Error querying Porkbun API for %q -> %v", uri, err)
+	}
+	defer resp.Body.Close()
+
+	result, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading Porkbun API response for %q -> %v", uri, err)
+	}
+
+	var apiResp APIResponse
+	if err := json.Unmarshal(result, &apiResp); err != nil {
+		return nil, fmt.Errorf("Porkbun API error for %q: %d", uri, resp.StatusCode)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 || apiResp.Status != "SUCCESS" {
+		if apiResp.Message != "" {
+			return nil, fmt.Errorf("Porkbun API error for %q: %d: %s", uri, resp.StatusCode, apiResp.Message)
+		}
+		return nil, fmt.Errorf("Porkbun API error for %q: %d", uri, resp.StatusCode)
+	}
+
+	return result, nil
+}
+
+// dnsRecord represents a Porkbun DNS record
+type dnsRecord struct {
+	// ID is returned as a string when retrieving records, but as a number
+	// when creating them.
+	ID      json.Number `json:"id"`
+	Name    string      `json:"name"`
+	Type    string      `json:"type"`
+	Content string      `json:"content"`
+	TTL     string      `json:"ttl,omitempty"`
+}
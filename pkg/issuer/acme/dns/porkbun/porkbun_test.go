@@ -0,0 +1,293 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package porkbun
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jetstack/cert-manager/pkg/issuer/acme/dns/util"
+)
+
+var (
+	porkbunLiveTest     bool
+	porkbunAPIKey       string
+	porkbunSecretAPIKey string
+	porkbunDomain       string
+)
+
+func init() {
+	porkbunAPIKey = os.Getenv("PORKBUN_API_KEY")
+	porkbunSecretAPIKey = os.Getenv("PORKBUN_SECRET_API_KEY")
+	porkbunDomain = os.Getenv("PORKBUN_DOMAIN")
+	if len(porkbunAPIKey) > 0 && len(porkbunSecretAPIKey) > 0 && len(porkbunDomain) > 0 {
+		porkbunLiveTest = true
+	}
+}
+
+func restoreEnv() {
+	os.Setenv("PORKBUN_API_KEY", porkbunAPIKey)
+	os.Setenv("PORKBUN_SECRET_API_KEY", porkbunSecretAPIKey)
+}
+
+// mockPorkbun is an in-memory implementation of the parts of the Porkbun
+// API used by the DNSProvider.
+type mockPorkbun struct {
+	t *testing.T
+
+	lock     sync.Mutex
+	nextID   int
+	records  map[string]dnsRecord
+	requests []string
+}
+
+func newMockPorkbun(t *testing.T, records map[string]dnsRecord) (*mockPorkbun, *httptest.Server) {
+	m := &mockPorkbun{t: t, records: records, nextID: 1000}
+	if m.records == nil {
+		m.records = make(map[string]dnsRecord)
+	}
+	return m, httptest.NewServer(m)
+}
+
+func (m *mockPorkbun) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.requests = append(m.requests, r.URL.Path)
+
+	if r.Method != "POST" {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body map[string]string
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		m.t.Errorf("failed to decode request body: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if body["apikey"] != "key" || body["secretapikey"] != "secret" {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"status":"ERROR","message":"Invalid API key. (002)"}`))
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	switch {
+	case len(parts) == 5 && parts[0] == "dns" && parts[1] == "retrieveByNameType":
+		// /dns/retrieveByNameType/{domain}/{type}/{subdomain}
+		records := []dnsRecord{}
+		for _, record := range m.records {
+			if record.Name == parts[4]+"."+parts[2] && record.Type == parts[3] {
+				records = append(records, record)
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "SUCCESS", "records": records})
+	case len(parts) == 3 && parts[0] == "dns" && parts[1] == "create":
+		// /dns/create/{domain}
+		m.nextID++
+		id := fmt.Sprint(m.nextID)
+		m.records[id] = dnsRecord{
+			ID:      json.Number(id),
+			Name:    body["name"] + "." + parts[2],
+			Type:    body["type"],
+			Content: body["content"],
+			TTL:     body["ttl"],
+		}
+		// the ID of created records is returned as a number
+		fmt.Fprintf(w, `{"status":"SUCCESS","id":%s}`, id)
+	case len(parts) == 4 && parts[0] == "dns" && parts[1] == "delete":
+		// /dns/delete/{domain}/{id}
+		if _, ok := m.records[parts[3]]; !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"status":"ERROR","message":"Invalid record ID."}`))
+			return
+		}
+		delete(m.records, parts[3])
+		w.Write([]byte(`{"status":"SUCCESS"}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newTestProvider(t *testing.T, apiKey, secretAPIKey, apiURL string) *DNSProvider {
+	provider, err := NewDNSProviderCredentials(apiKey, secretAPIKey, util.RecursiveNameservers)
+	require.NoError(t, err)
+	provider.apiURL = apiURL
+	provider.findZoneByFqdn = func(fqdn string, nameservers []string) (string, error) {
+		return "example.com.", nil
+	}
+	return provider
+}
+
+const retrievePath = "/dns/retrieveByNameType/example.com/TXT/_acme-challenge"
+
+func txtRecord(id, content string) dnsRecord {
+	return dnsRecord{ID: json.Number(id), Name: "_acme-challenge.example.com", Type: "TXT", Content: content, TTL: "600"}
+}
+
+func TestNewDNSProviderValid(t *testing.T) {
+	os.Setenv("PORKBUN_API_KEY", "")
+	os.Setenv("PORKBUN_SECRET_API_KEY", "")
+	_, err := NewDNSProviderCredentials("123", "456", util.RecursiveNameservers)
+	assert.NoError(t, err)
+	restoreEnv()
+}
+
+func TestNewDNSProviderValidEnv(t *testing.T) {
+	os.Setenv("PORKBUN_API_KEY", "123")
+	os.Setenv("PORKBUN_SECRET_API_KEY", "456")
+	_, err := NewDNSProvider(util.RecursiveNameservers)
+	assert.NoError(t, err)
+	restoreEnv()
+}
+
+func TestNewDNSProviderMissingCredErr(t *testing.T) {
+	os.Setenv("PORKBUN_API_KEY", "123")
+	os.Setenv("PORKBUN_SECRET_API_KEY", "")
+	_, err := NewDNSProvider(util.RecursiveNameservers)
+	assert.EqualError(t, err, "Porkbun credentials missing")
+	restoreEnv()
+}
+
+func TestNewDNSProviderInvalidCredentials(t *testing.T) {
+	_, err := NewDNSProviderCredentials("123", "456\n", util.RecursiveNameservers)
+	assert.Error(t, err)
+}
+
+func TestPorkbunPresentCreatesRecord(t *testing.T) {
+	m, ts := newMockPorkbun(t, nil)
+	defer ts.Close()
+
+	provider := newTestProvider(t, "key", "secret", ts.URL)
+	err := provider.Present("example.com", "_acme-challenge.example.com.", "123d==")
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]dnsRecord{"1001": txtRecord("1001", "123d==")}, m.records)
+	assert.Equal(t, []string{retrievePath, "/dns/create/example.com"}, m.requests)
+}
+
+func TestPorkbunPresentIsIdempotent(t *testing.T) {
+	m, ts := newMockPorkbun(t, map[string]dnsRecord{
+		"1": txtRecord("1", "123d=="),
+	})
+	defer ts.Close()
+
+	provider := newTestProvider(t, "key", "secret", ts.URL)
+	err := provider.Present("example.com", "_acme-challenge.example.com.", "123d==")
+	require.NoError(t, err)
+
+	assert.Len(t, m.records, 1)
+	assert.Equal(t, []string{retrievePath}, m.requests)
+}
+
+func TestPorkbunPresentSubdomain(t *testing.T) {
+	m, ts := newMockPorkbun(t, nil)
+	defer ts.Close()
+
+	provider := newTestProvider(t, "key", "secret", ts.URL)
+	err := provider.Present("www.example.com", "_acme-challenge.www.example.com.", "123d==")
+	require.NoError(t, err)
+
+	// the record name is relative to the root domain
+	assert.Equal(t, []string{"/dns/retrieveByNameType/example.com/TXT/_acme-challenge.www", "/dns/create/example.com"}, m.requests)
+	assert.Equal(t, "_acme-challenge.www.example.com", m.records["1001"].Name)
+}
+
+func TestPorkbunCleanUpDeletesRecordByID(t *testing.T) {
+	m, ts := newMockPorkbun(t, map[string]dnsRecord{
+		"1": txtRecord("1", "existing"),
+		"2": txtRecord("2", "123d=="),
+	})
+	defer ts.Close()
+
+	provider := newTestProvider(t, "key", "secret", ts.URL)
+	err := provider.CleanUp("example.com", "_acme-challenge.example.com.", "123d==")
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]dnsRecord{"1": txtRecord("1", "existing")}, m.records)
+	assert.Equal(t, []string{retrievePath, "/dns/delete/example.com/2"}, m.requests)
+}
+
+func TestPorkbunCleanUpMissingRecord(t *testing.T) {
+	m, ts := newMockPorkbun(t, map[string]dnsRecord{
+		"1": txtRecord("1", "existing"),
+	})
+	defer ts.Close()
+
+	provider := newTestProvider(t, "key", "secret", ts.URL)
+	err := provider.CleanUp("example.com", "_acme-challenge.example.com.", "123d==")
+	require.NoError(t, err)
+
+	assert.Len(t, m.records, 1)
+	assert.Equal(t, []string{retrievePath}, m.requests)
+}
+
+func TestPorkbunPresentAndCleanUp(t *testing.T) {
+	m, ts := newMockPorkbun(t, nil)
+	defer ts.Close()
+
+	provider := newTestProvider(t, "key", "secret", ts.URL)
+	require.NoError(t, provider.Present("example.com", "_acme-challenge.example.com.", "123d=="))
+	require.NoError(t, provider.CleanUp("example.com", "_acme-challenge.example.com.", "123d=="))
+
+	assert.Empty(t, m.records)
+	assert.Equal(t, "/dns/delete/example.com/1001", m.requests[len(m.requests)-1])
+}
+
+func TestPorkbunAPIError(t *testing.T) {
+	_, ts := newMockPorkbun(t, nil)
+	defer ts.Close()
+
+	provider := newTestProvider(t, "key", "wrong-secret", ts.URL)
+	err := provider.Present("example.com", "_acme-challenge.example.com.", "123d==")
+	require.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "Invalid API key. (002)"), err.Error())
+}
+
+func TestPorkbunPresent(t *testing.T) {
+	if !porkbunLiveTest {
+		t.Skip("skipping live test")
+	}
+
+	provider, err := NewDNSProviderCredentials(porkbunAPIKey, porkbunSecretAPIKey, util.RecursiveNameservers)
+	assert.NoError(t, err)
+
+	err = provider.Present(porkbunDomain, "_acme-challenge."+porkbunDomain+".", "123d==")
+	assert.NoError(t, err)
+}
+
+func TestPorkbunCleanUp(t *testing.T) {
+	if !porkbunLiveTest {
+		t.Skip("skipping live test")
+	}
+
+	provider, err := NewDNSProviderCredentials(porkbunAPIKey, porkbunSecretAPIKey, util.RecursiveNameservers)
+	assert.NoError(t, err)
+
+	err = provider.CleanUp(porkbunDomain, "_acme-challenge."+porkbunDomain+".", "123d==")
+	assert.NoError(t, err)
+}
@@ -42,6 +42,10 @@ type DNSProvider struct {
 	client           *route53.Route53
 	hostedZoneID     string
 	log              logr.Logger
+
+	// recordTTL overrides the TTL of the TXT records managed by the
+	// provider if it is greater than zero.
+	recordTTL int
 }
 
 type sessionProvider struct {
@@ -166,13 +170,29 @@ func NewDNSProvider(accessKeyID, secretAccessKey, hostedZoneID, region, role str
 // Present creates a TXT record using the specified parameters
 func (r *DNSProvider) Present(domain, fqdn, value string) error {
 	value = `"` + value + `"`
-	return r.changeRecord(route53.ChangeActionUpsert, fqdn, value, route53TTL)
+	return r.changeRecord(route53.ChangeActionUpsert, fqdn, value, r.ttl())
 }
 
 // CleanUp removes the TXT record matching the specified parameters
 func (r *DNSProvider) CleanUp(domain, fqdn, value string) error {
 	value = `"` + value + `"`
-	return r.changeRecord(route53.ChangeActionDelete, fqdn, value, route53TTL)
+	return r.changeRecord(route53.ChangeActionDelete, fqdn, value, r.ttl())
+}
+
+// SetRecordTTL sets the TTL, in seconds, of the TXT records managed by the
+// provider.
+func (r *DNSProvider) SetRecordTTL(ttl int) {
+	r.recordTTL = ttl
+}
+
+// ttl returns the TTL of the TXT records managed by the provider. Route 53
+// only deletes a record set if its TTL matches, so the same TTL must be used
+// when presenting and cleaning up a record.
+func (r *DNSProvider) ttl() int {
+	if r.recordTTL > 0 {
+		return r.recordTTL
+	}
+	return route53TTL
 }
 
 func (r *DNSProvider) changeRecord(action, fqdn, value string, ttl int) error {
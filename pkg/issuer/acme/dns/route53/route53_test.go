@@ -11,6 +11,8 @@ package route53
 import (
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"net/http"
 	"net/http/httptest"
 	"os"
 	"testing"
@@ -149,6 +151,64 @@ func TestRoute53Present(t *testing.T) {
 	assert.Equal(t, `Failed to change Route 53 record set: AccessDenied: User: arn:aws:iam::0123456789:user/test-cert-manager is not authorized to perform: route53:ChangeResourceRecordSets on resource: arn:aws:route53:::hostedzone/OPQRSTU`, err.Error())
 }
 
+func TestRoute53RecordTTL(t *testing.T) {
+	tests := map[string]struct {
+		recordTTL   int
+		expectedTTL string
+	}{
+		"uses the default ttl if not set": {
+			expectedTTL: "<TTL>10</TTL>",
+		},
+		"uses the configured ttl": {
+			recordTTL:   300,
+			expectedTTL: "<TTL>300</TTL>",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var changes []string
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var body string
+				switch r.URL.Path {
+				case "/2013-04-01/hostedzonesbyname":
+					body = ListHostedZonesByNameResponse
+				case "/2013-04-01/hostedzone/ABCDEFG/rrset/":
+					b, err := ioutil.ReadAll(r.Body)
+					if err != nil {
+						t.Errorf("failed to read request body: %v", err)
+					}
+					changes = append(changes, string(b))
+					body = ChangeResourceRecordSetsResponse
+				case "/2013-04-01/change/123456":
+					body = GetChangeResponse
+				default:
+					t.Errorf("unexpected request path %q", r.URL.Path)
+				}
+				w.Header().Set("Content-Type", "application/xml")
+				_, _ = w.Write([]byte(body))
+			}))
+			defer ts.Close()
+
+			provider, err := makeRoute53Provider(ts)
+			require.NoError(t, err, "Expected to make a Route 53 provider without error")
+			if test.recordTTL > 0 {
+				provider.SetRecordTTL(test.recordTTL)
+			}
+
+			fqdn := "_acme-challenge.example.com."
+			require.NoError(t, provider.Present("example.com", fqdn, "123456d=="), "Expected Present to return no error")
+			require.NoError(t, provider.CleanUp("example.com", fqdn, "123456d=="), "Expected CleanUp to return no error")
+
+			require.Len(t, changes, 2)
+			for i, action := range []string{"UPSERT", "DELETE"} {
+				assert.Contains(t, changes[i], "<Action>"+action+"</Action>")
+				assert.Contains(t, changes[i], test.expectedTTL)
+			}
+		})
+	}
+}
+
 func TestAssumeRole(t *testing.T) {
 	creds := &sts.Credentials{
 		AccessKeyId:     aws.String("foo"),
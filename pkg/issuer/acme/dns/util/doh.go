@@ -0,0 +1,94 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/miekg/dns"
+)
+
+const (
+	// dohMediaType is the media type used for DNS-over-HTTPS wire format
+	// messages, as defined in RFC 8484.
+	dohMediaType = "application/dns-message"
+
+	// dohMaxMessageSize is the maximum size of a DNS message.
+	dohMaxMessageSize = 65535
+)
+
+// dohClient is the HTTP client used to perform DNS-over-HTTPS queries.
+// It is a variable so that it can be overridden in tests.
+var dohClient = &http.Client{}
+
+// IsDoHResolver returns true if the given nameserver is a DNS-over-HTTPS
+// endpoint URL rather than a host:port address.
+func IsDoHResolver(nameserver string) bool {
+	return strings.HasPrefix(nameserver, "https://")
+}
+
+// dohExchange performs a DNS query against the given DNS-over-HTTPS
+// endpoint using the POST method described in RFC 8484.
+func dohExchange(m *dns.Msg, endpoint string) (*dns.Msg, error) {
+	// RFC 8484 recommends a message ID of 0 to maximise HTTP cache
+	// friendliness.
+	q := m.Copy()
+	q.Id = 0
+
+	packed, err := q.Pack()
+	if err != nil {
+		return nil, fmt.Errorf("error packing DNS query: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), DNSTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(packed))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", dohMediaType)
+	req.Header.Set("Accept", dohMediaType)
+
+	resp, err := dohClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error querying DNS-over-HTTPS resolver %q: %v", endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code from DNS-over-HTTPS resolver %q: %d", endpoint, resp.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, dohMaxMessageSize))
+	if err != nil {
+		return nil, fmt.Errorf("error reading response from DNS-over-HTTPS resolver %q: %v", endpoint, err)
+	}
+
+	in := new(dns.Msg)
+	if err := in.Unpack(body); err != nil {
+		return nil, fmt.Errorf("error unpacking response from DNS-over-HTTPS resolver %q: %v", endpoint, err)
+	}
+
+	return in, nil
+}
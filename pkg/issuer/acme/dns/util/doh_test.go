@@ -0,0 +1,133 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/miekg/dns"
+)
+
+// newMockDoHServer returns a DNS-over-HTTPS server that answers every TXT
+// query for fqdn with the given value, and NXDOMAIN for anything else.
+func newMockDoHServer(t *testing.T, fqdn, value string) *httptest.Server {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != dohMediaType {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		q := new(dns.Msg)
+		if err := q.Unpack(body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		resp := new(dns.Msg)
+		resp.SetReply(q)
+		if q.Question[0].Name == fqdn && q.Question[0].Qtype == dns.TypeTXT {
+			resp.Answer = append(resp.Answer, &dns.TXT{
+				Hdr: dns.RR_Header{Name: fqdn, Rrtype: dns.TypeTXT, Class: dns.ClassINET, Ttl: 60},
+				Txt: []string{value},
+			})
+		} else {
+			resp.Rcode = dns.RcodeNameError
+		}
+
+		packed, err := resp.Pack()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", dohMediaType)
+		w.Write(packed)
+	}))
+
+	oldClient := dohClient
+	dohClient = server.Client()
+	t.Cleanup(func() {
+		dohClient = oldClient
+		server.Close()
+	})
+
+	return server
+}
+
+func TestIsDoHResolver(t *testing.T) {
+	tests := map[string]bool{
+		"https://dns.example.com/dns-query": true,
+		"8.8.8.8:53":                        false,
+		"http://dns.example.com/dns-query":  false,
+	}
+	for ns, exp := range tests {
+		if got := IsDoHResolver(ns); got != exp {
+			t.Errorf("%s: got %t; want %t", ns, got, exp)
+		}
+	}
+}
+
+func TestCheckAuthoritativeNssDoH(t *testing.T) {
+	const fqdn = "_acme-challenge.example.com."
+	server := newMockDoHServer(t, fqdn, "challenge-value")
+
+	ok, err := checkAuthoritativeNss(fqdn, "challenge-value", []string{server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ok {
+		t.Errorf("expected TXT record to be found using DoH resolver")
+	}
+
+	ok, err = checkAuthoritativeNss(fqdn, "other-value", []string{server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ok {
+		t.Errorf("expected non-matching TXT record to not be found using DoH resolver")
+	}
+
+	ok, err = checkAuthoritativeNss("_acme-challenge.missing.example.com.", "challenge-value", []string{server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ok {
+		t.Errorf("expected NXDOMAIN response to not find the TXT record")
+	}
+}
+
+func TestDNSQueryDoHError(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	oldClient := dohClient
+	dohClient = server.Client()
+	defer func() { dohClient = oldClient }()
+
+	if _, err := DNSQuery("example.com.", dns.TypeTXT, []string{server.URL}, true); err == nil {
+		t.Errorf("expected an error from a failing DoH resolver")
+	}
+}
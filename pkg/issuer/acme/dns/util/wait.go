@@ -171,6 +171,14 @@ func DNSQuery(fqdn string, rtype uint16, nameservers []string, recursive bool) (
 	// Will retry the request based on the number of servers (n+1)
 	for i := 1; i <= len(nameservers)+1; i++ {
 		ns := nameservers[i%len(nameservers)]
+		if IsDoHResolver(ns) {
+			in, err = dohExchange(m, ns)
+			if err == nil {
+				break
+			}
+			continue
+		}
+
 		udp := &dns.Client{Net: "udp", Timeout: DNSTimeout}
 		in, _, err = udp.Exchange(m, ns)
 
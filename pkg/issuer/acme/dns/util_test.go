@@ -28,6 +28,8 @@ import (
 	"github.com/jetstack/cert-manager/pkg/issuer/acme/dns/clouddns"
 	"github.com/jetstack/cert-manager/pkg/issuer/acme/dns/cloudflare"
 	"github.com/jetstack/cert-manager/pkg/issuer/acme/dns/digitalocean"
+	"github.com/jetstack/cert-manager/pkg/issuer/acme/dns/gandi"
+	"github.com/jetstack/cert-manager/pkg/issuer/acme/dns/porkbun"
 	"github.com/jetstack/cert-manager/pkg/issuer/acme/dns/route53"
 	"github.com/jetstack/cert-manager/pkg/issuer/acme/dns/util"
 	"github.com/jetstack/cert-manager/test/unit/gen"
@@ -155,6 +157,14 @@ func newFakeDNSProviders() *fakeDNSProviders {
 			f.call("digitalocean", token, util.RecursiveNameservers)
 			return nil, nil
 		},
+		gandi: func(token string, dns01Nameservers []string) (*gandi.DNSProvider, error) {
+			f.call("gandi", token, util.RecursiveNameservers)
+			return nil, nil
+		},
+		porkbun: func(apiKey, secretAPIKey string, dns01Nameservers []string) (*porkbun.DNSProvider, error) {
+			f.call("porkbun", apiKey, secretAPIKey, util.RecursiveNameservers)
+			return nil, nil
+		},
 	}
 	return f
 }
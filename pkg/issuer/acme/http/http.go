@@ -25,10 +25,12 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	k8snet "k8s.io/utils/net"
 
+	"k8s.io/apimachinery/pkg/types"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	corev1listers "k8s.io/client-go/listers/core/v1"
 	networkingv1beta1listers "k8s.io/client-go/listers/networking/v1beta1"
@@ -63,6 +65,25 @@ type Solver struct {
 
 	testReachability reachabilityTest
 	requiredPasses   int
+
+	// cleanupDelay is the time to wait after a challenge has become valid
+	// before its solver resources are deleted.
+	cleanupDelay time.Duration
+	// cleanupDeferredUntil holds the time after which the solver resources
+	// for a valid challenge may be deleted, keyed by the challenge UID.
+	cleanupDeferredUntil map[types.UID]time.Time
+	cleanupLock          sync.Mutex
+}
+
+// CleanupDeferredError is returned by CleanUp when the deletion of the
+// solver resources has been deferred by the configured cleanup delay. CleanUp
+// should be called again once RetryAfter has elapsed.
+type CleanupDeferredError struct {
+	RetryAfter time.Duration
+}
+
+func (e *CleanupDeferredError) Error() string {
+	return fmt.Sprintf("clean up of http01 solver resources deferred for %s", e.RetryAfter)
 }
 
 type reachabilityTest func(ctx context.Context, url *url.URL, key string) error
@@ -71,12 +92,14 @@ type reachabilityTest func(ctx context.Context, url *url.URL, key string) error
 // TODO: refactor this to have fewer args
 func NewSolver(ctx *controller.Context) *Solver {
 	return &Solver{
-		Context:          ctx,
-		podLister:        ctx.KubeSharedInformerFactory.Core().V1().Pods().Lister(),
-		serviceLister:    ctx.KubeSharedInformerFactory.Core().V1().Services().Lister(),
-		ingressLister:    ctx.KubeSharedInformerFactory.Networking().V1beta1().Ingresses().Lister(),
-		testReachability: testReachability,
-		requiredPasses:   5,
+		Context:              ctx,
+		podLister:            ctx.KubeSharedInformerFactory.Core().V1().Pods().Lister(),
+		serviceLister:        ctx.KubeSharedInformerFactory.Core().V1().Services().Lister(),
+		ingressLister:        ctx.KubeSharedInformerFactory.Networking().V1beta1().Ingresses().Lister(),
+		testReachability:     testReachability,
+		requiredPasses:       5,
+		cleanupDelay:         ctx.HTTP01SolverCleanupDelay,
+		cleanupDeferredUntil: make(map[types.UID]time.Time),
 	}
 }
 
@@ -131,9 +154,14 @@ func (s *Solver) Check(ctx context.Context, issuer v1.GenericIssuer, ch *cmacme.
 	log = log.WithValues("url", url)
 	ctx = logf.NewContext(ctx, log)
 
+	var readTimeout time.Duration
+	if ch.Spec.Solver.HTTP01 != nil && ch.Spec.Solver.HTTP01.Ingress != nil && ch.Spec.Solver.HTTP01.Ingress.ReadTimeoutSeconds != nil {
+		readTimeout = time.Duration(*ch.Spec.Solver.HTTP01.Ingress.ReadTimeoutSeconds) * time.Second
+	}
+
 	log.V(logf.DebugLevel).Info("running self check multiple times to ensure challenge has propagated", "required_passes", s.requiredPasses)
 	for i := 0; i < s.requiredPasses; i++ {
-		err := s.testReachability(ctx, url, ch.Spec.Key)
+		err := s.testReachabilityWithTimeout(ctx, url, ch.Spec.Key, readTimeout)
 		if err != nil {
 			return err
 		}
@@ -146,9 +174,25 @@ func (s *Solver) Check(ctx context.Context, issuer v1.GenericIssuer, ch *cmacme.
 	return nil
 }
 
+// testReachabilityWithTimeout runs a single reachability test, bounding it by
+// the given timeout if one is set.
+func (s *Solver) testReachabilityWithTimeout(ctx context.Context, url *url.URL, key string, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	return s.testReachability(ctx, url, key)
+}
+
 // CleanUp will ensure the created service, ingress and pod are clean/deleted of any
 // cert-manager created data.
 func (s *Solver) CleanUp(ctx context.Context, issuer v1.GenericIssuer, ch *cmacme.Challenge) error {
+	if retryAfter := s.cleanupDeferral(ch); retryAfter > 0 {
+		return &CleanupDeferredError{RetryAfter: retryAfter}
+	}
+
 	var errs []error
 	errs = append(errs, s.cleanupPods(ctx, ch))
 	errs = append(errs, s.cleanupServices(ctx, ch))
@@ -156,6 +200,33 @@ func (s *Solver) CleanUp(ctx context.Context, issuer v1.GenericIssuer, ch *cmacm
 	return utilerrors.NewAggregate(errs)
 }
 
+// cleanupDeferral returns how much longer the deletion of the solver
+// resources for the given challenge should be deferred. Only valid challenges
+// that are not being deleted are deferred, starting from the first time
+// CleanUp is called for them.
+func (s *Solver) cleanupDeferral(ch *cmacme.Challenge) time.Duration {
+	s.cleanupLock.Lock()
+	defer s.cleanupLock.Unlock()
+
+	if s.cleanupDelay <= 0 || ch.Status.State != cmacme.Valid || ch.DeletionTimestamp != nil {
+		delete(s.cleanupDeferredUntil, ch.UID)
+		return 0
+	}
+
+	now := s.Clock.Now()
+	until, ok := s.cleanupDeferredUntil[ch.UID]
+	if !ok {
+		until = now.Add(s.cleanupDelay)
+		s.cleanupDeferredUntil[ch.UID] = until
+	}
+	if now.Before(until) {
+		return until.Sub(now)
+	}
+
+	delete(s.cleanupDeferredUntil, ch.UID)
+	return 0
+}
+
 func (s *Solver) buildChallengeUrl(ch *cmacme.Challenge) *url.URL {
 	url := &url.URL{}
 	url.Scheme = "http"
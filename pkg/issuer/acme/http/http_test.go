@@ -18,11 +18,17 @@ package http
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/url"
 	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakeclock "k8s.io/utils/clock/testing"
 
 	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
+	"github.com/jetstack/cert-manager/pkg/controller/test"
 )
 
 // countReachabilityTestCalls is a wrapper function that allows us to count the number
@@ -56,6 +62,28 @@ func TestCheck(t *testing.T) {
 			},
 			expectedErr: true,
 		},
+		{
+			name: "should bound each check by the configured read timeout",
+			reachabilityTest: func(ctx context.Context, _ *url.URL, _ string) error {
+				deadline, ok := ctx.Deadline()
+				if !ok || time.Until(deadline) > 5*time.Second {
+					return fmt.Errorf("expected check to time out within 5s, got deadline %v", deadline)
+				}
+				return nil
+			},
+			challenge: &cmacme.Challenge{
+				Spec: cmacme.ChallengeSpec{
+					Solver: cmacme.ACMEChallengeSolver{
+						HTTP01: &cmacme.ACMEChallengeSolverHTTP01{
+							Ingress: &cmacme.ACMEChallengeSolverHTTP01Ingress{
+								ReadTimeoutSeconds: func(i int32) *int32 { return &i }(5),
+							},
+						},
+					},
+				},
+			},
+			expectedErr: false,
+		},
 	}
 
 	for i := range tests {
@@ -87,3 +115,107 @@ func TestCheck(t *testing.T) {
 		})
 	}
 }
+
+func TestCleanUpDelay(t *testing.T) {
+	tests := map[string]struct {
+		state        cmacme.State
+		deleting     bool
+		cleanupDelay time.Duration
+		// expectedDeferrals is the RetryAfter expected from each call to
+		// CleanUp before the solver resources are deleted, with the clock
+		// stepped forward by 30s between calls.
+		expectedDeferrals []time.Duration
+	}{
+		"valid challenge with no cleanup delay is cleaned up immediately": {
+			state: cmacme.Valid,
+		},
+		"valid challenge is cleaned up after the cleanup delay": {
+			state:             cmacme.Valid,
+			cleanupDelay:      time.Minute,
+			expectedDeferrals: []time.Duration{time.Minute, 30 * time.Second},
+		},
+		"invalid challenge is cleaned up immediately": {
+			state:        cmacme.Invalid,
+			cleanupDelay: time.Minute,
+		},
+		"valid challenge that is being deleted is cleaned up immediately": {
+			state:        cmacme.Valid,
+			deleting:     true,
+			cleanupDelay: time.Minute,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			fakeClock := fakeclock.NewFakeClock(time.Now())
+			ch := &cmacme.Challenge{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-challenge",
+					Namespace: defaultTestNamespace,
+					UID:       "test-uid",
+				},
+				Spec: cmacme.ChallengeSpec{
+					DNSName: "example.com",
+					Token:   "token",
+					Key:     "key",
+					Solver: cmacme.ACMEChallengeSolver{
+						HTTP01: &cmacme.ACMEChallengeSolverHTTP01{
+							Ingress: &cmacme.ACMEChallengeSolverHTTP01Ingress{},
+						},
+					},
+				},
+				Status: cmacme.ChallengeStatus{
+					State: tt.state,
+				},
+			}
+			if tt.deleting {
+				ch.DeletionTimestamp = &metav1.Time{Time: fakeClock.Now()}
+			}
+
+			s := &solverFixture{
+				Builder:   &test.Builder{Clock: fakeClock},
+				Challenge: ch,
+			}
+			s.Setup(t)
+			defer s.Builder.Stop()
+			s.Solver.cleanupDelay = tt.cleanupDelay
+
+			if _, err := s.Solver.createPod(ch); err != nil {
+				t.Fatalf("error preparing test: %v", err)
+			}
+			s.Builder.Sync()
+
+			podDeletes := func() int {
+				deletes := 0
+				for _, action := range s.Builder.FakeKubeClient().Actions() {
+					if action.GetVerb() == "delete" && action.GetResource().Resource == "pods" {
+						deletes++
+					}
+				}
+				return deletes
+			}
+
+			for _, expected := range tt.expectedDeferrals {
+				err := s.Solver.CleanUp(context.Background(), nil, ch)
+				var deferred *CleanupDeferredError
+				if !errors.As(err, &deferred) {
+					t.Fatalf("expected clean up to be deferred, got error: %v", err)
+				}
+				if deferred.RetryAfter != expected {
+					t.Errorf("expected clean up to be deferred for %s, got %s", expected, deferred.RetryAfter)
+				}
+				if deletes := podDeletes(); deletes != 0 {
+					t.Fatalf("expected no solver pods to be deleted while clean up is deferred, got %d", deletes)
+				}
+				fakeClock.Step(30 * time.Second)
+			}
+
+			if err := s.Solver.CleanUp(context.Background(), nil, ch); err != nil {
+				t.Fatalf("unexpected error cleaning up: %v", err)
+			}
+			if deletes := podDeletes(); deletes != 1 {
+				t.Errorf("expected the solver pod to be deleted, got %d deletes", deletes)
+			}
+		})
+	}
+}
@@ -253,5 +253,9 @@ func (s *Solver) mergePodObjectMetaWithPodTemplate(pod *corev1.Pod, podTempl *cm
 		pod.Spec.ServiceAccountName = podTempl.Spec.ServiceAccountName
 	}
 
+	if podTempl.Spec.Image != "" {
+		pod.Spec.Containers[0].Image = podTempl.Spec.Image
+	}
+
 	return pod
 }
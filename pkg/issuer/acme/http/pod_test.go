@@ -297,6 +297,17 @@ func TestMergePodObjectMetaWithPodTemplate(t *testing.T) {
 											},
 										},
 										ServiceAccountName: "cert-manager",
+										Affinity: &corev1.Affinity{
+											NodeAffinity: &corev1.NodeAffinity{
+												RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+													NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+														MatchExpressions: []corev1.NodeSelectorRequirement{
+															{Key: "egress", Operator: corev1.NodeSelectorOpIn, Values: []string{"true"}},
+														},
+													}},
+												},
+											},
+										},
 									},
 								},
 							},
@@ -328,6 +339,17 @@ func TestMergePodObjectMetaWithPodTemplate(t *testing.T) {
 				}
 				resultingPod.Spec.PriorityClassName = "high"
 				resultingPod.Spec.ServiceAccountName = "cert-manager"
+				resultingPod.Spec.Affinity = &corev1.Affinity{
+					NodeAffinity: &corev1.NodeAffinity{
+						RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+							NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+								MatchExpressions: []corev1.NodeSelectorRequirement{
+									{Key: "egress", Operator: corev1.NodeSelectorOpIn, Values: []string{"true"}},
+								},
+							}},
+						},
+					},
+				}
 				s.testResources[createdPodKey] = resultingPod
 
 				s.Builder.Sync()
@@ -402,6 +424,42 @@ func TestMergePodObjectMetaWithPodTemplate(t *testing.T) {
 				}
 			},
 		},
+		"should use the solver image from template": {
+			Challenge: &cmacme.Challenge{
+				Spec: cmacme.ChallengeSpec{
+					DNSName: "example.com",
+					Solver: cmacme.ACMEChallengeSolver{
+						HTTP01: &cmacme.ACMEChallengeSolverHTTP01{
+							Ingress: &cmacme.ACMEChallengeSolverHTTP01Ingress{
+								PodTemplate: &cmacme.ACMEChallengeSolverHTTP01IngressPodTemplate{
+									Spec: cmacme.ACMEChallengeSolverHTTP01IngressPodSpec{
+										Image: "registry.internal/cert-manager-acmesolver:v1.0.0",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			PreFn: func(t *testing.T, s *solverFixture) {
+				s.Builder.Sync()
+			},
+			CheckFn: func(t *testing.T, s *solverFixture, args ...interface{}) {
+				resp, ok := args[0].(*corev1.Pod)
+				if !ok {
+					t.Errorf("expected pod to be returned, but got %v", args[0])
+					return
+				}
+
+				if len(resp.Spec.Containers) != 1 {
+					t.Errorf("expected pod to have 1 container, but got %d", len(resp.Spec.Containers))
+					return
+				}
+				if image := resp.Spec.Containers[0].Image; image != "registry.internal/cert-manager-acmesolver:v1.0.0" {
+					t.Errorf("expected solver image to be overridden, but got %q", image)
+				}
+			},
+		},
 	}
 
 	for name, test := range tests {
@@ -132,6 +132,12 @@ func buildService(ch *cmacme.Challenge) (*corev1.Service, error) {
 	if httpDomainCfg.ServiceType != "" {
 		service.Spec.Type = httpDomainCfg.ServiceType
 	}
+	// in IPv6-only clusters the solver must be exposed on an IPv6 cluster IP
+	// for the challenge to be reachable
+	if httpDomainCfg.ServiceIPFamily != "" {
+		family := httpDomainCfg.ServiceIPFamily
+		service.Spec.IPFamily = &family
+	}
 
 	return service, nil
 }
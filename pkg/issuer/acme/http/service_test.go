@@ -259,3 +259,40 @@ func TestGetServicesForChallenge(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildServiceIPFamily(t *testing.T) {
+	ipv6 := v1.IPv6Protocol
+	tests := map[string]struct {
+		family v1.IPFamily
+		want   *v1.IPFamily
+	}{
+		"should not request an IP family if none is configured": {},
+		"should request the IPv6 family if configured": {
+			family: v1.IPv6Protocol,
+			want:   &ipv6,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ch := &cmacme.Challenge{
+				Spec: cmacme.ChallengeSpec{
+					DNSName: "example.com",
+					Solver: cmacme.ACMEChallengeSolver{
+						HTTP01: &cmacme.ACMEChallengeSolverHTTP01{
+							Ingress: &cmacme.ACMEChallengeSolverHTTP01Ingress{
+								ServiceIPFamily: test.family,
+							},
+						},
+					},
+				},
+			}
+			svc, err := buildService(ch)
+			if err != nil {
+				t.Fatalf("unexpected error building service: %v", err)
+			}
+			if !reflect.DeepEqual(svc.Spec.IPFamily, test.want) {
+				t.Errorf("unexpected IP family, exp=%v, got=%v", test.want, svc.Spec.IPFamily)
+			}
+		})
+	}
+}
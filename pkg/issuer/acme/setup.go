@@ -360,14 +360,44 @@ func (a *Acme) getEABKey(ns string) ([]byte, error) {
 	// we include this step to make it easier for end-users to encode secret
 	// keys in case the CA provides a key that is not in standard, padded
 	// base64 encoding.
-	keyData := make([]byte, base64.RawURLEncoding.DecodedLen(len(encodedKeyData)))
-	if _, err := base64.RawURLEncoding.Decode(keyData, encodedKeyData); err != nil {
-		return nil, errors.NewInvalidData("failed to decode external account binding key data: %v", err)
+	keyData, err := decodeEABKey(encodedKeyData)
+	if err != nil {
+		return nil, errors.NewInvalidData("failed to decode external account binding key data in Secret %q at index %q: %v", eab.Name, eab.Key, err)
 	}
 
 	return keyData, nil
 }
 
+// decodeEABKey decodes the MAC key of an External Account Binding.
+// ACME servers provide the key base64url encoded without padding, but it is
+// commonly stored using the standard base64 alphabet or with padding, which
+// results in a different key and the binding signature being rejected.
+// Both alphabets are accepted, with or without padding, as they can be
+// distinguished unambiguously. An error describing the problem is returned
+// if the key data is not valid in either encoding.
+func decodeEABKey(data []byte) ([]byte, error) {
+	encoded := strings.TrimRight(strings.TrimSpace(string(data)), "=")
+
+	urlAlphabet := strings.ContainsAny(encoded, "-_")
+	stdAlphabet := strings.ContainsAny(encoded, "+/")
+	switch {
+	case urlAlphabet && stdAlphabet:
+		return nil, fmt.Errorf("key data contains characters of both the base64url ('-', '_') and the standard base64 ('+', '/') alphabet")
+	case stdAlphabet:
+		key, err := base64.RawStdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("key data is not valid standard base64: %v", err)
+		}
+		return key, nil
+	}
+
+	key, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("key data is not valid base64url: %v", err)
+	}
+	return key, nil
+}
+
 // createAccountPrivateKey will generate a new RSA private key, and create it
 // as a secret resource in the apiserver.
 func (a *Acme) createAccountPrivateKey(sel cmmeta.SecretKeySelector, ns string) (*rsa.PrivateKey, error) {
@@ -0,0 +1,86 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package acme
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestDecodeEABKey(t *testing.T) {
+	// the key is chosen so that its encodings contain characters that differ
+	// between the base64url and the standard base64 alphabets, and require
+	// padding when padded encodings are used.
+	key := []byte{0xfb, 0xff, 0xbf, 0x01, 0x02}
+
+	tests := map[string]struct {
+		data        string
+		expectedErr string
+	}{
+		"base64url without padding": {
+			data: base64.RawURLEncoding.EncodeToString(key),
+		},
+		"base64url with padding": {
+			data: base64.URLEncoding.EncodeToString(key),
+		},
+		"standard base64 without padding": {
+			data: base64.RawStdEncoding.EncodeToString(key),
+		},
+		"standard base64 with padding": {
+			data: base64.StdEncoding.EncodeToString(key),
+		},
+		"trailing newline is ignored": {
+			data: base64.RawURLEncoding.EncodeToString(key) + "\n",
+		},
+		"mixed alphabets": {
+			data:        "-_+/",
+			expectedErr: "key data contains characters of both the base64url ('-', '_') and the standard base64 ('+', '/') alphabet",
+		},
+		"invalid standard base64": {
+			data:        "ab+/c",
+			expectedErr: "key data is not valid standard base64",
+		},
+		"invalid base64url": {
+			data:        "ab-_c",
+			expectedErr: "key data is not valid base64url",
+		},
+		"invalid characters": {
+			data:        "not*base64",
+			expectedErr: "key data is not valid base64url",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			decoded, err := decodeEABKey([]byte(test.data))
+			if test.expectedErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), test.expectedErr) {
+					t.Fatalf("expected error starting with %q, got: %v", test.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !bytes.Equal(decoded, key) {
+				t.Errorf("unexpected decoded key, exp=%x got=%x", key, decoded)
+			}
+		})
+	}
+}
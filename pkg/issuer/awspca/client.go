@@ -0,0 +1,144 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awspca
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/acmpca"
+	"github.com/aws/aws-sdk-go/service/acmpca/acmpcaiface"
+	"github.com/aws/aws-sdk-go/service/sts"
+	corelisters "k8s.io/client-go/listers/core/v1"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	"github.com/jetstack/cert-manager/pkg/util"
+)
+
+// ClientBuilder builds an ACM PCA client for the private certificate
+// authority configured on the given issuer.
+type ClientBuilder func(namespace string, secretsLister corelisters.SecretLister,
+	issuer cmapi.GenericIssuer, ambient bool) (acmpcaiface.ACMPCAAPI, error)
+
+// NewClient builds an ACM PCA client for the private certificate authority
+// configured on the given issuer. Credentials are read from the Secret
+// referenced by the issuer in the given namespace. If no credentials are
+// configured and ambient is true, the default AWS credential chain is used.
+func NewClient(namespace string, secretsLister corelisters.SecretLister,
+	issuer cmapi.GenericIssuer, ambient bool) (acmpcaiface.ACMPCAAPI, error) {
+	cfg := issuer.GetSpec().AWSPCA
+	if cfg == nil {
+		return nil, fmt.Errorf("issuer %q does not have an AWS PCA configuration", issuer.GetObjectMeta().Name)
+	}
+
+	region, err := Region(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	secretAccessKey := ""
+	if cfg.SecretAccessKey.Name != "" {
+		secret, err := secretsLister.Secrets(namespace).Get(cfg.SecretAccessKey.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		secretAccessKeyBytes, ok := secret.Data[cfg.SecretAccessKey.Key]
+		if !ok {
+			return nil, fmt.Errorf("error getting AWS secret access key: key '%s' not found in secret", cfg.SecretAccessKey.Key)
+		}
+		secretAccessKey = string(secretAccessKeyBytes)
+	}
+
+	sess, err := newSession(strings.TrimSpace(cfg.AccessKeyID), strings.TrimSpace(secretAccessKey), region, cfg.Role, ambient)
+	if err != nil {
+		return nil, err
+	}
+
+	return acmpca.New(sess), nil
+}
+
+// Region returns the AWS region of the private certificate authority,
+// defaulting to the region of its ARN.
+func Region(cfg *cmapi.AWSPCAIssuer) (string, error) {
+	if cfg.Region != "" {
+		return cfg.Region, nil
+	}
+
+	caARN, err := arn.Parse(cfg.ARN)
+	if err != nil {
+		return "", fmt.Errorf("invalid certificate authority ARN %q: %v", cfg.ARN, err)
+	}
+	return caARN.Region, nil
+}
+
+func newSession(accessKeyID, secretAccessKey, region, role string, ambient bool) (*session.Session, error) {
+	if accessKeyID == "" && secretAccessKey == "" {
+		if !ambient {
+			return nil, fmt.Errorf("unable to construct AWS PCA client: empty credentials; perhaps you meant to enable ambient credentials?")
+		}
+	} else if accessKeyID == "" || secretAccessKey == "" {
+		// It's always an error to set one of those but not the other
+		return nil, fmt.Errorf("unable to construct AWS PCA client: only one of access and secret key was provided")
+	}
+
+	sessionOpts := session.Options{
+		Config: *aws.NewConfig().WithRegion(region),
+	}
+
+	// Leaving credentials unset results in the default credential chain being
+	// used, which is a reasonable default for getting ambient credentials.
+	if accessKeyID != "" {
+		sessionOpts.Config.Credentials = credentials.NewStaticCredentials(accessKeyID, secretAccessKey, "")
+		// also disable 'ambient' configuration sources
+		sessionOpts.SharedConfigState = session.SharedConfigDisable
+	}
+
+	sess, err := session.NewSessionWithOptions(sessionOpts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create aws session: %s", err)
+	}
+
+	if role != "" {
+		result, err := sts.New(sess).AssumeRole(&sts.AssumeRoleInput{
+			RoleArn:         aws.String(role),
+			RoleSessionName: aws.String("cert-manager"),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to assume role: %s", err)
+		}
+
+		sessionOpts.Config.Credentials = credentials.NewStaticCredentialsFromCreds(credentials.Value{
+			AccessKeyID:     *result.Credentials.AccessKeyId,
+			SecretAccessKey: *result.Credentials.SecretAccessKey,
+			SessionToken:    *result.Credentials.SessionToken,
+		})
+
+		sess, err = session.NewSessionWithOptions(sessionOpts)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create aws session: %s", err)
+		}
+	}
+
+	sess.Handlers.Build.PushBack(request.WithAppendUserAgent(util.CertManagerUserAgent))
+	return sess, nil
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/acmpca"
+	"github.com/aws/aws-sdk-go/service/acmpca/acmpcaiface"
+)
+
+// Client is a mock ACM PCA client. Calls to methods that do not have a
+// corresponding function set will panic.
+type Client struct {
+	acmpcaiface.ACMPCAAPI
+
+	DescribeCertificateAuthorityFn func(*acmpca.DescribeCertificateAuthorityInput) (*acmpca.DescribeCertificateAuthorityOutput, error)
+	IssueCertificateFn             func(*acmpca.IssueCertificateInput) (*acmpca.IssueCertificateOutput, error)
+	GetCertificateFn               func(*acmpca.GetCertificateInput) (*acmpca.GetCertificateOutput, error)
+}
+
+func (c *Client) DescribeCertificateAuthorityWithContext(_ aws.Context, input *acmpca.DescribeCertificateAuthorityInput, _ ...request.Option) (*acmpca.DescribeCertificateAuthorityOutput, error) {
+	return c.DescribeCertificateAuthorityFn(input)
+}
+
+func (c *Client) IssueCertificateWithContext(_ aws.Context, input *acmpca.IssueCertificateInput, _ ...request.Option) (*acmpca.IssueCertificateOutput, error) {
+	return c.IssueCertificateFn(input)
+}
+
+func (c *Client) GetCertificateWithContext(_ aws.Context, input *acmpca.GetCertificateInput, _ ...request.Option) (*acmpca.GetCertificateOutput, error) {
+	return c.GetCertificateFn(input)
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awspca
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/acmpca"
+	corev1 "k8s.io/api/core/v1"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+)
+
+func (a *AWSPCA) Setup(ctx context.Context) (err error) {
+	defer func() {
+		if err != nil {
+			errorMessage := "Failed to setup AWS PCA issuer"
+			a.log.Error(err, errorMessage)
+			apiutil.SetIssuerCondition(a.issuer, cmapi.IssuerConditionReady, cmmeta.ConditionFalse, "ErrorSetup", fmt.Sprintf("%s: %v", errorMessage, err))
+			err = fmt.Errorf("%s: %v", errorMessage, err)
+		}
+	}()
+
+	client, err := a.clientBuilder(a.resourceNamespace, a.secretsLister, a.issuer, a.IssuerOptions.CanUseAmbientCredentials(a.issuer))
+	if err != nil {
+		return fmt.Errorf("error building client: %v", err)
+	}
+
+	caARN := a.issuer.GetSpec().AWSPCA.ARN
+	out, err := client.DescribeCertificateAuthorityWithContext(ctx, &acmpca.DescribeCertificateAuthorityInput{
+		CertificateAuthorityArn: aws.String(caARN),
+	})
+	if err != nil {
+		return fmt.Errorf("error describing certificate authority %q: %v", caARN, err)
+	}
+
+	if out.CertificateAuthority == nil {
+		return fmt.Errorf("certificate authority %q not found", caARN)
+	}
+	if status := aws.StringValue(out.CertificateAuthority.Status); status != acmpca.CertificateAuthorityStatusActive {
+		return fmt.Errorf("certificate authority %q is not active, its status is %q", caARN, status)
+	}
+
+	// If it does not already have a 'ready' condition, we'll also log an event
+	// to make it really clear to users that this Issuer is ready.
+	if !apiutil.IssuerHasCondition(a.issuer, cmapi.IssuerCondition{
+		Type:   cmapi.IssuerConditionReady,
+		Status: cmmeta.ConditionTrue,
+	}) {
+		a.Recorder.Eventf(a.issuer, corev1.EventTypeNormal, "Ready", "Verified issuer with AWS PCA certificate authority")
+	}
+	a.log.V(logf.DebugLevel).Info("AWS PCA issuer started")
+	apiutil.SetIssuerCondition(a.issuer, cmapi.IssuerConditionReady, cmmeta.ConditionTrue, "AWS PCA issuer started", "AWS PCA issuer started")
+
+	return nil
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awspca
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/acmpca"
+	"github.com/aws/aws-sdk-go/service/acmpca/acmpcaiface"
+	corelisters "k8s.io/client-go/listers/core/v1"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	"github.com/jetstack/cert-manager/pkg/controller"
+	controllertest "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/pkg/issuer/awspca/fake"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/util"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+const testCAARN = "arn:aws:acm-pca:us-east-1:123456789012:certificate-authority/12345678-1234-1234-1234-123456789012"
+
+func TestSetup(t *testing.T) {
+	baseIssuer := gen.Issuer("test-issuer",
+		gen.SetIssuerAWSPCA(cmapi.AWSPCAIssuer{
+			ARN: testCAARN,
+		}),
+	)
+
+	clientWithStatus := func(status string) ClientBuilder {
+		return func(string, corelisters.SecretLister, cmapi.GenericIssuer, bool) (acmpcaiface.ACMPCAAPI, error) {
+			return &fake.Client{
+				DescribeCertificateAuthorityFn: func(input *acmpca.DescribeCertificateAuthorityInput) (*acmpca.DescribeCertificateAuthorityOutput, error) {
+					if aws.StringValue(input.CertificateAuthorityArn) != testCAARN {
+						return nil, errors.New("unexpected certificate authority ARN")
+					}
+					return &acmpca.DescribeCertificateAuthorityOutput{
+						CertificateAuthority: &acmpca.CertificateAuthority{
+							Arn:    aws.String(testCAARN),
+							Status: aws.String(status),
+						},
+					}, nil
+				},
+			}, nil
+		}
+	}
+
+	tests := map[string]testSetupT{
+		"if client builder fails then should error": {
+			clientBuilder: func(string, corelisters.SecretLister, cmapi.GenericIssuer, bool) (acmpcaiface.ACMPCAAPI, error) {
+				return nil, errors.New("this is an error")
+			},
+			expectedErr: true,
+			iss:         baseIssuer.DeepCopy(),
+			expectedCondition: &cmapi.IssuerCondition{
+				Reason:  "ErrorSetup",
+				Message: "Failed to setup AWS PCA issuer: error building client: this is an error",
+				Status:  "False",
+			},
+		},
+
+		"if describing the certificate authority fails then should error": {
+			clientBuilder: func(string, corelisters.SecretLister, cmapi.GenericIssuer, bool) (acmpcaiface.ACMPCAAPI, error) {
+				return &fake.Client{
+					DescribeCertificateAuthorityFn: func(*acmpca.DescribeCertificateAuthorityInput) (*acmpca.DescribeCertificateAuthorityOutput, error) {
+						return nil, errors.New("this is a describe error")
+					},
+				}, nil
+			},
+			expectedErr: true,
+			iss:         baseIssuer.DeepCopy(),
+			expectedCondition: &cmapi.IssuerCondition{
+				Reason:  "ErrorSetup",
+				Message: `Failed to setup AWS PCA issuer: error describing certificate authority "` + testCAARN + `": this is a describe error`,
+				Status:  "False",
+			},
+		},
+
+		"if the certificate authority is not active then should error": {
+			clientBuilder: clientWithStatus(acmpca.CertificateAuthorityStatusDisabled),
+			expectedErr:   true,
+			iss:           baseIssuer.DeepCopy(),
+			expectedCondition: &cmapi.IssuerCondition{
+				Reason:  "ErrorSetup",
+				Message: `Failed to setup AWS PCA issuer: certificate authority "` + testCAARN + `" is not active, its status is "DISABLED"`,
+				Status:  "False",
+			},
+		},
+
+		"if the certificate authority is active then should set condition": {
+			clientBuilder: clientWithStatus(acmpca.CertificateAuthorityStatusActive),
+			iss:           baseIssuer.DeepCopy(),
+			expectedErr:   false,
+			expectedCondition: &cmapi.IssuerCondition{
+				Message: "AWS PCA issuer started",
+				Reason:  "AWS PCA issuer started",
+				Status:  "True",
+			},
+			expectedEvents: []string{
+				"Normal Ready Verified issuer with AWS PCA certificate authority",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			test.runTest(t)
+		})
+	}
+}
+
+type testSetupT struct {
+	clientBuilder ClientBuilder
+	iss           cmapi.GenericIssuer
+
+	expectedErr       bool
+	expectedEvents    []string
+	expectedCondition *cmapi.IssuerCondition
+}
+
+func (s *testSetupT) runTest(t *testing.T) {
+	rec := &controllertest.FakeRecorder{}
+
+	a := &AWSPCA{
+		resourceNamespace: "test-namespace",
+		Context: &controller.Context{
+			Recorder: rec,
+		},
+		issuer:        s.iss,
+		clientBuilder: s.clientBuilder,
+		log:           logf.Log.WithName("awspca"),
+	}
+
+	err := a.Setup(context.TODO())
+	if err != nil && !s.expectedErr {
+		t.Errorf("expected to not get an error, but got: %v", err)
+	}
+	if err == nil && s.expectedErr {
+		t.Errorf("expected to get an error but did not get one")
+	}
+
+	if !util.EqualSorted(s.expectedEvents, rec.Events) {
+		t.Errorf("got unexpected events, exp='%s' got='%s'",
+			s.expectedEvents, rec.Events)
+	}
+
+	conditions := s.iss.GetStatus().Conditions
+	if s.expectedCondition == nil &&
+		len(conditions) > 0 {
+		t.Errorf("expected no conditions but got=%+v",
+			conditions)
+	}
+
+	if s.expectedCondition != nil {
+		if len(conditions) != 1 {
+			t.Error("expected conditions but got none")
+			t.FailNow()
+		}
+
+		c := conditions[0]
+
+		if s.expectedCondition.Message != c.Message {
+			t.Errorf("unexpected condition message, exp=%s got=%s",
+				s.expectedCondition.Message, c.Message)
+		}
+		if s.expectedCondition.Reason != c.Reason {
+			t.Errorf("unexpected condition reason, exp=%s got=%s",
+				s.expectedCondition.Reason, c.Reason)
+		}
+		if s.expectedCondition.Status != c.Status {
+			t.Errorf("unexpected condition status, exp=%s got=%s",
+				s.expectedCondition.Status, c.Status)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Venafi/vcert/v4/pkg/endpoint"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+// tppTokenTimeout is the timeout for requests to the TPP OAuth token endpoint.
+const tppTokenTimeout = time.Second * 30
+
+// tppTokenRefresh holds the details needed to obtain a new TPP access token
+// using an OAuth refresh token.
+type tppTokenRefresh struct {
+	// secretName is the name of the credentials Secret that the refreshed
+	// tokens are written back to.
+	secretName   string
+	clientID     string
+	refreshToken string
+
+	// tokenURL is the TPP OAuth endpoint used to refresh access tokens.
+	tokenURL   string
+	httpClient *http.Client
+}
+
+type tppRefreshTokenRequest struct {
+	ClientID     string `json:"client_id"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+type tppRefreshTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// tppTokenRefreshForIssuer returns the details needed to refresh the TPP
+// access token of the given issuer, or nil if the issuer is not a TPP issuer
+// or its credentials Secret does not contain a refresh token.
+func tppTokenRefreshForIssuer(iss cmapi.GenericIssuer, secretsLister corelisters.SecretLister, namespace string) (*tppTokenRefresh, error) {
+	tpp := iss.GetSpec().Venafi.TPP
+	if tpp == nil {
+		return nil, nil
+	}
+
+	tppSecret, err := secretsLister.Secrets(namespace).Get(tpp.CredentialsRef.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken := string(tppSecret.Data[tppRefreshTokenKey])
+	if refreshToken == "" {
+		return nil, nil
+	}
+
+	clientID := string(tppSecret.Data[tppClientIDKey])
+	if clientID == "" {
+		clientID = defaultTPPClientID
+	}
+
+	httpClient, err := tppHTTPClient(tpp.CABundle)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tppTokenRefresh{
+		secretName:   tpp.CredentialsRef.Name,
+		clientID:     clientID,
+		refreshToken: refreshToken,
+		tokenURL:     tppTokenURL(tpp.URL),
+		httpClient:   httpClient,
+	}, nil
+}
+
+// tppHTTPClient returns an HTTP client that trusts the given CA bundle, or
+// the system roots if the bundle is empty.
+func tppHTTPClient(caBundle []byte) (*http.Client, error) {
+	if len(caBundle) == 0 {
+		return &http.Client{Timeout: tppTokenTimeout}, nil
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caBundle) {
+		return nil, fmt.Errorf("error parsing TPP caBundle")
+	}
+
+	return &http.Client{
+		Timeout: tppTokenTimeout,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{RootCAs: pool},
+		},
+	}, nil
+}
+
+// tppTokenURL returns the OAuth token endpoint for the given vedsdk URL, for
+// example "https://tpp.example.com/vedsdk" becomes
+// "https://tpp.example.com/vedauth/authorize/token".
+func tppTokenURL(baseURL string) string {
+	base := strings.TrimSuffix(strings.TrimSuffix(baseURL, "/"), "/vedsdk")
+	return base + "/vedauth/authorize/token"
+}
+
+// isUnauthorized returns true if the given error from vcert indicates that
+// TPP rejected the access token, usually because it has expired.
+func isUnauthorized(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "401")
+}
+
+// withTokenRefresh calls fn and, if TPP rejects the access token and a
+// refresh token is available, refreshes the access token and calls fn once
+// more.
+func (v *Venafi) withTokenRefresh(fn func() error) error {
+	err := fn()
+	if v.tppRefresh == nil || !isUnauthorized(err) {
+		return err
+	}
+
+	if err := v.refreshAccessToken(); err != nil {
+		return fmt.Errorf("error refreshing TPP access token: %v", err)
+	}
+
+	return fn()
+}
+
+// refreshAccessToken obtains a new access token from TPP, stores the new
+// tokens in the credentials Secret and rebuilds the vcert client to use them.
+func (v *Venafi) refreshAccessToken() error {
+	tokens, err := v.tppRefresh.refresh()
+	if err != nil {
+		return err
+	}
+
+	secret, err := v.secretsClient.Secrets(v.namespace).Get(context.TODO(), v.tppRefresh.secretName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if secret.Data == nil {
+		secret.Data = make(map[string][]byte)
+	}
+	secret.Data[tppAccessTokenKey] = []byte(tokens.AccessToken)
+	if tokens.RefreshToken != "" {
+		secret.Data[tppRefreshTokenKey] = []byte(tokens.RefreshToken)
+	}
+	if _, err := v.secretsClient.Secrets(v.namespace).Update(context.TODO(), secret, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("error storing refreshed TPP access token: %v", err)
+	}
+
+	if tokens.RefreshToken != "" {
+		v.tppRefresh.refreshToken = tokens.RefreshToken
+	}
+	v.config.Credentials = &endpoint.Authentication{
+		AccessToken: tokens.AccessToken,
+	}
+
+	vcertClient, err := v.connectorBuilder(v.config)
+	if err != nil {
+		return err
+	}
+	v.vcertClient = vcertClient
+
+	return nil
+}
+
+// refresh exchanges the refresh token for a new access token.
+func (r *tppTokenRefresh) refresh() (*tppRefreshTokenResponse, error) {
+	body, err := json.Marshal(tppRefreshTokenRequest{
+		ClientID:     r.clientID,
+		RefreshToken: r.refreshToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.httpClient.Post(r.tokenURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code from TPP token endpoint: %d", resp.StatusCode)
+	}
+
+	var tokens tppRefreshTokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tokens); err != nil {
+		return nil, fmt.Errorf("error decoding TPP token response: %v", err)
+	}
+	if tokens.AccessToken == "" {
+		return nil, fmt.Errorf("TPP token response did not contain an access token")
+	}
+
+	return &tokens, nil
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	vcert "github.com/Venafi/vcert/v4"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	internalfake "github.com/jetstack/cert-manager/pkg/issuer/venafi/client/fake"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+// newFakeTPPTokenServer returns a TPP server that exchanges the refresh token
+// "old-refresh-token" for the access token "new-access-token".
+func newFakeTPPTokenServer() *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/vedauth/authorize/token" || r.Method != http.MethodPost {
+			http.NotFound(w, r)
+			return
+		}
+
+		var req tppRefreshTokenRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if req.RefreshToken != "old-refresh-token" || req.ClientID != defaultTPPClientID {
+			http.Error(w, "invalid grant", http.StatusBadRequest)
+			return
+		}
+
+		json.NewEncoder(w).Encode(tppRefreshTokenResponse{
+			AccessToken:  "new-access-token",
+			RefreshToken: "new-refresh-token",
+		})
+	}))
+}
+
+// fakeTPPConnector returns a connector that rejects all requests with a 401
+// unless it was built with the access token "new-access-token".
+func fakeTPPConnector(cfg *vcert.Config) (connector, error) {
+	return internalfake.Connector{
+		PingFunc: func() error {
+			if cfg.Credentials.AccessToken != "new-access-token" {
+				return errors.New("unexpected status code on TPP Ping. Status: 401 Unauthorized")
+			}
+			return nil
+		},
+	}.Default(), nil
+}
+
+func TestTokenRefreshOnUnauthorized(t *testing.T) {
+	server := newFakeTPPTokenServer()
+	defer server.Close()
+
+	caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	issuer := gen.Issuer("tpp-issuer",
+		gen.SetIssuerVenafi(cmapi.VenafiIssuer{
+			Zone: "test-zone",
+			TPP: &cmapi.VenafiTPP{
+				URL:            server.URL + "/vedsdk",
+				CredentialsRef: cmmeta.LocalObjectReference{Name: "tpp-credentials"},
+				CABundle:       caBundle,
+			},
+		}),
+	)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "tpp-credentials",
+			Namespace: "test-namespace",
+		},
+		Data: map[string][]byte{
+			tppAccessTokenKey:  []byte("expired-access-token"),
+			tppRefreshTokenKey: []byte("old-refresh-token"),
+		},
+	}
+	kubeClient := kubefake.NewSimpleClientset(secret)
+	secretsLister := generateSecretLister(secret, nil)
+
+	cfg, err := configForIssuer(issuer, secretsLister, "test-namespace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tppRefresh, err := tppTokenRefreshForIssuer(issuer, secretsLister, "test-namespace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vcertClient, err := fakeTPPConnector(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v := &Venafi{
+		namespace:        "test-namespace",
+		secretsLister:    secretsLister,
+		secretsClient:    kubeClient.CoreV1(),
+		vcertClient:      vcertClient,
+		config:           cfg,
+		tppRefresh:       tppRefresh,
+		connectorBuilder: fakeTPPConnector,
+	}
+
+	if err := v.Ping(); err != nil {
+		t.Fatalf("expected ping to succeed after refreshing the access token, got: %v", err)
+	}
+
+	updated, err := kubeClient.CoreV1().Secrets("test-namespace").Get(context.TODO(), "tpp-credentials", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token := string(updated.Data[tppAccessTokenKey]); token != "new-access-token" {
+		t.Errorf("expected the refreshed access token to be stored in the Secret, got %q", token)
+	}
+	if token := string(updated.Data[tppRefreshTokenKey]); token != "new-refresh-token" {
+		t.Errorf("expected the new refresh token to be stored in the Secret, got %q", token)
+	}
+}
+
+func TestTokenRefreshNotConfigured(t *testing.T) {
+	refreshes := 0
+	v := &Venafi{
+		vcertClient: internalfake.Connector{
+			PingFunc: func() error {
+				return errors.New("unexpected status code on TPP Ping. Status: 401 Unauthorized")
+			},
+		}.Default(),
+		connectorBuilder: func(*vcert.Config) (connector, error) {
+			refreshes++
+			return nil, nil
+		},
+	}
+
+	if err := v.Ping(); err == nil {
+		t.Errorf("expected an error when no refresh token is configured")
+	}
+	if refreshes != 0 {
+		t.Errorf("expected the client to not be rebuilt when no refresh token is configured")
+	}
+}
+
+func TestTPPTokenURL(t *testing.T) {
+	tests := map[string]string{
+		"https://tpp.example.com/vedsdk":  "https://tpp.example.com/vedauth/authorize/token",
+		"https://tpp.example.com/vedsdk/": "https://tpp.example.com/vedauth/authorize/token",
+		"https://tpp.example.com":         "https://tpp.example.com/vedauth/authorize/token",
+	}
+	for in, exp := range tests {
+		if got := tppTokenURL(in); got != exp {
+			t.Errorf("%s: expected %q, got %q", in, exp, got)
+		}
+	}
+}
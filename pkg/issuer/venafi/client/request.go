@@ -50,7 +50,11 @@ func (v *Venafi) RequestCertificate(csrPEM []byte, duration time.Duration, custo
 		return "", err
 	}
 	// Send the certificate signing request to Venafi
-	requestID, err := v.vcertClient.RequestCertificate(vreq)
+	var requestID string
+	err = v.withTokenRefresh(func() (err error) {
+		requestID, err = v.vcertClient.RequestCertificate(vreq)
+		return err
+	})
 	return requestID, err
 }
 
@@ -64,7 +68,11 @@ func (v *Venafi) RetrieveCertificate(pickupID string, csrPEM []byte, duration ti
 	vreq.Timeout = time.Second * 10
 
 	// Retrieve the certificate from request
-	pemCollection, err := v.vcertClient.RetrieveCertificate(vreq)
+	var pemCollection *certificate.PEMCollection
+	err = v.withTokenRefresh(func() (err error) {
+		pemCollection, err = v.vcertClient.RetrieveCertificate(vreq)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -80,7 +88,7 @@ func (v *Venafi) buildVReq(csrPEM []byte, duration time.Duration, customFields [
 	// Retrieve a copy of the Venafi zone.
 	// This contains default values and policy control info that we can apply
 	// and check against locally.
-	zoneCfg, err := v.vcertClient.ReadZoneConfiguration()
+	zoneCfg, err := v.ReadZoneConfiguration()
 	if err != nil {
 		return nil, err
 	}
@@ -97,8 +105,10 @@ func (v *Venafi) buildVReq(csrPEM []byte, duration time.Duration, customFields [
 	// Create a vcert Request structure
 	vreq := newVRequest(tmpl)
 
-	// Convert over custom fields from our struct type to venafi's
-	vfields, err := convertCustomFieldsToVcert(customFields)
+	// Convert over the custom fields of the issuer and of the request from
+	// our struct type to venafi's
+	fields := append(append([]api.CustomField{}, v.customFields...), customFields...)
+	vfields, err := convertCustomFieldsToVcert(fields)
 	if err != nil {
 		return nil, err
 	}
@@ -23,6 +23,7 @@ import (
 	"crypto/x509/pkix"
 	"encoding/pem"
 	"errors"
+	"reflect"
 	"testing"
 	"time"
 
@@ -237,6 +238,43 @@ func TestVenafi_RequestCertificate(t *testing.T) {
 	}
 }
 
+func TestVenafi_RequestCertificateIssuerCustomFields(t *testing.T) {
+	privateKey, err := pki.GenerateRSAPrivateKey(2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sent []certificate.CustomField
+	v := &Venafi{
+		vcertClient: internalfake.Connector{
+			RequestCertificateFunc: func(r *certificate.Request) (string, error) {
+				sent = r.CustomFields
+				return "pickup-id", nil
+			},
+		}.Default(),
+		customFields: []api.CustomField{{Name: "cost-center", Value: "1234"}},
+	}
+
+	csrPEM := generateCSR(t, privateKey, "common-name", []string{"foo.example.com"})
+	if _, err := v.RequestCertificate(csrPEM, time.Minute, []api.CustomField{{Name: "team", Value: "a"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got []certificate.CustomField
+	for _, f := range sent {
+		if f.Type == certificate.CustomFieldPlain {
+			got = append(got, f)
+		}
+	}
+	exp := []certificate.CustomField{
+		{Type: certificate.CustomFieldPlain, Name: "cost-center", Value: "1234"},
+		{Type: certificate.CustomFieldPlain, Name: "team", Value: "a"},
+	}
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("expected custom fields %+v to be sent, got %+v", exp, sent)
+	}
+}
+
 func TestVenafi_RetrieveCertificate(t *testing.T) {
 	privateKey, err := pki.GenerateRSAPrivateKey(2048)
 	if err != nil {
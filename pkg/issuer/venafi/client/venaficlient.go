@@ -23,6 +23,7 @@ import (
 	vcert "github.com/Venafi/vcert/v4"
 	"github.com/Venafi/vcert/v4/pkg/certificate"
 	"github.com/Venafi/vcert/v4/pkg/endpoint"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	corelisters "k8s.io/client-go/listers/core/v1"
 
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
@@ -34,11 +35,20 @@ const (
 	tppPasswordKey    = "password"
 	tppAccessTokenKey = "access-token"
 
+	// tppRefreshTokenKey and tppClientIDKey hold the OAuth refresh token and
+	// client ID used to obtain a new access token once it has expired.
+	tppRefreshTokenKey = "refresh-token"
+	tppClientIDKey     = "client-id"
+
+	// defaultTPPClientID is the OAuth client ID used to refresh access tokens
+	// if the credentials Secret does not specify one.
+	defaultTPPClientID = "cert-manager.io"
+
 	defaultAPIKeyKey = "api-key"
 )
 
 type VenafiClientBuilder func(namespace string, secretsLister corelisters.SecretLister,
-	issuer cmapi.GenericIssuer) (Interface, error)
+	secretsClient corev1client.SecretsGetter, issuer cmapi.GenericIssuer) (Interface, error)
 
 // Interface implements a Venafi client
 type Interface interface {
@@ -56,8 +66,22 @@ type Venafi struct {
 	// For ClusterIssuers, this will be the cluster resource namespace.
 	namespace     string
 	secretsLister corelisters.SecretLister
+	secretsClient corev1client.SecretsGetter
 
 	vcertClient connector
+
+	// config is the configuration the vcertClient was built from. It is
+	// used to rebuild the client after refreshing an expired access token.
+	config *vcert.Config
+	// tppRefresh is set if the TPP credentials Secret contains a refresh
+	// token that can be used to obtain a new access token.
+	tppRefresh *tppTokenRefresh
+	// connectorBuilder builds a vcert connector from the given config.
+	connectorBuilder func(*vcert.Config) (connector, error)
+	// customFields are the custom fields configured on the issuer. They are
+	// sent with every certificate request, before any custom fields of the
+	// request itself.
+	customFields []api.CustomField
 }
 
 // connector exposes a subset of the vcert Connector interface to make stubbing
@@ -70,24 +94,52 @@ type connector interface {
 	RenewCertificate(req *certificate.RenewalRequest) (requestID string, err error)
 }
 
-func New(namespace string, secretsLister corelisters.SecretLister, issuer cmapi.GenericIssuer) (Interface, error) {
+func New(namespace string, secretsLister corelisters.SecretLister, secretsClient corev1client.SecretsGetter, issuer cmapi.GenericIssuer) (Interface, error) {
 	cfg, err := configForIssuer(issuer, secretsLister, namespace)
 	if err != nil {
 		return nil, err
 	}
 
-	vcertClient, err := vcert.NewClient(cfg)
+	tppRefresh, err := tppTokenRefreshForIssuer(issuer, secretsLister, namespace)
 	if err != nil {
-		return nil, fmt.Errorf("error creating Venafi client: %s", err.Error())
+		return nil, err
+	}
+
+	vcertClient, err := newConnector(cfg)
+	if err != nil {
+		return nil, err
 	}
 
 	return &Venafi{
-		namespace:     namespace,
-		secretsLister: secretsLister,
-		vcertClient:   vcertClient,
+		namespace:        namespace,
+		secretsLister:    secretsLister,
+		secretsClient:    secretsClient,
+		vcertClient:      vcertClient,
+		config:           cfg,
+		tppRefresh:       tppRefresh,
+		connectorBuilder: newConnector,
+		customFields:     customFieldsForIssuer(issuer),
 	}, nil
 }
 
+// customFieldsForIssuer returns the custom fields configured on a Venafi
+// issuer.
+func customFieldsForIssuer(iss cmapi.GenericIssuer) []api.CustomField {
+	var fields []api.CustomField
+	for _, f := range iss.GetSpec().Venafi.CustomFields {
+		fields = append(fields, api.CustomField{Name: f.Name, Value: f.Value})
+	}
+	return fields
+}
+
+func newConnector(cfg *vcert.Config) (connector, error) {
+	vcertClient, err := vcert.NewClient(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Venafi client: %s", err.Error())
+	}
+	return vcertClient, nil
+}
+
 // configForIssuer will convert a cert-manager Venafi issuer into a vcert.Config
 // that can be used to instantiate an API client.
 func configForIssuer(iss cmapi.GenericIssuer, secretsLister corelisters.SecretLister, namespace string) (*vcert.Config, error) {
@@ -103,8 +155,18 @@ func configForIssuer(iss cmapi.GenericIssuer, secretsLister corelisters.SecretLi
 		username := string(tppSecret.Data[tppUsernameKey])
 		password := string(tppSecret.Data[tppPasswordKey])
 		accessToken := string(tppSecret.Data[tppAccessTokenKey])
+		refreshToken := string(tppSecret.Data[tppRefreshTokenKey])
 		caBundle := string(tpp.CABundle)
 
+		switch {
+		case refreshToken != "" && accessToken == "":
+			return nil, fmt.Errorf("TPP credentials Secret %q contains a %q but no %q",
+				tpp.CredentialsRef.Name, tppRefreshTokenKey, tppAccessTokenKey)
+		case accessToken == "" && (username == "" || password == ""):
+			return nil, fmt.Errorf("TPP credentials Secret %q must contain either an %q or both a %q and %q",
+				tpp.CredentialsRef.Name, tppAccessTokenKey, tppUsernameKey, tppPasswordKey)
+		}
+
 		return &vcert.Config{
 			ConnectorType: endpoint.ConnectorTypeTPP,
 			BaseUrl:       tpp.URL,
@@ -148,11 +210,18 @@ func configForIssuer(iss cmapi.GenericIssuer, secretsLister corelisters.SecretLi
 }
 
 func (v *Venafi) Ping() error {
-	return v.vcertClient.Ping()
+	return v.withTokenRefresh(func() error {
+		return v.vcertClient.Ping()
+	})
 }
 
 func (v *Venafi) ReadZoneConfiguration() (*endpoint.ZoneConfiguration, error) {
-	return v.vcertClient.ReadZoneConfiguration()
+	var zoneCfg *endpoint.ZoneConfiguration
+	err := v.withTokenRefresh(func() (err error) {
+		zoneCfg, err = v.vcertClient.ReadZoneConfiguration()
+		return err
+	})
+	return zoneCfg, err
 }
 
 func (v *Venafi) SetClient(client endpoint.Connector) {
@@ -18,6 +18,7 @@ package client
 
 import (
 	"errors"
+	"reflect"
 	"testing"
 
 	vcert "github.com/Venafi/vcert/v4"
@@ -27,6 +28,7 @@ import (
 
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/jetstack/cert-manager/pkg/issuer/venafi/client/api"
 	"github.com/jetstack/cert-manager/test/unit/gen"
 	testlisters "github.com/jetstack/cert-manager/test/unit/listers"
 )
@@ -143,6 +145,42 @@ func TestConfigForIssuerT(t *testing.T) {
 			},
 			expectedErr: false,
 		},
+		"if TPP and secret returns access-token and refresh-token, should return config with the access token": {
+			iss: tppIssuer,
+			secretsLister: generateSecretLister(&corev1.Secret{
+				Data: map[string][]byte{
+					tppAccessTokenKey:  []byte(accessToken),
+					tppRefreshTokenKey: []byte("test-refresh-token"),
+				},
+			}, nil),
+			CheckFn: func(t *testing.T, cnf *vcert.Config) {
+				if actualAccessToken := cnf.Credentials.AccessToken; actualAccessToken != accessToken {
+					t.Errorf("got unexpected accessToken: %q", actualAccessToken)
+				}
+				checkZone(t, zone, cnf)
+			},
+			expectedErr: false,
+		},
+		"if TPP and secret returns refresh-token without access-token, should error": {
+			iss: tppIssuer,
+			secretsLister: generateSecretLister(&corev1.Secret{
+				Data: map[string][]byte{
+					tppRefreshTokenKey: []byte("test-refresh-token"),
+				},
+			}, nil),
+			CheckFn:     checkNoConfigReturned,
+			expectedErr: true,
+		},
+		"if TPP and secret returns only a username, should error": {
+			iss: tppIssuer,
+			secretsLister: generateSecretLister(&corev1.Secret{
+				Data: map[string][]byte{
+					tppUsernameKey: []byte(username),
+				},
+			}, nil),
+			CheckFn:     checkNoConfigReturned,
+			expectedErr: true,
+		},
 		"if Cloud but getting secret fails, should error": {
 			iss:           cloudIssuer,
 			secretsLister: generateSecretLister(nil, errors.New("this is a network error")),
@@ -235,3 +273,29 @@ func (c *testConfigForIssuerT) runTest(t *testing.T) {
 		c.CheckFn(t, resp)
 	}
 }
+
+func TestCustomFieldsForIssuer(t *testing.T) {
+	iss := gen.Issuer("venafi-issuer",
+		gen.SetIssuerVenafi(cmapi.VenafiIssuer{
+			Zone: "test-zone",
+			TPP:  &cmapi.VenafiTPP{},
+			CustomFields: []cmapi.VenafiCustomField{
+				{Name: "cost-center", Value: "1234"},
+				{Name: "owner", Value: "team-a"},
+			},
+		}),
+	)
+
+	exp := []api.CustomField{
+		{Name: "cost-center", Value: "1234"},
+		{Name: "owner", Value: "team-a"},
+	}
+	if got := customFieldsForIssuer(iss); !reflect.DeepEqual(got, exp) {
+		t.Errorf("expected custom fields %+v, got %+v", exp, got)
+	}
+
+	noFields := gen.Issuer("venafi-issuer", gen.SetIssuerVenafi(cmapi.VenafiIssuer{Zone: "test-zone"}))
+	if got := customFieldsForIssuer(noFields); got != nil {
+		t.Errorf("expected no custom fields, got %+v", got)
+	}
+}
@@ -37,7 +37,7 @@ func (v *Venafi) Setup(ctx context.Context) (err error) {
 		}
 	}()
 
-	client, err := v.clientBuilder(v.resourceNamespace, v.secretsLister, v.issuer)
+	client, err := v.clientBuilder(v.resourceNamespace, v.secretsLister, v.secretsClient, v.issuer)
 	if err != nil {
 		return fmt.Errorf("error building client: %v", err)
 	}
@@ -21,12 +21,14 @@ limitations under the License.
 // acme_client_request_count{"scheme", "host", "path", "method", "status"}
 // acme_client_request_duration_seconds{"scheme", "host", "path", "method", "status"}
 // controller_sync_call_count{"controller"}
+// controller_workqueue_depth{"controller"}
 package metrics
 
 import (
 	"context"
 	"net"
 	"net/http"
+	"sync"
 	"time"
 
 	logf "github.com/jetstack/cert-manager/pkg/logs"
@@ -60,6 +62,10 @@ type Metrics struct {
 	acmeClientRequestDurationSeconds *prometheus.SummaryVec
 	acmeClientRequestCount           *prometheus.CounterVec
 	controllerSyncCallCount          *prometheus.CounterVec
+	controllerWorkqueueDepth         *prometheus.GaugeVec
+
+	workqueueDepthsLock sync.Mutex
+	workqueueDepths     map[string]*workqueueDepth
 }
 
 var readyConditionStatuses = [...]cmmeta.ConditionStatus{cmmeta.ConditionTrue, cmmeta.ConditionFalse, cmmeta.ConditionUnknown}
@@ -117,6 +123,15 @@ func New(log logr.Logger) *Metrics {
 			},
 			[]string{"controller"},
 		)
+
+		controllerWorkqueueDepth = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "controller_workqueue_depth",
+				Help:      "The number of items waiting to be processed in a controller's workqueue.",
+			},
+			[]string{"controller"},
+		)
 	)
 
 	// Create server and register Prometheus metrics handler
@@ -129,21 +144,29 @@ func New(log logr.Logger) *Metrics {
 		acmeClientRequestCount:           acmeClientRequestCount,
 		acmeClientRequestDurationSeconds: acmeClientRequestDurationSeconds,
 		controllerSyncCallCount:          controllerSyncCallCount,
+		controllerWorkqueueDepth:         controllerWorkqueueDepth,
+
+		workqueueDepths: make(map[string]*workqueueDepth),
 	}
 
 	return m
 }
 
-// Start will register the Prometheu metrics, and start the Prometheus server
-func (m *Metrics) Start(listenAddress string, enablePprof bool) (*http.Server, error) {
+// Start will register the Prometheu metrics, and start the Prometheus server.
+// If workqueueBacklogThreshold is greater than zero, the /readyz endpoint
+// reports not ready while any controller workqueue holds more items than the
+// threshold.
+func (m *Metrics) Start(listenAddress string, enablePprof bool, workqueueBacklogThreshold int) (*http.Server, error) {
 	m.registry.MustRegister(m.certificateExpiryTimeSeconds)
 	m.registry.MustRegister(m.certificateReadyStatus)
 	m.registry.MustRegister(m.acmeClientRequestDurationSeconds)
 	m.registry.MustRegister(m.acmeClientRequestCount)
 	m.registry.MustRegister(m.controllerSyncCallCount)
+	m.registry.MustRegister(m.controllerWorkqueueDepth)
 
 	mux := http.NewServeMux()
 	mux.Handle("/metrics", promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}))
+	mux.Handle("/readyz", m.readyzHandler(workqueueBacklogThreshold))
 	if enablePprof {
 		profiling.Install(mux)
 	}
@@ -0,0 +1,156 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// WorkqueueMetricsProvider returns a workqueue.MetricsProvider that exposes
+// the depth of each named controller workqueue. It must be registered using
+// workqueue.SetProvider before any controller workqueues are constructed.
+func (m *Metrics) WorkqueueMetricsProvider() workqueue.MetricsProvider {
+	return workqueueMetricsProvider{m}
+}
+
+// workqueueDepth records the depth of a single workqueue, so that it can be
+// read back when checking readiness, alongside the exposed gauge.
+type workqueueDepth struct {
+	depth int64
+	gauge prometheus.Gauge
+}
+
+func (d *workqueueDepth) Inc() {
+	atomic.AddInt64(&d.depth, 1)
+	d.gauge.Inc()
+}
+
+func (d *workqueueDepth) Dec() {
+	atomic.AddInt64(&d.depth, -1)
+	d.gauge.Dec()
+}
+
+func (d *workqueueDepth) get() int64 {
+	return atomic.LoadInt64(&d.depth)
+}
+
+func (m *Metrics) workqueueDepthFor(name string) *workqueueDepth {
+	m.workqueueDepthsLock.Lock()
+	defer m.workqueueDepthsLock.Unlock()
+
+	if d, ok := m.workqueueDepths[name]; ok {
+		return d
+	}
+	d := &workqueueDepth{gauge: m.controllerWorkqueueDepth.WithLabelValues(name)}
+	m.workqueueDepths[name] = d
+	return d
+}
+
+// workqueuesOverThreshold returns the depth of each workqueue that holds more
+// than threshold items.
+func (m *Metrics) workqueuesOverThreshold(threshold int64) map[string]int64 {
+	m.workqueueDepthsLock.Lock()
+	defer m.workqueueDepthsLock.Unlock()
+
+	backlogged := make(map[string]int64)
+	for name, d := range m.workqueueDepths {
+		if depth := d.get(); depth > threshold {
+			backlogged[name] = depth
+		}
+	}
+	return backlogged
+}
+
+// readyzHandler returns a handler that reports not ready while any workqueue
+// holds more than threshold items. If threshold is not greater than zero, the
+// handler always reports ready.
+func (m *Metrics) readyzHandler(threshold int) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		if threshold <= 0 {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		backlogged := m.workqueuesOverThreshold(int64(threshold))
+		if len(backlogged) == 0 {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		var names []string
+		for name := range backlogged {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		var msgs []string
+		for _, name := range names {
+			msgs = append(msgs, fmt.Sprintf("%s: %d", name, backlogged[name]))
+		}
+
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintf(w, "workqueues over the backlog threshold of %d items: %s\n", threshold, strings.Join(msgs, ", "))
+	}
+}
+
+// workqueueMetricsProvider only exposes the depth of workqueues. All other
+// workqueue metrics are discarded.
+type workqueueMetricsProvider struct {
+	m *Metrics
+}
+
+func (p workqueueMetricsProvider) NewDepthMetric(name string) workqueue.GaugeMetric {
+	return p.m.workqueueDepthFor(name)
+}
+
+func (workqueueMetricsProvider) NewAddsMetric(string) workqueue.CounterMetric {
+	return noopMetric{}
+}
+
+func (workqueueMetricsProvider) NewLatencyMetric(string) workqueue.HistogramMetric {
+	return noopMetric{}
+}
+
+func (workqueueMetricsProvider) NewWorkDurationMetric(string) workqueue.HistogramMetric {
+	return noopMetric{}
+}
+
+func (workqueueMetricsProvider) NewUnfinishedWorkSecondsMetric(string) workqueue.SettableGaugeMetric {
+	return noopMetric{}
+}
+
+func (workqueueMetricsProvider) NewLongestRunningProcessorSecondsMetric(string) workqueue.SettableGaugeMetric {
+	return noopMetric{}
+}
+
+func (workqueueMetricsProvider) NewRetriesMetric(string) workqueue.CounterMetric {
+	return noopMetric{}
+}
+
+type noopMetric struct{}
+
+func (noopMetric) Inc()            {}
+func (noopMetric) Dec()            {}
+func (noopMetric) Set(float64)     {}
+func (noopMetric) Observe(float64) {}
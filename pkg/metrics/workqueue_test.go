@@ -0,0 +1,121 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/client-go/util/workqueue"
+
+	logtesting "github.com/jetstack/cert-manager/pkg/logs/testing"
+)
+
+const workqueueDepthMetadata = `
+	# HELP certmanager_controller_workqueue_depth The number of items waiting to be processed in a controller's workqueue.
+	# TYPE certmanager_controller_workqueue_depth gauge
+`
+
+func TestWorkqueueDepthMetric(t *testing.T) {
+	m := New(logtesting.TestLogger{T: t})
+
+	// The workqueue metrics provider can only be set once per process, so
+	// this must be the only test that constructs workqueues using it.
+	workqueue.SetProvider(m.WorkqueueMetricsProvider())
+
+	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "test-controller")
+	defer queue.ShutDown()
+
+	queue.Add("default/a")
+	queue.Add("default/b")
+	queue.Add("default/c")
+	// items that are already queued are not added again
+	queue.Add("default/a")
+
+	if err := testutil.CollectAndCompare(m.controllerWorkqueueDepth,
+		strings.NewReader(workqueueDepthMetadata+`
+	certmanager_controller_workqueue_depth{controller="test-controller"} 3
+`),
+		"certmanager_controller_workqueue_depth",
+	); err != nil {
+		t.Errorf("unexpected workqueue depth after adding items: %s", err)
+	}
+
+	item, _ := queue.Get()
+	queue.Done(item)
+
+	if err := testutil.CollectAndCompare(m.controllerWorkqueueDepth,
+		strings.NewReader(workqueueDepthMetadata+`
+	certmanager_controller_workqueue_depth{controller="test-controller"} 2
+`),
+		"certmanager_controller_workqueue_depth",
+	); err != nil {
+		t.Errorf("unexpected workqueue depth after processing an item: %s", err)
+	}
+}
+
+func TestReadyzHandler(t *testing.T) {
+	tests := map[string]struct {
+		threshold      int
+		depths         map[string]int
+		expectedStatus int
+		expectedBody   string
+	}{
+		"always ready if no threshold is set": {
+			threshold:      0,
+			depths:         map[string]int{"certificates-issuing": 100},
+			expectedStatus: http.StatusOK,
+		},
+		"ready if all workqueues are within the threshold": {
+			threshold:      10,
+			depths:         map[string]int{"certificates-issuing": 10, "orders": 2},
+			expectedStatus: http.StatusOK,
+		},
+		"not ready if any workqueue is over the threshold": {
+			threshold:      10,
+			depths:         map[string]int{"certificates-issuing": 11, "orders": 2, "challenges": 20},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   "workqueues over the backlog threshold of 10 items: certificates-issuing: 11, challenges: 20\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := New(logtesting.TestLogger{T: t})
+			provider := m.WorkqueueMetricsProvider()
+			for queue, depth := range test.depths {
+				metric := provider.NewDepthMetric(queue)
+				for i := 0; i < depth; i++ {
+					metric.Inc()
+				}
+			}
+
+			rec := httptest.NewRecorder()
+			m.readyzHandler(test.threshold)(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			if rec.Code != test.expectedStatus {
+				t.Errorf("expected status %d, got %d", test.expectedStatus, rec.Code)
+			}
+			if body := rec.Body.String(); body != test.expectedBody {
+				t.Errorf("expected body %q, got %q", test.expectedBody, body)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+)
+
+// OIDExtensionBasicConstraints is the OID of the BasicConstraints extension,
+// as defined in RFC 5280, 4.2.1.9.
+var OIDExtensionBasicConstraints = asn1.ObjectIdentifier{2, 5, 29, 19}
+
+// basicConstraints is copied from x509.go
+type basicConstraints struct {
+	IsCA       bool `asn1:"optional"`
+	MaxPathLen int  `asn1:"optional,default:-1"`
+}
+
+// MarshalBasicConstraints returns a BasicConstraints extension for a CA with
+// the given path length constraint. Following the x509 package, a maxPathLen
+// of 0 is only encoded if maxPathLenZero is true, otherwise no path length
+// constraint is set.
+func MarshalBasicConstraints(maxPathLen int, maxPathLenZero bool) (pkix.Extension, error) {
+	if maxPathLen == 0 && !maxPathLenZero {
+		maxPathLen = -1
+	}
+	value, err := asn1.Marshal(basicConstraints{IsCA: true, MaxPathLen: maxPathLen})
+	if err != nil {
+		return pkix.Extension{}, err
+	}
+	return pkix.Extension{Id: OIDExtensionBasicConstraints, Critical: true, Value: value}, nil
+}
+
+// MaxPathLenFromCSR returns the path length constraint requested by the
+// BasicConstraints extension of the given CSR, in the same form as the
+// MaxPathLen and MaxPathLenZero fields of an x509.Certificate. It returns 0
+// and false if the CSR does not request a path length constraint.
+func MaxPathLenFromCSR(csr *x509.CertificateRequest) (maxPathLen int, maxPathLenZero bool, err error) {
+	for _, extension := range csr.Extensions {
+		if !extension.Id.Equal(OIDExtensionBasicConstraints) {
+			continue
+		}
+		var constraints basicConstraints
+		rest, err := asn1.Unmarshal(extension.Value, &constraints)
+		if err != nil {
+			return 0, false, fmt.Errorf("failed to asn1 decode basic constraints: %w", err)
+		}
+		if len(rest) != 0 {
+			return 0, false, fmt.Errorf("trailing data after asn1 encoded basic constraints")
+		}
+		if constraints.MaxPathLen < 0 {
+			return 0, false, nil
+		}
+		return constraints.MaxPathLen, constraints.MaxPathLen == 0, nil
+	}
+	return 0, false, nil
+}
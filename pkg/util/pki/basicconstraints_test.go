@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"crypto/x509"
+	"testing"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+func TestMaxPathLenEncodedInCSR(t *testing.T) {
+	pk, err := GenerateRSAPrivateKey(2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		spec               cmapi.CertificateSpec
+		wantMaxPathLen     int
+		wantMaxPathLenZero bool
+	}{
+		"CA without a path length constraint": {
+			spec: cmapi.CertificateSpec{IsCA: true},
+		},
+		"CA with a path length of 1": {
+			spec:           cmapi.CertificateSpec{IsCA: true, MaxPathLen: 1},
+			wantMaxPathLen: 1,
+		},
+		"CA with a path length of 0": {
+			spec:               cmapi.CertificateSpec{IsCA: true, MaxPathLenZero: true},
+			wantMaxPathLenZero: true,
+		},
+		"path length is ignored for a leaf certificate": {
+			spec: cmapi.CertificateSpec{MaxPathLen: 1, MaxPathLenZero: true},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			test.spec.CommonName = "example.org"
+			template, err := GenerateCSR(&cmapi.Certificate{Spec: test.spec})
+			if err != nil {
+				t.Fatal(err)
+			}
+			der, err := EncodeCSR(template, pk)
+			if err != nil {
+				t.Fatal(err)
+			}
+			csr, err := x509.ParseCertificateRequest(der)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			maxPathLen, maxPathLenZero, err := MaxPathLenFromCSR(csr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if maxPathLen != test.wantMaxPathLen || maxPathLenZero != test.wantMaxPathLenZero {
+				t.Errorf("MaxPathLenFromCSR() = %d, %v, want %d, %v", maxPathLen, maxPathLenZero, test.wantMaxPathLen, test.wantMaxPathLenZero)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"bytes"
+	"crypto/x509"
+)
+
+// maxChainLength is the maximum number of certificates in a chain built by
+// BuildCertificateChains. It guards against cycles between cross-signed
+// certificates.
+const maxChainLength = 10
+
+// BuildCertificateChains returns every chain that can be built upwards from
+// cert using the given candidate issuing certificates. Each chain starts with
+// cert and ends either at a self-signed certificate or at a certificate whose
+// issuer is not among the candidates.
+func BuildCertificateChains(cert *x509.Certificate, candidates []*x509.Certificate) [][]*x509.Certificate {
+	return buildChains([]*x509.Certificate{cert}, candidates)
+}
+
+func buildChains(chain []*x509.Certificate, candidates []*x509.Certificate) [][]*x509.Certificate {
+	last := chain[len(chain)-1]
+	if isSelfSigned(last) || len(chain) >= maxChainLength {
+		return [][]*x509.Certificate{chain}
+	}
+
+	var chains [][]*x509.Certificate
+	for _, candidate := range candidates {
+		if containsCertificate(chain, candidate) {
+			continue
+		}
+		if !bytes.Equal(last.RawIssuer, candidate.RawSubject) {
+			continue
+		}
+		if err := last.CheckSignatureFrom(candidate); err != nil {
+			continue
+		}
+
+		next := make([]*x509.Certificate, len(chain), len(chain)+1)
+		copy(next, chain)
+		chains = append(chains, buildChains(append(next, candidate), candidates)...)
+	}
+
+	if len(chains) == 0 {
+		return [][]*x509.Certificate{chain}
+	}
+
+	return chains
+}
+
+// PreferredCertificateChain returns the first chain built from cert and the
+// candidates that is anchored at a root with the given common name. The root
+// of a chain is its last certificate if that certificate is self-signed,
+// otherwise it is the issuer of its last certificate.
+// It returns false if no such chain can be built.
+func PreferredCertificateChain(cert *x509.Certificate, candidates []*x509.Certificate, rootCN string) ([]*x509.Certificate, bool) {
+	for _, chain := range BuildCertificateChains(cert, candidates) {
+		last := chain[len(chain)-1]
+		if isSelfSigned(last) && last.Subject.CommonName == rootCN {
+			return chain, true
+		}
+		if !isSelfSigned(last) && last.Issuer.CommonName == rootCN {
+			return chain, true
+		}
+	}
+
+	return nil, false
+}
+
+func isSelfSigned(cert *x509.Certificate) bool {
+	return bytes.Equal(cert.RawIssuer, cert.RawSubject) && cert.CheckSignatureFrom(cert) == nil
+}
+
+func containsCertificate(certs []*x509.Certificate, cert *x509.Certificate) bool {
+	for _, c := range certs {
+		if c.Equal(cert) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"crypto"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+}
+
+func mustCreateCA(t *testing.T, serial int64, cn string, key crypto.Signer, parent *testCA) *testCA {
+	if key == nil {
+		var err error
+		key, err = GenerateECPrivateKey(256)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+
+	issuerCert, issuerKey := template, key
+	if parent != nil {
+		issuerCert, issuerKey = parent.cert, parent.key
+	}
+
+	_, cert, err := SignCertificate(template, issuerCert, key.Public(), issuerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testCA{cert: cert, key: key}
+}
+
+func chainCommonNames(chain []*x509.Certificate) []string {
+	var names []string
+	for _, c := range chain {
+		names = append(names, c.Subject.CommonName+" <- "+c.Issuer.CommonName)
+	}
+	return names
+}
+
+func TestPreferredCertificateChain(t *testing.T) {
+	// Build a hierarchy where the intermediate is issued by "Root X", which
+	// is both self-signed and cross-signed by "Root Y".
+	rootY := mustCreateCA(t, 1, "Root Y", nil, nil)
+	rootX := mustCreateCA(t, 2, "Root X", nil, nil)
+	rootXCrossSigned := mustCreateCA(t, 3, "Root X", rootX.key, rootY)
+	intermediate := mustCreateCA(t, 4, "Intermediate", nil, rootX)
+
+	candidates := []*x509.Certificate{rootXCrossSigned.cert, rootX.cert, rootY.cert}
+
+	tests := map[string]struct {
+		candidates    []*x509.Certificate
+		rootCN        string
+		expectedChain []*x509.Certificate
+		expectedOK    bool
+	}{
+		"preferring the self-signed root selects the short chain": {
+			candidates:    candidates,
+			rootCN:        "Root X",
+			expectedChain: []*x509.Certificate{intermediate.cert, rootX.cert},
+			expectedOK:    true,
+		},
+		"preferring the cross-signing root selects the long chain": {
+			candidates:    candidates,
+			rootCN:        "Root Y",
+			expectedChain: []*x509.Certificate{intermediate.cert, rootXCrossSigned.cert, rootY.cert},
+			expectedOK:    true,
+		},
+		"a chain anchored at a root that is not a candidate is matched by its issuer": {
+			candidates:    []*x509.Certificate{rootXCrossSigned.cert},
+			rootCN:        "Root Y",
+			expectedChain: []*x509.Certificate{intermediate.cert, rootXCrossSigned.cert},
+			expectedOK:    true,
+		},
+		"an unknown root does not match any chain": {
+			candidates: candidates,
+			rootCN:     "Root Z",
+			expectedOK: false,
+		},
+		"no candidates only matches the issuer of the certificate": {
+			rootCN:        "Root X",
+			expectedChain: []*x509.Certificate{intermediate.cert},
+			expectedOK:    true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			chain, ok := PreferredCertificateChain(intermediate.cert, test.candidates, test.rootCN)
+			if ok != test.expectedOK {
+				t.Fatalf("unexpected ok, exp=%t got=%t", test.expectedOK, ok)
+			}
+
+			if len(chain) != len(test.expectedChain) {
+				t.Fatalf("unexpected chain, exp=%v got=%v", chainCommonNames(test.expectedChain), chainCommonNames(chain))
+			}
+			for i := range chain {
+				if !chain[i].Equal(test.expectedChain[i]) {
+					t.Errorf("unexpected chain, exp=%v got=%v", chainCommonNames(test.expectedChain), chainCommonNames(chain))
+				}
+			}
+		})
+	}
+}
+
+func TestBuildCertificateChainsIgnoresCycles(t *testing.T) {
+	rootA := mustCreateCA(t, 1, "Root A", nil, nil)
+	rootB := mustCreateCA(t, 2, "Root B", nil, nil)
+	// A and B cross-sign each other
+	aByB := mustCreateCA(t, 3, "Root A", rootA.key, rootB)
+	bByA := mustCreateCA(t, 4, "Root B", rootB.key, rootA)
+	leaf := mustCreateCA(t, 5, "Leaf", nil, rootA)
+
+	chains := BuildCertificateChains(leaf.cert, []*x509.Certificate{aByB.cert, bByA.cert})
+	for _, chain := range chains {
+		if len(chain) > maxChainLength {
+			t.Errorf("chain exceeds maximum length: %v", chainCommonNames(chain))
+		}
+	}
+	if len(chains) == 0 {
+		t.Errorf("expected at least one chain to be built")
+	}
+}
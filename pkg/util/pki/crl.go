@@ -0,0 +1,65 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/jetstack/cert-manager/pkg/util/errors"
+)
+
+// GenerateCRL creates a certificate revocation list that lists the given
+// revoked certificates, signed by the given CA, and returns it PEM encoded.
+// The CA certificate must have the cRLSign key usage and a subject key
+// identifier.
+func GenerateCRL(caCert *x509.Certificate, caKey crypto.Signer, revoked []pkix.RevokedCertificate, number *big.Int, thisUpdate, nextUpdate time.Time) ([]byte, error) {
+	template := &x509.RevocationList{
+		RevokedCertificates: revoked,
+		Number:              number,
+		ThisUpdate:          thisUpdate,
+		NextUpdate:          nextUpdate,
+	}
+
+	derBytes, err := x509.CreateRevocationList(rand.Reader, template, caCert, caKey)
+	if err != nil {
+		return nil, fmt.Errorf("error creating CRL: %w", err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: derBytes}), nil
+}
+
+// DecodeX509CRLBytes will decode a PEM encoded certificate revocation list.
+func DecodeX509CRLBytes(crlBytes []byte) (*pkix.CertificateList, error) {
+	block, _ := pem.Decode(crlBytes)
+	if block == nil || block.Type != "X509 CRL" {
+		return nil, errors.NewInvalidData("error decoding CRL PEM block")
+	}
+
+	crl, err := x509.ParseDERCRL(block.Bytes)
+	if err != nil {
+		return nil, errors.NewInvalidData("error parsing CRL: %s", err.Error())
+	}
+
+	return crl, nil
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestGenerateCRL(t *testing.T) {
+	caKey, err := GenerateECPrivateKey(256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		SubjectKeyId:          []byte{1, 2, 3, 4},
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, caKey.Public(), caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCert, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	thisUpdate := time.Now().UTC().Truncate(time.Second)
+	nextUpdate := thisUpdate.Add(24 * time.Hour)
+	revokedAt := thisUpdate.Add(-time.Minute)
+	revoked := []pkix.RevokedCertificate{
+		{SerialNumber: big.NewInt(1234), RevocationTime: revokedAt},
+	}
+
+	crlPEM, err := GenerateCRL(caCert, caKey, revoked, big.NewInt(1), thisUpdate, nextUpdate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	crl, err := DecodeX509CRLBytes(crlPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := caCert.CheckCRLSignature(crl); err != nil {
+		t.Errorf("failed to verify CRL signature: %v", err)
+	}
+	if !crl.TBSCertList.ThisUpdate.Equal(thisUpdate) {
+		t.Errorf("unexpected thisUpdate, exp=%s, got=%s", thisUpdate, crl.TBSCertList.ThisUpdate)
+	}
+	if !crl.TBSCertList.NextUpdate.Equal(nextUpdate) {
+		t.Errorf("unexpected nextUpdate, exp=%s, got=%s", nextUpdate, crl.TBSCertList.NextUpdate)
+	}
+	if len(crl.TBSCertList.RevokedCertificates) != 1 {
+		t.Fatalf("expected 1 revoked certificate, got %d", len(crl.TBSCertList.RevokedCertificates))
+	}
+	got := crl.TBSCertList.RevokedCertificates[0]
+	if got.SerialNumber.Cmp(big.NewInt(1234)) != 0 {
+		t.Errorf("unexpected revoked serial number, exp=1234, got=%s", got.SerialNumber)
+	}
+	if !got.RevocationTime.Equal(revokedAt) {
+		t.Errorf("unexpected revocation time, exp=%s, got=%s", revokedAt, got.RevocationTime)
+	}
+
+	otherKey, err := GenerateECPrivateKey(256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherTemplate := *caTemplate
+	otherDER, err := x509.CreateCertificate(rand.Reader, &otherTemplate, &otherTemplate, otherKey.Public(), otherKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherCert, err := x509.ParseCertificate(otherDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := otherCert.CheckCRLSignature(crl); err == nil {
+		t.Errorf("expected CRL signature verification against a different CA to fail")
+	}
+}
+
+func TestGenerateCRLRequiresCRLSignKeyUsage(t *testing.T) {
+	caKey, err := GenerateECPrivateKey(256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		SubjectKeyId:          []byte{1, 2, 3, 4},
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, caKey.Public(), caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCert, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := GenerateCRL(caCert, caKey, nil, big.NewInt(1), time.Now(), time.Now().Add(time.Hour)); err == nil {
+		t.Errorf("expected an error generating a CRL with a CA that cannot sign CRLs")
+	}
+}
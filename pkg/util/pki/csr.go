@@ -57,13 +57,52 @@ func URIsForCertificate(crt *v1.Certificate) ([]*url.URL, error) {
 	return uris, nil
 }
 
+func RegisteredIDsForCertificate(crt *v1.Certificate) ([]asn1.ObjectIdentifier, error) {
+	var registeredIDs []asn1.ObjectIdentifier
+	for _, id := range crt.Spec.RegisteredIDs {
+		oid, err := ParseObjectIdentifier(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse RegisteredIDs: %s", err)
+		}
+		registeredIDs = append(registeredIDs, oid)
+	}
+
+	return registeredIDs, nil
+}
+
 func DNSNamesForCertificate(crt *v1.Certificate) ([]string, error) {
 	_, err := URLsFromStrings(crt.Spec.DNSNames)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse DNSNames: %s", err)
 	}
 
-	return crt.Spec.DNSNames, nil
+	return DNSNamesToASCII(crt.Spec.DNSNames)
+}
+
+// MaxCommonNameLength is the maximum length in bytes of a common name, as
+// defined by the ub-common-name upper bound in RFC 5280.
+const MaxCommonNameLength = 64
+
+// CommonNameForCertificate returns the common name to request for the given
+// Certificate. If spec.commonName is empty and the Certificate has opted in
+// using the CommonNameFromDNSNameAnnotationKey annotation, the first of the
+// given (ASCII encoded) DNS names is used instead.
+func CommonNameForCertificate(crt *v1.Certificate, dnsNames []string) string {
+	if len(crt.Spec.CommonName) > 0 || crt.Annotations[v1.CommonNameFromDNSNameAnnotationKey] != "true" {
+		return crt.Spec.CommonName
+	}
+
+	return CommonNameFromDNSNames(dnsNames)
+}
+
+// CommonNameFromDNSNames returns the first DNS name if it fits within
+// MaxCommonNameLength, and an empty string otherwise.
+func CommonNameFromDNSNames(dnsNames []string) string {
+	if len(dnsNames) == 0 || len(dnsNames[0]) > MaxCommonNameLength {
+		return ""
+	}
+
+	return dnsNames[0]
 }
 
 func URLsFromStrings(urlStrs []string) ([]*url.URL, error) {
@@ -95,6 +134,14 @@ func IPAddressesToString(ipAddresses []net.IP) []string {
 	return ipNames
 }
 
+func ObjectIdentifiersToString(oids []asn1.ObjectIdentifier) []string {
+	var oidStrs []string
+	for _, oid := range oids {
+		oidStrs = append(oidStrs, oid.String())
+	}
+	return oidStrs
+}
+
 func URLsToString(uris []*url.URL) []string {
 	var uriStrs []string
 	for _, uri := range uris {
@@ -178,7 +225,6 @@ func BuildCertManagerKeyUsages(ku x509.KeyUsage, eku []x509.ExtKeyUsage) []v1.Ke
 // The CSR will not be signed, and should be passed to either EncodeCSR or
 // to the x509.CreateCertificateRequest function.
 func GenerateCSR(crt *v1.Certificate) (*x509.CertificateRequest, error) {
-	commonName := crt.Spec.CommonName
 	iPAddresses := IPAddressesForCertificate(crt)
 	organization := OrganizationForCertificate(crt)
 	subject := SubjectForCertificate(crt)
@@ -187,14 +233,20 @@ func GenerateCSR(crt *v1.Certificate) (*x509.CertificateRequest, error) {
 	if err != nil {
 		return nil, err
 	}
+	commonName := CommonNameForCertificate(crt, dnsNames)
 
 	uriNames, err := URIsForCertificate(crt)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(commonName) == 0 && len(dnsNames) == 0 && len(uriNames) == 0 && len(crt.Spec.EmailAddresses) == 0 && len(crt.Spec.IPAddresses) == 0 {
-		return nil, fmt.Errorf("no common name, DNS name, URI SAN, or Email SAN specified on certificate")
+	registeredIDs, err := RegisteredIDsForCertificate(crt)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(commonName) == 0 && len(dnsNames) == 0 && len(uriNames) == 0 && len(crt.Spec.EmailAddresses) == 0 && len(crt.Spec.IPAddresses) == 0 && len(registeredIDs) == 0 {
+		return nil, fmt.Errorf("no common name, DNS name, URI SAN, Email SAN or registeredID SAN specified on certificate")
 	}
 
 	pubKeyAlgo, sigAlgo, err := SignatureAlgorithm(crt)
@@ -210,6 +262,27 @@ func GenerateCSR(crt *v1.Certificate) (*x509.CertificateRequest, error) {
 		}
 	}
 
+	// The x509 package does not support registeredID names, so the
+	// subjectAltName extension must be built here if any are requested. The
+	// x509 package will not add its own if one is present in ExtraExtensions.
+	if len(registeredIDs) > 0 {
+		sanExtension, err := MarshalSANs(dnsNames, crt.Spec.EmailAddresses, iPAddresses, uriNames, registeredIDs)
+		if err != nil {
+			return nil, err
+		}
+		extraExtensions = append(extraExtensions, sanExtension)
+	}
+
+	// The path length constraint of a CA is requested with a BasicConstraints
+	// extension, which issuers copy into the signed certificate.
+	if crt.Spec.IsCA && (crt.Spec.MaxPathLen > 0 || crt.Spec.MaxPathLenZero) {
+		basicConstraintsExtension, err := MarshalBasicConstraints(crt.Spec.MaxPathLen, crt.Spec.MaxPathLenZero)
+		if err != nil {
+			return nil, fmt.Errorf("failed to asn1 encode basic constraints: %w", err)
+		}
+		extraExtensions = append(extraExtensions, basicConstraintsExtension)
+	}
+
 	return &x509.CertificateRequest{
 		Version:            3,
 		SignatureAlgorithm: sigAlgo,
@@ -270,8 +343,11 @@ func buildKeyUsagesExtensionsForCertificate(crt *v1.Certificate) ([]pkix.Extensi
 // generated by GenerateCSR.
 // The PublicKey field must be populated by the caller.
 func GenerateTemplate(crt *v1.Certificate) (*x509.Certificate, error) {
-	commonName := crt.Spec.CommonName
-	dnsNames := crt.Spec.DNSNames
+	dnsNames, err := DNSNamesToASCII(crt.Spec.DNSNames)
+	if err != nil {
+		return nil, err
+	}
+	commonName := CommonNameForCertificate(crt, dnsNames)
 	ipAddresses := IPAddressesForCertificate(crt)
 	organization := OrganizationForCertificate(crt)
 	subject := SubjectForCertificate(crt)
@@ -300,12 +376,20 @@ func GenerateTemplate(crt *v1.Certificate) (*x509.Certificate, error) {
 		return nil, err
 	}
 
+	var maxPathLen int
+	var maxPathLenZero bool
+	if crt.Spec.IsCA {
+		maxPathLen, maxPathLenZero = crt.Spec.MaxPathLen, crt.Spec.MaxPathLenZero
+	}
+
 	return &x509.Certificate{
 		Version:               3,
 		BasicConstraintsValid: true,
 		SerialNumber:          serialNumber,
 		PublicKeyAlgorithm:    pubKeyAlgo,
 		IsCA:                  crt.Spec.IsCA,
+		MaxPathLen:            maxPathLen,
+		MaxPathLenZero:        maxPathLenZero,
 		Subject: pkix.Name{
 			Country:            subject.Countries,
 			Organization:       organization,
@@ -340,6 +424,27 @@ func GenerateTemplateFromCertificateRequest(cr *v1.CertificateRequest) (*x509.Ce
 	return GenerateTemplateFromCSRPEMWithUsages(cr.Spec.Request, certDuration, cr.Spec.IsCA, keyUsage, extKeyUsage)
 }
 
+// GenerateTemplateFromCertificateRequestVerbatim generates a certificate
+// template for the given CertificateRequest that uses the key usages and
+// extended key usages requested by the CSR itself, rather than those set on
+// the CertificateRequest's spec.
+// The duration and isCA fields of the spec are still honoured.
+func GenerateTemplateFromCertificateRequestVerbatim(cr *v1.CertificateRequest) (*x509.Certificate, error) {
+	csr, err := DecodeX509CertificateRequestBytes(cr.Spec.Request)
+	if err != nil {
+		return nil, err
+	}
+	keyUsage, extKeyUsage, err := KeyUsagesFromCSR(csr)
+	if err != nil {
+		return nil, err
+	}
+	if cr.Spec.IsCA {
+		keyUsage |= x509.KeyUsageCertSign
+	}
+	certDuration := apiutil.DefaultCertDuration(cr.Spec.Duration)
+	return GenerateTemplateFromCSRPEMWithUsages(cr.Spec.Request, certDuration, cr.Spec.IsCA, keyUsage, extKeyUsage)
+}
+
 func GenerateTemplateFromCSRPEM(csrPEM []byte, duration time.Duration, isCA bool) (*x509.Certificate, error) {
 	var (
 		ku  x509.KeyUsage
@@ -368,6 +473,17 @@ func GenerateTemplateFromCSRPEMWithUsages(csrPEM []byte, duration time.Duration,
 		return nil, fmt.Errorf("failed to generate serial number: %s", err.Error())
 	}
 
+	// Only CA certificates carry the path length constraint requested by
+	// the CSR.
+	var maxPathLen int
+	var maxPathLenZero bool
+	if isCA {
+		maxPathLen, maxPathLenZero, err = MaxPathLenFromCSR(csr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &x509.Certificate{
 		Version:               csr.Version,
 		BasicConstraintsValid: true,
@@ -375,6 +491,8 @@ func GenerateTemplateFromCSRPEMWithUsages(csrPEM []byte, duration time.Duration,
 		PublicKeyAlgorithm:    csr.PublicKeyAlgorithm,
 		PublicKey:             csr.PublicKey,
 		IsCA:                  isCA,
+		MaxPathLen:            maxPathLen,
+		MaxPathLenZero:        maxPathLenZero,
 		Subject:               csr.Subject,
 		NotBefore:             time.Now(),
 		NotAfter:              time.Now().Add(duration),
@@ -531,8 +649,46 @@ func SignatureAlgorithm(crt *v1.Certificate) (x509.PublicKeyAlgorithm, x509.Sign
 		default:
 			return x509.UnknownPublicKeyAlgorithm, x509.UnknownSignatureAlgorithm, fmt.Errorf("unsupported ecdsa keysize specified: %d", crt.Spec.PrivateKey.Size)
 		}
+	case v1.Ed25519KeyAlgorithm:
+		pubKeyAlgo = x509.Ed25519
+		sigAlgo = x509.PureEd25519
 	default:
-		return x509.UnknownPublicKeyAlgorithm, x509.UnknownSignatureAlgorithm, fmt.Errorf("unsupported algorithm specified: %s. should be either 'ecdsa' or 'rsa", crt.Spec.PrivateKey.Algorithm)
+		return x509.UnknownPublicKeyAlgorithm, x509.UnknownSignatureAlgorithm, fmt.Errorf("unsupported algorithm specified: %s. should be either 'ecdsa', 'ed25519' or 'rsa", crt.Spec.PrivateKey.Algorithm)
+	}
+
+	if crt.Spec.PrivateKey != nil && crt.Spec.PrivateKey.SignatureHash != "" {
+		var err error
+		sigAlgo, err = signatureAlgorithmWithHash(pubKeyAlgo, crt.Spec.PrivateKey.SignatureHash)
+		if err != nil {
+			return x509.UnknownPublicKeyAlgorithm, x509.UnknownSignatureAlgorithm, err
+		}
 	}
+
 	return pubKeyAlgo, sigAlgo, nil
 }
+
+// signatureAlgorithmWithHash returns the signature algorithm of the given
+// public key algorithm that uses the given hash function.
+func signatureAlgorithmWithHash(pubKeyAlgo x509.PublicKeyAlgorithm, hash v1.PrivateKeySignatureHash) (x509.SignatureAlgorithm, error) {
+	algorithms := map[x509.PublicKeyAlgorithm]map[v1.PrivateKeySignatureHash]x509.SignatureAlgorithm{
+		x509.RSA: {
+			v1.SHA1SignatureHash:   x509.SHA1WithRSA,
+			v1.SHA256SignatureHash: x509.SHA256WithRSA,
+			v1.SHA384SignatureHash: x509.SHA384WithRSA,
+			v1.SHA512SignatureHash: x509.SHA512WithRSA,
+		},
+		x509.ECDSA: {
+			v1.SHA1SignatureHash:   x509.ECDSAWithSHA1,
+			v1.SHA256SignatureHash: x509.ECDSAWithSHA256,
+			v1.SHA384SignatureHash: x509.ECDSAWithSHA384,
+			v1.SHA512SignatureHash: x509.ECDSAWithSHA512,
+		},
+	}
+
+	sigAlgo, ok := algorithms[pubKeyAlgo][hash]
+	if !ok {
+		return x509.UnknownSignatureAlgorithm, fmt.Errorf("unsupported signature hash %q for %s keys", hash, pubKeyAlgo)
+	}
+
+	return sigAlgo, nil
+}
@@ -17,12 +17,16 @@ limitations under the License.
 package pki
 
 import (
+	"crypto"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/asn1"
 	"reflect"
+	"strings"
 	"testing"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	v1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	"github.com/jetstack/cert-manager/pkg/util"
@@ -113,10 +117,15 @@ func TestBuildUsages(t *testing.T) {
 }
 
 func TestCommonNameForCertificate(t *testing.T) {
+	// the longest DNS name that fits within the maximum common name length
+	maxLengthName := strings.Repeat("a", MaxCommonNameLength-len(".com")) + ".com"
+	tooLongName := "a" + maxLengthName
+
 	type testT struct {
 		name        string
 		crtCN       string
 		crtDNSNames []string
+		fromDNSName bool
 		expectedCN  string
 	}
 	tests := []testT{
@@ -141,10 +150,44 @@ func TestCommonNameForCertificate(t *testing.T) {
 			crtDNSNames: []string{"dnsname1", "dnsname2"},
 			expectedCN:  "",
 		},
+		{
+			name:        "common name derived from the first dns name when opted in",
+			crtDNSNames: []string{"dnsname1", "dnsname2"},
+			fromDNSName: true,
+			expectedCN:  "dnsname1",
+		},
+		{
+			name:        "common name not derived if already set",
+			crtCN:       "cn",
+			crtDNSNames: []string{"dnsname1"},
+			fromDNSName: true,
+			expectedCN:  "cn",
+		},
+		{
+			name:        "common name derived from a dns name of the maximum length",
+			crtDNSNames: []string{maxLengthName},
+			fromDNSName: true,
+			expectedCN:  maxLengthName,
+		},
+		{
+			name:        "common name left empty if the first dns name is too long",
+			crtDNSNames: []string{tooLongName, "dnsname2"},
+			fromDNSName: true,
+			expectedCN:  "",
+		},
+		{
+			name:        "common name left empty if there are no dns names",
+			fromDNSName: true,
+			expectedCN:  "",
+		},
 	}
 	testFn := func(test testT) func(*testing.T) {
 		return func(t *testing.T) {
-			actualCN := buildCertificate(test.crtCN, test.crtDNSNames...).Spec.CommonName
+			crt := buildCertificate(test.crtCN, test.crtDNSNames...)
+			if test.fromDNSName {
+				crt.Annotations = map[string]string{cmapi.CommonNameFromDNSNameAnnotationKey: "true"}
+			}
+			actualCN := CommonNameForCertificate(crt, crt.Spec.DNSNames)
 			if actualCN != test.expectedCN {
 				t.Errorf("expected %q but got %q", test.expectedCN, actualCN)
 				return
@@ -223,6 +266,7 @@ func TestSignatureAlgorithmForCertificate(t *testing.T) {
 		name            string
 		keyAlgo         cmapi.PrivateKeyAlgorithm
 		keySize         int
+		signatureHash   cmapi.PrivateKeySignatureHash
 		expectErr       bool
 		expectedSigAlgo x509.SignatureAlgorithm
 		expectedKeyType x509.PublicKeyAlgorithm
@@ -301,16 +345,58 @@ func TestSignatureAlgorithmForCertificate(t *testing.T) {
 			keySize:   100,
 			expectErr: true,
 		},
+		{
+			name:            "certificate with KeyAlgorithm ed25519",
+			keyAlgo:         cmapi.Ed25519KeyAlgorithm,
+			expectedSigAlgo: x509.PureEd25519,
+			expectedKeyType: x509.Ed25519,
+		},
 		{
 			name:      "certificate with KeyAlgorithm set to unknown key algo",
 			keyAlgo:   cmapi.PrivateKeyAlgorithm("blah"),
 			expectErr: true,
 		},
+		{
+			name:            "certificate with KeyAlgorithm rsa and signature hash SHA1",
+			keyAlgo:         cmapi.RSAKeyAlgorithm,
+			signatureHash:   cmapi.SHA1SignatureHash,
+			expectedSigAlgo: x509.SHA1WithRSA,
+			expectedKeyType: x509.RSA,
+		},
+		{
+			name:            "certificate with KeyAlgorithm rsa, size 4096 and signature hash SHA256",
+			keyAlgo:         cmapi.RSAKeyAlgorithm,
+			keySize:         4096,
+			signatureHash:   cmapi.SHA256SignatureHash,
+			expectedSigAlgo: x509.SHA256WithRSA,
+			expectedKeyType: x509.RSA,
+		},
+		{
+			name:            "certificate with KeyAlgorithm ecdsa and signature hash SHA384",
+			keyAlgo:         cmapi.ECDSAKeyAlgorithm,
+			signatureHash:   cmapi.SHA384SignatureHash,
+			expectedSigAlgo: x509.ECDSAWithSHA384,
+			expectedKeyType: x509.ECDSA,
+		},
+		{
+			name:          "certificate with KeyAlgorithm ed25519 and a signature hash",
+			keyAlgo:       cmapi.Ed25519KeyAlgorithm,
+			signatureHash: cmapi.SHA256SignatureHash,
+			expectErr:     true,
+		},
+		{
+			name:          "certificate with unknown signature hash",
+			keyAlgo:       cmapi.RSAKeyAlgorithm,
+			signatureHash: cmapi.PrivateKeySignatureHash("MD5"),
+			expectErr:     true,
+		},
 	}
 
 	testFn := func(test testT) func(*testing.T) {
 		return func(t *testing.T) {
-			actualPKAlgo, actualSigAlgo, err := SignatureAlgorithm(buildCertificateWithKeyParams(test.keyAlgo, test.keySize))
+			crt := buildCertificateWithKeyParams(test.keyAlgo, test.keySize)
+			crt.Spec.PrivateKey.SignatureHash = test.signatureHash
+			actualPKAlgo, actualSigAlgo, err := SignatureAlgorithm(crt)
 			if test.expectErr && err == nil {
 				t.Error("expected err, but got no error")
 				return
@@ -340,6 +426,68 @@ func TestSignatureAlgorithmForCertificate(t *testing.T) {
 	}
 }
 
+func TestGenerateCSRSignatureHash(t *testing.T) {
+	rsaKey, err := GenerateRSAPrivateKey(2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := GenerateECPrivateKey(ECCurve256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		keyAlgo         cmapi.PrivateKeyAlgorithm
+		key             crypto.Signer
+		signatureHash   cmapi.PrivateKeySignatureHash
+		expectedSigAlgo x509.SignatureAlgorithm
+	}{
+		"RSA key with default hash": {
+			keyAlgo: cmapi.RSAKeyAlgorithm, key: rsaKey,
+			expectedSigAlgo: x509.SHA256WithRSA,
+		},
+		"RSA key with SHA1": {
+			keyAlgo: cmapi.RSAKeyAlgorithm, key: rsaKey, signatureHash: cmapi.SHA1SignatureHash,
+			expectedSigAlgo: x509.SHA1WithRSA,
+		},
+		"RSA key with SHA384": {
+			keyAlgo: cmapi.RSAKeyAlgorithm, key: rsaKey, signatureHash: cmapi.SHA384SignatureHash,
+			expectedSigAlgo: x509.SHA384WithRSA,
+		},
+		"ECDSA key with SHA512": {
+			keyAlgo: cmapi.ECDSAKeyAlgorithm, key: ecKey, signatureHash: cmapi.SHA512SignatureHash,
+			expectedSigAlgo: x509.ECDSAWithSHA512,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			crt := buildCertificateWithKeyParams(test.keyAlgo, 0)
+			crt.Spec.PrivateKey.SignatureHash = test.signatureHash
+
+			template, err := GenerateCSR(crt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			der, err := EncodeCSR(template, test.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			csr, err := x509.ParseCertificateRequest(der)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if csr.SignatureAlgorithm != test.expectedSigAlgo {
+				t.Errorf("expected CSR signature algorithm %s, got %s", test.expectedSigAlgo, csr.SignatureAlgorithm)
+			}
+			if err := csr.CheckSignature(); err != nil {
+				t.Errorf("CSR signature does not verify: %v", err)
+			}
+		})
+	}
+}
+
 func TestRemoveDuplicates(t *testing.T) {
 	type testT struct {
 		input  []string
@@ -401,6 +549,9 @@ func TestGenerateCSR(t *testing.T) {
 		},
 	}
 
+	fromDNSName := metav1.ObjectMeta{Annotations: map[string]string{cmapi.CommonNameFromDNSNameAnnotationKey: "true"}}
+	tooLongName := strings.Repeat("a", 60) + "." + strings.Repeat("b", 10) + ".example.org"
+
 	tests := []struct {
 		name    string
 		crt     *cmapi.Certificate
@@ -417,6 +568,16 @@ func TestGenerateCSR(t *testing.T) {
 				ExtraExtensions:    defaultExtraExtensions,
 			},
 		},
+		{
+			name: "Generate CSR from certificate with a Unicode DNS name",
+			crt:  &cmapi.Certificate{Spec: cmapi.CertificateSpec{DNSNames: []string{"例え.jp", "*.bücher.example"}}},
+			want: &x509.CertificateRequest{Version: 3,
+				SignatureAlgorithm: x509.SHA256WithRSA,
+				PublicKeyAlgorithm: x509.RSA,
+				DNSNames:           []string{"xn--r8jz45g.jp", "*.xn--bcher-kva.example"},
+				ExtraExtensions:    defaultExtraExtensions,
+			},
+		},
 		{
 			name: "Generate CSR from certificate with only CN",
 			crt:  &cmapi.Certificate{Spec: cmapi.CertificateSpec{CommonName: "example.org"}},
@@ -427,6 +588,16 @@ func TestGenerateCSR(t *testing.T) {
 				ExtraExtensions:    defaultExtraExtensions,
 			},
 		},
+		{
+			name: "Generate CSR from certificate with subject serialNumber",
+			crt:  &cmapi.Certificate{Spec: cmapi.CertificateSpec{CommonName: "example.org", Subject: &cmapi.X509Subject{SerialNumber: "DEVICE-0001"}}},
+			want: &x509.CertificateRequest{Version: 3,
+				SignatureAlgorithm: x509.SHA256WithRSA,
+				PublicKeyAlgorithm: x509.RSA,
+				Subject:            pkix.Name{CommonName: "example.org", SerialNumber: "DEVICE-0001"},
+				ExtraExtensions:    defaultExtraExtensions,
+			},
+		},
 		{
 			name: "Generate CSR from certificate with extended key usages",
 			crt:  &cmapi.Certificate{Spec: cmapi.CertificateSpec{CommonName: "example.org", Usages: []cmapi.KeyUsage{cmapi.UsageDigitalSignature, cmapi.UsageKeyEncipherment, cmapi.UsageIPsecEndSystem}}},
@@ -447,6 +618,57 @@ func TestGenerateCSR(t *testing.T) {
 				ExtraExtensions:    defaultExtraExtensions,
 			},
 		},
+		{
+			name: "Generate CSR from certificate with registeredIDs",
+			crt:  &cmapi.Certificate{Spec: cmapi.CertificateSpec{DNSNames: []string{"example.org"}, RegisteredIDs: []string{"1.2.3.4"}}},
+			want: &x509.CertificateRequest{Version: 3,
+				SignatureAlgorithm: x509.SHA256WithRSA,
+				PublicKeyAlgorithm: x509.RSA,
+				DNSNames:           []string{"example.org"},
+				ExtraExtensions: append(defaultExtraExtensions, pkix.Extension{
+					Id: OIDExtensionSubjectAltName,
+					// SEQUENCE { [2] "example.org", [8] 1.2.3.4 }
+					Value: append(append([]byte{0x30, 0x12, 0x82, 0x0b}, []byte("example.org")...), 0x88, 0x03, 0x2a, 0x03, 0x04),
+				}),
+			},
+		},
+		{
+			name: "Generate CSR with the CN derived from the first DNS name",
+			crt:  &cmapi.Certificate{ObjectMeta: fromDNSName, Spec: cmapi.CertificateSpec{DNSNames: []string{"example.org", "www.example.org"}}},
+			want: &x509.CertificateRequest{Version: 3,
+				SignatureAlgorithm: x509.SHA256WithRSA,
+				PublicKeyAlgorithm: x509.RSA,
+				Subject:            pkix.Name{CommonName: "example.org"},
+				DNSNames:           []string{"example.org", "www.example.org"},
+				ExtraExtensions:    defaultExtraExtensions,
+			},
+		},
+		{
+			name: "Generate CSR with the CN derived from the ASCII form of the first DNS name",
+			crt:  &cmapi.Certificate{ObjectMeta: fromDNSName, Spec: cmapi.CertificateSpec{DNSNames: []string{"例え.jp"}}},
+			want: &x509.CertificateRequest{Version: 3,
+				SignatureAlgorithm: x509.SHA256WithRSA,
+				PublicKeyAlgorithm: x509.RSA,
+				Subject:            pkix.Name{CommonName: "xn--r8jz45g.jp"},
+				DNSNames:           []string{"xn--r8jz45g.jp"},
+				ExtraExtensions:    defaultExtraExtensions,
+			},
+		},
+		{
+			name: "Generate CSR with an empty CN if the first DNS name is too long to be derived",
+			crt:  &cmapi.Certificate{ObjectMeta: fromDNSName, Spec: cmapi.CertificateSpec{DNSNames: []string{tooLongName, "example.org"}}},
+			want: &x509.CertificateRequest{Version: 3,
+				SignatureAlgorithm: x509.SHA256WithRSA,
+				PublicKeyAlgorithm: x509.RSA,
+				DNSNames:           []string{tooLongName, "example.org"},
+				ExtraExtensions:    defaultExtraExtensions,
+			},
+		},
+		{
+			name:    "Error on generating CSR from certificate with an invalid registeredID",
+			crt:     &cmapi.Certificate{Spec: cmapi.CertificateSpec{CommonName: "example.org", RegisteredIDs: []string{"not-an-oid"}}},
+			wantErr: true,
+		},
 		{
 			name:    "Error on generating CSR from certificate with no subject",
 			crt:     &cmapi.Certificate{Spec: cmapi.CertificateSpec{}},
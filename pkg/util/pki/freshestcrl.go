@@ -0,0 +1,96 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+)
+
+// OIDExtensionFreshestCRL is the OID of the FreshestCRL extension, also
+// known as the Delta CRL Distribution Point, as defined in RFC 5280, 4.2.1.15.
+var OIDExtensionFreshestCRL = asn1.ObjectIdentifier{2, 5, 29, 46}
+
+// distributionPoint and distributionPointName are copied from x509.go. The
+// FreshestCRL extension has the same syntax as the CRLDistributionPoints
+// extension.
+type distributionPoint struct {
+	DistributionPoint distributionPointName `asn1:"optional,tag:0"`
+	Reason            asn1.BitString        `asn1:"optional,tag:1"`
+	CRLIssuer         asn1.RawValue         `asn1:"optional,tag:2"`
+}
+
+type distributionPointName struct {
+	FullName     []asn1.RawValue  `asn1:"optional,tag:0"`
+	RelativeName pkix.RDNSequence `asn1:"optional,tag:1"`
+}
+
+// uniformResourceIdentifier is the tag of the uniformResourceIdentifier
+// GeneralName, as defined in RFC 5280, 4.2.1.6.
+const uniformResourceIdentifier = 6
+
+// MarshalFreshestCRL returns a FreshestCRL extension with a distribution
+// point for each of the given URLs. Following RFC 5280, the extension is
+// marked as non-critical.
+func MarshalFreshestCRL(urls []string) (pkix.Extension, error) {
+	var points []distributionPoint
+	for _, url := range urls {
+		points = append(points, distributionPoint{
+			DistributionPoint: distributionPointName{
+				FullName: []asn1.RawValue{
+					{Tag: uniformResourceIdentifier, Class: asn1.ClassContextSpecific, Bytes: []byte(url)},
+				},
+			},
+		})
+	}
+	value, err := asn1.Marshal(points)
+	if err != nil {
+		return pkix.Extension{}, err
+	}
+	return pkix.Extension{Id: OIDExtensionFreshestCRL, Value: value}, nil
+}
+
+// FreshestCRLDistributionPoints returns the URLs of the distribution points
+// in the FreshestCRL extension of the given certificate, or nil if the
+// certificate does not have the extension.
+func FreshestCRLDistributionPoints(cert *x509.Certificate) ([]string, error) {
+	for _, extension := range cert.Extensions {
+		if !extension.Id.Equal(OIDExtensionFreshestCRL) {
+			continue
+		}
+		var points []distributionPoint
+		rest, err := asn1.Unmarshal(extension.Value, &points)
+		if err != nil {
+			return nil, fmt.Errorf("failed to asn1 decode freshest CRL: %w", err)
+		}
+		if len(rest) != 0 {
+			return nil, fmt.Errorf("trailing data after asn1 encoded freshest CRL")
+		}
+		var urls []string
+		for _, point := range points {
+			for _, name := range point.DistributionPoint.FullName {
+				if name.Class == asn1.ClassContextSpecific && name.Tag == uniformResourceIdentifier {
+					urls = append(urls, string(name.Bytes))
+				}
+			}
+		}
+		return urls, nil
+	}
+	return nil, nil
+}
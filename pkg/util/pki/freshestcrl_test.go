@@ -0,0 +1,106 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFreshestCRLDistributionPoints(t *testing.T) {
+	pk, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oidExtensionCRLDistributionPoints := asn1.ObjectIdentifier{2, 5, 29, 31}
+
+	tests := map[string]struct {
+		urls []string
+	}{
+		"certificate without the extension": {},
+		"single distribution point": {
+			urls: []string{"http://crl.example.com/delta.crl"},
+		},
+		"multiple distribution points": {
+			urls: []string{"http://crl.example.com/delta.crl", "ldap://ldap.example.com/cn=delta"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			template := &x509.Certificate{
+				SerialNumber:          big.NewInt(1),
+				Subject:               pkix.Name{CommonName: "example.com"},
+				NotBefore:             time.Now(),
+				NotAfter:              time.Now().Add(time.Hour),
+				CRLDistributionPoints: test.urls,
+			}
+			if len(test.urls) > 0 {
+				extension, err := MarshalFreshestCRL(test.urls)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if extension.Critical {
+					t.Errorf("expected the FreshestCRL extension to be non-critical")
+				}
+				template.ExtraExtensions = []pkix.Extension{extension}
+			}
+
+			der, err := x509.CreateCertificate(rand.Reader, template, template, pk.Public(), pk)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cert, err := x509.ParseCertificate(der)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			urls, err := FreshestCRLDistributionPoints(cert)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(urls, test.urls) {
+				t.Errorf("FreshestCRLDistributionPoints() = %v, want %v", urls, test.urls)
+			}
+
+			// the FreshestCRL extension has the same syntax as the
+			// CRLDistributionPoints extension encoded by the x509 package
+			var crldp, freshest []byte
+			for _, extension := range cert.Extensions {
+				switch {
+				case extension.Id.Equal(oidExtensionCRLDistributionPoints):
+					crldp = extension.Value
+				case extension.Id.Equal(OIDExtensionFreshestCRL):
+					freshest = extension.Value
+				}
+			}
+			if !bytes.Equal(crldp, freshest) {
+				t.Errorf("expected the FreshestCRL extension to be encoded the same as the CRLDistributionPoints extension")
+			}
+		})
+	}
+}
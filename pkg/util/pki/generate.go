@@ -19,6 +19,7 @@ package pki
 import (
 	"crypto"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
@@ -49,7 +50,7 @@ const (
 // GeneratePrivateKeyForCertificate will generate a private key suitable for
 // the provided cert-manager Certificate resource, taking into account the
 // parameters on the provided resource.
-// The returned key will either be RSA or ECDSA.
+// The returned key will either be RSA, ECDSA or Ed25519.
 func GeneratePrivateKeyForCertificate(crt *v1.Certificate) (crypto.Signer, error) {
 	crt = crt.DeepCopy()
 	if crt.Spec.PrivateKey == nil {
@@ -72,6 +73,8 @@ func GeneratePrivateKeyForCertificate(crt *v1.Certificate) (crypto.Signer, error
 		}
 
 		return GenerateECPrivateKey(keySize)
+	case v1.Ed25519KeyAlgorithm:
+		return GenerateEd25519PrivateKey()
 	default:
 		return nil, fmt.Errorf("unsupported private key algorithm specified: %s", crt.Spec.PrivateKey.Algorithm)
 	}
@@ -111,9 +114,17 @@ func GenerateECPrivateKey(keySize int) (*ecdsa.PrivateKey, error) {
 	return ecdsa.GenerateKey(ecCurve, rand.Reader)
 }
 
+// GenerateEd25519PrivateKey will generate an Ed25519 private key.
+func GenerateEd25519PrivateKey() (ed25519.PrivateKey, error) {
+	_, pk, err := ed25519.GenerateKey(rand.Reader)
+	return pk, err
+}
+
 // EncodePrivateKey will encode a given crypto.PrivateKey by first inspecting
 // the type of key encoding and then inspecting the type of key provided.
-// It only supports encoding RSA or ECDSA keys.
+// It only supports encoding RSA, ECDSA or Ed25519 keys.
+// PKCS#1 only defines an encoding for RSA keys, so an error is returned if
+// PKCS1 is explicitly requested for any other type of key.
 func EncodePrivateKey(pk crypto.PrivateKey, keyEncoding v1.PrivateKeyEncoding) ([]byte, error) {
 	switch keyEncoding {
 	case v1.PrivateKeyEncoding(""), v1.PKCS1:
@@ -121,7 +132,17 @@ func EncodePrivateKey(pk crypto.PrivateKey, keyEncoding v1.PrivateKeyEncoding) (
 		case *rsa.PrivateKey:
 			return EncodePKCS1PrivateKey(k), nil
 		case *ecdsa.PrivateKey:
+			if keyEncoding == v1.PKCS1 {
+				return nil, fmt.Errorf("error encoding private key: ecdsa keys cannot be encoded as %s, use %s", keyEncoding, v1.PKCS8)
+			}
 			return EncodeECPrivateKey(k)
+		case ed25519.PrivateKey:
+			// Ed25519 keys have no PKCS#1 form, so they are always PKCS#8
+			// encoded when no encoding is specified.
+			if keyEncoding == v1.PKCS1 {
+				return nil, fmt.Errorf("error encoding private key: ed25519 keys cannot be encoded as %s, use %s", keyEncoding, v1.PKCS8)
+			}
+			return EncodePKCS8PrivateKey(k)
 		default:
 			return nil, fmt.Errorf("error encoding private key: unknown key type: %T", pk)
 		}
@@ -162,13 +183,15 @@ func EncodeECPrivateKey(pk *ecdsa.PrivateKey) ([]byte, error) {
 }
 
 // PublicKeyForPrivateKey will return the crypto.PublicKey for the given
-// crypto.PrivateKey. It only supports RSA and ECDSA keys.
+// crypto.PrivateKey. It only supports RSA, ECDSA and Ed25519 keys.
 func PublicKeyForPrivateKey(pk crypto.PrivateKey) (crypto.PublicKey, error) {
 	switch k := pk.(type) {
 	case *rsa.PrivateKey:
 		return k.Public(), nil
 	case *ecdsa.PrivateKey:
 		return k.Public(), nil
+	case ed25519.PrivateKey:
+		return k.Public(), nil
 	default:
 		return nil, fmt.Errorf("unknown private key type: %T", pk)
 	}
@@ -180,7 +203,7 @@ func PublicKeyForPrivateKey(pk crypto.PrivateKey) (crypto.PublicKey, error) {
 // given Certificate.
 // It will return true if the public key *is* valid for the given Certificate.
 // It will return an error if either of the passed parameters are of an
-// unrecognised type (i.e. non RSA/ECDSA)
+// unrecognised type (i.e. non RSA/ECDSA/Ed25519)
 func PublicKeyMatchesCertificate(check crypto.PublicKey, crt *x509.Certificate) (bool, error) {
 	switch pub := crt.PublicKey.(type) {
 	case *rsa.PublicKey:
@@ -201,6 +224,12 @@ func PublicKeyMatchesCertificate(check crypto.PublicKey, crt *x509.Certificate)
 			return false, nil
 		}
 		return true, nil
+	case ed25519.PublicKey:
+		ed25519Check, ok := check.(ed25519.PublicKey)
+		if !ok {
+			return false, nil
+		}
+		return pub.Equal(ed25519Check), nil
 	default:
 		return false, fmt.Errorf("unrecognised Certificate public key type")
 	}
@@ -212,7 +241,7 @@ func PublicKeyMatchesCertificate(check crypto.PublicKey, crt *x509.Certificate)
 // given CertificateRequest.
 // It will return true if the public key *is* valid for the given CertificateRequest.
 // It will return an error if either of the passed parameters are of an
-// unrecognised type (i.e. non RSA/ECDSA)
+// unrecognised type (i.e. non RSA/ECDSA/Ed25519)
 func PublicKeyMatchesCSR(check crypto.PublicKey, csr *x509.CertificateRequest) (bool, error) {
 	return PublicKeysEqual(check, csr.PublicKey)
 }
@@ -237,6 +266,12 @@ func PublicKeysEqual(a, b crypto.PublicKey) (bool, error) {
 			return false, nil
 		}
 		return true, nil
+	case ed25519.PublicKey:
+		ed25519Check, ok := b.(ed25519.PublicKey)
+		if !ok {
+			return false, nil
+		}
+		return pub.Equal(ed25519Check), nil
 	default:
 		return false, fmt.Errorf("unrecognised public key type")
 	}
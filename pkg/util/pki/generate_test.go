@@ -19,6 +19,7 @@ package pki
 import (
 	"crypto"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
@@ -143,6 +144,11 @@ func TestGeneratePrivateKeyForCertificate(t *testing.T) {
 			keyAlgo:   v1.ECDSAKeyAlgorithm,
 			expectErr: false,
 		},
+		{
+			name:      "ed25519 with keysize not specified",
+			keyAlgo:   v1.Ed25519KeyAlgorithm,
+			expectErr: false,
+		},
 	}
 
 	testFn := func(test testT) func(*testing.T) {
@@ -216,6 +222,13 @@ func TestGeneratePrivateKeyForCertificate(t *testing.T) {
 						return
 					}
 				}
+
+				if test.keyAlgo == v1.Ed25519KeyAlgorithm {
+					if _, ok := privateKey.(ed25519.PrivateKey); !ok {
+						t.Errorf("expected ed25519 private key, but got %T", privateKey)
+						return
+					}
+				}
 			}
 		}
 	}
@@ -339,6 +352,170 @@ func TestPublicKeyMatchesCertificateRequest(t *testing.T) {
 	}
 }
 
+func TestEd25519SelfSignedCertificate(t *testing.T) {
+	crt := buildCertificateWithKeyParams(v1.Ed25519KeyAlgorithm, 0)
+
+	privateKey, err := GeneratePrivateKeyForCertificate(crt)
+	if err != nil {
+		t.Fatalf("error generating private key: %v", err)
+	}
+
+	csrTemplate, err := GenerateCSR(crt)
+	if err != nil {
+		t.Fatalf("error generating csr template: %v", err)
+	}
+	csrDER, err := EncodeCSR(csrTemplate, privateKey)
+	if err != nil {
+		t.Fatalf("error encoding csr: %v", err)
+	}
+	csr, err := x509.ParseCertificateRequest(csrDER)
+	if err != nil {
+		t.Fatalf("error parsing csr: %v", err)
+	}
+	if err := csr.CheckSignature(); err != nil {
+		t.Errorf("expected csr signature to be valid, but got: %v", err)
+	}
+
+	matches, err := PublicKeyMatchesCSR(privateKey.Public(), csr)
+	if err != nil {
+		t.Errorf("expected no error, but got: %v", err)
+	}
+	if !matches {
+		t.Errorf("expected private key to match csr, but it did not")
+	}
+
+	template, err := GenerateTemplate(crt)
+	if err != nil {
+		t.Fatalf("error generating certificate template: %v", err)
+	}
+	_, cert, err := SignCertificate(template, template, privateKey.Public(), privateKey)
+	if err != nil {
+		t.Fatalf("error signing certificate: %v", err)
+	}
+	if err := cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature); err != nil {
+		t.Errorf("expected self-signed certificate signature to be valid, but got: %v", err)
+	}
+
+	matches, err = PublicKeyMatchesCertificate(privateKey.Public(), cert)
+	if err != nil {
+		t.Errorf("expected no error, but got: %v", err)
+	}
+	if !matches {
+		t.Errorf("expected private key to match certificate, but it did not")
+	}
+
+	keyPEM, err := EncodePrivateKey(privateKey, v1.PrivateKeyEncoding(""))
+	if err != nil {
+		t.Fatalf("error encoding private key: %v", err)
+	}
+	decodedKey, err := DecodePrivateKeyBytes(keyPEM)
+	if err != nil {
+		t.Fatalf("error decoding private key: %v", err)
+	}
+	if _, ok := decodedKey.(ed25519.PrivateKey); !ok {
+		t.Errorf("expected ed25519 private key, but got %T", decodedKey)
+	}
+
+	if _, err := EncodePrivateKey(privateKey, v1.PKCS1); err == nil {
+		t.Errorf("expected error encoding ed25519 private key as PKCS1, but got none")
+	}
+}
+
+// TestSubjectSerialNumberSelfSignedCertificate checks that the subject
+// serialNumber of a Certificate is carried through the CSR into a
+// certificate signed from it, as done by the SelfSigned and CA issuers.
+func TestSubjectSerialNumberSelfSignedCertificate(t *testing.T) {
+	crt := buildCertificateWithKeyParams(v1.ECDSAKeyAlgorithm, 256)
+	crt.Spec.Subject = &v1.X509Subject{SerialNumber: "DEVICE-0001"}
+
+	privateKey, err := GeneratePrivateKeyForCertificate(crt)
+	if err != nil {
+		t.Fatalf("error generating private key: %v", err)
+	}
+
+	csrTemplate, err := GenerateCSR(crt)
+	if err != nil {
+		t.Fatalf("error generating csr template: %v", err)
+	}
+	csrDER, err := EncodeCSR(csrTemplate, privateKey)
+	if err != nil {
+		t.Fatalf("error encoding csr: %v", err)
+	}
+	csrPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER})
+
+	template, err := GenerateTemplateFromCSRPEM(csrPEM, time.Hour, false)
+	if err != nil {
+		t.Fatalf("error generating certificate template: %v", err)
+	}
+	_, cert, err := SignCertificate(template, template, privateKey.Public(), privateKey)
+	if err != nil {
+		t.Fatalf("error signing certificate: %v", err)
+	}
+
+	// decode the subject RDNs of the issued certificate rather than relying
+	// on the parsed pkix.Name, to check the attribute is actually encoded
+	var serialNumbers []interface{}
+	for _, attr := range cert.Subject.Names {
+		// id-at-serialNumber
+		if attr.Type.String() == "2.5.4.5" {
+			serialNumbers = append(serialNumbers, attr.Value)
+		}
+	}
+	if len(serialNumbers) != 1 || serialNumbers[0] != "DEVICE-0001" {
+		t.Errorf("expected a single serialNumber RDN with value %q, got %v", "DEVICE-0001", serialNumbers)
+	}
+	if cert.Subject.CommonName != "test" {
+		t.Errorf("expected commonName %q, got %q", "test", cert.Subject.CommonName)
+	}
+}
+
+func TestEncodeECDSAPrivateKey(t *testing.T) {
+	privateKey, err := GenerateECPrivateKey(ECCurve256)
+	if err != nil {
+		t.Fatalf("error generating private key: %v", err)
+	}
+
+	tests := map[string]struct {
+		keyEncoding   v1.PrivateKeyEncoding
+		expectedType  string
+		expectedError bool
+	}{
+		"empty key encoding": {
+			keyEncoding:  v1.PrivateKeyEncoding(""),
+			expectedType: "EC PRIVATE KEY",
+		},
+		"pkcs8 key encoding": {
+			keyEncoding:  v1.PKCS8,
+			expectedType: "PRIVATE KEY",
+		},
+		"pkcs1 key encoding": {
+			keyEncoding:   v1.PKCS1,
+			expectedError: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			keyPEM, err := EncodePrivateKey(privateKey, test.keyEncoding)
+			if test.expectedError {
+				if err == nil {
+					t.Errorf("expected error encoding ecdsa private key as %s, but got none", test.keyEncoding)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error encoding private key: %v", err)
+			}
+			block, _ := pem.Decode(keyPEM)
+			if block == nil {
+				t.Fatalf("failed to decode PEM encoded private key")
+			}
+			if block.Type != test.expectedType {
+				t.Errorf("expected PEM block type %q, but got %q", test.expectedType, block.Type)
+			}
+		})
+	}
+}
+
 func TestPrivateKeyEncodings(t *testing.T) {
 	type testT struct {
 		name         string
@@ -0,0 +1,76 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/idna"
+)
+
+// DNSNameToASCII converts a DNS name that contains Unicode characters into
+// its ASCII (A-label, or punycode) form, e.g. "例え.jp" becomes
+// "xn--r8jz45g.jp", which is the form required in X.509 certificates and ACME
+// identifiers. Names that are already ASCII are returned unchanged. A leading
+// wildcard label is preserved.
+func DNSNameToASCII(name string) (string, error) {
+	if isASCII(name) {
+		return name, nil
+	}
+
+	prefix := ""
+	if strings.HasPrefix(name, "*.") {
+		prefix, name = "*.", strings.TrimPrefix(name, "*.")
+	}
+
+	ascii, err := idna.Lookup.ToASCII(name)
+	if err != nil {
+		return "", fmt.Errorf("failed to convert DNS name %q to ASCII: %w", prefix+name, err)
+	}
+
+	return prefix + ascii, nil
+}
+
+// DNSNamesToASCII converts each of the given DNS names into ASCII form using
+// DNSNameToASCII.
+func DNSNamesToASCII(names []string) ([]string, error) {
+	if names == nil {
+		return nil, nil
+	}
+
+	out := make([]string, len(names))
+	for i, name := range names {
+		ascii, err := DNSNameToASCII(name)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = ascii
+	}
+
+	return out, nil
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import "testing"
+
+func TestDNSNameToASCII(t *testing.T) {
+	tests := map[string]struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		"ASCII name is unchanged": {
+			name: "example.com",
+			want: "example.com",
+		},
+		"ASCII names are not normalised": {
+			name: "_acme.Example.com",
+			want: "_acme.Example.com",
+		},
+		"Unicode name is converted to punycode": {
+			name: "例え.jp",
+			want: "xn--r8jz45g.jp",
+		},
+		"Unicode name is lower cased": {
+			name: "BÜCHER.example",
+			want: "xn--bcher-kva.example",
+		},
+		"wildcard label is preserved": {
+			name: "*.例え.jp",
+			want: "*.xn--r8jz45g.jp",
+		},
+		"Unicode name with invalid characters": {
+			name:    "例え_.jp",
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := DNSNameToASCII(test.name)
+			if test.wantErr != (err != nil) {
+				t.Fatalf("expected error=%t but got: %v", test.wantErr, err)
+			}
+			if got != test.want {
+				t.Errorf("expected %q but got %q", test.want, got)
+			}
+		})
+	}
+}
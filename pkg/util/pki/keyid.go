@@ -0,0 +1,67 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"crypto"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+// subjectKeyIdentifierLength is the length in bytes of key identifiers
+// derived using RFC 7093 method 1, which matches the length of a SHA-1 hash.
+const subjectKeyIdentifierLength = 20
+
+// subjectPublicKeyInfo is the ASN.1 structure of a SubjectPublicKeyInfo as
+// described in RFC 5280, 4.1.
+type subjectPublicKeyInfo struct {
+	Algorithm        pkix.AlgorithmIdentifier
+	SubjectPublicKey asn1.BitString
+}
+
+// SubjectKeyIdentifier derives the Subject Key Identifier of the given public
+// key using the given method. Both methods hash the value of the
+// subjectPublicKey BIT STRING, excluding the tag, length and number of unused
+// bits.
+func SubjectKeyIdentifier(pub crypto.PublicKey, method cmapi.SubjectKeyIdentifierMethod) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal public key: %w", err)
+	}
+
+	var spki subjectPublicKeyInfo
+	if _, err := asn1.Unmarshal(der, &spki); err != nil {
+		return nil, fmt.Errorf("failed to decode subject public key info: %w", err)
+	}
+
+	switch method {
+	case cmapi.SubjectKeyIdentifierMethodRFC5280:
+		sum := sha1.Sum(spki.SubjectPublicKey.Bytes)
+		return sum[:], nil
+	case cmapi.SubjectKeyIdentifierMethodRFC7093Method1:
+		sum := sha256.Sum256(spki.SubjectPublicKey.Bytes)
+		return sum[:subjectKeyIdentifierLength], nil
+	default:
+		return nil, fmt.Errorf("unsupported subject key identifier method %q", method)
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"testing"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+func TestSubjectKeyIdentifier(t *testing.T) {
+	rsaKey, err := GenerateRSAPrivateKey(2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := GenerateECPrivateKey(256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edKey, err := GenerateEd25519PrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := map[string]crypto.Signer{
+		"RSA":     rsaKey,
+		"ECDSA":   ecKey,
+		"Ed25519": edKey,
+	}
+
+	for name, key := range keys {
+		t.Run(name, func(t *testing.T) {
+			template := &x509.Certificate{
+				SerialNumber: big.NewInt(1),
+				Subject:      pkix.Name{CommonName: "ca"},
+				IsCA:         true,
+			}
+			der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cert, err := x509.ParseCertificate(der)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var spki struct {
+				Algorithm        pkix.AlgorithmIdentifier
+				SubjectPublicKey asn1.BitString
+			}
+			if _, err := asn1.Unmarshal(cert.RawSubjectPublicKeyInfo, &spki); err != nil {
+				t.Fatal(err)
+			}
+			sha1Sum := sha1.Sum(spki.SubjectPublicKey.Bytes)
+			sha256Sum := sha256.Sum256(spki.SubjectPublicKey.Bytes)
+
+			got, err := SubjectKeyIdentifier(key.Public(), cmapi.SubjectKeyIdentifierMethodRFC5280)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, sha1Sum[:]) {
+				t.Errorf("unexpected RFC5280 key identifier, exp=%x, got=%x", sha1Sum[:], got)
+			}
+
+			got, err = SubjectKeyIdentifier(key.Public(), cmapi.SubjectKeyIdentifierMethodRFC7093Method1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, sha256Sum[:20]) {
+				t.Errorf("unexpected RFC7093Method1 key identifier, exp=%x, got=%x", sha256Sum[:20], got)
+			}
+		})
+	}
+
+	if _, err := SubjectKeyIdentifier(rsaKey.Public(), "SHA512"); err == nil {
+		t.Errorf("expected an error for an unsupported method")
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/asn1"
+	"fmt"
 )
 
 // Copied from x509.go
@@ -148,3 +149,39 @@ func buildASN1KeyUsageRequest(usage x509.KeyUsage) (pkix.Extension, error) {
 
 	return OIDExtensionKeyUsage, nil
 }
+
+// KeyUsagesFromCSR returns the key usages and extended key usages requested
+// by the key usage and extended key usage extensions of the given CSR.
+// Extended key usages with an unknown OID are ignored.
+func KeyUsagesFromCSR(csr *x509.CertificateRequest) (ku x509.KeyUsage, ekus []x509.ExtKeyUsage, err error) {
+	for _, extension := range csr.Extensions {
+		switch {
+		case extension.Id.Equal(OIDExtensionExtendedKeyUsage):
+			var asn1ExtendedUsages []asn1.ObjectIdentifier
+			if _, err := asn1.Unmarshal(extension.Value, &asn1ExtendedUsages); err != nil {
+				return 0, nil, fmt.Errorf("failed to decode csr extended usages: %s", err)
+			}
+			for _, asnExtUsage := range asn1ExtendedUsages {
+				if eku, ok := ExtKeyUsageFromOID(asnExtUsage); ok {
+					ekus = append(ekus, eku)
+				}
+			}
+
+		case extension.Id.Equal(OIDExtensionKeyUsage):
+			// RFC 5280, 4.2.1.3
+			var asn1bits asn1.BitString
+			if _, err := asn1.Unmarshal(extension.Value, &asn1bits); err != nil {
+				return 0, nil, fmt.Errorf("failed to decode csr usages: %s", err)
+			}
+			var usage int
+			for i := 0; i < 9; i++ {
+				if asn1bits.At(i) != 0 {
+					usage |= 1 << uint(i)
+				}
+			}
+			ku = x509.KeyUsage(usage)
+		}
+	}
+
+	return ku, ekus, nil
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Copied from x509.go
+var OIDExtensionSubjectAltName = asn1.ObjectIdentifier{2, 5, 29, 17}
+
+// GeneralName CHOICE tags, as defined in RFC 5280, 4.2.1.6
+const (
+	nameTypeEmail        = 1
+	nameTypeDNS          = 2
+	nameTypeURI          = 6
+	nameTypeIP           = 7
+	nameTypeRegisteredID = 8
+)
+
+// ParseObjectIdentifier parses an object identifier in dotted-decimal
+// notation, for example 1.2.3.4.
+func ParseObjectIdentifier(s string) (asn1.ObjectIdentifier, error) {
+	parts := strings.Split(s, ".")
+	oid := make(asn1.ObjectIdentifier, len(parts))
+	for i, part := range parts {
+		arc, err := strconv.Atoi(part)
+		if err != nil || arc < 0 {
+			return nil, fmt.Errorf("invalid object identifier %q: arc %q is not a non-negative integer", s, part)
+		}
+		oid[i] = arc
+	}
+
+	// asn1 refuses to encode identifiers that are not valid, so use it to
+	// check the remaining constraints on the first two arcs
+	if _, err := asn1.Marshal(oid); err != nil {
+		return nil, fmt.Errorf("invalid object identifier %q: %s", s, err)
+	}
+
+	return oid, nil
+}
+
+// MarshalSANs returns a subjectAltName extension containing the given names.
+// It is equivalent to the extension generated by the x509 package, with the
+// addition of registeredID names which the x509 package does not support.
+func MarshalSANs(dnsNames, emailAddresses []string, ipAddresses []net.IP, uris []*url.URL, registeredIDs []asn1.ObjectIdentifier) (pkix.Extension, error) {
+	var rawValues []asn1.RawValue
+	for _, name := range dnsNames {
+		rawValues = append(rawValues, asn1.RawValue{Tag: nameTypeDNS, Class: asn1.ClassContextSpecific, Bytes: []byte(name)})
+	}
+	for _, email := range emailAddresses {
+		// Email addresses are encoded as rfc822Name, an IA5String, which
+		// cannot hold the internationalized mailboxes of RFC 8398.
+		if !isIA5String(email) {
+			return pkix.Extension{}, fmt.Errorf("email address %q cannot be encoded as an rfc822Name: it contains non-ASCII characters", email)
+		}
+		rawValues = append(rawValues, asn1.RawValue{Tag: nameTypeEmail, Class: asn1.ClassContextSpecific, Bytes: []byte(email)})
+	}
+	for _, rawIP := range ipAddresses {
+		// If possible, we always want to encode IPv4 addresses in 4 bytes.
+		ip := rawIP.To4()
+		if ip == nil {
+			ip = rawIP
+		}
+		rawValues = append(rawValues, asn1.RawValue{Tag: nameTypeIP, Class: asn1.ClassContextSpecific, Bytes: ip})
+	}
+	for _, uri := range uris {
+		rawValues = append(rawValues, asn1.RawValue{Tag: nameTypeURI, Class: asn1.ClassContextSpecific, Bytes: []byte(uri.String())})
+	}
+	for _, oid := range registeredIDs {
+		// registeredID is implicitly tagged, so only the contents of the
+		// encoded object identifier are used
+		der, err := asn1.Marshal(oid)
+		if err != nil {
+			return pkix.Extension{}, fmt.Errorf("failed to encode registeredID %s: %s", oid, err)
+		}
+		var encoded asn1.RawValue
+		if _, err := asn1.Unmarshal(der, &encoded); err != nil {
+			return pkix.Extension{}, fmt.Errorf("failed to encode registeredID %s: %s", oid, err)
+		}
+		rawValues = append(rawValues, asn1.RawValue{Tag: nameTypeRegisteredID, Class: asn1.ClassContextSpecific, Bytes: encoded.Bytes})
+	}
+
+	value, err := asn1.Marshal(rawValues)
+	if err != nil {
+		return pkix.Extension{}, err
+	}
+
+	return pkix.Extension{
+		Id:    OIDExtensionSubjectAltName,
+		Value: value,
+	}, nil
+}
+
+// isIA5String returns true if s only contains characters of the IA5 (ASCII)
+// character set.
+func isIA5String(s string) bool {
+	for _, r := range s {
+		if r > unicode.MaxASCII {
+			return false
+		}
+	}
+	return true
+}
+
+// RegisteredIDsFromExtensions returns the registeredID names contained in
+// the subjectAltName extension of the given extensions, if any.
+func RegisteredIDsFromExtensions(extensions []pkix.Extension) ([]asn1.ObjectIdentifier, error) {
+	var registeredIDs []asn1.ObjectIdentifier
+	for _, extension := range extensions {
+		if !extension.Id.Equal(OIDExtensionSubjectAltName) {
+			continue
+		}
+
+		var seq asn1.RawValue
+		rest, err := asn1.Unmarshal(extension.Value, &seq)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode subjectAltName extension: %s", err)
+		}
+		if len(rest) != 0 {
+			return nil, errors.New("trailing data after subjectAltName extension")
+		}
+		if !seq.IsCompound || seq.Tag != asn1.TagSequence || seq.Class != asn1.ClassUniversal {
+			return nil, errors.New("subjectAltName extension is not a sequence")
+		}
+
+		rest = seq.Bytes
+		for len(rest) > 0 {
+			var v asn1.RawValue
+			rest, err = asn1.Unmarshal(rest, &v)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode subjectAltName: %s", err)
+			}
+			if v.Class != asn1.ClassContextSpecific || v.Tag != nameTypeRegisteredID {
+				continue
+			}
+
+			// re-add the universal object identifier tag that is replaced
+			// by the implicit registeredID tag so the value can be decoded
+			der, err := asn1.Marshal(asn1.RawValue{Tag: asn1.TagOID, Class: asn1.ClassUniversal, Bytes: v.Bytes})
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode registeredID: %s", err)
+			}
+			var oid asn1.ObjectIdentifier
+			if _, err := asn1.Unmarshal(der, &oid); err != nil {
+				return nil, fmt.Errorf("failed to decode registeredID: %s", err)
+			}
+			registeredIDs = append(registeredIDs, oid)
+		}
+	}
+
+	return registeredIDs, nil
+}
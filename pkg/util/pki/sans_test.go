@@ -0,0 +1,213 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"bytes"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"net"
+	"reflect"
+	"testing"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+func TestParseObjectIdentifier(t *testing.T) {
+	tests := map[string]struct {
+		oid     string
+		want    asn1.ObjectIdentifier
+		wantErr bool
+	}{
+		"simple identifier":              {oid: "1.2.3.4", want: asn1.ObjectIdentifier{1, 2, 3, 4}},
+		"large arcs":                     {oid: "2.999.1234567", want: asn1.ObjectIdentifier{2, 999, 1234567}},
+		"single arc":                     {oid: "1", wantErr: true},
+		"first arc greater than 2":       {oid: "3.1", wantErr: true},
+		"second arc too large for arc 1": {oid: "1.40", wantErr: true},
+		"non-numeric arc":                {oid: "1.2.a", wantErr: true},
+		"negative arc":                   {oid: "1.-2", wantErr: true},
+		"empty arc":                      {oid: "1..2", wantErr: true},
+		"empty string":                   {oid: "", wantErr: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseObjectIdentifier(test.oid)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("ParseObjectIdentifier() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if !test.want.Equal(got) {
+				t.Errorf("ParseObjectIdentifier() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRegisteredIDsEncodedInCSR(t *testing.T) {
+	pk, err := GenerateRSAPrivateKey(2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	crt := &cmapi.Certificate{Spec: cmapi.CertificateSpec{
+		CommonName:     "example.org",
+		DNSNames:       []string{"example.org"},
+		EmailAddresses: []string{"alice@example.org"},
+		IPAddresses:    []string{"10.0.0.1"},
+		URIs:           []string{"spiffe://example.org/device"},
+		RegisteredIDs:  []string{"1.2.3.4", "1.3.6.1.4.1.311.21.8"},
+	}}
+
+	template, err := GenerateCSR(crt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := EncodeCSR(template, pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	csr, err := x509.ParseCertificateRequest(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// all other names must still be decoded by the x509 package
+	if !reflect.DeepEqual(csr.DNSNames, crt.Spec.DNSNames) {
+		t.Errorf("unexpected DNS names: %v", csr.DNSNames)
+	}
+	if !reflect.DeepEqual(csr.EmailAddresses, crt.Spec.EmailAddresses) {
+		t.Errorf("unexpected email addresses: %v", csr.EmailAddresses)
+	}
+	if len(csr.IPAddresses) != 1 || !csr.IPAddresses[0].Equal(net.ParseIP("10.0.0.1")) {
+		t.Errorf("unexpected IP addresses: %v", csr.IPAddresses)
+	}
+	if got := URLsToString(csr.URIs); !reflect.DeepEqual(got, crt.Spec.URIs) {
+		t.Errorf("unexpected URIs: %v", got)
+	}
+
+	registeredIDs, err := RegisteredIDsFromExtensions(csr.Extensions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ObjectIdentifiersToString(registeredIDs); !reflect.DeepEqual(got, crt.Spec.RegisteredIDs) {
+		t.Errorf("unexpected registeredIDs: %v", got)
+	}
+
+	var sanExtensions int
+	for _, ext := range csr.Extensions {
+		if !ext.Id.Equal(OIDExtensionSubjectAltName) {
+			continue
+		}
+		sanExtensions++
+
+		// registeredID is an implicitly tagged OBJECT IDENTIFIER, so each
+		// entry is encoded as [8] followed by the identifier's contents
+		for _, want := range [][]byte{
+			{0x88, 0x03, 0x2a, 0x03, 0x04},
+			{0x88, 0x09, 0x2b, 0x06, 0x01, 0x04, 0x01, 0x82, 0x37, 0x15, 0x08},
+		} {
+			if !bytes.Contains(ext.Value, want) {
+				t.Errorf("expected subjectAltName extension to contain %x, got %x", want, ext.Value)
+			}
+		}
+	}
+	if sanExtensions != 1 {
+		t.Errorf("expected exactly one subjectAltName extension, got %d", sanExtensions)
+	}
+}
+
+func TestRegisteredIDsFromExtensionsWithoutRegisteredIDs(t *testing.T) {
+	ext, err := MarshalSANs([]string{"example.org"}, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	registeredIDs, err := RegisteredIDsFromExtensions([]pkix.Extension{ext})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(registeredIDs) != 0 {
+		t.Errorf("expected no registeredIDs, got %v", registeredIDs)
+	}
+}
+
+func TestEmailAddressesEncodedAsRFC822Names(t *testing.T) {
+	pk, err := GenerateRSAPrivateKey(2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	emails := []string{"alice@example.org", "bob+smime@mail.example.org"}
+	tests := map[string]struct {
+		registeredIDs []string
+	}{
+		// the subjectAltName extension is built by the x509 package
+		"without registeredIDs": {},
+		// the subjectAltName extension is built by MarshalSANs
+		"with registeredIDs": {registeredIDs: []string{"1.2.3.4"}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			crt := &cmapi.Certificate{Spec: cmapi.CertificateSpec{
+				EmailAddresses: emails,
+				RegisteredIDs:  test.registeredIDs,
+			}}
+
+			template, err := GenerateCSR(crt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			der, err := EncodeCSR(template, pk)
+			if err != nil {
+				t.Fatal(err)
+			}
+			csr, err := x509.ParseCertificateRequest(der)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var rfc822Names []string
+			for _, ext := range csr.Extensions {
+				if !ext.Id.Equal(OIDExtensionSubjectAltName) {
+					continue
+				}
+
+				var names []asn1.RawValue
+				if _, err := asn1.Unmarshal(ext.Value, &names); err != nil {
+					t.Fatal(err)
+				}
+				for _, name := range names {
+					if name.Class == asn1.ClassContextSpecific && name.Tag == nameTypeEmail {
+						rfc822Names = append(rfc822Names, string(name.Bytes))
+					}
+				}
+			}
+
+			if !reflect.DeepEqual(rfc822Names, emails) {
+				t.Errorf("expected rfc822Name SANs %v, got %v", emails, rfc822Names)
+			}
+		})
+	}
+}
+
+func TestMarshalSANsRejectsNonASCIIEmailAddresses(t *testing.T) {
+	if _, err := MarshalSANs(nil, []string{"álice@example.org"}, nil, nil, nil); err == nil {
+		t.Error("expected an error encoding an internationalized email address as an rfc822Name")
+	}
+}
@@ -16,5 +16,30 @@ limitations under the License.
 
 package util
 
+import (
+	"strings"
+	"unicode"
+)
+
 // CertManagerUserAgent is the user agent that http clients in this codebase should use
 var CertManagerUserAgent = "cert-manager/" + version()
+
+// FieldManagerFromUserAgent returns the field manager that the apiserver
+// records for writes that do not set one explicitly. The apiserver derives it
+// from the part of the request's user agent before the first '/', which for
+// clients configured with rest.AddUserAgent is the name of the binary.
+func FieldManagerFromUserAgent(userAgent string) string {
+	var b strings.Builder
+	for _, r := range strings.Split(userAgent, "/")[0] {
+		// the apiserver drops non-printable characters and truncates the
+		// field manager to 128 characters
+		if !unicode.IsPrint(r) {
+			continue
+		}
+		if b.Len() >= 128 {
+			break
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
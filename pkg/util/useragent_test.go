@@ -0,0 +1,53 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFieldManagerFromUserAgent(t *testing.T) {
+	tests := map[string]struct {
+		userAgent string
+		expected  string
+	}{
+		"user agent set with rest.AddUserAgent": {
+			userAgent: "controller/v0.0.0 (linux/amd64) kubernetes/$Format/cert-manager/v1.2.0",
+			expected:  "controller",
+		},
+		"user agent without a version": {
+			userAgent: "cert-manager",
+			expected:  "cert-manager",
+		},
+		"non-printable characters are dropped": {
+			userAgent: "cert\x00-manager/v1.2.0",
+			expected:  "cert-manager",
+		},
+		"long user agents are truncated": {
+			userAgent: strings.Repeat("a", 200) + "/v1.2.0",
+			expected:  strings.Repeat("a", 128),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := FieldManagerFromUserAgent(test.userAgent); got != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"fmt"
+	"regexp"
+
+	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
+	"github.com/jetstack/cert-manager/pkg/internal/api/validation"
+	acmeinstall "github.com/jetstack/cert-manager/pkg/internal/apis/acme/install"
+	cminstall "github.com/jetstack/cert-manager/pkg/internal/apis/certmanager/install"
+	cmvalidation "github.com/jetstack/cert-manager/pkg/internal/apis/certmanager/validation"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+// CertificatePolicy configures requirements that the webhook enforces on
+// every Certificate, in addition to the validation of the Certificate API.
+// The zero value enforces no additional requirements.
+type CertificatePolicy struct {
+	// SecretNamePattern is a regular expression that the spec.secretName of
+	// every Certificate must match. The pattern must match the whole
+	// secretName. If empty, any secretName is allowed.
+	SecretNamePattern string
+
+	// MinRSAKeySize is the minimum size of the RSA private key of every
+	// Certificate, and MinECDSACurve the minimum curve size of the ECDSA
+	// private key of every Certificate. Keys that do not specify a size are
+	// checked against their default size. A minimum of 0 disables the check
+	// for that algorithm.
+	MinRSAKeySize int
+	MinECDSACurve int
+
+	// MaxSubjectAltNames is the maximum total number of subject alternative
+	// names a Certificate may request. If 0, the limit of Let's Encrypt is
+	// enforced for Certificates that reference an ACME issuer, provided that
+	// Issuers and ClusterIssuers are set.
+	MaxSubjectAltNames int
+
+	// Issuers and ClusterIssuers are used to look up the issuer referenced by
+	// a Certificate, so that the Certificate can be checked against the
+	// requirements of its issuer, such as the
+	// cert-manager.io/require-common-name annotation. If either is nil, these
+	// checks are skipped.
+	Issuers        cmlisters.IssuerLister
+	ClusterIssuers cmlisters.ClusterIssuerLister
+}
+
+// NewValidationRegistry returns a validation registry with all of the
+// validations of ValidationRegistry, as well as the checks of the given
+// Certificate policy. An error is returned if the policy is invalid.
+func NewValidationRegistry(policy CertificatePolicy) (*validation.Registry, error) {
+	p, err := policy.build()
+	if err != nil {
+		return nil, err
+	}
+
+	reg := validation.NewRegistry(Scheme)
+	cminstall.InstallValidation(reg)
+	acmeinstall.InstallValidation(reg)
+	if err := p.AddToValidationRegistry(reg); err != nil {
+		return nil, err
+	}
+	return reg, nil
+}
+
+func (c CertificatePolicy) build() (*cmvalidation.CertificatePolicy, error) {
+	p := &cmvalidation.CertificatePolicy{}
+
+	if c.SecretNamePattern != "" {
+		re, err := regexp.Compile("^(?:" + c.SecretNamePattern + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid Certificate secretName pattern %q: %w", c.SecretNamePattern, err)
+		}
+		p.SecretNamePattern = re
+	}
+
+	if c.MinRSAKeySize != 0 && (c.MinRSAKeySize < pki.MinRSAKeySize || c.MinRSAKeySize > pki.MaxRSAKeySize) {
+		return nil, fmt.Errorf("invalid minimum RSA key size %d: must be between %d & %d", c.MinRSAKeySize, pki.MinRSAKeySize, pki.MaxRSAKeySize)
+	}
+	switch c.MinECDSACurve {
+	case 0, pki.ECCurve256, pki.ECCurve384, pki.ECCurve521:
+	default:
+		return nil, fmt.Errorf("invalid minimum ECDSA curve %d: must be one of %d, %d or %d", c.MinECDSACurve, pki.ECCurve256, pki.ECCurve384, pki.ECCurve521)
+	}
+	p.MinRSAKeySize = c.MinRSAKeySize
+	p.MinECDSAKeySize = c.MinECDSACurve
+
+	if c.MaxSubjectAltNames < 0 {
+		return nil, fmt.Errorf("invalid maximum number of subject alternative names %d: must not be negative", c.MaxSubjectAltNames)
+	}
+	p.MaxSubjectAltNames = c.MaxSubjectAltNames
+
+	if c.Issuers != nil && c.ClusterIssuers != nil {
+		p.IssuerResolver = newIssuerResolver(c.Issuers, c.ClusterIssuers)
+	}
+
+	return p, nil
+}
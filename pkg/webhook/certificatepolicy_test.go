@@ -0,0 +1,178 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2/klogr"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
+	"github.com/jetstack/cert-manager/pkg/webhook/handlers"
+)
+
+func TestCertificatePolicyIssuerValidation(t *testing.T) {
+	issuers := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	clusterIssuers := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, obj := range []runtime.Object{
+		&cmapi.Issuer{
+			ObjectMeta: metav1.ObjectMeta{Name: "plain", Namespace: "team-a"},
+			Spec:       cmapi.IssuerSpec{IssuerConfig: cmapi.IssuerConfig{CA: &cmapi.CAIssuer{SecretName: "ca"}}},
+		},
+		&cmapi.Issuer{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "require-cn",
+				Namespace:   "team-a",
+				Annotations: map[string]string{cmapi.RequireCommonNameAnnotationKey: "true"},
+			},
+			Spec: cmapi.IssuerSpec{IssuerConfig: cmapi.IssuerConfig{CA: &cmapi.CAIssuer{SecretName: "ca"}}},
+		},
+	} {
+		if err := issuers.Add(obj); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := clusterIssuers.Add(&cmapi.ClusterIssuer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "require-cn",
+			Annotations: map[string]string{cmapi.RequireCommonNameAnnotationKey: "true"},
+		},
+		Spec: cmapi.IssuerSpec{IssuerConfig: cmapi.IssuerConfig{ACME: &cmacme.ACMEIssuer{Server: "https://acme.example.com"}}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	registry, err := NewValidationRegistry(CertificatePolicy{
+		Issuers:        cmlisters.NewIssuerLister(issuers),
+		ClusterIssuers: cmlisters.NewClusterIssuerLister(clusterIssuers),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := handlers.NewRegistryBackedValidator(klogr.New(), Scheme, registry)
+
+	dnsNames := func(n int) []string {
+		var names []string
+		for i := 0; i < n; i++ {
+			names = append(names, fmt.Sprintf("%d.example.com", i))
+		}
+		return names
+	}
+
+	tests := map[string]struct {
+		namespace  string
+		issuerRef  string
+		commonName string
+		dnsNames   []string
+		expectErr  string
+	}{
+		"rejects a Certificate without a commonName for an Issuer that requires one": {
+			namespace: "team-a",
+			issuerRef: `{"name": "require-cn"}`,
+			expectErr: "spec.commonName: Required value: issuer 'require-cn' requires a commonName to be set",
+		},
+		"rejects a Certificate without a commonName for a ClusterIssuer that requires one": {
+			namespace: "team-a",
+			issuerRef: `{"name": "require-cn", "kind": "ClusterIssuer"}`,
+			expectErr: "spec.commonName: Required value: issuer 'require-cn' requires a commonName to be set",
+		},
+		"accepts a Certificate with a commonName for an Issuer that requires one": {
+			namespace:  "team-a",
+			issuerRef:  `{"name": "require-cn"}`,
+			commonName: "example.com",
+		},
+		"accepts a Certificate without a commonName for an Issuer that does not require one": {
+			namespace: "team-a",
+			issuerRef: `{"name": "plain"}`,
+		},
+		"only resolves Issuers in the namespace of the Certificate": {
+			namespace: "team-b",
+			issuerRef: `{"name": "require-cn"}`,
+		},
+		"accepts a Certificate for an issuer of another group": {
+			namespace: "team-a",
+			issuerRef: `{"name": "require-cn", "kind": "Issuer", "group": "example.com"}`,
+		},
+		"rejects a Certificate with more than 100 subject alternative names for an ACME issuer": {
+			namespace:  "team-a",
+			issuerRef:  `{"name": "require-cn", "kind": "ClusterIssuer"}`,
+			commonName: "0.example.com",
+			dnsNames:   dnsNames(101),
+			expectErr:  "spec: Invalid value: 101: the total number of dnsNames, ipAddresses, uris and emailAddresses must be no more than 100",
+		},
+		"accepts a Certificate with more than 100 subject alternative names for a non-ACME issuer": {
+			namespace: "team-a",
+			issuerRef: `{"name": "plain"}`,
+			dnsNames:  dnsNames(101),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if test.dnsNames == nil {
+				test.dnsNames = []string{"example.com"}
+			}
+			dnsNamesJSON, err := json.Marshal(test.dnsNames)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp := v.Validate(&admissionv1.AdmissionRequest{
+				RequestKind: &metav1.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"},
+				Operation:   admissionv1.Create,
+				Namespace:   test.namespace,
+				Object: runtime.RawExtension{
+					Raw: []byte(fmt.Sprintf(`{
+	"apiVersion": "cert-manager.io/v1",
+	"kind": "Certificate",
+	"metadata": {
+		"name": "example",
+		"namespace": %q,
+		"creationTimestamp": null
+	},
+	"spec": {
+		"secretName": "example-tls",
+		"commonName": %q,
+		"dnsNames": %s,
+		"issuerRef": %s
+	}
+}`, test.namespace, test.commonName, dnsNamesJSON, test.issuerRef)),
+				},
+			})
+			if test.expectErr == "" {
+				if !resp.Allowed {
+					t.Fatalf("expected request to be allowed, got %v", resp.Result)
+				}
+				return
+			}
+			if resp.Allowed {
+				t.Fatalf("expected request to be denied with %q", test.expectErr)
+			}
+			if !strings.Contains(resp.Result.Message, test.expectErr) {
+				t.Errorf("expected error containing %q, got %q", test.expectErr, resp.Result.Message)
+			}
+		})
+	}
+}
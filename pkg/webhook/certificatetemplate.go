@@ -0,0 +1,204 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmapiv1alpha2 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmapiv1alpha3 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha3"
+	cmapiv1beta1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1beta1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	internalcmapi "github.com/jetstack/cert-manager/pkg/internal/apis/certmanager"
+	internalcmmeta "github.com/jetstack/cert-manager/pkg/internal/apis/meta"
+	"github.com/jetstack/cert-manager/pkg/webhook/handlers"
+)
+
+// CertificateTemplate sets the fields of Certificates that reference a
+// template ConfigMap with spec.templateRef from that template, unless they
+// are set on the Certificate itself.
+type CertificateTemplate struct {
+	client kubernetes.Interface
+}
+
+var _ handlers.AdmissionDefaulter = &CertificateTemplate{}
+
+func NewCertificateTemplate(client kubernetes.Interface) *CertificateTemplate {
+	return &CertificateTemplate{client: client}
+}
+
+// certificateTemplate holds the fields of a Certificate's spec that may be
+// set from a template, in the format of the cert-manager.io/v1 API.
+type certificateTemplate struct {
+	Subject               *cmapi.X509Subject           `json:"subject,omitempty"`
+	Duration              *metav1.Duration             `json:"duration,omitempty"`
+	RenewBefore           *metav1.Duration             `json:"renewBefore,omitempty"`
+	Keystores             *cmapi.CertificateKeystores  `json:"keystores,omitempty"`
+	IssuerRef             *cmmeta.ObjectReference      `json:"issuerRef,omitempty"`
+	Usages                []cmapi.KeyUsage             `json:"usages,omitempty"`
+	PrivateKey            *cmapi.CertificatePrivateKey `json:"privateKey,omitempty"`
+	EncodeUsagesInRequest *bool                        `json:"encodeUsagesInRequest,omitempty"`
+}
+
+// Default sets any of the template fields that are not set on the given
+// Certificate from the template ConfigMap referenced by its templateRef.
+// The template is only applied when a Certificate is created or its
+// templateRef is changed, so that later updates do not depend on the template
+// ConfigMap still existing.
+func (d *CertificateTemplate) Default(admissionSpec *admissionv1.AdmissionRequest, obj runtime.Object) error {
+	// updates to the status subresource never change the spec
+	if admissionSpec.SubResource != "" {
+		return nil
+	}
+	switch obj.(type) {
+	case *cmapi.Certificate, *cmapiv1beta1.Certificate, *cmapiv1alpha3.Certificate, *cmapiv1alpha2.Certificate:
+	default:
+		return nil
+	}
+
+	// merge the template using the internal API version, so that all the
+	// external API versions are handled in the same way
+	crt := &internalcmapi.Certificate{}
+	if err := Scheme.Convert(obj, crt, nil); err != nil {
+		return err
+	}
+	// a templateRef without a name is rejected by validation
+	if crt.Spec.TemplateRef == nil || crt.Spec.TemplateRef.Name == "" {
+		return nil
+	}
+
+	switch admissionSpec.Operation {
+	case admissionv1.Create:
+	case admissionv1.Update:
+		oldName, err := templateRefName(admissionSpec.OldObject.Raw)
+		if err != nil {
+			return err
+		}
+		if oldName == crt.Spec.TemplateRef.Name {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	tmpl, err := d.template(admissionSpec.Namespace, crt.Spec.TemplateRef.Name)
+	if err != nil {
+		return err
+	}
+
+	mergeCertificateTemplate(&crt.Spec, tmpl)
+
+	return Scheme.Convert(crt, obj, nil)
+}
+
+// templateRefName returns the name of the template ConfigMap referenced by the
+// given encoded Certificate, or an empty string if it does not reference one.
+// spec.templateRef has the same format in all API versions.
+func templateRefName(raw []byte) (string, error) {
+	var crt struct {
+		Spec struct {
+			TemplateRef *cmmeta.LocalObjectReference `json:"templateRef"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(raw, &crt); err != nil {
+		return "", fmt.Errorf("failed to decode existing Certificate: %w", err)
+	}
+	if crt.Spec.TemplateRef == nil {
+		return "", nil
+	}
+	return crt.Spec.TemplateRef.Name, nil
+}
+
+// template returns the template held by the named ConfigMap, converted to the
+// internal API version.
+func (d *CertificateTemplate) template(namespace, name string) (*internalcmapi.CertificateSpec, error) {
+	cm, err := d.client.CoreV1().ConfigMaps(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("Certificate template ConfigMap %q not found in namespace %q", name, namespace))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Certificate template ConfigMap %q: %w", name, err)
+	}
+
+	data, ok := cm.Data[cmapi.CertificateTemplateConfigMapKey]
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("Certificate template ConfigMap %q does not have a %q key", name, cmapi.CertificateTemplateConfigMapKey))
+	}
+
+	var tmpl certificateTemplate
+	if err := yaml.UnmarshalStrict([]byte(data), &tmpl); err != nil {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("Certificate template ConfigMap %q is invalid: %v", name, err))
+	}
+
+	spec := cmapi.CertificateSpec{
+		Subject:               tmpl.Subject,
+		Duration:              tmpl.Duration,
+		RenewBefore:           tmpl.RenewBefore,
+		Keystores:             tmpl.Keystores,
+		Usages:                tmpl.Usages,
+		PrivateKey:            tmpl.PrivateKey,
+		EncodeUsagesInRequest: tmpl.EncodeUsagesInRequest,
+	}
+	if tmpl.IssuerRef != nil {
+		spec.IssuerRef = *tmpl.IssuerRef
+	}
+
+	internalCrt := &internalcmapi.Certificate{}
+	if err := Scheme.Convert(&cmapi.Certificate{Spec: spec}, internalCrt, nil); err != nil {
+		return nil, err
+	}
+	return &internalCrt.Spec, nil
+}
+
+// mergeCertificateTemplate sets each template field that is not set in spec
+// from tmpl, such that fields set explicitly always override the template.
+func mergeCertificateTemplate(spec, tmpl *internalcmapi.CertificateSpec) {
+	if spec.Subject == nil {
+		spec.Subject = tmpl.Subject
+	}
+	if spec.Duration == nil {
+		spec.Duration = tmpl.Duration
+	}
+	if spec.RenewBefore == nil {
+		spec.RenewBefore = tmpl.RenewBefore
+	}
+	if spec.Keystores == nil {
+		spec.Keystores = tmpl.Keystores
+	}
+	if spec.IssuerRef == (internalcmmeta.ObjectReference{}) {
+		spec.IssuerRef = tmpl.IssuerRef
+	}
+	if len(spec.Usages) == 0 {
+		spec.Usages = tmpl.Usages
+	}
+	if spec.PrivateKey == nil {
+		spec.PrivateKey = tmpl.PrivateKey
+	}
+	if spec.EncodeUsagesInRequest == nil {
+		spec.EncodeUsagesInRequest = tmpl.EncodeUsagesInRequest
+	}
+}
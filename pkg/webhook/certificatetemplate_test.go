@@ -0,0 +1,281 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mattbaird/jsonpatch"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/klog/v2/klogr"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmapiv1alpha2 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/jetstack/cert-manager/pkg/webhook/handlers"
+)
+
+func templateConfigMap(namespace, name string, data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}, Data: data}
+}
+
+func TestCertificateTemplate(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		templateConfigMap("team-a", "defaults", map[string]string{
+			cmapi.CertificateTemplateConfigMapKey: `
+duration: 720h
+issuerRef:
+  name: team-a-ca
+  kind: ClusterIssuer
+usages:
+- server auth
+subject:
+  organizations:
+  - team-a
+`,
+		}),
+		templateConfigMap("team-a", "missing-key", map[string]string{"other": "duration: 1h"}),
+		templateConfigMap("team-a", "unknown-field", map[string]string{
+			cmapi.CertificateTemplateConfigMapKey: "secretName: not-allowed",
+		}),
+	)
+	d := NewCertificateTemplate(client)
+
+	duration := &metav1.Duration{Duration: 720 * time.Hour}
+	ref := &cmmeta.LocalObjectReference{Name: "defaults"}
+
+	tests := map[string]struct {
+		obj       runtime.Object
+		expected  runtime.Object
+		expectErr string
+	}{
+		"sets unset fields from the template": {
+			obj: &cmapi.Certificate{Spec: cmapi.CertificateSpec{SecretName: "tls", TemplateRef: ref}},
+			expected: &cmapi.Certificate{Spec: cmapi.CertificateSpec{
+				SecretName:  "tls",
+				TemplateRef: ref,
+				Duration:    duration,
+				IssuerRef:   cmmeta.ObjectReference{Name: "team-a-ca", Kind: "ClusterIssuer"},
+				Usages:      []cmapi.KeyUsage{cmapi.UsageServerAuth},
+				Subject:     &cmapi.X509Subject{Organizations: []string{"team-a"}},
+			}},
+		},
+		"does not override fields set on the Certificate": {
+			obj: &cmapi.Certificate{Spec: cmapi.CertificateSpec{
+				TemplateRef: ref,
+				Duration:    &metav1.Duration{Duration: time.Hour},
+				IssuerRef:   cmmeta.ObjectReference{Name: "explicit"},
+			}},
+			expected: &cmapi.Certificate{Spec: cmapi.CertificateSpec{
+				TemplateRef: ref,
+				Duration:    &metav1.Duration{Duration: time.Hour},
+				IssuerRef:   cmmeta.ObjectReference{Name: "explicit"},
+				Usages:      []cmapi.KeyUsage{cmapi.UsageServerAuth},
+				Subject:     &cmapi.X509Subject{Organizations: []string{"team-a"}},
+			}},
+		},
+		"sets fields of older API versions": {
+			obj: &cmapiv1alpha2.Certificate{Spec: cmapiv1alpha2.CertificateSpec{TemplateRef: ref}},
+			expected: &cmapiv1alpha2.Certificate{Spec: cmapiv1alpha2.CertificateSpec{
+				TemplateRef:  ref,
+				Duration:     duration,
+				IssuerRef:    cmmeta.ObjectReference{Name: "team-a-ca", Kind: "ClusterIssuer"},
+				Usages:       []cmapiv1alpha2.KeyUsage{cmapiv1alpha2.UsageServerAuth},
+				Organization: []string{"team-a"},
+				Subject:      &cmapiv1alpha2.X509Subject{},
+			}},
+		},
+		"does nothing without a templateRef": {
+			obj:      &cmapi.Certificate{},
+			expected: &cmapi.Certificate{},
+		},
+		"ignores resources other than Certificates": {
+			obj:      &cmapi.CertificateRequest{},
+			expected: &cmapi.CertificateRequest{},
+		},
+		"rejects a missing template ConfigMap": {
+			obj:       &cmapi.Certificate{Spec: cmapi.CertificateSpec{TemplateRef: &cmmeta.LocalObjectReference{Name: "missing"}}},
+			expectErr: `Certificate template ConfigMap "missing" not found in namespace "team-a"`,
+		},
+		"rejects a ConfigMap without a template": {
+			obj:       &cmapi.Certificate{Spec: cmapi.CertificateSpec{TemplateRef: &cmmeta.LocalObjectReference{Name: "missing-key"}}},
+			expectErr: `Certificate template ConfigMap "missing-key" does not have a "template" key`,
+		},
+		"rejects a template with fields that cannot be templated": {
+			obj:       &cmapi.Certificate{Spec: cmapi.CertificateSpec{TemplateRef: &cmmeta.LocalObjectReference{Name: "unknown-field"}}},
+			expectErr: `Certificate template ConfigMap "unknown-field" is invalid`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := d.Default(&admissionv1.AdmissionRequest{Namespace: "team-a", Operation: admissionv1.Create}, test.obj)
+			if test.expectErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.expectErr) {
+					t.Fatalf("expected error containing %q, got %v", test.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(test.expected, test.obj) {
+				t.Errorf("expected %#v, got %#v", test.expected, test.obj)
+			}
+		})
+	}
+}
+
+func TestCertificateTemplateMutation(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		templateConfigMap("team-a", "defaults", map[string]string{
+			cmapi.CertificateTemplateConfigMapKey: "issuerRef:\n  name: template-ca\n",
+		}),
+		namespace("team-a", map[string]string{
+			cmapi.DefaultIssuerNameAnnotationKey: "namespace-ca",
+		}),
+	)
+	m := handlers.NewSchemeBackedDefaulter(klogr.New(), Scheme, NewCertificateTemplate(client), NewNamespaceDefaultIssuer(client))
+
+	resp := m.Mutate(&admissionv1.AdmissionRequest{
+		Namespace: "team-a",
+		Operation: admissionv1.Create,
+		Object: runtime.RawExtension{
+			Raw: []byte(`{"apiVersion": "cert-manager.io/v1", "kind": "Certificate", "metadata": {"name": "example", "namespace": "team-a"}, "spec": {"secretName": "example-tls", "templateRef": {"name": "defaults"}}}`),
+		},
+	})
+	if !resp.Allowed {
+		t.Fatalf("expected request to be allowed, got %v", resp.Result)
+	}
+
+	var ops []jsonpatch.JsonPatchOperation
+	if err := json.Unmarshal(resp.Patch, &ops); err != nil {
+		t.Fatal(err)
+	}
+	for _, op := range ops {
+		if op.Path == "/spec/issuerRef/name" && op.Value == "template-ca" {
+			return
+		}
+		if op.Path == "/spec/issuerRef" {
+			if ref, ok := op.Value.(map[string]interface{}); ok && ref["name"] == "template-ca" {
+				return
+			}
+		}
+	}
+	t.Errorf("expected patch to set issuerRef from the template, got %s", resp.Patch)
+}
+
+func TestCertificateTemplateUpdate(t *testing.T) {
+	// the template ConfigMap referenced by the existing Certificate has been
+	// deleted since the Certificate was created
+	client := fake.NewSimpleClientset(
+		templateConfigMap("team-a", "new-defaults", map[string]string{
+			cmapi.CertificateTemplateConfigMapKey: "duration: 720h\n",
+		}),
+	)
+	d := NewCertificateTemplate(client)
+
+	oldCrt := []byte(`{"apiVersion": "cert-manager.io/v1", "kind": "Certificate", "spec": {"secretName": "tls", "templateRef": {"name": "deleted"}}}`)
+
+	tests := map[string]struct {
+		request   *admissionv1.AdmissionRequest
+		obj       runtime.Object
+		expected  runtime.Object
+		expectErr string
+	}{
+		"an update that does not change templateRef succeeds without the template": {
+			request: &admissionv1.AdmissionRequest{
+				Namespace: "team-a",
+				Operation: admissionv1.Update,
+				OldObject: runtime.RawExtension{Raw: oldCrt},
+			},
+			obj: &cmapi.Certificate{Spec: cmapi.CertificateSpec{
+				SecretName:  "tls-renamed",
+				TemplateRef: &cmmeta.LocalObjectReference{Name: "deleted"},
+			}},
+			expected: &cmapi.Certificate{Spec: cmapi.CertificateSpec{
+				SecretName:  "tls-renamed",
+				TemplateRef: &cmmeta.LocalObjectReference{Name: "deleted"},
+			}},
+		},
+		"an update of the status subresource succeeds without the template": {
+			request: &admissionv1.AdmissionRequest{
+				Namespace:   "team-a",
+				Operation:   admissionv1.Update,
+				SubResource: "status",
+				OldObject:   runtime.RawExtension{Raw: oldCrt},
+			},
+			obj: &cmapi.Certificate{Spec: cmapi.CertificateSpec{
+				TemplateRef: &cmmeta.LocalObjectReference{Name: "deleted"},
+			}},
+			expected: &cmapi.Certificate{Spec: cmapi.CertificateSpec{
+				TemplateRef: &cmmeta.LocalObjectReference{Name: "deleted"},
+			}},
+		},
+		"an update that changes templateRef applies the new template": {
+			request: &admissionv1.AdmissionRequest{
+				Namespace: "team-a",
+				Operation: admissionv1.Update,
+				OldObject: runtime.RawExtension{Raw: oldCrt},
+			},
+			obj: &cmapi.Certificate{Spec: cmapi.CertificateSpec{
+				TemplateRef: &cmmeta.LocalObjectReference{Name: "new-defaults"},
+			}},
+			expected: &cmapi.Certificate{Spec: cmapi.CertificateSpec{
+				TemplateRef: &cmmeta.LocalObjectReference{Name: "new-defaults"},
+				Duration:    &metav1.Duration{Duration: 720 * time.Hour},
+			}},
+		},
+		"an update that adds a templateRef referencing a missing template is rejected": {
+			request: &admissionv1.AdmissionRequest{
+				Namespace: "team-a",
+				Operation: admissionv1.Update,
+				OldObject: runtime.RawExtension{Raw: []byte(`{"apiVersion": "cert-manager.io/v1", "kind": "Certificate", "spec": {"secretName": "tls"}}`)},
+			},
+			obj: &cmapi.Certificate{Spec: cmapi.CertificateSpec{
+				TemplateRef: &cmmeta.LocalObjectReference{Name: "deleted"},
+			}},
+			expectErr: `Certificate template ConfigMap "deleted" not found in namespace "team-a"`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := d.Default(test.request, test.obj)
+			if test.expectErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.expectErr) {
+					t.Fatalf("expected error containing %q, got %v", test.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(test.expected, test.obj) {
+				t.Errorf("expected %#v, got %#v", test.expected, test.obj)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmapiv1alpha2 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmapiv1alpha3 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha3"
+	cmapiv1beta1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1beta1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/jetstack/cert-manager/pkg/webhook/handlers"
+)
+
+// NamespaceDefaultIssuer defaults the issuerRef of Certificates that do not
+// specify one to the Issuer named in the annotations of their Namespace.
+type NamespaceDefaultIssuer struct {
+	client kubernetes.Interface
+}
+
+var _ handlers.AdmissionDefaulter = &NamespaceDefaultIssuer{}
+
+func NewNamespaceDefaultIssuer(client kubernetes.Interface) *NamespaceDefaultIssuer {
+	return &NamespaceDefaultIssuer{client: client}
+}
+
+// Default sets the issuerRef of the given Certificate from the
+// cert-manager.io/default-issuer annotations of its Namespace, if the
+// Certificate does not specify an issuerRef and the Namespace is annotated.
+func (d *NamespaceDefaultIssuer) Default(admissionSpec *admissionv1.AdmissionRequest, obj runtime.Object) error {
+	var issuerRef *cmmeta.ObjectReference
+	switch crt := obj.(type) {
+	case *cmapi.Certificate:
+		issuerRef = &crt.Spec.IssuerRef
+	case *cmapiv1beta1.Certificate:
+		issuerRef = &crt.Spec.IssuerRef
+	case *cmapiv1alpha3.Certificate:
+		issuerRef = &crt.Spec.IssuerRef
+	case *cmapiv1alpha2.Certificate:
+		issuerRef = &crt.Spec.IssuerRef
+	default:
+		return nil
+	}
+
+	if *issuerRef != (cmmeta.ObjectReference{}) {
+		return nil
+	}
+
+	ns, err := d.client.CoreV1().Namespaces().Get(context.TODO(), admissionSpec.Namespace, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get namespace %q for default issuer: %w", admissionSpec.Namespace, err)
+	}
+
+	name := ns.Annotations[cmapi.DefaultIssuerNameAnnotationKey]
+	if name == "" {
+		return nil
+	}
+
+	kind := ns.Annotations[cmapi.DefaultIssuerKindAnnotationKey]
+	group := ns.Annotations[cmapi.DefaultIssuerGroupAnnotationKey]
+	if group == "" || group == certmanager.GroupName {
+		switch kind {
+		case "", cmapi.IssuerKind, cmapi.ClusterIssuerKind:
+		default:
+			return apierrors.NewBadRequest(fmt.Sprintf("namespace %q has invalid %s annotation %q: must be one of %q or %q",
+				ns.Name, cmapi.DefaultIssuerKindAnnotationKey, kind, cmapi.IssuerKind, cmapi.ClusterIssuerKind))
+		}
+	}
+
+	*issuerRef = cmmeta.ObjectReference{
+		Name:  name,
+		Kind:  kind,
+		Group: group,
+	}
+
+	return nil
+}
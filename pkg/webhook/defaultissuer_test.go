@@ -0,0 +1,214 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mattbaird/jsonpatch"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/klog/v2/klogr"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmapiv1alpha2 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/jetstack/cert-manager/pkg/webhook/handlers"
+)
+
+func namespace(name string, annotations map[string]string) *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: annotations}}
+}
+
+func TestNamespaceDefaultIssuer(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		namespace("plain", nil),
+		namespace("issuer", map[string]string{
+			cmapi.DefaultIssuerNameAnnotationKey: "ns-issuer",
+		}),
+		namespace("cluster-issuer", map[string]string{
+			cmapi.DefaultIssuerNameAnnotationKey: "ca",
+			cmapi.DefaultIssuerKindAnnotationKey: "ClusterIssuer",
+		}),
+		namespace("external-issuer", map[string]string{
+			cmapi.DefaultIssuerNameAnnotationKey:  "pca",
+			cmapi.DefaultIssuerKindAnnotationKey:  "AWSPCAClusterIssuer",
+			cmapi.DefaultIssuerGroupAnnotationKey: "awspca.cert-manager.io",
+		}),
+		namespace("invalid-kind", map[string]string{
+			cmapi.DefaultIssuerNameAnnotationKey: "ca",
+			cmapi.DefaultIssuerKindAnnotationKey: "Secret",
+		}),
+	)
+	d := NewNamespaceDefaultIssuer(client)
+
+	tests := map[string]struct {
+		namespace string
+		obj       runtime.Object
+		expected  runtime.Object
+		expectErr string
+	}{
+		"defaults issuerRef from the namespace annotation": {
+			namespace: "issuer",
+			obj:       &cmapi.Certificate{},
+			expected: &cmapi.Certificate{Spec: cmapi.CertificateSpec{
+				IssuerRef: cmmeta.ObjectReference{Name: "ns-issuer"},
+			}},
+		},
+		"defaults issuerRef kind from the namespace annotation": {
+			namespace: "cluster-issuer",
+			obj:       &cmapi.Certificate{},
+			expected: &cmapi.Certificate{Spec: cmapi.CertificateSpec{
+				IssuerRef: cmmeta.ObjectReference{Name: "ca", Kind: "ClusterIssuer"},
+			}},
+		},
+		"defaults issuerRef of an external issuer": {
+			namespace: "external-issuer",
+			obj:       &cmapi.Certificate{},
+			expected: &cmapi.Certificate{Spec: cmapi.CertificateSpec{
+				IssuerRef: cmmeta.ObjectReference{Name: "pca", Kind: "AWSPCAClusterIssuer", Group: "awspca.cert-manager.io"},
+			}},
+		},
+		"defaults issuerRef of older API versions": {
+			namespace: "issuer",
+			obj:       &cmapiv1alpha2.Certificate{},
+			expected: &cmapiv1alpha2.Certificate{Spec: cmapiv1alpha2.CertificateSpec{
+				IssuerRef: cmmeta.ObjectReference{Name: "ns-issuer"},
+			}},
+		},
+		"does not override an explicit issuerRef": {
+			namespace: "issuer",
+			obj: &cmapi.Certificate{Spec: cmapi.CertificateSpec{
+				IssuerRef: cmmeta.ObjectReference{Name: "explicit"},
+			}},
+			expected: &cmapi.Certificate{Spec: cmapi.CertificateSpec{
+				IssuerRef: cmmeta.ObjectReference{Name: "explicit"},
+			}},
+		},
+		"does nothing if the namespace is not annotated": {
+			namespace: "plain",
+			obj:       &cmapi.Certificate{},
+			expected:  &cmapi.Certificate{},
+		},
+		"ignores resources other than Certificates": {
+			namespace: "issuer",
+			obj:       &cmapi.CertificateRequest{},
+			expected:  &cmapi.CertificateRequest{},
+		},
+		"rejects an invalid issuer kind": {
+			namespace: "invalid-kind",
+			obj:       &cmapi.Certificate{},
+			expectErr: `namespace "invalid-kind" has invalid cert-manager.io/default-issuer-kind annotation "Secret"`,
+		},
+		"fails if the namespace cannot be read": {
+			namespace: "missing",
+			obj:       &cmapi.Certificate{},
+			expectErr: `failed to get namespace "missing" for default issuer`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := d.Default(&admissionv1.AdmissionRequest{Namespace: test.namespace}, test.obj)
+			if test.expectErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.expectErr) {
+					t.Fatalf("expected error containing %q, got %v", test.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(test.expected, test.obj) {
+				t.Errorf("expected %#v, got %#v", test.expected, test.obj)
+			}
+		})
+	}
+}
+
+func TestNamespaceDefaultIssuerMutation(t *testing.T) {
+	client := fake.NewSimpleClientset(namespace("team-a", map[string]string{
+		cmapi.DefaultIssuerNameAnnotationKey: "team-a-ca",
+		cmapi.DefaultIssuerKindAnnotationKey: "Issuer",
+	}))
+	m := handlers.NewSchemeBackedDefaulter(klogr.New(), Scheme, NewNamespaceDefaultIssuer(client))
+
+	resp := m.Mutate(&admissionv1.AdmissionRequest{
+		Namespace: "team-a",
+		Object: runtime.RawExtension{
+			Raw: []byte(`{
+	"apiVersion": "cert-manager.io/v1",
+	"kind": "Certificate",
+	"metadata": {
+		"name": "example",
+		"namespace": "team-a",
+		"creationTimestamp": null
+	},
+	"spec": {
+		"secretName": "example-tls",
+		"dnsNames": ["example.com"]
+	}
+}`),
+		},
+	})
+	if !resp.Allowed {
+		t.Fatalf("expected request to be allowed, got %v", resp.Result)
+	}
+
+	var ops []jsonpatch.JsonPatchOperation
+	if err := json.Unmarshal(resp.Patch, &ops); err != nil {
+		t.Fatal(err)
+	}
+	for _, op := range ops {
+		if op.Path == "/spec/issuerRef/name" && op.Value == "team-a-ca" {
+			return
+		}
+		if op.Path == "/spec/issuerRef" {
+			if ref, ok := op.Value.(map[string]interface{}); ok && ref["name"] == "team-a-ca" && ref["kind"] == "Issuer" {
+				return
+			}
+		}
+	}
+	t.Errorf("expected patch to set issuerRef, got %s", resp.Patch)
+}
+
+func TestNamespaceDefaultIssuerMutationDenied(t *testing.T) {
+	client := fake.NewSimpleClientset(namespace("team-a", map[string]string{
+		cmapi.DefaultIssuerNameAnnotationKey: "team-a-ca",
+		cmapi.DefaultIssuerKindAnnotationKey: "Issuers",
+	}))
+	m := handlers.NewSchemeBackedDefaulter(klogr.New(), Scheme, NewNamespaceDefaultIssuer(client))
+
+	resp := m.Mutate(&admissionv1.AdmissionRequest{
+		Namespace: "team-a",
+		Object: runtime.RawExtension{
+			Raw: []byte(`{"apiVersion": "cert-manager.io/v1", "kind": "Certificate", "metadata": {"name": "example", "namespace": "team-a"}, "spec": {"secretName": "example-tls"}}`),
+		},
+	})
+	if resp.Allowed {
+		t.Fatal("expected request to be denied")
+	}
+	if resp.Result == nil || resp.Result.Reason != metav1.StatusReasonBadRequest {
+		t.Errorf("expected bad request result, got %v", resp.Result)
+	}
+}
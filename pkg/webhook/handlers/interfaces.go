@@ -20,6 +20,7 @@ import (
 	admissionv1 "k8s.io/api/admission/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 type ValidatingAdmissionHook interface {
@@ -34,6 +35,15 @@ type MutatingAdmissionHook interface {
 	Mutate(admissionSpec *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse
 }
 
+// AdmissionDefaulter applies defaults to an object that cannot be applied by
+// the defaulting functions registered with a scheme, for example because they
+// depend on the admission request or on other resources.
+type AdmissionDefaulter interface {
+	// Default is called with the object being admitted, after any scheme
+	// defaults have been applied. An error denies the admission request.
+	Default(admissionSpec *admissionv1.AdmissionRequest, obj runtime.Object) error
+}
+
 type ConversionHook interface {
 	// ConvertV1 is called to convert a resource in one version into a different version.
 	ConvertV1(conversionSpec *apiextensionsv1.ConversionRequest) *apiextensionsv1.ConversionResponse
@@ -26,6 +26,7 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/mattbaird/jsonpatch"
 	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
@@ -35,20 +36,24 @@ import (
 )
 
 type SchemeBackedDefaulter struct {
-	log    logr.Logger
-	scheme *runtime.Scheme
-	codec  runtime.Codec
+	log        logr.Logger
+	scheme     *runtime.Scheme
+	codec      runtime.Codec
+	defaulters []AdmissionDefaulter
 }
 
-func NewSchemeBackedDefaulter(log logr.Logger, scheme *runtime.Scheme) *SchemeBackedDefaulter {
+// NewSchemeBackedDefaulter returns a mutating admission hook that applies the
+// defaults registered with the scheme, followed by the given defaulters.
+func NewSchemeBackedDefaulter(log logr.Logger, scheme *runtime.Scheme, defaulters ...AdmissionDefaulter) *SchemeBackedDefaulter {
 	factory := serializer.NewCodecFactory(scheme)
 	serializer := apijson.NewSerializerWithOptions(apijson.DefaultMetaFactory, scheme, scheme, apijson.SerializerOptions{})
 	encoder := factory.WithoutConversion().EncoderForVersion(serializer, nil)
 	decoder := factory.UniversalDeserializer()
 	return &SchemeBackedDefaulter{
-		log:    log,
-		scheme: scheme,
-		codec:  runtime.NewCodec(encoder, decoder),
+		log:        log,
+		scheme:     scheme,
+		codec:      runtime.NewCodec(encoder, decoder),
+		defaulters: defaulters,
 	}
 }
 
@@ -70,6 +75,20 @@ func (c *SchemeBackedDefaulter) Mutate(admissionSpec *admissionv1.AdmissionReque
 	defaultedObj := obj.DeepCopyObject()
 	// apply defaults to the object
 	c.scheme.Default(defaultedObj)
+	for _, d := range c.defaulters {
+		if err := d.Default(admissionSpec, defaultedObj); err != nil {
+			if apiStatus, ok := err.(apierrors.APIStatus); ok {
+				result := apiStatus.Status()
+				status.Result = &result
+			} else {
+				status.Result = &metav1.Status{
+					Status: metav1.StatusFailure, Code: http.StatusInternalServerError, Reason: metav1.StatusReasonInternalError,
+					Message: fmt.Sprintf("Failed to apply defaults: %v", err.Error()),
+				}
+			}
+			return status
+		}
+	}
 	// encode the default object to JSON
 	buf := bytes.Buffer{}
 	if err := c.codec.Encode(defaultedObj, &buf); err != nil {
@@ -18,11 +18,15 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"net/http"
 	"reflect"
 	"testing"
 
 	"github.com/mattbaird/jsonpatch"
 	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2/klogr"
@@ -100,6 +104,56 @@ func TestDefaultCertificate(t *testing.T) {
 	}
 }
 
+type defaulterFunc func(*admissionv1.AdmissionRequest, runtime.Object) error
+
+func (f defaulterFunc) Default(req *admissionv1.AdmissionRequest, obj runtime.Object) error {
+	return f(req, obj)
+}
+
+func TestDefaulterErrors(t *testing.T) {
+	scheme := runtime.NewScheme()
+	install.Install(scheme)
+
+	raw := []byte(`{"apiVersion": "testgroup.testing.cert-manager.io/v1", "kind": "TestType", "metadata": {"name": "testing"}}`)
+	tests := map[string]struct {
+		err      error
+		expected *metav1.Status
+	}{
+		"API status errors are returned as is": {
+			err: apierrors.NewBadRequest("invalid default"),
+			expected: &metav1.Status{
+				Status: metav1.StatusFailure, Code: http.StatusBadRequest, Reason: metav1.StatusReasonBadRequest,
+				Message: "invalid default",
+			},
+		},
+		"other errors are internal errors": {
+			err: errors.New("lookup failed"),
+			expected: &metav1.Status{
+				Status: metav1.StatusFailure, Code: http.StatusInternalServerError, Reason: metav1.StatusReasonInternalError,
+				Message: "Failed to apply defaults: lookup failed",
+			},
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			c := NewSchemeBackedDefaulter(klogr.New(), scheme, defaulterFunc(func(*admissionv1.AdmissionRequest, runtime.Object) error {
+				return test.err
+			}))
+			runAdmissionTest(t, c.Mutate, admissionTestT{
+				inputRequest: admissionv1.AdmissionRequest{
+					UID:    types.UID("abc"),
+					Object: runtime.RawExtension{Raw: raw},
+				},
+				expectedResponse: admissionv1.AdmissionResponse{
+					UID:    types.UID("abc"),
+					Result: test.expected,
+				},
+			})
+		})
+	}
+}
+
 type admissionTestT struct {
 	inputRequest     admissionv1.AdmissionRequest
 	expectedResponse admissionv1.AdmissionResponse
@@ -27,7 +27,8 @@ type TestType struct {
 	metav1.ObjectMeta `json:"metadata"`
 
 	// TestField is used in tests.
-	// Validation doesn't allow this to be set to the value of TestFieldValueNotAllowed.
+	// Validation doesn't allow this to be set to the value of TestFieldValueNotAllowed,
+	// and warns if it is set to the value of TestFieldValueWarn.
 	TestField    string  `json:"testField"`
 	TestFieldPtr *string `json:"testFieldPtr,omitempty"`
 
@@ -37,4 +38,5 @@ type TestType struct {
 
 const (
 	TestFieldValueNotAllowed = "not-allowed-value"
+	TestFieldValueWarn       = "warn-value"
 )
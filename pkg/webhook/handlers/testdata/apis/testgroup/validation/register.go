@@ -28,5 +28,11 @@ func Register(reg *validation.Registry) error {
 	if err := reg.AddValidateUpdateFunc(&testgroup.TestType{}, ValidateTestTypeUpdate); err != nil {
 		return err
 	}
+	if err := reg.AddWarnFunc(&testgroup.TestType{}, WarnTestType); err != nil {
+		return err
+	}
+	if err := reg.AddWarnUpdateFunc(&testgroup.TestType{}, WarnTestTypeUpdate); err != nil {
+		return err
+	}
 	return nil
 }
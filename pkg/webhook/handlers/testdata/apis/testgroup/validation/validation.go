@@ -33,6 +33,18 @@ func ValidateTestType(obj runtime.Object) field.ErrorList {
 	return el
 }
 
+func WarnTestType(obj runtime.Object) []string {
+	testType := obj.(*testgroup.TestType)
+	if testType.TestField == v1.TestFieldValueWarn {
+		return []string{"testField: value is deprecated"}
+	}
+	return nil
+}
+
+func WarnTestTypeUpdate(oldObj, newObj runtime.Object) []string {
+	return WarnTestType(newObj)
+}
+
 func ValidateTestTypeUpdate(oldObj, newObj runtime.Object) field.ErrorList {
 	old, ok := oldObj.(*testgroup.TestType)
 	new := newObj.(*testgroup.TestType)
@@ -45,6 +45,11 @@ func NewRegistryBackedValidator(log logr.Logger, scheme *runtime.Scheme, registr
 	}
 }
 
+// Validate validates the object in the given admission request and returns
+// any warnings about it. It has no side effects, so dry-run requests, such as
+// those made by `kubectl apply --dry-run=server`, are validated and warned
+// about exactly as other requests are. This allows users to preview warnings
+// without persisting the resource.
 func (r *registryBackedValidator) Validate(admissionSpec *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
 	status := &admissionv1.AdmissionResponse{}
 	status.UID = admissionSpec.UID
@@ -108,5 +113,10 @@ func (r *registryBackedValidator) Validate(admissionSpec *admissionv1.AdmissionR
 	}
 
 	status.Allowed = true
+	if admissionSpec.Operation == admissionv1.Create {
+		status.Warnings = r.registry.Warn(obj, gvk)
+	} else if admissionSpec.Operation == admissionv1.Update {
+		status.Warnings = r.registry.WarnUpdate(oldObj, obj, gvk)
+	}
 	return status
 }
@@ -19,12 +19,14 @@ package handlers
 import (
 	"fmt"
 	"net/http"
+	"reflect"
 	"testing"
 
 	admissionv1 "k8s.io/api/admission/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/diff"
 
 	"github.com/jetstack/cert-manager/pkg/internal/api/validation"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
@@ -289,3 +291,92 @@ func TestRegistryBackedValidator(t *testing.T) {
 		})
 	}
 }
+
+func TestRegistryBackedValidatorDryRun(t *testing.T) {
+	scheme := runtime.NewScheme()
+	registry := validation.NewRegistry(scheme)
+	install.Install(scheme)
+	install.InstallValidations(registry)
+
+	c := NewRegistryBackedValidator(logf.Log, scheme, registry)
+	testTypeGVK := &metav1.GroupVersionKind{
+		Group:   v1.SchemeGroupVersion.Group,
+		Version: v1.SchemeGroupVersion.Version,
+		Kind:    "TestType",
+	}
+	object := func(testField string) runtime.RawExtension {
+		return runtime.RawExtension{
+			Raw: []byte(fmt.Sprintf(`
+{
+	"apiVersion": "testgroup.testing.cert-manager.io/v1",
+	"kind": "TestType",
+	"metadata": {
+		"name": "testing",
+		"namespace": "abc",
+		"creationTimestamp": null
+	},
+	"testField": "%s"
+}
+`, testField)),
+		}
+	}
+
+	tests := map[string]struct {
+		request          admissionv1.AdmissionRequest
+		expectedWarnings []string
+	}{
+		"create with a warning": {
+			request: admissionv1.AdmissionRequest{
+				UID:         types.UID("abc"),
+				RequestKind: testTypeGVK,
+				Operation:   admissionv1.Create,
+				Object:      object(v1.TestFieldValueWarn),
+			},
+			expectedWarnings: []string{"testField: value is deprecated"},
+		},
+		"update with a warning": {
+			request: admissionv1.AdmissionRequest{
+				UID:         types.UID("abc"),
+				RequestKind: testTypeGVK,
+				Operation:   admissionv1.Update,
+				Object:      object(v1.TestFieldValueWarn),
+				OldObject:   object("some-value"),
+			},
+			expectedWarnings: []string{"testField: value is deprecated"},
+		},
+		"create without a warning": {
+			request: admissionv1.AdmissionRequest{
+				UID:         types.UID("abc"),
+				RequestKind: testTypeGVK,
+				Operation:   admissionv1.Create,
+				Object:      object("some-value"),
+			},
+		},
+		"denied create": {
+			request: admissionv1.AdmissionRequest{
+				UID:         types.UID("abc"),
+				RequestKind: testTypeGVK,
+				Operation:   admissionv1.Create,
+				Object:      object(v1.TestFieldValueNotAllowed),
+			},
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			req := test.request.DeepCopy()
+			resp := c.Validate(req)
+			if !reflect.DeepEqual(test.expectedWarnings, resp.Warnings) {
+				t.Errorf("expected warnings %v, got %v", test.expectedWarnings, resp.Warnings)
+			}
+
+			dryRun := true
+			dryRunReq := test.request.DeepCopy()
+			dryRunReq.DryRun = &dryRun
+			dryRunResp := c.Validate(dryRunReq)
+			if !reflect.DeepEqual(resp, dryRunResp) {
+				t.Errorf("dry-run response was not the same as the response to a normal request: %v", diff.ObjectGoPrintSideBySide(resp, dryRunResp))
+			}
+		})
+	}
+}
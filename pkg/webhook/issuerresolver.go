@@ -0,0 +1,65 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"fmt"
+
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
+	internalcmapi "github.com/jetstack/cert-manager/pkg/internal/apis/certmanager"
+	cmvalidation "github.com/jetstack/cert-manager/pkg/internal/apis/certmanager/validation"
+	internalcmmeta "github.com/jetstack/cert-manager/pkg/internal/apis/meta"
+)
+
+// newIssuerResolver returns an IssuerResolver that looks up cert-manager
+// Issuers and ClusterIssuers using the given listers, and converts them to
+// the internal API version so that Certificates can be validated against
+// them. References to issuers of other API groups are not resolved.
+func newIssuerResolver(issuers cmlisters.IssuerLister, clusterIssuers cmlisters.ClusterIssuerLister) cmvalidation.IssuerResolver {
+	return func(namespace string, ref internalcmmeta.ObjectReference) (internalcmapi.GenericIssuer, error) {
+		if ref.Group != "" && ref.Group != certmanager.GroupName {
+			return nil, fmt.Errorf("issuers of group %q cannot be resolved", ref.Group)
+		}
+
+		switch ref.Kind {
+		case "", cmapi.IssuerKind:
+			issuer, err := issuers.Issuers(namespace).Get(ref.Name)
+			if err != nil {
+				return nil, err
+			}
+			out := &internalcmapi.Issuer{}
+			if err := Scheme.Convert(issuer, out, nil); err != nil {
+				return nil, err
+			}
+			return out, nil
+		case cmapi.ClusterIssuerKind:
+			issuer, err := clusterIssuers.Get(ref.Name)
+			if err != nil {
+				return nil, err
+			}
+			out := &internalcmapi.ClusterIssuer{}
+			if err := Scheme.Convert(issuer, out, nil); err != nil {
+				return nil, err
+			}
+			return out, nil
+		default:
+			return nil, fmt.Errorf("issuers of kind %q cannot be resolved", ref.Kind)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// metricsNamespace is the namespace for cert-manager metric names
+	metricsNamespace = "certmanager"
+	metricsSubsystem = "webhook"
+
+	validationOutcomeAllowed = "allowed"
+	validationOutcomeDenied  = "denied"
+)
+
+// validationMetrics holds the Prometheus metrics recorded for requests to
+// the validating webhook.
+type validationMetrics struct {
+	registry *prometheus.Registry
+
+	validationDurationSeconds *prometheus.HistogramVec
+	validationTotal           *prometheus.CounterVec
+}
+
+func newValidationMetrics() *validationMetrics {
+	m := &validationMetrics{
+		registry: prometheus.NewRegistry(),
+
+		validationDurationSeconds: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: metricsNamespace,
+				Subsystem: metricsSubsystem,
+				Name:      "validation_duration_seconds",
+				Help:      "The time taken in seconds to validate a resource submitted to the webhook.",
+				Buckets:   prometheus.DefBuckets,
+			},
+			[]string{"resource"},
+		),
+
+		validationTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: metricsNamespace,
+				Subsystem: metricsSubsystem,
+				Name:      "validation_total",
+				Help:      "The number of resources validated by the webhook, partitioned by whether they were allowed or denied.",
+			},
+			[]string{"resource", "outcome"},
+		),
+	}
+
+	m.registry.MustRegister(m.validationDurationSeconds)
+	m.registry.MustRegister(m.validationTotal)
+
+	return m
+}
+
+// observeValidation records a single validation of the given resource that
+// took duration and was either allowed or denied.
+func (m *validationMetrics) observeValidation(resource string, allowed bool, duration time.Duration) {
+	outcome := validationOutcomeDenied
+	if allowed {
+		outcome = validationOutcomeAllowed
+	}
+	m.validationDurationSeconds.WithLabelValues(resource).Observe(duration.Seconds())
+	m.validationTotal.WithLabelValues(resource, outcome).Inc()
+}
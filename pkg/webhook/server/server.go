@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	admissionv1 "k8s.io/api/admission/v1"
 	admissionv1beta1 "k8s.io/api/admission/v1beta1"
 	apiextensionsinstall "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/install"
@@ -80,7 +81,7 @@ type Server struct {
 	ListenAddr string
 
 	// HealthzAddr is the address the healthz HTTP server should listen on
-	// If not specified, the healthz endpoint will not be exposed.
+	// If not specified, the healthz and metrics endpoints will not be exposed.
 	HealthzAddr string
 
 	// EnablePprof controls whether net/http/pprof handlers are registered with
@@ -114,6 +115,7 @@ type Server struct {
 	MinTLSVersion string
 
 	listener net.Listener
+	metrics  *validationMetrics
 }
 
 func (s *Server) Run(stopCh <-chan struct{}) error {
@@ -133,6 +135,8 @@ func (s *Server) Run(stopCh <-chan struct{}) error {
 	var healthzChan <-chan error
 	var certSourceChan <-chan error
 
+	s.metrics = newValidationMetrics()
+
 	// if a HealthzAddr is provided, start the healthz listener
 	if s.HealthzAddr != "" {
 		l, err := net.Listen("tcp", s.HealthzAddr)
@@ -143,6 +147,7 @@ func (s *Server) Run(stopCh <-chan struct{}) error {
 		mux := http.NewServeMux()
 		mux.HandleFunc("/healthz", s.handleHealthz)
 		mux.HandleFunc("/livez", s.handleLivez)
+		mux.Handle("/metrics", promhttp.HandlerFor(s.metrics.registry, promhttp.HandlerOpts{}))
 		s.Log.V(logf.InfoLevel).Info("listening for insecure healthz connections", "address", s.HealthzAddr)
 		healthzChan = s.startServer(l, internalStopCh, mux)
 	}
@@ -302,7 +307,13 @@ func (s *Server) validate(obj runtime.Object) (runtime.Object, error) {
 		review = &admissionv1.AdmissionReview{}
 		util.Convert_v1beta1_AdmissionReview_To_admission_AdmissionReview(reviewv1beta1, review)
 	}
+	start := time.Now()
 	resp := s.ValidationWebhook.Validate(review.Request)
+	// Dry-run requests are only used to preview the outcome of a request, so
+	// are not recorded so that they do not skew the validation metrics.
+	if s.metrics != nil && review.Request != nil && !isDryRun(review.Request) {
+		s.metrics.observeValidation(review.Request.Resource.Resource, resp.Allowed, time.Since(start))
+	}
 	review.Response = resp
 
 	// reply v1
@@ -316,6 +327,11 @@ func (s *Server) validate(obj runtime.Object) (runtime.Object, error) {
 	return reviewv1beta1, nil
 }
 
+// isDryRun returns true if the given admission request will not be persisted.
+func isDryRun(req *admissionv1.AdmissionRequest) bool {
+	return req.DryRun != nil && *req.DryRun
+}
+
 func (s *Server) mutate(obj runtime.Object) (runtime.Object, error) {
 	outputVersion := admissionv1.SchemeGroupVersion
 	review, isV1 := obj.(*admissionv1.AdmissionReview)
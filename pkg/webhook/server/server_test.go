@@ -17,12 +17,17 @@ limitations under the License.
 package server
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
 	testingcmlogs "github.com/jetstack/cert-manager/pkg/logs/testing"
@@ -83,3 +88,96 @@ func TestConvert(t *testing.T) {
 		})
 	}
 }
+
+// validatorFunc implements handlers.ValidatingAdmissionHook with a function.
+type validatorFunc func(*admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse
+
+func (f validatorFunc) Validate(req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	return f(req)
+}
+
+func TestValidateMetrics(t *testing.T) {
+	s := &Server{
+		// allow everything except issuers
+		ValidationWebhook: validatorFunc(func(req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+			return &admissionv1.AdmissionResponse{
+				UID:     req.UID,
+				Allowed: req.Resource.Resource != "issuers",
+			}
+		}),
+		Log:     &testingcmlogs.TestLogger{T: t},
+		metrics: newValidationMetrics(),
+	}
+
+	reviews := []runtime.Object{
+		&admissionv1.AdmissionReview{
+			Request: &admissionv1.AdmissionRequest{
+				Resource: metav1.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"},
+			},
+		},
+		&admissionv1beta1.AdmissionReview{
+			Request: &admissionv1beta1.AdmissionRequest{
+				Resource: metav1.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"},
+			},
+		},
+		&admissionv1.AdmissionReview{
+			Request: &admissionv1.AdmissionRequest{
+				Resource: metav1.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "issuers"},
+			},
+		},
+	}
+	for _, review := range reviews {
+		_, err := s.validate(review)
+		require.NoError(t, err)
+	}
+
+	expectedTotal := `
+	# HELP certmanager_webhook_validation_total The number of resources validated by the webhook, partitioned by whether they were allowed or denied.
+	# TYPE certmanager_webhook_validation_total counter
+	certmanager_webhook_validation_total{outcome="allowed",resource="certificates"} 2
+	certmanager_webhook_validation_total{outcome="denied",resource="issuers"} 1
+`
+	err := testutil.CollectAndCompare(s.metrics.validationTotal, strings.NewReader(expectedTotal), "certmanager_webhook_validation_total")
+	assert.NoError(t, err)
+
+	// one duration series is recorded per resource
+	assert.Equal(t, 2, testutil.CollectAndCount(s.metrics.validationDurationSeconds))
+}
+
+func TestValidateDryRunMetrics(t *testing.T) {
+	s := &Server{
+		ValidationWebhook: validatorFunc(func(req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+			return &admissionv1.AdmissionResponse{
+				UID:      req.UID,
+				Allowed:  true,
+				Warnings: []string{"a warning"},
+			}
+		}),
+		Log:     &testingcmlogs.TestLogger{T: t},
+		metrics: newValidationMetrics(),
+	}
+
+	dryRun := true
+	reviews := []runtime.Object{
+		&admissionv1.AdmissionReview{
+			Request: &admissionv1.AdmissionRequest{
+				Resource: metav1.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"},
+				DryRun:   &dryRun,
+			},
+		},
+		&admissionv1beta1.AdmissionReview{
+			Request: &admissionv1beta1.AdmissionRequest{
+				Resource: metav1.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"},
+				DryRun:   &dryRun,
+			},
+		},
+	}
+	for _, review := range reviews {
+		_, err := s.validate(review)
+		require.NoError(t, err)
+	}
+
+	// dry-run requests are still validated but are not recorded
+	assert.Equal(t, 0, testutil.CollectAndCount(s.metrics.validationTotal))
+	assert.Equal(t, 0, testutil.CollectAndCount(s.metrics.validationDurationSeconds))
+}
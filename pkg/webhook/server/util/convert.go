@@ -56,6 +56,7 @@ func Convert_v1beta1_AdmissionRequest_To_admission_AdmissionRequest(in *admissio
 	out.Operation = admissionv1.Operation(in.Operation)
 	out.Object = in.Object
 	out.OldObject = in.OldObject
+	out.DryRun = in.DryRun
 	out.Options = in.Options
 }
 
@@ -86,5 +87,6 @@ func Convert_admission_AdmissionRequest_To_v1beta1_AdmissionRequest(in *admissio
 	out.Operation = admissionv1beta1.Operation(in.Operation)
 	out.Object = in.Object
 	out.OldObject = in.OldObject
+	out.DryRun = in.DryRun
 	out.Options = in.Options
 }
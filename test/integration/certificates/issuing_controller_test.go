@@ -227,14 +227,16 @@ func TestIssuingController(t *testing.T) {
 		}
 
 		for expKey, expV := range map[string]string{
-			cmapi.AltNamesAnnotationKey:    "example.com,foo.example.com",
-			cmapi.IPSANAnnotationKey:       "1.2.3.4,5.6.7.8",
-			cmapi.URISANAnnotationKey:      "spiffe://hello.world",
-			cmapi.CommonNameAnnotationKey:  "my-common-name",
-			cmapi.IssuerNameAnnotationKey:  "testissuer",
-			cmapi.IssuerKindAnnotationKey:  "Issuer",
-			cmapi.IssuerGroupAnnotationKey: "foo.io",
-			cmapi.CertificateNameKey:       "testcrt",
+			cmapi.AltNamesAnnotationKey:             "example.com,foo.example.com",
+			cmapi.IPSANAnnotationKey:                "1.2.3.4,5.6.7.8",
+			cmapi.URISANAnnotationKey:               "spiffe://hello.world",
+			cmapi.CommonNameAnnotationKey:           "my-common-name",
+			cmapi.IssuerNameAnnotationKey:           "testissuer",
+			cmapi.IssuerKindAnnotationKey:           "Issuer",
+			cmapi.IssuerGroupAnnotationKey:          "foo.io",
+			cmapi.CertificateNameKey:                "testcrt",
+			cmapi.CertificateNotBeforeAnnotationKey: certTemplate.NotBefore.UTC().Format(time.RFC3339),
+			cmapi.CertificateNotAfterAnnotationKey:  certTemplate.NotAfter.UTC().Format(time.RFC3339),
 		} {
 			if v, ok := secret.Annotations[expKey]; !ok || expV != v {
 				return false, fmt.Errorf("expected Secret to have the annotation %s:%s, got %s:%s",
@@ -459,3 +461,201 @@ func TestIssuingController_PKCS8_PrivateKey(t *testing.T) {
 		t.Fatalf("Failed to wait for final state: %+v", crt)
 	}
 }
+
+// TestIssuingController_RemovesStaleSecretFields ensures that data entries and
+// the owner reference written by cert-manager are removed from an existing
+// Secret when they are no longer required, even if the Secret was written
+// using Update rather than server-side apply.
+func TestIssuingController_RemovesStaleSecretFields(t *testing.T) {
+	config, stopFn := framework.RunControlPlane(t)
+	defer stopFn()
+
+	// Build, instantiate and run the issuing controller.
+	kubeClient, factory, cmCl, cmFactory := framework.NewClients(t, config)
+	controllerOptions := controllerpkg.CertificateOptions{
+		EnableOwnerRef: true,
+	}
+
+	ctrl, queue, mustSync := issuing.NewController(logf.Log, kubeClient, cmCl, factory, cmFactory, framework.NewEventRecorder(t), clock.RealClock{}, controllerOptions)
+	c := controllerpkg.NewController(
+		context.Background(),
+		"issuing_test",
+		metrics.New(logf.Log),
+		ctrl.ProcessItem,
+		mustSync,
+		nil,
+		queue,
+	)
+	stopController := framework.StartInformersAndController(t, factory, cmFactory, c)
+	defer stopController()
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Second*20)
+	defer cancel()
+
+	var (
+		crtName                  = "testcrt"
+		revision                 = 1
+		namespace                = "testns"
+		nextPrivateKeySecretName = "next-private-key-test-crt"
+		secretName               = "test-crt-tls"
+	)
+
+	// Create Namespace
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}
+	_, err := kubeClient.CoreV1().Namespaces().Create(context.TODO(), ns, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Create a new private key
+	sk, err := utilpki.GenerateRSAPrivateKey(2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	skBytes := utilpki.EncodePKCS1PrivateKey(sk)
+
+	// Store new private key in secret
+	_, err = kubeClient.CoreV1().Secrets(namespace).Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nextPrivateKeySecretName,
+			Namespace: namespace,
+		},
+		Data: map[string][]byte{
+			corev1.TLSPrivateKeyKey: skBytes,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Create Certificate, opting out of the owner reference on its Secret
+	crt := gen.Certificate(crtName,
+		gen.SetCertificateNamespace(namespace),
+		gen.SetCertificateCommonName("my-common-name"),
+		gen.SetCertificateDNSNames("example.com"),
+		gen.SetCertificateKeyAlgorithm(cmapi.RSAKeyAlgorithm),
+		gen.SetCertificateKeySize(2048),
+		gen.SetCertificateSecretName(secretName),
+		gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "testissuer", Group: "foo.io", Kind: "Issuer"}),
+	)
+	crt.Annotations = map[string]string{cmapi.SecretOwnerReferenceAnnotationKey: "false"}
+
+	crt, err = cmCl.CertmanagerV1().Certificates(namespace).Create(ctx, crt, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Create the Secret the way older versions of cert-manager did, using
+	// Update with a keystore and an owner reference to the Certificate.
+	_, err = kubeClient.CoreV1().Secrets(namespace).Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            secretName,
+			Namespace:       namespace,
+			Annotations:     map[string]string{cmapi.CertificateNameKey: crtName},
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(crt, cmapi.SchemeGroupVersion.WithKind("Certificate"))},
+		},
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       []byte("old-cert"),
+			corev1.TLSPrivateKeyKey: []byte("old-key"),
+			"keystore.p12":          []byte("old-keystore"),
+			"truststore.p12":        []byte("old-truststore"),
+		},
+		Type: corev1.SecretTypeTLS,
+	}, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Create x509 CSR from Certificate
+	csr, err := utilpki.GenerateCSR(crt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Encode CSR
+	csrDER, err := utilpki.EncodeCSR(csr, sk)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	csrPEM := pem.EncodeToMemory(&pem.Block{
+		Type: "CERTIFICATE REQUEST", Bytes: csrDER,
+	})
+
+	// Sign Certificate
+	certTemplate, err := utilpki.GenerateTemplate(crt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Sign and encode the certificate
+	certPem, _, err := utilpki.SignCertificate(certTemplate, certTemplate, sk.Public(), sk)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Create CertificateRequest
+	req := gen.CertificateRequest(crtName,
+		gen.SetCertificateRequestNamespace(namespace),
+		gen.SetCertificateRequestCSR(csrPEM),
+		gen.SetCertificateRequestIssuer(crt.Spec.IssuerRef),
+		gen.SetCertificateRequestAnnotations(map[string]string{
+			cmapi.CertificateRequestRevisionAnnotationKey: fmt.Sprintf("%d", revision+1),
+		}),
+		gen.AddCertificateRequestOwnerReferences(*metav1.NewControllerRef(
+			crt,
+			cmapi.SchemeGroupVersion.WithKind("Certificate"),
+		)),
+	)
+	req, err = cmCl.CertmanagerV1().CertificateRequests(namespace).Create(ctx, req, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Set CertificateRequest as ready
+	req.Status.CA = certPem
+	req.Status.Certificate = certPem
+	apiutil.SetCertificateRequestCondition(req, cmapi.CertificateRequestConditionReady, cmmeta.ConditionTrue, cmapi.CertificateRequestReasonIssued, "")
+	_, err = cmCl.CertmanagerV1().CertificateRequests(namespace).UpdateStatus(ctx, req, metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Add Issuing condition to Certificate
+	apiutil.SetCertificateCondition(crt, cmapi.CertificateConditionIssuing, cmmeta.ConditionTrue, "", "")
+	crt.Status.NextPrivateKeySecretName = &nextPrivateKeySecretName
+	crt.Status.Revision = &revision
+	crt, err = cmCl.CertmanagerV1().Certificates(namespace).UpdateStatus(ctx, crt, metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Wait for the new certificate to be stored in the Secret, and for the
+	// keystore entries and owner reference to have been removed.
+	err = wait.Poll(time.Millisecond*100, time.Second*5, func() (done bool, err error) {
+		secret, err := kubeClient.CoreV1().Secrets(namespace).Get(ctx, secretName, metav1.GetOptions{})
+		if err != nil {
+			return false, fmt.Errorf("Failed to fetch Secret %s/%s: %s", namespace, secretName, err)
+		}
+
+		if !bytes.Equal(secret.Data[corev1.TLSCertKey], certPem) {
+			t.Logf("Secret does not contain the issued certificate yet, retrying")
+			return false, nil
+		}
+
+		for _, key := range []string{"keystore.p12", "truststore.p12"} {
+			if _, ok := secret.Data[key]; ok {
+				return false, fmt.Errorf("expected Secret data entry %q to be removed", key)
+			}
+		}
+
+		if len(secret.OwnerReferences) > 0 {
+			return false, fmt.Errorf("expected Secret owner references to be removed, got %v", secret.OwnerReferences)
+		}
+
+		return true, nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to wait for final state: %v", err)
+	}
+}
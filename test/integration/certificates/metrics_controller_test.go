@@ -29,6 +29,7 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
 
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
@@ -51,7 +52,10 @@ func TestMetricsController(t *testing.T) {
 	kubernetesCl, factory, cmClient, cmFactory := framework.NewClients(t, config)
 
 	metricsHandler := metrics.New(logf.Log)
-	server, err := metricsHandler.Start("127.0.0.1:0", false)
+	// expose the depth of the controller's workqueue, which must be set up
+	// before the workqueue is constructed
+	workqueue.SetProvider(metricsHandler.WorkqueueMetricsProvider())
+	server, err := metricsHandler.Start("127.0.0.1:0", false, 10)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -77,6 +81,7 @@ func TestMetricsController(t *testing.T) {
 		crtName         = "testcrt"
 		namespace       = "testns"
 		metricsEndpoint = fmt.Sprintf("http://%s/metrics", server.Addr)
+		readyzEndpoint  = fmt.Sprintf("http://%s/readyz", server.Addr)
 
 		lastErr error
 	)
@@ -121,8 +126,21 @@ func TestMetricsController(t *testing.T) {
 		}
 	}
 
-	// Should expose no metrics
-	waitForMetrics("")
+	// Should expose only the depth of the empty workqueue
+	waitForMetrics(`# HELP certmanager_controller_workqueue_depth The number of items waiting to be processed in a controller's workqueue.
+# TYPE certmanager_controller_workqueue_depth gauge
+certmanager_controller_workqueue_depth{controller="CertificateMetrics"} 0
+`)
+
+	// Should report ready as the workqueue is not backlogged
+	resp, err := http.DefaultClient.Get(readyzEndpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected %s to return %d but got %d", readyzEndpoint, http.StatusOK, resp.StatusCode)
+	}
 
 	// Create Certificate
 	crt := gen.Certificate(crtName,
@@ -150,6 +168,9 @@ certmanager_certificate_ready_status{condition="Unknown",name="testcrt",namespac
 # HELP certmanager_controller_sync_call_count The number of sync() calls made by a controller.
 # TYPE certmanager_controller_sync_call_count counter
 certmanager_controller_sync_call_count{controller="metrics_test"} 1
+# HELP certmanager_controller_workqueue_depth The number of items waiting to be processed in a controller's workqueue.
+# TYPE certmanager_controller_workqueue_depth gauge
+certmanager_controller_workqueue_depth{controller="CertificateMetrics"} 0
 `)
 
 	// Set Certificate Expiry and Ready status True
@@ -179,6 +200,9 @@ certmanager_certificate_ready_status{condition="Unknown",name="testcrt",namespac
 # HELP certmanager_controller_sync_call_count The number of sync() calls made by a controller.
 # TYPE certmanager_controller_sync_call_count counter
 certmanager_controller_sync_call_count{controller="metrics_test"} 2
+# HELP certmanager_controller_workqueue_depth The number of items waiting to be processed in a controller's workqueue.
+# TYPE certmanager_controller_workqueue_depth gauge
+certmanager_controller_workqueue_depth{controller="CertificateMetrics"} 0
 `)
 
 	err = cmClient.CertmanagerV1().Certificates(namespace).Delete(ctx, crt.Name, metav1.DeleteOptions{})
@@ -190,5 +214,8 @@ certmanager_controller_sync_call_count{controller="metrics_test"} 2
 	waitForMetrics(`# HELP certmanager_controller_sync_call_count The number of sync() calls made by a controller.
 # TYPE certmanager_controller_sync_call_count counter
 certmanager_controller_sync_call_count{controller="metrics_test"} 3
+# HELP certmanager_controller_workqueue_depth The number of items waiting to be processed in a controller's workqueue.
+# TYPE certmanager_controller_workqueue_depth gauge
+certmanager_controller_workqueue_depth{controller="CertificateMetrics"} 0
 `)
 }
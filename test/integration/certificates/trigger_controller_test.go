@@ -64,7 +64,7 @@ func TestTriggerController(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	ctrl, queue, mustSync := trigger.NewController(logf.Log, cmCl, factory, cmFactory, framework.NewEventRecorder(t), fakeClock, policies.NewTriggerPolicyChain(fakeClock))
+	ctrl, queue, mustSync := trigger.NewController(logf.Log, cmCl, factory, cmFactory, framework.NewEventRecorder(t), fakeClock, policies.NewTriggerPolicyChain(fakeClock, 0), false, 0, 0)
 	c := controllerpkg.NewController(
 		context.Background(),
 		"trigger_test",
@@ -121,7 +121,7 @@ func TestTriggerController_RenewNearExpiry(t *testing.T) {
 	// only use the 'current certificate nearing expiry' policy chain during the test
 	// as we want to test the very specific case of triggering due to a renewal being
 	// required
-	policyChain := policies.Chain{policies.CurrentCertificateNearingExpiry(fakeClock)}
+	policyChain := policies.Chain{policies.CurrentCertificateNearingExpiry(fakeClock, 0)}
 	// Build, instantiate and run the trigger controller.
 	kubeClient, factory, cmCl, cmFactory := framework.NewClients(t, config)
 
@@ -133,7 +133,7 @@ func TestTriggerController_RenewNearExpiry(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	ctrl, queue, mustSync := trigger.NewController(logf.Log, cmCl, factory, cmFactory, framework.NewEventRecorder(t), fakeClock, policyChain)
+	ctrl, queue, mustSync := trigger.NewController(logf.Log, cmCl, factory, cmFactory, framework.NewEventRecorder(t), fakeClock, policyChain, false, 0, 0)
 	c := controllerpkg.NewController(
 		logf.NewContext(context.Background(), logf.Log, "trigger_controller_RenewNearExpiry"),
 		"trigger_test",
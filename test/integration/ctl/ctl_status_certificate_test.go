@@ -195,7 +195,10 @@ error when finding Secret "example-tls": secrets "example-tls" not found
 Not Before: <none>
 Not After: 2020-09-16T09:26:18Z
 Renewal Time: <none>
-No CertificateRequest found for this Certificate$`,
+No CertificateRequest found for this Certificate
+Resource Tree:
+Certificate testns-1/testcrt-1: Ready
+└── CertificateRequest: No CertificateRequest found for this Certificate$`,
 		},
 		"certificate issued and renewal in progress with Issuer": {
 			certificate: gen.Certificate(crt2Name,
@@ -319,7 +322,13 @@ Order:
   No Authorizations for this Order
 Challenges:
 - Name: test-challenge1, Type: HTTP-01, Token: dummy-token1, Key: , State: , Reason: , Processing: false, Presented: false
-- Name: test-challenge2, Type: DNS-01, Token: dummy-token2, Key: , State: , Reason: , Processing: false, Presented: false$`,
+- Name: test-challenge2, Type: DNS-01, Token: dummy-token2, Key: , State: , Reason: , Processing: false, Presented: false
+Resource Tree:
+Certificate testns-1/testcrt-2: Ready
+└── CertificateRequest testreq-1: Not Ready, Reason: Pending, Message: Waiting on certificate issuance from order default/example-order: "pending"
+    └── Order example-order: State: <none>, URL: <none>
+        ├── Challenge test-challenge1: Type: HTTP-01, DNS Name: <none>, State: <none>
+        └── Challenge test-challenge2: Type: DNS-01, DNS Name: <none>, State: <none>$`,
 		},
 		"certificate issued and renewal in progress without Issuer": {
 			certificate: gen.Certificate(crt3Name,
@@ -372,7 +381,10 @@ CertificateRequest:
   Events:
     Type  Reason  Age        From  Message
     ----  ------  ----       ----  -------
-    type  reason  <unknown>        message$`,
+    type  reason  <unknown>        message
+Resource Tree:
+Certificate testns-1/testcrt-3: Ready
+└── CertificateRequest testreq-2: Not Ready, Reason: Pending, Message: Waiting on certificate issuance from order default/example-order: "pending"$`,
 		},
 		"certificate issued and renewal in progress without ClusterIssuer": {
 			certificate: gen.Certificate(crt4Name,
@@ -411,7 +423,10 @@ CertificateRequest:
   Namespace: testns-1
   Conditions:
     Ready: False, Reason: Pending, Message: Waiting on certificate issuance from order default/example-order: "pending"
-  Events:  <none>$`,
+  Events:  <none>
+Resource Tree:
+Certificate testns-1/testcrt-4: Ready
+└── CertificateRequest testreq-3: Not Ready, Reason: Pending, Message: Waiting on certificate issuance from order default/example-order: "pending"$`,
 		},
 	}
 
@@ -56,6 +56,12 @@ func SetCertificateIssuer(o cmmeta.ObjectReference) CertificateModifier {
 	}
 }
 
+func SetCertificateFallbackIssuer(o cmmeta.ObjectReference) CertificateModifier {
+	return func(c *v1.Certificate) {
+		c.Spec.FallbackIssuerRef = &o
+	}
+}
+
 func SetCertificateDNSNames(dnsNames ...string) CertificateModifier {
 	return func(crt *v1.Certificate) {
 		crt.Spec.DNSNames = dnsNames
@@ -80,12 +86,30 @@ func SetCertificateURIs(uris ...string) CertificateModifier {
 	}
 }
 
+func SetCertificateEmails(emails ...string) CertificateModifier {
+	return func(crt *v1.Certificate) {
+		crt.Spec.EmailAddresses = emails
+	}
+}
+
 func SetCertificateIsCA(isCA bool) CertificateModifier {
 	return func(crt *v1.Certificate) {
 		crt.Spec.IsCA = isCA
 	}
 }
 
+func SetCertificateMaxPathLen(maxPathLen int) CertificateModifier {
+	return func(crt *v1.Certificate) {
+		crt.Spec.MaxPathLen = maxPathLen
+	}
+}
+
+func SetCertificateMaxPathLenZero(maxPathLenZero bool) CertificateModifier {
+	return func(crt *v1.Certificate) {
+		crt.Spec.MaxPathLenZero = maxPathLenZero
+	}
+}
+
 func SetCertificateKeyAlgorithm(keyAlgorithm v1.PrivateKeyAlgorithm) CertificateModifier {
 	return func(crt *v1.Certificate) {
 		crt.Spec.PrivateKey.Algorithm = keyAlgorithm
@@ -104,6 +128,15 @@ func SetCertificateKeyEncoding(keyEncoding v1.PrivateKeyEncoding) CertificateMod
 	}
 }
 
+func SetCertificateRotationPolicy(rotationPolicy v1.PrivateKeyRotationPolicy) CertificateModifier {
+	return func(crt *v1.Certificate) {
+		if crt.Spec.PrivateKey == nil {
+			crt.Spec.PrivateKey = &v1.CertificatePrivateKey{}
+		}
+		crt.Spec.PrivateKey.RotationPolicy = rotationPolicy
+	}
+}
+
 func SetCertificateSecretName(secretName string) CertificateModifier {
 	return func(crt *v1.Certificate) {
 		crt.Spec.SecretName = secretName
@@ -170,6 +203,9 @@ func SetCertificateRenewalTIme(p metav1.Time) CertificateModifier {
 
 func SetCertificateOrganization(orgs ...string) CertificateModifier {
 	return func(ch *v1.Certificate) {
+		if ch.Spec.Subject == nil {
+			ch.Spec.Subject = &v1.X509Subject{}
+		}
 		ch.Spec.Subject.Organizations = orgs
 	}
 }
@@ -50,6 +50,12 @@ func SetCertificateRequestIssuer(o cmmeta.ObjectReference) CertificateRequestMod
 	}
 }
 
+func SetCertificateRequestFallbackIssuer(o cmmeta.ObjectReference) CertificateRequestModifier {
+	return func(cr *v1.CertificateRequest) {
+		cr.Spec.FallbackIssuerRef = &o
+	}
+}
+
 func SetCertificateRequestCSR(csr []byte) CertificateRequestModifier {
 	return func(cr *v1.CertificateRequest) {
 		cr.Spec.Request = csr
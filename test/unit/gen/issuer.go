@@ -65,6 +65,16 @@ func SetIssuerACME(a cmacme.ACMEIssuer) IssuerModifier {
 	}
 }
 
+func SetIssuerACMEAllowedDomains(domains ...string) IssuerModifier {
+	return func(iss v1.GenericIssuer) {
+		spec := iss.GetSpec()
+		if spec.ACME == nil {
+			spec.ACME = &cmacme.ACMEIssuer{}
+		}
+		spec.ACME.AllowedDomains = domains
+	}
+}
+
 func SetIssuerCA(a v1.CAIssuer) IssuerModifier {
 	return func(iss v1.GenericIssuer) {
 		iss.GetSpec().CA = &a
@@ -89,6 +99,12 @@ func SetIssuerVenafi(a v1.VenafiIssuer) IssuerModifier {
 	}
 }
 
+func SetIssuerAWSPCA(a v1.AWSPCAIssuer) IssuerModifier {
+	return func(iss v1.GenericIssuer) {
+		iss.GetSpec().AWSPCA = &a
+	}
+}
+
 func AddIssuerCondition(c v1.IssuerCondition) IssuerModifier {
 	return func(iss v1.GenericIssuer) {
 		iss.GetStatus().Conditions = append(iss.GetStatus().Conditions, c)
@@ -17,6 +17,8 @@ limitations under the License.
 package gen
 
 import (
+	"k8s.io/apimachinery/pkg/types"
+
 	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 )
@@ -107,3 +109,9 @@ func SetOrderCsr(csr []byte) OrderModifier {
 		order.Spec.Request = csr
 	}
 }
+
+func SetOrderUID(uid types.UID) OrderModifier {
+	return func(order *cmacme.Order) {
+		order.UID = uid
+	}
+}